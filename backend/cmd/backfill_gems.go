@@ -0,0 +1,52 @@
+package main
+
+import (
+	"backend/internals/config"
+	"backend/internals/db/models"
+	"backend/internals/repositories"
+	"fmt"
+	"github.com/bsthun/gut"
+	"github.com/spf13/viper"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// backfill_gems builds the gem ledger from the evaluations passed before it existed, then the
+// leaderboards from the ledger. It is safe to run again, evaluations already in the ledger are skipped.
+func main() {
+	// initialize config
+	config.BootConfiguration()
+
+	// connect to database
+	dsn := fmt.Sprintf(
+		"host=%s user=%s password=%s dbname=%s port=%d sslmode=disable",
+		viper.GetString("DB_HOST"),
+		viper.GetString("DB_USERNAME"),
+		viper.GetString("DB_PASSWORD"),
+		viper.GetString("DB_NAME"),
+		viper.GetInt("DB_PORT"),
+	)
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		gut.Fatal("Failed to connect to database", err)
+	}
+
+	if err := db.AutoMigrate(new(models.GemEntry), new(models.LeaderboardScore)); err != nil {
+		gut.Fatal("failed to migrate gem entries", err)
+	}
+
+	count, err := repositories.NewGemRepository(db).BackfillUserEvals()
+	if err != nil {
+		gut.Fatal("failed to backfill gem ledger", err)
+	}
+
+	gut.Debug(fmt.Sprintf("Backfilled %d gem entries", count))
+
+	// backfilled entries are written around the leaderboards, so these are computed again
+	scores, err := repositories.NewLeaderboardRepository(db).Rebuild()
+	if err != nil {
+		gut.Fatal("failed to rebuild leaderboards", err)
+	}
+
+	gut.Debug(fmt.Sprintf("Rebuilt %d leaderboard scores", scores))
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"backend/internals/config"
+	"backend/internals/minio"
+	"backend/internals/repositories"
+	"backend/internals/services"
+	utilServices "backend/internals/utils/services"
+	"flag"
+	"fmt"
+	"github.com/bsthun/gut"
+	"github.com/spf13/viper"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"os"
+)
+
+func main() {
+	// initialize config
+	config.BootConfiguration()
+
+	// parse flags
+	courseId := flag.Uint64("courseId", 0, "ID of the course to export")
+	output := flag.String("output", "", "Path of the bundle zip, defaults to course-<courseId>.zip")
+	flag.Parse()
+
+	if *courseId == 0 {
+		gut.Fatal("missing required flag: courseId", nil)
+	}
+	if *output == "" {
+		*output = fmt.Sprintf("course-%d.zip", *courseId)
+	}
+
+	// connect to database
+	dsn := fmt.Sprintf(
+		"host=%s user=%s password=%s dbname=%s port=%d sslmode=disable",
+		viper.GetString("DB_HOST"),
+		viper.GetString("DB_USERNAME"),
+		viper.GetString("DB_PASSWORD"),
+		viper.GetString("DB_NAME"),
+		viper.GetInt("DB_PORT"),
+	)
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		gut.Fatal("Failed to connect to database", err)
+	}
+
+	// connect to object storage for bundled assets
+	minio.SetUpMinio()
+
+	courseBundleService := services.NewCourseBundleService(
+		repositories.NewCoursePageRepository(db),
+		repositories.NewModuleRepository(db),
+		repositories.NewStepRepository(db),
+		repositories.NewStepEvaluateRepository(db),
+		repositories.NewContentImportRepository(db),
+		utilServices.NewMinioService(minio.MinioClient),
+		config.Env,
+	)
+
+	bundle, err := courseBundleService.Export(*courseId)
+	if err != nil {
+		gut.Fatal("failed to export course", err)
+	}
+
+	if err := os.WriteFile(*output, bundle, 0644); err != nil {
+		gut.Fatal("failed to write bundle", err)
+	}
+
+	gut.Debug("Exported course to " + *output)
+}
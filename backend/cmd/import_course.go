@@ -0,0 +1,69 @@
+package main
+
+import (
+	"backend/internals/config"
+	"backend/internals/minio"
+	"backend/internals/repositories"
+	"backend/internals/services"
+	utilServices "backend/internals/utils/services"
+	"flag"
+	"fmt"
+	"github.com/bsthun/gut"
+	"github.com/spf13/viper"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"os"
+)
+
+func main() {
+	// initialize config
+	config.BootConfiguration()
+
+	// parse flags
+	input := flag.String("input", "", "Path of the bundle zip to import")
+	flag.Parse()
+
+	if *input == "" {
+		gut.Fatal("missing required flag: input", nil)
+	}
+
+	data, err := os.ReadFile(*input)
+	if err != nil {
+		gut.Fatal("failed to read bundle", err)
+	}
+
+	// connect to database
+	dsn := fmt.Sprintf(
+		"host=%s user=%s password=%s dbname=%s port=%d sslmode=disable",
+		viper.GetString("DB_HOST"),
+		viper.GetString("DB_USERNAME"),
+		viper.GetString("DB_PASSWORD"),
+		viper.GetString("DB_NAME"),
+		viper.GetInt("DB_PORT"),
+	)
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		gut.Fatal("Failed to connect to database", err)
+	}
+
+	// connect to object storage for bundled assets
+	minio.SetUpMinio()
+
+	courseBundleService := services.NewCourseBundleService(
+		repositories.NewCoursePageRepository(db),
+		repositories.NewModuleRepository(db),
+		repositories.NewStepRepository(db),
+		repositories.NewStepEvaluateRepository(db),
+		repositories.NewContentImportRepository(db),
+		utilServices.NewMinioService(minio.MinioClient),
+		config.Env,
+	)
+
+	result, err := courseBundleService.Import(data)
+	if err != nil {
+		gut.Fatal("failed to import course", err)
+	}
+
+	gut.Debug(fmt.Sprintf("Imported course %d with %d modules, %d steps, %d evaluations and %d assets",
+		*result.CourseId, len(result.ModuleIds), len(result.StepIds), len(result.StepEvaluateIds), result.AssetCount))
+}
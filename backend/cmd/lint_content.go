@@ -0,0 +1,64 @@
+package main
+
+import (
+	"backend/internals/config"
+	"backend/internals/repositories"
+	"backend/internals/services"
+	"backend/internals/utils"
+	utilServices "backend/internals/utils/services"
+	"flag"
+	"fmt"
+	"github.com/bsthun/gut"
+	"github.com/spf13/viper"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"os"
+)
+
+func main() {
+	// initialize config
+	config.BootConfiguration()
+
+	// parse flags, defaulting to the parents watched by the outline webhook
+	moduleParentDocumentId := flag.String("moduleParentDocumentId", utils.Val(config.Env.OutlineModuleParentId), "Outline parent document ID of the modules")
+	courseParentDocumentId := flag.String("courseParentDocumentId", utils.Val(config.Env.OutlineCourseParentId), "Outline parent document ID of the courses")
+	flag.Parse()
+
+	if *moduleParentDocumentId == "" && *courseParentDocumentId == "" {
+		gut.Fatal("missing required flag: moduleParentDocumentId or courseParentDocumentId", nil)
+	}
+
+	// connect to database, used to resolve fields and already imported modules
+	dsn := fmt.Sprintf(
+		"host=%s user=%s password=%s dbname=%s port=%d sslmode=disable",
+		viper.GetString("DB_HOST"),
+		viper.GetString("DB_USERNAME"),
+		viper.GetString("DB_PASSWORD"),
+		viper.GetString("DB_NAME"),
+		viper.GetInt("DB_PORT"),
+	)
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		gut.Fatal("Failed to connect to database", err)
+	}
+
+	contentLintService := services.NewContentLintService(
+		repositories.NewModuleRepository(db),
+		repositories.NewFieldTypeRepository(db),
+		utilServices.NewOutlineService(config.Env),
+	)
+
+	problems, err := contentLintService.LintDocuments(*moduleParentDocumentId, *courseParentDocumentId)
+	if err != nil {
+		gut.Fatal("failed to lint documents", err)
+	}
+
+	for _, problem := range problems {
+		fmt.Printf("%s (%s):%d: %s\n", problem.Title, problem.DocumentId, problem.Line, problem.Message)
+	}
+
+	if len(problems) > 0 {
+		fmt.Printf("%d problems found\n", len(problems))
+		os.Exit(1)
+	}
+}
@@ -109,6 +109,7 @@ func main() {
 			&models.UserEvaluate{},
 			&models.UserPass{},
 			&models.UserActivity{},
+			&models.ImportJob{},
 		); err != nil {
 			gut.Fatal("Failed to migrate schema", err)
 		}
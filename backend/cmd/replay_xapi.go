@@ -0,0 +1,58 @@
+package main
+
+import (
+	"backend/internals/config"
+	"backend/internals/db/models"
+	"backend/internals/repositories"
+	"backend/internals/services"
+	utilServices "backend/internals/utils/services"
+	"fmt"
+	"github.com/bsthun/gut"
+	"github.com/spf13/viper"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// replay_xapi adds the xAPI statements of the evaluations and step visits recorded before the
+// platform emitted them to the outbox, the running server sends them to the LRS. It is safe to
+// run again, statements already in the outbox are skipped.
+func main() {
+	// initialize config
+	config.BootConfiguration()
+
+	// connect to database
+	dsn := fmt.Sprintf(
+		"host=%s user=%s password=%s dbname=%s port=%d sslmode=disable",
+		viper.GetString("DB_HOST"),
+		viper.GetString("DB_USERNAME"),
+		viper.GetString("DB_PASSWORD"),
+		viper.GetString("DB_NAME"),
+		viper.GetInt("DB_PORT"),
+	)
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		gut.Fatal("Failed to connect to database", err)
+	}
+
+	if err := db.AutoMigrate(new(models.XapiStatement)); err != nil {
+		gut.Fatal("failed to migrate xapi statements", err)
+	}
+
+	xapiService := services.NewXapiService(
+		repositories.NewXapiStatementRepository(db),
+		repositories.NewUserRepository(db),
+		repositories.NewStepRepository(db),
+		repositories.NewStepEvaluateRepository(db),
+		repositories.NewCourseRepository(db),
+		repositories.NewCertificateRepository(db),
+		utilServices.NewLrsClient(config.Env),
+		config.Env,
+	)
+
+	replay, err := xapiService.Replay()
+	if err != nil {
+		gut.Fatal("failed to replay xapi statements", err)
+	}
+
+	gut.Debug(fmt.Sprintf("Replayed %d user evaluates and %d user activities into %d new statements", replay.UserEvaluates, replay.UserActivities, replay.Statements))
+}
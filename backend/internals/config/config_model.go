@@ -1,25 +1,43 @@
 package config
 
 type Config struct {
-	DBAutoMigrate     *bool     `yaml:"DB_AUTOMIGRATE" mapstructure:"DB_AUTOMIGRATE"`
-	DBHost            *string   `yaml:"DB_HOST" mapstructure:"DB_HOST"`
-	DBName            *string   `yaml:"DB_NAME" mapstructure:"DB_NAME"`
-	DBPassword        *string   `yaml:"DB_PASSWORD" mapstructure:"DB_PASSWORD"`
-	DBPort            *int      `yaml:"DB_PORT" mapstructure:"DB_PORT"`
-	DBUsername        *string   `yaml:"DB_USERNAME" mapstructure:"DB_USERNAME"`
-	ServerHost        *string   `yaml:"SERVER_HOST" mapstructure:"SERVER_HOST"`
-	ServerOrigins     []*string `yaml:"SERVER_ORIGINS" mapstructure:"SERVER_ORIGINS"`
-	ServerPort        *int      `yaml:"SERVER_PORT" mapstructure:"SERVER_PORT"`
-	SecretKey         *string   `yaml:"SECRET" mapstructure:"SECRET"`
-	Environment       *int      `yaml:"ENVIRONMENT" mapstructure:"ENVIRONMENT"`
-	OauthClientId     *string   `yaml:"OAUTH_CLIENT_ID" mapstructure:"OAUTH_CLIENT_ID"`
-	OauthClientSecret *string   `yaml:"OAUTH_CLIENT_SECRET" mapstructure:"OAUTH_CLIENT_SECRET"`
-	OauthEndpoint     *string   `yaml:"OAUTH_ENDPOINT" mapstructure:"OAUTH_ENDPOINT"`
-	FrontendUrl       *string   `yaml:"FRONTEND_URL" mapstructure:"FRONTEND_URL"`
-	FrontendScheme    *string   `yaml:"FRONTEND_SCHEME" mapstructure:"FRONTEND_SCHEME"`
-	MinioS3Endpoint   *string   `yaml:"MINIO_S3_ENDPOINT" mapstructure:"MINIO_S3_ENDPOINT"`
-	MinioS3AccessKey  *string   `yaml:"MINIO_S3_ACCESS_KEY" mapstructure:"MINIO_S3_ACCESS_KEY"`
-	MinioS3SecretKey  *string   `yaml:"MINIO_S3_SECRET_KEY" mapstructure:"MINIO_S3_SECRET_KEY"`
-	MinioS3BucketName *string   `yaml:"MINIO_S3_BUCKET_NAME" mapstructure:"MINIO_S3_BUCKET_NAME"`
-	OutlineToken      *string   `yaml:"OUTLINE_TOKEN" mapstructure:"OUTLINE_TOKEN"`
+	DBAutoMigrate         *bool     `yaml:"DB_AUTOMIGRATE" mapstructure:"DB_AUTOMIGRATE"`
+	DBHost                *string   `yaml:"DB_HOST" mapstructure:"DB_HOST"`
+	DBName                *string   `yaml:"DB_NAME" mapstructure:"DB_NAME"`
+	DBPassword            *string   `yaml:"DB_PASSWORD" mapstructure:"DB_PASSWORD"`
+	DBPort                *int      `yaml:"DB_PORT" mapstructure:"DB_PORT"`
+	DBUsername            *string   `yaml:"DB_USERNAME" mapstructure:"DB_USERNAME"`
+	ServerHost            *string   `yaml:"SERVER_HOST" mapstructure:"SERVER_HOST"`
+	ServerOrigins         []*string `yaml:"SERVER_ORIGINS" mapstructure:"SERVER_ORIGINS"`
+	ServerPort            *int      `yaml:"SERVER_PORT" mapstructure:"SERVER_PORT"`
+	SecretKey             *string   `yaml:"SECRET" mapstructure:"SECRET"`
+	Environment           *int      `yaml:"ENVIRONMENT" mapstructure:"ENVIRONMENT"`
+	OauthClientId         *string   `yaml:"OAUTH_CLIENT_ID" mapstructure:"OAUTH_CLIENT_ID"`
+	OauthClientSecret     *string   `yaml:"OAUTH_CLIENT_SECRET" mapstructure:"OAUTH_CLIENT_SECRET"`
+	OauthEndpoint         *string   `yaml:"OAUTH_ENDPOINT" mapstructure:"OAUTH_ENDPOINT"`
+	FrontendUrl           *string   `yaml:"FRONTEND_URL" mapstructure:"FRONTEND_URL"`
+	FrontendScheme        *string   `yaml:"FRONTEND_SCHEME" mapstructure:"FRONTEND_SCHEME"`
+	MinioS3Endpoint       *string   `yaml:"MINIO_S3_ENDPOINT" mapstructure:"MINIO_S3_ENDPOINT"`
+	MinioS3AccessKey      *string   `yaml:"MINIO_S3_ACCESS_KEY" mapstructure:"MINIO_S3_ACCESS_KEY"`
+	MinioS3SecretKey      *string   `yaml:"MINIO_S3_SECRET_KEY" mapstructure:"MINIO_S3_SECRET_KEY"`
+	MinioS3BucketName     *string   `yaml:"MINIO_S3_BUCKET_NAME" mapstructure:"MINIO_S3_BUCKET_NAME"`
+	OutlineToken          *string   `yaml:"OUTLINE_TOKEN" mapstructure:"OUTLINE_TOKEN"`
+	OutlineWebhookSecret  *string   `yaml:"OUTLINE_WEBHOOK_SECRET" mapstructure:"OUTLINE_WEBHOOK_SECRET"`
+	OutlineModuleParentId *string   `yaml:"OUTLINE_MODULE_PARENT_ID" mapstructure:"OUTLINE_MODULE_PARENT_ID"`
+	OutlineCourseParentId *string   `yaml:"OUTLINE_COURSE_PARENT_ID" mapstructure:"OUTLINE_COURSE_PARENT_ID"`
+	CertificateFontPath   *string   `yaml:"CERTIFICATE_FONT_PATH" mapstructure:"CERTIFICATE_FONT_PATH"` // UTF-8 TTF font, needed to print Thai names
+	ApiUrl                *string   `yaml:"API_URL" mapstructure:"API_URL"`                             // public URL of this API, e.g. https://learn.example.com/api
+	BadgeIssuerName       *string   `yaml:"BADGE_ISSUER_NAME" mapstructure:"BADGE_ISSUER_NAME"`
+	BadgeIssuerEmail      *string   `yaml:"BADGE_ISSUER_EMAIL" mapstructure:"BADGE_ISSUER_EMAIL"`
+	BadgeSigningKeyPath   *string   `yaml:"BADGE_SIGNING_KEY_PATH" mapstructure:"BADGE_SIGNING_KEY_PATH"` // RSA private key PEM, badges are only hosted without it
+	SmtpHost              *string   `yaml:"SMTP_HOST" mapstructure:"SMTP_HOST"`                           // emails stay in the outbox without it
+	SmtpPort              *int      `yaml:"SMTP_PORT" mapstructure:"SMTP_PORT"`
+	SmtpUsername          *string   `yaml:"SMTP_USERNAME" mapstructure:"SMTP_USERNAME"`
+	SmtpPassword          *string   `yaml:"SMTP_PASSWORD" mapstructure:"SMTP_PASSWORD"`
+	SmtpFrom              *string   `yaml:"SMTP_FROM" mapstructure:"SMTP_FROM"`                   // e.g. IoT Learning Platform <no-reply@example.com>
+	StreakFreezeDays      *int      `yaml:"STREAK_FREEZE_DAYS" mapstructure:"STREAK_FREEZE_DAYS"` // missed days in a row a streak survives, none without it
+	LrsEndpoint           *string   `yaml:"LRS_ENDPOINT" mapstructure:"LRS_ENDPOINT"`             // e.g. https://lrs.example.com/xapi, learning events are not recorded without it
+	LrsUsername           *string   `yaml:"LRS_USERNAME" mapstructure:"LRS_USERNAME"`
+	LrsPassword           *string   `yaml:"LRS_PASSWORD" mapstructure:"LRS_PASSWORD"`
+	RecommendationSeed    *int64    `yaml:"RECOMMENDATION_SEED" mapstructure:"RECOMMENDATION_SEED"` // varies the order of close course suggestions
 }
@@ -0,0 +1,200 @@
+package content
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+const BundleVersion = 1
+
+// Bundle is a portable course: a zip holding manifest.json, course.md, one markdown file
+// per module, evaluations.json with the step evaluation definitions, and the assets
+// referenced by the markdown under assets/.
+type Bundle struct {
+	Manifest    *BundleManifest
+	Course      *CourseDocument
+	Modules     []*ModuleDocument
+	Evaluations []*BundleEvaluation
+	Assets      map[string][]byte
+}
+
+type BundleManifest struct {
+	Version    int            `json:"version"`
+	ExportedAt time.Time      `json:"exportedAt"`
+	Course     BundleCourse   `json:"course"`
+	Modules    []BundleModule `json:"modules"`
+	Assets     []BundleAsset  `json:"assets"`
+}
+
+type BundleCourse struct {
+	SourceId  uint64 `json:"sourceId"`
+	Name      string `json:"name"`
+	FieldName string `json:"fieldName"`
+	File      string `json:"file"`
+}
+
+type BundleModule struct {
+	SourceId uint64       `json:"sourceId"`
+	Title    string       `json:"title"`
+	File     string       `json:"file"`
+	Steps    []BundleStep `json:"steps"`
+}
+
+type BundleStep struct {
+	SourceId uint64 `json:"sourceId"`
+	Title    string `json:"title"`
+}
+
+type BundleAsset struct {
+	File        string `json:"file"`
+	SourceUrl   string `json:"sourceUrl"`
+	ContentType string `json:"contentType"`
+}
+
+type BundleEvaluation struct {
+	SourceId       uint64 `json:"sourceId"`
+	ModuleSourceId uint64 `json:"moduleSourceId"`
+	StepTitle      string `json:"stepTitle"`
+	Order          int    `json:"order"`
+	Question       string `json:"question"`
+	Type           string `json:"type"`
+	Instruction    string `json:"instruction"`
+	Gem            int    `json:"gem"`
+}
+
+// WriteBundle writes the bundle as a zip archive.
+func WriteBundle(writer io.Writer, bundle *Bundle) error {
+	archive := zip.NewWriter(writer)
+
+	manifest, err := json.MarshalIndent(bundle.Manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	evaluations, err := json.MarshalIndent(bundle.Evaluations, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	files := map[string][]byte{
+		"manifest.json":             manifest,
+		"evaluations.json":          evaluations,
+		bundle.Manifest.Course.File: []byte(RenderCourse(bundle.Course)),
+	}
+	for i, module := range bundle.Manifest.Modules {
+		files[module.File] = []byte(RenderModule(bundle.Modules[i], false))
+	}
+	for file, data := range bundle.Assets {
+		files[file] = data
+	}
+
+	// write the manifest first so the archive is self-describing when streamed
+	names := []string{"manifest.json", "evaluations.json", bundle.Manifest.Course.File}
+	for _, module := range bundle.Manifest.Modules {
+		names = append(names, module.File)
+	}
+	for _, asset := range bundle.Manifest.Assets {
+		names = append(names, asset.File)
+	}
+
+	for _, name := range names {
+		entry, err := archive.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err := entry.Write(files[name]); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+// ReadBundle reads and parses a zip archive written by WriteBundle.
+func ReadBundle(data []byte) (*Bundle, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid bundle archive: %w", err)
+	}
+
+	files := make(map[string][]byte)
+	for _, file := range archive.File {
+		// reject entries escaping the bundle root
+		name := path.Clean(file.Name)
+		if strings.HasPrefix(name, "../") || strings.HasPrefix(name, "/") {
+			return nil, fmt.Errorf("invalid bundle entry: %s", file.Name)
+		}
+
+		reader, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		content, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			return nil, err
+		}
+		files[name] = content
+	}
+
+	bundle := &Bundle{
+		Manifest: new(BundleManifest),
+		Assets:   make(map[string][]byte),
+	}
+
+	if err := readBundleJson(files, "manifest.json", bundle.Manifest); err != nil {
+		return nil, err
+	}
+	if bundle.Manifest.Version != BundleVersion {
+		return nil, fmt.Errorf("unsupported bundle version: %d", bundle.Manifest.Version)
+	}
+	if err := readBundleJson(files, "evaluations.json", &bundle.Evaluations); err != nil {
+		return nil, err
+	}
+
+	courseMarkdown, ok := files[bundle.Manifest.Course.File]
+	if !ok {
+		return nil, fmt.Errorf("bundle is missing %s", bundle.Manifest.Course.File)
+	}
+	if bundle.Course, err = ParseCourse(string(courseMarkdown)); err != nil {
+		return nil, fmt.Errorf("%s: %w", bundle.Manifest.Course.File, err)
+	}
+
+	for _, module := range bundle.Manifest.Modules {
+		moduleMarkdown, ok := files[module.File]
+		if !ok {
+			return nil, fmt.Errorf("bundle is missing %s", module.File)
+		}
+		document, err := ParseModule(string(moduleMarkdown))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", module.File, err)
+		}
+		bundle.Modules = append(bundle.Modules, document)
+	}
+
+	for _, asset := range bundle.Manifest.Assets {
+		data, ok := files[asset.File]
+		if !ok {
+			return nil, fmt.Errorf("bundle is missing %s", asset.File)
+		}
+		bundle.Assets[asset.File] = data
+	}
+
+	return bundle, nil
+}
+
+func readBundleJson(files map[string][]byte, name string, target any) error {
+	data, ok := files[name]
+	if !ok {
+		return fmt.Errorf("bundle is missing %s", name)
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package content
+
+import (
+	"fmt"
+	"strings"
+)
+
+type CourseDocument struct {
+	Name        string
+	ImageUrl    string
+	Description string
+	FieldName   string
+	Contents    []*CourseContentDocument
+}
+
+type CourseContentDocument struct {
+	Line        int
+	Type        string // text or module
+	Text        string
+	ModuleTitle string
+}
+
+// ParseCourse parses an outline course document: a "# <course name>" header with
+// Image, Description and Field metadata, followed by text sections and "## Module"
+// sections listing module titles one per line.
+func ParseCourse(markdown string) (*CourseDocument, error) {
+	lines := strings.Split(markdown, "\n")
+	if len(lines) < 1 || strings.TrimSpace(lines[0]) == "" {
+		return nil, fmt.Errorf("malformed markdown: empty content")
+	}
+
+	document := &CourseDocument{
+		Name: strings.TrimPrefix(strings.TrimSpace(lines[0]), "# "),
+	}
+
+	var currentSection string
+	var contentBuffer strings.Builder
+	contentLine := 0
+	inMetadataSection := false
+
+	flush := func() {
+		document.Contents = append(document.Contents, &CourseContentDocument{
+			Line: contentLine,
+			Type: "text",
+			Text: contentBuffer.String(),
+		})
+		contentBuffer.Reset()
+	}
+
+	for i, line := range lines[1:] {
+		line = strings.TrimSpace(line)
+
+		if strings.HasPrefix(line, "## ") {
+			section := strings.TrimPrefix(line, "## ")
+			inMetadataSection = section == "Image" || section == "Description" || section == "Field"
+
+			if !inMetadataSection && contentBuffer.Len() > 0 {
+				flush()
+			}
+			currentSection = section
+			continue
+		}
+
+		if line == "" {
+			continue
+		}
+
+		switch {
+		case currentSection == "Image":
+			document.ImageUrl = strings.Trim(line, "<>")
+		case currentSection == "Description":
+			document.Description = line
+		case currentSection == "Field":
+			document.FieldName = line
+		case currentSection == "Module":
+			document.Contents = append(document.Contents, &CourseContentDocument{
+				Line:        i + 2,
+				Type:        "module",
+				ModuleTitle: line,
+			})
+		default:
+			if contentBuffer.Len() == 0 {
+				contentLine = i + 2
+			}
+			contentBuffer.WriteString(line + "\n")
+		}
+	}
+
+	if !inMetadataSection && contentBuffer.Len() > 0 {
+		flush()
+	}
+
+	return document, nil
+}
@@ -0,0 +1,213 @@
+package content
+
+import (
+	"backend/internals/utils"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var imagePattern = regexp.MustCompile(`!\[[^\]]*\]\(([^)\s]*)[^)]*\)`)
+
+// metadata images are written as a bare <url> line
+var imageMetaPattern = regexp.MustCompile(`^<(https?://[^>]+)>$`)
+
+var stepSections = []string{"Description", "Content", "Outcome", "Check", "Error"}
+
+// Problem is a lint finding at a 1-based line of a document.
+type Problem struct {
+	Line    int
+	Message string
+}
+
+func (p *Problem) String() string {
+	return fmt.Sprintf("%d: %s", p.Line, p.Message)
+}
+
+type ImageLink struct {
+	Line int
+	Url  string
+}
+
+// LintModule checks a module document and reports every problem found, unlike ParseModule
+// which stops at the first one. The returned document holds whatever could be read and is
+// nil when the module header is missing.
+func LintModule(markdown string) (*ModuleDocument, []*Problem) {
+	lines := strings.Split(markdown, "\n")
+	problems := make([]*Problem, 0)
+
+	if !strings.HasPrefix(lines[0], "# ") || strings.HasPrefix(lines[0], "# Step") {
+		return nil, append(problems, &Problem{Line: 1, Message: "missing module title"})
+	}
+	document := &ModuleDocument{
+		Title: strings.TrimPrefix(lines[0], "# "),
+	}
+
+	titles := make(map[string]int)
+	var step *StepDocument
+	var sections map[string]bool
+	var section string
+	var evaluations []int
+
+	finishStep := func() {
+		if step == nil {
+			return
+		}
+		for _, required := range stepSections {
+			if !sections[required] {
+				problems = append(problems, &Problem{Line: step.Line, Message: fmt.Sprintf("step %q is missing required section %s", step.Title, required)})
+			}
+		}
+		problems = append(problems, lintEvaluations(lines, evaluations)...)
+	}
+
+	for i, line := range lines[1:] {
+		lineNumber := i + 2
+
+		if strings.HasPrefix(line, "# Step") {
+			finishStep()
+
+			titleParts := strings.Split(strings.TrimSpace(line), ": ")
+			step = &StepDocument{
+				Line:  lineNumber,
+				Title: titleParts[len(titleParts)-1],
+			}
+			sections = make(map[string]bool)
+			section = ""
+			evaluations = nil
+
+			if previous, ok := titles[step.Title]; ok {
+				problems = append(problems, &Problem{Line: lineNumber, Message: fmt.Sprintf("duplicate step title %q, first used on line %d", step.Title, previous)})
+			} else {
+				titles[step.Title] = lineNumber
+			}
+			document.Steps = append(document.Steps, step)
+			continue
+		}
+
+		if strings.HasPrefix(line, "## ") {
+			section = strings.TrimSpace(strings.TrimPrefix(line, "## "))
+			if step != nil && section != "Evaluation" && !utils.Contains(stepSections, section) {
+				problems = append(problems, &Problem{Line: lineNumber, Message: fmt.Sprintf("unknown section %s, its content is ignored", section)})
+			}
+			continue
+		}
+
+		if step == nil {
+			// module metadata, the parser reads the value two lines below the heading
+			if strings.TrimSpace(line) != "" && (section == "Image" || section == "Description") {
+				if section == "Image" {
+					document.ImageUrl = strings.Trim(strings.TrimSpace(line), "<>")
+				} else {
+					document.Description = strings.TrimSpace(line)
+				}
+			}
+			continue
+		}
+
+		switch {
+		case section == "Evaluation":
+			if strings.HasPrefix(strings.TrimSpace(line), "* ") {
+				evaluations = append(evaluations, lineNumber)
+			}
+		case utils.Contains(stepSections, section):
+			// sections holding only blank lines are dropped on import
+			if strings.TrimSpace(line) != "" {
+				sections[section] = true
+			}
+		}
+	}
+	finishStep()
+
+	if len(document.Steps) == 0 {
+		problems = append(problems, &Problem{Line: 1, Message: "module has no steps"})
+	}
+
+	return document, problems
+}
+
+// lintEvaluations checks the evaluation bullets at the given line numbers, which come in
+// groups of question, type, instruction and gem.
+func lintEvaluations(lines []string, evaluationLines []int) []*Problem {
+	problems := make([]*Problem, 0)
+	bullet := func(lineNumber int) string {
+		return strings.TrimPrefix(strings.TrimSpace(lines[lineNumber-1]), "* ")
+	}
+
+	for i := 0; i < len(evaluationLines); i += 4 {
+		if i+3 >= len(evaluationLines) {
+			problems = append(problems, &Problem{Line: evaluationLines[i], Message: fmt.Sprintf("incomplete evaluation, expected 4 items but found %d", len(evaluationLines)-i)})
+			break
+		}
+
+		evalType := bullet(evaluationLines[i+1])
+		if evalType != "check" && evalType != "text" && evalType != "image" {
+			problems = append(problems, &Problem{Line: evaluationLines[i+1], Message: fmt.Sprintf("invalid evaluation type %q, expected check, text or image", evalType)})
+		}
+
+		gem, err := strconv.ParseInt(bullet(evaluationLines[i+3]), 10, 64)
+		if err != nil {
+			problems = append(problems, &Problem{Line: evaluationLines[i+3], Message: fmt.Sprintf("invalid gem value %q", bullet(evaluationLines[i+3]))})
+		} else if gem < 0 {
+			problems = append(problems, &Problem{Line: evaluationLines[i+3], Message: fmt.Sprintf("negative gem value %d", gem)})
+		}
+	}
+
+	return problems
+}
+
+// LintCourse checks a course document. Module titles and the field are returned in the
+// document for the caller to resolve.
+func LintCourse(markdown string) (*CourseDocument, []*Problem) {
+	problems := make([]*Problem, 0)
+
+	document, err := ParseCourse(markdown)
+	if err != nil {
+		return nil, append(problems, &Problem{Line: 1, Message: err.Error()})
+	}
+
+	lines := strings.Split(markdown, "\n")
+	if !strings.HasPrefix(strings.TrimSpace(lines[0]), "# ") {
+		problems = append(problems, &Problem{Line: 1, Message: "missing course title"})
+	}
+	if document.FieldName == "" {
+		problems = append(problems, &Problem{Line: 1, Message: "missing Field section"})
+	}
+
+	modules := make(map[string]int)
+	for _, courseContent := range document.Contents {
+		if courseContent.Type != "module" {
+			continue
+		}
+		if previous, ok := modules[courseContent.ModuleTitle]; ok {
+			problems = append(problems, &Problem{Line: courseContent.Line, Message: fmt.Sprintf("module %q is already listed on line %d", courseContent.ModuleTitle, previous)})
+			continue
+		}
+		modules[courseContent.ModuleTitle] = courseContent.Line
+	}
+
+	return document, problems
+}
+
+// FindImages returns the markdown image links of the document, reporting links without
+// a target as problems.
+func FindImages(markdown string) ([]*ImageLink, []*Problem) {
+	images := make([]*ImageLink, 0)
+	problems := make([]*Problem, 0)
+
+	for i, line := range strings.Split(markdown, "\n") {
+		if match := imageMetaPattern.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+			images = append(images, &ImageLink{Line: i + 1, Url: match[1]})
+		}
+		for _, match := range imagePattern.FindAllStringSubmatch(line, -1) {
+			if match[1] == "" {
+				problems = append(problems, &Problem{Line: i + 1, Message: "image link without target"})
+				continue
+			}
+			images = append(images, &ImageLink{Line: i + 1, Url: match[1]})
+		}
+	}
+
+	return images, problems
+}
@@ -0,0 +1,142 @@
+package content
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type ModuleDocument struct {
+	Title       string
+	ImageUrl    string
+	Description string
+	Steps       []*StepDocument
+}
+
+type StepDocument struct {
+	Line        int
+	Title       string
+	Description string
+	Content     string
+	Outcome     string
+	Check       string
+	Error       string
+	Evaluations []*EvaluationDocument
+}
+
+type EvaluationDocument struct {
+	Line        int
+	Order       int
+	Question    string
+	Type        string
+	Instruction string
+	Gem         int
+}
+
+// ParseModule parses an outline module document: a "# <module title>" header with
+// Image and Description metadata, followed by "# Step" sections.
+func ParseModule(markdown string) (*ModuleDocument, error) {
+	// split content into sections
+	sections := strings.Split(markdown, "\n# Step")
+	if len(sections) < 2 {
+		return nil, fmt.Errorf("malformed markdown: missing module")
+	}
+
+	// process module title and metadata
+	header := strings.Split(sections[0], "\n")
+	document := &ModuleDocument{
+		Title: strings.TrimPrefix(header[0], "# "),
+	}
+	meta := header[1:]
+	for i, line := range meta {
+		if i+2 >= len(meta) {
+			break
+		}
+		if strings.Contains(line, "Image") {
+			document.ImageUrl = strings.TrimPrefix(strings.TrimSuffix(meta[i+2], ">"), "<")
+		}
+		if strings.Contains(line, "Description") {
+			document.Description = strings.TrimSpace(meta[i+2])
+		}
+	}
+
+	// process each step section
+	line := len(header)
+	for _, section := range sections[1:] {
+		lines := strings.Split(section, "\n")
+		step, err := parseStep(lines, line+1)
+		if err != nil {
+			return nil, err
+		}
+		document.Steps = append(document.Steps, step)
+		line += len(lines)
+	}
+
+	return document, nil
+}
+
+func parseStep(lines []string, line int) (*StepDocument, error) {
+	stepTitleParts := strings.Split(strings.TrimSpace(lines[0]), ": ")
+	step := &StepDocument{
+		Line:  line,
+		Title: stepTitleParts[len(stepTitleParts)-1],
+	}
+
+	currentSection := ""
+	var evalBuffer []string
+	var evalLines []int
+
+	for i, content := range lines[1:] {
+		if strings.HasPrefix(content, "## ") {
+			currentSection = strings.TrimSpace(strings.TrimPrefix(content, "## "))
+			continue
+		}
+
+		switch currentSection {
+		case "Description":
+			step.Description += content + "\n"
+		case "Content":
+			step.Content += content + "\n"
+		case "Outcome":
+			step.Outcome += content + "\n"
+		case "Check":
+			step.Check += content + "\n"
+		case "Error":
+			step.Error += content + "\n"
+		case "Evaluation":
+			if strings.HasPrefix(strings.TrimSpace(content), "* ") {
+				evalBuffer = append(evalBuffer, strings.TrimPrefix(strings.TrimSpace(content), "* "))
+				evalLines = append(evalLines, line+i+1)
+			}
+		}
+	}
+
+	// verify required sections
+	if step.Description == "" || step.Content == "" || step.Outcome == "" || step.Check == "" || step.Error == "" {
+		return nil, fmt.Errorf("malformed markdown: missing required sections, stepTitle: %s", step.Title)
+	}
+
+	for i := 0; i < len(evalBuffer); i += 4 {
+		if i+3 >= len(evalBuffer) {
+			return nil, fmt.Errorf("malformed markdown: incomplete evaluation, stepTitle: %s", step.Title)
+		}
+
+		evalType := evalBuffer[i+1]
+		if evalType != "check" && evalType != "text" && evalType != "image" {
+			return nil, fmt.Errorf("malformed markdown: invalid evaluation type; evalType: %s", evalType)
+		}
+
+		gem, _ := strconv.ParseInt(evalBuffer[i+3], 10, 64)
+
+		step.Evaluations = append(step.Evaluations, &EvaluationDocument{
+			Line:        evalLines[i],
+			Order:       i/4 + 1,
+			Question:    evalBuffer[i],
+			Type:        evalType,
+			Instruction: evalBuffer[i+2],
+			Gem:         int(gem),
+		})
+	}
+
+	return step, nil
+}
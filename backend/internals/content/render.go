@@ -0,0 +1,77 @@
+package content
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RenderModule renders a module document back into the outline markdown understood by ParseModule.
+// Evaluations are rendered only when includeEvaluations is set.
+func RenderModule(document *ModuleDocument, includeEvaluations bool) string {
+	var builder strings.Builder
+
+	builder.WriteString("# " + document.Title + "\n")
+	builder.WriteString("## Image\n\n<" + document.ImageUrl + ">\n")
+	builder.WriteString("## Description\n\n" + document.Description + "\n")
+
+	for i, step := range document.Steps {
+		builder.WriteString(fmt.Sprintf("# Step %d: %s\n", i+1, step.Title))
+		writeSection(&builder, "Description", step.Description)
+		writeSection(&builder, "Content", step.Content)
+		writeSection(&builder, "Outcome", step.Outcome)
+		writeSection(&builder, "Check", step.Check)
+		writeSection(&builder, "Error", step.Error)
+
+		if includeEvaluations && len(step.Evaluations) > 0 {
+			builder.WriteString("## Evaluation\n")
+			for _, evaluation := range step.Evaluations {
+				builder.WriteString("* " + evaluation.Question + "\n")
+				builder.WriteString("* " + evaluation.Type + "\n")
+				builder.WriteString("* " + evaluation.Instruction + "\n")
+				builder.WriteString(fmt.Sprintf("* %d\n", evaluation.Gem))
+			}
+		}
+	}
+
+	// the last section would otherwise gain a blank line on every round trip
+	return strings.TrimSuffix(builder.String(), "\n")
+}
+
+// RenderCourse renders a course document back into the outline markdown understood by ParseCourse.
+func RenderCourse(document *CourseDocument) string {
+	var builder strings.Builder
+
+	builder.WriteString("# " + document.Name + "\n")
+	if document.ImageUrl != "" {
+		builder.WriteString("## Image\n\n<" + document.ImageUrl + ">\n")
+	}
+	if document.Description != "" {
+		builder.WriteString("## Description\n\n" + document.Description + "\n")
+	}
+	builder.WriteString("## Field\n\n" + document.FieldName + "\n")
+
+	inModuleSection := false
+	for _, content := range document.Contents {
+		if content.Type == "module" {
+			if !inModuleSection {
+				builder.WriteString("## Module\n\n")
+				inModuleSection = true
+			}
+			builder.WriteString(content.ModuleTitle + "\n")
+			continue
+		}
+
+		inModuleSection = false
+		writeSection(&builder, "Text", content.Text)
+	}
+
+	return builder.String()
+}
+
+func writeSection(builder *strings.Builder, heading string, text string) {
+	builder.WriteString("## " + heading + "\n")
+	builder.WriteString(text)
+	if !strings.HasSuffix(text, "\n") {
+		builder.WriteString("\n")
+	}
+}
@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"backend/internals/entities/response"
+	"backend/internals/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type AchievementController struct {
+	achievementSvc services.AchievementService
+}
+
+func NewAchievementController(achievementSvc services.AchievementService) *AchievementController {
+	return &AchievementController{
+		achievementSvc: achievementSvc,
+	}
+}
+
+// GetAchievements
+// @ID getAchievements
+// @Tags achievement
+// @Summary List the achievements earned by the user and those still locked with their progress
+// @Produce json
+// @Success 200 {object} response.InfoResponse[[]payload.AchievementInfo]
+// @Failure 400 {object} response.GenericError
+// @Router /achievements [get]
+func (r *AchievementController) GetAchievements(c *fiber.Ctx) error {
+	// * login state
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["userId"].(float64)
+
+	achievements, err := r.achievementSvc.GetAchievements(uint64(userId))
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to get achievements",
+		}
+	}
+
+	return response.Ok(c, achievements)
+}
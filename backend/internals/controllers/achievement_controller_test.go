@@ -0,0 +1,86 @@
+package controllers_test
+
+import (
+	"backend/internals/controllers"
+	"backend/internals/entities/payload"
+	"backend/internals/entities/response"
+	"backend/internals/routes/handler"
+	mockServices "backend/mocks/services"
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type AchievementControllerTestSuite struct {
+	suite.Suite
+}
+
+func setupTestAchievementController(mockAchievementService *mockServices.AchievementService) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: handler.ErrorHandler,
+	})
+
+	controller := controllers.NewAchievementController(mockAchievementService)
+
+	// Middleware to simulate JWT Locals
+	app.Use(func(c *fiber.Ctx) error {
+		token := jwt.New(jwt.SigningMethodHS256)
+		claims := token.Claims.(jwt.MapClaims)
+		claims["userId"] = float64(123)
+		c.Locals("user", token)
+		return c.Next()
+	})
+
+	app.Get("/achievements", controller.GetAchievements)
+	return app
+}
+
+func (suite *AchievementControllerTestSuite) TestGetAchievementsWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockAchievementService := new(mockServices.AchievementService)
+	app := setupTestAchievementController(mockAchievementService)
+
+	mockAchievementService.EXPECT().GetAchievements(uint64(123)).Return([]*payload.AchievementInfo{
+		{Code: "first_comment", Threshold: 1, Progress: 1, Earned: true},
+		{Code: "ten_upvotes", Threshold: 10, Progress: 4},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/achievements", nil)
+	res, err := app.Test(req)
+
+	var responsePayload response.InfoResponse[[]payload.AchievementInfo]
+	resBody, _ := io.ReadAll(res.Body)
+	json.Unmarshal(resBody, &responsePayload)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Len(responsePayload.Data, 2)
+	is.Equal(int64(4), responsePayload.Data[1].Progress)
+}
+
+func (suite *AchievementControllerTestSuite) TestGetAchievementsWhenFailed() {
+	is := assert.New(suite.T())
+
+	mockAchievementService := new(mockServices.AchievementService)
+	app := setupTestAchievementController(mockAchievementService)
+
+	mockAchievementService.EXPECT().GetAchievements(uint64(123)).Return(nil, fmt.Errorf("database is down"))
+
+	req := httptest.NewRequest(http.MethodGet, "/achievements", nil)
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusInternalServerError, res.StatusCode)
+}
+
+func TestAchievementController(t *testing.T) {
+	suite.Run(t, new(AchievementControllerTestSuite))
+}
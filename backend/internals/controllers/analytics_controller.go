@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"backend/internals/entities/payload"
+	"backend/internals/entities/response"
+	"backend/internals/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AnalyticsController struct {
+	analyticsSvc services.AnalyticsService
+}
+
+func NewAnalyticsController(analyticsSvc services.AnalyticsService) *AnalyticsController {
+	return &AnalyticsController{
+		analyticsSvc: analyticsSvc,
+	}
+}
+
+// GetStepFunnel
+// @ID getStepFunnel
+// @Tags instructor
+// @Summary Get how many learners reach and pass each step of a course, with attempts, time to pass and the biggest drop-offs
+// @Produce json
+// @Param courseId path uint64 true "Course ID"
+// @Param q query payload.StepFunnelQuery false "StepFunnelQuery"
+// @Success 200 {object} response.InfoResponse[payload.StepFunnelReport]
+// @Failure 400 {object} response.GenericError
+// @Router /instructor/analytics/courses/{courseId}/steps [get]
+func (r *AnalyticsController) GetStepFunnel(c *fiber.Ctx) error {
+	param := new(payload.CourseIdParam)
+	if err := c.ParamsParser(param); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid courseId parameter",
+		}
+	}
+
+	query := new(payload.StepFunnelQuery)
+	if err := c.QueryParser(query); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid query",
+		}
+	}
+
+	report, err := r.analyticsSvc.GetStepFunnel(uint64(param.CourseId), query.ModuleId)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to get step funnel",
+		}
+	}
+
+	return response.Ok(c, report)
+}
+
+// GetCourseTimeSpent
+// @ID getCourseTimeSpent
+// @Tags instructor
+// @Summary Get the active time of the learners of a course per module and step
+// @Produce json
+// @Param courseId path uint64 true "Course ID"
+// @Success 200 {object} response.InfoResponse[payload.CourseTimeSpentReport]
+// @Failure 400 {object} response.GenericError
+// @Router /instructor/analytics/courses/{courseId}/time-spent [get]
+func (r *AnalyticsController) GetCourseTimeSpent(c *fiber.Ctx) error {
+	param := new(payload.CourseIdParam)
+	if err := c.ParamsParser(param); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid courseId parameter",
+		}
+	}
+
+	report, err := r.analyticsSvc.GetCourseTimeSpent(uint64(param.CourseId))
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to get time spent",
+		}
+	}
+
+	return response.Ok(c, report)
+}
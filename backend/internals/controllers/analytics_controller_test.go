@@ -0,0 +1,105 @@
+package controllers_test
+
+import (
+	"backend/internals/controllers"
+	"backend/internals/entities/payload"
+	"backend/internals/entities/response"
+	"backend/internals/routes/handler"
+	"backend/internals/utils"
+	mockServices "backend/mocks/services"
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type AnalyticsControllerTestSuite struct {
+	suite.Suite
+}
+
+func setupTestAnalyticsController(mockAnalyticsService *mockServices.AnalyticsService) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: handler.ErrorHandler,
+	})
+
+	controller := controllers.NewAnalyticsController(mockAnalyticsService)
+
+	app.Get("/instructor/analytics/courses/:courseId/steps", controller.GetStepFunnel)
+	app.Get("/instructor/analytics/courses/:courseId/time-spent", controller.GetCourseTimeSpent)
+	return app
+}
+
+func (suite *AnalyticsControllerTestSuite) TestGetStepFunnelWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockAnalyticsService := new(mockServices.AnalyticsService)
+	app := setupTestAnalyticsController(mockAnalyticsService)
+
+	mockAnalyticsService.EXPECT().GetStepFunnel(uint64(3), utils.Ptr(uint64(5))).Return(&payload.StepFunnelReport{
+		CourseId: utils.Ptr(uint64(3)),
+		ModuleId: utils.Ptr(uint64(5)),
+		Steps: []*payload.StepFunnel{
+			{StepId: utils.Ptr(uint64(1)), Reached: 40, DropOff: 12, Highlighted: true},
+			{StepId: utils.Ptr(uint64(2)), Reached: 28},
+		},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/instructor/analytics/courses/3/steps?moduleId=5", nil)
+	res, err := app.Test(req)
+
+	var responsePayload response.InfoResponse[payload.StepFunnelReport]
+	resBody, _ := io.ReadAll(res.Body)
+	json.Unmarshal(resBody, &responsePayload)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Len(responsePayload.Data.Steps, 2)
+	is.True(responsePayload.Data.Steps[0].Highlighted)
+}
+
+func (suite *AnalyticsControllerTestSuite) TestGetStepFunnelWhenFailed() {
+	is := assert.New(suite.T())
+
+	mockAnalyticsService := new(mockServices.AnalyticsService)
+	app := setupTestAnalyticsController(mockAnalyticsService)
+
+	mockAnalyticsService.EXPECT().GetStepFunnel(uint64(3), (*uint64)(nil)).Return(nil, fmt.Errorf("database error"))
+
+	req := httptest.NewRequest(http.MethodGet, "/instructor/analytics/courses/3/steps", nil)
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusInternalServerError, res.StatusCode)
+}
+
+func (suite *AnalyticsControllerTestSuite) TestGetCourseTimeSpentWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockAnalyticsService := new(mockServices.AnalyticsService)
+	app := setupTestAnalyticsController(mockAnalyticsService)
+
+	mockAnalyticsService.EXPECT().GetCourseTimeSpent(uint64(3)).Return(&payload.CourseTimeSpentReport{
+		CourseId: utils.Ptr(uint64(3)),
+		Steps:    []*payload.TimeSpentStats{{Id: utils.Ptr(uint64(8)), Learners: 2, TotalSeconds: 600}},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/instructor/analytics/courses/3/time-spent", nil)
+	res, err := app.Test(req)
+
+	var responsePayload response.InfoResponse[payload.CourseTimeSpentReport]
+	resBody, _ := io.ReadAll(res.Body)
+	json.Unmarshal(resBody, &responsePayload)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Len(responsePayload.Data.Steps, 1)
+}
+
+func TestAnalyticsController(t *testing.T) {
+	suite.Run(t, new(AnalyticsControllerTestSuite))
+}
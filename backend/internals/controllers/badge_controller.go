@@ -0,0 +1,333 @@
+package controllers
+
+import (
+	"backend/internals/entities/payload"
+	"backend/internals/entities/response"
+	"backend/internals/services"
+	"backend/internals/utils"
+	"encoding/json"
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type BadgeController struct {
+	badgeSvc services.BadgeService
+}
+
+func NewBadgeController(badgeSvc services.BadgeService) *BadgeController {
+	return &BadgeController{
+		badgeSvc: badgeSvc,
+	}
+}
+
+// CreateBadgeClass
+// @ID createBadgeClass
+// @Tags admin
+// @Summary Define the Open Badge of a course, or of a module of the course
+// @Accept multipart/form-data
+// @Produce json
+// @Param data formData string true "JSON of payload.CreateBadgeClass"
+// @Param image formData file true "PNG image of the badge"
+// @Success 200 {object} response.InfoResponse[payload.BadgeClassInfo]
+// @Failure 400 {object} response.GenericError
+// @Router /admin/badges/classes [post]
+func (r *BadgeController) CreateBadgeClass(c *fiber.Ctx) error {
+	// Parse JSON from "data" form field
+	body := new(payload.CreateBadgeClass)
+	if err := json.Unmarshal([]byte(c.FormValue("data")), body); err != nil {
+		return &response.GenericError{
+			Err: err,
+		}
+	}
+
+	// * validate body
+	if err := utils.Validate.Struct(body); err != nil {
+		var validationErrors validator.ValidationErrors
+		errors.As(err, &validationErrors)
+		return &response.GenericError{
+			Err: validationErrors,
+		}
+	}
+
+	image, err := c.FormFile("image")
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "image not found",
+		}
+	}
+
+	badgeClass, err := r.badgeSvc.CreateBadgeClass(body, image)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to create badge class",
+		}
+	}
+
+	return response.Ok(c, badgeClass)
+}
+
+// RevokeAssertion
+// @ID revokeBadgeAssertion
+// @Tags admin
+// @Summary Revoke an issued badge
+// @Accept json
+// @Produce json
+// @Param assertionId path string true "Assertion ID"
+// @Param q body payload.RevokeBadgeAssertion true "RevokeBadgeAssertion"
+// @Success 200 {object} response.InfoResponse[payload.BadgeAssertionInfo]
+// @Failure 400 {object} response.GenericError
+// @Router /admin/badges/assertions/{assertionId}/revoke [post]
+func (r *BadgeController) RevokeAssertion(c *fiber.Ctx) error {
+	param := new(payload.BadgeAssertionIdParam)
+	if err := c.ParamsParser(param); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid assertionId parameter",
+		}
+	}
+
+	body := new(payload.RevokeBadgeAssertion)
+	if err := c.BodyParser(body); err != nil {
+		return &response.GenericError{
+			Err: err,
+		}
+	}
+
+	// * validate body
+	if err := utils.Validate.Struct(body); err != nil {
+		var validationErrors validator.ValidationErrors
+		errors.As(err, &validationErrors)
+		return &response.GenericError{
+			Err: validationErrors,
+		}
+	}
+
+	assertion, err := r.badgeSvc.RevokeAssertion(*param.AssertionId, body)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to revoke badge",
+		}
+	}
+
+	return response.Ok(c, assertion)
+}
+
+// ClaimBadge
+// @ID claimBadge
+// @Tags badge
+// @Summary Claim the badge of a fully passed course or module
+// @Accept json
+// @Produce json
+// @Param badgeClassId path uint64 true "Badge class ID"
+// @Success 200 {object} response.InfoResponse[payload.BadgeAssertionInfo]
+// @Failure 400 {object} response.GenericError
+// @Router /badge/classes/{badgeClassId}/claim [post]
+func (r *BadgeController) ClaimBadge(c *fiber.Ctx) error {
+	param := new(payload.BadgeClassIdParam)
+	if err := c.ParamsParser(param); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid badgeClassId parameter",
+		}
+	}
+
+	// * login state
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["userId"].(float64)
+
+	assertion, err := r.badgeSvc.ClaimBadge(uint64(userId), *param.BadgeClassId)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to claim badge",
+		}
+	}
+
+	return response.Ok(c, assertion)
+}
+
+// GetMyBadges
+// @ID getMyBadges
+// @Tags badge
+// @Summary Get the badges issued to the logged in user
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.InfoResponse[[]payload.BadgeAssertionInfo]
+// @Failure 400 {object} response.GenericError
+// @Router /badge/mine [get]
+func (r *BadgeController) GetMyBadges(c *fiber.Ctx) error {
+	// * login state
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["userId"].(float64)
+
+	assertions, err := r.badgeSvc.GetMyBadges(uint64(userId))
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to get badges",
+		}
+	}
+
+	return response.Ok(c, assertions)
+}
+
+// GetIssuer
+// @ID getBadgeIssuer
+// @Tags badge
+// @Summary Get the Open Badges issuer profile
+// @Produce json
+// @Success 200 {object} payload.OpenBadgeIssuer
+// @Failure 400 {object} response.GenericError
+// @Router /badges/issuer [get]
+func (r *BadgeController) GetIssuer(c *fiber.Ctx) error {
+	issuer, err := r.badgeSvc.GetIssuer()
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to get issuer",
+		}
+	}
+
+	return c.JSON(issuer)
+}
+
+// GetPublicKey
+// @ID getBadgePublicKey
+// @Tags badge
+// @Summary Get the public key signed badges are verified with
+// @Produce json
+// @Success 200 {object} payload.OpenBadgeCryptographicKey
+// @Failure 400 {object} response.GenericError
+// @Router /badges/issuer/key [get]
+func (r *BadgeController) GetPublicKey(c *fiber.Ctx) error {
+	key, err := r.badgeSvc.GetPublicKey()
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to get public key",
+		}
+	}
+
+	return c.JSON(key)
+}
+
+// GetRevocationList
+// @ID getBadgeRevocationList
+// @Tags badge
+// @Summary Get the revoked badge assertions
+// @Produce json
+// @Success 200 {object} payload.OpenBadgeRevocationList
+// @Failure 400 {object} response.GenericError
+// @Router /badges/revocations [get]
+func (r *BadgeController) GetRevocationList(c *fiber.Ctx) error {
+	revocationList, err := r.badgeSvc.GetRevocationList()
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to get revocation list",
+		}
+	}
+
+	return c.JSON(revocationList)
+}
+
+// GetBadgeClass
+// @ID getBadgeClass
+// @Tags badge
+// @Summary Get an Open Badges badge class
+// @Produce json
+// @Param badgeClassId path uint64 true "Badge class ID"
+// @Success 200 {object} payload.OpenBadgeClass
+// @Failure 400 {object} response.GenericError
+// @Router /badges/classes/{badgeClassId} [get]
+func (r *BadgeController) GetBadgeClass(c *fiber.Ctx) error {
+	param := new(payload.BadgeClassIdParam)
+	if err := c.ParamsParser(param); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid badgeClassId parameter",
+		}
+	}
+
+	badgeClass, err := r.badgeSvc.GetBadgeClass(*param.BadgeClassId)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to get badge class",
+		}
+	}
+
+	return c.JSON(badgeClass)
+}
+
+// GetAssertion
+// @ID getBadgeAssertion
+// @Tags badge
+// @Summary Get a hosted Open Badges assertion, answered with 410 Gone once revoked
+// @Produce json
+// @Param assertionId path string true "Assertion ID"
+// @Success 200 {object} payload.OpenBadgeAssertion
+// @Failure 400 {object} response.GenericError
+// @Router /badges/assertions/{assertionId} [get]
+func (r *BadgeController) GetAssertion(c *fiber.Ctx) error {
+	param := new(payload.BadgeAssertionIdParam)
+	if err := c.ParamsParser(param); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid assertionId parameter",
+		}
+	}
+
+	assertion, err := r.badgeSvc.GetAssertion(*param.AssertionId)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to get badge assertion",
+		}
+	}
+
+	if assertion.Revoked {
+		return c.Status(fiber.StatusGone).JSON(assertion)
+	}
+
+	return c.JSON(assertion)
+}
+
+// GetBakedBadge
+// @ID getBakedBadge
+// @Tags badge
+// @Summary Download the badge PNG with the assertion baked in
+// @Produce png
+// @Param assertionId path string true "Assertion ID"
+// @Success 200 {file} file
+// @Failure 400 {object} response.GenericError
+// @Router /badges/assertions/{assertionId}/image [get]
+func (r *BadgeController) GetBakedBadge(c *fiber.Ctx) error {
+	param := new(payload.BadgeAssertionIdParam)
+	if err := c.ParamsParser(param); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid assertionId parameter",
+		}
+	}
+
+	png, err := r.badgeSvc.BakeBadge(*param.AssertionId)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to bake badge",
+		}
+	}
+
+	c.Set(fiber.HeaderContentType, "image/png")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="badge.png"`)
+	return c.Send(png)
+}
@@ -0,0 +1,116 @@
+package controllers_test
+
+import (
+	"backend/internals/controllers"
+	"backend/internals/entities/payload"
+	"backend/internals/routes/handler"
+	"backend/internals/utils"
+	mockServices "backend/mocks/services"
+	"bytes"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type BadgeControllerTestSuite struct {
+	suite.Suite
+}
+
+func setupTestBadgeController(mockBadgeService *mockServices.BadgeService) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: handler.ErrorHandler,
+	})
+
+	controller := controllers.NewBadgeController(mockBadgeService)
+
+	// hosted documents are public, no JWT
+	app.Get("/badges/assertions/:assertionId", controller.GetAssertion)
+	app.Get("/badges/assertions/:assertionId/image", controller.GetBakedBadge)
+
+	// Middleware to simulate JWT Locals
+	app.Use(func(c *fiber.Ctx) error {
+		token := jwt.New(jwt.SigningMethodHS256)
+		claims := token.Claims.(jwt.MapClaims)
+		claims["userId"] = float64(123)
+		c.Locals("user", token)
+		return c.Next()
+	})
+
+	app.Post("/badge/classes/:badgeClassId/claim", controller.ClaimBadge)
+	app.Post("/admin/badges/assertions/:assertionId/revoke", controller.RevokeAssertion)
+	return app
+}
+
+func (suite *BadgeControllerTestSuite) TestGetAssertionWhenRevoked() {
+	is := assert.New(suite.T())
+
+	mockBadgeService := new(mockServices.BadgeService)
+	app := setupTestBadgeController(mockBadgeService)
+
+	mockBadgeService.EXPECT().GetAssertion("0b6c8d2e").Return(&payload.OpenBadgeAssertion{
+		Context: payload.OpenBadgesContext,
+		Id:      "https://learn.example.com/api/badges/assertions/0b6c8d2e",
+		Revoked: true,
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/badges/assertions/0b6c8d2e", nil)
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusGone, res.StatusCode)
+}
+
+func (suite *BadgeControllerTestSuite) TestGetBakedBadgeWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockBadgeService := new(mockServices.BadgeService)
+	app := setupTestBadgeController(mockBadgeService)
+
+	mockBadgeService.EXPECT().BakeBadge("0b6c8d2e").Return([]byte("\x89PNG"), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/badges/assertions/0b6c8d2e/image", nil)
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Equal("image/png", res.Header.Get("Content-Type"))
+}
+
+func (suite *BadgeControllerTestSuite) TestClaimBadgeWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockBadgeService := new(mockServices.BadgeService)
+	app := setupTestBadgeController(mockBadgeService)
+
+	mockBadgeService.EXPECT().ClaimBadge(uint64(123), uint64(4)).Return(&payload.BadgeAssertionInfo{AssertionId: utils.Ptr("0b6c8d2e")}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/badge/classes/4/claim", nil)
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+}
+
+func (suite *BadgeControllerTestSuite) TestRevokeAssertionWhenValidationFailed() {
+	is := assert.New(suite.T())
+
+	mockBadgeService := new(mockServices.BadgeService)
+	app := setupTestBadgeController(mockBadgeService)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/badges/assertions/0b6c8d2e/revoke", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusBadRequest, res.StatusCode)
+	mockBadgeService.AssertNotCalled(suite.T(), "RevokeAssertion", mock.Anything, mock.Anything)
+}
+
+func TestBadgeController(t *testing.T) {
+	suite.Run(t, new(BadgeControllerTestSuite))
+}
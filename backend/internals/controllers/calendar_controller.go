@@ -0,0 +1,176 @@
+package controllers
+
+import (
+	"backend/internals/entities/payload"
+	"backend/internals/entities/response"
+	"backend/internals/services"
+	"backend/internals/utils"
+	"errors"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type CalendarController struct {
+	calendarSvc services.CalendarService
+}
+
+func NewCalendarController(calendarSvc services.CalendarService) *CalendarController {
+	return &CalendarController{
+		calendarSvc: calendarSvc,
+	}
+}
+
+// CreateEvent
+// @ID createCalendarEvent
+// @Tags admin
+// @Summary Create a deadline or an event of a course, or of one of its cohorts
+// @Accept json
+// @Produce json
+// @Param q body payload.CreateCalendarEvent true "CreateCalendarEvent"
+// @Success 200 {object} response.InfoResponse[payload.CalendarEventInfo]
+// @Failure 400 {object} response.GenericError
+// @Router /admin/calendar/events [post]
+func (r *CalendarController) CreateEvent(c *fiber.Ctx) error {
+	body := new(payload.CreateCalendarEvent)
+	if err := c.BodyParser(body); err != nil {
+		return &response.GenericError{
+			Err: err,
+		}
+	}
+
+	// * validate body
+	if err := utils.Validate.Struct(body); err != nil {
+		var validationErrors validator.ValidationErrors
+		errors.As(err, &validationErrors)
+		return &response.GenericError{
+			Err: validationErrors,
+		}
+	}
+
+	event, err := r.calendarSvc.CreateEvent(body)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to create calendar event",
+		}
+	}
+
+	return response.Ok(c, event)
+}
+
+// CreateToken
+// @ID createCalendarToken
+// @Tags calendar
+// @Summary Create the calendar feed URL of the user, revoking the previous one
+// @Produce json
+// @Success 200 {object} response.InfoResponse[payload.CalendarFeed]
+// @Failure 400 {object} response.GenericError
+// @Router /calendar/token [post]
+func (r *CalendarController) CreateToken(c *fiber.Ctx) error {
+	// * login state
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["userId"].(float64)
+
+	feed, err := r.calendarSvc.CreateToken(uint64(userId))
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to create calendar token",
+		}
+	}
+
+	return response.Ok(c, feed)
+}
+
+// RevokeToken
+// @ID revokeCalendarToken
+// @Tags calendar
+// @Summary Revoke the calendar feed URL of the user
+// @Produce json
+// @Success 200 {object} response.InfoResponse[string]
+// @Failure 400 {object} response.GenericError
+// @Router /calendar/token [delete]
+func (r *CalendarController) RevokeToken(c *fiber.Ctx) error {
+	// * login state
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["userId"].(float64)
+
+	if err := r.calendarSvc.RevokeToken(uint64(userId)); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to revoke calendar token",
+		}
+	}
+
+	return response.Ok(c, "calendar token revoked")
+}
+
+// GetUserFeed
+// @ID getUserCalendarFeed
+// @Tags calendar
+// @Summary Get the iCalendar feed of the token owner, to subscribe to from calendar apps
+// @Produce text/calendar
+// @Param token path string true "Calendar token"
+// @Success 200 {file} file
+// @Failure 404 {object} response.ErrorResponse
+// @Router /calendar/feeds/{token}.ics [get]
+func (r *CalendarController) GetUserFeed(c *fiber.Ctx) error {
+	param := new(payload.CalendarFeedParam)
+	if err := c.ParamsParser(param); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid token parameter",
+		}
+	}
+
+	feed, err := r.calendarSvc.GetUserFeed(*param.Token)
+	if errors.Is(err, services.ErrCalendarTokenInvalid) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to get calendar feed",
+		}
+	}
+
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `inline; filename="calendar.ics"`)
+	return c.Send(feed)
+}
+
+// GetCourseFeed
+// @ID getCourseCalendarFeed
+// @Tags calendar
+// @Summary Get the public iCalendar feed of the workshop sessions and deadlines of a course
+// @Produce text/calendar
+// @Param courseId path uint64 true "Course ID"
+// @Success 200 {file} file
+// @Failure 400 {object} response.GenericError
+// @Router /calendar/courses/{courseId}.ics [get]
+func (r *CalendarController) GetCourseFeed(c *fiber.Ctx) error {
+	param := new(payload.CalendarCourseParam)
+	if err := c.ParamsParser(param); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid courseId parameter",
+		}
+	}
+
+	feed, err := r.calendarSvc.GetCourseFeed(*param.CourseId)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to get calendar feed",
+		}
+	}
+
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`inline; filename="course-%d.ics"`, *param.CourseId))
+	return c.Send(feed)
+}
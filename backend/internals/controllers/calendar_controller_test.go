@@ -0,0 +1,131 @@
+package controllers_test
+
+import (
+	"backend/internals/controllers"
+	"backend/internals/routes/handler"
+	"backend/internals/services"
+	mockServices "backend/mocks/services"
+	"bytes"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type CalendarControllerTestSuite struct {
+	suite.Suite
+}
+
+func setupTestCalendarController(mockCalendarService *mockServices.CalendarService) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: handler.ErrorHandler,
+	})
+
+	controller := controllers.NewCalendarController(mockCalendarService)
+
+	// feeds are authorized by their token, no JWT
+	app.Get("/calendar/feeds/:token.ics", controller.GetUserFeed)
+	app.Get("/calendar/courses/:courseId.ics", controller.GetCourseFeed)
+
+	// Middleware to simulate JWT Locals
+	app.Use(func(c *fiber.Ctx) error {
+		token := jwt.New(jwt.SigningMethodHS256)
+		claims := token.Claims.(jwt.MapClaims)
+		claims["userId"] = float64(123)
+		c.Locals("user", token)
+		return c.Next()
+	})
+
+	app.Delete("/calendar/token", controller.RevokeToken)
+	app.Post("/admin/calendar/events", controller.CreateEvent)
+	return app
+}
+
+func (suite *CalendarControllerTestSuite) TestGetUserFeedWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockCalendarService := new(mockServices.CalendarService)
+	app := setupTestCalendarController(mockCalendarService)
+
+	mockCalendarService.EXPECT().GetUserFeed("q1w2-e3_r4").Return([]byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/calendar/feeds/q1w2-e3_r4.ics", nil)
+	res, err := app.Test(req)
+
+	resBody, _ := io.ReadAll(res.Body)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Equal("text/calendar; charset=utf-8", res.Header.Get("Content-Type"))
+	is.Equal("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n", string(resBody))
+}
+
+func (suite *CalendarControllerTestSuite) TestGetUserFeedWhenTokenRevoked() {
+	is := assert.New(suite.T())
+
+	mockCalendarService := new(mockServices.CalendarService)
+	app := setupTestCalendarController(mockCalendarService)
+
+	mockCalendarService.EXPECT().GetUserFeed("revoked").Return(nil, services.ErrCalendarTokenInvalid)
+
+	req := httptest.NewRequest(http.MethodGet, "/calendar/feeds/revoked.ics", nil)
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusNotFound, res.StatusCode)
+}
+
+func (suite *CalendarControllerTestSuite) TestGetCourseFeedWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockCalendarService := new(mockServices.CalendarService)
+	app := setupTestCalendarController(mockCalendarService)
+
+	mockCalendarService.EXPECT().GetCourseFeed(uint64(7)).Return([]byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/calendar/courses/7.ics", nil)
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Equal(`inline; filename="course-7.ics"`, res.Header.Get("Content-Disposition"))
+}
+
+func (suite *CalendarControllerTestSuite) TestRevokeTokenWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockCalendarService := new(mockServices.CalendarService)
+	app := setupTestCalendarController(mockCalendarService)
+
+	mockCalendarService.EXPECT().RevokeToken(uint64(123)).Return(nil)
+
+	req := httptest.NewRequest(http.MethodDelete, "/calendar/token", nil)
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+}
+
+func (suite *CalendarControllerTestSuite) TestCreateEventWhenDeadlineHasEnd() {
+	is := assert.New(suite.T())
+
+	mockCalendarService := new(mockServices.CalendarService)
+	app := setupTestCalendarController(mockCalendarService)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/calendar/events", bytes.NewBufferString(`{"courseId":7,"kind":"deadline","title":"Lab report","startsAt":"2024-11-02T09:00:00+07:00","endsAt":"2024-11-02T10:00:00+07:00"}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusBadRequest, res.StatusCode)
+	mockCalendarService.AssertNotCalled(suite.T(), "CreateEvent", mock.Anything)
+}
+
+func TestCalendarController(t *testing.T) {
+	suite.Run(t, new(CalendarControllerTestSuite))
+}
@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"backend/internals/entities/payload"
+	"backend/internals/entities/response"
+	"backend/internals/services"
+	"backend/internals/utils"
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type CertificateController struct {
+	certificateSvc services.CertificateService
+}
+
+func NewCertificateController(certificateSvc services.CertificateService) *CertificateController {
+	return &CertificateController{
+		certificateSvc: certificateSvc,
+	}
+}
+
+// IssueCertificate
+// @ID issueCertificate
+// @Tags certificate
+// @Summary Issue the certificate of a fully passed course
+// @Accept json
+// @Produce json
+// @Param courseId path uint64 true "Course ID"
+// @Success 200 {object} response.InfoResponse[payload.CertificateInfo]
+// @Failure 400 {object} response.GenericError
+// @Router /certificate/courses/{courseId} [post]
+func (r *CertificateController) IssueCertificate(c *fiber.Ctx) error {
+	param := new(payload.CertificateCourseParam)
+	if err := c.ParamsParser(param); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid courseId parameter",
+		}
+	}
+
+	// * login state
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["userId"].(float64)
+
+	certificate, err := r.certificateSvc.IssueCertificate(uint64(userId), *param.CourseId)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to issue certificate",
+		}
+	}
+
+	return response.Ok(c, certificate)
+}
+
+// GetMyCertificates
+// @ID getMyCertificates
+// @Tags certificate
+// @Summary Get the certificates issued to the logged in user
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.InfoResponse[[]payload.CertificateInfo]
+// @Failure 400 {object} response.GenericError
+// @Router /certificate/mine [get]
+func (r *CertificateController) GetMyCertificates(c *fiber.Ctx) error {
+	// * login state
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["userId"].(float64)
+
+	certificates, err := r.certificateSvc.GetMyCertificates(uint64(userId))
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to get certificates",
+		}
+	}
+
+	return response.Ok(c, certificates)
+}
+
+// VerifyCertificate
+// @ID verifyCertificate
+// @Tags certificate
+// @Summary Verify a certificate by its ID or by the signed token of its QR code
+// @Accept json
+// @Produce json
+// @Param q query payload.CertificateVerifyQuery true "CertificateVerifyQuery"
+// @Success 200 {object} response.InfoResponse[payload.CertificateVerification]
+// @Failure 400 {object} response.GenericError
+// @Router /certificates/verify [get]
+func (r *CertificateController) VerifyCertificate(c *fiber.Ctx) error {
+	query := new(payload.CertificateVerifyQuery)
+	if err := c.QueryParser(query); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid certificate query",
+		}
+	}
+
+	// * validate query
+	if err := utils.Validate.Struct(query); err != nil {
+		var validationErrors validator.ValidationErrors
+		errors.As(err, &validationErrors)
+		return &response.GenericError{
+			Err: validationErrors,
+		}
+	}
+
+	verification, err := r.certificateSvc.VerifyCertificate(query)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to verify certificate",
+		}
+	}
+
+	return response.Ok(c, verification)
+}
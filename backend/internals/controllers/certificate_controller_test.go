@@ -0,0 +1,107 @@
+package controllers_test
+
+import (
+	"backend/internals/controllers"
+	"backend/internals/entities/payload"
+	"backend/internals/routes/handler"
+	"backend/internals/utils"
+	mockServices "backend/mocks/services"
+	"encoding/json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type CertificateControllerTestSuite struct {
+	suite.Suite
+}
+
+func setupTestCertificateController(mockCertificateService *mockServices.CertificateService) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: handler.ErrorHandler,
+	})
+
+	controller := controllers.NewCertificateController(mockCertificateService)
+
+	// verification is public, no JWT
+	app.Get("/certificates/verify", controller.VerifyCertificate)
+
+	// Middleware to simulate JWT Locals
+	app.Use(func(c *fiber.Ctx) error {
+		token := jwt.New(jwt.SigningMethodHS256)
+		claims := token.Claims.(jwt.MapClaims)
+		claims["userId"] = float64(123)
+		c.Locals("user", token)
+		return c.Next()
+	})
+
+	app.Get("/certificate/mine", controller.GetMyCertificates)
+	app.Post("/certificate/courses/:courseId", controller.IssueCertificate)
+	return app
+}
+
+func (suite *CertificateControllerTestSuite) TestIssueCertificateWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockCertificateService := new(mockServices.CertificateService)
+	app := setupTestCertificateController(mockCertificateService)
+
+	mockCertificateService.EXPECT().IssueCertificate(uint64(123), uint64(7)).Return(&payload.CertificateInfo{
+		CertificateId: utils.Ptr("7KQ2-M4XD-PA6T-3HZC"),
+		CourseId:      utils.Ptr(uint64(7)),
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/certificate/courses/7", nil)
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+}
+
+func (suite *CertificateControllerTestSuite) TestVerifyCertificateWhenValidationFailed() {
+	is := assert.New(suite.T())
+
+	mockCertificateService := new(mockServices.CertificateService)
+	app := setupTestCertificateController(mockCertificateService)
+
+	req := httptest.NewRequest(http.MethodGet, "/certificates/verify?id=AAAA-BBBB-CCCC-DDDD&token=AAAA-BBBB-CCCC-DDDD.sig", nil)
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusBadRequest, res.StatusCode)
+	mockCertificateService.AssertNotCalled(suite.T(), "VerifyCertificate", mock.Anything)
+}
+
+func (suite *CertificateControllerTestSuite) TestVerifyCertificateWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockCertificateService := new(mockServices.CertificateService)
+	app := setupTestCertificateController(mockCertificateService)
+
+	mockCertificateService.EXPECT().VerifyCertificate(&payload.CertificateVerifyQuery{Id: utils.Ptr("7KQ2-M4XD-PA6T-3HZC")}).Return(&payload.CertificateVerification{
+		Valid:         true,
+		CertificateId: utils.Ptr("7KQ2-M4XD-PA6T-3HZC"),
+		RecipientName: utils.Ptr("Somchai Jaidee"),
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/certificates/verify?id=7KQ2-M4XD-PA6T-3HZC", nil)
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+
+	body, _ := io.ReadAll(res.Body)
+	var result map[string]any
+	is.Nil(json.Unmarshal(body, &result))
+	is.Equal(true, result["data"].(map[string]any)["valid"])
+}
+
+func TestCertificateController(t *testing.T) {
+	suite.Run(t, new(CertificateControllerTestSuite))
+}
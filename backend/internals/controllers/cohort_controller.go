@@ -0,0 +1,230 @@
+package controllers
+
+import (
+	"backend/internals/entities/payload"
+	"backend/internals/entities/response"
+	"backend/internals/services"
+	"backend/internals/utils"
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type CohortController struct {
+	cohortSvc services.CohortService
+}
+
+func NewCohortController(cohortSvc services.CohortService) *CohortController {
+	return &CohortController{
+		cohortSvc: cohortSvc,
+	}
+}
+
+// CreateCohort
+// @ID createCohort
+// @Tags admin
+// @Summary Create a cohort of a course
+// @Accept json
+// @Produce json
+// @Param q body payload.CreateCohort true "CreateCohort"
+// @Success 200 {object} response.InfoResponse[payload.CohortInfo]
+// @Failure 400 {object} response.GenericError
+// @Router /admin/cohorts [post]
+func (r *CohortController) CreateCohort(c *fiber.Ctx) error {
+	body := new(payload.CreateCohort)
+	if err := c.BodyParser(body); err != nil {
+		return &response.GenericError{
+			Err: err,
+		}
+	}
+
+	// * validate body
+	if err := utils.Validate.Struct(body); err != nil {
+		var validationErrors validator.ValidationErrors
+		errors.As(err, &validationErrors)
+		return &response.GenericError{
+			Err: validationErrors,
+		}
+	}
+
+	cohort, err := r.cohortSvc.CreateCohort(body)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to create cohort",
+		}
+	}
+
+	return response.Ok(c, cohort)
+}
+
+// AddMember
+// @ID addCohortMember
+// @Tags admin
+// @Summary Add a learner or an instructor to a cohort
+// @Accept json
+// @Produce json
+// @Param cohortId path uint64 true "Cohort ID"
+// @Param q body payload.AddCohortMember true "AddCohortMember"
+// @Success 200 {object} response.InfoResponse[string]
+// @Failure 400 {object} response.GenericError
+// @Router /admin/cohorts/{cohortId}/members [post]
+func (r *CohortController) AddMember(c *fiber.Ctx) error {
+	param := new(payload.CohortIdParam)
+	if err := c.ParamsParser(param); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid cohortId parameter",
+		}
+	}
+
+	body := new(payload.AddCohortMember)
+	if err := c.BodyParser(body); err != nil {
+		return &response.GenericError{
+			Err: err,
+		}
+	}
+
+	// * validate body
+	if err := utils.Validate.Struct(body); err != nil {
+		var validationErrors validator.ValidationErrors
+		errors.As(err, &validationErrors)
+		return &response.GenericError{
+			Err: validationErrors,
+		}
+	}
+
+	if err := r.cohortSvc.AddMember(*param.CohortId, body); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to add cohort member",
+		}
+	}
+
+	return response.Ok(c, "cohort member added")
+}
+
+// GetCohort
+// @ID getCohort
+// @Tags cohort
+// @Summary Get a cohort and its current unlock
+// @Accept json
+// @Produce json
+// @Param cohortId path uint64 true "Cohort ID"
+// @Success 200 {object} response.InfoResponse[payload.CohortInfo]
+// @Failure 400 {object} response.GenericError
+// @Router /cohorts/{cohortId} [get]
+func (r *CohortController) GetCohort(c *fiber.Ctx) error {
+	param := new(payload.CohortIdParam)
+	if err := c.ParamsParser(param); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid cohortId parameter",
+		}
+	}
+
+	// * login state
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["userId"].(float64)
+
+	cohort, err := r.cohortSvc.GetCohort(*param.CohortId, uint64(userId))
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to get cohort",
+		}
+	}
+
+	return response.Ok(c, cohort)
+}
+
+// SetUnlock
+// @ID setCohortUnlock
+// @Tags cohort
+// @Summary Set the course content position, and optionally the step, unlocked for the cohort
+// @Accept json
+// @Produce json
+// @Param cohortId path uint64 true "Cohort ID"
+// @Param q body payload.CohortUnlockBody true "CohortUnlockBody"
+// @Success 200 {object} response.InfoResponse[payload.CohortUnlock]
+// @Failure 400 {object} response.GenericError
+// @Router /cohorts/{cohortId}/unlock [post]
+func (r *CohortController) SetUnlock(c *fiber.Ctx) error {
+	param := new(payload.CohortIdParam)
+	if err := c.ParamsParser(param); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid cohortId parameter",
+		}
+	}
+
+	body := new(payload.CohortUnlockBody)
+	if err := c.BodyParser(body); err != nil {
+		return &response.GenericError{
+			Err: err,
+		}
+	}
+
+	// * validate body
+	if err := utils.Validate.Struct(body); err != nil {
+		var validationErrors validator.ValidationErrors
+		errors.As(err, &validationErrors)
+		return &response.GenericError{
+			Err: validationErrors,
+		}
+	}
+
+	// * login state
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["userId"].(float64)
+
+	unlock, err := r.cohortSvc.SetUnlock(*param.CohortId, uint64(userId), body)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to unlock",
+		}
+	}
+
+	return response.Ok(c, unlock)
+}
+
+// GetUnlockFeed
+// @ID getCohortUnlockFeed
+// @Tags cohort
+// @Summary Stream the unlock of the cohort as server-sent events, starting with the current one
+// @Produce text/event-stream
+// @Param cohortId path uint64 true "Cohort ID"
+// @Success 200 {object} payload.CohortUnlock
+// @Failure 400 {object} response.GenericError
+// @Router /cohorts/{cohortId}/feed [get]
+func (r *CohortController) GetUnlockFeed(c *fiber.Ctx) error {
+	param := new(payload.CohortIdParam)
+	if err := c.ParamsParser(param); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid cohortId parameter",
+		}
+	}
+
+	// * login state
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["userId"].(float64)
+
+	current, updates, unsubscribe, err := r.cohortSvc.Subscribe(*param.CohortId, uint64(userId))
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to subscribe to cohort",
+		}
+	}
+
+	streamEvents(c, &payload.Event{Type: payload.EventCohortUnlock, Data: current}, updates, unsubscribe)
+
+	return nil
+}
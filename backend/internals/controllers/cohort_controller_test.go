@@ -0,0 +1,189 @@
+package controllers_test
+
+import (
+	"backend/internals/controllers"
+	"backend/internals/entities/payload"
+	"backend/internals/entities/response"
+	"backend/internals/routes/handler"
+	"backend/internals/utils"
+	mockServices "backend/mocks/services"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type CohortControllerTestSuite struct {
+	suite.Suite
+}
+
+func setupTestCohortController(mockCohortService *mockServices.CohortService) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: handler.ErrorHandler,
+	})
+
+	controller := controllers.NewCohortController(mockCohortService)
+
+	// Middleware to simulate JWT Locals
+	app.Use(func(c *fiber.Ctx) error {
+		token := jwt.New(jwt.SigningMethodHS256)
+		claims := token.Claims.(jwt.MapClaims)
+		claims["userId"] = float64(123)
+		c.Locals("user", token)
+		return c.Next()
+	})
+
+	app.Get("/cohorts/:cohortId", controller.GetCohort)
+	app.Get("/cohorts/:cohortId/feed", controller.GetUnlockFeed)
+	app.Post("/cohorts/:cohortId/unlock", controller.SetUnlock)
+	app.Post("/admin/cohorts", controller.CreateCohort)
+	app.Post("/admin/cohorts/:cohortId/members", controller.AddMember)
+	return app
+}
+
+func (suite *CohortControllerTestSuite) TestCreateCohortWhenValidationFailed() {
+	is := assert.New(suite.T())
+
+	mockCohortService := new(mockServices.CohortService)
+	app := setupTestCohortController(mockCohortService)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/cohorts", bytes.NewBufferString(`{"courseId":7}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusBadRequest, res.StatusCode)
+	mockCohortService.AssertNotCalled(suite.T(), "CreateCohort", mock.Anything)
+}
+
+func (suite *CohortControllerTestSuite) TestAddMemberWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockCohortService := new(mockServices.CohortService)
+	app := setupTestCohortController(mockCohortService)
+
+	mockCohortService.EXPECT().AddMember(uint64(3), &payload.AddCohortMember{
+		UserId: utils.Ptr(uint64(9)),
+		Role:   utils.Ptr("instructor"),
+	}).Return(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/cohorts/3/members", bytes.NewBufferString(`{"userId":9,"role":"instructor"}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+}
+
+func (suite *CohortControllerTestSuite) TestSetUnlockWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockCohortService := new(mockServices.CohortService)
+	app := setupTestCohortController(mockCohortService)
+
+	mockCohortService.EXPECT().SetUnlock(uint64(3), uint64(123), &payload.CohortUnlockBody{
+		Position: utils.Ptr(int64(2)),
+		StepId:   utils.Ptr(uint64(5)),
+	}).Return(&payload.CohortUnlock{CohortId: utils.Ptr(uint64(3)), Position: utils.Ptr(int64(2)), StepId: utils.Ptr(uint64(5))}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/cohorts/3/unlock", bytes.NewBufferString(`{"position":2,"stepId":5}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+
+	var responsePayload response.InfoResponse[payload.CohortUnlock]
+	resBody, _ := io.ReadAll(res.Body)
+	json.Unmarshal(resBody, &responsePayload)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Equal(uint64(5), *responsePayload.Data.StepId)
+}
+
+func (suite *CohortControllerTestSuite) TestSetUnlockWhenFailedToUnlock() {
+	is := assert.New(suite.T())
+
+	mockCohortService := new(mockServices.CohortService)
+	app := setupTestCohortController(mockCohortService)
+
+	mockCohortService.EXPECT().SetUnlock(uint64(3), uint64(123), mock.Anything).Return(nil, fmt.Errorf("only instructors of cohort 3 can unlock steps"))
+
+	req := httptest.NewRequest(http.MethodPost, "/cohorts/3/unlock", bytes.NewBufferString(`{"position":2}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+
+	var responsePayload response.GenericError
+	resBody, _ := io.ReadAll(res.Body)
+	json.Unmarshal(resBody, &responsePayload)
+
+	is.Nil(err)
+	is.Equal(http.StatusInternalServerError, res.StatusCode)
+	is.Equal("failed to unlock", responsePayload.Message)
+}
+
+func (suite *CohortControllerTestSuite) TestGetUnlockFeedWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockCohortService := new(mockServices.CohortService)
+	app := setupTestCohortController(mockCohortService)
+
+	// the feed ends once the service closes the updates
+	updates := make(chan *payload.Event, 1)
+	updates <- &payload.Event{
+		Type: payload.EventCohortUnlock,
+		Data: &payload.CohortUnlock{CohortId: utils.Ptr(uint64(3)), Position: utils.Ptr(int64(2))},
+	}
+	close(updates)
+	unsubscribed := false
+
+	mockCohortService.EXPECT().Subscribe(uint64(3), uint64(123)).Return(
+		&payload.CohortUnlock{CohortId: utils.Ptr(uint64(3)), Position: utils.Ptr(int64(1))},
+		(<-chan *payload.Event)(updates),
+		func() { unsubscribed = true },
+		nil,
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/cohorts/3/feed", nil)
+	res, err := app.Test(req)
+
+	resBody, _ := io.ReadAll(res.Body)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Equal("text/event-stream", res.Header.Get("Content-Type"))
+	is.Equal(2, strings.Count(string(resBody), "event: cohort.unlock\n"))
+	is.Contains(string(resBody), `"position":2`)
+	is.True(unsubscribed)
+}
+
+func (suite *CohortControllerTestSuite) TestGetUnlockFeedWhenNotMember() {
+	is := assert.New(suite.T())
+
+	mockCohortService := new(mockServices.CohortService)
+	app := setupTestCohortController(mockCohortService)
+
+	mockCohortService.EXPECT().Subscribe(uint64(3), uint64(123)).Return(nil, nil, nil, fmt.Errorf("user 123 is not a member of cohort 3"))
+
+	req := httptest.NewRequest(http.MethodGet, "/cohorts/3/feed", nil)
+	res, err := app.Test(req)
+
+	var responsePayload response.GenericError
+	resBody, _ := io.ReadAll(res.Body)
+	json.Unmarshal(resBody, &responsePayload)
+
+	is.Nil(err)
+	is.Equal(http.StatusInternalServerError, res.StatusCode)
+	is.Equal("failed to subscribe to cohort", responsePayload.Message)
+}
+
+func TestCohortController(t *testing.T) {
+	suite.Run(t, new(CohortControllerTestSuite))
+}
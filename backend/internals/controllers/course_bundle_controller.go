@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"backend/internals/entities/payload"
+	"backend/internals/entities/response"
+	"backend/internals/services"
+	"fmt"
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CourseBundleController struct {
+	courseBundleSvc services.CourseBundleService
+}
+
+func NewCourseBundleController(courseBundleSvc services.CourseBundleService) *CourseBundleController {
+	return &CourseBundleController{
+		courseBundleSvc: courseBundleSvc,
+	}
+}
+
+// ExportCourse
+// @ID exportCourseBundle
+// @Tags admin
+// @Summary Export a course with its modules, steps, evaluations and assets as a zip bundle
+// @Produce application/zip
+// @Param courseId path uint true "Course ID"
+// @Success 200 {file} file
+// @Failure 400 {object} response.GenericError
+// @Router /admin/courses/{courseId}/bundle [get]
+func (r *CourseBundleController) ExportCourse(c *fiber.Ctx) error {
+	param := new(payload.CourseIdParam)
+	if err := c.ParamsParser(param); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid courseId parameter",
+		}
+	}
+
+	bundle, err := r.courseBundleSvc.Export(uint64(param.CourseId))
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to export course",
+		}
+	}
+
+	c.Set(fiber.HeaderContentType, "application/zip")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="course-%d.zip"`, param.CourseId))
+	return c.Send(bundle)
+}
+
+// ImportCourse
+// @ID importCourseBundle
+// @Tags admin
+// @Summary Import a course bundle, updating records already imported from the same bundle
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Course bundle zip"
+// @Success 200 {object} response.InfoResponse[payload.CourseBundleImportResult]
+// @Failure 400 {object} response.GenericError
+// @Router /admin/courses/bundle [post]
+func (r *CourseBundleController) ImportCourse(c *fiber.Ctx) error {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "missing bundle file",
+		}
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to open bundle file",
+		}
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to read bundle file",
+		}
+	}
+
+	result, err := r.courseBundleSvc.Import(data)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to import course bundle",
+		}
+	}
+
+	return response.Ok(c, result)
+}
@@ -0,0 +1,121 @@
+package controllers_test
+
+import (
+	"backend/internals/controllers"
+	"backend/internals/entities/payload"
+	"backend/internals/entities/response"
+	"backend/internals/routes/handler"
+	"backend/internals/utils"
+	mockServices "backend/mocks/services"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type CourseBundleControllerTestSuite struct {
+	suite.Suite
+}
+
+func setupTestCourseBundleController(mockCourseBundleService *mockServices.CourseBundleService) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: handler.ErrorHandler,
+	})
+
+	courseBundleController := controllers.NewCourseBundleController(mockCourseBundleService)
+
+	app.Get("/admin/courses/:courseId/bundle", courseBundleController.ExportCourse)
+	app.Post("/admin/courses/bundle", courseBundleController.ImportCourse)
+	return app
+}
+
+func (suite *CourseBundleControllerTestSuite) TestExportCourseWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockCourseBundleService := new(mockServices.CourseBundleService)
+	app := setupTestCourseBundleController(mockCourseBundleService)
+
+	mockCourseBundleService.EXPECT().Export(uint64(7)).Return([]byte("zip"), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/courses/7/bundle", nil)
+	res, err := app.Test(req)
+
+	resBody, _ := io.ReadAll(res.Body)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Equal("application/zip", res.Header.Get("Content-Type"))
+	is.Contains(res.Header.Get("Content-Disposition"), "course-7.zip")
+	is.Equal([]byte("zip"), resBody)
+}
+
+func (suite *CourseBundleControllerTestSuite) TestExportCourseWhenFailed() {
+	is := assert.New(suite.T())
+
+	mockCourseBundleService := new(mockServices.CourseBundleService)
+	app := setupTestCourseBundleController(mockCourseBundleService)
+
+	mockCourseBundleService.EXPECT().Export(uint64(7)).Return(nil, fmt.Errorf("record not found"))
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/courses/7/bundle", nil)
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusInternalServerError, res.StatusCode)
+}
+
+func (suite *CourseBundleControllerTestSuite) TestImportCourseWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockCourseBundleService := new(mockServices.CourseBundleService)
+	app := setupTestCourseBundleController(mockCourseBundleService)
+
+	mockCourseBundleService.EXPECT().Import([]byte("zip")).Return(&payload.CourseBundleImportResult{
+		CourseId:  utils.Ptr(uint64(70)),
+		ModuleIds: map[uint64]uint64{3: 30},
+	}, nil)
+
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	part, _ := writer.CreateFormFile("file", "course.zip")
+	part.Write([]byte("zip"))
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/courses/bundle", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	res, err := app.Test(req)
+
+	var responsePayload response.InfoResponse[payload.CourseBundleImportResult]
+	resBody, _ := io.ReadAll(res.Body)
+	json.Unmarshal(resBody, &responsePayload)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Equal(uint64(70), *responsePayload.Data.CourseId)
+	is.Equal(uint64(30), responsePayload.Data.ModuleIds[3])
+}
+
+func (suite *CourseBundleControllerTestSuite) TestImportCourseWhenFileMissing() {
+	is := assert.New(suite.T())
+
+	mockCourseBundleService := new(mockServices.CourseBundleService)
+	app := setupTestCourseBundleController(mockCourseBundleService)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/courses/bundle", nil)
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusInternalServerError, res.StatusCode)
+	mockCourseBundleService.AssertNotCalled(suite.T(), "Import")
+}
+
+func TestCourseBundleController(t *testing.T) {
+	suite.Run(t, new(CourseBundleControllerTestSuite))
+}
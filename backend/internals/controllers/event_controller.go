@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"backend/internals/entities/payload"
+	"backend/internals/entities/response"
+	utilServices "backend/internals/utils/services"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// eventKeepAlive is how often an idle stream writes a comment, which also detects gone clients.
+const eventKeepAlive = 15 * time.Second
+
+type EventController struct {
+	eventHub utilServices.EventHub
+}
+
+func NewEventController(eventHub utilServices.EventHub) *EventController {
+	return &EventController{
+		eventHub: eventHub,
+	}
+}
+
+// GetEventStream
+// @ID getEventStream
+// @Tags event
+// @Summary Stream the events of the current user, and of a step when stepId is given, as server-sent events
+// @Produce text/event-stream
+// @Param q query payload.EventStreamQuery false "EventStreamQuery"
+// @Success 200 {object} payload.Event
+// @Failure 400 {object} response.GenericError
+// @Router /events [get]
+func (r *EventController) GetEventStream(c *fiber.Ctx) error {
+	query := new(payload.EventStreamQuery)
+	if err := c.QueryParser(query); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid query",
+		}
+	}
+
+	// * login state
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["userId"].(float64)
+
+	topics := []string{utilServices.UserTopic(uint64(userId))}
+	if query.StepId != nil {
+		topics = append(topics, utilServices.StepTopic(*query.StepId))
+	}
+
+	events, unsubscribe := r.eventHub.Subscribe(topics...)
+	streamEvents(c, nil, events, unsubscribe)
+
+	return nil
+}
+
+// streamEvents writes the initial event, when given, and then the events as server-sent events
+// until the client is gone or the events are closed, after which it unsubscribes.
+func streamEvents(c *fiber.Ctx, initial *payload.Event, events <-chan *payload.Event, unsubscribe func()) {
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer unsubscribe()
+
+		keepAlive := time.NewTicker(eventKeepAlive)
+		defer keepAlive.Stop()
+
+		event := initial
+		for {
+			if event != nil {
+				data, _ := json.Marshal(event.Data)
+				fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+			}
+			// a failed flush means the client is gone
+			if err := w.Flush(); err != nil {
+				return
+			}
+
+			select {
+			case next, ok := <-events:
+				if !ok {
+					return
+				}
+				event = next
+			case <-keepAlive.C:
+				event = nil
+				fmt.Fprint(w, ": keep-alive\n\n")
+			}
+		}
+	})
+}
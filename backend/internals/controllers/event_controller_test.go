@@ -0,0 +1,94 @@
+package controllers_test
+
+import (
+	"backend/internals/controllers"
+	"backend/internals/entities/payload"
+	"backend/internals/routes/handler"
+	"backend/internals/utils"
+	mockUtilServices "backend/mocks/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type EventControllerTestSuite struct {
+	suite.Suite
+}
+
+func setupTestEventController(mockEventHub *mockUtilServices.EventHub) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: handler.ErrorHandler,
+	})
+
+	controller := controllers.NewEventController(mockEventHub)
+
+	// Middleware to simulate JWT Locals
+	app.Use(func(c *fiber.Ctx) error {
+		token := jwt.New(jwt.SigningMethodHS256)
+		claims := token.Claims.(jwt.MapClaims)
+		claims["userId"] = float64(123)
+		c.Locals("user", token)
+		return c.Next()
+	})
+
+	app.Get("/events", controller.GetEventStream)
+	return app
+}
+
+func (suite *EventControllerTestSuite) TestGetEventStreamWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockEventHub := new(mockUtilServices.EventHub)
+	app := setupTestEventController(mockEventHub)
+
+	// the stream ends once the hub closes the events
+	events := make(chan *payload.Event, 1)
+	events <- &payload.Event{
+		Type: payload.EventSubmissionGraded,
+		Data: &payload.UserEvalResult{UserEvalId: utils.Ptr(uint64(4)), Pass: utils.Ptr(true)},
+	}
+	close(events)
+	unsubscribed := false
+
+	mockEventHub.EXPECT().Subscribe("user:123", "step:5").Return((<-chan *payload.Event)(events), func() { unsubscribed = true })
+
+	req := httptest.NewRequest(http.MethodGet, "/events?stepId=5", nil)
+	res, err := app.Test(req)
+
+	resBody, _ := io.ReadAll(res.Body)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Equal("text/event-stream", res.Header.Get("Content-Type"))
+	is.Contains(string(resBody), "event: submission.graded\ndata: {\"userEvalId\":4,")
+	is.Contains(string(resBody), `"pass":true`)
+	is.True(unsubscribed)
+}
+
+func (suite *EventControllerTestSuite) TestGetEventStreamWithoutStep() {
+	is := assert.New(suite.T())
+
+	mockEventHub := new(mockUtilServices.EventHub)
+	app := setupTestEventController(mockEventHub)
+
+	events := make(chan *payload.Event)
+	close(events)
+
+	mockEventHub.EXPECT().Subscribe("user:123").Return((<-chan *payload.Event)(events), func() {})
+
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	mockEventHub.AssertExpectations(suite.T())
+}
+
+func TestEventController(t *testing.T) {
+	suite.Run(t, new(EventControllerTestSuite))
+}
@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"backend/internals/entities/payload"
+	"backend/internals/entities/response"
+	"backend/internals/services"
+	"backend/internals/utils"
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type GemController struct {
+	gemSvc services.GemService
+}
+
+func NewGemController(gemSvc services.GemService) *GemController {
+	return &GemController{
+		gemSvc: gemSvc,
+	}
+}
+
+// GetLedger
+// @ID getGemLedger
+// @Tags gem
+// @Summary Get the gem balance of the user with their latest gem entries
+// @Produce json
+// @Success 200 {object} response.InfoResponse[payload.GemLedger]
+// @Failure 400 {object} response.GenericError
+// @Router /gems/ledger [get]
+func (r *GemController) GetLedger(c *fiber.Ctx) error {
+	// * login state
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["userId"].(float64)
+
+	ledger, err := r.gemSvc.GetLedger(uint64(userId))
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to get gem ledger",
+		}
+	}
+
+	return response.Ok(c, ledger)
+}
+
+// Adjust
+// @ID adjustGems
+// @Tags gem
+// @Summary Correct the gem balance of a user, repeating a request with the same idempotency key has no effect
+// @Accept json
+// @Produce json
+// @Param q body payload.GemAdjustment true "GemAdjustment"
+// @Success 200 {object} response.InfoResponse[payload.GemEntryInfo]
+// @Failure 400 {object} response.GenericError
+// @Failure 409 {object} response.GenericError
+// @Router /admin/gems/adjustments [post]
+func (r *GemController) Adjust(c *fiber.Ctx) error {
+	body := new(payload.GemAdjustment)
+	if err := c.BodyParser(body); err != nil {
+		return &response.GenericError{
+			Err: err,
+		}
+	}
+
+	// * validate body
+	if err := utils.Validate.Struct(body); err != nil {
+		var validationErrors validator.ValidationErrors
+		errors.As(err, &validationErrors)
+		return &response.GenericError{
+			Err: validationErrors,
+		}
+	}
+
+	// * login state
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	adminId := claims["userId"].(float64)
+
+	entry, err := r.gemSvc.Adjust(uint64(adminId), body)
+	if errors.Is(err, services.ErrGemBalanceInsufficient) {
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to adjust gems",
+		}
+	}
+
+	return response.Ok(c, entry)
+}
@@ -0,0 +1,126 @@
+package controllers_test
+
+import (
+	"backend/internals/controllers"
+	"backend/internals/entities/payload"
+	"backend/internals/entities/response"
+	"backend/internals/routes/handler"
+	"backend/internals/services"
+	"backend/internals/utils"
+	mockServices "backend/mocks/services"
+	"bytes"
+	"encoding/json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type GemControllerTestSuite struct {
+	suite.Suite
+}
+
+func setupTestGemController(mockGemService *mockServices.GemService) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: handler.ErrorHandler,
+	})
+
+	controller := controllers.NewGemController(mockGemService)
+
+	// Middleware to simulate JWT Locals
+	app.Use(func(c *fiber.Ctx) error {
+		token := jwt.New(jwt.SigningMethodHS256)
+		claims := token.Claims.(jwt.MapClaims)
+		claims["userId"] = float64(123)
+		c.Locals("user", token)
+		return c.Next()
+	})
+
+	app.Get("/gems/ledger", controller.GetLedger)
+	app.Post("/admin/gems/adjustments", controller.Adjust)
+	return app
+}
+
+func (suite *GemControllerTestSuite) TestGetLedgerWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockGemService := new(mockServices.GemService)
+	app := setupTestGemController(mockGemService)
+
+	mockGemService.EXPECT().GetLedger(uint64(123)).Return(&payload.GemLedger{
+		Balance: 3,
+		Entries: []*payload.GemEntryInfo{
+			{Id: utils.Ptr(uint64(1)), Kind: utils.Ptr("award"), Amount: utils.Ptr(int64(3))},
+		},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/gems/ledger", nil)
+	res, err := app.Test(req)
+
+	var responsePayload response.InfoResponse[payload.GemLedger]
+	resBody, _ := io.ReadAll(res.Body)
+	json.Unmarshal(resBody, &responsePayload)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Equal(int64(3), responsePayload.Data.Balance)
+	is.Len(responsePayload.Data.Entries, 1)
+}
+
+func (suite *GemControllerTestSuite) TestAdjustWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockGemService := new(mockServices.GemService)
+	app := setupTestGemController(mockGemService)
+
+	mockGemService.EXPECT().Adjust(uint64(123), mock.MatchedBy(func(body *payload.GemAdjustment) bool {
+		return *body.UserId == 9 && *body.Amount == 2 && *body.IdempotencyKey == "event-bonus-9"
+	})).Return(&payload.GemEntryInfo{Id: utils.Ptr(uint64(4)), Amount: utils.Ptr(int64(2))}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/gems/adjustments", bytes.NewBufferString(`{"userId":9,"amount":2,"reason":"event bonus","idempotencyKey":"event-bonus-9"}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+}
+
+func (suite *GemControllerTestSuite) TestAdjustWhenAmountZero() {
+	is := assert.New(suite.T())
+
+	mockGemService := new(mockServices.GemService)
+	app := setupTestGemController(mockGemService)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/gems/adjustments", bytes.NewBufferString(`{"userId":9,"amount":0,"reason":"nothing","idempotencyKey":"noop"}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusBadRequest, res.StatusCode)
+	mockGemService.AssertNotCalled(suite.T(), "Adjust", mock.Anything, mock.Anything)
+}
+
+func (suite *GemControllerTestSuite) TestAdjustWhenBalanceWouldBeNegative() {
+	is := assert.New(suite.T())
+
+	mockGemService := new(mockServices.GemService)
+	app := setupTestGemController(mockGemService)
+
+	mockGemService.EXPECT().Adjust(uint64(123), mock.Anything).Return(nil, services.ErrGemBalanceInsufficient)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/gems/adjustments", bytes.NewBufferString(`{"userId":9,"amount":-50,"reason":"awarded twice","idempotencyKey":"refund-9"}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusConflict, res.StatusCode)
+}
+
+func TestGemController(t *testing.T) {
+	suite.Run(t, new(GemControllerTestSuite))
+}
@@ -0,0 +1,275 @@
+package controllers
+
+import (
+	"backend/internals/entities/payload"
+	"backend/internals/entities/response"
+	"backend/internals/services"
+	"backend/internals/utils"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type HelpRequestController struct {
+	helpRequestSvc services.HelpRequestService
+}
+
+func NewHelpRequestController(helpRequestSvc services.HelpRequestService) *HelpRequestController {
+	return &HelpRequestController{
+		helpRequestSvc: helpRequestSvc,
+	}
+}
+
+// RequestHelp
+// @ID requestHelp
+// @Tags help
+// @Summary Raise a hand in the help queue of a workshop session or a cohort
+// @Accept multipart/form-data
+// @Produce json
+// @Param data formData string true "JSON of payload.CreateHelpRequest"
+// @Param photo formData file false "Photo of the problem"
+// @Success 200 {object} response.InfoResponse[payload.HelpRequestInfo]
+// @Failure 400 {object} response.GenericError
+// @Router /help [post]
+func (r *HelpRequestController) RequestHelp(c *fiber.Ctx) error {
+	// Parse JSON from "data" form field
+	body := new(payload.CreateHelpRequest)
+	if err := json.Unmarshal([]byte(c.FormValue("data")), body); err != nil {
+		return &response.GenericError{
+			Err: err,
+		}
+	}
+
+	// * validate body
+	if err := utils.Validate.Struct(body); err != nil {
+		var validationErrors validator.ValidationErrors
+		errors.As(err, &validationErrors)
+		return &response.GenericError{
+			Err: validationErrors,
+		}
+	}
+
+	// * the photo is optional
+	var photo *multipart.FileHeader
+	if fileHeader, err := c.FormFile("photo"); err == nil {
+		photo = fileHeader
+	}
+
+	// * login state
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["userId"].(float64)
+
+	helpRequest, err := r.helpRequestSvc.RequestHelp(uint64(userId), body, photo)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to request help",
+		}
+	}
+
+	return response.Ok(c, helpRequest)
+}
+
+// GetMyHelpRequests
+// @ID getMyHelpRequests
+// @Tags help
+// @Summary Get the open help requests of the current user with their place in the queue
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.InfoResponse[[]payload.HelpRequestInfo]
+// @Failure 400 {object} response.GenericError
+// @Router /help/mine [get]
+func (r *HelpRequestController) GetMyHelpRequests(c *fiber.Ctx) error {
+	// * login state
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["userId"].(float64)
+
+	helpRequests, err := r.helpRequestSvc.GetMyHelpRequests(uint64(userId))
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to get help requests",
+		}
+	}
+
+	return response.Ok(c, helpRequests)
+}
+
+// CancelHelpRequest
+// @ID cancelHelpRequest
+// @Tags help
+// @Summary Lower the hand of an open help request of the current user
+// @Accept json
+// @Produce json
+// @Param helpRequestId path uint64 true "Help request ID"
+// @Success 200 {object} response.InfoResponse[string]
+// @Failure 400 {object} response.GenericError
+// @Router /help/{helpRequestId}/cancel [post]
+func (r *HelpRequestController) CancelHelpRequest(c *fiber.Ctx) error {
+	param := new(payload.HelpRequestIdParam)
+	if err := c.ParamsParser(param); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid helpRequestId parameter",
+		}
+	}
+
+	// * login state
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["userId"].(float64)
+
+	if err := r.helpRequestSvc.Cancel(*param.HelpRequestId, uint64(userId)); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to cancel help request",
+		}
+	}
+
+	return response.Ok(c, "help request cancelled")
+}
+
+// GetQueue
+// @ID getHelpQueue
+// @Tags instructor
+// @Summary Get the open help requests of a session or cohort, first come first served
+// @Accept json
+// @Produce json
+// @Param q query payload.HelpQueueQuery true "HelpQueueQuery"
+// @Success 200 {object} response.InfoResponse[[]payload.HelpRequestInfo]
+// @Failure 400 {object} response.GenericError
+// @Router /instructor/help [get]
+func (r *HelpRequestController) GetQueue(c *fiber.Ctx) error {
+	query := new(payload.HelpQueueQuery)
+	if err := c.QueryParser(query); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid query",
+		}
+	}
+
+	// * validate query
+	if err := utils.Validate.Struct(query); err != nil {
+		var validationErrors validator.ValidationErrors
+		errors.As(err, &validationErrors)
+		return &response.GenericError{
+			Err: validationErrors,
+		}
+	}
+
+	queue, err := r.helpRequestSvc.GetQueue(query)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to get help queue",
+		}
+	}
+
+	return response.Ok(c, queue)
+}
+
+// ClaimHelpRequest
+// @ID claimHelpRequest
+// @Tags instructor
+// @Summary Claim a waiting help request
+// @Accept json
+// @Produce json
+// @Param helpRequestId path uint64 true "Help request ID"
+// @Success 200 {object} response.InfoResponse[payload.HelpRequestInfo]
+// @Failure 400 {object} response.GenericError
+// @Router /instructor/help/{helpRequestId}/claim [post]
+func (r *HelpRequestController) ClaimHelpRequest(c *fiber.Ctx) error {
+	param := new(payload.HelpRequestIdParam)
+	if err := c.ParamsParser(param); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid helpRequestId parameter",
+		}
+	}
+
+	// * login state
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["userId"].(float64)
+
+	helpRequest, err := r.helpRequestSvc.Claim(*param.HelpRequestId, uint64(userId))
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to claim help request",
+		}
+	}
+
+	return response.Ok(c, helpRequest)
+}
+
+// ResolveHelpRequest
+// @ID resolveHelpRequest
+// @Tags instructor
+// @Summary Resolve a help request claimed by the current user
+// @Accept json
+// @Produce json
+// @Param helpRequestId path uint64 true "Help request ID"
+// @Success 200 {object} response.InfoResponse[payload.HelpRequestInfo]
+// @Failure 400 {object} response.GenericError
+// @Router /instructor/help/{helpRequestId}/resolve [post]
+func (r *HelpRequestController) ResolveHelpRequest(c *fiber.Ctx) error {
+	param := new(payload.HelpRequestIdParam)
+	if err := c.ParamsParser(param); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid helpRequestId parameter",
+		}
+	}
+
+	// * login state
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["userId"].(float64)
+
+	helpRequest, err := r.helpRequestSvc.Resolve(*param.HelpRequestId, uint64(userId))
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to resolve help request",
+		}
+	}
+
+	return response.Ok(c, helpRequest)
+}
+
+// GetStepHelpStats
+// @ID getStepHelpStats
+// @Tags instructor
+// @Summary Get the help requests, wait and resolution times per step, longest wait first
+// @Accept json
+// @Produce json
+// @Param q query payload.HelpStatsQuery false "HelpStatsQuery"
+// @Success 200 {object} response.InfoResponse[[]payload.StepHelpStats]
+// @Failure 400 {object} response.GenericError
+// @Router /instructor/help/stats [get]
+func (r *HelpRequestController) GetStepHelpStats(c *fiber.Ctx) error {
+	query := new(payload.HelpStatsQuery)
+	if err := c.QueryParser(query); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid query",
+		}
+	}
+
+	stats, err := r.helpRequestSvc.GetStepHelpStats(query.CourseId)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to get step help stats",
+		}
+	}
+
+	return response.Ok(c, stats)
+}
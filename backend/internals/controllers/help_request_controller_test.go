@@ -0,0 +1,178 @@
+package controllers_test
+
+import (
+	"backend/internals/controllers"
+	"backend/internals/entities/payload"
+	"backend/internals/entities/response"
+	"backend/internals/routes/handler"
+	"backend/internals/utils"
+	mockServices "backend/mocks/services"
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type HelpRequestControllerTestSuite struct {
+	suite.Suite
+}
+
+func setupTestHelpRequestController(mockHelpRequestService *mockServices.HelpRequestService) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: handler.ErrorHandler,
+	})
+
+	controller := controllers.NewHelpRequestController(mockHelpRequestService)
+
+	// Middleware to simulate JWT Locals
+	app.Use(func(c *fiber.Ctx) error {
+		token := jwt.New(jwt.SigningMethodHS256)
+		claims := token.Claims.(jwt.MapClaims)
+		claims["userId"] = float64(123)
+		c.Locals("user", token)
+		return c.Next()
+	})
+
+	app.Post("/help", controller.RequestHelp)
+	app.Get("/help/mine", controller.GetMyHelpRequests)
+	app.Post("/help/:helpRequestId/cancel", controller.CancelHelpRequest)
+	app.Get("/instructor/help", controller.GetQueue)
+	app.Get("/instructor/help/stats", controller.GetStepHelpStats)
+	app.Post("/instructor/help/:helpRequestId/claim", controller.ClaimHelpRequest)
+	app.Post("/instructor/help/:helpRequestId/resolve", controller.ResolveHelpRequest)
+	return app
+}
+
+func (suite *HelpRequestControllerTestSuite) TestRequestHelpWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockHelpRequestService := new(mockServices.HelpRequestService)
+	app := setupTestHelpRequestController(mockHelpRequestService)
+
+	mockHelpRequestService.EXPECT().RequestHelp(uint64(123), &payload.CreateHelpRequest{
+		SessionId: utils.Ptr(uint64(4)),
+		StepId:    utils.Ptr(uint64(12)),
+	}, mock.Anything).Return(&payload.HelpRequestInfo{
+		HelpRequestId: utils.Ptr(uint64(30)),
+		Status:        utils.Ptr("waiting"),
+		Position:      utils.Ptr(int64(3)),
+	}, nil)
+
+	formData := "data={\"sessionId\":4, \"stepId\":12}"
+	req := httptest.NewRequest(http.MethodPost, "/help", strings.NewReader(formData))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	res, err := app.Test(req)
+
+	var responsePayload response.InfoResponse[payload.HelpRequestInfo]
+	resBody, _ := io.ReadAll(res.Body)
+	json.Unmarshal(resBody, &responsePayload)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Equal(int64(3), *responsePayload.Data.Position)
+}
+
+func (suite *HelpRequestControllerTestSuite) TestRequestHelpWhenBothQueuesGiven() {
+	is := assert.New(suite.T())
+
+	mockHelpRequestService := new(mockServices.HelpRequestService)
+	app := setupTestHelpRequestController(mockHelpRequestService)
+
+	formData := "data={\"sessionId\":4, \"cohortId\":3, \"stepId\":12}"
+	req := httptest.NewRequest(http.MethodPost, "/help", strings.NewReader(formData))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusBadRequest, res.StatusCode)
+	mockHelpRequestService.AssertNotCalled(suite.T(), "RequestHelp", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *HelpRequestControllerTestSuite) TestGetQueueWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockHelpRequestService := new(mockServices.HelpRequestService)
+	app := setupTestHelpRequestController(mockHelpRequestService)
+
+	mockHelpRequestService.EXPECT().GetQueue(&payload.HelpQueueQuery{SessionId: utils.Ptr(uint64(4))}).Return([]*payload.HelpRequestInfo{
+		{HelpRequestId: utils.Ptr(uint64(30)), Position: utils.Ptr(int64(1))},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/instructor/help?sessionId=4", nil)
+	res, err := app.Test(req)
+
+	var responsePayload response.InfoResponse[[]payload.HelpRequestInfo]
+	resBody, _ := io.ReadAll(res.Body)
+	json.Unmarshal(resBody, &responsePayload)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Len(responsePayload.Data, 1)
+}
+
+func (suite *HelpRequestControllerTestSuite) TestGetQueueWhenQueueMissing() {
+	is := assert.New(suite.T())
+
+	mockHelpRequestService := new(mockServices.HelpRequestService)
+	app := setupTestHelpRequestController(mockHelpRequestService)
+
+	req := httptest.NewRequest(http.MethodGet, "/instructor/help", nil)
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusBadRequest, res.StatusCode)
+}
+
+func (suite *HelpRequestControllerTestSuite) TestClaimHelpRequestWhenFailedToClaim() {
+	is := assert.New(suite.T())
+
+	mockHelpRequestService := new(mockServices.HelpRequestService)
+	app := setupTestHelpRequestController(mockHelpRequestService)
+
+	mockHelpRequestService.EXPECT().Claim(uint64(30), uint64(123)).Return(nil, fmt.Errorf("help request is not open for this change"))
+
+	req := httptest.NewRequest(http.MethodPost, "/instructor/help/30/claim", nil)
+	res, err := app.Test(req)
+
+	var responsePayload response.GenericError
+	resBody, _ := io.ReadAll(res.Body)
+	json.Unmarshal(resBody, &responsePayload)
+
+	is.Nil(err)
+	is.Equal(http.StatusInternalServerError, res.StatusCode)
+	is.Equal("failed to claim help request", responsePayload.Message)
+}
+
+func (suite *HelpRequestControllerTestSuite) TestGetStepHelpStatsWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockHelpRequestService := new(mockServices.HelpRequestService)
+	app := setupTestHelpRequestController(mockHelpRequestService)
+
+	mockHelpRequestService.EXPECT().GetStepHelpStats(utils.Ptr(uint64(7))).Return([]*payload.StepHelpStats{
+		{StepId: utils.Ptr(uint64(12)), Requests: utils.Ptr(int64(5)), AvgWaitSeconds: utils.Ptr(240.0)},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/instructor/help/stats?courseId=7", nil)
+	res, err := app.Test(req)
+
+	var responsePayload response.InfoResponse[[]payload.StepHelpStats]
+	resBody, _ := io.ReadAll(res.Body)
+	json.Unmarshal(resBody, &responsePayload)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Equal(240.0, *responsePayload.Data[0].AvgWaitSeconds)
+}
+
+func TestHelpRequestController(t *testing.T) {
+	suite.Run(t, new(HelpRequestControllerTestSuite))
+}
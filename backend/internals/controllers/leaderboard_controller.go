@@ -0,0 +1,169 @@
+package controllers
+
+import (
+	"backend/internals/entities/payload"
+	"backend/internals/entities/response"
+	"backend/internals/services"
+	"backend/internals/utils"
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type LeaderboardController struct {
+	leaderboardSvc services.LeaderboardService
+}
+
+func NewLeaderboardController(leaderboardSvc services.LeaderboardService) *LeaderboardController {
+	return &LeaderboardController{
+		leaderboardSvc: leaderboardSvc,
+	}
+}
+
+// GetLeaderboard
+// @ID getLeaderboard
+// @Tags leaderboard
+// @Summary Get the users earning the most gems globally, in a course or in a field over all time, this month or this week
+// @Produce json
+// @Param q query payload.LeaderboardQuery true "LeaderboardQuery"
+// @Success 200 {object} response.InfoResponse[payload.Leaderboard]
+// @Failure 400 {object} response.GenericError
+// @Router /leaderboards [get]
+func (r *LeaderboardController) GetLeaderboard(c *fiber.Ctx) error {
+	query, err := leaderboardQuery(c)
+	if err != nil {
+		return err
+	}
+
+	leaderboard, err := r.leaderboardSvc.GetLeaderboard(query)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to get leaderboard",
+		}
+	}
+
+	return response.Ok(c, leaderboard)
+}
+
+// GetStanding
+// @ID getLeaderboardStanding
+// @Tags leaderboard
+// @Summary Get the rank of the user on a leaderboard with the users ranked around them
+// @Produce json
+// @Param q query payload.LeaderboardQuery true "LeaderboardQuery"
+// @Success 200 {object} response.InfoResponse[payload.Leaderboard]
+// @Failure 400 {object} response.GenericError
+// @Router /leaderboards/me [get]
+func (r *LeaderboardController) GetStanding(c *fiber.Ctx) error {
+	query, err := leaderboardQuery(c)
+	if err != nil {
+		return err
+	}
+
+	// * login state
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["userId"].(float64)
+
+	leaderboard, err := r.leaderboardSvc.GetStanding(uint64(userId), query)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to get leaderboard standing",
+		}
+	}
+
+	return response.Ok(c, leaderboard)
+}
+
+// GetPreference
+// @ID getLeaderboardPreference
+// @Tags leaderboard
+// @Summary Get whether the user is hidden from leaderboards
+// @Produce json
+// @Success 200 {object} response.InfoResponse[payload.LeaderboardPreference]
+// @Failure 400 {object} response.GenericError
+// @Router /leaderboards/preferences [get]
+func (r *LeaderboardController) GetPreference(c *fiber.Ctx) error {
+	// * login state
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["userId"].(float64)
+
+	preference, err := r.leaderboardSvc.GetPreference(uint64(userId))
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to get leaderboard preference",
+		}
+	}
+
+	return response.Ok(c, preference)
+}
+
+// UpdatePreference
+// @ID updateLeaderboardPreference
+// @Tags leaderboard
+// @Summary Hide the user from leaderboards or show them again
+// @Accept json
+// @Produce json
+// @Param q body payload.LeaderboardPreference true "LeaderboardPreference"
+// @Success 200 {object} response.InfoResponse[payload.LeaderboardPreference]
+// @Failure 400 {object} response.GenericError
+// @Router /leaderboards/preferences [put]
+func (r *LeaderboardController) UpdatePreference(c *fiber.Ctx) error {
+	body := new(payload.LeaderboardPreference)
+	if err := c.BodyParser(body); err != nil {
+		return &response.GenericError{
+			Err: err,
+		}
+	}
+
+	// * validate body
+	if err := utils.Validate.Struct(body); err != nil {
+		var validationErrors validator.ValidationErrors
+		errors.As(err, &validationErrors)
+		return &response.GenericError{
+			Err: validationErrors,
+		}
+	}
+
+	// * login state
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["userId"].(float64)
+
+	preference, err := r.leaderboardSvc.UpdatePreference(uint64(userId), body)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to update leaderboard preference",
+		}
+	}
+
+	return response.Ok(c, preference)
+}
+
+func leaderboardQuery(c *fiber.Ctx) (*payload.LeaderboardQuery, error) {
+	query := new(payload.LeaderboardQuery)
+	if err := c.QueryParser(query); err != nil {
+		return nil, &response.GenericError{
+			Err:     err,
+			Message: "invalid query",
+		}
+	}
+
+	// * validate query
+	if err := utils.Validate.Struct(query); err != nil {
+		var validationErrors validator.ValidationErrors
+		errors.As(err, &validationErrors)
+		return nil, &response.GenericError{
+			Err: validationErrors,
+		}
+	}
+
+	return query, nil
+}
@@ -0,0 +1,135 @@
+package controllers_test
+
+import (
+	"backend/internals/controllers"
+	"backend/internals/entities/payload"
+	"backend/internals/entities/response"
+	"backend/internals/routes/handler"
+	"backend/internals/utils"
+	mockServices "backend/mocks/services"
+	"bytes"
+	"encoding/json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type LeaderboardControllerTestSuite struct {
+	suite.Suite
+}
+
+func setupTestLeaderboardController(mockLeaderboardService *mockServices.LeaderboardService) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: handler.ErrorHandler,
+	})
+
+	controller := controllers.NewLeaderboardController(mockLeaderboardService)
+
+	// Middleware to simulate JWT Locals
+	app.Use(func(c *fiber.Ctx) error {
+		token := jwt.New(jwt.SigningMethodHS256)
+		claims := token.Claims.(jwt.MapClaims)
+		claims["userId"] = float64(123)
+		c.Locals("user", token)
+		return c.Next()
+	})
+
+	app.Get("/leaderboards", controller.GetLeaderboard)
+	app.Get("/leaderboards/me", controller.GetStanding)
+	app.Put("/leaderboards/preferences", controller.UpdatePreference)
+	return app
+}
+
+func (suite *LeaderboardControllerTestSuite) TestGetLeaderboardWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockLeaderboardService := new(mockServices.LeaderboardService)
+	app := setupTestLeaderboardController(mockLeaderboardService)
+
+	mockLeaderboardService.EXPECT().GetLeaderboard(mock.MatchedBy(func(query *payload.LeaderboardQuery) bool {
+		return *query.Scope == "field" && *query.ScopeId == 2 && *query.Period == "month" && *query.Limit == 10
+	})).Return(&payload.Leaderboard{
+		Scope:   "field",
+		ScopeId: 2,
+		Period:  "month",
+		Rows:    []*payload.LeaderboardRow{{Rank: 1, UserId: 9, Gems: 12}},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/leaderboards?scope=field&scopeId=2&period=month&limit=10", nil)
+	res, err := app.Test(req)
+
+	var responsePayload response.InfoResponse[payload.Leaderboard]
+	resBody, _ := io.ReadAll(res.Body)
+	json.Unmarshal(resBody, &responsePayload)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Equal(uint64(9), responsePayload.Data.Rows[0].UserId)
+}
+
+func (suite *LeaderboardControllerTestSuite) TestGetLeaderboardWhenScopeIdMissing() {
+	is := assert.New(suite.T())
+
+	mockLeaderboardService := new(mockServices.LeaderboardService)
+	app := setupTestLeaderboardController(mockLeaderboardService)
+
+	req := httptest.NewRequest(http.MethodGet, "/leaderboards?scope=course&period=week", nil)
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusBadRequest, res.StatusCode)
+	mockLeaderboardService.AssertNotCalled(suite.T(), "GetLeaderboard", mock.Anything)
+}
+
+func (suite *LeaderboardControllerTestSuite) TestGetStandingWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockLeaderboardService := new(mockServices.LeaderboardService)
+	app := setupTestLeaderboardController(mockLeaderboardService)
+
+	me := &payload.LeaderboardRow{Rank: 3, UserId: 123, Gems: 5}
+	mockLeaderboardService.EXPECT().GetStanding(uint64(123), mock.Anything).Return(&payload.Leaderboard{
+		Scope:  "global",
+		Period: "all",
+		Me:     me,
+		Rows:   []*payload.LeaderboardRow{me},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/leaderboards/me?scope=global&period=all", nil)
+	res, err := app.Test(req)
+
+	var responsePayload response.InfoResponse[payload.Leaderboard]
+	resBody, _ := io.ReadAll(res.Body)
+	json.Unmarshal(resBody, &responsePayload)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Equal(int64(3), responsePayload.Data.Me.Rank)
+}
+
+func (suite *LeaderboardControllerTestSuite) TestUpdatePreferenceWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockLeaderboardService := new(mockServices.LeaderboardService)
+	app := setupTestLeaderboardController(mockLeaderboardService)
+
+	body := &payload.LeaderboardPreference{Hidden: utils.Ptr(true)}
+	mockLeaderboardService.EXPECT().UpdatePreference(uint64(123), body).Return(body, nil)
+
+	req := httptest.NewRequest(http.MethodPut, "/leaderboards/preferences", bytes.NewBufferString(`{"hidden":true}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+}
+
+func TestLeaderboardController(t *testing.T) {
+	suite.Run(t, new(LeaderboardControllerTestSuite))
+}
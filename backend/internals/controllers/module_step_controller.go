@@ -27,6 +27,7 @@ func NewModuleStepController(moduleStepSvc services.ModuleStepServices) *ModuleS
 // @Accept json
 // @Produce json
 // @Param moduleId path string true "Module ID"
+// @Param courseId query uint false "Course ID, required for modules shared by several courses"
 // @Success 200 {object} response.InfoResponse[[]payload.ModuleStep] "Successful response with module steps and evaluation status"
 // @Failure 400 {object} response.GenericError "Invalid or missing moduleId parameter"
 // @Failure 500 {object} response.GenericError "Internal server error"
@@ -45,6 +46,14 @@ func (c *ModuleStepController) GetModuleSteps(ctx *fiber.Ctx) error {
         }
     }
 
+    query := new(payload.CourseIdQuery)
+    if err := ctx.QueryParser(query); err != nil {
+        return &response.GenericError{
+            Err:     err,
+            Message: "invalid courseId query",
+        }
+    }
+
     var moduleId string
     if param.ModuleId != nil {
         moduleId = strconv.FormatUint(*param.ModuleId, 10)
@@ -56,7 +65,7 @@ func (c *ModuleStepController) GetModuleSteps(ctx *fiber.Ctx) error {
     }
 
     // Fetch steps from service
-    steps, err := c.moduleStepSvc.GetModuleSteps(userId, moduleId)
+    steps, err := c.moduleStepSvc.GetModuleSteps(userId, moduleId, query.CourseId)
     if err != nil {
         return &response.GenericError{
             Err:     err,
@@ -56,7 +56,7 @@ func (suite *ModuleStepControllerTestSuite) TestGetModuleStepsWhenSuccess() {
     }
 
     // Set up the mock expectation
-    mockModuleStepService.EXPECT().GetModuleSteps(userId, moduleId).Return(mockSteps, nil)
+    mockModuleStepService.EXPECT().GetModuleSteps(userId, moduleId, (*uint64)(nil)).Return(mockSteps, nil)
 
     // Create the HTTP request
     req := httptest.NewRequest(http.MethodGet, "/step/123/info", nil)
@@ -94,7 +94,7 @@ func (suite *ModuleStepControllerTestSuite) TestGetModuleStepsWhenServiceFails()
 
     moduleID := "module123"
 
-    mockModuleStepService.EXPECT().GetModuleSteps(uint(123), moduleID).Return(nil, errors.New("service error"))
+    mockModuleStepService.EXPECT().GetModuleSteps(uint(123), moduleID, (*uint64)(nil)).Return(nil, errors.New("service error"))
 
     req := httptest.NewRequest(http.MethodGet, "/step/module123/info", nil)
     req.Header.Set("Authorization", "Bearer mockToken")
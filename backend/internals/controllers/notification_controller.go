@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"backend/internals/entities/payload"
+	"backend/internals/entities/response"
+	"backend/internals/services"
+	"backend/internals/utils"
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type NotificationController struct {
+	notificationSvc services.NotificationService
+}
+
+func NewNotificationController(notificationSvc services.NotificationService) *NotificationController {
+	return &NotificationController{
+		notificationSvc: notificationSvc,
+	}
+}
+
+// GetPreference
+// @ID getNotificationPreference
+// @Tags notification
+// @Summary Get the email language of the user and the emails they receive
+// @Produce json
+// @Success 200 {object} response.InfoResponse[payload.NotificationPreference]
+// @Failure 400 {object} response.GenericError
+// @Router /notifications/preferences [get]
+func (r *NotificationController) GetPreference(c *fiber.Ctx) error {
+	// * login state
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["userId"].(float64)
+
+	preference, err := r.notificationSvc.GetPreference(uint64(userId))
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to get notification preference",
+		}
+	}
+
+	return response.Ok(c, preference)
+}
+
+// UpdatePreference
+// @ID updateNotificationPreference
+// @Tags notification
+// @Summary Set the email language of the user and the emails they receive
+// @Accept json
+// @Produce json
+// @Param q body payload.NotificationPreference true "NotificationPreference"
+// @Success 200 {object} response.InfoResponse[payload.NotificationPreference]
+// @Failure 400 {object} response.GenericError
+// @Router /notifications/preferences [put]
+func (r *NotificationController) UpdatePreference(c *fiber.Ctx) error {
+	body := new(payload.NotificationPreference)
+	if err := c.BodyParser(body); err != nil {
+		return &response.GenericError{
+			Err: err,
+		}
+	}
+
+	// * validate body
+	if err := utils.Validate.Struct(body); err != nil {
+		var validationErrors validator.ValidationErrors
+		errors.As(err, &validationErrors)
+		return &response.GenericError{
+			Err: validationErrors,
+		}
+	}
+
+	// * login state
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["userId"].(float64)
+
+	preference, err := r.notificationSvc.UpdatePreference(uint64(userId), body)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to update notification preference",
+		}
+	}
+
+	return response.Ok(c, preference)
+}
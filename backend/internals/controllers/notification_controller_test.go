@@ -0,0 +1,110 @@
+package controllers_test
+
+import (
+	"backend/internals/controllers"
+	"backend/internals/entities/payload"
+	"backend/internals/entities/response"
+	"backend/internals/routes/handler"
+	"backend/internals/utils"
+	mockServices "backend/mocks/services"
+	"bytes"
+	"encoding/json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type NotificationControllerTestSuite struct {
+	suite.Suite
+}
+
+func setupTestNotificationController(mockNotificationService *mockServices.NotificationService) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: handler.ErrorHandler,
+	})
+
+	controller := controllers.NewNotificationController(mockNotificationService)
+
+	// Middleware to simulate JWT Locals
+	app.Use(func(c *fiber.Ctx) error {
+		token := jwt.New(jwt.SigningMethodHS256)
+		claims := token.Claims.(jwt.MapClaims)
+		claims["userId"] = float64(123)
+		c.Locals("user", token)
+		return c.Next()
+	})
+
+	app.Get("/notifications/preferences", controller.GetPreference)
+	app.Put("/notifications/preferences", controller.UpdatePreference)
+	return app
+}
+
+func (suite *NotificationControllerTestSuite) TestGetPreferenceWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockNotificationService := new(mockServices.NotificationService)
+	app := setupTestNotificationController(mockNotificationService)
+
+	mockNotificationService.EXPECT().GetPreference(uint64(123)).Return(&payload.NotificationPreference{
+		Locale:           utils.Ptr("th"),
+		SubmissionGraded: utils.Ptr(true),
+		CommentReply:     utils.Ptr(true),
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/notifications/preferences", nil)
+	res, err := app.Test(req)
+
+	var responsePayload response.InfoResponse[payload.NotificationPreference]
+	resBody, _ := io.ReadAll(res.Body)
+	json.Unmarshal(resBody, &responsePayload)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Equal("th", *responsePayload.Data.Locale)
+}
+
+func (suite *NotificationControllerTestSuite) TestUpdatePreferenceWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockNotificationService := new(mockServices.NotificationService)
+	app := setupTestNotificationController(mockNotificationService)
+
+	body := &payload.NotificationPreference{
+		Locale:           utils.Ptr("en"),
+		SubmissionGraded: utils.Ptr(true),
+		CommentReply:     utils.Ptr(false),
+	}
+	mockNotificationService.EXPECT().UpdatePreference(uint64(123), body).Return(body, nil)
+
+	req := httptest.NewRequest(http.MethodPut, "/notifications/preferences", bytes.NewBufferString(`{"locale":"en","submissionGraded":true,"commentReply":false}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+}
+
+func (suite *NotificationControllerTestSuite) TestUpdatePreferenceWhenLocaleUnsupported() {
+	is := assert.New(suite.T())
+
+	mockNotificationService := new(mockServices.NotificationService)
+	app := setupTestNotificationController(mockNotificationService)
+
+	req := httptest.NewRequest(http.MethodPut, "/notifications/preferences", bytes.NewBufferString(`{"locale":"fr","submissionGraded":true,"commentReply":true}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusBadRequest, res.StatusCode)
+	mockNotificationService.AssertNotCalled(suite.T(), "UpdatePreference", mock.Anything, mock.Anything)
+}
+
+func TestNotificationController(t *testing.T) {
+	suite.Run(t, new(NotificationControllerTestSuite))
+}
@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"backend/internals/entities/payload"
+	"backend/internals/entities/response"
+	"backend/internals/services"
+	"encoding/json"
+	"github.com/gofiber/fiber/v2"
+)
+
+type OutlineController struct {
+	outlineSyncSvc services.OutlineSyncService
+}
+
+func NewOutlineController(outlineSyncSvc services.OutlineSyncService) *OutlineController {
+	return &OutlineController{
+		outlineSyncSvc: outlineSyncSvc,
+	}
+}
+
+// Webhook
+// @ID outlineWebhook
+// @Tags outline
+// @Summary Receive Outline document events and schedule content imports
+// @Accept json
+// @Produce json
+// @Param Outline-Signature header string true "Outline webhook signature"
+// @Param q body payload.OutlineWebhookEvent true "OutlineWebhookEvent"
+// @Success 200 {object} response.InfoResponse[payload.ImportJobInfo]
+// @Failure 400 {object} response.GenericError
+// @Failure 401 {object} response.ErrorResponse
+// @Router /outline/webhook [post]
+func (r *OutlineController) Webhook(c *fiber.Ctx) error {
+	if err := r.outlineSyncSvc.VerifySignature(c.Get("Outline-Signature"), c.Body()); err != nil {
+		return fiber.NewError(fiber.StatusUnauthorized, err.Error())
+	}
+
+	event := new(payload.OutlineWebhookEvent)
+	if err := json.Unmarshal(c.Body(), event); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid webhook payload",
+		}
+	}
+
+	job, err := r.outlineSyncSvc.EnqueueEvent(event)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to schedule import",
+		}
+	}
+
+	return response.Ok(c, job)
+}
+
+// GetImportJobs
+// @ID getImportJobs
+// @Tags outline
+// @Summary List recent content import jobs with their status and last error
+// @Accept json
+// @Produce json
+// @Param limit query int false "Number of jobs, defaults to 50"
+// @Success 200 {object} response.InfoResponse[[]payload.ImportJobInfo]
+// @Failure 400 {object} response.GenericError
+// @Router /admin/outline/jobs [get]
+func (r *OutlineController) GetImportJobs(c *fiber.Ctx) error {
+	query := new(payload.ImportJobQuery)
+	if err := c.QueryParser(query); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid query",
+		}
+	}
+
+	limit := 50
+	if query.Limit != nil && *query.Limit > 0 {
+		limit = *query.Limit
+	}
+
+	jobs, err := r.outlineSyncSvc.GetImportJobs(limit)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to get import jobs",
+		}
+	}
+
+	return response.Ok(c, jobs)
+}
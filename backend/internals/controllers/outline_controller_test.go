@@ -0,0 +1,107 @@
+package controllers_test
+
+import (
+	"backend/internals/controllers"
+	"backend/internals/entities/payload"
+	"backend/internals/entities/response"
+	"backend/internals/routes/handler"
+	"backend/internals/utils"
+	mockServices "backend/mocks/services"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type OutlineControllerTestSuite struct {
+	suite.Suite
+}
+
+func setupTestOutlineController(mockOutlineSyncService *mockServices.OutlineSyncService) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: handler.ErrorHandler,
+	})
+
+	outlineController := controllers.NewOutlineController(mockOutlineSyncService)
+
+	app.Post("/outline/webhook", outlineController.Webhook)
+	app.Get("/admin/outline/jobs", outlineController.GetImportJobs)
+	return app
+}
+
+func (suite *OutlineControllerTestSuite) TestWebhookWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockOutlineSyncService := new(mockServices.OutlineSyncService)
+	app := setupTestOutlineController(mockOutlineSyncService)
+
+	body := []byte(`{"event":"documents.update","payload":{"id":"doc","model":{"id":"doc"}}}`)
+	mockOutlineSyncService.EXPECT().VerifySignature("t=1,s=abc", body).Return(nil)
+	mockOutlineSyncService.EXPECT().EnqueueEvent(mock.MatchedBy(func(event *payload.OutlineWebhookEvent) bool {
+		return event.Event == "documents.update" && event.Payload.Model.Id == "doc"
+	})).Return(&payload.ImportJobInfo{Id: utils.Ptr(uint64(1)), Status: utils.Ptr("pending")}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/outline/webhook", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Outline-Signature", "t=1,s=abc")
+
+	res, err := app.Test(req)
+
+	var responsePayload response.InfoResponse[payload.ImportJobInfo]
+	resBody, _ := io.ReadAll(res.Body)
+	json.Unmarshal(resBody, &responsePayload)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Equal("pending", *responsePayload.Data.Status)
+}
+
+func (suite *OutlineControllerTestSuite) TestWebhookWhenSignatureInvalid() {
+	is := assert.New(suite.T())
+
+	mockOutlineSyncService := new(mockServices.OutlineSyncService)
+	app := setupTestOutlineController(mockOutlineSyncService)
+
+	mockOutlineSyncService.EXPECT().VerifySignature(mock.Anything, mock.Anything).Return(fmt.Errorf("invalid outline signature"))
+
+	req := httptest.NewRequest(http.MethodPost, "/outline/webhook", bytes.NewReader([]byte(`{}`)))
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusUnauthorized, res.StatusCode)
+	mockOutlineSyncService.AssertNotCalled(suite.T(), "EnqueueEvent", mock.Anything)
+}
+
+func (suite *OutlineControllerTestSuite) TestGetImportJobsWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockOutlineSyncService := new(mockServices.OutlineSyncService)
+	app := setupTestOutlineController(mockOutlineSyncService)
+
+	mockOutlineSyncService.EXPECT().GetImportJobs(10).Return([]*payload.ImportJobInfo{
+		{Id: utils.Ptr(uint64(2)), Status: utils.Ptr("failed"), LastError: utils.Ptr("module not found")},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/outline/jobs?limit=10", nil)
+	res, err := app.Test(req)
+
+	var responsePayload response.InfoResponse[[]payload.ImportJobInfo]
+	resBody, _ := io.ReadAll(res.Body)
+	json.Unmarshal(resBody, &responsePayload)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Len(responsePayload.Data, 1)
+	is.Equal("module not found", *responsePayload.Data[0].LastError)
+}
+
+func TestOutlineController(t *testing.T) {
+	suite.Run(t, new(OutlineControllerTestSuite))
+}
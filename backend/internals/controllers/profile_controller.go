@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"backend/internals/entities/payload"
 	"backend/internals/entities/response"
 	"backend/internals/services"
 	"backend/internals/utils"
@@ -12,11 +13,13 @@ import (
 
 type ProfileController struct {
 	profileSvc services.ProfileService
+	streakSvc  services.StreakService
 }
 
-func NewProfileController(profileSvc services.ProfileService) ProfileController {
+func NewProfileController(profileSvc services.ProfileService, streakSvc services.StreakService) ProfileController {
 	return ProfileController{
 		profileSvc: profileSvc,
+		streakSvc:  streakSvc,
 	}
 }
 
@@ -73,3 +76,100 @@ func (r *ProfileController) GetUserGems(c *fiber.Ctx) error {
 
 	return response.Ok(c, totalGems)
 }
+
+// UpdateTimezone
+// @ID updateProfileTimezone
+// @Tags profile
+// @Summary Set the time zone activity days and streaks are counted in
+// @Accept json
+// @Produce json
+// @Param body body payload.ProfileTimezone true "time zone, e.g. Asia/Bangkok"
+// @Success 200 {object} response.InfoResponse[payload.Profile]
+// @Failure 400 {object} response.GenericError
+// @Router /profile/timezone [put]
+func (r *ProfileController) UpdateTimezone(c *fiber.Ctx) error {
+	user := c.Locals("user").(*jwt.Token).Claims.(jwt.MapClaims)
+	userId := user["userId"].(float64)
+
+	body := new(payload.ProfileTimezone)
+	if err := c.BodyParser(body); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid request body",
+		}
+	}
+
+	if err := utils.Validate.Struct(body); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid time zone",
+		}
+	}
+
+	userProfile, err := r.profileSvc.UpdateTimezone(utils.Ptr(strconv.Itoa(int(userId))), body)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to update time zone",
+		}
+	}
+
+	return response.Ok(c, userProfile)
+}
+
+// GetActivityHeatmap
+// @ID getActivityHeatmap
+// @Tags profile
+// @Summary Activities of the user per day over the last year
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.InfoResponse[payload.ActivityHeatmap]
+// @Failure 400 {object} response.GenericError
+// @Router /profile/activity/heatmap [get]
+func (r *ProfileController) GetActivityHeatmap(c *fiber.Ctx) error {
+	user := c.Locals("user").(*jwt.Token).Claims.(jwt.MapClaims)
+	userId := user["userId"].(float64)
+
+	heatmap, err := r.streakSvc.GetHeatmap(uint64(userId))
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to get activity heatmap",
+		}
+	}
+
+	return response.Ok(c, heatmap)
+}
+
+// GetTimeSpent
+// @ID getTimeSpent
+// @Tags profile
+// @Summary Active time of the user per course, or per module and step of a course
+// @Accept json
+// @Produce json
+// @Param courseId query uint64 false "Course ID"
+// @Success 200 {object} response.InfoResponse[payload.TimeSpentReport]
+// @Failure 400 {object} response.GenericError
+// @Router /profile/time-spent [get]
+func (r *ProfileController) GetTimeSpent(c *fiber.Ctx) error {
+	user := c.Locals("user").(*jwt.Token).Claims.(jwt.MapClaims)
+	userId := user["userId"].(float64)
+
+	query := new(payload.TimeSpentQuery)
+	if err := c.QueryParser(query); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid courseId query",
+		}
+	}
+
+	report, err := r.profileSvc.GetTimeSpent(uint64(userId), query.CourseId)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to get time spent",
+		}
+	}
+
+	return response.Ok(c, report)
+}
@@ -16,6 +16,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -23,7 +24,7 @@ type ProfileControllerTestSuit struct {
 	suite.Suite
 }
 
-func setupTestProfileController(mockProfileService *mockServices.ProfileService) *fiber.App {
+func setupTestProfileController(mockProfileService *mockServices.ProfileService, mockStreakService ...*mockServices.StreakService) *fiber.App {
 	fiberConfig := fiber.Config{
 		ErrorHandler: handler.ErrorHandler,
 	}
@@ -31,7 +32,11 @@ func setupTestProfileController(mockProfileService *mockServices.ProfileService)
 	app := fiber.New(fiberConfig)
 
 	// Initialize the controller
-	profileController := NewProfileController(mockProfileService)
+	streakService := new(mockServices.StreakService)
+	if len(mockStreakService) > 0 {
+		streakService = mockStreakService[0]
+	}
+	profileController := NewProfileController(mockProfileService, streakService)
 
 	// Middleware to simulate JWT Locals
 	app.Use(func(c *fiber.Ctx) error {
@@ -45,6 +50,9 @@ func setupTestProfileController(mockProfileService *mockServices.ProfileService)
 	// Register the route
 	app.Get("/profile/info", profileController.ProfileUserInfo)
 	app.Get("/profile/totalgems", profileController.GetUserGems) // Add the route for total gems
+	app.Put("/profile/timezone", profileController.UpdateTimezone)
+	app.Get("/profile/activity/heatmap", profileController.GetActivityHeatmap)
+	app.Get("/profile/time-spent", profileController.GetTimeSpent)
 	return app
 }
 
@@ -62,7 +70,7 @@ func (suite *ProfileControllerTestSuit) TestGetUserGemsWhenSuccess() {
 	mockProfileService.EXPECT().GetTotalGems(mock.Anything).Return(&expectedGems, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/profile/totalgems", nil) // Correct URL path
-	req.Header.Set("Authorization", "Bearer mockToken") // Add mock JWT token to the header
+	req.Header.Set("Authorization", "Bearer mockToken")                   // Add mock JWT token to the header
 
 	res, err := app.Test(req)
 
@@ -85,7 +93,7 @@ func (suite *ProfileControllerTestSuit) TestGetUserGemsWhenFailedToFetchTotalGem
 	mockProfileService.EXPECT().GetTotalGems(mock.Anything).Return(nil, fmt.Errorf("failed to fetch total gems"))
 
 	req := httptest.NewRequest(http.MethodGet, "/profile/totalgems", nil) // Correct URL path
-	req.Header.Set("Authorization", "Bearer mockToken") // Add mock JWT token to the header
+	req.Header.Set("Authorization", "Bearer mockToken")                   // Add mock JWT token to the header
 
 	res, err := app.Test(req)
 
@@ -146,6 +154,115 @@ func (suite *ProfileControllerTestSuit) TestProfileUserInfoWhenFailedToGetUserPr
 	is.Equal("failed to get user profile", errResponse.Message)
 }
 
+func (suite *ProfileControllerTestSuit) TestUpdateTimezoneWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockProfileService := new(mockServices.ProfileService)
+
+	app := setupTestProfileController(mockProfileService)
+
+	mockProfileService.EXPECT().UpdateTimezone(utils.Ptr("123"), &payload.ProfileTimezone{Timezone: utils.Ptr("Europe/Paris")}).
+		Return(&payload.Profile{Timezone: utils.Ptr("Europe/Paris")}, nil)
+
+	req := httptest.NewRequest(http.MethodPut, "/profile/timezone", strings.NewReader(`{"timezone":"Europe/Paris"}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+
+	r := new(response.InfoResponse[payload.Profile])
+	body, _ := io.ReadAll(res.Body)
+	json.Unmarshal(body, &r)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Equal("Europe/Paris", *r.Data.Timezone)
+}
+
+func (suite *ProfileControllerTestSuit) TestUpdateTimezoneWhenUnknown() {
+	is := assert.New(suite.T())
+
+	mockProfileService := new(mockServices.ProfileService)
+
+	app := setupTestProfileController(mockProfileService)
+
+	req := httptest.NewRequest(http.MethodPut, "/profile/timezone", strings.NewReader(`{"timezone":"Mars/Olympus"}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusBadRequest, res.StatusCode)
+	mockProfileService.AssertNotCalled(suite.T(), "UpdateTimezone", mock.Anything, mock.Anything)
+}
+
+func (suite *ProfileControllerTestSuit) TestGetActivityHeatmapWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockProfileService := new(mockServices.ProfileService)
+	mockStreakService := new(mockServices.StreakService)
+
+	app := setupTestProfileController(mockProfileService, mockStreakService)
+
+	mockStreakService.EXPECT().GetHeatmap(uint64(123)).Return(&payload.ActivityHeatmap{
+		From:     "2024-02-01",
+		To:       "2025-01-31",
+		Timezone: "Asia/Bangkok",
+		Days:     []*payload.ActivityDay{{Day: "2025-01-30", Count: 4}},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/profile/activity/heatmap", nil)
+	res, err := app.Test(req)
+
+	r := new(response.InfoResponse[payload.ActivityHeatmap])
+	body, _ := io.ReadAll(res.Body)
+	json.Unmarshal(body, &r)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Len(r.Data.Days, 1)
+	is.Equal(int64(4), r.Data.Days[0].Count)
+}
+
+func (suite *ProfileControllerTestSuit) TestGetActivityHeatmapWhenFailed() {
+	is := assert.New(suite.T())
+
+	mockProfileService := new(mockServices.ProfileService)
+	mockStreakService := new(mockServices.StreakService)
+
+	app := setupTestProfileController(mockProfileService, mockStreakService)
+
+	mockStreakService.EXPECT().GetHeatmap(uint64(123)).Return(nil, fmt.Errorf("database error"))
+
+	req := httptest.NewRequest(http.MethodGet, "/profile/activity/heatmap", nil)
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusInternalServerError, res.StatusCode)
+}
+
+func (suite *ProfileControllerTestSuit) TestGetTimeSpentWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockProfileService := new(mockServices.ProfileService)
+
+	app := setupTestProfileController(mockProfileService)
+
+	mockProfileService.EXPECT().GetTimeSpent(uint64(123), utils.Ptr(uint64(3))).Return(&payload.TimeSpentReport{
+		CourseId:     utils.Ptr(uint64(3)),
+		TotalSeconds: 600,
+		Steps:        []*payload.TimeSpent{{Id: utils.Ptr(uint64(8)), Seconds: 600}},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/profile/time-spent?courseId=3", nil)
+	res, err := app.Test(req)
+
+	r := new(response.InfoResponse[payload.TimeSpentReport])
+	body, _ := io.ReadAll(res.Body)
+	json.Unmarshal(body, &r)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Equal(int64(600), r.Data.TotalSeconds)
+}
+
 func TestProfileController(t *testing.T) {
 	suite.Run(t, new(ProfileControllerTestSuit))
 }
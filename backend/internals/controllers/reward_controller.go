@@ -0,0 +1,299 @@
+package controllers
+
+import (
+	"backend/internals/entities/payload"
+	"backend/internals/entities/response"
+	"backend/internals/services"
+	"backend/internals/utils"
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type RewardController struct {
+	rewardSvc services.RewardService
+}
+
+func NewRewardController(rewardSvc services.RewardService) *RewardController {
+	return &RewardController{
+		rewardSvc: rewardSvc,
+	}
+}
+
+// GetCatalog
+// @ID getRewardCatalog
+// @Tags reward
+// @Summary List the reward items learners can redeem gems for
+// @Produce json
+// @Success 200 {object} response.InfoResponse[[]payload.RewardItemInfo]
+// @Failure 400 {object} response.GenericError
+// @Router /rewards [get]
+func (r *RewardController) GetCatalog(c *fiber.Ctx) error {
+	items, err := r.rewardSvc.GetCatalog(false)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to get reward catalog",
+		}
+	}
+
+	return response.Ok(c, items)
+}
+
+// Redeem
+// @ID redeemReward
+// @Tags reward
+// @Summary Spend gems on a reward item, staff hand it over later
+// @Produce json
+// @Param rewardItemId path uint64 true "Reward item ID"
+// @Success 200 {object} response.InfoResponse[payload.RedemptionInfo]
+// @Failure 400 {object} response.GenericError
+// @Failure 404 {object} response.GenericError
+// @Failure 409 {object} response.GenericError
+// @Router /rewards/{rewardItemId}/redeem [post]
+func (r *RewardController) Redeem(c *fiber.Ctx) error {
+	param := new(payload.RewardItemIdParam)
+	if err := c.ParamsParser(param); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid rewardItemId parameter",
+		}
+	}
+
+	// * login state
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["userId"].(float64)
+
+	redemption, err := r.rewardSvc.Redeem(uint64(userId), *param.RewardItemId)
+	if errors.Is(err, services.ErrRewardItemNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	if errors.Is(err, services.ErrRewardNotRedeemable) {
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to redeem reward",
+		}
+	}
+
+	return response.Ok(c, redemption)
+}
+
+// GetMyRedemptions
+// @ID getMyRedemptions
+// @Tags reward
+// @Summary List the redemptions of the user, latest first
+// @Produce json
+// @Success 200 {object} response.InfoResponse[[]payload.RedemptionInfo]
+// @Failure 400 {object} response.GenericError
+// @Router /rewards/redemptions/mine [get]
+func (r *RewardController) GetMyRedemptions(c *fiber.Ctx) error {
+	// * login state
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["userId"].(float64)
+
+	redemptions, err := r.rewardSvc.GetMyRedemptions(uint64(userId))
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to get redemptions",
+		}
+	}
+
+	return response.Ok(c, redemptions)
+}
+
+// GetItems
+// @ID getRewardItems
+// @Tags admin
+// @Summary List every reward item, inactive ones included
+// @Produce json
+// @Success 200 {object} response.InfoResponse[[]payload.RewardItemInfo]
+// @Failure 400 {object} response.GenericError
+// @Router /admin/rewards [get]
+func (r *RewardController) GetItems(c *fiber.Ctx) error {
+	items, err := r.rewardSvc.GetCatalog(true)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to get reward items",
+		}
+	}
+
+	return response.Ok(c, items)
+}
+
+// CreateItem
+// @ID createRewardItem
+// @Tags admin
+// @Summary Add an item to the reward catalog
+// @Accept json
+// @Produce json
+// @Param q body payload.CreateRewardItem true "CreateRewardItem"
+// @Success 200 {object} response.InfoResponse[payload.RewardItemInfo]
+// @Failure 400 {object} response.GenericError
+// @Router /admin/rewards [post]
+func (r *RewardController) CreateItem(c *fiber.Ctx) error {
+	body := new(payload.CreateRewardItem)
+	if err := c.BodyParser(body); err != nil {
+		return &response.GenericError{
+			Err: err,
+		}
+	}
+
+	// * validate body
+	if err := utils.Validate.Struct(body); err != nil {
+		var validationErrors validator.ValidationErrors
+		errors.As(err, &validationErrors)
+		return &response.GenericError{
+			Err: validationErrors,
+		}
+	}
+
+	item, err := r.rewardSvc.CreateItem(body)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to create reward item",
+		}
+	}
+
+	return response.Ok(c, item)
+}
+
+// UpdateItem
+// @ID updateRewardItem
+// @Tags admin
+// @Summary Change the price, stock or other fields of a reward item
+// @Accept json
+// @Produce json
+// @Param rewardItemId path uint64 true "Reward item ID"
+// @Param q body payload.UpdateRewardItem true "UpdateRewardItem"
+// @Success 200 {object} response.InfoResponse[payload.RewardItemInfo]
+// @Failure 400 {object} response.GenericError
+// @Failure 404 {object} response.GenericError
+// @Router /admin/rewards/{rewardItemId} [put]
+func (r *RewardController) UpdateItem(c *fiber.Ctx) error {
+	param := new(payload.RewardItemIdParam)
+	if err := c.ParamsParser(param); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid rewardItemId parameter",
+		}
+	}
+
+	body := new(payload.UpdateRewardItem)
+	if err := c.BodyParser(body); err != nil {
+		return &response.GenericError{
+			Err: err,
+		}
+	}
+
+	// * validate body
+	if err := utils.Validate.Struct(body); err != nil {
+		var validationErrors validator.ValidationErrors
+		errors.As(err, &validationErrors)
+		return &response.GenericError{
+			Err: validationErrors,
+		}
+	}
+
+	item, err := r.rewardSvc.UpdateItem(*param.RewardItemId, body)
+	if errors.Is(err, services.ErrRewardItemNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to update reward item",
+		}
+	}
+
+	return response.Ok(c, item)
+}
+
+// GetRedemptions
+// @ID getRedemptions
+// @Tags instructor
+// @Summary List the redemptions to hand over, oldest first
+// @Produce json
+// @Param status query string false "pending or fulfilled"
+// @Success 200 {object} response.InfoResponse[[]payload.RedemptionInfo]
+// @Failure 400 {object} response.GenericError
+// @Router /instructor/redemptions [get]
+func (r *RewardController) GetRedemptions(c *fiber.Ctx) error {
+	query := new(payload.RedemptionQuery)
+	if err := c.QueryParser(query); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid query",
+		}
+	}
+
+	// * validate query
+	if err := utils.Validate.Struct(query); err != nil {
+		var validationErrors validator.ValidationErrors
+		errors.As(err, &validationErrors)
+		return &response.GenericError{
+			Err: validationErrors,
+		}
+	}
+
+	redemptions, err := r.rewardSvc.GetRedemptions(query.Status)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to get redemptions",
+		}
+	}
+
+	return response.Ok(c, redemptions)
+}
+
+// Fulfill
+// @ID fulfillRedemption
+// @Tags instructor
+// @Summary Mark a pending redemption as handed over
+// @Produce json
+// @Param redemptionId path uint64 true "Redemption ID"
+// @Success 200 {object} response.InfoResponse[payload.RedemptionInfo]
+// @Failure 400 {object} response.GenericError
+// @Failure 404 {object} response.GenericError
+// @Failure 409 {object} response.GenericError
+// @Router /instructor/redemptions/{redemptionId}/fulfill [post]
+func (r *RewardController) Fulfill(c *fiber.Ctx) error {
+	param := new(payload.RedemptionIdParam)
+	if err := c.ParamsParser(param); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid redemptionId parameter",
+		}
+	}
+
+	// * login state
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	staffId := claims["userId"].(float64)
+
+	redemption, err := r.rewardSvc.Fulfill(*param.RedemptionId, uint64(staffId))
+	if errors.Is(err, services.ErrRedemptionNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	if errors.Is(err, services.ErrRedemptionNotPending) {
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to fulfill redemption",
+		}
+	}
+
+	return response.Ok(c, redemption)
+}
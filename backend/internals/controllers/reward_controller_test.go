@@ -0,0 +1,178 @@
+package controllers_test
+
+import (
+	"backend/internals/controllers"
+	"backend/internals/entities/payload"
+	"backend/internals/entities/response"
+	"backend/internals/repositories"
+	"backend/internals/routes/handler"
+	"backend/internals/services"
+	"backend/internals/utils"
+	mockServices "backend/mocks/services"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type RewardControllerTestSuite struct {
+	suite.Suite
+}
+
+func setupTestRewardController(mockRewardService *mockServices.RewardService) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: handler.ErrorHandler,
+	})
+
+	controller := controllers.NewRewardController(mockRewardService)
+
+	// Middleware to simulate JWT Locals
+	app.Use(func(c *fiber.Ctx) error {
+		token := jwt.New(jwt.SigningMethodHS256)
+		claims := token.Claims.(jwt.MapClaims)
+		claims["userId"] = float64(123)
+		c.Locals("user", token)
+		return c.Next()
+	})
+
+	app.Get("/rewards", controller.GetCatalog)
+	app.Post("/rewards/:rewardItemId/redeem", controller.Redeem)
+	app.Post("/admin/rewards", controller.CreateItem)
+	app.Get("/instructor/redemptions", controller.GetRedemptions)
+	app.Post("/instructor/redemptions/:redemptionId/fulfill", controller.Fulfill)
+	return app
+}
+
+func (suite *RewardControllerTestSuite) TestGetCatalogWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockRewardService := new(mockServices.RewardService)
+	app := setupTestRewardController(mockRewardService)
+
+	mockRewardService.EXPECT().GetCatalog(false).Return([]*payload.RewardItemInfo{
+		{Id: utils.Ptr(uint64(1)), Name: utils.Ptr("Sticker pack"), Price: utils.Ptr(int64(20))},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/rewards", nil)
+	res, err := app.Test(req)
+
+	var responsePayload response.InfoResponse[[]payload.RewardItemInfo]
+	resBody, _ := io.ReadAll(res.Body)
+	json.Unmarshal(resBody, &responsePayload)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Len(responsePayload.Data, 1)
+}
+
+func (suite *RewardControllerTestSuite) TestRedeemWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockRewardService := new(mockServices.RewardService)
+	app := setupTestRewardController(mockRewardService)
+
+	mockRewardService.EXPECT().Redeem(uint64(123), uint64(7)).Return(&payload.RedemptionInfo{
+		Id:     utils.Ptr(uint64(1)),
+		Price:  utils.Ptr(int64(20)),
+		Status: utils.Ptr(payload.RedemptionStatusPending),
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/rewards/7/redeem", nil)
+	res, err := app.Test(req)
+
+	var responsePayload response.InfoResponse[payload.RedemptionInfo]
+	resBody, _ := io.ReadAll(res.Body)
+	json.Unmarshal(resBody, &responsePayload)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Equal(payload.RedemptionStatusPending, *responsePayload.Data.Status)
+}
+
+func (suite *RewardControllerTestSuite) TestRedeemWhenBalanceTooLow() {
+	is := assert.New(suite.T())
+
+	mockRewardService := new(mockServices.RewardService)
+	app := setupTestRewardController(mockRewardService)
+
+	mockRewardService.EXPECT().Redeem(uint64(123), uint64(7)).
+		Return(nil, fmt.Errorf("%w: %w", services.ErrRewardNotRedeemable, repositories.ErrGemsInsufficient))
+
+	req := httptest.NewRequest(http.MethodPost, "/rewards/7/redeem", nil)
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusConflict, res.StatusCode)
+}
+
+func (suite *RewardControllerTestSuite) TestRedeemWhenItemNotFound() {
+	is := assert.New(suite.T())
+
+	mockRewardService := new(mockServices.RewardService)
+	app := setupTestRewardController(mockRewardService)
+
+	mockRewardService.EXPECT().Redeem(uint64(123), uint64(7)).Return(nil, services.ErrRewardItemNotFound)
+
+	req := httptest.NewRequest(http.MethodPost, "/rewards/7/redeem", nil)
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusNotFound, res.StatusCode)
+}
+
+func (suite *RewardControllerTestSuite) TestCreateItemWhenInvalid() {
+	is := assert.New(suite.T())
+
+	mockRewardService := new(mockServices.RewardService)
+	app := setupTestRewardController(mockRewardService)
+
+	body, _ := json.Marshal(map[string]any{"name": "Sensor kit", "price": 0, "stock": 5})
+	req := httptest.NewRequest(http.MethodPost, "/admin/rewards", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusBadRequest, res.StatusCode)
+	mockRewardService.AssertNotCalled(suite.T(), "CreateItem", mock.Anything)
+}
+
+func (suite *RewardControllerTestSuite) TestGetRedemptionsWhenInvalidStatus() {
+	is := assert.New(suite.T())
+
+	mockRewardService := new(mockServices.RewardService)
+	app := setupTestRewardController(mockRewardService)
+
+	req := httptest.NewRequest(http.MethodGet, "/instructor/redemptions?status=lost", nil)
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusBadRequest, res.StatusCode)
+}
+
+func (suite *RewardControllerTestSuite) TestFulfillWhenAlreadyFulfilled() {
+	is := assert.New(suite.T())
+
+	mockRewardService := new(mockServices.RewardService)
+	app := setupTestRewardController(mockRewardService)
+
+	mockRewardService.EXPECT().Fulfill(uint64(5), uint64(123)).
+		Return(nil, fmt.Errorf("%w: %w", services.ErrRedemptionNotPending, repositories.ErrRedemptionFulfilled))
+
+	req := httptest.NewRequest(http.MethodPost, "/instructor/redemptions/5/fulfill", nil)
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusConflict, res.StatusCode)
+}
+
+func TestRewardController(t *testing.T) {
+	suite.Run(t, new(RewardControllerTestSuite))
+}
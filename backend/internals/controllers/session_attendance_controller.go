@@ -0,0 +1,160 @@
+package controllers
+
+import (
+	"backend/internals/entities/payload"
+	"backend/internals/entities/response"
+	"backend/internals/services"
+	"backend/internals/utils"
+	"errors"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type SessionAttendanceController struct {
+	sessionAttendanceSvc services.SessionAttendanceService
+}
+
+func NewSessionAttendanceController(sessionAttendanceSvc services.SessionAttendanceService) *SessionAttendanceController {
+	return &SessionAttendanceController{
+		sessionAttendanceSvc: sessionAttendanceSvc,
+	}
+}
+
+// GetCheckInCode
+// @ID getCheckInCode
+// @Tags instructor
+// @Summary Get the current rotating check-in code of a session
+// @Accept json
+// @Produce json
+// @Param sessionId path uint64 true "Session ID"
+// @Success 200 {object} response.InfoResponse[payload.CheckInCode]
+// @Failure 400 {object} response.GenericError
+// @Router /instructor/sessions/{sessionId}/check-in-code [get]
+func (r *SessionAttendanceController) GetCheckInCode(c *fiber.Ctx) error {
+	param := new(payload.SessionIdParam)
+	if err := c.ParamsParser(param); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid sessionId parameter",
+		}
+	}
+
+	code, err := r.sessionAttendanceSvc.GenerateCheckInCode(*param.SessionId)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to generate check-in code",
+		}
+	}
+
+	return response.Ok(c, code)
+}
+
+// GetCheckInQrCode
+// @ID getCheckInQrCode
+// @Tags instructor
+// @Summary Get the current rotating check-in code of a session as a QR code image
+// @Produce image/png
+// @Param sessionId path uint64 true "Session ID"
+// @Success 200 {file} file
+// @Failure 400 {object} response.GenericError
+// @Router /instructor/sessions/{sessionId}/check-in-qr [get]
+func (r *SessionAttendanceController) GetCheckInQrCode(c *fiber.Ctx) error {
+	param := new(payload.SessionIdParam)
+	if err := c.ParamsParser(param); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid sessionId parameter",
+		}
+	}
+
+	png, err := r.sessionAttendanceSvc.GenerateCheckInQrCode(*param.SessionId)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to generate check-in qr code",
+		}
+	}
+
+	// the code rotates, never serve a cached image
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	c.Set(fiber.HeaderContentType, "image/png")
+	return c.Send(png)
+}
+
+// CheckIn
+// @ID checkIn
+// @Tags session
+// @Summary Record the attendance of the user with the check-in code shown in the session
+// @Accept json
+// @Produce json
+// @Param q body payload.CheckInBody true "CheckInBody"
+// @Success 200 {object} response.InfoResponse[payload.AttendanceInfo]
+// @Failure 400 {object} response.GenericError
+// @Router /sessions/check-in [post]
+func (r *SessionAttendanceController) CheckIn(c *fiber.Ctx) error {
+	body := new(payload.CheckInBody)
+	if err := c.BodyParser(body); err != nil {
+		return &response.GenericError{
+			Err: err,
+		}
+	}
+
+	// * validate body
+	if err := utils.Validate.Struct(body); err != nil {
+		var validationErrors validator.ValidationErrors
+		errors.As(err, &validationErrors)
+		return &response.GenericError{
+			Err: validationErrors,
+		}
+	}
+
+	// * login state
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["userId"].(float64)
+
+	attendance, err := r.sessionAttendanceSvc.CheckIn(*body.Code, uint64(userId))
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to check in",
+		}
+	}
+
+	return response.Ok(c, attendance)
+}
+
+// ExportRoster
+// @ID exportAttendanceRoster
+// @Tags instructor
+// @Summary Export the registrations and check-ins of a session as CSV
+// @Produce text/csv
+// @Param sessionId path uint64 true "Session ID"
+// @Success 200 {file} file
+// @Failure 400 {object} response.GenericError
+// @Router /instructor/sessions/{sessionId}/roster [get]
+func (r *SessionAttendanceController) ExportRoster(c *fiber.Ctx) error {
+	param := new(payload.SessionIdParam)
+	if err := c.ParamsParser(param); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid sessionId parameter",
+		}
+	}
+
+	roster, err := r.sessionAttendanceSvc.ExportRoster(*param.SessionId)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to export roster",
+		}
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="session-%d-roster.csv"`, *param.SessionId))
+	return c.Send(roster)
+}
@@ -0,0 +1,132 @@
+package controllers_test
+
+import (
+	"backend/internals/controllers"
+	"backend/internals/entities/payload"
+	"backend/internals/entities/response"
+	"backend/internals/routes/handler"
+	"backend/internals/utils"
+	mockServices "backend/mocks/services"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type SessionAttendanceControllerTestSuite struct {
+	suite.Suite
+}
+
+func setupTestSessionAttendanceController(mockSessionAttendanceService *mockServices.SessionAttendanceService) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: handler.ErrorHandler,
+	})
+
+	controller := controllers.NewSessionAttendanceController(mockSessionAttendanceService)
+
+	// Middleware to simulate JWT Locals
+	app.Use(func(c *fiber.Ctx) error {
+		token := jwt.New(jwt.SigningMethodHS256)
+		claims := token.Claims.(jwt.MapClaims)
+		claims["userId"] = float64(123)
+		c.Locals("user", token)
+		return c.Next()
+	})
+
+	app.Post("/sessions/check-in", controller.CheckIn)
+	app.Get("/instructor/sessions/:sessionId/check-in-qr", controller.GetCheckInQrCode)
+	app.Get("/instructor/sessions/:sessionId/roster", controller.ExportRoster)
+	return app
+}
+
+func (suite *SessionAttendanceControllerTestSuite) TestCheckInWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockSessionAttendanceService := new(mockServices.SessionAttendanceService)
+	app := setupTestSessionAttendanceController(mockSessionAttendanceService)
+
+	mockSessionAttendanceService.EXPECT().CheckIn("4.1.sig", uint64(123)).Return(&payload.AttendanceInfo{
+		SessionId:   utils.Ptr(uint64(4)),
+		CheckedInAt: utils.TimeNowPtr(),
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/sessions/check-in", bytes.NewReader([]byte(`{"code":"4.1.sig"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+
+	var responsePayload response.InfoResponse[payload.AttendanceInfo]
+	resBody, _ := io.ReadAll(res.Body)
+	json.Unmarshal(resBody, &responsePayload)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Equal(uint64(4), *responsePayload.Data.SessionId)
+}
+
+func (suite *SessionAttendanceControllerTestSuite) TestCheckInWhenCodeExpired() {
+	is := assert.New(suite.T())
+
+	mockSessionAttendanceService := new(mockServices.SessionAttendanceService)
+	app := setupTestSessionAttendanceController(mockSessionAttendanceService)
+
+	mockSessionAttendanceService.EXPECT().CheckIn("4.1.sig", uint64(123)).Return(nil, fmt.Errorf("check-in code has expired"))
+
+	req := httptest.NewRequest(http.MethodPost, "/sessions/check-in", bytes.NewReader([]byte(`{"code":"4.1.sig"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+
+	r := new(response.GenericError)
+	resBody, _ := io.ReadAll(res.Body)
+	json.Unmarshal(resBody, r)
+
+	is.Nil(err)
+	is.Equal(http.StatusInternalServerError, res.StatusCode)
+	is.Equal("failed to check in", r.Message)
+}
+
+func (suite *SessionAttendanceControllerTestSuite) TestGetCheckInQrCodeWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockSessionAttendanceService := new(mockServices.SessionAttendanceService)
+	app := setupTestSessionAttendanceController(mockSessionAttendanceService)
+
+	mockSessionAttendanceService.EXPECT().GenerateCheckInQrCode(uint64(4)).Return([]byte("\x89PNG"), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/instructor/sessions/4/check-in-qr", nil)
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Equal("image/png", res.Header.Get("Content-Type"))
+	is.Equal("no-store", res.Header.Get("Cache-Control"))
+}
+
+func (suite *SessionAttendanceControllerTestSuite) TestExportRosterWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockSessionAttendanceService := new(mockServices.SessionAttendanceService)
+	app := setupTestSessionAttendanceController(mockSessionAttendanceService)
+
+	mockSessionAttendanceService.EXPECT().ExportRoster(uint64(4)).Return([]byte("user_id\n1\n"), nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/instructor/sessions/4/roster", nil)
+	res, err := app.Test(req)
+
+	body, _ := io.ReadAll(res.Body)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Equal("text/csv", res.Header.Get("Content-Type"))
+	is.Equal("user_id\n1\n", string(body))
+}
+
+func TestSessionAttendanceController(t *testing.T) {
+	suite.Run(t, new(SessionAttendanceControllerTestSuite))
+}
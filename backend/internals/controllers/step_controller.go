@@ -36,6 +36,7 @@ func NewStepController(stepSvc services.StepService, conf *config.Config, minioS
 // @Accept json
 // @Produce json
 // @Param stepId path uint true "Step ID"
+// @Param courseId query uint false "Course ID, required for modules shared by several courses"
 // @Success 200 {object} response.InfoResponse[payload.StepInfo]
 // @Failure 400 {object} response.GenericError
 // @Router /step/{stepId} [get]
@@ -47,8 +48,20 @@ func (r *StepController) GetStepInfo(c *fiber.Ctx) error {
 			Message: "invalid stepId param",
 		}
 	}
+	query := new(payload.CourseIdQuery)
+	if err := c.QueryParser(query); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid courseId query",
+		}
+	}
+
+	// * login state
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["userId"].(float64)
 
-	stepInfo, err := r.stepSvc.GetStepInfo(param.StepId)
+	stepInfo, err := r.stepSvc.GetStepInfo(param.StepId, query.CourseId, &userId)
 	if err != nil {
 		return &response.GenericError{
 			Err:     err,
@@ -66,6 +79,7 @@ func (r *StepController) GetStepInfo(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param stepId path uint true "Step ID"
+// @Param courseId query uint false "Course ID, required for modules shared by several courses"
 // @Success 200 {object} response.InfoResponse[payload.GetGemsResponse]
 // @Failure 400 {object} response.GenericError
 // @Router /step/gem/{stepId} [get]
@@ -78,13 +92,20 @@ func (r *StepController) GetGemEachStep(c *fiber.Ctx) error {
 			Message: "invalid stepId param",
 		}
 	}
+	query := new(payload.CourseIdQuery)
+	if err := c.QueryParser(query); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid courseId query",
+		}
+	}
 
 	// * login state
 	user := c.Locals("user").(*jwt.Token)
 	claims := user.Claims.(jwt.MapClaims)
 	userId := claims["userId"].(float64)
 
-	totalGems, currentGems, err := r.stepSvc.GetGems(param.StepId, &userId)
+	totalGems, currentGems, err := r.stepSvc.GetGems(param.StepId, query.CourseId, &userId)
 	if err != nil {
 		return &response.GenericError{
 			Err:     err,
@@ -169,7 +190,7 @@ func (r *StepController) CommentOnStep(c *fiber.Ctx) error {
 	claims := user.Claims.(jwt.MapClaims)
 	userId := claims["userId"].(float64)
 
-	if err := r.stepSvc.CreateStpComment(body.StepId, &userId, body.Content); err != nil {
+	if err := r.stepSvc.CreateStpComment(body.StepId, &userId, body.Content, body.ParentId); err != nil {
 		return &response.GenericError{
 			Err:     err,
 			Message: "failed to create stepComment",
@@ -229,6 +250,7 @@ func (r *StepController) UpVoteStepComment(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param stepId path uint true "Step ID"
+// @Param courseId query uint false "Course ID, required for modules shared by several courses"
 // @Success 200 {object} response.InfoResponse[[]payload.StepEvalInfo]
 // @Failure 400 {object} response.GenericError
 // @Router /step/stepEval/{stepId} [get]
@@ -241,12 +263,20 @@ func (r *StepController) GetStepEvaluate(c *fiber.Ctx) error {
 			Message: "invalid stepId param",
 		}
 	}
+	query := new(payload.CourseIdQuery)
+	if err := c.QueryParser(query); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid courseId query",
+		}
+	}
+
 	// * login state
 	user := c.Locals("user").(*jwt.Token)
 	claims := user.Claims.(jwt.MapClaims)
 	userId := claims["userId"].(float64)
 
-	stepEvals, err := r.stepSvc.GetStepEvalInfo(param.StepId, &userId)
+	stepEvals, err := r.stepSvc.GetStepEvalInfo(param.StepId, query.CourseId, &userId)
 	if err != nil {
 		return &response.GenericError{
 			Err:     err,
@@ -292,8 +322,27 @@ func (r *StepController) SubmitStepEval(c *fiber.Ctx) error {
 	claims := user.Claims.(jwt.MapClaims)
 	userId := claims["userId"].(float64)
 
+	// * resolve the course the step is taken in
+	courseId, err := r.stepSvc.ResolveCourseId(body.StepId, body.CourseId)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to resolve course",
+		}
+	}
+
+	// * cohort learners may only submit steps their instructor has unlocked
+	if err := r.stepSvc.EnsureStepUnlocked(body.StepId, courseId, &userId); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "step is locked",
+		}
+	}
+
 	userEval := &payload.CreateUserEvalReq{
 		UserId:     &userId,
+		CourseId:   courseId,
+		StepId:     body.StepId,
 		StepEvalId: body.StepEvalId,
 		Content:    body.Content,
 	}
@@ -327,7 +376,7 @@ func (r *StepController) SubmitStepEval(c *fiber.Ctx) error {
 		}
 
 		// * Generate filename
-		filename, err := r.stepSvc.CreateFileFormat(body.StepId, body.StepEvalId, &userId)
+		filename, err := r.stepSvc.CreateFileFormat(courseId, body.StepId, body.StepEvalId, &userId)
 		if err != nil {
 			return &response.GenericError{
 				Err:     err,
@@ -418,6 +467,58 @@ func (r *StepController) CheckStepEvalStatus(c *fiber.Ctx) error {
 	return response.Ok(c, userEval)
 }
 
+// GradeUserEval
+// @ID gradeUserEval
+// @Tags instructor
+// @Summary Grade a submission, the learner is notified on the event stream
+// @Accept json
+// @Produce json
+// @Param userEvalId path uint64 true "User eval ID"
+// @Param q body payload.GradeUserEval true "GradeUserEval"
+// @Success 200 {object} response.InfoResponse[payload.UserEvalResult]
+// @Failure 400 {object} response.GenericError
+// @Router /instructor/evaluations/{userEvalId}/grade [post]
+func (r *StepController) GradeUserEval(c *fiber.Ctx) error {
+	param := new(payload.UserEvalIdParam)
+	if err := c.ParamsParser(param); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid userEvalId parameter",
+		}
+	}
+
+	body := new(payload.GradeUserEval)
+	if err := c.BodyParser(body); err != nil {
+		return &response.GenericError{
+			Err: err,
+		}
+	}
+
+	// * validate body
+	if err := utils.Validate.Struct(body); err != nil {
+		var validationErrors validator.ValidationErrors
+		errors.As(err, &validationErrors)
+		return &response.GenericError{
+			Err: validationErrors,
+		}
+	}
+
+	// * login state
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["userId"].(float64)
+
+	result, err := r.stepSvc.GradeUserEval(param.UserEvalId, uint64(userId), body)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to grade user eval",
+		}
+	}
+
+	return response.Ok(c, result)
+}
+
 // SubmitStepEvalTypCheck
 // @ID submitStepEvalTypCheck
 // @Tags step
@@ -452,7 +553,7 @@ func (r *StepController) SubmitStepEvalTypCheck(c *fiber.Ctx) error {
 	claims := user.Claims.(jwt.MapClaims)
 	userId := claims["userId"].(float64)
 
-	userEvalId, err := r.stepSvc.SubmitStepEvalTypeCheck(body.StepEvalId, utils.Ptr(uint64(userId)))
+	userEvalId, err := r.stepSvc.SubmitStepEvalTypeCheck(body.StepEvalId, body.CourseId, utils.Ptr(uint64(userId)))
 	if err != nil {
 		return &response.GenericError{
 			Err:     err,
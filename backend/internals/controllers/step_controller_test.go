@@ -64,6 +64,8 @@ func setupTestStepController(mockStepService *mockServices.StepService, mockMini
 	stepComment.Post("/create", stepController.CommentOnStep)
 	stepComment.Post("/upvote", stepController.UpVoteStepComment)
 	stepComment.Get("/:stepId", stepController.GetStepComment)
+
+	app.Post("/instructor/evaluations/:userEvalId/grade", stepController.GradeUserEval)
 	return app
 }
 func (suite *StepControllerTestSuit) TestGetStepInfoWhenSuccess() {
@@ -108,7 +110,7 @@ func (suite *StepControllerTestSuit) TestGetStepInfoWhenSuccess() {
 		},
 	}
 
-	mockStepService.EXPECT().GetStepInfo(mock.Anything).Return(&payload.StepInfo{
+	mockStepService.EXPECT().GetStepInfo(mock.Anything, mock.Anything, mock.Anything).Return(&payload.StepInfo{
 		Step:       mockStepDetail,
 		Authors:    mockAuthors,
 		UserPassed: mockUserPassed,
@@ -154,7 +156,7 @@ func (suite *StepControllerTestSuit) TestGetStepInfoWhenFailedToGetStepInfo() {
 
 	mockStepId := utils.Ptr(uint64(2))
 
-	mockStepService.EXPECT().GetStepInfo(mock.Anything).Return(nil, fmt.Errorf("failed to get stepInfo"))
+	mockStepService.EXPECT().GetStepInfo(mock.Anything, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("failed to get stepInfo"))
 
 	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/step/%d", mockStepId), nil)
 	res, err := app.Test(req)
@@ -180,7 +182,7 @@ func (suite *StepControllerTestSuit) TestGetGemWhenSuccess() {
 	mockTotalGem := utils.Ptr(5)
 	mockCurrentGem := utils.Ptr(2)
 
-	mockStepService.EXPECT().GetGems(mock.Anything, mock.Anything).Return(mockTotalGem, mockCurrentGem, nil)
+	mockStepService.EXPECT().GetGems(mock.Anything, mock.Anything, mock.Anything).Return(mockTotalGem, mockCurrentGem, nil)
 
 	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/step/gem/%d", mockStepId), nil)
 	res, err := app.Test(req)
@@ -224,7 +226,7 @@ func (suite *StepControllerTestSuit) TestGetGemWhenFailedToGetGem() {
 
 	mockStepId := utils.Ptr(uint64(2))
 
-	mockStepService.EXPECT().GetGems(mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("failed to get gem"))
+	mockStepService.EXPECT().GetGems(mock.Anything, mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("failed to get gem"))
 
 	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/step/gem/%d", mockStepId), nil)
 	res, err := app.Test(req)
@@ -326,7 +328,7 @@ func (suite *StepControllerTestSuit) TestCommentOnStepWhenSuccess() {
 		Content: utils.Ptr("content"),
 	}
 
-	mockStepService.EXPECT().CreateStpComment(mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockStepService.EXPECT().CreateStpComment(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	jsonBody, _ := json.Marshal(mockBodyReq)
 	req := httptest.NewRequest(http.MethodPost, "/step/comment/create", strings.NewReader(string(jsonBody)))
@@ -376,7 +378,7 @@ func (suite *StepControllerTestSuit) TestCommentOnStepWhenValidationFailed() {
 		StepId: utils.Ptr(uint64(2)),
 	}
 
-	mockStepService.EXPECT().CreateStpComment(mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockStepService.EXPECT().CreateStpComment(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	jsonBody, _ := json.Marshal(mockBodyReq)
 	req := httptest.NewRequest(http.MethodPost, "/step/comment/create", strings.NewReader(string(jsonBody)))
@@ -405,7 +407,7 @@ func (suite *StepControllerTestSuit) TestCommentOnStepWhenFailedToCreateStepComm
 		Content: utils.Ptr("content"),
 	}
 
-	mockStepService.EXPECT().CreateStpComment(mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("failed to createStepComment"))
+	mockStepService.EXPECT().CreateStpComment(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("failed to createStepComment"))
 
 	jsonBody, _ := json.Marshal(mockBodyReq)
 	req := httptest.NewRequest(http.MethodPost, "/step/comment/create", strings.NewReader(string(jsonBody)))
@@ -543,7 +545,7 @@ func (suite *StepControllerTestSuit) TestGetStepEvalWhenSuccess() {
 		},
 	}
 
-	mockStepService.EXPECT().GetStepEvalInfo(mock.Anything, mock.Anything).Return(mockStepEvals, nil)
+	mockStepService.EXPECT().GetStepEvalInfo(mock.Anything, mock.Anything, mock.Anything).Return(mockStepEvals, nil)
 
 	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/step/stepEval/%d", mockStepId), nil)
 	res, err := app.Test(req)
@@ -587,7 +589,7 @@ func (suite *StepControllerTestSuit) TestGetStepEvalWhenFailedToGetStepEval() {
 
 	mockStepId := utils.Ptr(uint64(3))
 
-	mockStepService.EXPECT().GetStepEvalInfo(mock.Anything, mock.Anything).Return(nil, fmt.Errorf("failed to get stepEval"))
+	mockStepService.EXPECT().GetStepEvalInfo(mock.Anything, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("failed to get stepEval"))
 
 	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/step/stepEval/%d", mockStepId), nil)
 	res, err := app.Test(req)
@@ -614,7 +616,7 @@ func (suite *StepControllerTestSuit) TestSubmitStepEvalTypeCheckWhenSuccess() {
 
 	mockUserEvalId := utils.Ptr(uint64(2))
 
-	mockStepService.EXPECT().SubmitStepEvalTypeCheck(mock.Anything, mock.Anything).Return(mockUserEvalId, nil)
+	mockStepService.EXPECT().SubmitStepEvalTypeCheck(mock.Anything, mock.Anything, mock.Anything).Return(mockUserEvalId, nil)
 
 	jsonBody, _ := json.Marshal(mockBodyReq)
 	req := httptest.NewRequest(http.MethodPost, "/step/stepEval/submit-type-check", strings.NewReader(string(jsonBody)))
@@ -688,7 +690,7 @@ func (suite *StepControllerTestSuit) TestSubmitStepEvalTypeCheckWhenFailedToSubm
 		StepEvalId: utils.Ptr(uint64(3)),
 	}
 
-	mockStepService.EXPECT().SubmitStepEvalTypeCheck(mock.Anything, mock.Anything).Return(nil, fmt.Errorf("failed to submit "))
+	mockStepService.EXPECT().SubmitStepEvalTypeCheck(mock.Anything, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("failed to submit "))
 
 	jsonBody, _ := json.Marshal(mockBodyReq)
 	req := httptest.NewRequest(http.MethodPost, "/step/stepEval/submit-type-check", strings.NewReader(string(jsonBody)))
@@ -802,6 +804,9 @@ func (suite *StepControllerTestSuit) TestSubmitStepEvalTypeTextWhenSuccess() {
 
 	app := setupTestStepController(mockStepService, mockMinioService)
 
+	mockStepService.EXPECT().ResolveCourseId(mock.Anything, mock.Anything).Return(utils.Ptr(uint64(1)), nil)
+	mockStepService.EXPECT().EnsureStepUnlocked(mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
 	mockUserEvalId := utils.Ptr(uint64(1))
 
 	mockStepService.EXPECT().CreateUserEval(mock.Anything).Return(mockUserEvalId, nil)
@@ -879,6 +884,9 @@ func (suite *StepControllerTestSuit) TestSubmitStepEvalWhenFailedToCreateUserEva
 
 	app := setupTestStepController(mockStepService, mockMinioService)
 
+	mockStepService.EXPECT().ResolveCourseId(mock.Anything, mock.Anything).Return(utils.Ptr(uint64(1)), nil)
+	mockStepService.EXPECT().EnsureStepUnlocked(mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
 	mockStepService.EXPECT().CreateUserEval(mock.Anything).Return(nil, fmt.Errorf("failed to creat userEval"))
 
 	formData := "data={\"stepId\":1, \"stepEvalId\":123, \"content\": \"Valid content\"}"
@@ -903,10 +911,13 @@ func (suite *StepControllerTestSuit) TestSubmitStepEvalTypeImageWhenSuccess() {
 
 	app := setupTestStepController(mockStepService, mockMinioService)
 
+	mockStepService.EXPECT().ResolveCourseId(mock.Anything, mock.Anything).Return(utils.Ptr(uint64(1)), nil)
+	mockStepService.EXPECT().EnsureStepUnlocked(mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
 	mockUserEvalId := utils.Ptr(uint64(1))
 	mockFileName := utils.Ptr("file.png")
 
-	mockStepService.EXPECT().CreateFileFormat(mock.Anything, mock.Anything, mock.Anything).Return(mockFileName, nil)
+	mockStepService.EXPECT().CreateFileFormat(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockFileName, nil)
 	mockStepService.EXPECT().CreateUserEval(mock.Anything).Return(mockUserEvalId, nil)
 	mockMinioService.EXPECT().PutObject(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
@@ -950,6 +961,9 @@ func (suite *StepControllerTestSuit) TestSubmitStepEvalTypeImageWhenFileNotFound
 
 	app := setupTestStepController(mockStepService, mockMinioService)
 
+	mockStepService.EXPECT().ResolveCourseId(mock.Anything, mock.Anything).Return(utils.Ptr(uint64(1)), nil)
+	mockStepService.EXPECT().EnsureStepUnlocked(mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
 	// Prepare the form with the JSON data and file
 	formData := new(bytes.Buffer)
 	writer := multipart.NewWriter(formData)
@@ -991,7 +1005,10 @@ func (suite *StepControllerTestSuit) TestSubmitStepEvalTypeImageWhenFailedToCrea
 
 	app := setupTestStepController(mockStepService, mockMinioService)
 
-	mockStepService.EXPECT().CreateFileFormat(mock.Anything, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("failed to createFileFormat"))
+	mockStepService.EXPECT().ResolveCourseId(mock.Anything, mock.Anything).Return(utils.Ptr(uint64(1)), nil)
+	mockStepService.EXPECT().EnsureStepUnlocked(mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	mockStepService.EXPECT().CreateFileFormat(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("failed to createFileFormat"))
 
 	// Prepare the form with the JSON data and file
 	formData := new(bytes.Buffer)
@@ -1034,9 +1051,12 @@ func (suite *StepControllerTestSuit) TestSubmitStepEvalTypeImageWhenFailedToPutO
 
 	app := setupTestStepController(mockStepService, mockMinioService)
 
+	mockStepService.EXPECT().ResolveCourseId(mock.Anything, mock.Anything).Return(utils.Ptr(uint64(1)), nil)
+	mockStepService.EXPECT().EnsureStepUnlocked(mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
 	mockFileName := utils.Ptr("file.png")
 
-	mockStepService.EXPECT().CreateFileFormat(mock.Anything, mock.Anything, mock.Anything).Return(mockFileName, nil)
+	mockStepService.EXPECT().CreateFileFormat(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(mockFileName, nil)
 	mockMinioService.EXPECT().PutObject(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("failed to put object"))
 
 	// Prepare the form with the JSON data and file
@@ -1072,6 +1092,100 @@ func (suite *StepControllerTestSuit) TestSubmitStepEvalTypeImageWhenFailedToPutO
 	is.Equal("failed to upload file", r.Message)
 }
 
+func (suite *StepControllerTestSuit) TestSubmitStepEvalWhenFailedToResolveCourse() {
+	is := assert.New(suite.T())
+
+	mockStepService := new(mockServices.StepService)
+	mockMinioService := new(mockUtilServices.MinioService)
+
+	app := setupTestStepController(mockStepService, mockMinioService)
+
+	mockStepService.EXPECT().ResolveCourseId(mock.Anything, mock.Anything).Return(nil, fmt.Errorf("module 2 is shared by several courses, courseId is required"))
+
+	formData := "data={\"stepId\":1, \"stepEvalId\":123, \"content\": \"Valid content\"}"
+	req := httptest.NewRequest(fiber.MethodPost, "/step/stepEval/submit", strings.NewReader(formData))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	res, err := app.Test(req)
+
+	body, _ := io.ReadAll(res.Body)
+	var r response.GenericError
+	json.Unmarshal(body, &r)
+
+	is.Nil(err)
+	is.Equal(http.StatusInternalServerError, res.StatusCode)
+	is.Equal("failed to resolve course", r.Message)
+	mockStepService.AssertNotCalled(suite.T(), "CreateUserEval", mock.Anything)
+}
+
+func (suite *StepControllerTestSuit) TestSubmitStepEvalWhenStepLocked() {
+	is := assert.New(suite.T())
+
+	mockStepService := new(mockServices.StepService)
+	mockMinioService := new(mockUtilServices.MinioService)
+
+	app := setupTestStepController(mockStepService, mockMinioService)
+
+	mockStepService.EXPECT().ResolveCourseId(mock.Anything, mock.Anything).Return(utils.Ptr(uint64(1)), nil)
+	mockStepService.EXPECT().EnsureStepUnlocked(mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("step 1 is locked until the cohort instructor unlocks it"))
+
+	formData := "data={\"stepId\":1, \"stepEvalId\":123, \"content\": \"Valid content\"}"
+	req := httptest.NewRequest(fiber.MethodPost, "/step/stepEval/submit", strings.NewReader(formData))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	res, err := app.Test(req)
+
+	body, _ := io.ReadAll(res.Body)
+	var r response.GenericError
+	json.Unmarshal(body, &r)
+
+	is.Nil(err)
+	is.Equal(http.StatusInternalServerError, res.StatusCode)
+	is.Equal("step is locked", r.Message)
+	mockStepService.AssertNotCalled(suite.T(), "CreateUserEval", mock.Anything)
+}
+
+func (suite *StepControllerTestSuit) TestGradeUserEvalWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockStepService := new(mockServices.StepService)
+	mockMinioService := new(mockUtilServices.MinioService)
+
+	app := setupTestStepController(mockStepService, mockMinioService)
+
+	mockStepService.EXPECT().GradeUserEval(utils.Ptr(uint64(4)), uint64(123), &payload.GradeUserEval{
+		Pass:    utils.Ptr(false),
+		Comment: utils.Ptr("the LED is not wired"),
+	}).Return(&payload.UserEvalResult{UserEvalId: utils.Ptr(uint64(4)), Pass: utils.Ptr(false)}, nil)
+
+	req := httptest.NewRequest(fiber.MethodPost, "/instructor/evaluations/4/grade", strings.NewReader(`{"pass":false,"comment":"the LED is not wired"}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+
+	var r response.InfoResponse[payload.UserEvalResult]
+	body, _ := io.ReadAll(res.Body)
+	json.Unmarshal(body, &r)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.False(*r.Data.Pass)
+}
+
+func (suite *StepControllerTestSuit) TestGradeUserEvalWhenValidationFailed() {
+	is := assert.New(suite.T())
+
+	mockStepService := new(mockServices.StepService)
+	mockMinioService := new(mockUtilServices.MinioService)
+
+	app := setupTestStepController(mockStepService, mockMinioService)
+
+	req := httptest.NewRequest(fiber.MethodPost, "/instructor/evaluations/4/grade", strings.NewReader(`{"comment":"missing result"}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusBadRequest, res.StatusCode)
+	mockStepService.AssertNotCalled(suite.T(), "GradeUserEval", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestStepController(t *testing.T) {
 	suite.Run(t, new(StepControllerTestSuit))
 }
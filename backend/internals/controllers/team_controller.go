@@ -0,0 +1,184 @@
+package controllers
+
+import (
+	"backend/internals/entities/payload"
+	"backend/internals/entities/response"
+	"backend/internals/services"
+	"backend/internals/utils"
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type TeamController struct {
+	teamSvc services.TeamService
+}
+
+func NewTeamController(teamSvc services.TeamService) *TeamController {
+	return &TeamController{
+		teamSvc: teamSvc,
+	}
+}
+
+// CreateTeam
+// @ID createTeam
+// @Tags instructor
+// @Summary Create a team of a course, optionally for a workshop session
+// @Accept json
+// @Produce json
+// @Param q body payload.CreateTeam true "CreateTeam"
+// @Success 200 {object} response.InfoResponse[payload.TeamInfo]
+// @Failure 400 {object} response.GenericError
+// @Router /instructor/teams [post]
+func (r *TeamController) CreateTeam(c *fiber.Ctx) error {
+	body := new(payload.CreateTeam)
+	if err := c.BodyParser(body); err != nil {
+		return &response.GenericError{
+			Err: err,
+		}
+	}
+
+	// * validate body
+	if err := utils.Validate.Struct(body); err != nil {
+		var validationErrors validator.ValidationErrors
+		errors.As(err, &validationErrors)
+		return &response.GenericError{
+			Err: validationErrors,
+		}
+	}
+
+	team, err := r.teamSvc.CreateTeam(body)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to create team",
+		}
+	}
+
+	return response.Ok(c, team)
+}
+
+// AddMember
+// @ID addTeamMember
+// @Tags instructor
+// @Summary Add a learner to a team
+// @Accept json
+// @Produce json
+// @Param teamId path uint64 true "Team ID"
+// @Param q body payload.AddTeamMember true "AddTeamMember"
+// @Success 200 {object} response.InfoResponse[payload.TeamInfo]
+// @Failure 400 {object} response.GenericError
+// @Router /instructor/teams/{teamId}/members [post]
+func (r *TeamController) AddMember(c *fiber.Ctx) error {
+	param := new(payload.TeamIdParam)
+	if err := c.ParamsParser(param); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid teamId parameter",
+		}
+	}
+
+	body := new(payload.AddTeamMember)
+	if err := c.BodyParser(body); err != nil {
+		return &response.GenericError{
+			Err: err,
+		}
+	}
+
+	// * validate body
+	if err := utils.Validate.Struct(body); err != nil {
+		var validationErrors validator.ValidationErrors
+		errors.As(err, &validationErrors)
+		return &response.GenericError{
+			Err: validationErrors,
+		}
+	}
+
+	team, err := r.teamSvc.AddMember(*param.TeamId, body)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to add team member",
+		}
+	}
+
+	return response.Ok(c, team)
+}
+
+// GetTeam
+// @ID getTeam
+// @Tags instructor
+// @Summary Get a team and its members
+// @Accept json
+// @Produce json
+// @Param teamId path uint64 true "Team ID"
+// @Success 200 {object} response.InfoResponse[payload.TeamInfo]
+// @Failure 400 {object} response.GenericError
+// @Router /instructor/teams/{teamId} [get]
+func (r *TeamController) GetTeam(c *fiber.Ctx) error {
+	param := new(payload.TeamIdParam)
+	if err := c.ParamsParser(param); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid teamId parameter",
+		}
+	}
+
+	team, err := r.teamSvc.GetTeam(*param.TeamId)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to get team",
+		}
+	}
+
+	return response.Ok(c, team)
+}
+
+// SetTeamEligible
+// @ID setTeamEligible
+// @Tags instructor
+// @Summary Mark a step evaluation as one a team submits together
+// @Accept json
+// @Produce json
+// @Param stepEvalId path uint64 true "Step evaluation ID"
+// @Param q body payload.TeamEligibleBody true "TeamEligibleBody"
+// @Success 200 {object} response.InfoResponse[payload.StepEvalTeamEligible]
+// @Failure 400 {object} response.GenericError
+// @Router /instructor/stepEvals/{stepEvalId}/team [put]
+func (r *TeamController) SetTeamEligible(c *fiber.Ctx) error {
+	param := new(payload.StepEvalIdParam)
+	if err := c.ParamsParser(param); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid stepEvalId parameter",
+		}
+	}
+
+	body := new(payload.TeamEligibleBody)
+	if err := c.BodyParser(body); err != nil {
+		return &response.GenericError{
+			Err: err,
+		}
+	}
+
+	// * validate body
+	if err := utils.Validate.Struct(body); err != nil {
+		var validationErrors validator.ValidationErrors
+		errors.As(err, &validationErrors)
+		return &response.GenericError{
+			Err: validationErrors,
+		}
+	}
+
+	stepEval, err := r.teamSvc.SetTeamEligible(*param.StepEvalId, body)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to update step evaluation",
+		}
+	}
+
+	return response.Ok(c, stepEval)
+}
@@ -0,0 +1,90 @@
+package controllers_test
+
+import (
+	"backend/internals/controllers"
+	"backend/internals/entities/payload"
+	"backend/internals/routes/handler"
+	"backend/internals/utils"
+	mockServices "backend/mocks/services"
+	"bytes"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type TeamControllerTestSuite struct {
+	suite.Suite
+}
+
+func setupTestTeamController(mockTeamService *mockServices.TeamService) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: handler.ErrorHandler,
+	})
+
+	controller := controllers.NewTeamController(mockTeamService)
+
+	app.Post("/instructor/teams", controller.CreateTeam)
+	app.Get("/instructor/teams/:teamId", controller.GetTeam)
+	app.Post("/instructor/teams/:teamId/members", controller.AddMember)
+	app.Put("/instructor/stepEvals/:stepEvalId/team", controller.SetTeamEligible)
+	return app
+}
+
+func (suite *TeamControllerTestSuite) TestCreateTeamWhenValidationFailed() {
+	is := assert.New(suite.T())
+
+	mockTeamService := new(mockServices.TeamService)
+	app := setupTestTeamController(mockTeamService)
+
+	req := httptest.NewRequest(http.MethodPost, "/instructor/teams", bytes.NewBufferString(`{"courseId":7}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusBadRequest, res.StatusCode)
+	mockTeamService.AssertNotCalled(suite.T(), "CreateTeam", mock.Anything)
+}
+
+func (suite *TeamControllerTestSuite) TestAddMemberWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockTeamService := new(mockServices.TeamService)
+	app := setupTestTeamController(mockTeamService)
+
+	mockTeamService.EXPECT().AddMember(uint64(6), &payload.AddTeamMember{UserId: utils.Ptr(uint64(9))}).Return(&payload.TeamInfo{
+		TeamId: utils.Ptr(uint64(6)),
+		Name:   utils.Ptr("Team A"),
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/instructor/teams/6/members", bytes.NewBufferString(`{"userId":9}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+}
+
+func (suite *TeamControllerTestSuite) TestSetTeamEligibleWhenFailed() {
+	is := assert.New(suite.T())
+
+	mockTeamService := new(mockServices.TeamService)
+	app := setupTestTeamController(mockTeamService)
+
+	mockTeamService.EXPECT().SetTeamEligible(uint64(12), &payload.TeamEligibleBody{TeamEligible: utils.Ptr(true)}).Return(nil, fmt.Errorf("record not found"))
+
+	req := httptest.NewRequest(http.MethodPut, "/instructor/stepEvals/12/team", bytes.NewBufferString(`{"teamEligible":true}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusInternalServerError, res.StatusCode)
+}
+
+func TestTeamController(t *testing.T) {
+	suite.Run(t, new(TeamControllerTestSuite))
+}
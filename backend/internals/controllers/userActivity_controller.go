@@ -1,8 +1,8 @@
 package controllers
 
 import (
-	"backend/internals/entities/response"
 	"backend/internals/entities/payload"
+	"backend/internals/entities/response"
 	"backend/internals/services"
 	"backend/internals/utils"
 	"github.com/gofiber/fiber/v2"
@@ -52,6 +52,7 @@ func (r *UserActivityController) GetRecentActivity(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param stepId path uint64 true "Step ID" example(123)
+// @Param courseId query uint false "Course ID, required for modules shared by several courses"
 // @Success 200 {object} response.InfoResponse[string]
 // @Failure 400 {object} response.GenericError
 // @Failure 500 {object} response.GenericError
@@ -66,11 +67,19 @@ func (c *UserActivityController) CreateOrUpdateActivity(ctx *fiber.Ctx) error {
 		}
 	}
 
+	query := new(payload.CourseIdQuery)
+	if err := ctx.QueryParser(query); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid courseId query",
+		}
+	}
+
 	user := ctx.Locals("user").(*jwt.Token)
 	claims := user.Claims.(jwt.MapClaims)
 	userId := claims["userId"].(float64)
 
-	err := c.userActivitySvc.UpdateUserActivity(uint64(userId), param.StepId)
+	err := c.userActivitySvc.UpdateUserActivity(uint64(userId), param.StepId, query.CourseId)
 	if err != nil {
 		return &response.GenericError{
 			Err:     err,
@@ -80,3 +89,47 @@ func (c *UserActivityController) CreateOrUpdateActivity(ctx *fiber.Ctx) error {
 
 	return response.Ok(ctx, "user activity updated successfully")
 }
+
+// Heartbeat
+// @Summary Record that the user is on a step
+// @Description Sent every 30 seconds while the step is open. Pauses over 5 minutes start a new session and idle time is not counted.
+// @Tags UserActivity
+// @Accept json
+// @Produce json
+// @Param stepId path uint64 true "Step ID" example(123)
+// @Param body body payload.Heartbeat true "Heartbeat"
+// @Success 200 {object} response.InfoResponse[payload.StepSessionInfo]
+// @Failure 400 {object} response.GenericError
+// @Failure 500 {object} response.GenericError
+// @Router /user/activity/{stepId}/heartbeat [post]
+func (c *UserActivityController) Heartbeat(ctx *fiber.Ctx) error {
+	param := new(payload.UserActivityParam)
+	if err := ctx.ParamsParser(param); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid stepId parameter",
+		}
+	}
+
+	body := new(payload.Heartbeat)
+	if err := ctx.BodyParser(body); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid request body",
+		}
+	}
+
+	user := ctx.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["userId"].(float64)
+
+	session, err := c.userActivitySvc.RecordHeartbeat(uint64(userId), param.StepId, body)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to record heartbeat",
+		}
+	}
+
+	return response.Ok(ctx, session)
+}
@@ -0,0 +1,88 @@
+package controllers_test
+
+import (
+	"backend/internals/controllers"
+	"backend/internals/entities/payload"
+	"backend/internals/entities/response"
+	"backend/internals/routes/handler"
+	"backend/internals/utils"
+	mockServices "backend/mocks/services"
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type UserActivityControllerTestSuite struct {
+	suite.Suite
+}
+
+func setupTestUserActivityController(mockUserActivityService *mockServices.UserActivityService) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: handler.ErrorHandler,
+	})
+
+	controller := controllers.NewUserActivityController(mockUserActivityService)
+
+	// Middleware to simulate JWT Locals
+	app.Use(func(c *fiber.Ctx) error {
+		token := jwt.New(jwt.SigningMethodHS256)
+		claims := token.Claims.(jwt.MapClaims)
+		claims["userId"] = float64(123)
+		c.Locals("user", token)
+		return c.Next()
+	})
+
+	app.Post("/user/activity/:stepId/heartbeat", controller.Heartbeat)
+	return app
+}
+
+func (suite *UserActivityControllerTestSuite) TestHeartbeatWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockUserActivityService := new(mockServices.UserActivityService)
+	app := setupTestUserActivityController(mockUserActivityService)
+
+	mockUserActivityService.EXPECT().RecordHeartbeat(uint64(123), uint64(8), &payload.Heartbeat{CourseId: utils.Ptr(uint64(3)), Idle: utils.Ptr(false)}).
+		Return(&payload.StepSessionInfo{SessionId: utils.Ptr(uint64(1)), ActiveSeconds: utils.Ptr(int64(30))}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/user/activity/8/heartbeat", strings.NewReader(`{"courseId":3,"idle":false}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+
+	var responsePayload response.InfoResponse[payload.StepSessionInfo]
+	resBody, _ := io.ReadAll(res.Body)
+	json.Unmarshal(resBody, &responsePayload)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Equal(int64(30), *responsePayload.Data.ActiveSeconds)
+}
+
+func (suite *UserActivityControllerTestSuite) TestHeartbeatWhenFailed() {
+	is := assert.New(suite.T())
+
+	mockUserActivityService := new(mockServices.UserActivityService)
+	app := setupTestUserActivityController(mockUserActivityService)
+
+	mockUserActivityService.EXPECT().RecordHeartbeat(uint64(123), uint64(8), &payload.Heartbeat{}).
+		Return(nil, fmt.Errorf("module 5 is shared by several courses, courseId is required"))
+
+	req := httptest.NewRequest(http.MethodPost, "/user/activity/8/heartbeat", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusInternalServerError, res.StatusCode)
+}
+
+func TestUserActivityController(t *testing.T) {
+	suite.Run(t, new(UserActivityControllerTestSuite))
+}
@@ -1,9 +1,13 @@
 package controllers
 
 import (
+	"backend/internals/entities/payload"
 	"backend/internals/entities/response"
 	"backend/internals/services"
+	"backend/internals/utils"
+	"errors"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
 )
@@ -43,6 +47,50 @@ func (c *UserStrengthController) GetStrengthDataByUserID(ctx *fiber.Ctx) error {
 	return response.Ok(ctx, strengthData)
 }
 
+// GetStrengthRadar
+// @ID getStrengthRadar
+// @Tags user-strength
+// @Summary Get the strength of the user in every field week after week, with the weakest fields
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param weeks query int false "Weeks of history, the current one included"
+// @Success 200 {object} response.InfoResponse[payload.StrengthRadar]
+// @Failure 400 {object} response.GenericError
+// @Router /strength/radar [get]
+func (c *UserStrengthController) GetStrengthRadar(ctx *fiber.Ctx) error {
+	user := ctx.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["userId"].(float64)
+
+	query := new(payload.StrengthRadarQuery)
+	if err := ctx.QueryParser(query); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid query",
+		}
+	}
+
+	// * validate query
+	if err := utils.Validate.Struct(query); err != nil {
+		var validationErrors validator.ValidationErrors
+		errors.As(err, &validationErrors)
+		return &response.GenericError{
+			Err: validationErrors,
+		}
+	}
+
+	radar, err := c.userStrengthSvc.GetStrengthRadar(uint64(userId), query)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "Failed to fetch strength radar",
+		}
+	}
+
+	return response.Ok(ctx, radar)
+}
+
 // GetSuggestionCourse
 // @ID getSuggestionCourse
 // @Tags user-strength
@@ -0,0 +1,94 @@
+package controllers_test
+
+import (
+	"backend/internals/controllers"
+	"backend/internals/entities/payload"
+	"backend/internals/entities/response"
+	"backend/internals/routes/handler"
+	"backend/internals/utils"
+	mockServices "backend/mocks/services"
+	"encoding/json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type UserStrengthControllerTestSuite struct {
+	suite.Suite
+}
+
+func setupTestUserStrengthController(mockUserStrengthService *mockServices.UserStrengthService) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: handler.ErrorHandler,
+	})
+
+	controller := controllers.NewUserStrengthController(mockUserStrengthService)
+
+	// Middleware to simulate JWT Locals
+	app.Use(func(c *fiber.Ctx) error {
+		token := jwt.New(jwt.SigningMethodHS256)
+		claims := token.Claims.(jwt.MapClaims)
+		claims["userId"] = float64(123)
+		c.Locals("user", token)
+		return c.Next()
+	})
+
+	app.Get("/strength/radar", controller.GetStrengthRadar)
+	return app
+}
+
+func (suite *UserStrengthControllerTestSuite) TestGetStrengthRadarWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockUserStrengthService := new(mockServices.UserStrengthService)
+	app := setupTestUserStrengthController(mockUserStrengthService)
+
+	mockUserStrengthService.EXPECT().GetStrengthRadar(uint64(123), &payload.StrengthRadarQuery{Weeks: utils.Ptr(8)}).Return(&payload.StrengthRadar{
+		Weeks: []*payload.StrengthWeek{
+			{Fields: []payload.StrengthFieldData{{FieldId: utils.Ptr(uint64(1)), FieldName: "Electronics", Strength: 25}}},
+		},
+		Weakest: []*payload.WeakField{
+			{
+				StrengthFieldData: payload.StrengthFieldData{FieldId: utils.Ptr(uint64(1)), FieldName: "Electronics", Strength: 25},
+				Steps:             []*payload.StrengthStep{{StepId: utils.Ptr(uint64(4)), Gems: 5}},
+			},
+		},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/strength/radar?weeks=8", nil)
+	res, err := app.Test(req)
+
+	var responsePayload response.InfoResponse[payload.StrengthRadar]
+	resBody, _ := io.ReadAll(res.Body)
+	json.Unmarshal(resBody, &responsePayload)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Equal(25.0, responsePayload.Data.Weeks[0].Fields[0].Strength)
+	is.Equal("Electronics", responsePayload.Data.Weakest[0].FieldName)
+	is.Equal(uint64(4), *responsePayload.Data.Weakest[0].Steps[0].StepId)
+}
+
+func (suite *UserStrengthControllerTestSuite) TestGetStrengthRadarWhenTooManyWeeks() {
+	is := assert.New(suite.T())
+
+	mockUserStrengthService := new(mockServices.UserStrengthService)
+	app := setupTestUserStrengthController(mockUserStrengthService)
+
+	req := httptest.NewRequest(http.MethodGet, "/strength/radar?weeks=60", nil)
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusBadRequest, res.StatusCode)
+	mockUserStrengthService.AssertNotCalled(suite.T(), "GetStrengthRadar", mock.Anything, mock.Anything)
+}
+
+func TestUserStrengthController(t *testing.T) {
+	suite.Run(t, new(UserStrengthControllerTestSuite))
+}
@@ -0,0 +1,187 @@
+package controllers
+
+import (
+	"backend/internals/entities/payload"
+	"backend/internals/entities/response"
+	"backend/internals/services"
+	"backend/internals/utils"
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type WorkshopSessionController struct {
+	workshopSessionSvc services.WorkshopSessionService
+}
+
+func NewWorkshopSessionController(workshopSessionSvc services.WorkshopSessionService) *WorkshopSessionController {
+	return &WorkshopSessionController{
+		workshopSessionSvc: workshopSessionSvc,
+	}
+}
+
+// GetUpcomingSessions
+// @ID getUpcomingSessions
+// @Tags session
+// @Summary List upcoming workshop sessions of a course or of the courses of a field type
+// @Accept json
+// @Produce json
+// @Param courseId query uint64 false "Course ID"
+// @Param fieldId query uint64 false "Field type ID"
+// @Success 200 {object} response.InfoResponse[[]payload.WorkshopSessionInfo]
+// @Failure 400 {object} response.GenericError
+// @Router /sessions [get]
+func (r *WorkshopSessionController) GetUpcomingSessions(c *fiber.Ctx) error {
+	query := new(payload.WorkshopSessionQuery)
+	if err := c.QueryParser(query); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid query",
+		}
+	}
+
+	sessions, err := r.workshopSessionSvc.GetUpcomingSessions(query.CourseId, query.FieldId)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to get upcoming sessions",
+		}
+	}
+
+	return response.Ok(c, sessions)
+}
+
+// Register
+// @ID registerSession
+// @Tags session
+// @Summary Register for a workshop session, joining the waitlist when it is full
+// @Accept json
+// @Produce json
+// @Param sessionId path uint64 true "Session ID"
+// @Success 200 {object} response.InfoResponse[payload.SessionRegistrationInfo]
+// @Failure 400 {object} response.GenericError
+// @Router /sessions/{sessionId}/register [post]
+func (r *WorkshopSessionController) Register(c *fiber.Ctx) error {
+	param := new(payload.SessionIdParam)
+	if err := c.ParamsParser(param); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid sessionId parameter",
+		}
+	}
+
+	// * login state
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["userId"].(float64)
+
+	registration, err := r.workshopSessionSvc.Register(*param.SessionId, uint64(userId))
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to register for session",
+		}
+	}
+
+	return response.Ok(c, registration)
+}
+
+// Cancel
+// @ID cancelSessionRegistration
+// @Tags session
+// @Summary Cancel a workshop session registration, the first waitlisted user takes the seat
+// @Accept json
+// @Produce json
+// @Param sessionId path uint64 true "Session ID"
+// @Success 200 {object} response.InfoResponse[string]
+// @Failure 400 {object} response.GenericError
+// @Router /sessions/{sessionId}/cancel [post]
+func (r *WorkshopSessionController) Cancel(c *fiber.Ctx) error {
+	param := new(payload.SessionIdParam)
+	if err := c.ParamsParser(param); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "invalid sessionId parameter",
+		}
+	}
+
+	// * login state
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["userId"].(float64)
+
+	if err := r.workshopSessionSvc.Cancel(*param.SessionId, uint64(userId)); err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to cancel registration",
+		}
+	}
+
+	return response.Ok(c, "registration cancelled")
+}
+
+// GetMyRegistrations
+// @ID getMySessionRegistrations
+// @Tags session
+// @Summary List the workshop sessions the user is registered or waitlisted for
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.InfoResponse[[]payload.SessionRegistrationInfo]
+// @Failure 400 {object} response.GenericError
+// @Router /sessions/registrations [get]
+func (r *WorkshopSessionController) GetMyRegistrations(c *fiber.Ctx) error {
+	// * login state
+	user := c.Locals("user").(*jwt.Token)
+	claims := user.Claims.(jwt.MapClaims)
+	userId := claims["userId"].(float64)
+
+	registrations, err := r.workshopSessionSvc.GetUserRegistrations(uint64(userId))
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to get registrations",
+		}
+	}
+
+	return response.Ok(c, registrations)
+}
+
+// CreateSession
+// @ID createSession
+// @Tags admin
+// @Summary Schedule a workshop session for a course
+// @Accept json
+// @Produce json
+// @Param q body payload.CreateWorkshopSession true "CreateWorkshopSession"
+// @Success 200 {object} response.InfoResponse[payload.WorkshopSessionInfo]
+// @Failure 400 {object} response.GenericError
+// @Router /admin/sessions [post]
+func (r *WorkshopSessionController) CreateSession(c *fiber.Ctx) error {
+	body := new(payload.CreateWorkshopSession)
+	if err := c.BodyParser(body); err != nil {
+		return &response.GenericError{
+			Err: err,
+		}
+	}
+
+	// * validate body
+	if err := utils.Validate.Struct(body); err != nil {
+		var validationErrors validator.ValidationErrors
+		errors.As(err, &validationErrors)
+		return &response.GenericError{
+			Err: validationErrors,
+		}
+	}
+
+	session, err := r.workshopSessionSvc.CreateSession(body)
+	if err != nil {
+		return &response.GenericError{
+			Err:     err,
+			Message: "failed to create session",
+		}
+	}
+
+	return response.Ok(c, session)
+}
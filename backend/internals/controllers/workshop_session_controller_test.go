@@ -0,0 +1,173 @@
+package controllers_test
+
+import (
+	"backend/internals/controllers"
+	"backend/internals/entities/payload"
+	"backend/internals/entities/response"
+	"backend/internals/routes/handler"
+	"backend/internals/utils"
+	mockServices "backend/mocks/services"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type WorkshopSessionControllerTestSuite struct {
+	suite.Suite
+}
+
+func setupTestWorkshopSessionController(mockWorkshopSessionService *mockServices.WorkshopSessionService) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: handler.ErrorHandler,
+	})
+
+	controller := controllers.NewWorkshopSessionController(mockWorkshopSessionService)
+
+	// Middleware to simulate JWT Locals
+	app.Use(func(c *fiber.Ctx) error {
+		token := jwt.New(jwt.SigningMethodHS256)
+		claims := token.Claims.(jwt.MapClaims)
+		claims["userId"] = float64(123)
+		c.Locals("user", token)
+		return c.Next()
+	})
+
+	app.Get("/sessions", controller.GetUpcomingSessions)
+	app.Get("/sessions/registrations", controller.GetMyRegistrations)
+	app.Post("/sessions/:sessionId/register", controller.Register)
+	app.Post("/sessions/:sessionId/cancel", controller.Cancel)
+	app.Post("/admin/sessions", controller.CreateSession)
+	return app
+}
+
+func (suite *WorkshopSessionControllerTestSuite) TestGetUpcomingSessionsWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockWorkshopSessionService := new(mockServices.WorkshopSessionService)
+	app := setupTestWorkshopSessionController(mockWorkshopSessionService)
+
+	mockWorkshopSessionService.EXPECT().GetUpcomingSessions((*uint64)(nil), utils.Ptr(uint64(2))).Return([]*payload.WorkshopSessionInfo{
+		{SessionId: utils.Ptr(uint64(4)), Title: utils.Ptr("Soldering lab"), SeatsLeft: utils.Ptr(int64(1))},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/sessions?fieldId=2", nil)
+	res, err := app.Test(req)
+
+	var responsePayload response.InfoResponse[[]payload.WorkshopSessionInfo]
+	resBody, _ := io.ReadAll(res.Body)
+	json.Unmarshal(resBody, &responsePayload)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Len(responsePayload.Data, 1)
+	is.Equal(int64(1), *responsePayload.Data[0].SeatsLeft)
+}
+
+func (suite *WorkshopSessionControllerTestSuite) TestRegisterWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockWorkshopSessionService := new(mockServices.WorkshopSessionService)
+	app := setupTestWorkshopSessionController(mockWorkshopSessionService)
+
+	mockWorkshopSessionService.EXPECT().Register(uint64(4), uint64(123)).Return(&payload.SessionRegistrationInfo{
+		RegistrationId: utils.Ptr(uint64(9)),
+		Status:         utils.Ptr("registered"),
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/sessions/4/register", nil)
+	res, err := app.Test(req)
+
+	var responsePayload response.InfoResponse[payload.SessionRegistrationInfo]
+	resBody, _ := io.ReadAll(res.Body)
+	json.Unmarshal(resBody, &responsePayload)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Equal("registered", *responsePayload.Data.Status)
+}
+
+func (suite *WorkshopSessionControllerTestSuite) TestRegisterWhenFailed() {
+	is := assert.New(suite.T())
+
+	mockWorkshopSessionService := new(mockServices.WorkshopSessionService)
+	app := setupTestWorkshopSessionController(mockWorkshopSessionService)
+
+	mockWorkshopSessionService.EXPECT().Register(uint64(4), uint64(123)).Return(nil, fmt.Errorf("session 4 has already started"))
+
+	req := httptest.NewRequest(http.MethodPost, "/sessions/4/register", nil)
+	res, err := app.Test(req)
+
+	r := new(response.GenericError)
+	resBody, _ := io.ReadAll(res.Body)
+	json.Unmarshal(resBody, r)
+
+	is.Nil(err)
+	is.Equal(http.StatusInternalServerError, res.StatusCode)
+	is.Equal("failed to register for session", r.Message)
+}
+
+func (suite *WorkshopSessionControllerTestSuite) TestCancelWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockWorkshopSessionService := new(mockServices.WorkshopSessionService)
+	app := setupTestWorkshopSessionController(mockWorkshopSessionService)
+
+	mockWorkshopSessionService.EXPECT().Cancel(uint64(4), uint64(123)).Return(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/sessions/4/cancel", nil)
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+}
+
+func (suite *WorkshopSessionControllerTestSuite) TestGetMyRegistrationsWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockWorkshopSessionService := new(mockServices.WorkshopSessionService)
+	app := setupTestWorkshopSessionController(mockWorkshopSessionService)
+
+	mockWorkshopSessionService.EXPECT().GetUserRegistrations(uint64(123)).Return([]*payload.SessionRegistrationInfo{
+		{RegistrationId: utils.Ptr(uint64(9)), Status: utils.Ptr("waitlisted"), WaitlistPosition: utils.Ptr(int64(2))},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/sessions/registrations", nil)
+	res, err := app.Test(req)
+
+	var responsePayload response.InfoResponse[[]payload.SessionRegistrationInfo]
+	resBody, _ := io.ReadAll(res.Body)
+	json.Unmarshal(resBody, &responsePayload)
+
+	is.Nil(err)
+	is.Equal(http.StatusOK, res.StatusCode)
+	is.Equal(int64(2), *responsePayload.Data[0].WaitlistPosition)
+}
+
+func (suite *WorkshopSessionControllerTestSuite) TestCreateSessionWhenValidationFailed() {
+	is := assert.New(suite.T())
+
+	mockWorkshopSessionService := new(mockServices.WorkshopSessionService)
+	app := setupTestWorkshopSessionController(mockWorkshopSessionService)
+
+	body := []byte(`{"courseId":7,"title":"Soldering lab","startsAt":"2026-11-01T09:00:00+07:00","endsAt":"2026-11-01T12:00:00+07:00","capacity":0}`)
+	req := httptest.NewRequest(http.MethodPost, "/admin/sessions", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	res, err := app.Test(req)
+
+	is.Nil(err)
+	is.Equal(http.StatusBadRequest, res.StatusCode)
+	mockWorkshopSessionService.AssertNotCalled(suite.T(), "CreateSession", mock.Anything)
+}
+
+func TestWorkshopSessionController(t *testing.T) {
+	suite.Run(t, new(WorkshopSessionControllerTestSuite))
+}
@@ -84,8 +84,135 @@ func Migrate() error {
 		new(models.UserActivity),
 		new(models.UserEvaluate),
 		new(models.UserPass),
+		new(models.ImportJob),
+		new(models.WorkshopSession),
+		new(models.SessionRegistration),
+		new(models.SessionAttendance),
+		new(models.Cohort),
+		new(models.CohortMember),
+		new(models.HelpRequest),
+		new(models.Team),
+		new(models.TeamMember),
+		new(models.Certificate),
+		new(models.BadgeClass),
+		new(models.BadgeAssertion),
+		new(models.CalendarEvent),
+		new(models.CalendarToken),
+		new(models.Notification),
+		new(models.NotificationPreference),
+		new(models.GemEntry),
+		new(models.LeaderboardScore),
+		new(models.LeaderboardPreference),
+		new(models.UserAchievement),
+		new(models.UserDailyActivity),
+		new(models.RewardItem),
+		new(models.Redemption),
+		new(models.StepSession),
+		new(models.XapiStatement),
+		new(models.StrengthSnapshot),
 	); err != nil {
 		return err
 	}
+	if err := UpdateGemEntryChecks(Gorm); err != nil {
+		return err
+	}
+	if err := BackfillCourseIds(Gorm); err != nil {
+		return err
+	}
+	if err := UniqueUserEvaluates(Gorm); err != nil {
+		return err
+	}
+	return CreateViews(Gorm)
+}
+
+// UpdateGemEntryChecks brings the kind and source checks of gem entries up to date with the model.
+// AutoMigrate only writes them when creating the table, so a table created before spends would
+// reject them.
+func UpdateGemEntryChecks(db *gorm.DB) error {
+	err := db.Exec(`ALTER TABLE gem_entries
+		DROP CONSTRAINT IF EXISTS gem_entries_kind_check,
+		DROP CONSTRAINT IF EXISTS gem_entries_source_type_check,
+		ADD CONSTRAINT gem_entries_kind_check CHECK (kind IN ('award', 'revoke', 'adjust', 'spend')),
+		ADD CONSTRAINT gem_entries_source_type_check CHECK (source_type IN ('user_evaluate', 'manual', 'redemption'))`).Error
+	if err != nil {
+		return fmt.Errorf("failed to update gem entry checks: %w", err)
+	}
+	return nil
+}
+
+// BackfillCourseIds attributes evaluations and activities recorded before course context was
+// tracked to the course of their module. Rows of modules shared by several courses stay
+// unattributed and count for every course containing the module. An evaluation already taken in
+// the course is kept over an unattributed one, which would break the uniqueness of evaluations.
+func BackfillCourseIds(db *gorm.DB) error {
+	tables := map[string]struct{ stepId, unattributed string }{
+		"user_evaluates": {
+			stepId: "(SELECT step_id FROM step_evaluates WHERE step_evaluates.id = user_evaluates.step_evaluate_id)",
+			unattributed: `NOT EXISTS (
+				SELECT 1 FROM user_evaluates attributed
+				WHERE attributed.user_id = user_evaluates.user_id AND attributed.step_evaluate_id = user_evaluates.step_evaluate_id
+					AND attributed.course_id IS NOT NULL
+			)`,
+		},
+		"user_activities": {
+			stepId:       "user_activities.step_id",
+			unattributed: "TRUE",
+		},
+	}
+
+	for table, columns := range tables {
+		query := fmt.Sprintf(`UPDATE %[1]s SET course_id = (
+			SELECT MIN(course_contents.course_id) FROM course_contents
+			JOIN steps ON steps.module_id = course_contents.module_id
+			WHERE steps.id = %[2]s
+		)
+		WHERE course_id IS NULL AND (
+			SELECT COUNT(DISTINCT course_contents.course_id) FROM course_contents
+			JOIN steps ON steps.module_id = course_contents.module_id
+			WHERE steps.id = %[2]s
+		) = 1 AND %[3]s`, table, columns.stepId, columns.unattributed)
+		if err := db.Exec(query).Error; err != nil {
+			return fmt.Errorf("failed to backfill course of %s: %w", table, err)
+		}
+	}
 	return nil
 }
+
+// UniqueUserEvaluates keeps one evaluation per user, step evaluation and course, so submitting
+// again updates it instead of adding a row worth the gems of the step once more. Duplicates left
+// by earlier submissions are removed first, keeping the passed one updated last, and the gems
+// the ledger awarded them are revoked.
+func UniqueUserEvaluates(db *gorm.DB) error {
+	duplicates := `SELECT id FROM (
+		SELECT id, ROW_NUMBER() OVER (
+			PARTITION BY user_id, step_evaluate_id, COALESCE(course_id, 0)
+			ORDER BY pass IS TRUE DESC, updated_at DESC, id DESC
+		) AS rank
+		FROM user_evaluates
+	) ranked WHERE rank > 1`
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`INSERT INTO gem_entries (user_id, kind, amount, source_type, source_id, course_id, idempotency_key, reason, created_at)
+			SELECT user_id, 'revoke', -SUM(amount), source_type, source_id, (ARRAY_AGG(course_id ORDER BY id DESC))[1],
+				'user_evaluate:' || source_id || ':duplicate', 'duplicate evaluation', NOW()
+			FROM gem_entries
+			WHERE source_type = 'user_evaluate' AND source_id IN (` + duplicates + `)
+			GROUP BY user_id, source_type, source_id
+			HAVING SUM(amount) > 0
+			ON CONFLICT (idempotency_key) DO NOTHING`).Error
+		if err != nil {
+			return fmt.Errorf("failed to revoke gems of duplicate user evaluations: %w", err)
+		}
+
+		if err := tx.Exec(`DELETE FROM user_evaluates WHERE id IN (` + duplicates + `)`).Error; err != nil {
+			return fmt.Errorf("failed to remove duplicate user evaluations: %w", err)
+		}
+
+		err = tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_user_evaluate_unique
+			ON user_evaluates (user_id, step_evaluate_id, (COALESCE(course_id, 0)))`).Error
+		if err != nil {
+			return fmt.Errorf("failed to create unique index of user evaluations: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// UserAchievement records an achievement earned by a user, Code names one of the achievement rules.
+type UserAchievement struct {
+	Id        *uint64    `gorm:"primaryKey"`
+	UserId    *uint64    `gorm:"uniqueIndex:idx_user_achievement_user_id_code,priority:1; not null"`
+	User      *User      `gorm:"foreignKey:UserId"`
+	Code      *string    `gorm:"type:VARCHAR(255); uniqueIndex:idx_user_achievement_user_id_code,priority:2; not null"`
+	EarnedAt  *time.Time `gorm:"not null"`
+	CreatedAt *time.Time `gorm:"not null"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// BadgeClass is the Open Badge awarded for completing a course, or one of its modules when
+// ModuleId is set. Its image is a PNG in MinIO, so assertions can be baked into it.
+type BadgeClass struct {
+	Id          *uint64    `gorm:"primaryKey"`
+	CourseId    *uint64    `gorm:"index:idx_badge_class_course_id; not null"`
+	Course      *Course    `gorm:"foreignKey:CourseId"`
+	ModuleId    *uint64    `gorm:"null"`
+	Module      *Module    `gorm:"foreignKey:ModuleId"`
+	Name        *string    `gorm:"type:VARCHAR(255); not null"`
+	Description *string    `gorm:"type:TEXT; not null"`
+	Criteria    *string    `gorm:"type:TEXT; not null"` // what a learner did to earn the badge
+	Image       *string    `gorm:"type:VARCHAR(255); not null"`
+	CreatedAt   *time.Time `gorm:"not null"`
+	UpdatedAt   *time.Time `gorm:"not null"`
+}
+
+// BadgeAssertion awards a badge class to a learner. The UID is the public ID of the hosted
+// assertion, a revoked assertion stays hosted to tell verifiers it was revoked.
+type BadgeAssertion struct {
+	Id               *uint64     `gorm:"primaryKey"`
+	Uid              *string     `gorm:"type:VARCHAR(36); uniqueIndex; not null"`
+	BadgeClassId     *uint64     `gorm:"uniqueIndex:idx_badge_assertion_user; not null"`
+	BadgeClass       *BadgeClass `gorm:"foreignKey:BadgeClassId"`
+	UserId           *uint64     `gorm:"uniqueIndex:idx_badge_assertion_user; index; not null"`
+	User             *User       `gorm:"foreignKey:UserId"`
+	RecipientSalt    *string     `gorm:"type:VARCHAR(64); not null"`
+	IssuedOn         *time.Time  `gorm:"not null"`
+	RevokedAt        *time.Time  `gorm:"null"`
+	RevocationReason *string     `gorm:"type:TEXT; null"`
+	CreatedAt        *time.Time  `gorm:"not null"`
+	UpdatedAt        *time.Time  `gorm:"not null"`
+}
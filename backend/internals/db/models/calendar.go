@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// CalendarEvent is a deadline or an event of a course, shown to its enrolled learners. When
+// CohortId is set it is shown to the members of that cohort only.
+type CalendarEvent struct {
+	Id          *uint64    `gorm:"primaryKey"`
+	CourseId    *uint64    `gorm:"index:idx_calendar_event_course_id; not null"`
+	Course      *Course    `gorm:"foreignKey:CourseId"`
+	CohortId    *uint64    `gorm:"index:idx_calendar_event_cohort_id; null"`
+	Cohort      *Cohort    `gorm:"foreignKey:CohortId"`
+	Kind        *string    `gorm:"type:VARCHAR(255) CHECK(kind IN ('deadline', 'event')); not null"`
+	Title       *string    `gorm:"type:VARCHAR(255); not null"`
+	Description *string    `gorm:"type:TEXT; null"`
+	StartsAt    *time.Time `gorm:"index:idx_calendar_event_starts_at; not null"` // due time of a deadline
+	EndsAt      *time.Time `gorm:"null"`                                         // deadlines have no end
+	Location    *string    `gorm:"type:VARCHAR(255); null"`
+	CreatedAt   *time.Time `gorm:"not null"`
+	UpdatedAt   *time.Time `gorm:"not null"`
+}
+
+// CalendarToken grants read access to the calendar feed of its user, so the feed URL can be
+// subscribed to by calendar apps without the session cookie. Only the SHA-256 of the token is
+// kept, a user has at most one and creating a new one revokes the previous.
+type CalendarToken struct {
+	Id        *uint64    `gorm:"primaryKey"`
+	UserId    *uint64    `gorm:"uniqueIndex:idx_calendar_token_user_id; not null"`
+	User      *User      `gorm:"foreignKey:UserId"`
+	TokenHash *string    `gorm:"type:VARCHAR(64); uniqueIndex:idx_calendar_token_hash; not null"`
+	CreatedAt *time.Time `gorm:"not null"`
+	UpdatedAt *time.Time `gorm:"not null"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Certificate is issued to a learner who passed every evaluation of a course. The names are
+// kept as printed on the PDF, so verification still matches it when the profile or the course
+// is renamed later.
+type Certificate struct {
+	Id            *uint64    `gorm:"primaryKey"`
+	Code          *string    `gorm:"type:VARCHAR(32); uniqueIndex; not null"` // certificate ID printed on the PDF
+	UserId        *uint64    `gorm:"uniqueIndex:idx_certificate_user_course; not null"`
+	User          *User      `gorm:"foreignKey:UserId"`
+	CourseId      *uint64    `gorm:"uniqueIndex:idx_certificate_user_course; not null"`
+	Course        *Course    `gorm:"foreignKey:CourseId"`
+	RecipientName *string    `gorm:"type:VARCHAR(255); not null"`
+	CourseName    *string    `gorm:"type:VARCHAR(255); not null"`
+	CompletedAt   *time.Time `gorm:"not null"`
+	Object        *string    `gorm:"type:VARCHAR(255); not null"` // object name of the PDF in MinIO
+	CreatedAt     *time.Time `gorm:"not null"`
+	UpdatedAt     *time.Time `gorm:"not null"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Cohort is a group of learners taking a course together, paced by its instructors. Steps
+// after the unlocked course content position, or after the unlocked step within it, are locked
+// for the learners of the cohort. Nothing is unlocked until an instructor sets a position.
+type Cohort struct {
+	Id             *uint64    `gorm:"primaryKey"`
+	CourseId       *uint64    `gorm:"index:idx_cohort_course_id; not null"`
+	Course         *Course    `gorm:"foreignKey:CourseId"`
+	Name           *string    `gorm:"type:VARCHAR(255); not null"`
+	UnlockedOrder  *int64     `gorm:"null"`
+	UnlockedStepId *uint64    `gorm:"null"`
+	UnlockedStep   *Step      `gorm:"foreignKey:UnlockedStepId"`
+	CreatedAt      *time.Time `gorm:"not null"`
+	UpdatedAt      *time.Time `gorm:"not null"`
+}
+
+type CohortMember struct {
+	Id        *uint64    `gorm:"primaryKey"`
+	CohortId  *uint64    `gorm:"uniqueIndex:idx_cohort_member_user; not null"`
+	Cohort    *Cohort    `gorm:"foreignKey:CohortId"`
+	UserId    *uint64    `gorm:"uniqueIndex:idx_cohort_member_user; index; not null"`
+	User      *User      `gorm:"foreignKey:UserId"`
+	Role      *string    `gorm:"type:VARCHAR(255) CHECK(role IN ('learner', 'instructor')); not null"`
+	CreatedAt *time.Time `gorm:"not null"`
+	UpdatedAt *time.Time `gorm:"not null"`
+}
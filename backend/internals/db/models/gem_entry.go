@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// GemEntry is an append-only record of the gems of a user, balances are the sum of the amounts.
+// Awards are positive, revokes and spends negative and adjustments either. Spends pay for shop
+// redemptions and are left out of the leaderboards. Entries of a source, like the
+// user evaluation awarding them, net to what the source is currently worth. The idempotency key
+// makes writing the same entry twice a no-op.
+type GemEntry struct {
+	Id             *uint64    `gorm:"primaryKey"`
+	UserId         *uint64    `gorm:"index:idx_gem_entry_user_id; not null"`
+	User           *User      `gorm:"foreignKey:UserId"`
+	Kind           *string    `gorm:"type:VARCHAR(255) CHECK(kind IN ('award', 'revoke', 'adjust', 'spend')); not null"`
+	Amount         *int64     `gorm:"not null"`
+	SourceType     *string    `gorm:"type:VARCHAR(255) CHECK(source_type IN ('user_evaluate', 'manual', 'redemption')); index:idx_gem_entry_source; not null"`
+	SourceId       *uint64    `gorm:"index:idx_gem_entry_source; null"`
+	CourseId       *uint64    `gorm:"null"` // course the gems count for in the field totals
+	Course         *Course    `gorm:"foreignKey:CourseId"`
+	IdempotencyKey *string    `gorm:"type:VARCHAR(255); uniqueIndex:idx_gem_entry_idempotency_key; not null"`
+	Reason         *string    `gorm:"type:TEXT; null"`
+	CreatedBy      *uint64    `gorm:"null"` // admin making an adjustment
+	CreatedAt      *time.Time `gorm:"not null"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// HelpRequest is a raised hand of a learner in the help queue of a workshop session or a cohort.
+// It is waiting until a TA claims it, and the claim and resolve times give the wait and the
+// resolution time of the step it was raised on.
+type HelpRequest struct {
+	Id         *uint64          `gorm:"primaryKey"`
+	SessionId  *uint64          `gorm:"index:idx_help_request_session_id; null"`
+	Session    *WorkshopSession `gorm:"foreignKey:SessionId"`
+	CohortId   *uint64          `gorm:"index:idx_help_request_cohort_id; null"`
+	Cohort     *Cohort          `gorm:"foreignKey:CohortId"`
+	StepId     *uint64          `gorm:"index:idx_help_request_step_id; not null"`
+	Step       *Step            `gorm:"foreignKey:StepId"`
+	UserId     *uint64          `gorm:"index:idx_help_request_user_id; not null"`
+	User       *User            `gorm:"foreignKey:UserId"`
+	Note       *string          `gorm:"type:TEXT; null"`
+	Photo      *string          `gorm:"type:VARCHAR(255); null"` // object name in the bucket
+	Status     *string          `gorm:"type:VARCHAR(255) CHECK(status IN ('waiting', 'claimed', 'resolved', 'cancelled')); not null"`
+	ClaimedBy  *uint64          `gorm:"null"`
+	Claimer    *User            `gorm:"foreignKey:ClaimedBy"`
+	ClaimedAt  *time.Time       `gorm:"null"`
+	ResolvedAt *time.Time       `gorm:"null"`
+	CreatedAt  *time.Time       `gorm:"not null"`
+	UpdatedAt  *time.Time       `gorm:"not null"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+type ImportJob struct {
+	Id         *uint64    `gorm:"primaryKey"`
+	DocumentId *string    `gorm:"type:VARCHAR(255); index:idx_import_job_document_id; not null"`
+	Kind       *string    `gorm:"type:VARCHAR(255) CHECK(kind IN ('module', 'course')); not null"`
+	Event      *string    `gorm:"type:VARCHAR(255); not null"`
+	Status     *string    `gorm:"type:VARCHAR(255) CHECK(status IN ('pending', 'running', 'succeeded', 'failed')); not null"`
+	Attempts   *int       `gorm:"not null"`
+	LastError  *string    `gorm:"type:TEXT; null"`
+	StartedAt  *time.Time `gorm:"null"`
+	FinishedAt *time.Time `gorm:"null"`
+	CreatedAt  *time.Time `gorm:"not null"`
+	UpdatedAt  *time.Time `gorm:"not null"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// LeaderboardScore is the gems a user earned in a scope, the whole platform, a course or a field,
+// over a period starting at PeriodStart. Scores are kept up to date as gem entries are appended,
+// so rankings never aggregate the ledger. All-time scores start at the epoch.
+type LeaderboardScore struct {
+	Id          *uint64    `gorm:"primaryKey"`
+	Scope       *string    `gorm:"type:VARCHAR(255) CHECK(scope IN ('global', 'course', 'field')); uniqueIndex:idx_leaderboard_score_key,priority:1; index:idx_leaderboard_score_rank,priority:1; not null"`
+	ScopeId     *uint64    `gorm:"uniqueIndex:idx_leaderboard_score_key,priority:2; index:idx_leaderboard_score_rank,priority:2; not null"` // 0 for global
+	Period      *string    `gorm:"type:VARCHAR(255) CHECK(period IN ('all', 'month', 'week')); uniqueIndex:idx_leaderboard_score_key,priority:3; index:idx_leaderboard_score_rank,priority:3; not null"`
+	PeriodStart *time.Time `gorm:"type:DATE; uniqueIndex:idx_leaderboard_score_key,priority:4; index:idx_leaderboard_score_rank,priority:4; not null"`
+	UserId      *uint64    `gorm:"uniqueIndex:idx_leaderboard_score_key,priority:5; not null"`
+	User        *User      `gorm:"foreignKey:UserId"`
+	Gems        *int64     `gorm:"index:idx_leaderboard_score_rank,priority:5,sort:desc; not null"`
+}
+
+// LeaderboardPreference keeps users who opted out of leaderboards off the rankings of others.
+type LeaderboardPreference struct {
+	Id        *uint64    `gorm:"primaryKey"`
+	UserId    *uint64    `gorm:"uniqueIndex:idx_leaderboard_preference_user_id; not null"`
+	User      *User      `gorm:"foreignKey:UserId"`
+	Hidden    *bool      `gorm:"not null"`
+	CreatedAt *time.Time `gorm:"not null"`
+	UpdatedAt *time.Time `gorm:"not null"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Notification is an email in the outbox, rendered when enqueued and sent by the notification
+// worker. Failed sends are retried with a backoff until the attempts run out.
+type Notification struct {
+	Id            *uint64    `gorm:"primaryKey"`
+	UserId        *uint64    `gorm:"index:idx_notification_user_id; not null"`
+	User          *User      `gorm:"foreignKey:UserId"`
+	Kind          *string    `gorm:"type:VARCHAR(255) CHECK(kind IN ('submission_graded', 'comment_reply')); not null"`
+	Recipient     *string    `gorm:"type:VARCHAR(255); not null"`
+	Subject       *string    `gorm:"type:TEXT; not null"`
+	TextBody      *string    `gorm:"type:TEXT; not null"`
+	HtmlBody      *string    `gorm:"type:TEXT; not null"`
+	Status        *string    `gorm:"type:VARCHAR(255) CHECK(status IN ('pending', 'sent', 'failed')); index:idx_notification_due; not null"`
+	Attempts      *int       `gorm:"not null"`
+	NextAttemptAt *time.Time `gorm:"index:idx_notification_due; not null"`
+	LastError     *string    `gorm:"type:TEXT; null"`
+	SentAt        *time.Time `gorm:"null"`
+	CreatedAt     *time.Time `gorm:"not null"`
+	UpdatedAt     *time.Time `gorm:"not null"`
+}
+
+// NotificationPreference is the choice of a user about emails, users without one get every
+// email in Thai.
+type NotificationPreference struct {
+	Id               *uint64    `gorm:"primaryKey"`
+	UserId           *uint64    `gorm:"uniqueIndex:idx_notification_preference_user_id; not null"`
+	User             *User      `gorm:"foreignKey:UserId"`
+	Locale           *string    `gorm:"type:VARCHAR(255) CHECK(locale IN ('th', 'en')); not null"`
+	SubmissionGraded *bool      `gorm:"not null"`
+	CommentReply     *bool      `gorm:"not null"`
+	CreatedAt        *time.Time `gorm:"not null"`
+	UpdatedAt        *time.Time `gorm:"not null"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// RewardItem is an item of the gem shop, like a sensor kit or a priority lab booking. Stock is
+// the number of items left to redeem, inactive items are hidden from learners.
+type RewardItem struct {
+	Id          *uint64    `gorm:"primaryKey"`
+	Name        *string    `gorm:"type:VARCHAR(255); not null"`
+	Description *string    `gorm:"type:TEXT; null"`
+	ImageUrl    *string    `gorm:"type:TEXT; null"`
+	Price       *int64     `gorm:"type:BIGINT CHECK(price > 0); not null"` // gems
+	Stock       *int64     `gorm:"type:BIGINT CHECK(stock >= 0); not null"`
+	Active      *bool      `gorm:"default:true; not null"`
+	CreatedAt   *time.Time `gorm:"not null"`
+	UpdatedAt   *time.Time `gorm:"not null"`
+}
+
+// Redemption is a reward item bought by a learner, pending until staff hand it over. The gems
+// are spent by the gem entry of the redemption, written in the same transaction.
+type Redemption struct {
+	Id           *uint64     `gorm:"primaryKey"`
+	UserId       *uint64     `gorm:"index:idx_redemption_user_id; not null"`
+	User         *User       `gorm:"foreignKey:UserId"`
+	RewardItemId *uint64     `gorm:"index:idx_redemption_reward_item_id; not null"`
+	RewardItem   *RewardItem `gorm:"foreignKey:RewardItemId"`
+	Price        *int64      `gorm:"not null"` // gems paid, the item price at the time
+	Status       *string     `gorm:"type:VARCHAR(255) CHECK(status IN ('pending', 'fulfilled')); index:idx_redemption_status; not null"`
+	FulfilledBy  *uint64     `gorm:"null"`
+	FulfilledAt  *time.Time  `gorm:"null"`
+	CreatedAt    *time.Time  `gorm:"not null"`
+	UpdatedAt    *time.Time  `gorm:"not null"`
+}
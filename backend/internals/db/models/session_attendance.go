@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+type SessionAttendance struct {
+	Id          *uint64          `gorm:"primaryKey"`
+	SessionId   *uint64          `gorm:"uniqueIndex:idx_session_attendance_user; not null"`
+	Session     *WorkshopSession `gorm:"foreignKey:SessionId"`
+	UserId      *uint64          `gorm:"uniqueIndex:idx_session_attendance_user; not null"`
+	User        *User            `gorm:"foreignKey:UserId"`
+	CheckedInAt *time.Time       `gorm:"not null"`
+	CreatedAt   *time.Time       `gorm:"not null"`
+	UpdatedAt   *time.Time       `gorm:"not null"`
+}
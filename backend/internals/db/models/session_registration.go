@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+type SessionRegistration struct {
+	Id        *uint64          `gorm:"primaryKey"`
+	SessionId *uint64          `gorm:"uniqueIndex:idx_session_registration_user; not null"`
+	Session   *WorkshopSession `gorm:"foreignKey:SessionId"`
+	UserId    *uint64          `gorm:"uniqueIndex:idx_session_registration_user; index; not null"`
+	User      *User            `gorm:"foreignKey:UserId"`
+	Status    *string          `gorm:"type:VARCHAR(255) CHECK(status IN ('registered', 'waitlisted', 'cancelled')); not null"`
+	QueuedAt  *time.Time       `gorm:"not null"` // waitlist order, reset when registering again after a cancel
+	CreatedAt *time.Time       `gorm:"not null"`
+	UpdatedAt *time.Time       `gorm:"not null"`
+}
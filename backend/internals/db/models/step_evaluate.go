@@ -3,14 +3,15 @@ package models
 import "time"
 
 type StepEvaluate struct {
-	Id          *uint64    `gorm:"primaryKey"`
-	StepId      *uint64    `gorm:"index:idx_step_evaluate,unique; not null"`
-	Step        *Step      `gorm:"foreignKey:StepId"`
-	Gem         *int       `gorm:"not null"`
-	Order       *int       `gorm:"index:idx_step_evaluate,unique; not null"`
-	Question    *string    `gorm:"type:TEXT; not null"`
-	Type        *string    `gorm:"type:VARCHAR(255) CHECK(type IN ('check', 'text', 'image')); not null"`
-	Instruction *string    `gorm:"type:TEXT; null"`
-	CreatedAt   *time.Time `gorm:"not null"`
-	UpdatedAt   *time.Time `gorm:"not null"`
+	Id           *uint64    `gorm:"primaryKey"`
+	StepId       *uint64    `gorm:"index:idx_step_evaluate,unique; not null"`
+	Step         *Step      `gorm:"foreignKey:StepId"`
+	Gem          *int       `gorm:"not null"`
+	Order        *int       `gorm:"index:idx_step_evaluate,unique; not null"`
+	Question     *string    `gorm:"type:TEXT; not null"`
+	Type         *string    `gorm:"type:VARCHAR(255) CHECK(type IN ('check', 'text', 'image')); not null"`
+	Instruction  *string    `gorm:"type:TEXT; null"`
+	TeamEligible *bool      `gorm:"not null; default:false"` // one submission counts for the whole team
+	CreatedAt    *time.Time `gorm:"not null"`
+	UpdatedAt    *time.Time `gorm:"not null"`
 }
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// StepSession is a stretch of time a user spent on a step, kept up by the heartbeats of the page.
+// A pause between heartbeats longer than the session gap starts a new session, and only the time
+// between heartbeats of an active page counts toward the active seconds.
+type StepSession struct {
+	Id            *uint64    `gorm:"primaryKey"`
+	UserId        *uint64    `gorm:"index:idx_step_session_user_step; not null"`
+	User          *User      `gorm:"foreignKey:UserId"`
+	StepId        *uint64    `gorm:"index:idx_step_session_user_step; index:idx_step_session_course_step; not null"`
+	Step          *Step      `gorm:"foreignKey:StepId"`
+	CourseId      *uint64    `gorm:"index:idx_step_session_course_step; not null"` // course the step was visited in
+	Course        *Course    `gorm:"foreignKey:CourseId"`
+	StartedAt     *time.Time `gorm:"not null"`
+	LastSeenAt    *time.Time `gorm:"index:idx_step_session_user_step; not null"`
+	ActiveSeconds *int64     `gorm:"not null; default:0"`
+	CreatedAt     *time.Time `gorm:"not null"`
+	UpdatedAt     *time.Time `gorm:"not null"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// StrengthSnapshot is the strength of a user in a field over the week starting at WeekStart: the
+// gems of the evaluations of the field they passed against all those the field offers. The
+// snapshot of the current week is kept up to date until the week ends.
+type StrengthSnapshot struct {
+	Id            *uint64    `gorm:"primaryKey"`
+	UserId        *uint64    `gorm:"uniqueIndex:idx_strength_snapshot_key,priority:1; not null"`
+	User          *User      `gorm:"foreignKey:UserId"`
+	WeekStart     *time.Time `gorm:"type:DATE; uniqueIndex:idx_strength_snapshot_key,priority:2; not null"`
+	FieldId       *uint64    `gorm:"uniqueIndex:idx_strength_snapshot_key,priority:3; not null"`
+	Field         *FieldType `gorm:"foreignKey:FieldId"`
+	EarnedGems    *int64     `gorm:"not null"`
+	AvailableGems *int64     `gorm:"not null"`
+	CreatedAt     *time.Time `gorm:"not null"`
+	UpdatedAt     *time.Time `gorm:"not null"`
+}
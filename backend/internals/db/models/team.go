@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Team is a group of learners doing lab work together in a course, optionally formed for a
+// workshop session. A submission of a member to a team-eligible evaluation counts for the
+// whole team.
+type Team struct {
+	Id        *uint64          `gorm:"primaryKey"`
+	CourseId  *uint64          `gorm:"index:idx_team_course_id; not null"`
+	Course    *Course          `gorm:"foreignKey:CourseId"`
+	SessionId *uint64          `gorm:"null"`
+	Session   *WorkshopSession `gorm:"foreignKey:SessionId"`
+	Name      *string          `gorm:"type:VARCHAR(255); not null"`
+	Members   []*TeamMember    `gorm:"foreignKey:TeamId"`
+	CreatedAt *time.Time       `gorm:"not null"`
+	UpdatedAt *time.Time       `gorm:"not null"`
+}
+
+type TeamMember struct {
+	Id        *uint64    `gorm:"primaryKey"`
+	TeamId    *uint64    `gorm:"uniqueIndex:idx_team_member_user; not null"`
+	Team      *Team      `gorm:"foreignKey:TeamId"`
+	UserId    *uint64    `gorm:"uniqueIndex:idx_team_member_user; index; not null"`
+	User      *User      `gorm:"foreignKey:UserId"`
+	CreatedAt *time.Time `gorm:"not null"`
+	UpdatedAt *time.Time `gorm:"not null"`
+}
@@ -9,6 +9,8 @@ type User struct {
 	Lastname  *string    `gorm:"type:VARCHAR(255); not null"`
 	Email     *string    `gorm:"type:VARCHAR(255); index:idx_user_email,unique; not null"`
 	PhotoUrl  *string    `gorm:"type:TEXT; null"`
+	Role      *string    `gorm:"type:VARCHAR(255) CHECK(role IN ('learner', 'instructor', 'admin')); default:learner; not null"`
+	Timezone  *string    `gorm:"type:VARCHAR(64); default:Asia/Bangkok; not null"` // IANA name, days of the activity log follow it
 	CreatedAt *time.Time `gorm:"not null"`
 	UpdatedAt *time.Time `gorm:"not null"`
 }
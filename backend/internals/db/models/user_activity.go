@@ -7,6 +7,8 @@ type UserActivity struct {
 	User      *User      `gorm:"foreignKey:UserId"`
 	StepId    *uint64    `gorm:"not null"`
 	Step      *Step      `gorm:"foreignKey:StepId"`
+	CourseId  *uint64    `gorm:"null"` // course the step was visited in, modules can be shared
+	Course    *Course    `gorm:"foreignKey:CourseId"`
 	CreatedAt *time.Time `gorm:"not null"`
 	UpdatedAt *time.Time `gorm:"not null"`
 }
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// UserDailyActivity counts the activities of a user on a day of their time zone. Unlike
+// UserActivity it keeps every day, for streaks and the activity heatmap.
+type UserDailyActivity struct {
+	Id        *uint64    `gorm:"primaryKey"`
+	UserId    *uint64    `gorm:"uniqueIndex:idx_user_daily_activity_user_id_day,priority:1; not null"`
+	User      *User      `gorm:"foreignKey:UserId"`
+	Day       *time.Time `gorm:"type:DATE; uniqueIndex:idx_user_daily_activity_user_id_day,priority:2; not null"`
+	Count     *int64     `gorm:"not null"`
+	CreatedAt *time.Time `gorm:"not null"`
+	UpdatedAt *time.Time `gorm:"not null"`
+}
@@ -2,15 +2,24 @@ package models
 
 import "time"
 
+// UserEvaluate is the submission of a user for a step evaluation, unique per course through the
+// idx_user_evaluate_unique index created by the migration, unattributed ones counting as course 0.
 type UserEvaluate struct {
 	Id             *uint64       `gorm:"primaryKey"`
 	UserId         *uint64       `gorm:"index:idx_user_evaluate; not null"`
 	User           *User         `gorm:"foreignKey:UserId"`
 	StepEvaluateId *uint64       `gorm:"index:idx_user_evaluate; not null"`
 	StepEvaluate   *StepEvaluate `gorm:"foreignKey:StepEvaluateId"`
+	CourseId       *uint64       `gorm:"index:idx_user_evaluate; null"` // course the step was taken in, modules can be shared
+	Course         *Course       `gorm:"foreignKey:CourseId"`
 	Content        *string       `gorm:"type:TEXT; not null"`
 	Pass           *bool         `gorm:"null"`
+	Attempts       *int          `gorm:"not null; default:1"` // submissions made, the last one is kept
 	Comment        *string       `gorm:"type:TEXT; null"`
+	TeamId         *uint64       `gorm:"index; null"` // team the submission was made for, shared by its members
+	Team           *Team         `gorm:"foreignKey:TeamId"`
+	SubmittedBy    *uint64       `gorm:"null"`
+	Submitter      *User         `gorm:"foreignKey:SubmittedBy"`
 	CreatedAt      *time.Time    `gorm:"not null"`
 	UpdatedAt      *time.Time    `gorm:"not null"`
 }
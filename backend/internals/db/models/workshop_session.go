@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+type WorkshopSession struct {
+	Id        *uint64    `gorm:"primaryKey"`
+	CourseId  *uint64    `gorm:"index:idx_workshop_session_course_id; not null"`
+	Course    *Course    `gorm:"foreignKey:CourseId"`
+	Title     *string    `gorm:"type:VARCHAR(255); not null"`
+	StartsAt  *time.Time `gorm:"index:idx_workshop_session_starts_at; not null"`
+	EndsAt    *time.Time `gorm:"not null"`
+	Room      *string    `gorm:"type:VARCHAR(255); null"`
+	OnlineUrl *string    `gorm:"type:TEXT; null"`
+	Capacity  *int       `gorm:"not null"`
+	CreatedAt *time.Time `gorm:"not null"`
+	UpdatedAt *time.Time `gorm:"not null"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// XapiStatement is an xAPI statement in the outbox, built when the learning event happens and
+// sent to the LRS by the xAPI worker. Failed sends are retried with a backoff until the attempts
+// run out.
+type XapiStatement struct {
+	Id            *uint64    `gorm:"primaryKey"`
+	StatementId   *string    `gorm:"type:VARCHAR(36); uniqueIndex:idx_xapi_statement_statement_id; not null"` // derived from the event, so replays are recognized
+	UserId        *uint64    `gorm:"index:idx_xapi_statement_user_id; not null"`
+	User          *User      `gorm:"foreignKey:UserId"`
+	Verb          *string    `gorm:"type:VARCHAR(255) CHECK(verb IN ('experienced', 'attempted', 'passed', 'failed', 'completed', 'commented')); not null"`
+	Statement     *string    `gorm:"type:TEXT; not null"` // JSON sent as is
+	Status        *string    `gorm:"type:VARCHAR(255) CHECK(status IN ('pending', 'sent', 'failed')); index:idx_xapi_statement_due; not null"`
+	Attempts      *int       `gorm:"not null"`
+	NextAttemptAt *time.Time `gorm:"index:idx_xapi_statement_due; not null"`
+	LastError     *string    `gorm:"type:TEXT; null"`
+	SentAt        *time.Time `gorm:"null"`
+	CreatedAt     *time.Time `gorm:"not null"`
+	UpdatedAt     *time.Time `gorm:"not null"`
+}
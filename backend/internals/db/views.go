@@ -0,0 +1,76 @@
+package db
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// stepFunnelView sums up per course and step how far learners get: who reached the step, who
+// passed all of its evaluations and how long and how many attempts it took them. Activities and
+// evaluations recorded without a course count for every course containing the module, and
+// passing is when the last evaluation of the step was passed.
+const stepFunnelView = `CREATE MATERIALIZED VIEW IF NOT EXISTS step_funnel_stats AS
+	WITH course_steps AS (
+		SELECT DISTINCT course_contents.course_id, steps.module_id, steps.id AS step_id
+		FROM course_contents
+		JOIN steps ON steps.module_id = course_contents.module_id
+		WHERE course_contents.type = 'module'
+	),
+	evaluations AS (
+		SELECT step_id, COUNT(*) AS evaluations
+		FROM step_evaluates
+		GROUP BY step_id
+	),
+	reached AS (
+		SELECT course_steps.course_id, course_steps.step_id, user_activities.user_id, MIN(user_activities.created_at) AS reached_at
+		FROM course_steps
+		JOIN user_activities ON user_activities.step_id = course_steps.step_id
+			AND (user_activities.course_id = course_steps.course_id OR user_activities.course_id IS NULL)
+		GROUP BY course_steps.course_id, course_steps.step_id, user_activities.user_id
+	),
+	submissions AS (
+		SELECT course_steps.course_id, course_steps.step_id, user_evaluates.user_id,
+			COUNT(*) AS submissions,
+			SUM(user_evaluates.attempts) AS attempts,
+			COUNT(*) FILTER (WHERE user_evaluates.pass) AS passed,
+			COUNT(*) FILTER (WHERE user_evaluates.pass IS NULL) AS pending,
+			MAX(user_evaluates.updated_at) FILTER (WHERE user_evaluates.pass) AS passed_at
+		FROM course_steps
+		JOIN step_evaluates ON step_evaluates.step_id = course_steps.step_id
+		JOIN user_evaluates ON user_evaluates.step_evaluate_id = step_evaluates.id
+			AND (user_evaluates.course_id = course_steps.course_id OR user_evaluates.course_id IS NULL)
+		GROUP BY course_steps.course_id, course_steps.step_id, user_evaluates.user_id
+	)
+	SELECT course_steps.course_id, course_steps.module_id, course_steps.step_id,
+		COALESCE(evaluations.evaluations, 0) AS evaluations,
+		COUNT(reached.user_id) AS reached,
+		COUNT(reached.user_id) FILTER (WHERE submissions.passed = evaluations.evaluations) AS passed,
+		percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM submissions.passed_at - reached.reached_at))
+			FILTER (WHERE submissions.passed = evaluations.evaluations) AS median_pass_seconds,
+		COALESCE(SUM(submissions.submissions), 0) AS submissions,
+		COALESCE(SUM(submissions.attempts), 0) AS attempts,
+		COALESCE(SUM(submissions.passed), 0) AS passed_submissions,
+		COALESCE(SUM(submissions.pending), 0) AS pending_submissions,
+		now() AS refreshed_at
+	FROM course_steps
+	LEFT JOIN evaluations ON evaluations.step_id = course_steps.step_id
+	LEFT JOIN reached ON reached.course_id = course_steps.course_id AND reached.step_id = course_steps.step_id
+	LEFT JOIN submissions ON submissions.course_id = reached.course_id AND submissions.step_id = reached.step_id
+		AND submissions.user_id = reached.user_id
+	GROUP BY course_steps.course_id, course_steps.module_id, course_steps.step_id, evaluations.evaluations`
+
+// CreateViews creates the materialized views of the analytics, filled with the current data.
+// A view is left alone once it exists, so changing its query means dropping it first.
+func CreateViews(db *gorm.DB) error {
+	if err := db.Exec(stepFunnelView).Error; err != nil {
+		return fmt.Errorf("failed to create step funnel view: %w", err)
+	}
+
+	// refreshing concurrently, without blocking the reports, needs a unique index
+	if err := db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_step_funnel_stats ON step_funnel_stats (course_id, step_id)").Error; err != nil {
+		return fmt.Errorf("failed to index step funnel view: %w", err)
+	}
+
+	return nil
+}
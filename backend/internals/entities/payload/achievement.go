@@ -0,0 +1,30 @@
+package payload
+
+import "time"
+
+// Triggers of the achievement rules, recorded by the services as they happen.
+const (
+	AchievementTriggerSubmissionPassed = "submission.passed"
+	AchievementTriggerCommentCreated   = "comment.created"
+	AchievementTriggerUpVoteReceived   = "upvote.received"
+	AchievementTriggerActivityRecorded = "activity.recorded"
+)
+
+// Metrics counted for the achievement rules.
+const (
+	AchievementMetricCommentsCreated      = "comments_created"
+	AchievementMetricUpVotesReceived      = "upvotes_received"
+	AchievementMetricModulesFinishedInDay = "modules_finished_in_day"
+	AchievementMetricFieldsCompleted      = "fields_completed"
+	AchievementMetricStepsVisited         = "steps_visited"
+)
+
+type AchievementInfo struct {
+	Code        string     `json:"code"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Threshold   int64      `json:"threshold"`
+	Progress    int64      `json:"progress"` // capped at the threshold
+	Earned      bool       `json:"earned"`
+	EarnedAt    *time.Time `json:"earnedAt"`
+}
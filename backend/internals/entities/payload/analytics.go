@@ -0,0 +1,37 @@
+package payload
+
+import "time"
+
+type StepFunnelQuery struct {
+	ModuleId *uint64 `query:"moduleId"`
+}
+
+// StepFunnel tells how far the learners of a course get on a step. Attempts count every
+// submission, resubmissions included, and the failure rate leaves out the ones waiting for a grade.
+type StepFunnel struct {
+	StepId             *uint64    `json:"stepId"`
+	StepTitle          *string    `json:"stepTitle"`
+	ModuleId           *uint64    `json:"moduleId"`
+	ModuleTitle        *string    `json:"moduleTitle"`
+	Evaluations        int64      `json:"evaluations"`
+	Reached            int64      `json:"reached"` // learners with activity on the step
+	Passed             int64      `json:"passed"`  // learners passing every evaluation, none for steps without any
+	MedianPassSeconds  *float64   `json:"medianPassSeconds"`
+	Submissions        int64      `json:"submissions"`
+	Attempts           int64      `json:"attempts"`
+	PassedSubmissions  int64      `json:"-"`
+	PendingSubmissions int64      `json:"-"`
+	AvgAttempts        *float64   `json:"avgAttempts"` // per learner and evaluation
+	FailureRate        *float64   `json:"failureRate"`
+	DropOff            int64      `json:"dropOff"` // learners reaching the step but not the next one
+	DropOffRate        *float64   `json:"dropOffRate"`
+	Highlighted        bool       `json:"highlighted"` // among the biggest drop-offs of the report
+	RefreshedAt        *time.Time `json:"-"`
+}
+
+type StepFunnelReport struct {
+	CourseId    *uint64       `json:"courseId"`
+	ModuleId    *uint64       `json:"moduleId"`
+	RefreshedAt *time.Time    `json:"refreshedAt"` // the figures are as of then
+	Steps       []*StepFunnel `json:"steps"`
+}
@@ -0,0 +1,121 @@
+package payload
+
+import "time"
+
+const OpenBadgesContext = "https://w3id.org/openbadges/v2"
+
+type BadgeClassIdParam struct {
+	BadgeClassId *uint64 `param:"badgeClassId"`
+}
+
+type BadgeAssertionIdParam struct {
+	AssertionId *string `param:"assertionId"`
+}
+
+type CreateBadgeClass struct {
+	CourseId    *uint64 `json:"courseId" validate:"required"`
+	ModuleId    *uint64 `json:"moduleId"`
+	Name        *string `json:"name" validate:"required"`
+	Description *string `json:"description" validate:"required"`
+	Criteria    *string `json:"criteria" validate:"required"`
+}
+
+type RevokeBadgeAssertion struct {
+	Reason *string `json:"reason" validate:"required"`
+}
+
+type BadgeClassInfo struct {
+	BadgeClassId *uint64 `json:"badgeClassId"`
+	CourseId     *uint64 `json:"courseId"`
+	ModuleId     *uint64 `json:"moduleId"`
+	Name         *string `json:"name"`
+	Description  *string `json:"description"`
+	ImageUrl     *string `json:"imageUrl"`
+	BadgeUrl     *string `json:"badgeUrl"`
+}
+
+type BadgeAssertionInfo struct {
+	AssertionId      *string         `json:"assertionId"`
+	AssertionUrl     *string         `json:"assertionUrl"`
+	BakedImageUrl    *string         `json:"bakedImageUrl"`
+	BadgeClass       *BadgeClassInfo `json:"badgeClass"`
+	IssuedOn         *time.Time      `json:"issuedOn"`
+	Revoked          bool            `json:"revoked"`
+	RevocationReason *string         `json:"revocationReason"`
+}
+
+// The Open Badges 2.0 documents hosted for verifiers, see https://www.imsglobal.org/sites/default/files/Badges/OBv2p0Final/index.html
+
+type OpenBadgeIssuer struct {
+	Context        string `json:"@context"`
+	Type           string `json:"type"`
+	Id             string `json:"id"`
+	Name           string `json:"name"`
+	Url            string `json:"url,omitempty"`
+	Email          string `json:"email,omitempty"`
+	PublicKey      string `json:"publicKey,omitempty"`
+	RevocationList string `json:"revocationList,omitempty"`
+}
+
+type OpenBadgeCryptographicKey struct {
+	Context      string `json:"@context"`
+	Type         string `json:"type"`
+	Id           string `json:"id"`
+	Owner        string `json:"owner"`
+	PublicKeyPem string `json:"publicKeyPem"`
+}
+
+type OpenBadgeCriteria struct {
+	Narrative string `json:"narrative"`
+}
+
+type OpenBadgeClass struct {
+	Context     string             `json:"@context"`
+	Type        string             `json:"type"`
+	Id          string             `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Image       string             `json:"image"`
+	Criteria    *OpenBadgeCriteria `json:"criteria"`
+	Issuer      string             `json:"issuer"`
+}
+
+type OpenBadgeRecipient struct {
+	Type     string `json:"type"`
+	Hashed   bool   `json:"hashed"`
+	Salt     string `json:"salt"`
+	Identity string `json:"identity"`
+}
+
+type OpenBadgeVerification struct {
+	Type    string `json:"type"`
+	Creator string `json:"creator,omitempty"`
+}
+
+// OpenBadgeAssertion is hosted at its ID. A revoked assertion only keeps its ID and the
+// revocation, as the specification asks.
+type OpenBadgeAssertion struct {
+	Context          string                 `json:"@context"`
+	Type             string                 `json:"type,omitempty"`
+	Id               string                 `json:"id"`
+	Recipient        *OpenBadgeRecipient    `json:"recipient,omitempty"`
+	Badge            string                 `json:"badge,omitempty"`
+	Image            string                 `json:"image,omitempty"`
+	Verification     *OpenBadgeVerification `json:"verification,omitempty"`
+	IssuedOn         *time.Time             `json:"issuedOn,omitempty"`
+	Revoked          bool                   `json:"revoked,omitempty"`
+	RevocationReason string                 `json:"revocationReason,omitempty"`
+}
+
+type OpenBadgeRevokedAssertion struct {
+	Id               string `json:"id"`
+	RevocationReason string `json:"revocationReason,omitempty"`
+}
+
+type OpenBadgeRevocationList struct {
+	Context           string                       `json:"@context"`
+	Type              string                       `json:"type"`
+	Id                string                       `json:"id"`
+	Issuer            string                       `json:"issuer"`
+	RevokedAssertions []*OpenBadgeRevokedAssertion `json:"revokedAssertions"`
+}
@@ -0,0 +1,40 @@
+package payload
+
+import "time"
+
+type CalendarFeedParam struct {
+	Token *string `param:"token"`
+}
+
+type CalendarCourseParam struct {
+	CourseId *uint64 `param:"courseId"`
+}
+
+type CreateCalendarEvent struct {
+	CourseId    *uint64    `json:"courseId" validate:"required"`
+	CohortId    *uint64    `json:"cohortId"`
+	Kind        *string    `json:"kind" validate:"required,oneof=deadline event"`
+	Title       *string    `json:"title" validate:"required"`
+	Description *string    `json:"description"`
+	StartsAt    *time.Time `json:"startsAt" validate:"required"`
+	EndsAt      *time.Time `json:"endsAt" validate:"required_if=Kind event,excluded_if=Kind deadline"`
+	Location    *string    `json:"location"`
+}
+
+type CalendarEventInfo struct {
+	EventId     *uint64    `json:"eventId"`
+	CourseId    *uint64    `json:"courseId"`
+	CohortId    *uint64    `json:"cohortId"`
+	Kind        *string    `json:"kind"`
+	Title       *string    `json:"title"`
+	Description *string    `json:"description"`
+	StartsAt    *time.Time `json:"startsAt"`
+	EndsAt      *time.Time `json:"endsAt"`
+	Location    *string    `json:"location"`
+}
+
+// CalendarFeed holds the URL to subscribe to, it is only shown when the token is created.
+type CalendarFeed struct {
+	FeedUrl   *string    `json:"feedUrl"`
+	CreatedAt *time.Time `json:"createdAt"`
+}
@@ -0,0 +1,41 @@
+package payload
+
+import "time"
+
+type CertificateCourseParam struct {
+	CourseId *uint64 `param:"courseId"`
+}
+
+// CertificateVerifyQuery looks a certificate up by its ID, or by the signed token of the QR code
+// printed on it.
+type CertificateVerifyQuery struct {
+	Id    *string `query:"id" validate:"required_without=Token,excluded_with=Token"`
+	Token *string `query:"token" validate:"required_without=Id"`
+}
+
+// CourseCompletion counts the evaluations of a course and how many of them the learner passed,
+// CompletedAt being when the last of them was passed.
+type CourseCompletion struct {
+	Required    int64      `json:"required"`
+	Passed      int64      `json:"passed"`
+	CompletedAt *time.Time `json:"completedAt"`
+}
+
+type CertificateInfo struct {
+	CertificateId *string    `json:"certificateId"`
+	CourseId      *uint64    `json:"courseId"`
+	CourseName    *string    `json:"courseName"`
+	RecipientName *string    `json:"recipientName"`
+	CompletedAt   *time.Time `json:"completedAt"`
+	IssuedAt      *time.Time `json:"issuedAt"`
+	PdfUrl        *string    `json:"pdfUrl"`
+}
+
+type CertificateVerification struct {
+	Valid         bool       `json:"valid"`
+	CertificateId *string    `json:"certificateId"`
+	RecipientName *string    `json:"recipientName"`
+	CourseName    *string    `json:"courseName"`
+	CompletedAt   *time.Time `json:"completedAt"`
+	IssuedAt      *time.Time `json:"issuedAt"`
+}
@@ -0,0 +1,40 @@
+package payload
+
+import "time"
+
+type CohortIdParam struct {
+	CohortId *uint64 `param:"cohortId"`
+}
+
+type CreateCohort struct {
+	CourseId *uint64 `json:"courseId" validate:"required"`
+	Name     *string `json:"name" validate:"required"`
+}
+
+type AddCohortMember struct {
+	UserId *uint64 `json:"userId" validate:"required"`
+	Role   *string `json:"role" validate:"required,oneof=learner instructor"`
+}
+
+// CohortUnlockBody moves the cohort to a course content position, optionally unlocking only
+// the steps of its module up to StepId.
+type CohortUnlockBody struct {
+	Position *int64  `json:"position" validate:"required"`
+	StepId   *uint64 `json:"stepId"`
+}
+
+type CohortUnlock struct {
+	CohortId  *uint64    `json:"cohortId"`
+	CourseId  *uint64    `json:"courseId"`
+	Position  *int64     `json:"position"`
+	StepId    *uint64    `json:"stepId"`
+	UpdatedAt *time.Time `json:"updatedAt"`
+}
+
+type CohortInfo struct {
+	CohortId   *uint64       `json:"cohortId"`
+	CourseId   *uint64       `json:"courseId"`
+	CourseName *string       `json:"courseName"`
+	Name       *string       `json:"name"`
+	Unlock     *CohortUnlock `json:"unlock"`
+}
@@ -0,0 +1,9 @@
+package payload
+
+type CourseBundleImportResult struct {
+	CourseId        *uint64           `json:"courseId"`
+	ModuleIds       map[uint64]uint64 `json:"moduleIds"`
+	StepIds         map[uint64]uint64 `json:"stepIds"`
+	StepEvaluateIds map[uint64]uint64 `json:"stepEvaluateIds"`
+	AssetCount      int               `json:"assetCount"`
+}
@@ -1,9 +1,12 @@
 package payload
 
+import "time"
+
 type CourseResponse struct {
-	ID    uint64        `json:"id"`
-	Name  string        `json:"name"`
-	Field FieldResponse `json:"field"`
+	ID     uint64        `json:"id"`
+	Name   string        `json:"name"`
+	Field  FieldResponse `json:"field"`
+	Reason string        `json:"reason"` // why the course is suggested
 }
 
 type FieldResponse struct {
@@ -16,3 +19,16 @@ type CourseSuggestionResponse struct {
 	Courses    []CourseResponse `json:"courses"`
 	TotalCount int64            `json:"totalCount"`
 }
+
+// CourseCandidate is a course the user could be suggested, neither enrolled in nor completed,
+// with its enrollments overall and by learners sharing a course with the user.
+type CourseCandidate struct {
+	CourseId      *uint64
+	Name          *string
+	FieldId       *uint64
+	FieldName     *string
+	FieldImageUrl *string
+	CreatedAt     *time.Time
+	Enrollments   int64
+	CoEnrollments int64
+}
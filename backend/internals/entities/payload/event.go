@@ -0,0 +1,36 @@
+package payload
+
+// Types of the events pushed on the event stream.
+const (
+	EventSubmissionGraded = "submission.graded"
+	EventCommentReply     = "comment.reply"
+	EventCommentUpVote    = "comment.upvote"
+	EventStepComment      = "step.comment"
+	EventCohortUnlock     = "cohort.unlock"
+	EventAchievement      = "achievement.earned"
+)
+
+// Event is a message of the event hub, Data depends on the Type.
+type Event struct {
+	Type string `json:"type"`
+	Data any    `json:"data"`
+}
+
+type StepCommentEvent struct {
+	StepCommentId *uint64      `json:"stepCommentId"`
+	StepId        *uint64      `json:"stepId"`
+	ParentId      *uint64      `json:"parentId"`
+	UserInfo      *CommentedBy `json:"userInfo"`
+	Comment       *string      `json:"comment"`
+}
+
+type CommentUpVoteEvent struct {
+	StepCommentId *uint64 `json:"stepCommentId"`
+	StepId        *uint64 `json:"stepId"`
+	UserId        *uint64 `json:"userId"` // who upvoted
+	UpVote        *int    `json:"upVote"`
+}
+
+type EventStreamQuery struct {
+	StepId *uint64 `query:"stepId"`
+}
@@ -0,0 +1,52 @@
+package payload
+
+import "time"
+
+type HelpRequestIdParam struct {
+	HelpRequestId *uint64 `param:"helpRequestId"`
+}
+
+// HelpQueueQuery selects the queue of a workshop session or of a cohort.
+type HelpQueueQuery struct {
+	SessionId *uint64 `query:"sessionId" validate:"required_without=CohortId,excluded_with=CohortId"`
+	CohortId  *uint64 `query:"cohortId" validate:"required_without=SessionId"`
+}
+
+type CreateHelpRequest struct {
+	SessionId *uint64 `json:"sessionId" validate:"required_without=CohortId,excluded_with=CohortId"`
+	CohortId  *uint64 `json:"cohortId" validate:"required_without=SessionId"`
+	StepId    *uint64 `json:"stepId" validate:"required"`
+	Note      *string `json:"note" validate:"omitempty,max=1000"`
+}
+
+type HelpStatsQuery struct {
+	CourseId *uint64 `query:"courseId"`
+}
+
+type HelpRequestInfo struct {
+	HelpRequestId *uint64    `json:"helpRequestId"`
+	SessionId     *uint64    `json:"sessionId"`
+	CohortId      *uint64    `json:"cohortId"`
+	StepId        *uint64    `json:"stepId"`
+	StepTitle     *string    `json:"stepTitle"`
+	Learner       *UserInfo  `json:"learner"`
+	Note          *string    `json:"note"`
+	PhotoUrl      *string    `json:"photoUrl"`
+	Status        *string    `json:"status"`
+	Position      *int64     `json:"position"` // 1-based among waiting requests, nil once claimed
+	ClaimedBy     *uint64    `json:"claimedBy"`
+	CreatedAt     *time.Time `json:"createdAt"`
+	ClaimedAt     *time.Time `json:"claimedAt"`
+	ResolvedAt    *time.Time `json:"resolvedAt"`
+}
+
+// StepHelpStats sums up the help requests raised on a step. The wait is from raising the hand
+// until a TA claims it, the resolution from the claim until it is resolved.
+type StepHelpStats struct {
+	StepId               *uint64  `json:"stepId"`
+	StepTitle            *string  `json:"stepTitle"`
+	Requests             *int64   `json:"requests"`
+	Resolved             *int64   `json:"resolved"`
+	AvgWaitSeconds       *float64 `json:"avgWaitSeconds"`
+	AvgResolutionSeconds *float64 `json:"avgResolutionSeconds"`
+}
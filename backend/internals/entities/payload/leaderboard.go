@@ -0,0 +1,51 @@
+package payload
+
+import "time"
+
+const (
+	LeaderboardScopeGlobal = "global"
+	LeaderboardScopeCourse = "course"
+	LeaderboardScopeField  = "field"
+
+	LeaderboardPeriodAll   = "all"
+	LeaderboardPeriodMonth = "month"
+	LeaderboardPeriodWeek  = "week"
+)
+
+type LeaderboardQuery struct {
+	Scope      *string `query:"scope" validate:"required,oneof=global course field"`
+	ScopeId    *uint64 `query:"scopeId" validate:"required_unless=Scope global,excluded_if=Scope global"` // course or field id
+	Period     *string `query:"period" validate:"required,oneof=all month week"`
+	Limit      *int    `query:"limit" validate:"omitempty,min=1,max=100"`     // users at the top
+	Neighbours *int    `query:"neighbours" validate:"omitempty,min=1,max=10"` // users above and below the user in their standing
+}
+
+// LeaderboardKey identifies the scores of one leaderboard, PeriodStart is the start of the period in Bangkok time.
+type LeaderboardKey struct {
+	Scope       string
+	ScopeId     uint64
+	Period      string
+	PeriodStart time.Time
+}
+
+type LeaderboardRow struct {
+	Rank      int64   `json:"rank"`
+	UserId    uint64  `json:"userId"`
+	FirstName *string `json:"firstName"`
+	LastName  *string `json:"lastName"`
+	PhotoUrl  *string `json:"photoUrl"`
+	Gems      int64   `json:"gems"`
+}
+
+type Leaderboard struct {
+	Scope       string            `json:"scope"`
+	ScopeId     uint64            `json:"scopeId"`
+	Period      string            `json:"period"`
+	PeriodStart time.Time         `json:"periodStart"`
+	Me          *LeaderboardRow   `json:"me,omitempty"`
+	Rows        []*LeaderboardRow `json:"rows"`
+}
+
+type LeaderboardPreference struct {
+	Hidden *bool `json:"hidden" validate:"required"`
+}
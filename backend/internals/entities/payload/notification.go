@@ -0,0 +1,36 @@
+package payload
+
+const (
+	NotificationSubmissionGraded = "submission_graded"
+	NotificationCommentReply     = "comment_reply"
+)
+
+type Email struct {
+	To      string
+	Subject string
+	Text    string
+	Html    string
+}
+
+type NotificationPreference struct {
+	Locale           *string `json:"locale" validate:"required,oneof=th en"`
+	SubmissionGraded *bool   `json:"submissionGraded" validate:"required"`
+	CommentReply     *bool   `json:"commentReply" validate:"required"`
+}
+
+// SubmissionGradedEmail is the data of the submission_graded templates.
+type SubmissionGradedEmail struct {
+	FirstName string
+	Question  string
+	Pass      bool
+	Comment   string
+	Url       string
+}
+
+// CommentReplyEmail is the data of the comment_reply templates.
+type CommentReplyEmail struct {
+	FirstName   string
+	ReplierName string
+	Reply       string
+	Url         string
+}
@@ -0,0 +1,44 @@
+package payload
+
+import "time"
+
+type OutlineDocument struct {
+	Id               string  `json:"id"`
+	Title            string  `json:"title"`
+	ParentDocumentId *string `json:"parentDocumentId"`
+}
+
+type OutlineWebhookEvent struct {
+	Id      string                `json:"id"`
+	Event   string                `json:"event"`
+	Payload OutlineWebhookPayload `json:"payload"`
+}
+
+type OutlineWebhookPayload struct {
+	Id    string           `json:"id"`
+	Model *OutlineDocument `json:"model"`
+}
+
+type ImportJobInfo struct {
+	Id         *uint64    `json:"id"`
+	DocumentId *string    `json:"documentId"`
+	Kind       *string    `json:"kind"`
+	Event      *string    `json:"event"`
+	Status     *string    `json:"status"`
+	Attempts   *int       `json:"attempts"`
+	LastError  *string    `json:"lastError"`
+	StartedAt  *time.Time `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt"`
+	CreatedAt  *time.Time `json:"createdAt"`
+}
+
+type ImportJobQuery struct {
+	Limit *int `query:"limit"`
+}
+
+type LintProblem struct {
+	DocumentId string `json:"documentId"`
+	Title      string `json:"title"`
+	Line       int    `json:"line"`
+	Message    string `json:"message"`
+}
@@ -6,4 +6,30 @@ type Profile struct {
 	Lastname  *string `json:"lastname"`
 	Email     *string `json:"email"`
 	PhotoUrl  *string `json:"photoUrl"`
+	Role      *string `json:"role"`
+	Timezone  *string `json:"timezone"`
+	Streak    *Streak `json:"streak"`
+}
+
+type ProfileTimezone struct {
+	Timezone *string `json:"timezone" validate:"required,timezone,max=64"`
+}
+
+type Streak struct {
+	Current    int     `json:"current"`              // days, frozen days excluded
+	Longest    int     `json:"longest"`              // days, frozen days excluded
+	LastActive *string `json:"lastActive,omitempty"` // day in the time zone of the user, e.g. 2025-01-31
+	FreezeDays int     `json:"freezeDays"`           // missed days in a row a streak survives
+}
+
+type ActivityDay struct {
+	Day   string `json:"day"` // e.g. 2025-01-31
+	Count int64  `json:"count"`
+}
+
+type ActivityHeatmap struct {
+	From     string         `json:"from"`
+	To       string         `json:"to"`
+	Timezone string         `json:"timezone"`
+	Days     []*ActivityDay `json:"days"` // only days with activity
 }
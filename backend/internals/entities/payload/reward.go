@@ -0,0 +1,60 @@
+package payload
+
+import "time"
+
+const (
+	RedemptionStatusPending   = "pending"
+	RedemptionStatusFulfilled = "fulfilled"
+)
+
+type RewardItemIdParam struct {
+	RewardItemId *uint64 `param:"rewardItemId"`
+}
+
+type RedemptionIdParam struct {
+	RedemptionId *uint64 `param:"redemptionId"`
+}
+
+type CreateRewardItem struct {
+	Name        *string `json:"name" validate:"required,max=255"`
+	Description *string `json:"description" validate:"omitempty,max=2000"`
+	ImageUrl    *string `json:"imageUrl" validate:"omitempty,url"`
+	Price       *int64  `json:"price" validate:"required,gt=0"`
+	Stock       *int64  `json:"stock" validate:"required,gte=0"`
+}
+
+// UpdateRewardItem changes the given fields of an item. The price of redemptions made before
+// stays what was paid.
+type UpdateRewardItem struct {
+	Name        *string `json:"name" validate:"omitempty,max=255"`
+	Description *string `json:"description" validate:"omitempty,max=2000"`
+	ImageUrl    *string `json:"imageUrl" validate:"omitempty,url"`
+	Price       *int64  `json:"price" validate:"omitempty,gt=0"`
+	Stock       *int64  `json:"stock" validate:"omitempty,gte=0"`
+	Active      *bool   `json:"active"`
+}
+
+type RedemptionQuery struct {
+	Status *string `query:"status" validate:"omitempty,oneof=pending fulfilled"`
+}
+
+type RewardItemInfo struct {
+	Id          *uint64 `json:"id"`
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	ImageUrl    *string `json:"imageUrl"`
+	Price       *int64  `json:"price"`
+	Stock       *int64  `json:"stock"`
+	Active      *bool   `json:"active"`
+}
+
+type RedemptionInfo struct {
+	Id          *uint64         `json:"id"`
+	Learner     *UserInfo       `json:"learner,omitempty"` // only listed for staff
+	RewardItem  *RewardItemInfo `json:"rewardItem"`
+	Price       *int64          `json:"price"`
+	Status      *string         `json:"status"`
+	FulfilledBy *uint64         `json:"fulfilledBy"`
+	FulfilledAt *time.Time      `json:"fulfilledAt"`
+	CreatedAt   *time.Time      `json:"createdAt"`
+}
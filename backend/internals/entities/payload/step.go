@@ -15,6 +15,7 @@ type GetGemsResponse struct {
 
 type StepCommentInfo struct {
 	StepCommentId *uint64      `json:"stepCommentId"`
+	ParentId      *uint64      `json:"parentId"`
 	UserInfo      *CommentedBy `json:"userInfo"`
 	Comment       *string      `json:"comment"`
 	UpVote        *int         `json:"upVote"`
@@ -30,8 +31,18 @@ type CommentedBy struct {
 }
 
 type Comment struct {
-	StepId  *uint64 `json:"stepId" validate:"required"`
-	Content *string `json:"content" validate:"required"`
+	StepId   *uint64 `json:"stepId" validate:"required"`
+	ParentId *uint64 `json:"parentId"` // set when replying to a comment of the step
+	Content  *string `json:"content" validate:"required"`
+}
+
+type UserEvalIdParam struct {
+	UserEvalId *uint64 `param:"userEvalId"`
+}
+
+type GradeUserEval struct {
+	Pass    *bool   `json:"pass" validate:"required"`
+	Comment *string `json:"comment"`
 }
 
 type UpVoteComment struct {
@@ -46,7 +57,10 @@ type StepInfo struct {
 
 type StepDetail struct {
 	StepId      *uint64 `json:"stepId"`
+	CourseId    *uint64 `json:"courseId"`
+	CourseName  *string `json:"courseName"`
 	ModuleId    *uint64 `json:"moduleId"`
+	ModuleTitle *string `json:"moduleTitle"`
 	Banner      *string `json:"banner"`
 	Title       *string `json:"title"`
 	Description *string `json:"description"`
@@ -62,6 +76,7 @@ type StepInfoQuery struct {
 }
 
 type SubmitStepEval struct {
+	CourseId   *uint64 `json:"courseId"`
 	StepId     *uint64 `json:"stepId" validate:"required"`
 	StepEvalId *uint64 `json:"stepEvalId" validate:"required"`
 	Content    *string `json:"content"`
@@ -78,15 +93,19 @@ type StepEvalInfo struct {
 }
 
 type UserEvalResult struct {
-	UserEvalId *uint64 `json:"userEvalId"`
-	Type       *string `json:"type"`
-	Content    *string `json:"content"`
-	Pass       *bool   `json:"pass"`
-	Comment    *string `json:"comment"`
+	UserEvalId  *uint64   `json:"userEvalId"`
+	Type        *string   `json:"type"`
+	Content     *string   `json:"content"`
+	Pass        *bool     `json:"pass"`
+	Comment     *string   `json:"comment"`
+	TeamId      *uint64   `json:"teamId"`
+	SubmittedBy *UserInfo `json:"submittedBy"`
 }
 
 type CreateUserEvalReq struct {
 	UserId     *float64 `json:"userId"`
+	CourseId   *uint64  `json:"courseId"`
+	StepId     *uint64  `json:"stepId"`
 	StepEvalId *uint64  `json:"stepEvalId"`
 	Content    *string  `json:"content"`
 }
@@ -106,5 +125,12 @@ type UserInfo struct {
 }
 
 type StepEvalIdBody struct {
+	CourseId   *uint64 `json:"courseId"`
 	StepEvalId *uint64 `json:"stepEvalId" validate:"required"`
 }
+
+// CourseIdQuery selects the course a shared module is taken in, it may be omitted for
+// modules belonging to a single course.
+type CourseIdQuery struct {
+	CourseId *uint64 `query:"courseId"`
+}
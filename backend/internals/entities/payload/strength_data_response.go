@@ -1,11 +1,46 @@
 package payload
 
+import "time"
+
 type StrengthDataResponse struct {
 	Data     []StrengthFieldData `json:"data"`
 	Username string              `json:"username"`
 }
 
 type StrengthFieldData struct {
-	FieldName string `json:"fieldName"`
-	TotalGems int64  `json:"totalGems"`
+	FieldId       *uint64 `json:"fieldId"`
+	FieldName     string  `json:"fieldName"`
+	TotalGems     int64   `json:"totalGems"` // gems of the evaluations of the field passed
+	AvailableGems int64   `json:"availableGems"`
+	Strength      float64 `json:"strength"` // percent of the available gems earned
+}
+
+type StrengthRadarQuery struct {
+	Weeks *int `query:"weeks" validate:"omitempty,min=1,max=52"` // weeks of history, the current one included
+}
+
+// StrengthRadar is the strength of the user in every field week after week, oldest first, and
+// the weakest fields with the steps left to improve them.
+type StrengthRadar struct {
+	Weeks   []*StrengthWeek `json:"weeks"`
+	Weakest []*WeakField    `json:"weakest"`
+}
+
+type StrengthWeek struct {
+	WeekStart time.Time           `json:"weekStart"`
+	Fields    []StrengthFieldData `json:"fields"`
+}
+
+type WeakField struct {
+	StrengthFieldData
+	Steps []*StrengthStep `json:"steps"`
+}
+
+// StrengthStep is a step with evaluations the user has not passed yet, worth Gems more in the field.
+type StrengthStep struct {
+	CourseId *uint64 `json:"courseId"`
+	ModuleId *uint64 `json:"moduleId"`
+	StepId   *uint64 `json:"stepId"`
+	Title    *string `json:"title"`
+	Gems     int64   `json:"gems"`
 }
@@ -0,0 +1,37 @@
+package payload
+
+type TeamIdParam struct {
+	TeamId *uint64 `param:"teamId"`
+}
+
+type StepEvalIdParam struct {
+	StepEvalId *uint64 `param:"stepEvalId"`
+}
+
+type CreateTeam struct {
+	CourseId  *uint64 `json:"courseId" validate:"required"`
+	SessionId *uint64 `json:"sessionId"`
+	Name      *string `json:"name" validate:"required"`
+}
+
+type AddTeamMember struct {
+	UserId *uint64 `json:"userId" validate:"required"`
+}
+
+type TeamEligibleBody struct {
+	TeamEligible *bool `json:"teamEligible" validate:"required"`
+}
+
+type TeamInfo struct {
+	TeamId     *uint64     `json:"teamId"`
+	CourseId   *uint64     `json:"courseId"`
+	CourseName *string     `json:"courseName"`
+	SessionId  *uint64     `json:"sessionId"`
+	Name       *string     `json:"name"`
+	Members    []*UserInfo `json:"members"`
+}
+
+type StepEvalTeamEligible struct {
+	StepEvalId   *uint64 `json:"stepEvalId"`
+	TeamEligible *bool   `json:"teamEligible"`
+}
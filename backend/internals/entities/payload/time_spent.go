@@ -0,0 +1,55 @@
+package payload
+
+import "time"
+
+// Heartbeat is sent periodically by a page showing a step. Idle is set when the learner has not
+// interacted with the page for a while, which keeps the session alive without counting the time.
+type Heartbeat struct {
+	CourseId *uint64 `json:"courseId"` // required for modules shared by several courses
+	Idle     *bool   `json:"idle"`
+}
+
+type StepSessionInfo struct {
+	SessionId     *uint64    `json:"sessionId"`
+	StepId        *uint64    `json:"stepId"`
+	CourseId      *uint64    `json:"courseId"`
+	StartedAt     *time.Time `json:"startedAt"`
+	LastSeenAt    *time.Time `json:"lastSeenAt"`
+	ActiveSeconds *int64     `json:"activeSeconds"`
+}
+
+type TimeSpentQuery struct {
+	CourseId *uint64 `query:"courseId"`
+}
+
+// TimeSpent is the active time of a learner on a course, a module or a step.
+type TimeSpent struct {
+	Id      *uint64 `json:"id"`
+	Title   *string `json:"title"`
+	Seconds int64   `json:"seconds"`
+}
+
+// TimeSpentReport lists the time of the learner per course, or per module and step of a course.
+type TimeSpentReport struct {
+	CourseId     *uint64      `json:"courseId"`
+	TotalSeconds int64        `json:"totalSeconds"`
+	Courses      []*TimeSpent `json:"courses,omitempty"`
+	Modules      []*TimeSpent `json:"modules,omitempty"`
+	Steps        []*TimeSpent `json:"steps,omitempty"`
+}
+
+// TimeSpentStats sums up the active time of the learners of a course on a module or a step.
+type TimeSpentStats struct {
+	Id            *uint64  `json:"id"`
+	Title         *string  `json:"title"`
+	Learners      int64    `json:"learners"`
+	TotalSeconds  int64    `json:"totalSeconds"`
+	AvgSeconds    *float64 `json:"avgSeconds"`    // per learner
+	MedianSeconds *float64 `json:"medianSeconds"` // per learner
+}
+
+type CourseTimeSpentReport struct {
+	CourseId *uint64           `json:"courseId"`
+	Modules  []*TimeSpentStats `json:"modules"`
+	Steps    []*TimeSpentStats `json:"steps"`
+}
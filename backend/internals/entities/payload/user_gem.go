@@ -1,6 +1,40 @@
 package payload
 
+import "time"
+
 type GemTotal struct {
 	UserID uint   `json:"userId"`
 	Total  uint64 `json:"total"`
 }
+
+const (
+	GemSourceUserEvaluate = "user_evaluate"
+	GemSourceManual       = "manual"
+	GemSourceRedemption   = "redemption"
+)
+
+const GemKindSpend = "spend"
+
+type GemAdjustment struct {
+	UserId         *uint64 `json:"userId" validate:"required"`
+	Amount         *int64  `json:"amount" validate:"required,ne=0"`
+	Reason         *string `json:"reason" validate:"required,max=1000"`
+	IdempotencyKey *string `json:"idempotencyKey" validate:"required,max=200"`
+}
+
+type GemEntryInfo struct {
+	Id         *uint64    `json:"id"`
+	Kind       *string    `json:"kind"`
+	Amount     *int64     `json:"amount"`
+	SourceType *string    `json:"sourceType"`
+	SourceId   *uint64    `json:"sourceId"`
+	CourseId   *uint64    `json:"courseId"`
+	CourseName *string    `json:"courseName"`
+	Reason     *string    `json:"reason"`
+	CreatedAt  *time.Time `json:"createdAt"`
+}
+
+type GemLedger struct {
+	Balance int64           `json:"balance"`
+	Entries []*GemEntryInfo `json:"entries"`
+}
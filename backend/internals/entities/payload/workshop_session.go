@@ -0,0 +1,63 @@
+package payload
+
+import "time"
+
+type SessionIdParam struct {
+	SessionId *uint64 `param:"sessionId"`
+}
+
+// WorkshopSessionQuery filters upcoming sessions by course or by the field type of their course.
+type WorkshopSessionQuery struct {
+	CourseId *uint64 `query:"courseId"`
+	FieldId  *uint64 `query:"fieldId"`
+}
+
+type CreateWorkshopSession struct {
+	CourseId  *uint64    `json:"courseId" validate:"required"`
+	Title     *string    `json:"title" validate:"required"`
+	StartsAt  *time.Time `json:"startsAt" validate:"required"`
+	EndsAt    *time.Time `json:"endsAt" validate:"required"`
+	Room      *string    `json:"room"`
+	OnlineUrl *string    `json:"onlineUrl" validate:"omitempty,url"`
+	Capacity  *int       `json:"capacity" validate:"required,min=1"`
+}
+
+type WorkshopSessionInfo struct {
+	SessionId  *uint64    `json:"sessionId"`
+	CourseId   *uint64    `json:"courseId"`
+	CourseName *string    `json:"courseName"`
+	Title      *string    `json:"title"`
+	StartsAt   *time.Time `json:"startsAt"`
+	EndsAt     *time.Time `json:"endsAt"`
+	Room       *string    `json:"room"`
+	OnlineUrl  *string    `json:"onlineUrl"`
+	Capacity   *int       `json:"capacity"`
+	Registered *int64     `json:"registered"`
+	Waitlisted *int64     `json:"waitlisted"`
+	SeatsLeft  *int64     `json:"seatsLeft"`
+}
+
+type SessionRegistrationInfo struct {
+	RegistrationId   *uint64              `json:"registrationId"`
+	Status           *string              `json:"status"`
+	WaitlistPosition *int64               `json:"waitlistPosition"`
+	RegisteredAt     *time.Time           `json:"registeredAt"`
+	Session          *WorkshopSessionInfo `json:"session"`
+}
+
+// CheckInCode is displayed by the instructor, it is only accepted until ExpiresAt.
+type CheckInCode struct {
+	SessionId *uint64    `json:"sessionId"`
+	Code      *string    `json:"code"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+type CheckInBody struct {
+	Code *string `json:"code" validate:"required"`
+}
+
+type AttendanceInfo struct {
+	SessionId   *uint64    `json:"sessionId"`
+	Title       *string    `json:"title"`
+	CheckedInAt *time.Time `json:"checkedInAt"`
+}
@@ -0,0 +1,82 @@
+package payload
+
+import "time"
+
+// Verbs of the statements, from the ADL vocabulary at http://adlnet.gov/expapi/verbs/.
+const (
+	XapiVerbExperienced = "experienced"
+	XapiVerbAttempted   = "attempted"
+	XapiVerbPassed      = "passed"
+	XapiVerbFailed      = "failed"
+	XapiVerbCompleted   = "completed"
+	XapiVerbCommented   = "commented"
+)
+
+// Activity types of the objects, from the ADL vocabulary at http://adlnet.gov/expapi/activities/.
+const (
+	XapiActivityCourse      = "http://adlnet.gov/expapi/activities/course"
+	XapiActivityLesson      = "http://adlnet.gov/expapi/activities/lesson"
+	XapiActivityInteraction = "http://adlnet.gov/expapi/activities/cmi.interaction"
+)
+
+// XapiStatement is a statement of the xAPI 1.0.3 specification, with the parts the platform uses.
+type XapiStatement struct {
+	Id        string       `json:"id"`
+	Actor     *XapiActor   `json:"actor"`
+	Verb      *XapiVerb    `json:"verb"`
+	Object    *XapiObject  `json:"object"`
+	Result    *XapiResult  `json:"result,omitempty"`
+	Context   *XapiContext `json:"context,omitempty"`
+	Timestamp time.Time    `json:"timestamp"`
+}
+
+type XapiActor struct {
+	ObjectType string       `json:"objectType"`
+	Name       string       `json:"name,omitempty"`
+	Mbox       string       `json:"mbox,omitempty"`
+	Account    *XapiAccount `json:"account,omitempty"` // identifies users without an email
+}
+
+type XapiAccount struct {
+	HomePage string `json:"homePage"`
+	Name     string `json:"name"`
+}
+
+type XapiVerb struct {
+	Id      string            `json:"id"`
+	Display map[string]string `json:"display"`
+}
+
+type XapiObject struct {
+	ObjectType string                  `json:"objectType"`
+	Id         string                  `json:"id"`
+	Definition *XapiActivityDefinition `json:"definition,omitempty"`
+}
+
+type XapiActivityDefinition struct {
+	Name map[string]string `json:"name,omitempty"`
+	Type string            `json:"type"`
+}
+
+type XapiResult struct {
+	Success    *bool   `json:"success,omitempty"`
+	Completion *bool   `json:"completion,omitempty"`
+	Response   *string `json:"response,omitempty"`
+}
+
+type XapiContext struct {
+	Platform          string                 `json:"platform"`
+	ContextActivities *XapiContextActivities `json:"contextActivities,omitempty"`
+}
+
+type XapiContextActivities struct {
+	Parent   []*XapiObject `json:"parent,omitempty"`
+	Grouping []*XapiObject `json:"grouping,omitempty"`
+}
+
+// XapiReplay counts the statements added to the outbox by a replay of the history.
+type XapiReplay struct {
+	UserEvaluates  int64
+	UserActivities int64
+	Statements     int64
+}
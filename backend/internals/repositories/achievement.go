@@ -0,0 +1,9 @@
+package repositories
+
+import "backend/internals/db/models"
+
+type AchievementRepository interface {
+	CountMetric(metric string, userId uint64) (int64, error)
+	FindUserAchievements(userId uint64) ([]*models.UserAchievement, error)
+	CreateUserAchievement(achievement *models.UserAchievement) (bool, error)
+}
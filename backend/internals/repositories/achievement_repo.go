@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type achievementRepo struct {
+	db *gorm.DB
+}
+
+func NewAchievementRepository(db *gorm.DB) AchievementRepository {
+	return &achievementRepo{
+		db: db,
+	}
+}
+
+// CountMetric counts what an achievement rule measures for the user.
+func (r *achievementRepo) CountMetric(metric string, userId uint64) (int64, error) {
+	var query *gorm.DB
+	switch metric {
+	case payload.AchievementMetricCommentsCreated:
+		query = r.db.Table("step_comments").
+			Select("COUNT(*)").
+			Where("step_comments.user_id = ?", userId)
+	case payload.AchievementMetricUpVotesReceived:
+		// upvoting your own comment does not count
+		query = r.db.Table("step_comment_upvotes").
+			Select("COUNT(*)").
+			Joins("JOIN step_comments ON step_comments.id = step_comment_upvotes.step_comment_id").
+			Where("step_comments.user_id = ? AND step_comment_upvotes.user_id <> ?", userId, userId)
+	case payload.AchievementMetricModulesFinishedInDay:
+		// modules whose evaluations were all passed within a day of the first submission
+		modules := r.db.Table("user_evaluates").
+			Select("steps.module_id").
+			Joins("JOIN step_evaluates ON step_evaluates.id = user_evaluates.step_evaluate_id").
+			Joins("JOIN steps ON steps.id = step_evaluates.step_id").
+			Where("user_evaluates.user_id = ? AND user_evaluates.pass = ?", userId, true).
+			Group("steps.module_id").
+			Having("COUNT(DISTINCT user_evaluates.step_evaluate_id) = (SELECT COUNT(*) FROM step_evaluates JOIN steps module_steps ON module_steps.id = step_evaluates.step_id WHERE module_steps.module_id = steps.module_id)").
+			Having("MAX(user_evaluates.updated_at) - MIN(user_evaluates.created_at) <= INTERVAL '1 day'")
+		query = r.db.Table("(?) AS modules", modules).Select("COUNT(*)")
+	case payload.AchievementMetricFieldsCompleted:
+		// fields with evaluations where every course is complete, counted like course completion
+		courses := r.db.Table("courses").
+			Select("courses.field_id, "+
+				"(SELECT COUNT(*) FROM step_evaluates WHERE step_evaluates.step_id IN (SELECT steps.id FROM steps JOIN course_contents ON course_contents.module_id = steps.module_id WHERE course_contents.course_id = courses.id)) AS required, "+
+				"(SELECT COUNT(DISTINCT user_evaluates.step_evaluate_id) FROM user_evaluates JOIN step_evaluates ON step_evaluates.id = user_evaluates.step_evaluate_id "+
+				"WHERE step_evaluates.step_id IN (SELECT steps.id FROM steps JOIN course_contents ON course_contents.module_id = steps.module_id WHERE course_contents.course_id = courses.id) "+
+				"AND user_evaluates.user_id = ? AND (user_evaluates.course_id = courses.id OR user_evaluates.course_id IS NULL) AND user_evaluates.pass = TRUE) AS passed", userId)
+		fields := r.db.Table("(?) AS courses", courses).
+			Select("courses.field_id").
+			Group("courses.field_id").
+			Having("SUM(courses.required) > 0 AND BOOL_AND(courses.passed >= courses.required)")
+		query = r.db.Table("(?) AS fields", fields).Select("COUNT(*)")
+	case payload.AchievementMetricStepsVisited:
+		query = r.db.Table("user_activities").
+			Select("COUNT(DISTINCT user_activities.step_id)").
+			Where("user_activities.user_id = ?", userId)
+	default:
+		return 0, fmt.Errorf("unknown achievement metric %s", metric)
+	}
+
+	var count int64
+	if err := query.Scan(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *achievementRepo) FindUserAchievements(userId uint64) ([]*models.UserAchievement, error) {
+	var achievements []*models.UserAchievement
+
+	result := r.db.Where("user_id = ?", userId).Order("earned_at ASC").Find(&achievements)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return achievements, nil
+}
+
+// CreateUserAchievement records the achievement unless the user already earned it, reporting whether it was recorded.
+func (r *achievementRepo) CreateUserAchievement(achievement *models.UserAchievement) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "code"}},
+		DoNothing: true,
+	}).Create(achievement)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,8 @@
+package repositories
+
+import "backend/internals/entities/payload"
+
+type AnalyticsRepository interface {
+	FindStepFunnel(courseId uint64, moduleId *uint64) ([]*payload.StepFunnel, error)
+	RefreshStepFunnel() error
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"backend/internals/entities/payload"
+
+	"gorm.io/gorm"
+)
+
+type analyticsRepo struct {
+	db *gorm.DB
+}
+
+func NewAnalyticsRepository(db *gorm.DB) AnalyticsRepository {
+	return &analyticsRepo{
+		db: db,
+	}
+}
+
+// FindStepFunnel returns the funnel of the steps of the course, or of one of its modules, in the
+// order of the course content and then of the steps in their module.
+func (r *analyticsRepo) FindStepFunnel(courseId uint64, moduleId *uint64) ([]*payload.StepFunnel, error) {
+	var steps []*payload.StepFunnel
+
+	query := r.db.Table("step_funnel_stats").
+		Select("step_funnel_stats.step_id, steps.title AS step_title, step_funnel_stats.module_id, modules.title AS module_title, "+
+			"step_funnel_stats.evaluations, step_funnel_stats.reached, step_funnel_stats.passed, step_funnel_stats.median_pass_seconds, "+
+			"step_funnel_stats.submissions, step_funnel_stats.attempts, step_funnel_stats.passed_submissions, "+
+			"step_funnel_stats.pending_submissions, step_funnel_stats.refreshed_at").
+		Joins("JOIN steps ON steps.id = step_funnel_stats.step_id").
+		Joins("JOIN modules ON modules.id = step_funnel_stats.module_id").
+		Joins("JOIN (SELECT module_id, MIN(\"order\") AS position FROM course_contents WHERE course_id = ? GROUP BY module_id) AS contents "+
+			"ON contents.module_id = step_funnel_stats.module_id", courseId).
+		Where("step_funnel_stats.course_id = ?", courseId)
+	if moduleId != nil {
+		query = query.Where("step_funnel_stats.module_id = ?", *moduleId)
+	}
+
+	result := query.Order("contents.position ASC, step_funnel_stats.step_id ASC").Scan(&steps)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return steps, nil
+}
+
+// RefreshStepFunnel recomputes the funnel from the activities and evaluations. Reports keep
+// reading the previous figures meanwhile.
+func (r *analyticsRepo) RefreshStepFunnel() error {
+	return r.db.Exec("REFRESH MATERIALIZED VIEW CONCURRENTLY step_funnel_stats").Error
+}
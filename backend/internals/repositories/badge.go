@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+)
+
+type BadgeRepository interface {
+	CreateBadgeClass(badgeClass *models.BadgeClass) error
+	FindBadgeClassById(badgeClassId uint64) (*models.BadgeClass, error)
+	FindBadgeClass(courseId uint64, moduleId *uint64) (*models.BadgeClass, error)
+	CreateAssertion(assertion *models.BadgeAssertion) error
+	FindAssertionByUid(uid string) (*models.BadgeAssertion, error)
+	FindAssertion(badgeClassId uint64, userId uint64) (*models.BadgeAssertion, error)
+	FindAssertionsByUserId(userId uint64) ([]*models.BadgeAssertion, error)
+	FindRevokedAssertions() ([]*models.BadgeAssertion, error)
+	RevokeAssertion(assertion *models.BadgeAssertion) error
+	FindCompletion(userId uint64, courseId uint64, moduleId *uint64) (*payload.CourseCompletion, error)
+}
@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"errors"
+	"gorm.io/gorm"
+)
+
+var ErrBadgeAlreadyRevoked = errors.New("badge assertion is already revoked")
+
+type badgeRepo struct {
+	db *gorm.DB
+}
+
+func NewBadgeRepository(db *gorm.DB) BadgeRepository {
+	return &badgeRepo{
+		db: db,
+	}
+}
+
+func (r *badgeRepo) CreateBadgeClass(badgeClass *models.BadgeClass) error {
+	return r.db.Create(badgeClass).Error
+}
+
+func (r *badgeRepo) FindBadgeClassById(badgeClassId uint64) (*models.BadgeClass, error) {
+	badgeClass := new(models.BadgeClass)
+
+	result := r.db.Where("id = ?", badgeClassId).Limit(1).Find(&badgeClass)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return badgeClass, nil
+}
+
+// FindBadgeClass returns the badge class of the course, or of the module of the course when
+// moduleId is given.
+func (r *badgeRepo) FindBadgeClass(courseId uint64, moduleId *uint64) (*models.BadgeClass, error) {
+	badgeClass := new(models.BadgeClass)
+
+	query := r.db.Where("course_id = ?", courseId)
+	if moduleId != nil {
+		query = query.Where("module_id = ?", *moduleId)
+	} else {
+		query = query.Where("module_id IS NULL")
+	}
+
+	result := query.Limit(1).Find(&badgeClass)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return badgeClass, nil
+}
+
+func (r *badgeRepo) CreateAssertion(assertion *models.BadgeAssertion) error {
+	return r.db.Create(assertion).Error
+}
+
+func (r *badgeRepo) FindAssertionByUid(uid string) (*models.BadgeAssertion, error) {
+	assertion := new(models.BadgeAssertion)
+
+	result := r.db.Preload("BadgeClass").Preload("User").Where("uid = ?", uid).Limit(1).Find(&assertion)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return assertion, nil
+}
+
+func (r *badgeRepo) FindAssertion(badgeClassId uint64, userId uint64) (*models.BadgeAssertion, error) {
+	assertion := new(models.BadgeAssertion)
+
+	result := r.db.Preload("BadgeClass").Where("badge_class_id = ? AND user_id = ?", badgeClassId, userId).Limit(1).Find(&assertion)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return assertion, nil
+}
+
+func (r *badgeRepo) FindAssertionsByUserId(userId uint64) ([]*models.BadgeAssertion, error) {
+	var assertions []*models.BadgeAssertion
+
+	result := r.db.Preload("BadgeClass").Where("user_id = ?", userId).Order("issued_on DESC").Find(&assertions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return assertions, nil
+}
+
+func (r *badgeRepo) FindRevokedAssertions() ([]*models.BadgeAssertion, error) {
+	var assertions []*models.BadgeAssertion
+
+	result := r.db.Where("revoked_at IS NOT NULL").Order("revoked_at ASC").Find(&assertions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return assertions, nil
+}
+
+// RevokeAssertion records the revocation of the assertion, unless it was revoked already.
+func (r *badgeRepo) RevokeAssertion(assertion *models.BadgeAssertion) error {
+	result := r.db.Model(&models.BadgeAssertion{}).
+		Where("id = ? AND revoked_at IS NULL", *assertion.Id).
+		Updates(map[string]any{
+			"revoked_at":        assertion.RevokedAt,
+			"revocation_reason": assertion.RevocationReason,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrBadgeAlreadyRevoked
+	}
+
+	return nil
+}
+
+func (r *badgeRepo) FindCompletion(userId uint64, courseId uint64, moduleId *uint64) (*payload.CourseCompletion, error) {
+	return findCompletion(r.db, userId, courseId, moduleId)
+}
@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"backend/internals/db/models"
+	"time"
+)
+
+type CalendarRepository interface {
+	CreateEvent(event *models.CalendarEvent) error
+	FindCourseEvents(courseId uint64, from time.Time) ([]*models.CalendarEvent, error)
+	FindUserEvents(userId uint64, from time.Time) ([]*models.CalendarEvent, error)
+	SaveToken(token *models.CalendarToken) error
+	DeleteToken(userId uint64) error
+	FindTokenByHash(tokenHash string) (*models.CalendarToken, error)
+}
@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"backend/internals/db/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type calendarRepo struct {
+	db *gorm.DB
+}
+
+func NewCalendarRepository(db *gorm.DB) CalendarRepository {
+	return &calendarRepo{
+		db: db,
+	}
+}
+
+func (r *calendarRepo) CreateEvent(event *models.CalendarEvent) error {
+	return r.db.Create(event).Error
+}
+
+// FindCourseEvents returns the events of the course shown to all of its learners, which did not end before from.
+func (r *calendarRepo) FindCourseEvents(courseId uint64, from time.Time) ([]*models.CalendarEvent, error) {
+	var events []*models.CalendarEvent
+
+	result := r.db.Preload("Course").
+		Where("course_id = ? AND cohort_id IS NULL", courseId).
+		Where("COALESCE(ends_at, starts_at) > ?", from).
+		Order("starts_at ASC, id ASC").
+		Find(&events)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return events, nil
+}
+
+// FindUserEvents returns the events of the courses the user is enrolled in and of the cohorts the
+// user is a member of, which did not end before from.
+func (r *calendarRepo) FindUserEvents(userId uint64, from time.Time) ([]*models.CalendarEvent, error) {
+	var events []*models.CalendarEvent
+
+	enrolled := r.db.Model(&models.Enroll{}).Select("course_id").Where("user_id = ?", userId)
+	member := r.db.Model(&models.CohortMember{}).Select("cohort_id").Where("user_id = ?", userId)
+
+	result := r.db.Preload("Course").Preload("Cohort").
+		Where(r.db.Where("cohort_id IS NULL AND course_id IN (?)", enrolled).Or("cohort_id IN (?)", member)).
+		Where("COALESCE(ends_at, starts_at) > ?", from).
+		Order("starts_at ASC, id ASC").
+		Find(&events)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return events, nil
+}
+
+// SaveToken replaces the token of the user, so the previous feed URL stops working.
+func (r *calendarRepo) SaveToken(token *models.CalendarToken) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"token_hash", "created_at", "updated_at"}),
+	}).Create(token).Error
+}
+
+func (r *calendarRepo) DeleteToken(userId uint64) error {
+	return r.db.Where("user_id = ?", userId).Delete(&models.CalendarToken{}).Error
+}
+
+func (r *calendarRepo) FindTokenByHash(tokenHash string) (*models.CalendarToken, error) {
+	token := new(models.CalendarToken)
+
+	result := r.db.Where("token_hash = ?", tokenHash).Limit(1).Find(&token)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return token, nil
+}
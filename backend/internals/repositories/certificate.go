@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+)
+
+type CertificateRepository interface {
+	CreateCertificate(certificate *models.Certificate) (bool, error)
+	DeleteCertificate(certificateId uint64) error
+	FindCertificateByCode(code string) (*models.Certificate, error)
+	FindCertificate(userId uint64, courseId uint64) (*models.Certificate, error)
+	FindCertificatesByUserId(userId uint64) ([]*models.Certificate, error)
+	FindCourseCompletion(userId uint64, courseId uint64) (*payload.CourseCompletion, error)
+}
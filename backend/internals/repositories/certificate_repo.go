@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type certificateRepo struct {
+	db *gorm.DB
+}
+
+func NewCertificateRepository(db *gorm.DB) CertificateRepository {
+	return &certificateRepo{
+		db: db,
+	}
+}
+
+// CreateCertificate adds the certificate and tells whether it was added, the course was already
+// certified to the user otherwise.
+func (r *certificateRepo) CreateCertificate(certificate *models.Certificate) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "course_id"}},
+		DoNothing: true,
+	}).Create(certificate)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r *certificateRepo) DeleteCertificate(certificateId uint64) error {
+	return r.db.Delete(&models.Certificate{}, certificateId).Error
+}
+
+func (r *certificateRepo) FindCertificateByCode(code string) (*models.Certificate, error) {
+	certificate := new(models.Certificate)
+
+	result := r.db.Where("code = ?", code).Limit(1).Find(&certificate)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return certificate, nil
+}
+
+func (r *certificateRepo) FindCertificate(userId uint64, courseId uint64) (*models.Certificate, error) {
+	certificate := new(models.Certificate)
+
+	result := r.db.Where("user_id = ? AND course_id = ?", userId, courseId).Limit(1).Find(&certificate)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return certificate, nil
+}
+
+func (r *certificateRepo) FindCertificatesByUserId(userId uint64) ([]*models.Certificate, error) {
+	var certificates []*models.Certificate
+
+	result := r.db.Where("user_id = ?", userId).Order("completed_at DESC").Find(&certificates)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return certificates, nil
+}
+
+// FindCourseCompletion counts the evaluations of the steps of the course against those the user
+// passed.
+func (r *certificateRepo) FindCourseCompletion(userId uint64, courseId uint64) (*payload.CourseCompletion, error) {
+	return findCompletion(r.db, userId, courseId, nil)
+}
+
+// findCompletion counts the evaluations of the steps of the course, or of one of its modules,
+// against those the user passed. Unattributed evaluations belong to modules shared by several
+// courses and count for each of them, like they do for progress.
+func findCompletion(db *gorm.DB, userId uint64, courseId uint64, moduleId *uint64) (*payload.CourseCompletion, error) {
+	completion := new(payload.CourseCompletion)
+
+	steps := db.Table("steps").
+		Select("steps.id").
+		Joins("JOIN course_contents ON course_contents.module_id = steps.module_id").
+		Where("course_contents.course_id = ?", courseId)
+	if moduleId != nil {
+		steps = steps.Where("steps.module_id = ?", *moduleId)
+	}
+
+	passed := db.Table("user_evaluates").
+		Select("step_evaluate_id, MIN(updated_at) AS passed_at").
+		Where("user_id = ? AND (course_id = ? OR course_id IS NULL) AND pass = TRUE", userId, courseId).
+		Group("step_evaluate_id")
+
+	err := db.Table("step_evaluates").
+		Select("COUNT(*) AS required, COUNT(passed.step_evaluate_id) AS passed, MAX(passed.passed_at) AS completed_at").
+		Joins("LEFT JOIN (?) passed ON passed.step_evaluate_id = step_evaluates.id", passed).
+		Where("step_evaluates.step_id IN (?)", steps).
+		Scan(completion).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return completion, nil
+}
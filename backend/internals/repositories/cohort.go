@@ -0,0 +1,13 @@
+package repositories
+
+import "backend/internals/db/models"
+
+type CohortRepository interface {
+	CreateCohort(cohort *models.Cohort) error
+	UpdateCohort(cohort *models.Cohort) error
+	FindCohortById(cohortId uint64) (*models.Cohort, error)
+	SaveMember(member *models.CohortMember) error
+	FindMember(cohortId uint64, userId uint64) (*models.CohortMember, error)
+	FindLearnerCohort(userId uint64, courseId uint64) (*models.Cohort, error)
+	IsCourseInstructor(userId uint64, courseId uint64) (bool, error)
+}
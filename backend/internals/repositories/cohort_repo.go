@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"backend/internals/db/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type cohortRepo struct {
+	db *gorm.DB
+}
+
+func NewCohortRepository(db *gorm.DB) CohortRepository {
+	return &cohortRepo{
+		db: db,
+	}
+}
+
+func (r *cohortRepo) CreateCohort(cohort *models.Cohort) error {
+	return r.db.Create(cohort).Error
+}
+
+func (r *cohortRepo) UpdateCohort(cohort *models.Cohort) error {
+	return r.db.Omit(clause.Associations).Save(cohort).Error
+}
+
+func (r *cohortRepo) FindCohortById(cohortId uint64) (*models.Cohort, error) {
+	cohort := new(models.Cohort)
+
+	result := r.db.Preload("Course").Where("id = ?", cohortId).Limit(1).Find(&cohort)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return cohort, nil
+}
+
+// SaveMember adds the user to the cohort, or changes the role of an existing member.
+func (r *cohortRepo) SaveMember(member *models.CohortMember) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "cohort_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role", "updated_at"}),
+	}).Create(member).Error
+}
+
+func (r *cohortRepo) FindMember(cohortId uint64, userId uint64) (*models.CohortMember, error) {
+	member := new(models.CohortMember)
+
+	result := r.db.Where("cohort_id = ? AND user_id = ?", cohortId, userId).Limit(1).Find(&member)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return member, nil
+}
+
+func (r *cohortRepo) FindLearnerCohort(userId uint64, courseId uint64) (*models.Cohort, error) {
+	cohort := new(models.Cohort)
+
+	result := r.db.
+		Joins("JOIN cohort_members ON cohort_members.cohort_id = cohorts.id").
+		Where("cohort_members.user_id = ? AND cohort_members.role = ? AND cohorts.course_id = ?", userId, "learner", courseId).
+		Order("cohorts.id ASC").
+		Limit(1).
+		Find(&cohort)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return cohort, nil
+}
+
+// IsCourseInstructor tells whether the user is an instructor of a cohort taking the course.
+func (r *cohortRepo) IsCourseInstructor(userId uint64, courseId uint64) (bool, error) {
+	var count int64
+
+	result := r.db.Model(&models.CohortMember{}).
+		Joins("JOIN cohorts ON cohorts.id = cohort_members.cohort_id").
+		Where("cohort_members.user_id = ? AND cohort_members.role = ? AND cohorts.course_id = ?", userId, "instructor", courseId).
+		Count(&count)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return count > 0, nil
+}
@@ -0,0 +1,12 @@
+package repositories
+
+import (
+	"backend/internals/content"
+	"backend/internals/db/models"
+)
+
+type ContentImportRepository interface {
+	SaveModule(document *content.ModuleDocument) (*models.Module, error)
+	SaveCourse(document *content.CourseDocument) (*models.Course, error)
+	SaveCourseBundle(course *content.CourseDocument, modules []*content.ModuleDocument) (*models.Course, []*models.Module, error)
+}
@@ -0,0 +1,229 @@
+package repositories
+
+import (
+	"backend/internals/content"
+	"backend/internals/db/models"
+	"backend/internals/utils"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+)
+
+type contentImportRepo struct {
+	db *gorm.DB
+}
+
+func NewContentImportRepository(db *gorm.DB) ContentImportRepository {
+	return &contentImportRepo{
+		db: db,
+	}
+}
+
+func (r *contentImportRepo) SaveModule(document *content.ModuleDocument) (*models.Module, error) {
+	var module *models.Module
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		module, err = saveModule(tx, document)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return module, nil
+}
+
+func (r *contentImportRepo) SaveCourse(document *content.CourseDocument) (*models.Course, error) {
+	var course *models.Course
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		course, err = saveCourse(tx, document)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return course, nil
+}
+
+func (r *contentImportRepo) SaveCourseBundle(courseDocument *content.CourseDocument, moduleDocuments []*content.ModuleDocument) (*models.Course, []*models.Module, error) {
+	var course *models.Course
+	modules := make([]*models.Module, 0, len(moduleDocuments))
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// modules first, the course contents reference them by title
+		for _, moduleDocument := range moduleDocuments {
+			module, err := saveModule(tx, moduleDocument)
+			if err != nil {
+				return err
+			}
+			modules = append(modules, module)
+		}
+
+		var err error
+		course, err = saveCourse(tx, courseDocument)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return course, modules, nil
+}
+
+func saveModule(tx *gorm.DB, document *content.ModuleDocument) (*models.Module, error) {
+	module := new(models.Module)
+
+	// find or create module
+	result := tx.Where("title = ?", document.Title).First(&module)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		module = &models.Module{
+			Title: utils.Ptr(document.Title),
+		}
+	} else if result.Error != nil {
+		return nil, result.Error
+	}
+
+	// update module metadata
+	module.ImageUrl = utils.Ptr(document.ImageUrl)
+	module.Description = utils.Ptr(document.Description)
+	if err := tx.Save(module).Error; err != nil {
+		return nil, fmt.Errorf("failed to save module: %w", err)
+	}
+
+	for _, stepDocument := range document.Steps {
+		if err := saveStep(tx, module.Id, stepDocument); err != nil {
+			return nil, err
+		}
+	}
+
+	return module, nil
+}
+
+func saveStep(tx *gorm.DB, moduleId *uint64, document *content.StepDocument) error {
+	step := new(models.Step)
+
+	// find or create step
+	result := tx.Where("module_id = ? AND title = ?", moduleId, document.Title).First(&step)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		step = &models.Step{
+			ModuleId: moduleId,
+			Title:    utils.Ptr(document.Title),
+		}
+	} else if result.Error != nil {
+		return result.Error
+	}
+
+	// update step content
+	step.Description = utils.Ptr(document.Description)
+	step.Content = utils.Ptr(document.Content)
+	step.Outcome = utils.Ptr(document.Outcome)
+	step.Check = utils.Ptr(document.Check)
+	step.Error = utils.Ptr(document.Error)
+	if err := tx.Save(step).Error; err != nil {
+		return fmt.Errorf("failed to save step %s: %w", document.Title, err)
+	}
+
+	for _, evalDocument := range document.Evaluations {
+		// check if an entry with the same Order exists
+		evaluation := new(models.StepEvaluate)
+		result := tx.Where("step_id = ? AND \"order\" = ?", step.Id, evalDocument.Order).First(&evaluation)
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			evaluation = &models.StepEvaluate{
+				StepId: step.Id,
+				Order:  utils.Ptr(evalDocument.Order),
+			}
+		} else if result.Error != nil {
+			return result.Error
+		}
+
+		evaluation.Question = utils.Ptr(evalDocument.Question)
+		evaluation.Type = utils.Ptr(evalDocument.Type)
+		evaluation.Instruction = utils.Ptr(evalDocument.Instruction)
+		evaluation.Gem = utils.Ptr(evalDocument.Gem)
+		if err := tx.Save(evaluation).Error; err != nil {
+			return fmt.Errorf("failed to save evaluation %d of step %s: %w", evalDocument.Order, document.Title, err)
+		}
+	}
+
+	return nil
+}
+
+func saveCourse(tx *gorm.DB, document *content.CourseDocument) (*models.Course, error) {
+	field := new(models.FieldType)
+	if err := tx.Where("name = ?", document.FieldName).First(&field).Error; err != nil {
+		return nil, fmt.Errorf("field not found: %s: %w", document.FieldName, err)
+	}
+
+	// find or create course
+	course := new(models.Course)
+	result := tx.Where("name = ?", document.Name).First(&course)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		course = &models.Course{
+			Name:    utils.Ptr(document.Name),
+			FieldId: field.Id,
+		}
+		if err := tx.Create(course).Error; err != nil {
+			return nil, fmt.Errorf("failed to create course: %w", err)
+		}
+	} else if result.Error != nil {
+		return nil, result.Error
+	}
+
+	for i, contentDocument := range document.Contents {
+		if err := saveCourseContent(tx, course.Id, int64(i+1), contentDocument); err != nil {
+			return nil, err
+		}
+	}
+
+	// drop contents removed from the document
+	if err := tx.Where("course_id = ? AND \"order\" > ?", course.Id, len(document.Contents)).Delete(&models.CourseContent{}).Error; err != nil {
+		return nil, fmt.Errorf("failed to remove stale course content: %w", err)
+	}
+
+	return course, nil
+}
+
+func saveCourseContent(tx *gorm.DB, courseId *uint64, order int64, document *content.CourseContentDocument) error {
+	var moduleId *uint64
+	var text *string
+	if document.Type == "module" {
+		module := new(models.Module)
+		if err := tx.Where("title = ?", document.ModuleTitle).First(&module).Error; err != nil {
+			return fmt.Errorf("module not found: %s: %w", document.ModuleTitle, err)
+		}
+		moduleId = module.Id
+	} else {
+		text = utils.Ptr(document.Text)
+	}
+
+	courseContent := new(models.CourseContent)
+	result := tx.Where("course_id = ? AND \"order\" = ?", courseId, order).First(&courseContent)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		courseContent = &models.CourseContent{
+			CourseId: courseId,
+			Order:    utils.Ptr(order),
+			Type:     utils.Ptr(document.Type),
+			Text:     text,
+			ModuleId: moduleId,
+		}
+		if err := tx.Create(courseContent).Error; err != nil {
+			return fmt.Errorf("failed to create course content: %w", err)
+		}
+		return nil
+	} else if result.Error != nil {
+		return result.Error
+	}
+
+	courseContent.Type = utils.Ptr(document.Type)
+	courseContent.Text = text
+	courseContent.ModuleId = moduleId
+	if err := tx.Save(courseContent).Error; err != nil {
+		return fmt.Errorf("failed to update course content: %w", err)
+	}
+
+	return nil
+}
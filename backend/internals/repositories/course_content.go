@@ -1,5 +1,9 @@
 package repositories
 
+import "backend/internals/db/models"
+
 type CourseContentRepository interface {
-	GetCourseIdByModuleId(moduleId *uint64) (*uint64, error)
+	GetCourseIdsByModuleId(moduleId *uint64) ([]uint64, error)
+	FindCourseContentByCourseIdAndModuleId(courseId *uint64, moduleId *uint64) (*models.CourseContent, error)
+	FindCourseContentByCourseIdAndOrder(courseId uint64, order int64) (*models.CourseContent, error)
 }
@@ -15,8 +15,40 @@ func NewCourseContentRepository(db *gorm.DB) CourseContentRepository {
 	}
 }
 
-func (r *courseContentRepo) GetCourseIdByModuleId(moduleId *uint64) (*uint64, error) {
+func (r *courseContentRepo) GetCourseIdsByModuleId(moduleId *uint64) ([]uint64, error) {
+	courseIds := make([]uint64, 0)
+	result := r.db.Model(&models.CourseContent{}).
+		Where("module_id = ?", moduleId).
+		Distinct().
+		Order("course_id").
+		Pluck("course_id", &courseIds)
+	return courseIds, result.Error
+}
+
+func (r *courseContentRepo) FindCourseContentByCourseIdAndModuleId(courseId *uint64, moduleId *uint64) (*models.CourseContent, error) {
 	courseContent := new(models.CourseContent)
-	result := r.db.First(&courseContent, "module_id = ? ", moduleId)
-	return courseContent.CourseId, result.Error
+	result := r.db.Preload("Course").Where("course_id = ? AND module_id = ?", courseId, moduleId).Order(`"order" ASC`).Limit(1).Find(&courseContent)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return courseContent, nil
+}
+
+func (r *courseContentRepo) FindCourseContentByCourseIdAndOrder(courseId uint64, order int64) (*models.CourseContent, error) {
+	courseContent := new(models.CourseContent)
+	result := r.db.Where(`course_id = ? AND "order" = ?`, courseId, order).Limit(1).Find(&courseContent)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return courseContent, nil
 }
@@ -39,26 +39,25 @@ func (r *courseRepository) GetCurrentCourse(userID uint) (*models.Course, error)
 		return nil, nil
 	}
 
-	var step models.Step
-	result = r.db.First(&step, "id = ?", userActivity.StepId)
-	if result.Error != nil {
-		return nil, result.Error
-	}
-
-	var module models.Module
-	result = r.db.First(&module, "id = ?", step.ModuleId)
-	if result.Error != nil {
-		return nil, result.Error
-	}
+	// activities recorded before course context was tracked fall back to the module's first course
+	courseId := userActivity.CourseId
+	if courseId == nil {
+		var step models.Step
+		result = r.db.First(&step, "id = ?", userActivity.StepId)
+		if result.Error != nil {
+			return nil, result.Error
+		}
 
-	var courseContent models.CourseContent
-	result = r.db.First(&courseContent, "module_id = ?", module.Id)
-	if result.Error != nil {
-		return nil, result.Error
+		var courseContent models.CourseContent
+		result = r.db.Order("course_id").First(&courseContent, "module_id = ?", step.ModuleId)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		courseId = courseContent.CourseId
 	}
 
 	var course models.Course
-	result = r.db.First(&course, "id = ?", courseContent.CourseId)
+	result = r.db.First(&course, "id = ?", courseId)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -0,0 +1,12 @@
+package repositories
+
+import "backend/internals/db/models"
+
+type GemRepository interface {
+	AppendAdjustment(entry *models.GemEntry) (bool, error)
+	FindEntryByIdempotencyKey(key string) (*models.GemEntry, error)
+	SumByUserId(userId uint64) (int64, error)
+	SumBySourceIds(userId uint64, sourceType string, sourceIds []uint64) (int64, error)
+	FindEntriesByUserId(userId uint64, limit int) ([]*models.GemEntry, error)
+	BackfillUserEvals() (int64, error)
+}
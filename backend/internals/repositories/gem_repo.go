@@ -0,0 +1,200 @@
+package repositories
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/utils"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrGemBalanceNegative = errors.New("gem balance would become negative")
+
+type gemRepo struct {
+	db *gorm.DB
+}
+
+func NewGemRepository(db *gorm.DB) GemRepository {
+	return &gemRepo{
+		db: db,
+	}
+}
+
+// AppendAdjustment writes the entry unless one with its idempotency key exists or it would bring
+// the balance of the user below zero, reporting whether it was written. The leaderboards are
+// updated along with it. The user row is locked like redemptions do, so concurrent adjustments
+// and redemptions see the balance left by each other.
+func (r *gemRepo) AppendAdjustment(entry *models.GemEntry) (bool, error) {
+	created := false
+
+	err := r.db.Transaction(func(tx *gorm.DB) (err error) {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.User{}, *entry.UserId).Error; err != nil {
+			return err
+		}
+
+		if *entry.Amount < 0 {
+			var balance int64
+			if err := tx.Model(&models.GemEntry{}).
+				Select("COALESCE(SUM(amount), 0)").
+				Where("user_id = ?", *entry.UserId).
+				Scan(&balance).Error; err != nil {
+				return err
+			}
+			if balance+*entry.Amount < 0 {
+				return ErrGemBalanceNegative
+			}
+		}
+
+		created, err = appendEntry(tx, entry)
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return created, nil
+}
+
+func appendEntry(tx *gorm.DB, entry *models.GemEntry) (bool, error) {
+	result := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "idempotency_key"}},
+		DoNothing: true,
+	}).Create(entry)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	return true, incrementLeaderboardScores(tx, entry)
+}
+
+// settleUserEval appends the entry bringing the gems of the evaluation to what it is worth now.
+// The row of the evaluation is locked by the transaction saving it, so settlements of the same
+// evaluation follow each other; the key counts the entries written before, so the same state is
+// never settled twice.
+func settleUserEval(tx *gorm.DB, userEval *models.UserEvaluate, worth int64) error {
+	var entries []*models.GemEntry
+	if err := tx.Where("source_type = ? AND source_id = ?", payload.GemSourceUserEvaluate, *userEval.Id).Order("id ASC").Find(&entries).Error; err != nil {
+		return err
+	}
+
+	var current int64
+	for _, entry := range entries {
+		current += *entry.Amount
+	}
+	if worth == current {
+		return nil
+	}
+
+	entry := &models.GemEntry{
+		UserId:         userEval.UserId,
+		Kind:           utils.Ptr("award"),
+		Amount:         utils.Ptr(worth - current),
+		SourceType:     utils.Ptr(payload.GemSourceUserEvaluate),
+		SourceId:       userEval.Id,
+		CourseId:       userEval.CourseId,
+		IdempotencyKey: utils.Ptr(fmt.Sprintf("%s:%d:%d", payload.GemSourceUserEvaluate, *userEval.Id, len(entries))),
+	}
+	if worth < current {
+		entry.Kind = utils.Ptr("revoke")
+		// revoked gems leave the course they were awarded for
+		entry.CourseId = entries[len(entries)-1].CourseId
+	}
+
+	_, err := appendEntry(tx, entry)
+	return err
+}
+
+func (r *gemRepo) FindEntryByIdempotencyKey(key string) (*models.GemEntry, error) {
+	var entry models.GemEntry
+
+	result := r.db.Limit(1).Find(&entry, "idempotency_key = ?", key)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return &entry, nil
+}
+
+func (r *gemRepo) SumByUserId(userId uint64) (int64, error) {
+	var balance int64
+
+	result := r.db.Model(&models.GemEntry{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("user_id = ?", userId).
+		Scan(&balance)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return balance, nil
+}
+
+func (r *gemRepo) SumBySourceIds(userId uint64, sourceType string, sourceIds []uint64) (int64, error) {
+	var total int64
+	if len(sourceIds) == 0 {
+		return 0, nil
+	}
+
+	result := r.db.Model(&models.GemEntry{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("user_id = ? AND source_type = ? AND source_id IN ?", userId, sourceType, sourceIds).
+		Scan(&total)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return total, nil
+}
+
+func (r *gemRepo) FindEntriesByUserId(userId uint64, limit int) ([]*models.GemEntry, error) {
+	var entries []*models.GemEntry
+
+	result := r.db.Preload("Course").Where("user_id = ?", userId).Order("id DESC").Limit(limit).Find(&entries)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return entries, nil
+}
+
+// BackfillUserEvals awards the gems of the passed evaluations recorded before the ledger, attributed
+// to the course they were taken in or else the first course of their module. A step evaluation is
+// awarded once per user and course, from the passed evaluation updated last, as repeated
+// submissions used to add rows. Steps with entries are left alone, so running it again writes
+// nothing.
+func (r *gemRepo) BackfillUserEvals() (int64, error) {
+	result := r.db.Exec(`WITH passed AS (
+			SELECT user_evaluates.id, user_evaluates.user_id, user_evaluates.step_evaluate_id, user_evaluates.updated_at, step_evaluates.gem,
+				COALESCE(user_evaluates.course_id, (SELECT MIN(course_contents.course_id) FROM course_contents WHERE course_contents.module_id = steps.module_id)) AS course_id
+			FROM user_evaluates
+			JOIN step_evaluates ON step_evaluates.id = user_evaluates.step_evaluate_id
+			JOIN steps ON steps.id = step_evaluates.step_id
+			WHERE user_evaluates.pass = ? AND step_evaluates.gem <> 0
+		)
+		INSERT INTO gem_entries (user_id, kind, amount, source_type, source_id, course_id, idempotency_key, reason, created_at)
+		SELECT DISTINCT ON (passed.user_id, passed.step_evaluate_id, passed.course_id)
+			passed.user_id, 'award', passed.gem, 'user_evaluate', passed.id, passed.course_id,
+			'user_evaluate:' || passed.id || ':backfill', 'backfill', passed.updated_at
+		FROM passed
+		WHERE NOT EXISTS (
+			SELECT 1 FROM passed awarded
+			JOIN gem_entries ON gem_entries.source_type = 'user_evaluate' AND gem_entries.source_id = awarded.id
+			WHERE awarded.user_id = passed.user_id AND awarded.step_evaluate_id = passed.step_evaluate_id
+				AND awarded.course_id IS NOT DISTINCT FROM passed.course_id
+		)
+		ORDER BY passed.user_id, passed.step_evaluate_id, passed.course_id, passed.updated_at DESC, passed.id DESC
+		ON CONFLICT (idempotency_key) DO NOTHING`, true)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+)
+
+type HelpRequestRepository interface {
+	CreateHelpRequest(helpRequest *models.HelpRequest) error
+	FindHelpRequestById(helpRequestId uint64) (*models.HelpRequest, error)
+	FindOpenHelpRequest(userId uint64, sessionId *uint64, cohortId *uint64) (*models.HelpRequest, error)
+	FindOpenHelpRequestsByUserId(userId uint64) ([]*models.HelpRequest, error)
+	FindQueue(sessionId *uint64, cohortId *uint64) ([]*models.HelpRequest, error)
+	CountWaitingBefore(helpRequest *models.HelpRequest) (int64, error)
+	Claim(helpRequestId uint64, claimedBy uint64) error
+	Resolve(helpRequestId uint64, claimedBy uint64) error
+	Cancel(helpRequestId uint64, userId uint64) error
+	FindStepHelpStats(courseId *uint64) ([]*payload.StepHelpStats, error)
+}
@@ -0,0 +1,195 @@
+package repositories
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/utils"
+	"errors"
+	"gorm.io/gorm"
+)
+
+var ErrHelpRequestNotOpen = errors.New("help request is not open for this change")
+
+type helpRequestRepo struct {
+	db *gorm.DB
+}
+
+func NewHelpRequestRepository(db *gorm.DB) HelpRequestRepository {
+	return &helpRequestRepo{
+		db: db,
+	}
+}
+
+func (r *helpRequestRepo) CreateHelpRequest(helpRequest *models.HelpRequest) error {
+	return r.db.Create(helpRequest).Error
+}
+
+func (r *helpRequestRepo) FindHelpRequestById(helpRequestId uint64) (*models.HelpRequest, error) {
+	helpRequest := new(models.HelpRequest)
+
+	result := r.db.Preload("User").Preload("Step").Where("id = ?", helpRequestId).Limit(1).Find(&helpRequest)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return helpRequest, nil
+}
+
+// FindOpenHelpRequest returns the waiting or claimed request of the user in the queue, or nil.
+func (r *helpRequestRepo) FindOpenHelpRequest(userId uint64, sessionId *uint64, cohortId *uint64) (*models.HelpRequest, error) {
+	helpRequest := new(models.HelpRequest)
+
+	result := r.queue(r.db, sessionId, cohortId).
+		Where("user_id = ? AND status IN ?", userId, []string{"waiting", "claimed"}).
+		Limit(1).
+		Find(&helpRequest)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return helpRequest, nil
+}
+
+func (r *helpRequestRepo) FindOpenHelpRequestsByUserId(userId uint64) ([]*models.HelpRequest, error) {
+	var helpRequests []*models.HelpRequest
+
+	result := r.db.Preload("Step").
+		Where("user_id = ? AND status IN ?", userId, []string{"waiting", "claimed"}).
+		Order("created_at ASC, id ASC").
+		Find(&helpRequests)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return helpRequests, nil
+}
+
+// FindQueue returns the open requests of the queue, first come first served.
+func (r *helpRequestRepo) FindQueue(sessionId *uint64, cohortId *uint64) ([]*models.HelpRequest, error) {
+	var helpRequests []*models.HelpRequest
+
+	result := r.queue(r.db.Preload("User").Preload("Step"), sessionId, cohortId).
+		Where("status IN ?", []string{"waiting", "claimed"}).
+		Order("created_at ASC, id ASC").
+		Find(&helpRequests)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return helpRequests, nil
+}
+
+// CountWaitingBefore returns how many waiting requests of the queue were raised before the request.
+func (r *helpRequestRepo) CountWaitingBefore(helpRequest *models.HelpRequest) (int64, error) {
+	var count int64
+
+	result := r.queue(r.db.Model(&models.HelpRequest{}), helpRequest.SessionId, helpRequest.CohortId).
+		Where("status = ?", "waiting").
+		Where("created_at < ? OR (created_at = ? AND id < ?)", *helpRequest.CreatedAt, *helpRequest.CreatedAt, *helpRequest.Id).
+		Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return count, nil
+}
+
+// Claim hands a waiting request to a TA. The status is part of the update condition, so of two
+// TAs claiming at once only one succeeds and the other gets ErrHelpRequestNotOpen.
+func (r *helpRequestRepo) Claim(helpRequestId uint64, claimedBy uint64) error {
+	result := r.db.Model(&models.HelpRequest{}).
+		Where("id = ? AND status = ?", helpRequestId, "waiting").
+		Updates(map[string]any{
+			"status":     "claimed",
+			"claimed_by": claimedBy,
+			"claimed_at": utils.TimeNow(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrHelpRequestNotOpen
+	}
+
+	return nil
+}
+
+func (r *helpRequestRepo) Resolve(helpRequestId uint64, claimedBy uint64) error {
+	result := r.db.Model(&models.HelpRequest{}).
+		Where("id = ? AND status = ? AND claimed_by = ?", helpRequestId, "claimed", claimedBy).
+		Updates(map[string]any{
+			"status":      "resolved",
+			"resolved_at": utils.TimeNow(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrHelpRequestNotOpen
+	}
+
+	return nil
+}
+
+func (r *helpRequestRepo) Cancel(helpRequestId uint64, userId uint64) error {
+	result := r.db.Model(&models.HelpRequest{}).
+		Where("id = ? AND user_id = ? AND status IN ?", helpRequestId, userId, []string{"waiting", "claimed"}).
+		Update("status", "cancelled")
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrHelpRequestNotOpen
+	}
+
+	return nil
+}
+
+// FindStepHelpStats sums up the requests per step, the steps learners wait longest on first.
+// Cancelled requests count as raised but not in the times. The course is that of the session or
+// the cohort the request was raised in.
+func (r *helpRequestRepo) FindStepHelpStats(courseId *uint64) ([]*payload.StepHelpStats, error) {
+	var stats []*payload.StepHelpStats
+
+	query := r.db.Table("help_requests").
+		Select("help_requests.step_id, steps.title AS step_title, " +
+			"COUNT(*) AS requests, " +
+			"COUNT(help_requests.resolved_at) AS resolved, " +
+			"AVG(EXTRACT(EPOCH FROM help_requests.claimed_at - help_requests.created_at)) AS avg_wait_seconds, " +
+			"AVG(EXTRACT(EPOCH FROM help_requests.resolved_at - help_requests.claimed_at)) AS avg_resolution_seconds").
+		Joins("JOIN steps ON steps.id = help_requests.step_id")
+	if courseId != nil {
+		query = query.
+			Joins("LEFT JOIN workshop_sessions ON workshop_sessions.id = help_requests.session_id").
+			Joins("LEFT JOIN cohorts ON cohorts.id = help_requests.cohort_id").
+			Where("COALESCE(workshop_sessions.course_id, cohorts.course_id) = ?", *courseId)
+	}
+
+	result := query.
+		Group("help_requests.step_id, steps.title").
+		Order("avg_wait_seconds DESC NULLS LAST, help_requests.step_id ASC").
+		Scan(&stats)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return stats, nil
+}
+
+func (r *helpRequestRepo) queue(query *gorm.DB, sessionId *uint64, cohortId *uint64) *gorm.DB {
+	if sessionId != nil {
+		return query.Where("session_id = ?", *sessionId)
+	}
+	return query.Where("cohort_id = ?", *cohortId)
+}
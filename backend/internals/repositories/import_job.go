@@ -0,0 +1,12 @@
+package repositories
+
+import "backend/internals/db/models"
+
+type ImportJobRepository interface {
+	CreateImportJob(job *models.ImportJob) error
+	UpdateImportJob(job *models.ImportJob) error
+	FindImportJobById(jobId uint64) (*models.ImportJob, error)
+	FindPendingImportJobByDocumentId(documentId string) (*models.ImportJob, error)
+	FindUnfinishedImportJobs() ([]*models.ImportJob, error)
+	FindRecentImportJobs(limit int) ([]*models.ImportJob, error)
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"backend/internals/db/models"
+	"gorm.io/gorm"
+)
+
+type importJobRepo struct {
+	db *gorm.DB
+}
+
+func NewImportJobRepository(db *gorm.DB) ImportJobRepository {
+	return &importJobRepo{
+		db: db,
+	}
+}
+
+func (r *importJobRepo) CreateImportJob(job *models.ImportJob) error {
+	return r.db.Create(job).Error
+}
+
+func (r *importJobRepo) UpdateImportJob(job *models.ImportJob) error {
+	return r.db.Save(job).Error
+}
+
+func (r *importJobRepo) FindImportJobById(jobId uint64) (*models.ImportJob, error) {
+	job := new(models.ImportJob)
+	result := r.db.First(&job, jobId)
+	return job, result.Error
+}
+
+func (r *importJobRepo) FindPendingImportJobByDocumentId(documentId string) (*models.ImportJob, error) {
+	job := new(models.ImportJob)
+
+	result := r.db.Where("document_id = ? AND status = ?", documentId, "pending").Find(&job)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return job, nil
+}
+
+func (r *importJobRepo) FindUnfinishedImportJobs() ([]*models.ImportJob, error) {
+	var jobs []*models.ImportJob
+	result := r.db.Where("status IN ?", []string{"pending", "running"}).Order("id ASC").Find(&jobs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return jobs, nil
+}
+
+func (r *importJobRepo) FindRecentImportJobs(limit int) ([]*models.ImportJob, error) {
+	var jobs []*models.ImportJob
+	result := r.db.Order("id DESC").Limit(limit).Find(&jobs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return jobs, nil
+}
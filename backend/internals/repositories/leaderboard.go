@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+)
+
+type LeaderboardRepository interface {
+	FindTop(key *payload.LeaderboardKey, limit int) ([]*payload.LeaderboardRow, error)
+	FindNeighbours(key *payload.LeaderboardKey, userId uint64, neighbours int) ([]*payload.LeaderboardRow, error)
+	FindPreference(userId uint64) (*models.LeaderboardPreference, error)
+	SavePreference(preference *models.LeaderboardPreference) error
+	Rebuild() (int64, error)
+}
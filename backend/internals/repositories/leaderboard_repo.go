@@ -0,0 +1,173 @@
+package repositories
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/utils"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var leaderboardEpoch = time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type leaderboardRepo struct {
+	db *gorm.DB
+}
+
+func NewLeaderboardRepository(db *gorm.DB) LeaderboardRepository {
+	return &leaderboardRepo{
+		db: db,
+	}
+}
+
+func (r *leaderboardRepo) FindTop(key *payload.LeaderboardKey, limit int) ([]*payload.LeaderboardRow, error) {
+	var rows []*payload.LeaderboardRow
+
+	result := r.db.Table("(?) AS ranked", r.ranked(key)).Order("row_position").Limit(limit).Find(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return rows, nil
+}
+
+// FindNeighbours returns the user with the users ranked right above and below them, nothing when
+// the user is not on the leaderboard.
+func (r *leaderboardRepo) FindNeighbours(key *payload.LeaderboardKey, userId uint64, neighbours int) ([]*payload.LeaderboardRow, error) {
+	var rows []*payload.LeaderboardRow
+
+	result := r.db.Raw(`WITH ranked AS (?)
+		SELECT * FROM ranked
+		WHERE row_position BETWEEN (SELECT row_position FROM ranked WHERE user_id = ?) - ? AND (SELECT row_position FROM ranked WHERE user_id = ?) + ?
+		ORDER BY row_position`, r.ranked(key), userId, neighbours, userId, neighbours).Scan(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return rows, nil
+}
+
+// ranked selects the users with gems on the leaderboard, leaving out those who opted out. Tied
+// users share their rank and are ordered by id.
+func (r *leaderboardRepo) ranked(key *payload.LeaderboardKey) *gorm.DB {
+	return r.db.Table("leaderboard_scores").
+		Select("RANK() OVER (ORDER BY leaderboard_scores.gems DESC) AS rank, "+
+			"ROW_NUMBER() OVER (ORDER BY leaderboard_scores.gems DESC, leaderboard_scores.user_id) AS row_position, "+
+			"leaderboard_scores.user_id, users.firstname AS first_name, users.lastname AS last_name, users.photo_url, leaderboard_scores.gems").
+		Joins("JOIN users ON users.id = leaderboard_scores.user_id").
+		Where("leaderboard_scores.scope = ? AND leaderboard_scores.scope_id = ? AND leaderboard_scores.period = ? AND leaderboard_scores.period_start = ?",
+			key.Scope, key.ScopeId, key.Period, utils.TimeDate(key.PeriodStart)).
+		Where("leaderboard_scores.gems > ?", 0).
+		Where("NOT EXISTS (SELECT 1 FROM leaderboard_preferences WHERE leaderboard_preferences.user_id = leaderboard_scores.user_id AND leaderboard_preferences.hidden)")
+}
+
+func (r *leaderboardRepo) FindPreference(userId uint64) (*models.LeaderboardPreference, error) {
+	preference := new(models.LeaderboardPreference)
+
+	result := r.db.Where("user_id = ?", userId).Limit(1).Find(&preference)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return preference, nil
+}
+
+func (r *leaderboardRepo) SavePreference(preference *models.LeaderboardPreference) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"hidden", "updated_at"}),
+	}).Create(preference).Error
+}
+
+// Rebuild recomputes every score from the gem ledger, for entries written without going through
+// appendEntry like the backfill of the ledger. Gems spent in the shop still count as earned.
+func (r *leaderboardRepo) Rebuild() (int64, error) {
+	var count int64
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM leaderboard_scores").Error; err != nil {
+			return err
+		}
+
+		result := tx.Exec(`INSERT INTO leaderboard_scores (scope, scope_id, period, period_start, user_id, gems)
+			SELECT scopes.scope, scopes.scope_id, periods.period, periods.period_start, gem_entries.user_id, SUM(gem_entries.amount)
+			FROM gem_entries
+			LEFT JOIN courses ON courses.id = gem_entries.course_id
+			CROSS JOIN LATERAL (VALUES ('global', 0), ('course', gem_entries.course_id), ('field', courses.field_id)) AS scopes (scope, scope_id)
+			CROSS JOIN LATERAL (VALUES
+				('all', ?::date),
+				('month', date_trunc('month', gem_entries.created_at AT TIME ZONE ?)::date),
+				('week', date_trunc('week', gem_entries.created_at AT TIME ZONE ?)::date)
+			) AS periods (period, period_start)
+			WHERE scopes.scope_id IS NOT NULL AND gem_entries.kind <> 'spend'
+			GROUP BY scopes.scope, scopes.scope_id, periods.period, periods.period_start, gem_entries.user_id`,
+			leaderboardEpoch, utils.BangkokTime.String(), utils.BangkokTime.String())
+		if result.Error != nil {
+			return result.Error
+		}
+
+		count = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// incrementLeaderboardScores adds the gems of a new ledger entry to the scores of every
+// leaderboard it counts for. Gems count for the period they are written in, so a revoke lowers
+// the current week rather than the week of the award. Spends leave the scores alone.
+func incrementLeaderboardScores(tx *gorm.DB, entry *models.GemEntry) error {
+	if *entry.Kind == payload.GemKindSpend {
+		return nil
+	}
+
+	scopes := map[string]uint64{payload.LeaderboardScopeGlobal: 0}
+	if entry.CourseId != nil {
+		scopes[payload.LeaderboardScopeCourse] = *entry.CourseId
+
+		var course models.Course
+		result := tx.Select("field_id").Limit(1).Find(&course, "id = ?", *entry.CourseId)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			scopes[payload.LeaderboardScopeField] = *course.FieldId
+		}
+	}
+
+	periods := map[string]time.Time{
+		payload.LeaderboardPeriodAll:   leaderboardEpoch,
+		payload.LeaderboardPeriodMonth: utils.TimeDate(utils.TimeMonthStart(*entry.CreatedAt)),
+		payload.LeaderboardPeriodWeek:  utils.TimeDate(utils.TimeWeekStart(*entry.CreatedAt)),
+	}
+
+	scores := make([]*models.LeaderboardScore, 0, len(scopes)*len(periods))
+	for scope, scopeId := range scopes {
+		for period, periodStart := range periods {
+			scores = append(scores, &models.LeaderboardScore{
+				Scope:       utils.Ptr(scope),
+				ScopeId:     utils.Ptr(scopeId),
+				Period:      utils.Ptr(period),
+				PeriodStart: utils.Ptr(periodStart),
+				UserId:      entry.UserId,
+				Gems:        entry.Amount,
+			})
+		}
+	}
+
+	return tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "scope"}, {Name: "scope_id"}, {Name: "period"}, {Name: "period_start"}, {Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"gems": gorm.Expr("leaderboard_scores.gems + excluded.gems"),
+		}),
+	}).Create(&scores).Error
+}
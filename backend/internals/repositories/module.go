@@ -6,4 +6,5 @@ import "backend/internals/db/models"
 type ModulesRepository interface {
 	FindModuleInfoByModuleID(moduleId string) (*models.Module, error)
 	GetModuleById(moduleId *uint64) (*models.Module, error)
+	FindModuleByTitle(title string) (*models.Module, error)
 }
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"backend/internals/db/models"
+	"errors"
 
 	"gorm.io/gorm"
 )
@@ -36,3 +37,17 @@ func (r *moduleRepo) FindModuleInfoByModuleID(moduleId string) (*models.Module,
     }
     return &module, nil
 }
+
+func (r *moduleRepo) FindModuleByTitle(title string) (*models.Module, error) {
+	module := new(models.Module)
+
+	result := r.db.Where("title = ?", title).First(&module)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return module, nil
+}
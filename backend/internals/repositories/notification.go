@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"backend/internals/db/models"
+	"time"
+)
+
+type NotificationRepository interface {
+	UpdateNotification(notification *models.Notification) error
+	FindDueNotifications(now time.Time, limit int) ([]*models.Notification, error)
+	FindPreference(userId uint64) (*models.NotificationPreference, error)
+	SavePreference(preference *models.NotificationPreference) error
+}
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"backend/internals/db/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type notificationRepo struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepo{
+		db: db,
+	}
+}
+
+func (r *notificationRepo) UpdateNotification(notification *models.Notification) error {
+	return r.db.Omit(clause.Associations).Save(notification).Error
+}
+
+// FindDueNotifications returns the pending notifications whose next attempt is due, oldest first.
+func (r *notificationRepo) FindDueNotifications(now time.Time, limit int) ([]*models.Notification, error) {
+	var notifications []*models.Notification
+
+	result := r.db.Where("status = ? AND next_attempt_at <= ?", "pending", now).
+		Order("next_attempt_at ASC, id ASC").
+		Limit(limit).
+		Find(&notifications)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return notifications, nil
+}
+
+func (r *notificationRepo) FindPreference(userId uint64) (*models.NotificationPreference, error) {
+	preference := new(models.NotificationPreference)
+
+	result := r.db.Where("user_id = ?", userId).Limit(1).Find(&preference)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return preference, nil
+}
+
+func (r *notificationRepo) SavePreference(preference *models.NotificationPreference) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"locale", "submission_graded", "comment_reply", "updated_at"}),
+	}).Create(preference).Error
+}
@@ -0,0 +1,15 @@
+package repositories
+
+import "backend/internals/db/models"
+
+type RewardRepository interface {
+	CreateItem(item *models.RewardItem) error
+	UpdateItem(item *models.RewardItem) error
+	FindItemById(itemId uint64) (*models.RewardItem, error)
+	FindItems(activeOnly bool) ([]*models.RewardItem, error)
+	Redeem(userId uint64, itemId uint64) (*models.Redemption, error)
+	FindRedemptionById(redemptionId uint64) (*models.Redemption, error)
+	FindRedemptionsByUserId(userId uint64) ([]*models.Redemption, error)
+	FindRedemptions(status *string) ([]*models.Redemption, error)
+	Fulfill(redemptionId uint64, fulfilledBy uint64) error
+}
@@ -0,0 +1,195 @@
+package repositories
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/utils"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrRewardUnavailable   = errors.New("reward item is not available")
+	ErrRewardOutOfStock    = errors.New("reward item is out of stock")
+	ErrGemsInsufficient    = errors.New("gem balance is too low for this reward")
+	ErrRedemptionFulfilled = errors.New("redemption is already fulfilled")
+)
+
+type rewardRepo struct {
+	db *gorm.DB
+}
+
+func NewRewardRepository(db *gorm.DB) RewardRepository {
+	return &rewardRepo{
+		db: db,
+	}
+}
+
+func (r *rewardRepo) CreateItem(item *models.RewardItem) error {
+	return r.db.Create(item).Error
+}
+
+func (r *rewardRepo) UpdateItem(item *models.RewardItem) error {
+	return r.db.Save(item).Error
+}
+
+func (r *rewardRepo) FindItemById(itemId uint64) (*models.RewardItem, error) {
+	item := new(models.RewardItem)
+
+	result := r.db.Where("id = ?", itemId).Limit(1).Find(&item)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return item, nil
+}
+
+func (r *rewardRepo) FindItems(activeOnly bool) ([]*models.RewardItem, error) {
+	var items []*models.RewardItem
+
+	query := r.db.Order("price ASC, id ASC")
+	if activeOnly {
+		query = query.Where("active = ?", true)
+	}
+
+	if err := query.Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// Redeem buys the item for the user, taking one from the stock and spending its price from their
+// gems. The user row is locked first so the redemptions of a user run one at a time and cannot
+// both spend the same gems, then the item row so two users cannot take the last one.
+func (r *rewardRepo) Redeem(userId uint64, itemId uint64) (*models.Redemption, error) {
+	redemption := new(models.Redemption)
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.User{}, userId).Error; err != nil {
+			return err
+		}
+
+		item := new(models.RewardItem)
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&item, itemId).Error; err != nil {
+			return err
+		}
+		if !*item.Active {
+			return ErrRewardUnavailable
+		}
+		if *item.Stock <= 0 {
+			return ErrRewardOutOfStock
+		}
+
+		var balance int64
+		if err := tx.Model(&models.GemEntry{}).
+			Select("COALESCE(SUM(amount), 0)").
+			Where("user_id = ?", userId).
+			Scan(&balance).Error; err != nil {
+			return err
+		}
+		if balance < *item.Price {
+			return ErrGemsInsufficient
+		}
+
+		if err := tx.Model(item).Update("stock", gorm.Expr("stock - 1")).Error; err != nil {
+			return err
+		}
+		*item.Stock--
+
+		redemption.UserId = &userId
+		redemption.RewardItemId = item.Id
+		redemption.Price = item.Price
+		redemption.Status = utils.Ptr(payload.RedemptionStatusPending)
+		if err := tx.Create(redemption).Error; err != nil {
+			return err
+		}
+		redemption.RewardItem = item
+
+		return tx.Create(&models.GemEntry{
+			UserId:         &userId,
+			Kind:           utils.Ptr(payload.GemKindSpend),
+			Amount:         utils.Ptr(-*item.Price),
+			SourceType:     utils.Ptr(payload.GemSourceRedemption),
+			SourceId:       redemption.Id,
+			IdempotencyKey: utils.Ptr(fmt.Sprintf("%s:%d", payload.GemSourceRedemption, *redemption.Id)),
+			Reason:         item.Name,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return redemption, nil
+}
+
+func (r *rewardRepo) FindRedemptionById(redemptionId uint64) (*models.Redemption, error) {
+	redemption := new(models.Redemption)
+
+	result := r.db.Preload("User").Preload("RewardItem").Where("id = ?", redemptionId).Limit(1).Find(&redemption)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return redemption, nil
+}
+
+func (r *rewardRepo) FindRedemptionsByUserId(userId uint64) ([]*models.Redemption, error) {
+	var redemptions []*models.Redemption
+
+	result := r.db.Preload("RewardItem").Where("user_id = ?", userId).Order("id DESC").Find(&redemptions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return redemptions, nil
+}
+
+// FindRedemptions returns the redemptions with the status, or all of them, oldest first so staff
+// hand items over in order.
+func (r *rewardRepo) FindRedemptions(status *string) ([]*models.Redemption, error) {
+	var redemptions []*models.Redemption
+
+	query := r.db.Preload("User").Preload("RewardItem").Order("id ASC")
+	if status != nil {
+		query = query.Where("status = ?", *status)
+	}
+
+	if err := query.Find(&redemptions).Error; err != nil {
+		return nil, err
+	}
+
+	return redemptions, nil
+}
+
+// Fulfill marks a pending redemption as handed over. The status is part of the update condition,
+// so a redemption is fulfilled once even when two staff members mark it at the same time.
+func (r *rewardRepo) Fulfill(redemptionId uint64, fulfilledBy uint64) error {
+	result := r.db.Model(&models.Redemption{}).
+		Where("id = ? AND status = ?", redemptionId, payload.RedemptionStatusPending).
+		Updates(map[string]any{
+			"status":       payload.RedemptionStatusFulfilled,
+			"fulfilled_by": fulfilledBy,
+			"fulfilled_at": utils.TimeNow(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrRedemptionFulfilled
+	}
+
+	return nil
+}
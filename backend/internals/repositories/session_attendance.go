@@ -0,0 +1,9 @@
+package repositories
+
+import "backend/internals/db/models"
+
+type SessionAttendanceRepository interface {
+	CreateAttendance(attendance *models.SessionAttendance) error
+	FindAttendance(sessionId uint64, userId uint64) (*models.SessionAttendance, error)
+	FindAttendancesBySessionId(sessionId uint64) ([]*models.SessionAttendance, error)
+}
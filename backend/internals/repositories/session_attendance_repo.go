@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"backend/internals/db/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type sessionAttendanceRepo struct {
+	db *gorm.DB
+}
+
+func NewSessionAttendanceRepository(db *gorm.DB) SessionAttendanceRepository {
+	return &sessionAttendanceRepo{
+		db: db,
+	}
+}
+
+// CreateAttendance records the attendance, keeping the first check-in when the user scans twice.
+func (r *sessionAttendanceRepo) CreateAttendance(attendance *models.SessionAttendance) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(attendance).Error
+}
+
+func (r *sessionAttendanceRepo) FindAttendance(sessionId uint64, userId uint64) (*models.SessionAttendance, error) {
+	attendance := new(models.SessionAttendance)
+
+	result := r.db.Where("session_id = ? AND user_id = ?", sessionId, userId).Limit(1).Find(&attendance)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return attendance, nil
+}
+
+func (r *sessionAttendanceRepo) FindAttendancesBySessionId(sessionId uint64) ([]*models.SessionAttendance, error) {
+	var attendances []*models.SessionAttendance
+
+	result := r.db.Preload("User").Where("session_id = ?", sessionId).Order("checked_in_at ASC").Find(&attendances)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return attendances, nil
+}
@@ -4,5 +4,6 @@ import "backend/internals/db/models"
 
 type StepCommentRepository interface {
 	GetStepCommentByStepId(stepId *uint64) ([]*models.StepComment, error)
-	CreateStepComment(stepComment *models.StepComment) error
+	GetStepCommentById(stepCommentId *uint64) (*models.StepComment, error)
+	CreateStepComment(stepComment *models.StepComment, notification *models.Notification) error
 }
@@ -26,6 +26,31 @@ func (r *stepCommentRepo) GetStepCommentByStepId(stepId *uint64) ([]*models.Step
 	return stepComments, nil
 }
 
-func (r *stepCommentRepo) CreateStepComment(stepComment *models.StepComment) error {
-	return r.db.Create(stepComment).Error
+func (r *stepCommentRepo) GetStepCommentById(stepCommentId *uint64) (*models.StepComment, error) {
+	stepComment := new(models.StepComment)
+
+	result := r.db.Where("id = ?", stepCommentId).Limit(1).Find(&stepComment)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return stepComment, nil
+}
+
+// CreateStepComment creates the comment along with the notification of the reply, if any, so the
+// email is sent for every reply saved.
+func (r *stepCommentRepo) CreateStepComment(stepComment *models.StepComment, notification *models.Notification) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(stepComment).Error; err != nil {
+			return err
+		}
+		if notification == nil {
+			return nil
+		}
+		return tx.Create(notification).Error
+	})
 }
@@ -5,4 +5,5 @@ import "backend/internals/db/models"
 type StepEvaluateRepository interface {
 	GetStepEvalByStepId(stepId *uint64) ([]*models.StepEvaluate, error)
 	GetStepEvalById(stepEvalId *uint64) (*models.StepEvaluate, error)
+	UpdateStepEval(stepEval *models.StepEvaluate) error
 }
@@ -3,6 +3,7 @@ package repositories
 import (
 	"backend/internals/db/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type stepEvaluateRepository struct {
@@ -28,8 +29,12 @@ func (r *stepEvaluateRepository) GetStepEvalByStepId(stepId *uint64) ([]*models.
 func (r *stepEvaluateRepository) GetStepEvalById(stepEvalId *uint64) (*models.StepEvaluate, error) {
 	stepEval := new(models.StepEvaluate)
 
-	if err := r.db.First(&stepEval, stepEvalId); err != nil {
-		return nil, err.Error
+	if err := r.db.First(&stepEval, stepEvalId).Error; err != nil {
+		return nil, err
 	}
 	return stepEval, nil
 }
+
+func (r *stepEvaluateRepository) UpdateStepEval(stepEval *models.StepEvaluate) error {
+	return r.db.Omit(clause.Associations).Save(stepEval).Error
+}
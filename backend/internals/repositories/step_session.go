@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"time"
+)
+
+type StepSessionRepository interface {
+	RecordHeartbeat(userId uint64, stepId uint64, courseId uint64, at time.Time, idle bool, gap time.Duration, maxCredit time.Duration) (*models.StepSession, error)
+	FindCourseTimeSpentByUserId(userId uint64) ([]*payload.TimeSpent, error)
+	FindModuleTimeSpentByUserId(userId uint64, courseId uint64) ([]*payload.TimeSpent, error)
+	FindStepTimeSpentByUserId(userId uint64, courseId uint64) ([]*payload.TimeSpent, error)
+	FindModuleTimeSpentStats(courseId uint64) ([]*payload.TimeSpentStats, error)
+	FindStepTimeSpentStats(courseId uint64) ([]*payload.TimeSpentStats, error)
+}
@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/utils"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type stepSessionRepo struct {
+	db *gorm.DB
+}
+
+func NewStepSessionRepository(db *gorm.DB) StepSessionRepository {
+	return &stepSessionRepo{
+		db: db,
+	}
+}
+
+// RecordHeartbeat extends the session of the user on the step seen within the gap, or starts a
+// new one. The time since the previous heartbeat is credited up to maxCredit, and not at all when
+// idle. The session row is locked so heartbeats of two open tabs do not credit the time twice.
+func (r *stepSessionRepo) RecordHeartbeat(userId uint64, stepId uint64, courseId uint64, at time.Time, idle bool, gap time.Duration, maxCredit time.Duration) (*models.StepSession, error) {
+	session := new(models.StepSession)
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ? AND step_id = ? AND course_id = ? AND last_seen_at >= ?", userId, stepId, courseId, at.Add(-gap)).
+			Order("last_seen_at DESC").
+			Limit(1).
+			Find(&session)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			session = &models.StepSession{
+				UserId:        &userId,
+				StepId:        &stepId,
+				CourseId:      &courseId,
+				StartedAt:     &at,
+				LastSeenAt:    &at,
+				ActiveSeconds: utils.Ptr(int64(0)),
+			}
+			return tx.Create(session).Error
+		}
+
+		// a heartbeat arriving late, after one of another tab, adds nothing
+		if !at.After(*session.LastSeenAt) {
+			return nil
+		}
+
+		if !idle {
+			credit := min(at.Sub(*session.LastSeenAt), maxCredit)
+			session.ActiveSeconds = utils.Ptr(*session.ActiveSeconds + int64(credit.Seconds()))
+		}
+		session.LastSeenAt = &at
+		return tx.Omit(clause.Associations).Save(session).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (r *stepSessionRepo) FindCourseTimeSpentByUserId(userId uint64) ([]*payload.TimeSpent, error) {
+	var courses []*payload.TimeSpent
+
+	result := r.db.Table("step_sessions").
+		Select("step_sessions.course_id AS id, courses.name AS title, SUM(step_sessions.active_seconds) AS seconds").
+		Joins("JOIN courses ON courses.id = step_sessions.course_id").
+		Where("step_sessions.user_id = ?", userId).
+		Group("step_sessions.course_id, courses.name").
+		Order("seconds DESC, step_sessions.course_id ASC").
+		Scan(&courses)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return courses, nil
+}
+
+// FindModuleTimeSpentByUserId returns the time of the user on the modules of the course, in the
+// order of the course content.
+func (r *stepSessionRepo) FindModuleTimeSpentByUserId(userId uint64, courseId uint64) ([]*payload.TimeSpent, error) {
+	var modules []*payload.TimeSpent
+
+	result := r.courseContent(r.db.Table("step_sessions"), courseId).
+		Select("steps.module_id AS id, modules.title AS title, SUM(step_sessions.active_seconds) AS seconds").
+		Joins("JOIN modules ON modules.id = steps.module_id").
+		Where("step_sessions.user_id = ? AND step_sessions.course_id = ?", userId, courseId).
+		Group("steps.module_id, modules.title, contents.position").
+		Order("contents.position ASC").
+		Scan(&modules)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return modules, nil
+}
+
+func (r *stepSessionRepo) FindStepTimeSpentByUserId(userId uint64, courseId uint64) ([]*payload.TimeSpent, error) {
+	var steps []*payload.TimeSpent
+
+	result := r.courseContent(r.db.Table("step_sessions"), courseId).
+		Select("step_sessions.step_id AS id, steps.title AS title, SUM(step_sessions.active_seconds) AS seconds").
+		Where("step_sessions.user_id = ? AND step_sessions.course_id = ?", userId, courseId).
+		Group("step_sessions.step_id, steps.title, contents.position").
+		Order("contents.position ASC, step_sessions.step_id ASC").
+		Scan(&steps)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return steps, nil
+}
+
+// FindModuleTimeSpentStats sums up the time of the learners of the course per module, in the
+// order of the course content.
+func (r *stepSessionRepo) FindModuleTimeSpentStats(courseId uint64) ([]*payload.TimeSpentStats, error) {
+	return r.timeSpentStats(courseId, "steps.module_id", "modules.title", "JOIN modules ON modules.id = learners.id")
+}
+
+func (r *stepSessionRepo) FindStepTimeSpentStats(courseId uint64) ([]*payload.TimeSpentStats, error) {
+	return r.timeSpentStats(courseId, "step_sessions.step_id", "steps.title", "JOIN steps ON steps.id = learners.id")
+}
+
+// timeSpentStats sums up the time of every learner of the course per the grouping column first,
+// then the learners per group. The title comes from the table of the join.
+func (r *stepSessionRepo) timeSpentStats(courseId uint64, column string, title string, join string) ([]*payload.TimeSpentStats, error) {
+	var stats []*payload.TimeSpentStats
+
+	learners := r.courseContent(r.db.Table("step_sessions"), courseId).
+		Select(fmt.Sprintf("%s AS id, MIN(contents.position) AS position, step_sessions.user_id, SUM(step_sessions.active_seconds) AS seconds", column)).
+		Where("step_sessions.course_id = ?", courseId).
+		Group(fmt.Sprintf("%s, step_sessions.user_id", column))
+
+	result := r.db.Table("(?) AS learners", learners).
+		Select(fmt.Sprintf("learners.id, %s AS title, COUNT(*) AS learners, SUM(learners.seconds) AS total_seconds, "+
+			"AVG(learners.seconds) AS avg_seconds, "+
+			"percentile_cont(0.5) WITHIN GROUP (ORDER BY learners.seconds) AS median_seconds", title)).
+		Joins(join).
+		Group(fmt.Sprintf("learners.id, %s", title)).
+		Order("MIN(learners.position) ASC, learners.id ASC").
+		Scan(&stats)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return stats, nil
+}
+
+// courseContent joins the step of the sessions and the position of its module in the course.
+func (r *stepSessionRepo) courseContent(query *gorm.DB, courseId uint64) *gorm.DB {
+	return query.
+		Joins("JOIN steps ON steps.id = step_sessions.step_id").
+		Joins("JOIN (SELECT module_id, MIN(\"order\") AS position FROM course_contents WHERE course_id = ? GROUP BY module_id) AS contents "+
+			"ON contents.module_id = steps.module_id", courseId)
+}
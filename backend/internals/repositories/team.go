@@ -0,0 +1,10 @@
+package repositories
+
+import "backend/internals/db/models"
+
+type TeamRepository interface {
+	CreateTeam(team *models.Team) error
+	FindTeamById(teamId uint64) (*models.Team, error)
+	SaveMember(member *models.TeamMember) error
+	FindTeamOfUser(userId uint64, courseId uint64) (*models.Team, error)
+}
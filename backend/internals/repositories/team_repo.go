@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"backend/internals/db/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type teamRepo struct {
+	db *gorm.DB
+}
+
+func NewTeamRepository(db *gorm.DB) TeamRepository {
+	return &teamRepo{
+		db: db,
+	}
+}
+
+func (r *teamRepo) CreateTeam(team *models.Team) error {
+	return r.db.Omit(clause.Associations).Create(team).Error
+}
+
+func (r *teamRepo) FindTeamById(teamId uint64) (*models.Team, error) {
+	team := new(models.Team)
+
+	result := r.db.Preload("Course").Preload("Members.User").Where("id = ?", teamId).Limit(1).Find(&team)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return team, nil
+}
+
+// SaveMember adds the user to the team, being a member already is not an error.
+func (r *teamRepo) SaveMember(member *models.TeamMember) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "team_id"}, {Name: "user_id"}},
+		DoNothing: true,
+	}).Create(member).Error
+}
+
+// FindTeamOfUser returns the team the user belongs to in the course, with its members.
+func (r *teamRepo) FindTeamOfUser(userId uint64, courseId uint64) (*models.Team, error) {
+	team := new(models.Team)
+
+	result := r.db.Preload("Members").
+		Joins("JOIN team_members ON team_members.team_id = teams.id").
+		Where("team_members.user_id = ? AND teams.course_id = ?", userId, courseId).
+		Order("teams.id ASC").
+		Limit(1).
+		Find(&team)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return team, nil
+}
@@ -3,6 +3,6 @@ package repositories
 import "backend/internals/db/models"
 
 type UserActivityRepository interface {
-	UpdateUserActivity(userId uint64, stepId uint64) error
+	UpdateUserActivity(userId uint64, stepId uint64, courseId *uint64) error
 	GetRecentActivitiesByUserID(userId *string) ([]models.UserActivity, error)
 }
@@ -18,11 +18,13 @@ func NewUserActivityRepository(db *gorm.DB) UserActivityRepository {
 	}
 }
 
-func (repo *userActivityRepository) UpdateUserActivity(userId uint64, stepId uint64) error {
+func (repo *userActivityRepository) UpdateUserActivity(userId uint64, stepId uint64, courseId *uint64) error {
     var existingActivity models.UserActivity
 
-    // Check if the activity already exists
-    result := repo.db.Where("user_id = ? AND step_id = ?", userId, stepId).First(&existingActivity)
+    // Check if the activity already exists, adopting an activity recorded without course
+    result := repo.db.Where("user_id = ? AND step_id = ? AND (course_id = ? OR course_id IS NULL)", userId, stepId, courseId).
+        Order("course_id IS NULL").
+        First(&existingActivity)
 
     if result.Error != nil {
         if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -30,6 +32,7 @@ func (repo *userActivityRepository) UpdateUserActivity(userId uint64, stepId uin
             newActivity := models.UserActivity{
                 UserId:    &userId,
                 StepId:    &stepId,
+                CourseId:  courseId,
                 CreatedAt: utils.TimeNowPtr(),
                 UpdatedAt: utils.TimeNowPtr(),
             }
@@ -43,8 +46,8 @@ func (repo *userActivityRepository) UpdateUserActivity(userId uint64, stepId uin
     } else {
         // If record exists, explicitly update the UpdatedAt field
         err := repo.db.Model(&existingActivity).
-            Where("user_id = ? AND step_id = ?", userId, stepId).
-            Update("updated_at", utils.TimeNowPtr()).Error
+            Where("user_id = ? AND step_id = ? AND course_id IS NOT DISTINCT FROM ?", userId, stepId, existingActivity.CourseId).
+            Updates(map[string]any{"course_id": courseId, "updated_at": utils.TimeNowPtr()}).Error
         if err != nil {
             return err
         }
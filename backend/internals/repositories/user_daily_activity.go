@@ -0,0 +1,12 @@
+package repositories
+
+import (
+	"backend/internals/db/models"
+	"time"
+)
+
+type UserDailyActivityRepository interface {
+	IncrementDailyActivity(userId uint64, day time.Time) error
+	FindActiveDays(userId uint64) ([]time.Time, error)
+	FindDailyActivities(userId uint64, from time.Time) ([]*models.UserDailyActivity, error)
+}
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/utils"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type userDailyActivityRepo struct {
+	db *gorm.DB
+}
+
+func NewUserDailyActivityRepository(db *gorm.DB) UserDailyActivityRepository {
+	return &userDailyActivityRepo{
+		db: db,
+	}
+}
+
+// IncrementDailyActivity counts an activity of the user on the day, a date of their time zone.
+func (r *userDailyActivityRepo) IncrementDailyActivity(userId uint64, day time.Time) error {
+	activity := &models.UserDailyActivity{
+		UserId: &userId,
+		Day:    utils.Ptr(utils.TimeDate(day)),
+		Count:  utils.Ptr(int64(1)),
+	}
+
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "day"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"count":      gorm.Expr("user_daily_activities.count + 1"),
+			"updated_at": gorm.Expr("excluded.updated_at"),
+		}),
+	}).Create(activity).Error
+}
+
+// FindActiveDays returns the days the user was active on, oldest first.
+func (r *userDailyActivityRepo) FindActiveDays(userId uint64) ([]time.Time, error) {
+	var days []time.Time
+
+	result := r.db.Model(&models.UserDailyActivity{}).
+		Where("user_id = ?", userId).
+		Order("day ASC").
+		Pluck("day", &days)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return days, nil
+}
+
+func (r *userDailyActivityRepo) FindDailyActivities(userId uint64, from time.Time) ([]*models.UserDailyActivity, error) {
+	var activities []*models.UserDailyActivity
+
+	result := r.db.Where("user_id = ? AND day >= ?", userId, utils.TimeDate(from)).Order("day ASC").Find(&activities)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return activities, nil
+}
@@ -2,9 +2,11 @@ package repositories
 
 import (
 	"backend/internals/db/models"
+	"backend/internals/utils"
 	"fmt"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type userEvaluateRepo struct {
@@ -17,10 +19,16 @@ func NewUserEvaluateRepo(db *gorm.DB) UserEvaluateRepository {
 	}
 }
 
-func (r *userEvaluateRepo) GetUserEvalByStepEvalIdUserId(stepEvalId *uint64, userId *float64) (*models.UserEvaluate, error) {
+func (r *userEvaluateRepo) GetUserEvalByStepEvalIdUserId(stepEvalId *uint64, courseId *uint64, userId *float64) (*models.UserEvaluate, error) {
 	userEval := new(models.UserEvaluate)
 
-	result := r.db.Where("step_evaluate_id = ? AND user_id = ?", stepEvalId, userId).Order("created_at DESC").Find(&userEval)
+	// unattributed evaluations belong to modules shared by several courses and count for each
+	// of them, evaluations taken in the course itself take precedence
+	result := r.db.Preload("Submitter").
+		Where("step_evaluate_id = ? AND user_id = ? AND (course_id = ? OR course_id IS NULL)", stepEvalId, userId, courseId).
+		Order("course_id IS NULL, created_at DESC").
+		Limit(1).
+		Find(&userEval)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -44,8 +52,8 @@ func (r *userEvaluateRepo) CreateUserEval(userEval *models.UserEvaluate) (*model
 func (r *userEvaluateRepo) GetUserEvalById(userEvalId *uint64) (*models.UserEvaluate, error) {
 	userEval := new(models.UserEvaluate)
 
-	if err := r.db.First(&userEval, userEvalId); err != nil {
-		return nil, err.Error
+	if err := r.db.First(&userEval, userEvalId).Error; err != nil {
+		return nil, err
 	}
 
 	return userEval, nil
@@ -54,7 +62,7 @@ func (r *userEvaluateRepo) GetUserEvalById(userEvalId *uint64) (*models.UserEval
 func (r *userEvaluateRepo) GetUserEvalByIdAndUserId(userEvalId *uint64, userId *uint64) (*models.UserEvaluate, error) {
 	userEval := new(models.UserEvaluate)
 
-	result := r.db.Find(&userEval, "id = ? AND user_id = ?", userEvalId, userId)
+	result := r.db.Preload("Submitter").Find(&userEval, "id = ? AND user_id = ?", userEvalId, userId)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -66,10 +74,10 @@ func (r *userEvaluateRepo) GetUserEvalByIdAndUserId(userEvalId *uint64, userId *
 	return userEval, nil
 }
 
-func (r *userEvaluateRepo) GetPassAllUserEvalByStepEvalId(stepEvalId *uint64) ([]*models.UserEvaluate, error) {
+func (r *userEvaluateRepo) GetPassAllUserEvalByStepEvalId(stepEvalId *uint64, courseId *uint64) ([]*models.UserEvaluate, error) {
 	userEval := make([]*models.UserEvaluate, 0)
 
-	result := r.db.Find(&userEval, "step_evaluate_id = ? AND pass = true", stepEvalId)
+	result := r.db.Find(&userEval, "step_evaluate_id = ? AND (course_id = ? OR course_id IS NULL) AND pass = true", stepEvalId, courseId)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -96,13 +104,13 @@ func (r *userEvaluateRepo) FindStepEvaluateIDsByStepID(stepID uint64) ([]uint64,
 	return stepEvaluateIDs, nil
 }
 
-func (r *userEvaluateRepo) FindUserPassedEvaluateIDs(userID uint, stepID uint64) ([]uint64, error) {
+func (r *userEvaluateRepo) FindUserPassedEvaluateIDs(userID uint, courseID uint64, stepID uint64) ([]uint64, error) {
 	var userPassedIDs []uint64
 
-	// Query the user_evaluates table to get IDs where pass is not null for the given user and step_id
+	// Query the user_evaluates table to get IDs where pass is not null for the given user, course and step_id
 	err := r.db.Table("user_evaluates").
-		Select("step_evaluate_id").
-		Where("user_id = ? AND step_evaluate_id IN (?) AND pass=TRUE", userID,
+		Distinct("step_evaluate_id").
+		Where("user_id = ? AND (course_id = ? OR course_id IS NULL) AND step_evaluate_id IN (?) AND pass=TRUE", userID, courseID,
 			r.db.Table("step_evaluates").Select("id").Where("step_id = ?", stepID),
 		).
 		Scan(&userPassedIDs).Error
@@ -115,5 +123,42 @@ func (r *userEvaluateRepo) FindUserPassedEvaluateIDs(userID uint, stepID uint64)
 }
 
 func (r *userEvaluateRepo) Update(userEval *models.UserEvaluate) error {
-	return r.db.Save(userEval).Error
+	return r.db.Omit(clause.Associations).Save(userEval).Error
+}
+
+// FindTeamUserEvals returns the evaluations the members of the team share for a submission.
+func (r *userEvaluateRepo) FindTeamUserEvals(stepEvalId *uint64, courseId *uint64, teamId *uint64) ([]*models.UserEvaluate, error) {
+	userEvals := make([]*models.UserEvaluate, 0)
+
+	result := r.db.Find(&userEvals, "step_evaluate_id = ? AND course_id = ? AND team_id = ?", stepEvalId, courseId, teamId)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return userEvals, nil
+}
+
+// SaveUserEvals creates or updates the evaluations at once, settling in the gem ledger what each
+// of them is worth: the gems when passed, nothing otherwise. A submission is recorded for either
+// all or none of its members, along with their gems and the notifications telling about it.
+func (r *userEvaluateRepo) SaveUserEvals(userEvals []*models.UserEvaluate, gems int64, notifications []*models.Notification) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, userEval := range userEvals {
+			if err := tx.Omit(clause.Associations).Save(userEval).Error; err != nil {
+				return err
+			}
+
+			var worth int64
+			if utils.Val(userEval.Pass) {
+				worth = gems
+			}
+			if err := settleUserEval(tx, userEval, worth); err != nil {
+				return err
+			}
+		}
+		if len(notifications) == 0 {
+			return nil
+		}
+		return tx.Create(&notifications).Error
+	})
 }
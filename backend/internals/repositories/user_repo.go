@@ -39,12 +39,12 @@ func (r *userRepository) DeleteUser(id uint) error {
 	return r.db.Delete(&models.User{}, id).Error
 }
 
+// GetTotalGemsByUserID returns the gem balance of the user from the gem ledger, never below zero.
 func (r *userRepository) GetTotalGemsByUserID(userID uint) (uint64, error) {
 	var totalGems uint64
-	err := r.db.Table("user_passes").
-		Joins("INNER JOIN step_evaluates ON user_passes.step_id = step_evaluates.step_id").
-		Where("user_passes.user_id = ?", userID).
-		Select("COALESCE(SUM(step_evaluates.gem), 0) AS total_gems"). // Handle NULL values with COALESCE
+	err := r.db.Table("gem_entries").
+		Where("gem_entries.user_id = ?", userID).
+		Select("GREATEST(COALESCE(SUM(gem_entries.amount), 0), 0) AS total_gems").
 		Scan(&totalGems).Error
 	if err != nil {
 		return 0, err
@@ -52,9 +52,10 @@ func (r *userRepository) GetTotalGemsByUserID(userID uint) (uint64, error) {
 	return totalGems, nil
 }
 
-func (r *userRepository) GetUserCompletedSteps(userID uint) ([]models.UserActivity, error) {
+func (r *userRepository) GetUserCompletedSteps(userID uint, courseID uint) ([]models.UserActivity, error) {
     var userActivities []models.UserActivity
-    result := r.db.Where("user_id = ?", userID).Find(&userActivities)
+    // unattributed activities belong to modules shared by several courses and count for each of them
+    result := r.db.Where("user_id = ? AND (course_id = ? OR course_id IS NULL)", userID, courseID).Find(&userActivities)
     if result.Error != nil {
         return nil, result.Error
     }
@@ -3,9 +3,13 @@ package repositories
 import (
 	"backend/internals/db/models"
 	"backend/internals/entities/payload"
+	"time"
 )
 
 type UserStrengthRepository interface {
 	GetStrengthDataByUserID(userId uint64) ([]payload.StrengthFieldData, error)
-	GetSuggestionCourse(userId uint64) ([]models.Course, error)
+	FindCourseCandidates(userId uint64) ([]*payload.CourseCandidate, error)
+	SnapshotStrengths(weekStart time.Time) (int64, error)
+	FindStrengthSnapshots(userId uint64, from time.Time) ([]*models.StrengthSnapshot, error)
+	FindUnfinishedSteps(userId uint64, fieldId uint64, limit int) ([]*payload.StrengthStep, error)
 }
@@ -3,8 +3,11 @@ package repositories
 import (
 	"backend/internals/db/models"
 	"backend/internals/entities/payload"
+	"backend/internals/utils"
+	"fmt"
 	"gorm.io/gorm"
 	"log"
+	"time"
 )
 
 type userStrengthRepository struct {
@@ -17,82 +20,163 @@ func NewUserStrengthRepository(db *gorm.DB) UserStrengthRepository {
 	}
 }
 
-// GetStrengthDataByUserID ดึงคะแนน strength ตามแต่ละ field type ที่ผู้ใช้ตอบถูก
+// fieldStrengths is the query of the gems every field offers and those each user earned in it, as
+// common table expressions. A step evaluation is offered once by every field with a course
+// containing its step. Earned gems are those the ledger awarded, net of revokes, for the courses
+// of the field.
+const fieldStrengths = `WITH field_evaluates AS (
+		SELECT DISTINCT courses.field_id, step_evaluates.id AS step_evaluate_id, step_evaluates.gem
+		FROM step_evaluates
+		JOIN steps ON steps.id = step_evaluates.step_id
+		JOIN course_contents ON course_contents.module_id = steps.module_id
+		JOIN courses ON courses.id = course_contents.course_id
+	),
+	available AS (
+		SELECT field_id, SUM(gem) AS gems FROM field_evaluates GROUP BY field_id
+	),
+	earned AS (
+		SELECT gem_entries.user_id, courses.field_id, SUM(gem_entries.amount) AS gems
+		FROM gem_entries
+		JOIN courses ON courses.id = gem_entries.course_id
+		WHERE gem_entries.kind IN ('award', 'revoke') %s
+		GROUP BY gem_entries.user_id, courses.field_id
+	)`
+
+// GetStrengthDataByUserID returns the gems of every field the user earned and those the field offers.
 func (r *userStrengthRepository) GetStrengthDataByUserID(userId uint64) ([]payload.StrengthFieldData, error) {
 	var strengthData []payload.StrengthFieldData
 
-	var evaluations []struct {
-		FieldName string
-		TotalGems int64
-	}
-
-	err := r.db.
-		Table("user_evaluates").
-		Joins("JOIN step_evaluates ON step_evaluates.id = user_evaluates.step_evaluate_id").
-		Joins("JOIN steps ON steps.id = step_evaluates.step_id").
-		Joins("JOIN modules ON modules.id = steps.module_id").
-		Joins("JOIN course_contents ON course_contents.module_id = modules.id").
-		Joins("JOIN courses ON courses.id = course_contents.course_id").
-		Joins("JOIN field_types ON field_types.id = courses.field_id").
-		Where("user_evaluates.user_id = ? AND user_evaluates.pass = ?", userId, true).
-		Select("field_types.name AS field_name, SUM(step_evaluates.gem) AS total_gems").
-		Group("field_types.name").
-		Find(&evaluations).Error
-
+	err := r.db.Raw(fmt.Sprintf(fieldStrengths, "AND gem_entries.user_id = ?")+`
+		SELECT field_types.id AS field_id, field_types.name AS field_name,
+			COALESCE(earned.gems, 0) AS total_gems, COALESCE(available.gems, 0) AS available_gems
+		FROM field_types
+		LEFT JOIN available ON available.field_id = field_types.id
+		LEFT JOIN earned ON earned.field_id = field_types.id
+		ORDER BY field_types.id ASC`, userId).
+		Scan(&strengthData).Error
 	if err != nil {
-		log.Printf("Error fetching evaluated steps for user %d: %v", userId, err)
+		log.Printf("Error fetching strength data for user %d: %v", userId, err)
 		return nil, err
 	}
 
-	// If no evaluations found, fetch all default field types
-	if len(evaluations) == 0 {
-		var fieldTypes []models.FieldType
-		err := r.db.Find(&fieldTypes).Error
-		if err != nil {
-			log.Printf("Error fetching default field types: %v", err)
-			return nil, err
-		}
-
-		strengthData = make([]payload.StrengthFieldData, len(fieldTypes))
-		for i, fieldType := range fieldTypes {
-			strengthData[i] = payload.StrengthFieldData{
-				FieldName: *fieldType.Name,
-				TotalGems: 0,
-			}
-		}
-
-		log.Printf("No passed evaluations found for user %d. Returning %d default field types.", userId, len(strengthData))
-		return strengthData, nil
+	return strengthData, nil
+}
+
+// SnapshotStrengths records the strengths of the week of every user who earned gems in a course,
+// replacing those recorded earlier in the week. It returns the snapshots recorded.
+func (r *userStrengthRepository) SnapshotStrengths(weekStart time.Time) (int64, error) {
+	result := r.db.Exec(fmt.Sprintf(fieldStrengths, "")+`
+		INSERT INTO strength_snapshots (user_id, week_start, field_id, earned_gems, available_gems, created_at, updated_at)
+		SELECT learners.user_id, ?, field_types.id, COALESCE(earned.gems, 0), COALESCE(available.gems, 0), NOW(), NOW()
+		FROM (SELECT DISTINCT user_id FROM earned) learners
+		CROSS JOIN field_types
+		LEFT JOIN available ON available.field_id = field_types.id
+		LEFT JOIN earned ON earned.user_id = learners.user_id AND earned.field_id = field_types.id
+		ON CONFLICT (user_id, week_start, field_id) DO UPDATE SET
+			earned_gems = excluded.earned_gems,
+			available_gems = excluded.available_gems,
+			updated_at = excluded.updated_at`, utils.TimeDate(weekStart))
+	if result.Error != nil {
+		return 0, result.Error
 	}
 
-	// Convert evaluations to response format
-	strengthData = make([]payload.StrengthFieldData, 0, len(evaluations))
-	for _, evaluation := range evaluations {
-		strengthData = append(strengthData, payload.StrengthFieldData{
-			FieldName: evaluation.FieldName,
-			TotalGems: evaluation.TotalGems,
-		})
+	return result.RowsAffected, nil
+}
+
+// FindStrengthSnapshots returns the snapshots of the user from the week starting at from, oldest
+// first, with their field.
+func (r *userStrengthRepository) FindStrengthSnapshots(userId uint64, from time.Time) ([]*models.StrengthSnapshot, error) {
+	var snapshots []*models.StrengthSnapshot
+
+	result := r.db.Preload("Field").
+		Where("user_id = ? AND week_start >= ?", userId, utils.TimeDate(from)).
+		Order("week_start ASC, field_id ASC").
+		Find(&snapshots)
+	if result.Error != nil {
+		return nil, result.Error
 	}
 
-	return strengthData, nil
+	return snapshots, nil
 }
 
-// GetSuggestionCourse returns random course suggestions
-func (r *userStrengthRepository) GetSuggestionCourse(userId uint64) ([]models.Course, error) {
-	log.Printf("Fetching random course suggestions for user ID: %d", userId)
+// FindUnfinishedSteps returns the steps of the courses of the field with evaluations the user has
+// not passed, those worth the most gems first. A step of several courses comes with the first one.
+func (r *userStrengthRepository) FindUnfinishedSteps(userId uint64, fieldId uint64, limit int) ([]*payload.StrengthStep, error) {
+	var steps []*payload.StrengthStep
+
+	unfinished := r.db.Table("step_evaluates").
+		Select("step_evaluates.step_id, SUM(step_evaluates.gem) AS gems").
+		Where("NOT EXISTS (?)", r.db.Table("user_evaluates").
+			Select("1").
+			Where("user_evaluates.step_evaluate_id = step_evaluates.id AND user_evaluates.user_id = ? AND user_evaluates.pass = TRUE", userId)).
+		Group("step_evaluates.step_id")
+
+	err := r.db.Table("(?) unfinished", unfinished).
+		Select("MIN(course_contents.course_id) AS course_id, steps.module_id, steps.id AS step_id, steps.title, unfinished.gems").
+		Joins("JOIN steps ON steps.id = unfinished.step_id").
+		Joins("JOIN course_contents ON course_contents.module_id = steps.module_id").
+		Joins("JOIN courses ON courses.id = course_contents.course_id").
+		Where("courses.field_id = ?", fieldId).
+		Group("steps.id, steps.module_id, steps.title, unfinished.gems").
+		Order("unfinished.gems DESC, steps.id ASC").
+		Limit(limit).
+		Scan(&steps).Error
+	if err != nil {
+		return nil, err
+	}
 
-	var courses []models.Course
-	err := r.db.
-		Preload("Field").  // Preload the field relationship
-		Order("RANDOM()"). // Random order
-		Limit(5).          // Limit to 5 courses
-		Find(&courses).Error
+	return steps, nil
+}
 
+// FindCourseCandidates returns the courses the user is neither enrolled in nor done with, by a
+// certificate or by passing every evaluation, with their enrollments. Learners enrolled in a
+// course of the user are the similar learners counted as co-enrollments.
+func (r *userStrengthRepository) FindCourseCandidates(userId uint64) ([]*payload.CourseCandidate, error) {
+	var candidates []*payload.CourseCandidate
+
+	err := r.db.Raw(`WITH mine AS (
+			SELECT DISTINCT course_id FROM enrolls WHERE user_id = @userId
+		),
+		similar AS (
+			SELECT DISTINCT user_id FROM enrolls
+			WHERE course_id IN (SELECT course_id FROM mine) AND user_id <> @userId
+		),
+		course_evaluates AS (
+			SELECT DISTINCT course_contents.course_id, step_evaluates.id AS step_evaluate_id
+			FROM step_evaluates
+			JOIN steps ON steps.id = step_evaluates.step_id
+			JOIN course_contents ON course_contents.module_id = steps.module_id
+		),
+		completed AS (
+			SELECT course_id FROM (
+				SELECT course_evaluates.course_id, EXISTS (
+					SELECT 1 FROM user_evaluates
+					WHERE user_evaluates.user_id = @userId AND user_evaluates.pass = TRUE
+						AND user_evaluates.step_evaluate_id = course_evaluates.step_evaluate_id
+						AND (user_evaluates.course_id = course_evaluates.course_id OR user_evaluates.course_id IS NULL)
+				) AS passed
+				FROM course_evaluates
+			) evaluated
+			GROUP BY course_id
+			HAVING BOOL_AND(passed)
+			UNION
+			SELECT course_id FROM certificates WHERE user_id = @userId
+		)
+		SELECT courses.id AS course_id, courses.name, courses.field_id, field_types.name AS field_name,
+			field_types.image_url AS field_image_url, courses.created_at,
+			COUNT(DISTINCT enrolls.user_id) AS enrollments,
+			COUNT(DISTINCT enrolls.user_id) FILTER (WHERE enrolls.user_id IN (SELECT user_id FROM similar)) AS co_enrollments
+		FROM courses
+		JOIN field_types ON field_types.id = courses.field_id
+		LEFT JOIN enrolls ON enrolls.course_id = courses.id
+		WHERE courses.id NOT IN (SELECT course_id FROM mine) AND courses.id NOT IN (SELECT course_id FROM completed)
+		GROUP BY courses.id, field_types.id
+		ORDER BY courses.id ASC`, map[string]any{"userId": userId}).
+		Scan(&candidates).Error
 	if err != nil {
-		log.Printf("Error fetching random courses for user %d: %v", userId, err)
+		log.Printf("Error fetching course candidates for user %d: %v", userId, err)
 		return nil, err
 	}
 
-	log.Printf("Found %d random courses for user %d", len(courses), userId)
-	return courses, nil
+	return candidates, nil
 }
@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"backend/internals/db/models"
+	"time"
+)
+
+type WorkshopSessionRepository interface {
+	CreateSession(session *models.WorkshopSession) error
+	FindSessionById(sessionId uint64) (*models.WorkshopSession, error)
+	FindUpcomingSessions(courseId *uint64, fieldId *uint64, from time.Time) ([]*models.WorkshopSession, error)
+	CountRegistrationsBySessionIds(sessionIds []uint64) (map[uint64]map[string]int64, error)
+	Register(sessionId uint64, userId uint64) (*models.SessionRegistration, error)
+	Cancel(sessionId uint64, userId uint64) (*models.SessionRegistration, error)
+	FindRegistrationsByUserId(userId uint64) ([]*models.SessionRegistration, error)
+	FindRegistrationsBySessionId(sessionId uint64) ([]*models.SessionRegistration, error)
+	CountWaitlistedBefore(registration *models.SessionRegistration) (int64, error)
+}
@@ -0,0 +1,238 @@
+package repositories
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/utils"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+var ErrAlreadyRegistered = errors.New("user is already registered for this session")
+var ErrNotRegistered = errors.New("user is not registered for this session")
+
+type workshopSessionRepo struct {
+	db *gorm.DB
+}
+
+func NewWorkshopSessionRepository(db *gorm.DB) WorkshopSessionRepository {
+	return &workshopSessionRepo{
+		db: db,
+	}
+}
+
+func (r *workshopSessionRepo) CreateSession(session *models.WorkshopSession) error {
+	return r.db.Create(session).Error
+}
+
+func (r *workshopSessionRepo) FindSessionById(sessionId uint64) (*models.WorkshopSession, error) {
+	session := new(models.WorkshopSession)
+
+	result := r.db.Preload("Course").Where("id = ?", sessionId).Limit(1).Find(&session)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	return session, nil
+}
+
+func (r *workshopSessionRepo) FindUpcomingSessions(courseId *uint64, fieldId *uint64, from time.Time) ([]*models.WorkshopSession, error) {
+	var sessions []*models.WorkshopSession
+
+	query := r.db.Preload("Course").Where("workshop_sessions.ends_at > ?", from)
+	if courseId != nil {
+		query = query.Where("workshop_sessions.course_id = ?", *courseId)
+	}
+	if fieldId != nil {
+		query = query.Joins("JOIN courses ON courses.id = workshop_sessions.course_id").
+			Where("courses.field_id = ?", *fieldId)
+	}
+
+	result := query.Order("workshop_sessions.starts_at ASC, workshop_sessions.id ASC").Find(&sessions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return sessions, nil
+}
+
+// CountRegistrationsBySessionIds returns the number of registrations per session and status.
+func (r *workshopSessionRepo) CountRegistrationsBySessionIds(sessionIds []uint64) (map[uint64]map[string]int64, error) {
+	var rows []struct {
+		SessionId uint64
+		Status    string
+		Count     int64
+	}
+
+	counts := make(map[uint64]map[string]int64)
+	if len(sessionIds) == 0 {
+		return counts, nil
+	}
+
+	result := r.db.Model(&models.SessionRegistration{}).
+		Select("session_id, status, COUNT(*) AS count").
+		Where("session_id IN ?", sessionIds).
+		Group("session_id, status").
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	for _, row := range rows {
+		if counts[row.SessionId] == nil {
+			counts[row.SessionId] = make(map[string]int64)
+		}
+		counts[row.SessionId][row.Status] = row.Count
+	}
+
+	return counts, nil
+}
+
+// Register takes a seat of the session for the user, or puts the user on the waitlist when the
+// session is full. The session row is locked so concurrent registrations cannot overbook it.
+func (r *workshopSessionRepo) Register(sessionId uint64, userId uint64) (*models.SessionRegistration, error) {
+	registration := new(models.SessionRegistration)
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		session := new(models.WorkshopSession)
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&session, sessionId).Error; err != nil {
+			return err
+		}
+
+		result := tx.Where("session_id = ? AND user_id = ?", sessionId, userId).Limit(1).Find(&registration)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 && *registration.Status != "cancelled" {
+			return ErrAlreadyRegistered
+		}
+
+		var registered int64
+		if err := tx.Model(&models.SessionRegistration{}).
+			Where("session_id = ? AND status = ?", sessionId, "registered").
+			Count(&registered).Error; err != nil {
+			return err
+		}
+
+		status := "registered"
+		if registered >= int64(*session.Capacity) {
+			status = "waitlisted"
+		}
+
+		// a cancelled registration is reused, keeping one row per user and session
+		registration.SessionId = &sessionId
+		registration.UserId = &userId
+		registration.Status = &status
+		registration.QueuedAt = utils.TimeNowPtr()
+		return tx.Save(registration).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return registration, nil
+}
+
+// Cancel cancels the registration of the user and, when it held a seat, promotes the first
+// person of the waitlist. The promoted registration is returned, or nil when nobody was waiting.
+func (r *workshopSessionRepo) Cancel(sessionId uint64, userId uint64) (*models.SessionRegistration, error) {
+	var promoted *models.SessionRegistration
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		session := new(models.WorkshopSession)
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&session, sessionId).Error; err != nil {
+			return err
+		}
+
+		registration := new(models.SessionRegistration)
+		result := tx.Where("session_id = ? AND user_id = ? AND status <> ?", sessionId, userId, "cancelled").Limit(1).Find(&registration)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotRegistered
+		}
+
+		heldSeat := *registration.Status == "registered"
+		registration.Status = utils.Ptr("cancelled")
+		if err := tx.Save(registration).Error; err != nil {
+			return err
+		}
+		if !heldSeat {
+			return nil
+		}
+
+		next := new(models.SessionRegistration)
+		result = tx.Where("session_id = ? AND status = ?", sessionId, "waitlisted").
+			Order("queued_at ASC, id ASC").
+			Limit(1).
+			Find(&next)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		next.Status = utils.Ptr("registered")
+		if err := tx.Save(next).Error; err != nil {
+			return err
+		}
+		promoted = next
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return promoted, nil
+}
+
+func (r *workshopSessionRepo) FindRegistrationsByUserId(userId uint64) ([]*models.SessionRegistration, error) {
+	var registrations []*models.SessionRegistration
+
+	result := r.db.Preload("Session.Course").
+		Joins("JOIN workshop_sessions ON workshop_sessions.id = session_registrations.session_id").
+		Where("session_registrations.user_id = ? AND session_registrations.status <> ?", userId, "cancelled").
+		Order("workshop_sessions.starts_at ASC").
+		Find(&registrations)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return registrations, nil
+}
+
+func (r *workshopSessionRepo) FindRegistrationsBySessionId(sessionId uint64) ([]*models.SessionRegistration, error) {
+	var registrations []*models.SessionRegistration
+
+	result := r.db.Preload("User").
+		Where("session_id = ? AND status <> ?", sessionId, "cancelled").
+		Order("queued_at ASC, id ASC").
+		Find(&registrations)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return registrations, nil
+}
+
+// CountWaitlistedBefore returns how many people are ahead of the registration on the waitlist.
+func (r *workshopSessionRepo) CountWaitlistedBefore(registration *models.SessionRegistration) (int64, error) {
+	var count int64
+
+	result := r.db.Model(&models.SessionRegistration{}).
+		Where("session_id = ? AND status = ?", *registration.SessionId, "waitlisted").
+		Where("queued_at < ? OR (queued_at = ? AND id < ?)", *registration.QueuedAt, *registration.QueuedAt, *registration.Id).
+		Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return count, nil
+}
@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"backend/internals/db/models"
+	"time"
+)
+
+type XapiStatementRepository interface {
+	CreateStatement(statement *models.XapiStatement) (bool, error)
+	UpdateStatement(statement *models.XapiStatement) error
+	FindDueStatements(now time.Time, limit int) ([]*models.XapiStatement, error)
+	FindUserEvaluates(afterId uint64, limit int) ([]*models.UserEvaluate, error)
+	FindUserActivities(offset int, limit int) ([]*models.UserActivity, error)
+}
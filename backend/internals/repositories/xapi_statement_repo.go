@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"backend/internals/db/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type xapiStatementRepo struct {
+	db *gorm.DB
+}
+
+func NewXapiStatementRepository(db *gorm.DB) XapiStatementRepository {
+	return &xapiStatementRepo{
+		db: db,
+	}
+}
+
+// CreateStatement adds the statement to the outbox and tells whether it was added, a statement
+// of the same event is already there otherwise.
+func (r *xapiStatementRepo) CreateStatement(statement *models.XapiStatement) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "statement_id"}},
+		DoNothing: true,
+	}).Create(statement)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r *xapiStatementRepo) UpdateStatement(statement *models.XapiStatement) error {
+	return r.db.Omit(clause.Associations).Save(statement).Error
+}
+
+// FindDueStatements returns the pending statements whose next attempt is due, oldest first.
+func (r *xapiStatementRepo) FindDueStatements(now time.Time, limit int) ([]*models.XapiStatement, error) {
+	var statements []*models.XapiStatement
+
+	result := r.db.Where("status = ? AND next_attempt_at <= ?", "pending", now).
+		Order("next_attempt_at ASC, id ASC").
+		Limit(limit).
+		Find(&statements)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return statements, nil
+}
+
+// FindUserEvaluates returns the evaluations after the id with what their statements describe,
+// a page of the history to replay.
+func (r *xapiStatementRepo) FindUserEvaluates(afterId uint64, limit int) ([]*models.UserEvaluate, error) {
+	var userEvals []*models.UserEvaluate
+
+	result := r.db.Preload("User").
+		Preload("StepEvaluate.Step").
+		Preload("Course").
+		Where("id > ?", afterId).
+		Order("id ASC").
+		Limit(limit).
+		Find(&userEvals)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return userEvals, nil
+}
+
+// FindUserActivities returns a page of the step visits with what their statements describe, a
+// page of the history to replay. Visits have no id, so they are paged in a stable order.
+func (r *xapiStatementRepo) FindUserActivities(offset int, limit int) ([]*models.UserActivity, error) {
+	var activities []*models.UserActivity
+
+	result := r.db.Preload("User").
+		Preload("Step").
+		Preload("Course").
+		Order("user_id ASC, step_id ASC, course_id ASC NULLS FIRST").
+		Offset(offset).
+		Limit(limit).
+		Find(&activities)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return activities, nil
+}
@@ -10,10 +10,16 @@ import (
 	"backend/internals/routes/handler"
 	"backend/internals/routes/middleware"
 	"backend/internals/services"
+	"backend/internals/utils"
 	services2 "backend/internals/utils/services"
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/logger"
@@ -21,6 +27,9 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// shutdownTimeout is how long open requests get to finish once the server shuts down.
+const shutdownTimeout = 10 * time.Second
+
 func SetupRoutes() {
 	// * Repositories
 	var userRepo = repositories.NewUserRepository(db.Gorm)
@@ -39,18 +48,50 @@ func SetupRoutes() {
 	var courseContentRepo = repositories.NewCourseContentRepository(db.Gorm)
 	var userActivityRepo = repositories.NewUserActivityRepository(db.Gorm)
 	var userStrengthRepo = repositories.NewUserStrengthRepository(db.Gorm) // Add UserStrengthRepo
+	var contentImportRepo = repositories.NewContentImportRepository(db.Gorm)
+	var importJobRepo = repositories.NewImportJobRepository(db.Gorm)
+	var workshopSessionRepo = repositories.NewWorkshopSessionRepository(db.Gorm)
+	var sessionAttendanceRepo = repositories.NewSessionAttendanceRepository(db.Gorm)
+	var cohortRepo = repositories.NewCohortRepository(db.Gorm)
+	var teamRepo = repositories.NewTeamRepository(db.Gorm)
+	var certificateRepo = repositories.NewCertificateRepository(db.Gorm)
+	var badgeRepo = repositories.NewBadgeRepository(db.Gorm)
+	var calendarRepo = repositories.NewCalendarRepository(db.Gorm)
+	var notificationRepo = repositories.NewNotificationRepository(db.Gorm)
+	var gemRepo = repositories.NewGemRepository(db.Gorm)
+	var rewardRepo = repositories.NewRewardRepository(db.Gorm)
+	var analyticsRepo = repositories.NewAnalyticsRepository(db.Gorm)
+	var stepSessionRepo = repositories.NewStepSessionRepository(db.Gorm)
+	var xapiStatementRepo = repositories.NewXapiStatementRepository(db.Gorm)
+	var leaderboardRepo = repositories.NewLeaderboardRepository(db.Gorm)
+	var achievementRepo = repositories.NewAchievementRepository(db.Gorm)
+	var dailyActivityRepo = repositories.NewUserDailyActivityRepository(db.Gorm)
+	var helpRequestRepo = repositories.NewHelpRequestRepository(db.Gorm)
 
 	// * third party
 	var oauthService = services2.NewOAuthService(config.Env)
 	var jwtService = services2.NewJwtService()
 	var minioService = services2.NewMinioService(minio.MinioClient)
+	var outlineService = services2.NewOutlineService(config.Env)
+	var eventHub = services2.NewEventHub()
+	var mailer = services2.NewSmtpMailer(config.Env)
+	var lrsClient = services2.NewLrsClient(config.Env)
 
 	// * Services
 	var loginService = services.NewLoginService(userRepo, oauthService, jwtService)
-	var profileService = services.NewProfileService(userRepo)
+	var streakService = services.NewStreakService(dailyActivityRepo, userRepo, config.Env)
+	var profileService = services.NewProfileService(userRepo, stepSessionRepo, streakService)
 	var courseService = services.NewCourseService(courseRepo, fieldTypeRepo)
 	var coursePageService = services.NewCoursePageService(coursePageRepo, courseRepo)
 	var progressService = services.NewProgressService(userRepo, courseRepo)
+	var notificationService = services.NewNotificationService(notificationRepo, userRepo, stepEvalRepo, mailer, config.Env)
+	var gemService = services.NewGemService(gemRepo)
+	var rewardService = services.NewRewardService(rewardRepo)
+	var analyticsService = services.NewAnalyticsService(analyticsRepo, stepSessionRepo)
+	var leaderboardService = services.NewLeaderboardService(leaderboardRepo)
+	var achievementService = services.NewAchievementService(achievementRepo, eventHub)
+	var xapiService = services.NewXapiService(xapiStatementRepo, userRepo, stepRepo, stepEvalRepo, courseRepo, certificateRepo, lrsClient, config.Env)
+	var certificateService = services.NewCertificateService(certificateRepo, userRepo, courseRepo, minioService, config.Env)
 	var stepService = services.NewStepService(
 		stepRepo,
 		stepEvalRepo,
@@ -60,17 +101,35 @@ func SetupRoutes() {
 		userRepo,
 		userEvalRepo,
 		courseContentRepo,
-		moduleRepo)
+		moduleRepo,
+		cohortRepo,
+		teamRepo,
+		gemService,
+		achievementService,
+		notificationService,
+		xapiService,
+		certificateService,
+		eventHub)
 	var articleService = services.NewArticleService(articleRepo)
 	var moduleService = services.NewModuleService(moduleRepo)
-	var moduleStepService = services.NewModuleStepService(stepRepo, userEvalRepo)
+	var moduleStepService = services.NewModuleStepService(stepRepo, userEvalRepo, courseContentRepo)
 	var enrollService = services.NewEnrollService(enrollRepo)
-	var userActivityService = services.NewUserActivityService(userActivityRepo, stepRepo, courseContentRepo)
-	var userStrengthService = services.NewUserStrengthService(userStrengthRepo, fieldTypeRepo, userRepo) // Add UserStrengthService
+	var userActivityService = services.NewUserActivityService(userActivityRepo, stepSessionRepo, stepRepo, courseContentRepo, streakService, achievementService, xapiService)
+	var userStrengthService = services.NewUserStrengthService(userStrengthRepo, userRepo, utils.TimeNow, utils.Val(config.Env.RecommendationSeed)) // Add UserStrengthService
+	var contentImportService = services.NewContentImportService(contentImportRepo, outlineService, minioService, config.Env)
+	var outlineSyncService = services.NewOutlineSyncService(importJobRepo, contentImportService, outlineService, config.Env)
+	var courseBundleService = services.NewCourseBundleService(coursePageRepo, moduleRepo, stepRepo, stepEvalRepo, contentImportRepo, minioService, config.Env)
+	var workshopSessionService = services.NewWorkshopSessionService(workshopSessionRepo, courseRepo)
+	var sessionAttendanceService = services.NewSessionAttendanceService(sessionAttendanceRepo, workshopSessionRepo, config.Env)
+	var cohortService = services.NewCohortService(cohortRepo, courseRepo, courseContentRepo, stepRepo, eventHub)
+	var teamService = services.NewTeamService(teamRepo, courseRepo, workshopSessionRepo, stepEvalRepo)
+	var badgeService = services.NewBadgeService(badgeRepo, userRepo, courseContentRepo, minioService, config.Env)
+	var calendarService = services.NewCalendarService(calendarRepo, workshopSessionRepo, courseRepo, cohortRepo, config.Env)
+	var helpRequestService = services.NewHelpRequestService(helpRequestRepo, workshopSessionRepo, sessionAttendanceRepo, cohortRepo, stepRepo, courseContentRepo, minioService, config.Env)
 
 	// * Controller
 	var loginController = controllers.NewLoginController(config.Env, loginService)
-	var profileController = controllers.NewProfileController(profileService)
+	var profileController = controllers.NewProfileController(profileService, streakService)
 	var courseController = controllers.NewCourseController(courseService)
 	var coursePageController = controllers.NewCoursePageController(coursePageService)
 	var articleController = controllers.NewArticleController(articleService)
@@ -81,6 +140,32 @@ func SetupRoutes() {
 	var stepController = controllers.NewStepController(stepService, config.Env, minioService)
 	var userActivityController = controllers.NewUserActivityController(userActivityService)
 	var userStrengthController = controllers.NewUserStrengthController(userStrengthService) // Add UserStrengthController
+	var outlineController = controllers.NewOutlineController(outlineSyncService)
+	var courseBundleController = controllers.NewCourseBundleController(courseBundleService)
+	var workshopSessionController = controllers.NewWorkshopSessionController(workshopSessionService)
+	var sessionAttendanceController = controllers.NewSessionAttendanceController(sessionAttendanceService)
+	var cohortController = controllers.NewCohortController(cohortService)
+	var teamController = controllers.NewTeamController(teamService)
+	var certificateController = controllers.NewCertificateController(certificateService)
+	var badgeController = controllers.NewBadgeController(badgeService)
+	var calendarController = controllers.NewCalendarController(calendarService)
+	var notificationController = controllers.NewNotificationController(notificationService)
+	var gemController = controllers.NewGemController(gemService)
+	var rewardController = controllers.NewRewardController(rewardService)
+	var analyticsController = controllers.NewAnalyticsController(analyticsService)
+	var leaderboardController = controllers.NewLeaderboardController(leaderboardService)
+	var achievementController = controllers.NewAchievementController(achievementService)
+	var helpRequestController = controllers.NewHelpRequestController(helpRequestService)
+	var eventController = controllers.NewEventController(eventHub)
+
+	// * Background jobs, stopped with the server
+	ctx, cancel := context.WithCancel(context.Background())
+	go outlineSyncService.Run(ctx)
+	go notificationService.Run(ctx)
+	go achievementService.Run(ctx)
+	go analyticsService.Run(ctx)
+	go xapiService.Run(ctx)
+	go userStrengthService.Run(ctx)
 
 	serverAddr := fmt.Sprintf("%s:%d", *config.Env.ServerHost, *config.Env.ServerPort)
 
@@ -112,6 +197,35 @@ func SetupRoutes() {
 	profile := api.Group("/profile", middleware.Jwt())
 	profile.Get("/info", profileController.ProfileUserInfo)
 	profile.Get("/totalgems", profileController.GetUserGems)
+	profile.Put("/timezone", profileController.UpdateTimezone)
+	profile.Get("/activity/heatmap", profileController.GetActivityHeatmap)
+	profile.Get("/time-spent", profileController.GetTimeSpent)
+
+	// * Notification routes
+	notifications := api.Group("/notifications", middleware.Jwt())
+	notifications.Get("/preferences", notificationController.GetPreference)
+	notifications.Put("/preferences", notificationController.UpdatePreference)
+
+	// * Gem routes
+	gems := api.Group("/gems", middleware.Jwt())
+	gems.Get("/ledger", gemController.GetLedger)
+
+	// * Gem shop routes
+	rewards := api.Group("/rewards", middleware.Jwt())
+	rewards.Get("", rewardController.GetCatalog)
+	rewards.Get("/redemptions/mine", rewardController.GetMyRedemptions)
+	rewards.Post("/:rewardItemId/redeem", rewardController.Redeem)
+
+	// * Leaderboard routes
+	leaderboards := api.Group("/leaderboards", middleware.Jwt())
+	leaderboards.Get("", leaderboardController.GetLeaderboard)
+	leaderboards.Get("/me", leaderboardController.GetStanding)
+	leaderboards.Get("/preferences", leaderboardController.GetPreference)
+	leaderboards.Put("/preferences", leaderboardController.UpdatePreference)
+
+	// * Achievement routes
+	achievements := api.Group("/achievements", middleware.Jwt())
+	achievements.Get("", achievementController.GetAchievements)
 
 	step := api.Group("/step", middleware.Jwt())
 	step.Get("/gem/:stepId", stepController.GetGemEachStep)
@@ -162,11 +276,101 @@ func SetupRoutes() {
 	userActivity := api.Group("/user", middleware.Jwt())
 	userActivity.Get("/recent-activities", userActivityController.GetRecentActivity)
 	userActivity.Post("/activity/:stepId", userActivityController.CreateOrUpdateActivity)
+	userActivity.Post("/activity/:stepId/heartbeat", userActivityController.Heartbeat)
 
 	userStrength := api.Group("/strength", middleware.Jwt())
 	userStrength.Get("/strength-info", userStrengthController.GetStrengthDataByUserID)
+	userStrength.Get("/radar", userStrengthController.GetStrengthRadar)
 	userStrength.Get("/suggestions", userStrengthController.GetSuggestionCourse)
 
+	// * Workshop session routes
+	sessions := api.Group("/sessions", middleware.Jwt())
+	sessions.Get("", workshopSessionController.GetUpcomingSessions)
+	sessions.Get("/registrations", workshopSessionController.GetMyRegistrations)
+	sessions.Post("/check-in", sessionAttendanceController.CheckIn)
+	sessions.Post("/:sessionId/register", workshopSessionController.Register)
+	sessions.Post("/:sessionId/cancel", workshopSessionController.Cancel)
+
+	// * Event stream
+	events := api.Group("/events", middleware.Jwt())
+	events.Get("", eventController.GetEventStream)
+
+	// * Cohort routes
+	cohorts := api.Group("/cohorts", middleware.Jwt())
+	cohorts.Get("/:cohortId", cohortController.GetCohort)
+	cohorts.Get("/:cohortId/feed", cohortController.GetUnlockFeed)
+	cohorts.Post("/:cohortId/unlock", cohortController.SetUnlock)
+
+	// * Help queue routes
+	help := api.Group("/help", middleware.Jwt())
+	help.Post("", helpRequestController.RequestHelp)
+	help.Get("/mine", helpRequestController.GetMyHelpRequests)
+	help.Post("/:helpRequestId/cancel", helpRequestController.CancelHelpRequest)
+
+	// * Certificate routes, verification is public so anyone holding a certificate can be checked
+	certificate := api.Group("/certificate", middleware.Jwt())
+	certificate.Get("/mine", certificateController.GetMyCertificates)
+	certificate.Post("/courses/:courseId", certificateController.IssueCertificate)
+	api.Get("/certificates/verify", certificateController.VerifyCertificate)
+
+	// * Open Badges, the hosted documents are public for wallets and verifiers
+	badge := api.Group("/badge", middleware.Jwt())
+	badge.Get("/mine", badgeController.GetMyBadges)
+	badge.Post("/classes/:badgeClassId/claim", badgeController.ClaimBadge)
+	badges := api.Group("/badges")
+	badges.Get("/issuer", badgeController.GetIssuer)
+	badges.Get("/issuer/key", badgeController.GetPublicKey)
+	badges.Get("/revocations", badgeController.GetRevocationList)
+	badges.Get("/classes/:badgeClassId", badgeController.GetBadgeClass)
+	badges.Get("/assertions/:assertionId", badgeController.GetAssertion)
+	badges.Get("/assertions/:assertionId/image", badgeController.GetBakedBadge)
+
+	// * Calendar feeds are fetched by calendar apps, the user feed is authorized by its token instead of the JWT
+	calendar := api.Group("/calendar")
+	calendar.Post("/token", middleware.Jwt(), calendarController.CreateToken)
+	calendar.Delete("/token", middleware.Jwt(), calendarController.RevokeToken)
+	calendar.Get("/feeds/:token.ics", calendarController.GetUserFeed)
+	calendar.Get("/courses/:courseId.ics", calendarController.GetCourseFeed)
+
+	// * Instructor routes
+	instructor := api.Group("/instructor", middleware.Jwt(), middleware.Role(userRepo, "instructor", "admin"))
+	instructor.Get("/sessions/:sessionId/check-in-code", sessionAttendanceController.GetCheckInCode)
+	instructor.Get("/sessions/:sessionId/check-in-qr", sessionAttendanceController.GetCheckInQrCode)
+	instructor.Get("/sessions/:sessionId/roster", sessionAttendanceController.ExportRoster)
+	instructor.Post("/evaluations/:userEvalId/grade", stepController.GradeUserEval)
+	instructor.Post("/teams", teamController.CreateTeam)
+	instructor.Get("/teams/:teamId", teamController.GetTeam)
+	instructor.Post("/teams/:teamId/members", teamController.AddMember)
+	instructor.Put("/stepEvals/:stepEvalId/team", teamController.SetTeamEligible)
+	instructor.Get("/help", helpRequestController.GetQueue)
+	instructor.Get("/help/stats", helpRequestController.GetStepHelpStats)
+	instructor.Post("/help/:helpRequestId/claim", helpRequestController.ClaimHelpRequest)
+	instructor.Post("/help/:helpRequestId/resolve", helpRequestController.ResolveHelpRequest)
+	instructor.Get("/redemptions", rewardController.GetRedemptions)
+	instructor.Post("/redemptions/:redemptionId/fulfill", rewardController.Fulfill)
+	instructor.Get("/analytics/courses/:courseId/steps", analyticsController.GetStepFunnel)
+	instructor.Get("/analytics/courses/:courseId/time-spent", analyticsController.GetCourseTimeSpent)
+
+	// * Outline content sync
+	outline := api.Group("/outline")
+	outline.Post("/webhook", outlineController.Webhook)
+
+	// * Admin routes
+	admin := api.Group("/admin", middleware.Jwt(), middleware.Role(userRepo, "admin"))
+	admin.Get("/outline/jobs", outlineController.GetImportJobs)
+	admin.Get("/courses/:courseId/bundle", courseBundleController.ExportCourse)
+	admin.Post("/courses/bundle", courseBundleController.ImportCourse)
+	admin.Post("/sessions", workshopSessionController.CreateSession)
+	admin.Post("/cohorts", cohortController.CreateCohort)
+	admin.Post("/cohorts/:cohortId/members", cohortController.AddMember)
+	admin.Post("/badges/classes", badgeController.CreateBadgeClass)
+	admin.Post("/badges/assertions/:assertionId/revoke", badgeController.RevokeAssertion)
+	admin.Post("/calendar/events", calendarController.CreateEvent)
+	admin.Post("/gems/adjustments", gemController.Adjust)
+	admin.Get("/rewards", rewardController.GetItems)
+	admin.Post("/rewards", rewardController.CreateItem)
+	admin.Put("/rewards/:rewardItemId", rewardController.UpdateItem)
+
 	// Custom handler to set Content-Type header based on file extension
 	api.Use("/static", func(c *fiber.Ctx) error {
 		filePath := c.Path()
@@ -181,7 +385,8 @@ func SetupRoutes() {
 	// * Not found
 	api.Use(handler.NotFoundHandler)
 
-	ListenAndServe(app, serverAddr)
+	// open event streams only end once the hub is closed, so close it before shutting down
+	ListenAndServe(app, serverAddr, cancel, eventHub.Close)
 }
 
 func getContentType(filename string) string {
@@ -207,10 +412,26 @@ func NewFiberApp() *fiber.App {
 	return app
 }
 
-func ListenAndServe(app *fiber.App, serverAddr string) {
+// ListenAndServe serves until SIGINT or SIGTERM, then runs the shutdown hooks in order and
+// waits for the open requests to finish.
+func ListenAndServe(app *fiber.App, serverAddr string, onShutdown ...func()) {
+	go func() {
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+		<-quit
+
+		logrus.Printf("[Server] Shutting down")
+		for _, hook := range onShutdown {
+			hook()
+		}
+		if err := app.ShutdownWithTimeout(shutdownTimeout); err != nil {
+			logrus.Errorf("[Server] Unable to shut down server: %v", err)
+		}
+	}()
+
 	err := app.Listen(serverAddr)
 	if err != nil {
 		panic(fmt.Errorf("[Server] Unable to start server: %w", err))
 	}
-	logrus.Printf("[Server] Server started successfully")
+	logrus.Printf("[Server] Server stopped")
 }
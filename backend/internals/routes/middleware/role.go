@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"backend/internals/entities/response"
+	"backend/internals/repositories"
+	"backend/internals/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"strconv"
+)
+
+// Role only lets users with one of the given roles through. It must be mounted after Jwt.
+func Role(userRepo repositories.UserRepository, roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims := c.Locals("user").(*jwt.Token).Claims.(jwt.MapClaims)
+		userId := claims["userId"].(float64)
+
+		user, err := userRepo.FindUserByID(utils.Ptr(strconv.Itoa(int(userId))))
+		if err != nil || user.Role == nil || !utils.Contains(roles, *user.Role) {
+			return c.Status(fiber.StatusForbidden).JSON(response.ErrorResponse{
+				Code:    strconv.Itoa(fiber.StatusForbidden),
+				Message: "Forbidden access",
+			})
+		}
+
+		return c.Next()
+	}
+}
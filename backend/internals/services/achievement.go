@@ -0,0 +1,12 @@
+package services
+
+import (
+	"backend/internals/entities/payload"
+	"context"
+)
+
+type AchievementService interface {
+	Record(userId uint64, trigger string)
+	GetAchievements(userId uint64) ([]*payload.AchievementInfo, error)
+	Run(ctx context.Context)
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/repositories"
+	"backend/internals/utils"
+	utilServices "backend/internals/utils/services"
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+// achievementRule awards an achievement once the metric counted for a user reaches the threshold.
+// The rule is checked whenever its trigger is recorded for the user.
+type achievementRule struct {
+	Code        string
+	Name        string
+	Description string
+	Trigger     string
+	Metric      string
+	Threshold   int64
+}
+
+var achievementRules = []achievementRule{
+	{
+		Code:        "first_comment",
+		Name:        "First Words",
+		Description: "Post your first comment on a step",
+		Trigger:     payload.AchievementTriggerCommentCreated,
+		Metric:      payload.AchievementMetricCommentsCreated,
+		Threshold:   1,
+	},
+	{
+		Code:        "ten_upvotes",
+		Name:        "Helpful Voice",
+		Description: "Receive 10 upvotes from others on your comments",
+		Trigger:     payload.AchievementTriggerUpVoteReceived,
+		Metric:      payload.AchievementMetricUpVotesReceived,
+		Threshold:   10,
+	},
+	{
+		Code:        "module_in_a_day",
+		Name:        "One Day Sprint",
+		Description: "Pass every evaluation of a module within a day of your first submission",
+		Trigger:     payload.AchievementTriggerSubmissionPassed,
+		Metric:      payload.AchievementMetricModulesFinishedInDay,
+		Threshold:   1,
+	},
+	{
+		Code:        "field_complete",
+		Name:        "Field Master",
+		Description: "Complete every course of a field",
+		Trigger:     payload.AchievementTriggerSubmissionPassed,
+		Metric:      payload.AchievementMetricFieldsCompleted,
+		Threshold:   1,
+	},
+	{
+		Code:        "fifty_steps",
+		Name:        "Explorer",
+		Description: "Visit 50 different steps",
+		Trigger:     payload.AchievementTriggerActivityRecorded,
+		Metric:      payload.AchievementMetricStepsVisited,
+		Threshold:   50,
+	},
+}
+
+type achievementTrigger struct {
+	userId  uint64
+	trigger string
+}
+
+type achievementService struct {
+	achievementRepo repositories.AchievementRepository
+	eventHub        utilServices.EventHub
+	rules           []achievementRule
+	triggers        chan achievementTrigger
+}
+
+func NewAchievementService(achievementRepo repositories.AchievementRepository, eventHub utilServices.EventHub) AchievementService {
+	return &achievementService{
+		achievementRepo: achievementRepo,
+		eventHub:        eventHub,
+		rules:           achievementRules,
+		triggers:        make(chan achievementTrigger, 100),
+	}
+}
+
+// Record queues the rules of the trigger to be checked for the user, so that counting does not
+// slow down the request recording it.
+func (r *achievementService) Record(userId uint64, trigger string) {
+	select {
+	case r.triggers <- achievementTrigger{userId: userId, trigger: trigger}:
+	default:
+		// queue is full, achievements left behind are awarded when the user lists them
+	}
+}
+
+// Run checks the recorded triggers until the context is cancelled.
+func (r *achievementService) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case recorded := <-r.triggers:
+			if err := r.evaluate(recorded.userId, recorded.trigger); err != nil {
+				logrus.Errorf("[ACHIEVEMENT] Unable to check %s achievements of user %d: %v", recorded.trigger, recorded.userId, err)
+			}
+		}
+	}
+}
+
+// GetAchievements lists every achievement with the progress of the user toward it, awarding those
+// reached without being checked yet.
+func (r *achievementService) GetAchievements(userId uint64) ([]*payload.AchievementInfo, error) {
+	earned, err := r.earned(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*payload.AchievementInfo, 0, len(r.rules))
+	for _, rule := range r.rules {
+		info := &payload.AchievementInfo{
+			Code:        rule.Code,
+			Name:        rule.Name,
+			Description: rule.Description,
+			Threshold:   rule.Threshold,
+		}
+
+		if achievement, ok := earned[rule.Code]; ok {
+			info.Progress = rule.Threshold
+			info.Earned = true
+			info.EarnedAt = achievement.EarnedAt
+		} else {
+			achievement, progress, err := r.check(userId, rule)
+			if err != nil {
+				return nil, err
+			}
+			info.Progress = min(progress, rule.Threshold)
+			if achievement != nil {
+				info.Earned = true
+				info.EarnedAt = achievement.EarnedAt
+			}
+		}
+
+		result = append(result, info)
+	}
+
+	return result, nil
+}
+
+func (r *achievementService) evaluate(userId uint64, trigger string) error {
+	earned, err := r.earned(userId)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range r.rules {
+		if rule.Trigger != trigger {
+			continue
+		}
+		if _, ok := earned[rule.Code]; ok {
+			continue
+		}
+		if _, _, err := r.check(userId, rule); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// check counts the metric of the rule and awards the achievement when it reaches the threshold,
+// telling the user about it.
+func (r *achievementService) check(userId uint64, rule achievementRule) (*models.UserAchievement, int64, error) {
+	progress, err := r.achievementRepo.CountMetric(rule.Metric, userId)
+	if err != nil {
+		return nil, 0, err
+	}
+	if progress < rule.Threshold {
+		return nil, progress, nil
+	}
+
+	achievement := &models.UserAchievement{
+		UserId:   &userId,
+		Code:     utils.Ptr(rule.Code),
+		EarnedAt: utils.TimeNowPtr(),
+	}
+	created, err := r.achievementRepo.CreateUserAchievement(achievement)
+	if err != nil {
+		return nil, 0, err
+	}
+	if created {
+		r.eventHub.Publish(utilServices.UserTopic(userId), &payload.Event{
+			Type: payload.EventAchievement,
+			Data: &payload.AchievementInfo{
+				Code:        rule.Code,
+				Name:        rule.Name,
+				Description: rule.Description,
+				Threshold:   rule.Threshold,
+				Progress:    rule.Threshold,
+				Earned:      true,
+				EarnedAt:    achievement.EarnedAt,
+			},
+		})
+	}
+
+	return achievement, progress, nil
+}
+
+func (r *achievementService) earned(userId uint64) (map[string]*models.UserAchievement, error) {
+	achievements, err := r.achievementRepo.FindUserAchievements(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	earned := make(map[string]*models.UserAchievement, len(achievements))
+	for _, achievement := range achievements {
+		earned[*achievement.Code] = achievement
+	}
+
+	return earned, nil
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/utils"
+	utilServices "backend/internals/utils/services"
+	mockRepositories "backend/mocks/repositories"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type AchievementServiceTestSuite struct {
+	suite.Suite
+}
+
+func (suite *AchievementServiceTestSuite) TestGetAchievementsWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockAchievementRepo := new(mockRepositories.AchievementRepository)
+
+	earnedAt := utils.TimeNowPtr()
+	mockAchievementRepo.EXPECT().FindUserAchievements(uint64(9)).Return([]*models.UserAchievement{
+		{Code: utils.Ptr("first_comment"), EarnedAt: earnedAt},
+	}, nil)
+	mockAchievementRepo.EXPECT().CountMetric(payload.AchievementMetricUpVotesReceived, uint64(9)).Return(4, nil)
+	mockAchievementRepo.EXPECT().CountMetric(payload.AchievementMetricModulesFinishedInDay, uint64(9)).Return(0, nil)
+	mockAchievementRepo.EXPECT().CountMetric(payload.AchievementMetricFieldsCompleted, uint64(9)).Return(0, nil)
+	mockAchievementRepo.EXPECT().CountMetric(payload.AchievementMetricStepsVisited, uint64(9)).Return(12, nil)
+
+	underTest := NewAchievementService(mockAchievementRepo, utilServices.NewEventHub())
+
+	achievements, err := underTest.GetAchievements(9)
+
+	is.Nil(err)
+	is.Len(achievements, len(achievementRules))
+	is.Equal("first_comment", achievements[0].Code)
+	is.True(achievements[0].Earned)
+	is.Equal(earnedAt, achievements[0].EarnedAt)
+	is.False(achievements[1].Earned)
+	is.Equal(int64(4), achievements[1].Progress)
+	is.Equal(int64(10), achievements[1].Threshold)
+	// the count of a comment is not asked again once the achievement is earned
+	mockAchievementRepo.AssertNotCalled(suite.T(), "CountMetric", payload.AchievementMetricCommentsCreated, mock.Anything)
+}
+
+func (suite *AchievementServiceTestSuite) TestGetAchievementsWhenReachedUnchecked() {
+	is := assert.New(suite.T())
+
+	mockAchievementRepo := new(mockRepositories.AchievementRepository)
+
+	mockAchievementRepo.EXPECT().FindUserAchievements(uint64(9)).Return(nil, nil)
+	mockAchievementRepo.EXPECT().CountMetric(payload.AchievementMetricStepsVisited, uint64(9)).Return(64, nil)
+	mockAchievementRepo.EXPECT().CountMetric(mock.Anything, uint64(9)).Return(0, nil)
+	mockAchievementRepo.EXPECT().CreateUserAchievement(mock.MatchedBy(func(achievement *models.UserAchievement) bool {
+		return *achievement.Code == "fifty_steps" && *achievement.UserId == 9
+	})).Return(true, nil)
+
+	underTest := NewAchievementService(mockAchievementRepo, utilServices.NewEventHub())
+
+	achievements, err := underTest.GetAchievements(9)
+
+	is.Nil(err)
+	explorer := achievements[len(achievements)-1]
+	is.Equal("fifty_steps", explorer.Code)
+	is.True(explorer.Earned)
+	is.Equal(int64(50), explorer.Progress)
+}
+
+func (suite *AchievementServiceTestSuite) TestRunWhenThresholdReached() {
+	is := assert.New(suite.T())
+
+	mockAchievementRepo := new(mockRepositories.AchievementRepository)
+	eventHub := utilServices.NewEventHub()
+
+	mockAchievementRepo.EXPECT().FindUserAchievements(uint64(9)).Return(nil, nil)
+	mockAchievementRepo.EXPECT().CountMetric(payload.AchievementMetricCommentsCreated, uint64(9)).Return(1, nil)
+	mockAchievementRepo.EXPECT().CreateUserAchievement(mock.Anything).Return(true, nil)
+
+	events, unsubscribe := eventHub.Subscribe(utilServices.UserTopic(9))
+	defer unsubscribe()
+
+	underTest := NewAchievementService(mockAchievementRepo, eventHub)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go underTest.Run(ctx)
+
+	underTest.Record(9, payload.AchievementTriggerCommentCreated)
+
+	event := <-events
+	is.Equal(payload.EventAchievement, event.Type)
+	is.Equal("first_comment", event.Data.(*payload.AchievementInfo).Code)
+	// only the rules of the recorded trigger are checked
+	mockAchievementRepo.AssertNotCalled(suite.T(), "CountMetric", payload.AchievementMetricUpVotesReceived, mock.Anything)
+}
+
+func TestAchievementService(t *testing.T) {
+	suite.Run(t, new(AchievementServiceTestSuite))
+}
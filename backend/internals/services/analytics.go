@@ -0,0 +1,12 @@
+package services
+
+import (
+	"backend/internals/entities/payload"
+	"context"
+)
+
+type AnalyticsService interface {
+	GetStepFunnel(courseId uint64, moduleId *uint64) (*payload.StepFunnelReport, error)
+	GetCourseTimeSpent(courseId uint64) (*payload.CourseTimeSpentReport, error)
+	Run(ctx context.Context)
+}
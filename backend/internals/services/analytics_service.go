@@ -0,0 +1,126 @@
+package services
+
+import (
+	"backend/internals/entities/payload"
+	"backend/internals/repositories"
+	"backend/internals/utils"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	analyticsRefreshInterval = 15 * time.Minute
+	stepFunnelHighlights     = 3
+)
+
+type analyticsService struct {
+	analyticsRepo   repositories.AnalyticsRepository
+	stepSessionRepo repositories.StepSessionRepository
+}
+
+func NewAnalyticsService(analyticsRepo repositories.AnalyticsRepository, stepSessionRepo repositories.StepSessionRepository) AnalyticsService {
+	return &analyticsService{
+		analyticsRepo:   analyticsRepo,
+		stepSessionRepo: stepSessionRepo,
+	}
+}
+
+// GetStepFunnel reports the funnel of the steps of a course, or of one of its modules, as of the
+// last refresh. The steps losing the most learners before the next one are highlighted.
+func (r *analyticsService) GetStepFunnel(courseId uint64, moduleId *uint64) (*payload.StepFunnelReport, error) {
+	steps, err := r.analyticsRepo.FindStepFunnel(courseId, moduleId)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &payload.StepFunnelReport{
+		CourseId: &courseId,
+		ModuleId: moduleId,
+		Steps:    steps,
+	}
+	if len(steps) > 0 {
+		report.RefreshedAt = steps[0].RefreshedAt
+	}
+
+	for i, step := range steps {
+		if step.Submissions > 0 {
+			step.AvgAttempts = utils.Ptr(float64(step.Attempts) / float64(step.Submissions))
+		}
+		if graded := step.Attempts - step.PendingSubmissions; graded > 0 {
+			step.FailureRate = utils.Ptr(float64(graded-step.PassedSubmissions) / float64(graded))
+		}
+		// the last step has no next one to drop off before
+		if i+1 < len(steps) {
+			step.DropOff = max(step.Reached-steps[i+1].Reached, 0)
+			if step.Reached > 0 {
+				step.DropOffRate = utils.Ptr(float64(step.DropOff) / float64(step.Reached))
+			}
+		}
+	}
+
+	highlightDropOffs(steps)
+
+	return report, nil
+}
+
+// highlightDropOffs marks the steps with the biggest drop-off, the larger share of learners first
+// among equal ones.
+func highlightDropOffs(steps []*payload.StepFunnel) {
+	ranked := make([]*payload.StepFunnel, 0, len(steps))
+	for _, step := range steps {
+		if step.DropOff > 0 {
+			ranked = append(ranked, step)
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].DropOff != ranked[j].DropOff {
+			return ranked[i].DropOff > ranked[j].DropOff
+		}
+		return *ranked[i].DropOffRate > *ranked[j].DropOffRate
+	})
+
+	for i := 0; i < len(ranked) && i < stepFunnelHighlights; i++ {
+		ranked[i].Highlighted = true
+	}
+}
+
+// GetCourseTimeSpent sums up the active time of the learners of the course per module and step,
+// in the order of the course content.
+func (r *analyticsService) GetCourseTimeSpent(courseId uint64) (*payload.CourseTimeSpentReport, error) {
+	modules, err := r.stepSessionRepo.FindModuleTimeSpentStats(courseId)
+	if err != nil {
+		return nil, err
+	}
+
+	steps, err := r.stepSessionRepo.FindStepTimeSpentStats(courseId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &payload.CourseTimeSpentReport{
+		CourseId: &courseId,
+		Modules:  modules,
+		Steps:    steps,
+	}, nil
+}
+
+// Run refreshes the precomputed analytics periodically until the context is cancelled.
+func (r *analyticsService) Run(ctx context.Context) {
+	ticker := time.NewTicker(analyticsRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.analyticsRepo.RefreshStepFunnel(); err != nil {
+				logrus.Errorf("[ANALYTICS] Unable to refresh step funnel: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"backend/internals/entities/payload"
+	"backend/internals/utils"
+	mockRepositories "backend/mocks/repositories"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type AnalyticsServiceTestSuite struct {
+	suite.Suite
+}
+
+func mockStepFunnel(stepId uint64, reached int64) *payload.StepFunnel {
+	return &payload.StepFunnel{
+		StepId:      utils.Ptr(stepId),
+		Reached:     reached,
+		RefreshedAt: utils.TimeNowPtr(),
+	}
+}
+
+func (suite *AnalyticsServiceTestSuite) TestGetStepFunnelWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockAnalyticsRepo := new(mockRepositories.AnalyticsRepository)
+
+	first := mockStepFunnel(1, 40)
+	first.Evaluations = 2
+	first.Submissions = 50
+	first.Attempts = 80
+	first.PassedSubmissions = 45
+	first.PendingSubmissions = 5
+	mockAnalyticsRepo.EXPECT().FindStepFunnel(uint64(3), (*uint64)(nil)).Return([]*payload.StepFunnel{
+		first, mockStepFunnel(2, 38), mockStepFunnel(3, 20), mockStepFunnel(4, 19), mockStepFunnel(5, 10), mockStepFunnel(6, 4),
+	}, nil)
+
+	underTest := NewAnalyticsService(mockAnalyticsRepo, nil)
+
+	report, err := underTest.GetStepFunnel(3, nil)
+
+	is.Nil(err)
+	is.Len(report.Steps, 6)
+	is.Equal(first.RefreshedAt, report.RefreshedAt)
+	is.InDelta(1.6, *first.AvgAttempts, 0.001)
+	is.InDelta(0.4, *first.FailureRate, 0.001)
+	is.Equal(int64(2), first.DropOff)
+	is.InDelta(0.05, *first.DropOffRate, 0.001)
+
+	highlighted := []uint64{}
+	for _, step := range report.Steps {
+		if step.Highlighted {
+			highlighted = append(highlighted, *step.StepId)
+		}
+	}
+	is.Equal([]uint64{2, 4, 5}, highlighted)
+	is.Equal(int64(0), report.Steps[5].DropOff)
+	is.Nil(report.Steps[5].DropOffRate)
+	is.Nil(report.Steps[5].AvgAttempts)
+	is.Nil(report.Steps[5].FailureRate)
+}
+
+func (suite *AnalyticsServiceTestSuite) TestGetStepFunnelWhenNoSteps() {
+	is := assert.New(suite.T())
+
+	mockAnalyticsRepo := new(mockRepositories.AnalyticsRepository)
+	mockAnalyticsRepo.EXPECT().FindStepFunnel(uint64(3), utils.Ptr(uint64(5))).Return(nil, nil)
+
+	underTest := NewAnalyticsService(mockAnalyticsRepo, nil)
+
+	report, err := underTest.GetStepFunnel(3, utils.Ptr(uint64(5)))
+
+	is.Nil(err)
+	is.Empty(report.Steps)
+	is.Nil(report.RefreshedAt)
+	is.Equal(uint64(5), *report.ModuleId)
+}
+
+func (suite *AnalyticsServiceTestSuite) TestGetStepFunnelWhenFailed() {
+	is := assert.New(suite.T())
+
+	mockAnalyticsRepo := new(mockRepositories.AnalyticsRepository)
+	mockAnalyticsRepo.EXPECT().FindStepFunnel(uint64(3), (*uint64)(nil)).Return(nil, fmt.Errorf("database error"))
+
+	underTest := NewAnalyticsService(mockAnalyticsRepo, nil)
+
+	report, err := underTest.GetStepFunnel(3, nil)
+
+	is.Nil(report)
+	is.Error(err)
+}
+
+func (suite *AnalyticsServiceTestSuite) TestGetCourseTimeSpentWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockStepSessionRepo := new(mockRepositories.StepSessionRepository)
+	mockStepSessionRepo.EXPECT().FindModuleTimeSpentStats(uint64(3)).Return([]*payload.TimeSpentStats{
+		{Id: utils.Ptr(uint64(5)), Learners: 2, TotalSeconds: 900, MedianSeconds: utils.Ptr(450.0)},
+	}, nil)
+	mockStepSessionRepo.EXPECT().FindStepTimeSpentStats(uint64(3)).Return([]*payload.TimeSpentStats{
+		{Id: utils.Ptr(uint64(8)), Learners: 2, TotalSeconds: 600},
+		{Id: utils.Ptr(uint64(9)), Learners: 1, TotalSeconds: 300},
+	}, nil)
+
+	underTest := NewAnalyticsService(nil, mockStepSessionRepo)
+
+	report, err := underTest.GetCourseTimeSpent(3)
+
+	is.Nil(err)
+	is.Equal(uint64(3), *report.CourseId)
+	is.Len(report.Modules, 1)
+	is.Len(report.Steps, 2)
+}
+
+func (suite *AnalyticsServiceTestSuite) TestGetCourseTimeSpentWhenFailed() {
+	is := assert.New(suite.T())
+
+	mockStepSessionRepo := new(mockRepositories.StepSessionRepository)
+	mockStepSessionRepo.EXPECT().FindModuleTimeSpentStats(uint64(3)).Return(nil, fmt.Errorf("database error"))
+
+	underTest := NewAnalyticsService(nil, mockStepSessionRepo)
+
+	report, err := underTest.GetCourseTimeSpent(3)
+
+	is.Nil(report)
+	is.Error(err)
+}
+
+func TestAnalyticsService(t *testing.T) {
+	suite.Run(t, new(AnalyticsServiceTestSuite))
+}
@@ -0,0 +1,19 @@
+package services
+
+import (
+	"backend/internals/entities/payload"
+	"mime/multipart"
+)
+
+type BadgeService interface {
+	CreateBadgeClass(body *payload.CreateBadgeClass, image *multipart.FileHeader) (*payload.BadgeClassInfo, error)
+	ClaimBadge(userId uint64, badgeClassId uint64) (*payload.BadgeAssertionInfo, error)
+	GetMyBadges(userId uint64) ([]*payload.BadgeAssertionInfo, error)
+	RevokeAssertion(uid string, body *payload.RevokeBadgeAssertion) (*payload.BadgeAssertionInfo, error)
+	GetIssuer() (*payload.OpenBadgeIssuer, error)
+	GetPublicKey() (*payload.OpenBadgeCryptographicKey, error)
+	GetRevocationList() (*payload.OpenBadgeRevocationList, error)
+	GetBadgeClass(badgeClassId uint64) (*payload.OpenBadgeClass, error)
+	GetAssertion(uid string) (*payload.OpenBadgeAssertion, error)
+	BakeBadge(uid string) ([]byte, error)
+}
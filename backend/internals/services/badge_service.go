@@ -0,0 +1,477 @@
+package services
+
+import (
+	"backend/internals/config"
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/repositories"
+	"backend/internals/utils"
+	utilServices "backend/internals/utils/services"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"mime/multipart"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+var ErrBadgeRevoked = errors.New("badge assertion is revoked")
+
+type badgeService struct {
+	badgeRepo         repositories.BadgeRepository
+	userRepo          repositories.UserRepository
+	courseContentRepo repositories.CourseContentRepository
+	minioService      utilServices.MinioService
+	conf              *config.Config
+}
+
+func NewBadgeService(
+	badgeRepo repositories.BadgeRepository,
+	userRepo repositories.UserRepository,
+	courseContentRepo repositories.CourseContentRepository,
+	minioService utilServices.MinioService,
+	conf *config.Config) BadgeService {
+	return &badgeService{
+		badgeRepo:         badgeRepo,
+		userRepo:          userRepo,
+		courseContentRepo: courseContentRepo,
+		minioService:      minioService,
+		conf:              conf,
+	}
+}
+
+func (r *badgeService) CreateBadgeClass(body *payload.CreateBadgeClass, image *multipart.FileHeader) (*payload.BadgeClassInfo, error) {
+	// the badge of a module is awarded within a course, so the module must be part of it
+	if body.ModuleId != nil {
+		courseContent, err := r.courseContentRepo.FindCourseContentByCourseIdAndModuleId(body.CourseId, body.ModuleId)
+		if err != nil {
+			return nil, err
+		}
+		if courseContent == nil {
+			return nil, fmt.Errorf("module %d is not part of course %d", *body.ModuleId, *body.CourseId)
+		}
+	}
+
+	existing, err := r.badgeRepo.FindBadgeClass(*body.CourseId, body.ModuleId)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("badge class %d is already defined for it", *existing.Id)
+	}
+
+	// baking writes the assertion into a PNG chunk
+	if image.Header.Get("Content-Type") != "image/png" {
+		return nil, fmt.Errorf("badge image must be a PNG")
+	}
+
+	file, err := image.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	objectName := fmt.Sprintf("badges/course%d_%s.png", *body.CourseId, uuid.NewString())
+	if err := r.minioService.PutObject(context.Background(), *r.conf.MinioS3BucketName, objectName, file, image); err != nil {
+		return nil, fmt.Errorf("failed to upload badge image: %w", err)
+	}
+
+	badgeClass := &models.BadgeClass{
+		CourseId:    body.CourseId,
+		ModuleId:    body.ModuleId,
+		Name:        body.Name,
+		Description: body.Description,
+		Criteria:    body.Criteria,
+		Image:       &objectName,
+	}
+	if err := r.badgeRepo.CreateBadgeClass(badgeClass); err != nil {
+		return nil, err
+	}
+
+	return r.badgeClassInfo(badgeClass), nil
+}
+
+// ClaimBadge awards the badge class to the user once its course or module is fully passed.
+// Claiming again returns the assertion issued before, even when it was revoked.
+func (r *badgeService) ClaimBadge(userId uint64, badgeClassId uint64) (*payload.BadgeAssertionInfo, error) {
+	badgeClass, err := r.findBadgeClass(badgeClassId)
+	if err != nil {
+		return nil, err
+	}
+
+	assertion, err := r.badgeRepo.FindAssertion(badgeClassId, userId)
+	if err != nil {
+		return nil, err
+	}
+	if assertion != nil {
+		return r.assertionInfo(assertion), nil
+	}
+
+	completion, err := r.badgeRepo.FindCompletion(userId, *badgeClass.CourseId, badgeClass.ModuleId)
+	if err != nil {
+		return nil, err
+	}
+	if completion.Required == 0 {
+		return nil, fmt.Errorf("badge class %d has no evaluations to pass", badgeClassId)
+	}
+	if completion.Passed < completion.Required {
+		return nil, fmt.Errorf("badge class %d is not earned yet, %d of %d evaluations passed", badgeClassId, completion.Passed, completion.Required)
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate recipient salt: %w", err)
+	}
+
+	assertion = &models.BadgeAssertion{
+		Uid:           utils.Ptr(uuid.NewString()),
+		BadgeClassId:  badgeClass.Id,
+		BadgeClass:    badgeClass,
+		UserId:        &userId,
+		RecipientSalt: utils.Ptr(hex.EncodeToString(salt)),
+		IssuedOn:      completion.CompletedAt,
+	}
+	if err := r.badgeRepo.CreateAssertion(assertion); err != nil {
+		return nil, err
+	}
+
+	return r.assertionInfo(assertion), nil
+}
+
+func (r *badgeService) GetMyBadges(userId uint64) ([]*payload.BadgeAssertionInfo, error) {
+	assertions, err := r.badgeRepo.FindAssertionsByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]*payload.BadgeAssertionInfo, 0, len(assertions))
+	for _, assertion := range assertions {
+		infos = append(infos, r.assertionInfo(assertion))
+	}
+
+	return infos, nil
+}
+
+func (r *badgeService) RevokeAssertion(uid string, body *payload.RevokeBadgeAssertion) (*payload.BadgeAssertionInfo, error) {
+	assertion, err := r.findAssertion(uid)
+	if err != nil {
+		return nil, err
+	}
+
+	assertion.RevokedAt = utils.TimeNowPtr()
+	assertion.RevocationReason = body.Reason
+	if err := r.badgeRepo.RevokeAssertion(assertion); err != nil {
+		return nil, err
+	}
+
+	return r.assertionInfo(assertion), nil
+}
+
+func (r *badgeService) GetIssuer() (*payload.OpenBadgeIssuer, error) {
+	issuer := &payload.OpenBadgeIssuer{
+		Context: payload.OpenBadgesContext,
+		Type:    "Issuer",
+		Id:      r.badgeUrl("issuer"),
+		Name:    utils.Val(r.conf.BadgeIssuerName),
+		Email:   utils.Val(r.conf.BadgeIssuerEmail),
+	}
+	if r.conf.FrontendScheme != nil && r.conf.FrontendUrl != nil {
+		issuer.Url = fmt.Sprintf("%s://%s", *r.conf.FrontendScheme, *r.conf.FrontendUrl)
+	}
+
+	// signed badges are checked against the key and the revocation list of their issuer
+	if r.signing() {
+		issuer.PublicKey = r.badgeUrl("issuer", "key")
+		issuer.RevocationList = r.badgeUrl("revocations")
+	}
+
+	return issuer, nil
+}
+
+func (r *badgeService) GetPublicKey() (*payload.OpenBadgeCryptographicKey, error) {
+	key, err := r.signingKey()
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &payload.OpenBadgeCryptographicKey{
+		Context:      payload.OpenBadgesContext,
+		Type:         "CryptographicKey",
+		Id:           r.badgeUrl("issuer", "key"),
+		Owner:        r.badgeUrl("issuer"),
+		PublicKeyPem: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+	}, nil
+}
+
+func (r *badgeService) GetRevocationList() (*payload.OpenBadgeRevocationList, error) {
+	assertions, err := r.badgeRepo.FindRevokedAssertions()
+	if err != nil {
+		return nil, err
+	}
+
+	revoked := make([]*payload.OpenBadgeRevokedAssertion, 0, len(assertions))
+	for _, assertion := range assertions {
+		revoked = append(revoked, &payload.OpenBadgeRevokedAssertion{
+			Id:               r.badgeUrl("assertions", *assertion.Uid),
+			RevocationReason: utils.Val(assertion.RevocationReason),
+		})
+	}
+
+	return &payload.OpenBadgeRevocationList{
+		Context:           payload.OpenBadgesContext,
+		Type:              "RevocationList",
+		Id:                r.badgeUrl("revocations"),
+		Issuer:            r.badgeUrl("issuer"),
+		RevokedAssertions: revoked,
+	}, nil
+}
+
+func (r *badgeService) GetBadgeClass(badgeClassId uint64) (*payload.OpenBadgeClass, error) {
+	badgeClass, err := r.findBadgeClass(badgeClassId)
+	if err != nil {
+		return nil, err
+	}
+
+	image, err := url.JoinPath(*r.conf.MinioS3Endpoint, *r.conf.MinioS3BucketName, *badgeClass.Image)
+	if err != nil {
+		return nil, err
+	}
+
+	return &payload.OpenBadgeClass{
+		Context:     payload.OpenBadgesContext,
+		Type:        "BadgeClass",
+		Id:          r.badgeUrl("classes", strconv.FormatUint(*badgeClass.Id, 10)),
+		Name:        *badgeClass.Name,
+		Description: *badgeClass.Description,
+		Image:       image,
+		Criteria:    &payload.OpenBadgeCriteria{Narrative: *badgeClass.Criteria},
+		Issuer:      r.badgeUrl("issuer"),
+	}, nil
+}
+
+// GetAssertion returns the hosted assertion, reduced to its revocation when it was revoked.
+func (r *badgeService) GetAssertion(uid string) (*payload.OpenBadgeAssertion, error) {
+	assertion, err := r.findAssertion(uid)
+	if err != nil {
+		return nil, err
+	}
+
+	if assertion.RevokedAt != nil {
+		return &payload.OpenBadgeAssertion{
+			Context:          payload.OpenBadgesContext,
+			Id:               r.badgeUrl("assertions", uid),
+			Revoked:          true,
+			RevocationReason: utils.Val(assertion.RevocationReason),
+		}, nil
+	}
+
+	return r.openBadgeAssertion(assertion), nil
+}
+
+// BakeBadge writes the assertion into the badge image, so the PNG alone carries the credential.
+// Signed badges carry the signature, hosted badges the assertion pointing at its hosted copy.
+func (r *badgeService) BakeBadge(uid string) ([]byte, error) {
+	assertion, err := r.findAssertion(uid)
+	if err != nil {
+		return nil, err
+	}
+	if assertion.RevokedAt != nil {
+		return nil, ErrBadgeRevoked
+	}
+
+	image, _, err := r.minioService.GetObject(context.Background(), *r.conf.MinioS3BucketName, *assertion.BadgeClass.Image)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get badge image: %w", err)
+	}
+
+	openBadgeAssertion := r.openBadgeAssertion(assertion)
+
+	var content string
+	if r.signing() {
+		if content, err = r.signAssertion(openBadgeAssertion); err != nil {
+			return nil, err
+		}
+	} else {
+		data, err := json.Marshal(openBadgeAssertion)
+		if err != nil {
+			return nil, err
+		}
+		content = string(data)
+	}
+
+	return bakePng(image, content)
+}
+
+func (r *badgeService) openBadgeAssertion(assertion *models.BadgeAssertion) *payload.OpenBadgeAssertion {
+	email := strings.ToLower(strings.TrimSpace(utils.Val(assertion.User.Email)))
+	identity := sha256.Sum256([]byte(email + *assertion.RecipientSalt))
+
+	verification := &payload.OpenBadgeVerification{Type: "HostedBadge"}
+	if r.signing() {
+		verification = &payload.OpenBadgeVerification{Type: "SignedBadge", Creator: r.badgeUrl("issuer", "key")}
+	}
+
+	return &payload.OpenBadgeAssertion{
+		Context: payload.OpenBadgesContext,
+		Type:    "Assertion",
+		Id:      r.badgeUrl("assertions", *assertion.Uid),
+		Recipient: &payload.OpenBadgeRecipient{
+			Type:     "email",
+			Hashed:   true,
+			Salt:     *assertion.RecipientSalt,
+			Identity: "sha256$" + hex.EncodeToString(identity[:]),
+		},
+		Badge:        r.badgeUrl("classes", strconv.FormatUint(*assertion.BadgeClassId, 10)),
+		Image:        r.badgeUrl("assertions", *assertion.Uid, "image"),
+		Verification: verification,
+		IssuedOn:     assertion.IssuedOn,
+	}
+}
+
+// signAssertion returns the assertion as a JWS signed with RS256, as signed badges require.
+func (r *badgeService) signAssertion(assertion *payload.OpenBadgeAssertion) (string, error) {
+	key, err := r.signingKey()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(assertion)
+	if err != nil {
+		return "", err
+	}
+	claims := jwt.MapClaims{}
+	if err := json.Unmarshal(data, &claims); err != nil {
+		return "", err
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(key)
+}
+
+func (r *badgeService) signing() bool {
+	return r.conf.BadgeSigningKeyPath != nil && *r.conf.BadgeSigningKeyPath != ""
+}
+
+func (r *badgeService) signingKey() (*rsa.PrivateKey, error) {
+	if !r.signing() {
+		return nil, fmt.Errorf("badges are not signed, no signing key is configured")
+	}
+
+	data, err := os.ReadFile(*r.conf.BadgeSigningKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read badge signing key: %w", err)
+	}
+
+	return jwt.ParseRSAPrivateKeyFromPEM(data)
+}
+
+// badgeUrl returns the public URL of a hosted badge document.
+func (r *badgeService) badgeUrl(elements ...string) string {
+	badgeUrl, _ := url.JoinPath(utils.Val(r.conf.ApiUrl), append([]string{"badges"}, elements...)...)
+	return badgeUrl
+}
+
+func (r *badgeService) findBadgeClass(badgeClassId uint64) (*models.BadgeClass, error) {
+	badgeClass, err := r.badgeRepo.FindBadgeClassById(badgeClassId)
+	if err != nil {
+		return nil, err
+	}
+	if badgeClass == nil {
+		return nil, fmt.Errorf("badge class %d not found", badgeClassId)
+	}
+
+	return badgeClass, nil
+}
+
+func (r *badgeService) findAssertion(uid string) (*models.BadgeAssertion, error) {
+	assertion, err := r.badgeRepo.FindAssertionByUid(uid)
+	if err != nil {
+		return nil, err
+	}
+	if assertion == nil {
+		return nil, fmt.Errorf("badge assertion %s not found", uid)
+	}
+
+	return assertion, nil
+}
+
+func (r *badgeService) badgeClassInfo(badgeClass *models.BadgeClass) *payload.BadgeClassInfo {
+	info := &payload.BadgeClassInfo{
+		BadgeClassId: badgeClass.Id,
+		CourseId:     badgeClass.CourseId,
+		ModuleId:     badgeClass.ModuleId,
+		Name:         badgeClass.Name,
+		Description:  badgeClass.Description,
+		BadgeUrl:     utils.Ptr(r.badgeUrl("classes", strconv.FormatUint(*badgeClass.Id, 10))),
+	}
+	if imageUrl, err := url.JoinPath(*r.conf.MinioS3Endpoint, *r.conf.MinioS3BucketName, *badgeClass.Image); err == nil {
+		info.ImageUrl = &imageUrl
+	}
+
+	return info
+}
+
+func (r *badgeService) assertionInfo(assertion *models.BadgeAssertion) *payload.BadgeAssertionInfo {
+	info := &payload.BadgeAssertionInfo{
+		AssertionId:      assertion.Uid,
+		AssertionUrl:     utils.Ptr(r.badgeUrl("assertions", *assertion.Uid)),
+		BakedImageUrl:    utils.Ptr(r.badgeUrl("assertions", *assertion.Uid, "image")),
+		IssuedOn:         assertion.IssuedOn,
+		Revoked:          assertion.RevokedAt != nil,
+		RevocationReason: assertion.RevocationReason,
+	}
+	if assertion.BadgeClass != nil {
+		info.BadgeClass = r.badgeClassInfo(assertion.BadgeClass)
+	}
+
+	return info
+}
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// bakePng inserts an iTXt chunk with the openbadges keyword before the end of the PNG.
+func bakePng(image []byte, content string) ([]byte, error) {
+	if !bytes.HasPrefix(image, pngSignature) {
+		return nil, fmt.Errorf("badge image is not a PNG")
+	}
+
+	// keyword, compression flag and method, empty language tag and translated keyword
+	data := append([]byte("openbadges\x00\x00\x00\x00\x00"), content...)
+	chunk := make([]byte, 0, len(data)+12)
+	chunk = binary.BigEndian.AppendUint32(chunk, uint32(len(data)))
+	chunk = append(chunk, "iTXt"...)
+	chunk = append(chunk, data...)
+	chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+
+	for offset := len(pngSignature); offset+8 <= len(image); {
+		length := int(binary.BigEndian.Uint32(image[offset:]))
+		if string(image[offset+4:offset+8]) == "IEND" {
+			baked := make([]byte, 0, len(image)+len(chunk))
+			baked = append(baked, image[:offset]...)
+			baked = append(baked, chunk...)
+			return append(baked, image[offset:]...), nil
+		}
+		offset += length + 12
+	}
+
+	return nil, fmt.Errorf("badge image has no end chunk")
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"backend/internals/config"
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/utils"
+	mockRepositories "backend/mocks/repositories"
+	mockUtilServices "backend/mocks/utils"
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type BadgeServiceTestSuite struct {
+	suite.Suite
+}
+
+func mockBadgeConfig() *config.Config {
+	return &config.Config{
+		ApiUrl:            utils.Ptr("https://learn.example.com/api"),
+		BadgeIssuerName:   utils.Ptr("IoT Learning Platform"),
+		MinioS3Endpoint:   utils.Ptr("https://minio.example.com"),
+		MinioS3BucketName: utils.Ptr("bucket"),
+	}
+}
+
+func mockBadgeAssertion() *models.BadgeAssertion {
+	return &models.BadgeAssertion{
+		Id:            utils.Ptr(uint64(1)),
+		Uid:           utils.Ptr("0b6c8d2e-8f57-4a43-9d8e-2f0f5c8f6a10"),
+		BadgeClassId:  utils.Ptr(uint64(4)),
+		BadgeClass:    &models.BadgeClass{Id: utils.Ptr(uint64(4)), CourseId: utils.Ptr(uint64(7)), Name: utils.Ptr("IoT 101"), Description: utils.Ptr("Completed IoT 101"), Image: utils.Ptr("badges/course7.png")},
+		UserId:        utils.Ptr(uint64(9)),
+		User:          &models.User{Id: utils.Ptr(uint64(9)), Email: utils.Ptr("Somchai@example.com")},
+		RecipientSalt: utils.Ptr("salt"),
+		IssuedOn:      utils.Ptr(time.Date(2024, 11, 2, 9, 0, 0, 0, utils.BangkokTime)),
+	}
+}
+
+func mockBadgeImage() []byte {
+	var buffer bytes.Buffer
+	_ = png.Encode(&buffer, image.NewRGBA(image.Rect(0, 0, 4, 4)))
+	return buffer.Bytes()
+}
+
+func (suite *BadgeServiceTestSuite) TestClaimBadgeWhenNotEarned() {
+	is := assert.New(suite.T())
+
+	mockBadgeRepo := new(mockRepositories.BadgeRepository)
+
+	mockBadgeRepo.EXPECT().FindBadgeClassById(uint64(4)).Return(&models.BadgeClass{Id: utils.Ptr(uint64(4)), CourseId: utils.Ptr(uint64(7)), ModuleId: utils.Ptr(uint64(5))}, nil)
+	mockBadgeRepo.EXPECT().FindAssertion(uint64(4), uint64(9)).Return(nil, nil)
+	mockBadgeRepo.EXPECT().FindCompletion(uint64(9), uint64(7), utils.Ptr(uint64(5))).Return(&payload.CourseCompletion{Required: 3, Passed: 1}, nil)
+
+	underTest := NewBadgeService(mockBadgeRepo, nil, nil, nil, mockBadgeConfig())
+
+	assertion, err := underTest.ClaimBadge(9, 4)
+
+	is.Nil(assertion)
+	is.Equal("badge class 4 is not earned yet, 1 of 3 evaluations passed", err.Error())
+	mockBadgeRepo.AssertNotCalled(suite.T(), "CreateAssertion", mock.Anything)
+}
+
+func (suite *BadgeServiceTestSuite) TestClaimBadgeWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockBadgeRepo := new(mockRepositories.BadgeRepository)
+
+	completedAt := time.Date(2024, 11, 2, 9, 0, 0, 0, utils.BangkokTime)
+	mockBadgeRepo.EXPECT().FindBadgeClassById(uint64(4)).Return(mockBadgeAssertion().BadgeClass, nil)
+	mockBadgeRepo.EXPECT().FindAssertion(uint64(4), uint64(9)).Return(nil, nil)
+	mockBadgeRepo.EXPECT().FindCompletion(uint64(9), uint64(7), (*uint64)(nil)).Return(&payload.CourseCompletion{Required: 3, Passed: 3, CompletedAt: &completedAt}, nil)
+	mockBadgeRepo.EXPECT().CreateAssertion(mock.MatchedBy(func(assertion *models.BadgeAssertion) bool {
+		return len(*assertion.Uid) == 36 && len(*assertion.RecipientSalt) == 32 && assertion.IssuedOn.Equal(completedAt)
+	})).Return(nil)
+
+	underTest := NewBadgeService(mockBadgeRepo, nil, nil, nil, mockBadgeConfig())
+
+	assertion, err := underTest.ClaimBadge(9, 4)
+
+	is.Nil(err)
+	is.Equal("https://learn.example.com/api/badges/assertions/"+*assertion.AssertionId, *assertion.AssertionUrl)
+	is.Equal("https://learn.example.com/api/badges/classes/4", *assertion.BadgeClass.BadgeUrl)
+	is.False(assertion.Revoked)
+}
+
+func (suite *BadgeServiceTestSuite) TestGetAssertionWhenRevoked() {
+	is := assert.New(suite.T())
+
+	mockBadgeRepo := new(mockRepositories.BadgeRepository)
+
+	revoked := mockBadgeAssertion()
+	revoked.RevokedAt = utils.TimeNowPtr()
+	revoked.RevocationReason = utils.Ptr("issued by mistake")
+	mockBadgeRepo.EXPECT().FindAssertionByUid(*revoked.Uid).Return(revoked, nil)
+
+	underTest := NewBadgeService(mockBadgeRepo, nil, nil, nil, mockBadgeConfig())
+
+	assertion, err := underTest.GetAssertion(*revoked.Uid)
+
+	is.Nil(err)
+	is.True(assertion.Revoked)
+	is.Equal("issued by mistake", assertion.RevocationReason)
+	is.Nil(assertion.Recipient)
+}
+
+func (suite *BadgeServiceTestSuite) TestBakeBadgeWhenHosted() {
+	is := assert.New(suite.T())
+
+	mockBadgeRepo := new(mockRepositories.BadgeRepository)
+	mockMinioService := new(mockUtilServices.MinioService)
+
+	mockBadgeRepo.EXPECT().FindAssertionByUid("0b6c8d2e-8f57-4a43-9d8e-2f0f5c8f6a10").Return(mockBadgeAssertion(), nil)
+	mockMinioService.EXPECT().GetObject(mock.Anything, "bucket", "badges/course7.png").Return(mockBadgeImage(), "image/png", nil)
+
+	underTest := NewBadgeService(mockBadgeRepo, nil, nil, mockMinioService, mockBadgeConfig())
+
+	baked, err := underTest.BakeBadge("0b6c8d2e-8f57-4a43-9d8e-2f0f5c8f6a10")
+
+	is.Nil(err)
+	_, err = png.Decode(bytes.NewReader(baked))
+	is.Nil(err)
+
+	// the chunk holds the hosted assertion with the recipient email hashed
+	index := bytes.Index(baked, []byte("iTXtopenbadges\x00\x00\x00\x00\x00"))
+	is.True(index > 0)
+	content := baked[index+len("iTXtopenbadges\x00\x00\x00\x00\x00"):]
+	content = content[:len(content)-4-12] // crc of the chunk, then the IEND chunk
+	var assertion payload.OpenBadgeAssertion
+	is.Nil(json.Unmarshal(content, &assertion))
+	is.Equal("HostedBadge", assertion.Verification.Type)
+	is.NotContains(strings.ToLower(string(baked)), "somchai@example.com")
+}
+
+func (suite *BadgeServiceTestSuite) TestBakeBadgeWhenSigned() {
+	is := assert.New(suite.T())
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	is.Nil(err)
+	keyPath := filepath.Join(suite.T().TempDir(), "badge.pem")
+	is.Nil(os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), 0600))
+
+	mockBadgeRepo := new(mockRepositories.BadgeRepository)
+	mockMinioService := new(mockUtilServices.MinioService)
+
+	mockBadgeRepo.EXPECT().FindAssertionByUid("0b6c8d2e-8f57-4a43-9d8e-2f0f5c8f6a10").Return(mockBadgeAssertion(), nil)
+	mockMinioService.EXPECT().GetObject(mock.Anything, "bucket", "badges/course7.png").Return(mockBadgeImage(), "image/png", nil)
+
+	conf := mockBadgeConfig()
+	conf.BadgeSigningKeyPath = &keyPath
+	underTest := NewBadgeService(mockBadgeRepo, nil, nil, mockMinioService, conf)
+
+	baked, err := underTest.BakeBadge("0b6c8d2e-8f57-4a43-9d8e-2f0f5c8f6a10")
+	is.Nil(err)
+
+	// the chunk holds a JWS verifying with the published public key
+	prefix := []byte("iTXtopenbadges\x00\x00\x00\x00\x00")
+	content := baked[bytes.Index(baked, prefix)+len(prefix):]
+	content = content[:len(content)-4-12] // crc of the chunk, then the IEND chunk
+	published, err := underTest.GetPublicKey()
+	is.Nil(err)
+	publicKey, err := jwt.ParseRSAPublicKeyFromPEM([]byte(published.PublicKeyPem))
+	is.Nil(err)
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(string(content), claims, func(token *jwt.Token) (any, error) { return publicKey, nil })
+	is.Nil(err)
+	verification, _ := json.Marshal(claims["verification"])
+	is.JSONEq(`{"type":"SignedBadge","creator":"https://learn.example.com/api/badges/issuer/key"}`, string(verification))
+}
+
+func (suite *BadgeServiceTestSuite) TestGetRevocationListWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockBadgeRepo := new(mockRepositories.BadgeRepository)
+
+	revoked := mockBadgeAssertion()
+	revoked.RevocationReason = utils.Ptr("issued by mistake")
+	mockBadgeRepo.EXPECT().FindRevokedAssertions().Return([]*models.BadgeAssertion{revoked}, nil)
+
+	underTest := NewBadgeService(mockBadgeRepo, nil, nil, nil, mockBadgeConfig())
+
+	revocationList, err := underTest.GetRevocationList()
+
+	is.Nil(err)
+	is.Equal("https://learn.example.com/api/badges/issuer", revocationList.Issuer)
+	is.Len(revocationList.RevokedAssertions, 1)
+	is.Equal("https://learn.example.com/api/badges/assertions/0b6c8d2e-8f57-4a43-9d8e-2f0f5c8f6a10", revocationList.RevokedAssertions[0].Id)
+}
+
+func TestBadgeService(t *testing.T) {
+	suite.Run(t, new(BadgeServiceTestSuite))
+}
@@ -0,0 +1,11 @@
+package services
+
+import "backend/internals/entities/payload"
+
+type CalendarService interface {
+	CreateEvent(body *payload.CreateCalendarEvent) (*payload.CalendarEventInfo, error)
+	CreateToken(userId uint64) (*payload.CalendarFeed, error)
+	RevokeToken(userId uint64) error
+	GetUserFeed(token string) ([]byte, error)
+	GetCourseFeed(courseId uint64) ([]byte, error)
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"backend/internals/config"
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/repositories"
+	"backend/internals/utils"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+var ErrCalendarTokenInvalid = errors.New("calendar token is invalid or revoked")
+
+// calendarFeedHistory keeps recently ended events in the feeds, calendar apps drop the events
+// missing from a refreshed feed.
+const calendarFeedHistory = 30 * 24 * time.Hour
+
+type calendarService struct {
+	calendarRepo        repositories.CalendarRepository
+	workshopSessionRepo repositories.WorkshopSessionRepository
+	courseRepo          repositories.CourseRepository
+	cohortRepo          repositories.CohortRepository
+	conf                *config.Config
+}
+
+func NewCalendarService(
+	calendarRepo repositories.CalendarRepository,
+	workshopSessionRepo repositories.WorkshopSessionRepository,
+	courseRepo repositories.CourseRepository,
+	cohortRepo repositories.CohortRepository,
+	conf *config.Config) CalendarService {
+	return &calendarService{
+		calendarRepo:        calendarRepo,
+		workshopSessionRepo: workshopSessionRepo,
+		courseRepo:          courseRepo,
+		cohortRepo:          cohortRepo,
+		conf:                conf,
+	}
+}
+
+func (r *calendarService) CreateEvent(body *payload.CreateCalendarEvent) (*payload.CalendarEventInfo, error) {
+	if body.EndsAt != nil && !body.EndsAt.After(*body.StartsAt) {
+		return nil, fmt.Errorf("event must end after it starts")
+	}
+
+	course, err := r.courseRepo.FindCourseByCourseId(body.CourseId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find course %d: %w", *body.CourseId, err)
+	}
+
+	if body.CohortId != nil {
+		cohort, err := r.cohortRepo.FindCohortById(*body.CohortId)
+		if err != nil {
+			return nil, err
+		}
+		if cohort == nil || *cohort.CourseId != *course.Id {
+			return nil, fmt.Errorf("cohort %d is not a cohort of course %d", *body.CohortId, *course.Id)
+		}
+	}
+
+	event := &models.CalendarEvent{
+		CourseId:    course.Id,
+		Course:      course,
+		CohortId:    body.CohortId,
+		Kind:        body.Kind,
+		Title:       body.Title,
+		Description: body.Description,
+		StartsAt:    body.StartsAt,
+		EndsAt:      body.EndsAt,
+		Location:    body.Location,
+	}
+	if err := r.calendarRepo.CreateEvent(event); err != nil {
+		return nil, err
+	}
+
+	return &payload.CalendarEventInfo{
+		EventId:     event.Id,
+		CourseId:    event.CourseId,
+		CohortId:    event.CohortId,
+		Kind:        event.Kind,
+		Title:       event.Title,
+		Description: event.Description,
+		StartsAt:    event.StartsAt,
+		EndsAt:      event.EndsAt,
+		Location:    event.Location,
+	}, nil
+}
+
+// CreateToken issues a new feed token for the user, revoking the previous one.
+func (r *calendarService) CreateToken(userId uint64) (*payload.CalendarFeed, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate calendar token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+
+	calendarToken := &models.CalendarToken{
+		UserId:    &userId,
+		TokenHash: utils.Ptr(calendarTokenHash(token)),
+		CreatedAt: utils.TimeNowPtr(),
+	}
+	if err := r.calendarRepo.SaveToken(calendarToken); err != nil {
+		return nil, err
+	}
+
+	feedUrl, _ := url.JoinPath(utils.Val(r.conf.ApiUrl), "calendar", "feeds", token+".ics")
+	return &payload.CalendarFeed{
+		FeedUrl:   &feedUrl,
+		CreatedAt: calendarToken.CreatedAt,
+	}, nil
+}
+
+func (r *calendarService) RevokeToken(userId uint64) error {
+	return r.calendarRepo.DeleteToken(userId)
+}
+
+// GetUserFeed returns the registered workshop sessions of the token owner, with the deadlines and
+// events of the courses and cohorts of the user.
+func (r *calendarService) GetUserFeed(token string) ([]byte, error) {
+	calendarToken, err := r.calendarRepo.FindTokenByHash(calendarTokenHash(token))
+	if err != nil {
+		return nil, err
+	}
+	if calendarToken == nil {
+		return nil, ErrCalendarTokenInvalid
+	}
+
+	now := utils.TimeNow()
+	from := now.Add(-calendarFeedHistory)
+
+	registrations, err := r.workshopSessionRepo.FindRegistrationsByUserId(*calendarToken.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := r.calendarRepo.FindUserEvents(*calendarToken.UserId, from)
+	if err != nil {
+		return nil, err
+	}
+
+	iCalEvents := make([]*utils.ICalEvent, 0, len(registrations)+len(events))
+	for _, registration := range registrations {
+		if !registration.Session.EndsAt.After(from) {
+			continue
+		}
+		iCalEvent := r.sessionICalEvent(registration.Session)
+		if *registration.Status == "waitlisted" {
+			iCalEvent.Status = "TENTATIVE"
+			iCalEvent.Description = "You are on the waitlist of this session.\n" + iCalEvent.Description
+		}
+		iCalEvents = append(iCalEvents, iCalEvent)
+	}
+	for _, event := range events {
+		iCalEvents = append(iCalEvents, r.calendarICalEvent(event))
+	}
+
+	return utils.ICalendar("IoT Learning Platform", iCalEvents, now), nil
+}
+
+// GetCourseFeed returns the workshop sessions, deadlines and events of the course shown to all of
+// its learners, cohort events are left out.
+func (r *calendarService) GetCourseFeed(courseId uint64) ([]byte, error) {
+	course, err := r.courseRepo.FindCourseByCourseId(&courseId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find course %d: %w", courseId, err)
+	}
+
+	now := utils.TimeNow()
+	from := now.Add(-calendarFeedHistory)
+
+	sessions, err := r.workshopSessionRepo.FindUpcomingSessions(&courseId, nil, from)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := r.calendarRepo.FindCourseEvents(courseId, from)
+	if err != nil {
+		return nil, err
+	}
+
+	iCalEvents := make([]*utils.ICalEvent, 0, len(sessions)+len(events))
+	for _, session := range sessions {
+		iCalEvents = append(iCalEvents, r.sessionICalEvent(session))
+	}
+	for _, event := range events {
+		iCalEvents = append(iCalEvents, r.calendarICalEvent(event))
+	}
+
+	return utils.ICalendar(*course.Name, iCalEvents, now), nil
+}
+
+func (r *calendarService) sessionICalEvent(session *models.WorkshopSession) *utils.ICalEvent {
+	iCalEvent := &utils.ICalEvent{
+		Uid:      r.calendarUid("session", *session.Id),
+		Summary:  *session.Title,
+		Location: utils.Val(session.Room),
+		Url:      utils.Val(session.OnlineUrl),
+		Status:   "CONFIRMED",
+		Start:    *session.StartsAt,
+		End:      session.EndsAt,
+		Updated:  utils.Val(session.UpdatedAt),
+	}
+	if session.Course != nil {
+		iCalEvent.Description = fmt.Sprintf("Workshop session of %s", *session.Course.Name)
+	}
+	if iCalEvent.Location == "" {
+		iCalEvent.Location = iCalEvent.Url
+	}
+
+	return iCalEvent
+}
+
+func (r *calendarService) calendarICalEvent(event *models.CalendarEvent) *utils.ICalEvent {
+	iCalEvent := &utils.ICalEvent{
+		Uid:         r.calendarUid(*event.Kind, *event.Id),
+		Summary:     *event.Title,
+		Description: utils.Val(event.Description),
+		Location:    utils.Val(event.Location),
+		Start:       *event.StartsAt,
+		End:         event.EndsAt,
+		Updated:     utils.Val(event.UpdatedAt),
+	}
+	if *event.Kind == "deadline" {
+		iCalEvent.Summary = "Due: " + iCalEvent.Summary
+	}
+
+	// tell which course, and which cohort, the event belongs to
+	origin := ""
+	if event.Course != nil {
+		origin = *event.Course.Name
+	}
+	if event.Cohort != nil {
+		origin = fmt.Sprintf("%s, %s", origin, *event.Cohort.Name)
+	}
+	if origin != "" && iCalEvent.Description != "" {
+		iCalEvent.Description = origin + "\n" + iCalEvent.Description
+	} else if origin != "" {
+		iCalEvent.Description = origin
+	}
+
+	return iCalEvent
+}
+
+// calendarUid is stable across refreshes of the feeds, so calendar apps update their copy of the
+// event instead of adding another.
+func (r *calendarService) calendarUid(kind string, id uint64) string {
+	host := "localhost"
+	if apiUrl, err := url.Parse(utils.Val(r.conf.ApiUrl)); err == nil && apiUrl.Hostname() != "" {
+		host = apiUrl.Hostname()
+	}
+
+	return fmt.Sprintf("%s-%d@%s", kind, id, host)
+}
+
+func calendarTokenHash(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
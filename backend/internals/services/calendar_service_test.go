@@ -0,0 +1,168 @@
+package services
+
+import (
+	"backend/internals/config"
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/utils"
+	mockRepositories "backend/mocks/repositories"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type CalendarServiceTestSuite struct {
+	suite.Suite
+}
+
+func mockCalendarConfig() *config.Config {
+	return &config.Config{
+		ApiUrl: utils.Ptr("https://learn.example.com/api"),
+	}
+}
+
+func (suite *CalendarServiceTestSuite) TestCreateEventWhenCohortOfAnotherCourse() {
+	is := assert.New(suite.T())
+
+	mockCalendarRepo := new(mockRepositories.CalendarRepository)
+	mockCourseRepo := new(mockRepositories.CourseRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+
+	other := mockCohort()
+	other.CourseId = utils.Ptr(uint64(8))
+	mockCourseRepo.EXPECT().FindCourseByCourseId(utils.Ptr(uint64(7))).Return(&models.Course{Id: utils.Ptr(uint64(7)), Name: utils.Ptr("IoT 101")}, nil)
+	mockCohortRepo.EXPECT().FindCohortById(uint64(3)).Return(other, nil)
+
+	underTest := NewCalendarService(mockCalendarRepo, nil, mockCourseRepo, mockCohortRepo, mockCalendarConfig())
+
+	event, err := underTest.CreateEvent(&payload.CreateCalendarEvent{
+		CourseId: utils.Ptr(uint64(7)),
+		CohortId: utils.Ptr(uint64(3)),
+		Kind:     utils.Ptr("deadline"),
+		Title:    utils.Ptr("Lab report"),
+		StartsAt: utils.TimeNowPtr(),
+	})
+
+	is.Nil(event)
+	is.Equal("cohort 3 is not a cohort of course 7", err.Error())
+	mockCalendarRepo.AssertNotCalled(suite.T(), "CreateEvent", mock.Anything)
+}
+
+func (suite *CalendarServiceTestSuite) TestCreateTokenWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockCalendarRepo := new(mockRepositories.CalendarRepository)
+
+	var saved *models.CalendarToken
+	mockCalendarRepo.EXPECT().SaveToken(mock.Anything).Run(func(token *models.CalendarToken) {
+		saved = token
+	}).Return(nil)
+
+	underTest := NewCalendarService(mockCalendarRepo, nil, nil, nil, mockCalendarConfig())
+
+	feed, err := underTest.CreateToken(9)
+
+	is.Nil(err)
+	is.True(strings.HasPrefix(*feed.FeedUrl, "https://learn.example.com/api/calendar/feeds/"))
+	is.True(strings.HasSuffix(*feed.FeedUrl, ".ics"))
+
+	// only the hash of the token in the URL is kept
+	token := strings.TrimSuffix(strings.TrimPrefix(*feed.FeedUrl, "https://learn.example.com/api/calendar/feeds/"), ".ics")
+	is.Equal(uint64(9), *saved.UserId)
+	is.Equal(calendarTokenHash(token), *saved.TokenHash)
+	is.NotContains(*saved.TokenHash, token)
+}
+
+func (suite *CalendarServiceTestSuite) TestGetUserFeedWhenTokenRevoked() {
+	is := assert.New(suite.T())
+
+	mockCalendarRepo := new(mockRepositories.CalendarRepository)
+
+	mockCalendarRepo.EXPECT().FindTokenByHash(calendarTokenHash("revoked")).Return(nil, nil)
+
+	underTest := NewCalendarService(mockCalendarRepo, nil, nil, nil, mockCalendarConfig())
+
+	feed, err := underTest.GetUserFeed("revoked")
+
+	is.Nil(feed)
+	is.ErrorIs(err, ErrCalendarTokenInvalid)
+}
+
+func (suite *CalendarServiceTestSuite) TestGetUserFeedWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockCalendarRepo := new(mockRepositories.CalendarRepository)
+	mockWorkshopSessionRepo := new(mockRepositories.WorkshopSessionRepository)
+
+	course := &models.Course{Id: utils.Ptr(uint64(7)), Name: utils.Ptr("IoT 101")}
+	startsAt := utils.TimeNow().Add(48 * time.Hour)
+	endsAt := startsAt.Add(2 * time.Hour)
+	endedAt := utils.TimeNow().Add(-60 * 24 * time.Hour)
+
+	mockCalendarRepo.EXPECT().FindTokenByHash(calendarTokenHash("token")).Return(&models.CalendarToken{UserId: utils.Ptr(uint64(9))}, nil)
+	mockWorkshopSessionRepo.EXPECT().FindRegistrationsByUserId(uint64(9)).Return([]*models.SessionRegistration{
+		{Status: utils.Ptr("registered"), Session: &models.WorkshopSession{Id: utils.Ptr(uint64(1)), Course: course, Title: utils.Ptr("Old session"), StartsAt: &endedAt, EndsAt: &endedAt}},
+		{Status: utils.Ptr("waitlisted"), Session: &models.WorkshopSession{Id: utils.Ptr(uint64(2)), Course: course, Title: utils.Ptr("Soldering, part 1"), StartsAt: &startsAt, EndsAt: &endsAt, Room: utils.Ptr("Lab 3")}},
+	}, nil)
+	mockCalendarRepo.EXPECT().FindUserEvents(uint64(9), mock.Anything).Return([]*models.CalendarEvent{
+		{Id: utils.Ptr(uint64(5)), Kind: utils.Ptr("deadline"), Course: course, Cohort: mockCohort(), Title: utils.Ptr("Lab report"), Description: utils.Ptr(strings.Repeat("Upload the report of the soldering lab. ", 4)), StartsAt: &startsAt},
+	}, nil)
+
+	underTest := NewCalendarService(mockCalendarRepo, mockWorkshopSessionRepo, nil, nil, mockCalendarConfig())
+
+	feed, err := underTest.GetUserFeed("token")
+
+	is.Nil(err)
+	lines := strings.Split(strings.TrimSuffix(string(feed), "\r\n"), "\r\n")
+	is.Equal("BEGIN:VCALENDAR", lines[0])
+	is.Equal("END:VCALENDAR", lines[len(lines)-1])
+	for _, line := range lines {
+		is.LessOrEqual(len(line), 75)
+	}
+
+	// sessions which ended long ago are left out
+	unfolded := strings.ReplaceAll(string(feed), "\r\n ", "")
+	is.Equal(2, strings.Count(unfolded, "BEGIN:VEVENT"))
+	is.NotContains(unfolded, "Old session")
+	is.Contains(unfolded, "UID:session-2@learn.example.com\r\n")
+	is.Contains(unfolded, "SUMMARY:Soldering\\, part 1\r\n")
+	is.Contains(unfolded, "STATUS:TENTATIVE\r\n")
+	is.Contains(unfolded, "DTSTART:"+startsAt.UTC().Format("20060102T150405Z")+"\r\n")
+	is.Contains(unfolded, "UID:deadline-5@learn.example.com\r\n")
+	is.Contains(unfolded, "SUMMARY:Due: Lab report\r\n")
+	is.Contains(unfolded, "DESCRIPTION:IoT 101\\, Section 1\\nUpload the report")
+}
+
+func (suite *CalendarServiceTestSuite) TestGetCourseFeedWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockCalendarRepo := new(mockRepositories.CalendarRepository)
+	mockWorkshopSessionRepo := new(mockRepositories.WorkshopSessionRepository)
+	mockCourseRepo := new(mockRepositories.CourseRepository)
+
+	startsAt := utils.TimeNow().Add(48 * time.Hour)
+	endsAt := startsAt.Add(2 * time.Hour)
+
+	mockCourseRepo.EXPECT().FindCourseByCourseId(utils.Ptr(uint64(7))).Return(&models.Course{Id: utils.Ptr(uint64(7)), Name: utils.Ptr("IoT 101")}, nil)
+	mockWorkshopSessionRepo.EXPECT().FindUpcomingSessions(utils.Ptr(uint64(7)), (*uint64)(nil), mock.Anything).Return([]*models.WorkshopSession{
+		{Id: utils.Ptr(uint64(2)), Title: utils.Ptr("Soldering"), StartsAt: &startsAt, EndsAt: &endsAt, OnlineUrl: utils.Ptr("https://meet.example.com/soldering")},
+	}, nil)
+	mockCalendarRepo.EXPECT().FindCourseEvents(uint64(7), mock.Anything).Return(nil, nil)
+
+	underTest := NewCalendarService(mockCalendarRepo, mockWorkshopSessionRepo, mockCourseRepo, nil, mockCalendarConfig())
+
+	feed, err := underTest.GetCourseFeed(7)
+
+	is.Nil(err)
+	is.Contains(string(feed), "X-WR-CALNAME:IoT 101\r\n")
+	is.Contains(string(feed), "LOCATION:https://meet.example.com/soldering\r\n")
+	is.Contains(string(feed), "STATUS:CONFIRMED\r\n")
+}
+
+func TestCalendarService(t *testing.T) {
+	suite.Run(t, new(CalendarServiceTestSuite))
+}
@@ -0,0 +1,10 @@
+package services
+
+import "backend/internals/entities/payload"
+
+type CertificateService interface {
+	IssueCertificate(userId uint64, courseId uint64) (*payload.CertificateInfo, error)
+	IssueOnCompletion(userId uint64, courseId uint64) error
+	GetMyCertificates(userId uint64) ([]*payload.CertificateInfo, error)
+	VerifyCertificate(query *payload.CertificateVerifyQuery) (*payload.CertificateVerification, error)
+}
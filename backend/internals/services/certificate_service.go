@@ -0,0 +1,322 @@
+package services
+
+import (
+	"backend/internals/config"
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/repositories"
+	"backend/internals/utils"
+	utilServices "backend/internals/utils/services"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/sirupsen/logrus"
+	"github.com/skip2/go-qrcode"
+)
+
+type certificateService struct {
+	certificateRepo repositories.CertificateRepository
+	userRepo        repositories.UserRepository
+	courseRepo      repositories.CourseRepository
+	minioService    utilServices.MinioService
+	conf            *config.Config
+}
+
+func NewCertificateService(
+	certificateRepo repositories.CertificateRepository,
+	userRepo repositories.UserRepository,
+	courseRepo repositories.CourseRepository,
+	minioService utilServices.MinioService,
+	conf *config.Config) CertificateService {
+	return &certificateService{
+		certificateRepo: certificateRepo,
+		userRepo:        userRepo,
+		courseRepo:      courseRepo,
+		minioService:    minioService,
+		conf:            conf,
+	}
+}
+
+// IssueCertificate issues the certificate of a course the user fully passed. A course is
+// certified once, asking again returns the certificate issued before.
+func (r *certificateService) IssueCertificate(userId uint64, courseId uint64) (*payload.CertificateInfo, error) {
+	certificate, err := r.certificateRepo.FindCertificate(userId, courseId)
+	if err != nil {
+		return nil, err
+	}
+	if certificate != nil {
+		return r.certificateInfo(certificate), nil
+	}
+
+	completion, err := r.certificateRepo.FindCourseCompletion(userId, courseId)
+	if err != nil {
+		return nil, err
+	}
+	if completion.Required == 0 {
+		return nil, fmt.Errorf("course %d has no evaluations to pass", courseId)
+	}
+	if completion.Passed < completion.Required {
+		return nil, fmt.Errorf("course %d is not completed yet, %d of %d evaluations passed", courseId, completion.Passed, completion.Required)
+	}
+
+	certificate, err = r.issue(userId, courseId, completion)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.certificateInfo(certificate), nil
+}
+
+// IssueOnCompletion issues the certificate of the course once the user passed all of it. It is
+// called whenever an evaluation of the course is passed, so nothing happens before that.
+func (r *certificateService) IssueOnCompletion(userId uint64, courseId uint64) error {
+	certificate, err := r.certificateRepo.FindCertificate(userId, courseId)
+	if err != nil {
+		return err
+	}
+	if certificate != nil {
+		return nil
+	}
+
+	completion, err := r.certificateRepo.FindCourseCompletion(userId, courseId)
+	if err != nil {
+		return err
+	}
+	if completion.Required == 0 || completion.Passed < completion.Required {
+		return nil
+	}
+
+	_, err = r.issue(userId, courseId, completion)
+	return err
+}
+
+// issue creates the certificate of the completed course, then uploads its PDF. A concurrent issue
+// of the same course returns the certificate it created instead of uploading a second PDF.
+func (r *certificateService) issue(userId uint64, courseId uint64, completion *payload.CourseCompletion) (*models.Certificate, error) {
+	user, err := r.userRepo.FindUserByID(utils.Ptr(strconv.FormatUint(userId, 10)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user %d: %w", userId, err)
+	}
+	course, err := r.courseRepo.FindCourseByCourseId(&courseId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find course %d: %w", courseId, err)
+	}
+
+	code, err := newCertificateCode()
+	if err != nil {
+		return nil, err
+	}
+
+	certificate := &models.Certificate{
+		Code:          &code,
+		UserId:        &userId,
+		CourseId:      &courseId,
+		RecipientName: utils.Ptr(strings.TrimSpace(utils.Val(user.Firstname) + " " + utils.Val(user.Lastname))),
+		CourseName:    course.Name,
+		CompletedAt:   completion.CompletedAt,
+		Object:        utils.Ptr(fmt.Sprintf("certificates/%s.pdf", code)),
+	}
+
+	pdf, err := r.renderCertificate(certificate)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := r.certificateRepo.CreateCertificate(certificate)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		existing, err := r.certificateRepo.FindCertificate(userId, courseId)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			return nil, fmt.Errorf("failed to create certificate of course %d", courseId)
+		}
+		return existing, nil
+	}
+
+	if err := r.minioService.PutObjectBytes(context.Background(), *r.conf.MinioS3BucketName, *certificate.Object, pdf, "application/pdf"); err != nil {
+		// a certificate without its PDF is withdrawn, so issuing it again can succeed
+		if err := r.certificateRepo.DeleteCertificate(*certificate.Id); err != nil {
+			logrus.Errorf("[CERTIFICATE] Unable to withdraw certificate %s: %v", code, err)
+		}
+		return nil, fmt.Errorf("failed to upload certificate: %w", err)
+	}
+
+	return certificate, nil
+}
+
+func (r *certificateService) GetMyCertificates(userId uint64) ([]*payload.CertificateInfo, error) {
+	certificates, err := r.certificateRepo.FindCertificatesByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]*payload.CertificateInfo, 0, len(certificates))
+	for _, certificate := range certificates {
+		infos = append(infos, r.certificateInfo(certificate))
+	}
+
+	return infos, nil
+}
+
+// VerifyCertificate looks the certificate up by its ID or by the signed token of its QR code.
+// An unknown certificate or a forged token is reported as not valid rather than as an error.
+func (r *certificateService) VerifyCertificate(query *payload.CertificateVerifyQuery) (*payload.CertificateVerification, error) {
+	code := strings.ToUpper(strings.TrimSpace(utils.Val(query.Id)))
+	if query.Token != nil {
+		var ok bool
+		if code, ok = r.verifyCertificateToken(*query.Token); !ok {
+			return &payload.CertificateVerification{Valid: false}, nil
+		}
+	}
+
+	certificate, err := r.certificateRepo.FindCertificateByCode(code)
+	if err != nil {
+		return nil, err
+	}
+	if certificate == nil {
+		return &payload.CertificateVerification{Valid: false}, nil
+	}
+
+	return &payload.CertificateVerification{
+		Valid:         true,
+		CertificateId: certificate.Code,
+		RecipientName: certificate.RecipientName,
+		CourseName:    certificate.CourseName,
+		CompletedAt:   certificate.CompletedAt,
+		IssuedAt:      certificate.CreatedAt,
+	}, nil
+}
+
+// signCertificateToken returns the token of the QR code printed on the certificate, formatted as
+// <certificateId>.<signature>.
+func (r *certificateService) signCertificateToken(code string) string {
+	mac := hmac.New(sha256.New, []byte(*r.conf.SecretKey))
+	mac.Write([]byte("certificate." + code))
+	signature := base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+
+	return code + "." + signature
+}
+
+func (r *certificateService) verifyCertificateToken(token string) (string, bool) {
+	token = strings.TrimSpace(token)
+	code, _, found := strings.Cut(token, ".")
+	if !found {
+		return "", false
+	}
+
+	return code, hmac.Equal([]byte(r.signCertificateToken(code)), []byte(token))
+}
+
+// renderCertificate renders the certificate as a landscape A4 PDF with a QR code pointing at
+// its verification. The core PDF fonts only cover Latin-1, Thai names need a UTF-8 font.
+func (r *certificateService) renderCertificate(certificate *models.Certificate) ([]byte, error) {
+	verifyContent := r.signCertificateToken(*certificate.Code)
+	if r.conf.FrontendScheme != nil && r.conf.FrontendUrl != nil {
+		verifyContent = fmt.Sprintf("%s://%s/certificates/verify?token=%s", *r.conf.FrontendScheme, *r.conf.FrontendUrl, url.QueryEscape(verifyContent))
+	}
+	qr, err := qrcode.Encode(verifyContent, qrcode.Medium, 256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render certificate qr code: %w", err)
+	}
+
+	pdf := fpdf.New("L", "mm", "A4", "")
+	translate := pdf.UnicodeTranslatorFromDescriptor("")
+	setFont := func(size float64, bold bool) {
+		if bold {
+			pdf.SetFont("Helvetica", "B", size)
+		} else {
+			pdf.SetFont("Helvetica", "", size)
+		}
+	}
+	if r.conf.CertificateFontPath != nil && *r.conf.CertificateFontPath != "" {
+		font, err := os.ReadFile(*r.conf.CertificateFontPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read certificate font: %w", err)
+		}
+		pdf.AddUTF8FontFromBytes("certificate", "", font)
+		translate = func(text string) string { return text }
+		setFont = func(size float64, _ bool) { pdf.SetFont("certificate", "", size) }
+	}
+
+	pdf.AddPage()
+	pdf.SetLineWidth(1.5)
+	pdf.Rect(10, 10, 277, 190, "D")
+
+	pdf.SetY(35)
+	setFont(34, true)
+	pdf.CellFormat(0, 18, translate("Certificate of Completion"), "", 1, "C", false, 0, "")
+	setFont(14, false)
+	pdf.CellFormat(0, 16, translate("This certifies that"), "", 1, "C", false, 0, "")
+	setFont(28, true)
+	pdf.CellFormat(0, 16, translate(*certificate.RecipientName), "", 1, "C", false, 0, "")
+	setFont(14, false)
+	pdf.CellFormat(0, 16, translate("has successfully completed the course"), "", 1, "C", false, 0, "")
+	setFont(22, true)
+	pdf.CellFormat(0, 14, translate(*certificate.CourseName), "", 1, "C", false, 0, "")
+	setFont(14, false)
+	completedAt := certificate.CompletedAt.In(utils.BangkokTime).Format("2 January 2006")
+	pdf.CellFormat(0, 14, translate("on "+completedAt), "", 1, "C", false, 0, "")
+
+	pdf.RegisterImageOptionsReader("verify", fpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(qr))
+	pdf.ImageOptions("verify", 240, 155, 35, 35, false, fpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+	pdf.SetXY(20, 178)
+	setFont(10, false)
+	pdf.CellFormat(0, 6, translate("Certificate ID: "+*certificate.Code), "", 1, "L", false, 0, "")
+	pdf.SetX(20)
+	pdf.CellFormat(0, 6, translate("Scan the QR code to verify this certificate"), "", 1, "L", false, 0, "")
+
+	var buffer bytes.Buffer
+	if err := pdf.Output(&buffer); err != nil {
+		return nil, fmt.Errorf("failed to render certificate: %w", err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func (r *certificateService) certificateInfo(certificate *models.Certificate) *payload.CertificateInfo {
+	info := &payload.CertificateInfo{
+		CertificateId: certificate.Code,
+		CourseId:      certificate.CourseId,
+		CourseName:    certificate.CourseName,
+		RecipientName: certificate.RecipientName,
+		CompletedAt:   certificate.CompletedAt,
+		IssuedAt:      certificate.CreatedAt,
+	}
+	if pdfUrl, err := url.JoinPath(*r.conf.MinioS3Endpoint, *r.conf.MinioS3BucketName, *certificate.Object); err == nil {
+		info.PdfUrl = &pdfUrl
+	}
+
+	return info
+}
+
+// newCertificateCode returns a random certificate ID such as 7KQ2-M4XD-PA6T-3HZC, hard to guess
+// and easy to type from a printed certificate.
+func newCertificateCode() (string, error) {
+	random := make([]byte, 10)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("failed to generate certificate id: %w", err)
+	}
+
+	code := base32.StdEncoding.EncodeToString(random)
+	groups := make([]string, 0, 4)
+	for i := 0; i < len(code); i += 4 {
+		groups = append(groups, code[i:i+4])
+	}
+
+	return strings.Join(groups, "-"), nil
+}
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"backend/internals/config"
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/utils"
+	mockRepositories "backend/mocks/repositories"
+	mockUtilServices "backend/mocks/utils"
+	"bytes"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type CertificateServiceTestSuite struct {
+	suite.Suite
+}
+
+func mockCertificateConfig() *config.Config {
+	return &config.Config{
+		SecretKey:         utils.Ptr("secret"),
+		FrontendScheme:    utils.Ptr("https"),
+		FrontendUrl:       utils.Ptr("learn.example.com"),
+		MinioS3Endpoint:   utils.Ptr("https://minio.example.com"),
+		MinioS3BucketName: utils.Ptr("bucket"),
+	}
+}
+
+func mockCertificate() *models.Certificate {
+	return &models.Certificate{
+		Id:            utils.Ptr(uint64(1)),
+		Code:          utils.Ptr("7KQ2-M4XD-PA6T-3HZC"),
+		UserId:        utils.Ptr(uint64(9)),
+		CourseId:      utils.Ptr(uint64(7)),
+		RecipientName: utils.Ptr("Somchai Jaidee"),
+		CourseName:    utils.Ptr("IoT 101"),
+		CompletedAt:   utils.Ptr(time.Date(2024, 11, 2, 9, 0, 0, 0, utils.BangkokTime)),
+		Object:        utils.Ptr("certificates/7KQ2-M4XD-PA6T-3HZC.pdf"),
+	}
+}
+
+func (suite *CertificateServiceTestSuite) TestIssueCertificateWhenNotCompleted() {
+	is := assert.New(suite.T())
+
+	mockCertificateRepo := new(mockRepositories.CertificateRepository)
+	mockMinioService := new(mockUtilServices.MinioService)
+
+	mockCertificateRepo.EXPECT().FindCertificate(uint64(9), uint64(7)).Return(nil, nil)
+	mockCertificateRepo.EXPECT().FindCourseCompletion(uint64(9), uint64(7)).Return(&payload.CourseCompletion{Required: 12, Passed: 11}, nil)
+
+	underTest := NewCertificateService(mockCertificateRepo, nil, nil, mockMinioService, mockCertificateConfig())
+
+	certificate, err := underTest.IssueCertificate(9, 7)
+
+	is.Nil(certificate)
+	is.Equal("course 7 is not completed yet, 11 of 12 evaluations passed", err.Error())
+	mockMinioService.AssertNotCalled(suite.T(), "PutObjectBytes", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *CertificateServiceTestSuite) TestIssueCertificateWhenAlreadyIssued() {
+	is := assert.New(suite.T())
+
+	mockCertificateRepo := new(mockRepositories.CertificateRepository)
+
+	mockCertificateRepo.EXPECT().FindCertificate(uint64(9), uint64(7)).Return(mockCertificate(), nil)
+
+	underTest := NewCertificateService(mockCertificateRepo, nil, nil, nil, mockCertificateConfig())
+
+	certificate, err := underTest.IssueCertificate(9, 7)
+
+	is.Nil(err)
+	is.Equal("7KQ2-M4XD-PA6T-3HZC", *certificate.CertificateId)
+	is.Equal("https://minio.example.com/bucket/certificates/7KQ2-M4XD-PA6T-3HZC.pdf", *certificate.PdfUrl)
+	mockCertificateRepo.AssertNotCalled(suite.T(), "FindCourseCompletion", mock.Anything, mock.Anything)
+}
+
+func (suite *CertificateServiceTestSuite) TestIssueCertificateWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockCertificateRepo := new(mockRepositories.CertificateRepository)
+	mockUserRepo := new(mockRepositories.UserRepository)
+	mockCourseRepo := new(mockRepositories.CourseRepository)
+	mockMinioService := new(mockUtilServices.MinioService)
+
+	completedAt := time.Date(2024, 11, 2, 9, 0, 0, 0, utils.BangkokTime)
+	mockCertificateRepo.EXPECT().FindCertificate(uint64(9), uint64(7)).Return(nil, nil)
+	mockCertificateRepo.EXPECT().FindCourseCompletion(uint64(9), uint64(7)).Return(&payload.CourseCompletion{Required: 12, Passed: 12, CompletedAt: &completedAt}, nil)
+	mockUserRepo.EXPECT().FindUserByID(utils.Ptr("9")).Return(&models.User{
+		Id:        utils.Ptr(uint64(9)),
+		Firstname: utils.Ptr("Somchai"),
+		Lastname:  utils.Ptr("Jaidee"),
+	}, nil)
+	mockCourseRepo.EXPECT().FindCourseByCourseId(utils.Ptr(uint64(7))).Return(&models.Course{Id: utils.Ptr(uint64(7)), Name: utils.Ptr("IoT 101")}, nil)
+	mockMinioService.EXPECT().PutObjectBytes(mock.Anything, "bucket", mock.MatchedBy(func(objectName string) bool {
+		return len(objectName) == len("certificates/7KQ2-M4XD-PA6T-3HZC.pdf")
+	}), mock.MatchedBy(func(data []byte) bool {
+		return bytes.HasPrefix(data, []byte("%PDF"))
+	}), "application/pdf").Return(nil)
+	mockCertificateRepo.EXPECT().CreateCertificate(mock.MatchedBy(func(certificate *models.Certificate) bool {
+		return *certificate.RecipientName == "Somchai Jaidee" && *certificate.CourseName == "IoT 101" && certificate.CompletedAt.Equal(completedAt)
+	})).Run(func(certificate *models.Certificate) {
+		certificate.Id = utils.Ptr(uint64(1))
+	}).Return(true, nil)
+
+	underTest := NewCertificateService(mockCertificateRepo, mockUserRepo, mockCourseRepo, mockMinioService, mockCertificateConfig())
+
+	certificate, err := underTest.IssueCertificate(9, 7)
+
+	is.Nil(err)
+	is.Regexp(`^[A-Z2-7]{4}-[A-Z2-7]{4}-[A-Z2-7]{4}-[A-Z2-7]{4}$`, *certificate.CertificateId)
+	is.Equal("Somchai Jaidee", *certificate.RecipientName)
+}
+
+// mockCompletedCourse sets up a user who passed every evaluation of course 7.
+func mockCompletedCourse(mockCertificateRepo *mockRepositories.CertificateRepository, mockUserRepo *mockRepositories.UserRepository, mockCourseRepo *mockRepositories.CourseRepository) {
+	mockCertificateRepo.EXPECT().FindCertificate(uint64(9), uint64(7)).Return(nil, nil).Once()
+	mockCertificateRepo.EXPECT().FindCourseCompletion(uint64(9), uint64(7)).Return(&payload.CourseCompletion{Required: 12, Passed: 12, CompletedAt: mockCertificate().CompletedAt}, nil)
+	mockUserRepo.EXPECT().FindUserByID(utils.Ptr("9")).Return(&models.User{Firstname: utils.Ptr("Somchai")}, nil)
+	mockCourseRepo.EXPECT().FindCourseByCourseId(utils.Ptr(uint64(7))).Return(&models.Course{Name: utils.Ptr("IoT 101")}, nil)
+}
+
+func (suite *CertificateServiceTestSuite) TestIssueCertificateWhenIssuedConcurrently() {
+	is := assert.New(suite.T())
+
+	mockCertificateRepo := new(mockRepositories.CertificateRepository)
+	mockUserRepo := new(mockRepositories.UserRepository)
+	mockCourseRepo := new(mockRepositories.CourseRepository)
+	mockMinioService := new(mockUtilServices.MinioService)
+
+	mockCompletedCourse(mockCertificateRepo, mockUserRepo, mockCourseRepo)
+	mockCertificateRepo.EXPECT().CreateCertificate(mock.Anything).Return(false, nil)
+	mockCertificateRepo.EXPECT().FindCertificate(uint64(9), uint64(7)).Return(mockCertificate(), nil).Once()
+
+	underTest := NewCertificateService(mockCertificateRepo, mockUserRepo, mockCourseRepo, mockMinioService, mockCertificateConfig())
+
+	certificate, err := underTest.IssueCertificate(9, 7)
+
+	// the certificate created by the other request is returned, without uploading a second PDF
+	is.Nil(err)
+	is.Equal("7KQ2-M4XD-PA6T-3HZC", *certificate.CertificateId)
+	mockMinioService.AssertNotCalled(suite.T(), "PutObjectBytes", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *CertificateServiceTestSuite) TestIssueCertificateWhenUploadFailed() {
+	is := assert.New(suite.T())
+
+	mockCertificateRepo := new(mockRepositories.CertificateRepository)
+	mockUserRepo := new(mockRepositories.UserRepository)
+	mockCourseRepo := new(mockRepositories.CourseRepository)
+	mockMinioService := new(mockUtilServices.MinioService)
+
+	mockCompletedCourse(mockCertificateRepo, mockUserRepo, mockCourseRepo)
+	mockCertificateRepo.EXPECT().CreateCertificate(mock.Anything).Run(func(certificate *models.Certificate) {
+		certificate.Id = utils.Ptr(uint64(3))
+	}).Return(true, nil)
+	mockMinioService.EXPECT().PutObjectBytes(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("bucket is unreachable"))
+	mockCertificateRepo.EXPECT().DeleteCertificate(uint64(3)).Return(nil)
+
+	underTest := NewCertificateService(mockCertificateRepo, mockUserRepo, mockCourseRepo, mockMinioService, mockCertificateConfig())
+
+	certificate, err := underTest.IssueCertificate(9, 7)
+
+	is.Nil(certificate)
+	is.EqualError(err, "failed to upload certificate: bucket is unreachable")
+	mockCertificateRepo.AssertExpectations(suite.T())
+}
+
+func (suite *CertificateServiceTestSuite) TestIssueOnCompletionWhenNotCompleted() {
+	is := assert.New(suite.T())
+
+	mockCertificateRepo := new(mockRepositories.CertificateRepository)
+
+	mockCertificateRepo.EXPECT().FindCertificate(uint64(9), uint64(7)).Return(nil, nil)
+	mockCertificateRepo.EXPECT().FindCourseCompletion(uint64(9), uint64(7)).Return(&payload.CourseCompletion{Required: 12, Passed: 11}, nil)
+
+	underTest := NewCertificateService(mockCertificateRepo, nil, nil, nil, mockCertificateConfig())
+
+	is.Nil(underTest.IssueOnCompletion(9, 7))
+	mockCertificateRepo.AssertNotCalled(suite.T(), "CreateCertificate", mock.Anything)
+}
+
+func (suite *CertificateServiceTestSuite) TestIssueOnCompletionWhenCompleted() {
+	is := assert.New(suite.T())
+
+	mockCertificateRepo := new(mockRepositories.CertificateRepository)
+	mockUserRepo := new(mockRepositories.UserRepository)
+	mockCourseRepo := new(mockRepositories.CourseRepository)
+	mockMinioService := new(mockUtilServices.MinioService)
+
+	mockCompletedCourse(mockCertificateRepo, mockUserRepo, mockCourseRepo)
+	mockCertificateRepo.EXPECT().CreateCertificate(mock.Anything).Run(func(certificate *models.Certificate) {
+		certificate.Id = utils.Ptr(uint64(3))
+	}).Return(true, nil)
+	mockMinioService.EXPECT().PutObjectBytes(mock.Anything, "bucket", mock.Anything, mock.Anything, "application/pdf").Return(nil)
+
+	underTest := NewCertificateService(mockCertificateRepo, mockUserRepo, mockCourseRepo, mockMinioService, mockCertificateConfig())
+
+	is.Nil(underTest.IssueOnCompletion(9, 7))
+	mockMinioService.AssertExpectations(suite.T())
+}
+
+func (suite *CertificateServiceTestSuite) TestVerifyCertificateWhenSignedToken() {
+	is := assert.New(suite.T())
+
+	mockCertificateRepo := new(mockRepositories.CertificateRepository)
+
+	mockCertificateRepo.EXPECT().FindCertificateByCode("7KQ2-M4XD-PA6T-3HZC").Return(mockCertificate(), nil)
+
+	underTest := NewCertificateService(mockCertificateRepo, nil, nil, nil, mockCertificateConfig()).(*certificateService)
+	token := underTest.signCertificateToken("7KQ2-M4XD-PA6T-3HZC")
+
+	verification, err := underTest.VerifyCertificate(&payload.CertificateVerifyQuery{Token: &token})
+
+	is.Nil(err)
+	is.True(verification.Valid)
+	is.Equal("Somchai Jaidee", *verification.RecipientName)
+}
+
+func (suite *CertificateServiceTestSuite) TestVerifyCertificateWhenForgedToken() {
+	is := assert.New(suite.T())
+
+	mockCertificateRepo := new(mockRepositories.CertificateRepository)
+
+	forger := NewCertificateService(mockCertificateRepo, nil, nil, nil, &config.Config{SecretKey: utils.Ptr("guess")}).(*certificateService)
+	token := forger.signCertificateToken("7KQ2-M4XD-PA6T-3HZC")
+
+	underTest := NewCertificateService(mockCertificateRepo, nil, nil, nil, mockCertificateConfig())
+
+	verification, err := underTest.VerifyCertificate(&payload.CertificateVerifyQuery{Token: &token})
+
+	is.Nil(err)
+	is.False(verification.Valid)
+	mockCertificateRepo.AssertNotCalled(suite.T(), "FindCertificateByCode", mock.Anything)
+}
+
+func (suite *CertificateServiceTestSuite) TestVerifyCertificateWhenUnknownId() {
+	is := assert.New(suite.T())
+
+	mockCertificateRepo := new(mockRepositories.CertificateRepository)
+
+	mockCertificateRepo.EXPECT().FindCertificateByCode("AAAA-BBBB-CCCC-DDDD").Return(nil, nil)
+
+	underTest := NewCertificateService(mockCertificateRepo, nil, nil, nil, mockCertificateConfig())
+
+	verification, err := underTest.VerifyCertificate(&payload.CertificateVerifyQuery{Id: utils.Ptr(" aaaa-bbbb-cccc-dddd ")})
+
+	is.Nil(err)
+	is.False(verification.Valid)
+}
+
+func TestCertificateService(t *testing.T) {
+	suite.Run(t, new(CertificateServiceTestSuite))
+}
@@ -0,0 +1,11 @@
+package services
+
+import "backend/internals/entities/payload"
+
+type CohortService interface {
+	CreateCohort(body *payload.CreateCohort) (*payload.CohortInfo, error)
+	AddMember(cohortId uint64, body *payload.AddCohortMember) error
+	GetCohort(cohortId uint64, userId uint64) (*payload.CohortInfo, error)
+	SetUnlock(cohortId uint64, userId uint64, body *payload.CohortUnlockBody) (*payload.CohortUnlock, error)
+	Subscribe(cohortId uint64, userId uint64) (*payload.CohortUnlock, <-chan *payload.Event, func(), error)
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/repositories"
+	"backend/internals/utils"
+	utilServices "backend/internals/utils/services"
+	"fmt"
+	"strconv"
+)
+
+type cohortService struct {
+	cohortRepo        repositories.CohortRepository
+	courseRepo        repositories.CourseRepository
+	courseContentRepo repositories.CourseContentRepository
+	stepRepo          repositories.StepRepository
+	eventHub          utilServices.EventHub
+}
+
+func NewCohortService(
+	cohortRepo repositories.CohortRepository,
+	courseRepo repositories.CourseRepository,
+	courseContentRepo repositories.CourseContentRepository,
+	stepRepo repositories.StepRepository,
+	eventHub utilServices.EventHub) CohortService {
+	return &cohortService{
+		cohortRepo:        cohortRepo,
+		courseRepo:        courseRepo,
+		courseContentRepo: courseContentRepo,
+		stepRepo:          stepRepo,
+		eventHub:          eventHub,
+	}
+}
+
+func (r *cohortService) CreateCohort(body *payload.CreateCohort) (*payload.CohortInfo, error) {
+	course, err := r.courseRepo.FindCourseByCourseId(body.CourseId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find course %d: %w", *body.CourseId, err)
+	}
+
+	cohort := &models.Cohort{
+		CourseId: course.Id,
+		Course:   course,
+		Name:     body.Name,
+	}
+	if err := r.cohortRepo.CreateCohort(cohort); err != nil {
+		return nil, err
+	}
+
+	return cohortInfo(cohort), nil
+}
+
+func (r *cohortService) AddMember(cohortId uint64, body *payload.AddCohortMember) error {
+	cohort, err := r.findCohort(cohortId)
+	if err != nil {
+		return err
+	}
+
+	// the unlock of a learner is decided by a single cohort per course
+	if *body.Role == "learner" {
+		existing, err := r.cohortRepo.FindLearnerCohort(*body.UserId, *cohort.CourseId)
+		if err != nil {
+			return err
+		}
+		if existing != nil && *existing.Id != cohortId {
+			return fmt.Errorf("user %d is already a learner of cohort %d of this course", *body.UserId, *existing.Id)
+		}
+	}
+
+	return r.cohortRepo.SaveMember(&models.CohortMember{
+		CohortId: cohort.Id,
+		UserId:   body.UserId,
+		Role:     body.Role,
+	})
+}
+
+func (r *cohortService) GetCohort(cohortId uint64, userId uint64) (*payload.CohortInfo, error) {
+	cohort, err := r.findCohort(cohortId)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.findMember(cohortId, userId); err != nil {
+		return nil, err
+	}
+
+	return cohortInfo(cohort), nil
+}
+
+func (r *cohortService) SetUnlock(cohortId uint64, userId uint64, body *payload.CohortUnlockBody) (*payload.CohortUnlock, error) {
+	cohort, err := r.findCohort(cohortId)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := r.findMember(cohortId, userId)
+	if err != nil {
+		return nil, err
+	}
+	if *member.Role != "instructor" {
+		return nil, fmt.Errorf("only instructors of cohort %d can unlock steps", cohortId)
+	}
+
+	courseContent, err := r.courseContentRepo.FindCourseContentByCourseIdAndOrder(*cohort.CourseId, *body.Position)
+	if err != nil {
+		return nil, err
+	}
+	if courseContent == nil {
+		return nil, fmt.Errorf("course %d has no content at position %d", *cohort.CourseId, *body.Position)
+	}
+
+	if body.StepId != nil {
+		if courseContent.ModuleId == nil {
+			return nil, fmt.Errorf("course content at position %d is not a module", *body.Position)
+		}
+		steps, err := r.stepRepo.FindStepsByModuleID(utils.Ptr(strconv.FormatUint(*courseContent.ModuleId, 10)))
+		if err != nil {
+			return nil, err
+		}
+		if !utils.Contains(stepIds(steps), *body.StepId) {
+			return nil, fmt.Errorf("step %d is not part of the module at position %d", *body.StepId, *body.Position)
+		}
+	}
+
+	cohort.UnlockedOrder = body.Position
+	cohort.UnlockedStepId = body.StepId
+	if err := r.cohortRepo.UpdateCohort(cohort); err != nil {
+		return nil, err
+	}
+
+	unlock := cohortUnlock(cohort)
+	r.eventHub.Publish(utilServices.CohortTopic(cohortId), &payload.Event{
+		Type: payload.EventCohortUnlock,
+		Data: unlock,
+	})
+
+	return unlock, nil
+}
+
+// Subscribe returns the current unlock of the cohort and the events of its changes. The returned
+// function stops the events and must be called once the subscriber is gone.
+func (r *cohortService) Subscribe(cohortId uint64, userId uint64) (*payload.CohortUnlock, <-chan *payload.Event, func(), error) {
+	cohort, err := r.findCohort(cohortId)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if _, err := r.findMember(cohortId, userId); err != nil {
+		return nil, nil, nil, err
+	}
+
+	events, unsubscribe := r.eventHub.Subscribe(utilServices.CohortTopic(cohortId))
+
+	return cohortUnlock(cohort), events, unsubscribe, nil
+}
+
+func (r *cohortService) findCohort(cohortId uint64) (*models.Cohort, error) {
+	cohort, err := r.cohortRepo.FindCohortById(cohortId)
+	if err != nil {
+		return nil, err
+	}
+	if cohort == nil {
+		return nil, fmt.Errorf("cohort %d not found", cohortId)
+	}
+
+	return cohort, nil
+}
+
+func (r *cohortService) findMember(cohortId uint64, userId uint64) (*models.CohortMember, error) {
+	member, err := r.cohortRepo.FindMember(cohortId, userId)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, fmt.Errorf("user %d is not a member of cohort %d", userId, cohortId)
+	}
+
+	return member, nil
+}
+
+func cohortInfo(cohort *models.Cohort) *payload.CohortInfo {
+	info := &payload.CohortInfo{
+		CohortId: cohort.Id,
+		CourseId: cohort.CourseId,
+		Name:     cohort.Name,
+		Unlock:   cohortUnlock(cohort),
+	}
+	if cohort.Course != nil {
+		info.CourseName = cohort.Course.Name
+	}
+
+	return info
+}
+
+func cohortUnlock(cohort *models.Cohort) *payload.CohortUnlock {
+	return &payload.CohortUnlock{
+		CohortId:  cohort.Id,
+		CourseId:  cohort.CourseId,
+		Position:  cohort.UnlockedOrder,
+		StepId:    cohort.UnlockedStepId,
+		UpdatedAt: cohort.UpdatedAt,
+	}
+}
+
+func stepIds(steps []*models.Step) []uint64 {
+	ids := make([]uint64, 0, len(steps))
+	for _, step := range steps {
+		ids = append(ids, *step.Id)
+	}
+	return ids
+}
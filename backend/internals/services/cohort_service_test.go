@@ -0,0 +1,158 @@
+package services
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/utils"
+	utilServices "backend/internals/utils/services"
+	mockRepositories "backend/mocks/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type CohortServiceTestSuite struct {
+	suite.Suite
+}
+
+func mockCohort() *models.Cohort {
+	return &models.Cohort{
+		Id:       utils.Ptr(uint64(3)),
+		CourseId: utils.Ptr(uint64(7)),
+		Course:   &models.Course{Id: utils.Ptr(uint64(7)), Name: utils.Ptr("IoT 101")},
+		Name:     utils.Ptr("Section 1"),
+	}
+}
+
+func (suite *CohortServiceTestSuite) TestAddMemberWhenLearnerOfAnotherCohort() {
+	is := assert.New(suite.T())
+
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	mockCourseRepo := new(mockRepositories.CourseRepository)
+	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
+	mockStepRepo := new(mockRepositories.StepRepository)
+
+	other := mockCohort()
+	other.Id = utils.Ptr(uint64(4))
+
+	mockCohortRepo.EXPECT().FindCohortById(uint64(3)).Return(mockCohort(), nil)
+	mockCohortRepo.EXPECT().FindLearnerCohort(uint64(9), uint64(7)).Return(other, nil)
+
+	underTest := NewCohortService(mockCohortRepo, mockCourseRepo, mockCourseContentRepo, mockStepRepo, utilServices.NewEventHub())
+
+	err := underTest.AddMember(3, &payload.AddCohortMember{
+		UserId: utils.Ptr(uint64(9)),
+		Role:   utils.Ptr("learner"),
+	})
+
+	is.NotNil(err)
+	is.Equal("user 9 is already a learner of cohort 4 of this course", err.Error())
+	mockCohortRepo.AssertNotCalled(suite.T(), "SaveMember", mock.Anything)
+}
+
+func (suite *CohortServiceTestSuite) TestSetUnlockWhenNotInstructor() {
+	is := assert.New(suite.T())
+
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	mockCourseRepo := new(mockRepositories.CourseRepository)
+	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
+	mockStepRepo := new(mockRepositories.StepRepository)
+
+	mockCohortRepo.EXPECT().FindCohortById(uint64(3)).Return(mockCohort(), nil)
+	mockCohortRepo.EXPECT().FindMember(uint64(3), uint64(9)).Return(&models.CohortMember{Role: utils.Ptr("learner")}, nil)
+
+	underTest := NewCohortService(mockCohortRepo, mockCourseRepo, mockCourseContentRepo, mockStepRepo, utilServices.NewEventHub())
+
+	unlock, err := underTest.SetUnlock(3, 9, &payload.CohortUnlockBody{Position: utils.Ptr(int64(1))})
+
+	is.Nil(unlock)
+	is.Equal("only instructors of cohort 3 can unlock steps", err.Error())
+	mockCohortRepo.AssertNotCalled(suite.T(), "UpdateCohort", mock.Anything)
+}
+
+func (suite *CohortServiceTestSuite) TestSetUnlockWhenStepNotInModule() {
+	is := assert.New(suite.T())
+
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	mockCourseRepo := new(mockRepositories.CourseRepository)
+	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
+	mockStepRepo := new(mockRepositories.StepRepository)
+
+	mockCohortRepo.EXPECT().FindCohortById(uint64(3)).Return(mockCohort(), nil)
+	mockCohortRepo.EXPECT().FindMember(uint64(3), uint64(2)).Return(&models.CohortMember{Role: utils.Ptr("instructor")}, nil)
+	mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndOrder(uint64(7), int64(1)).Return(&models.CourseContent{ModuleId: utils.Ptr(uint64(5))}, nil)
+	mockStepRepo.EXPECT().FindStepsByModuleID(utils.Ptr("5")).Return([]*models.Step{{Id: utils.Ptr(uint64(10))}, {Id: utils.Ptr(uint64(11))}}, nil)
+
+	underTest := NewCohortService(mockCohortRepo, mockCourseRepo, mockCourseContentRepo, mockStepRepo, utilServices.NewEventHub())
+
+	unlock, err := underTest.SetUnlock(3, 2, &payload.CohortUnlockBody{
+		Position: utils.Ptr(int64(1)),
+		StepId:   utils.Ptr(uint64(12)),
+	})
+
+	is.Nil(unlock)
+	is.Equal("step 12 is not part of the module at position 1", err.Error())
+}
+
+func (suite *CohortServiceTestSuite) TestSetUnlockPublishesToSubscribers() {
+	is := assert.New(suite.T())
+
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	mockCourseRepo := new(mockRepositories.CourseRepository)
+	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
+	mockStepRepo := new(mockRepositories.StepRepository)
+
+	mockCohortRepo.EXPECT().FindCohortById(uint64(3)).Return(mockCohort(), nil)
+	mockCohortRepo.EXPECT().FindMember(uint64(3), uint64(9)).Return(&models.CohortMember{Role: utils.Ptr("learner")}, nil)
+	mockCohortRepo.EXPECT().FindMember(uint64(3), uint64(2)).Return(&models.CohortMember{Role: utils.Ptr("instructor")}, nil)
+	mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndOrder(uint64(7), mock.Anything).Return(&models.CourseContent{ModuleId: utils.Ptr(uint64(5))}, nil)
+	mockCohortRepo.EXPECT().UpdateCohort(mock.Anything).Return(nil)
+
+	underTest := NewCohortService(mockCohortRepo, mockCourseRepo, mockCourseContentRepo, mockStepRepo, utilServices.NewEventHub())
+
+	current, updates, unsubscribe, err := underTest.Subscribe(3, 9)
+	is.Nil(err)
+	is.Nil(current.Position)
+
+	_, err = underTest.SetUnlock(3, 2, &payload.CohortUnlockBody{Position: utils.Ptr(int64(1))})
+	is.Nil(err)
+	_, err = underTest.SetUnlock(3, 2, &payload.CohortUnlockBody{Position: utils.Ptr(int64(2))})
+	is.Nil(err)
+
+	for _, position := range []int64{1, 2} {
+		event := <-updates
+		is.Equal(payload.EventCohortUnlock, event.Type)
+		is.Equal(position, *event.Data.(*payload.CohortUnlock).Position)
+	}
+
+	// the events end once unsubscribed
+	unsubscribe()
+	_, err = underTest.SetUnlock(3, 2, &payload.CohortUnlockBody{Position: utils.Ptr(int64(3))})
+	is.Nil(err)
+	_, open := <-updates
+	is.False(open)
+}
+
+func (suite *CohortServiceTestSuite) TestSubscribeWhenNotMember() {
+	is := assert.New(suite.T())
+
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	mockCourseRepo := new(mockRepositories.CourseRepository)
+	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
+	mockStepRepo := new(mockRepositories.StepRepository)
+
+	mockCohortRepo.EXPECT().FindCohortById(uint64(3)).Return(mockCohort(), nil)
+	mockCohortRepo.EXPECT().FindMember(uint64(3), uint64(9)).Return(nil, nil)
+
+	underTest := NewCohortService(mockCohortRepo, mockCourseRepo, mockCourseContentRepo, mockStepRepo, utilServices.NewEventHub())
+
+	_, updates, _, err := underTest.Subscribe(3, 9)
+
+	is.Nil(updates)
+	is.Equal("user 9 is not a member of cohort 3", err.Error())
+}
+
+func TestCohortService(t *testing.T) {
+	suite.Run(t, new(CohortServiceTestSuite))
+}
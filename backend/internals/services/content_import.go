@@ -0,0 +1,8 @@
+package services
+
+type ContentImportService interface {
+	ImportModule(documentId string) error
+	ImportModules(parentDocumentId string) error
+	ImportCourse(documentId string) error
+	ImportCourses(parentDocumentId string) error
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"backend/internals/config"
+	"backend/internals/content"
+	"backend/internals/repositories"
+	utilServices "backend/internals/utils/services"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+var attachmentPattern = regexp.MustCompile(`!\[]\(/api/attachments\.redirect\?id=([^\)]+)\)`)
+
+type contentImportService struct {
+	contentImportRepo repositories.ContentImportRepository
+	outlineService    utilServices.OutlineService
+	minioService      utilServices.MinioService
+	conf              *config.Config
+}
+
+func NewContentImportService(
+	contentImportRepo repositories.ContentImportRepository,
+	outlineService utilServices.OutlineService,
+	minioService utilServices.MinioService,
+	conf *config.Config) ContentImportService {
+	return &contentImportService{
+		contentImportRepo: contentImportRepo,
+		outlineService:    outlineService,
+		minioService:      minioService,
+		conf:              conf,
+	}
+}
+
+func (r *contentImportService) ImportModule(documentId string) error {
+	markdown, err := r.outlineService.ExportDocument(documentId)
+	if err != nil {
+		return fmt.Errorf("failed to export document %s: %w", documentId, err)
+	}
+
+	document, err := content.ParseModule(markdown)
+	if err != nil {
+		return err
+	}
+
+	// replace attachments paths
+	for _, step := range document.Steps {
+		for _, section := range []*string{&step.Description, &step.Content, &step.Outcome, &step.Check, &step.Error} {
+			if *section, err = r.replaceAttachmentPaths(*section); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := r.contentImportRepo.SaveModule(document); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *contentImportService) ImportModules(parentDocumentId string) error {
+	return r.importChildren(parentDocumentId, r.ImportModule)
+}
+
+func (r *contentImportService) ImportCourse(documentId string) error {
+	markdown, err := r.outlineService.ExportDocument(documentId)
+	if err != nil {
+		return fmt.Errorf("failed to export document %s: %w", documentId, err)
+	}
+
+	document, err := content.ParseCourse(markdown)
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.contentImportRepo.SaveCourse(document); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *contentImportService) ImportCourses(parentDocumentId string) error {
+	return r.importChildren(parentDocumentId, r.ImportCourse)
+}
+
+func (r *contentImportService) importChildren(parentDocumentId string, importDocument func(documentId string) error) error {
+	parent, err := r.outlineService.DocumentInfo(parentDocumentId)
+	if err != nil {
+		return fmt.Errorf("failed to get parent document %s: %w", parentDocumentId, err)
+	}
+
+	documents, err := r.outlineService.ListDocuments(parent.Id)
+	if err != nil {
+		return fmt.Errorf("failed to list documents of %s: %w", parent.Id, err)
+	}
+
+	for _, document := range documents {
+		if err := importDocument(document.Id); err != nil {
+			return fmt.Errorf("failed to import document %s: %w", document.Id, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *contentImportService) replaceAttachmentPaths(markdown string) (string, error) {
+	var replaceErr error
+	replaced := attachmentPattern.ReplaceAllStringFunc(markdown, func(match string) string {
+		if replaceErr != nil {
+			return match
+		}
+
+		attachmentId := attachmentPattern.FindStringSubmatch(match)[1]
+		attachmentId = strings.Split(attachmentId, " ")[0]
+
+		location, err := r.outlineService.AttachmentLocation(attachmentId)
+		if err != nil {
+			replaceErr = fmt.Errorf("failed to get attachment location: %w", err)
+			return match
+		}
+
+		stableUrl, err := r.mirrorAttachment(location)
+		if err != nil {
+			replaceErr = err
+			return match
+		}
+
+		return fmt.Sprintf("![](%s)", stableUrl)
+	})
+
+	return replaced, replaceErr
+}
+
+// mirrorAttachment downloads the attachment behind the (usually short-lived, signed)
+// location and stores it in our bucket under its content hash, returning the stable url.
+func (r *contentImportService) mirrorAttachment(location string) (string, error) {
+	data, contentType, err := r.outlineService.Download(location)
+	if err != nil {
+		return "", fmt.Errorf("failed to download attachment: %w", err)
+	}
+
+	// resolve file extension from the original path, then from the content type
+	extension := ""
+	if parsed, err := url.Parse(location); err == nil {
+		extension = strings.ToLower(path.Ext(parsed.Path))
+	}
+	if extension == "" {
+		if extensions, err := mime.ExtensionsByType(contentType); err == nil && len(extensions) > 0 {
+			extension = extensions[0]
+		}
+	}
+
+	return storeAttachment(r.minioService, r.conf, data, contentType, extension)
+}
+
+// storeAttachment stores the attachment in our bucket under its content hash and returns
+// its stable url; attachments already stored are not uploaded again.
+func storeAttachment(minioService utilServices.MinioService, conf *config.Config, data []byte, contentType string, extension string) (string, error) {
+	hash := sha256.Sum256(data)
+	objectName := "attachments/" + hex.EncodeToString(hash[:]) + extension
+
+	exists, err := minioService.ObjectExists(context.Background(), *conf.MinioS3BucketName, objectName)
+	if err != nil {
+		return "", fmt.Errorf("failed to check stored attachment: %w", err)
+	}
+	if !exists {
+		if err := minioService.PutObjectBytes(context.Background(), *conf.MinioS3BucketName, objectName, data, contentType); err != nil {
+			return "", fmt.Errorf("failed to upload attachment: %w", err)
+		}
+	}
+
+	return url.JoinPath(*conf.MinioS3Endpoint, *conf.MinioS3BucketName, objectName)
+}
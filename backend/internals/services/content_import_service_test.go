@@ -0,0 +1,167 @@
+package services
+
+import (
+	"backend/internals/config"
+	"backend/internals/content"
+	"backend/internals/entities/payload"
+	mockRepositories "backend/mocks/repositories"
+	mockUtilServices "backend/mocks/utils"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+)
+
+type ContentImportServiceTestSuite struct {
+	suite.Suite
+}
+
+const mockModuleMarkdown = `# Wi-Fi basics
+## Image
+
+<https://example.com/banner.png>
+## Description
+
+Connect your board
+# Step 1: Connect
+## Description
+Join the network
+## Content
+![](/api/attachments.redirect?id=abc "image")
+## Outcome
+Connected
+## Check
+Ping works
+## Error
+No IP
+## Evaluation
+* Show the serial output
+* image
+* Upload a photo
+* 5
+`
+
+func (suite *ContentImportServiceTestSuite) TestImportModuleWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockContentImportRepo := new(mockRepositories.ContentImportRepository)
+	mockOutlineService := new(mockUtilServices.OutlineService)
+	mockMinioService := new(mockUtilServices.MinioService)
+
+	var saved *content.ModuleDocument
+	mockOutlineService.EXPECT().ExportDocument("doc").Return(mockModuleMarkdown, nil)
+	mockOutlineService.EXPECT().AttachmentLocation("abc").Return("https://files.example.com/a/photo.png?signature=1", nil)
+	mockOutlineService.EXPECT().Download("https://files.example.com/a/photo.png?signature=1").Return([]byte("png"), "image/png", nil)
+	mockMinioService.EXPECT().ObjectExists(mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+	mockMinioService.EXPECT().PutObjectBytes(mock.Anything, mock.Anything, mock.MatchedBy(func(objectName string) bool {
+		return strings.HasPrefix(objectName, "attachments/") && strings.HasSuffix(objectName, ".png")
+	}), []byte("png"), "image/png").Return(nil)
+	mockContentImportRepo.EXPECT().SaveModule(mock.Anything).Run(func(document *content.ModuleDocument) {
+		saved = document
+	}).Return(nil, nil)
+
+	underTest := NewContentImportService(mockContentImportRepo, mockOutlineService, mockMinioService, config.Env)
+
+	err := underTest.ImportModule("doc")
+
+	is.Nil(err)
+	is.Equal("Wi-Fi basics", saved.Title)
+	is.Equal("https://example.com/banner.png", saved.ImageUrl)
+	is.Len(saved.Steps, 1)
+	is.Equal("Connect", saved.Steps[0].Title)
+	is.NotContains(saved.Steps[0].Content, "attachments.redirect")
+	is.Contains(saved.Steps[0].Content, *config.Env.MinioS3BucketName+"/attachments/")
+	is.Len(saved.Steps[0].Evaluations, 1)
+	is.Equal(5, saved.Steps[0].Evaluations[0].Gem)
+	is.Equal("image", saved.Steps[0].Evaluations[0].Type)
+}
+
+func (suite *ContentImportServiceTestSuite) TestImportModuleWhenAttachmentAlreadyMirrored() {
+	is := assert.New(suite.T())
+
+	mockContentImportRepo := new(mockRepositories.ContentImportRepository)
+	mockOutlineService := new(mockUtilServices.OutlineService)
+	mockMinioService := new(mockUtilServices.MinioService)
+
+	mockOutlineService.EXPECT().ExportDocument("doc").Return(mockModuleMarkdown, nil)
+	mockOutlineService.EXPECT().AttachmentLocation("abc").Return("https://files.example.com/a/photo.png", nil)
+	mockOutlineService.EXPECT().Download(mock.Anything).Return([]byte("png"), "image/png", nil)
+	mockMinioService.EXPECT().ObjectExists(mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	mockContentImportRepo.EXPECT().SaveModule(mock.Anything).Return(nil, nil)
+
+	underTest := NewContentImportService(mockContentImportRepo, mockOutlineService, mockMinioService, config.Env)
+
+	err := underTest.ImportModule("doc")
+
+	is.Nil(err)
+	mockMinioService.AssertNotCalled(suite.T(), "PutObjectBytes", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *ContentImportServiceTestSuite) TestImportModuleWhenMissingSections() {
+	is := assert.New(suite.T())
+
+	mockContentImportRepo := new(mockRepositories.ContentImportRepository)
+	mockOutlineService := new(mockUtilServices.OutlineService)
+	mockMinioService := new(mockUtilServices.MinioService)
+
+	mockOutlineService.EXPECT().ExportDocument("doc").Return("# Module\n# Step 1: Empty\n## Description\ntext\n", nil)
+
+	underTest := NewContentImportService(mockContentImportRepo, mockOutlineService, mockMinioService, config.Env)
+
+	err := underTest.ImportModule("doc")
+
+	is.NotNil(err)
+	is.Contains(err.Error(), "missing required sections")
+	mockContentImportRepo.AssertNotCalled(suite.T(), "SaveModule", mock.Anything)
+}
+
+func (suite *ContentImportServiceTestSuite) TestImportCourseWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockContentImportRepo := new(mockRepositories.ContentImportRepository)
+	mockOutlineService := new(mockUtilServices.OutlineService)
+
+	markdown := "# IoT 101\n## Field\n\nHardware\n## Introduction\n\nWelcome\n## Module\n\nWi-Fi basics\nMQTT basics\n"
+
+	var saved *content.CourseDocument
+	mockOutlineService.EXPECT().ExportDocument("doc").Return(markdown, nil)
+	mockContentImportRepo.EXPECT().SaveCourse(mock.Anything).Run(func(document *content.CourseDocument) {
+		saved = document
+	}).Return(nil, nil)
+
+	underTest := NewContentImportService(mockContentImportRepo, mockOutlineService, nil, config.Env)
+
+	err := underTest.ImportCourse("doc")
+
+	is.Nil(err)
+	is.Equal("IoT 101", saved.Name)
+	is.Equal("Hardware", saved.FieldName)
+	is.Len(saved.Contents, 3)
+	is.Equal("text", saved.Contents[0].Type)
+	is.Equal("Welcome\n", saved.Contents[0].Text)
+	is.Equal("MQTT basics", saved.Contents[2].ModuleTitle)
+}
+
+func (suite *ContentImportServiceTestSuite) TestImportModulesWhenChildFails() {
+	is := assert.New(suite.T())
+
+	mockContentImportRepo := new(mockRepositories.ContentImportRepository)
+	mockOutlineService := new(mockUtilServices.OutlineService)
+
+	mockOutlineService.EXPECT().DocumentInfo("parent").Return(&payload.OutlineDocument{Id: "parent-id"}, nil)
+	mockOutlineService.EXPECT().ListDocuments("parent-id").Return([]*payload.OutlineDocument{{Id: "child"}}, nil)
+	mockOutlineService.EXPECT().ExportDocument("child").Return("", fmt.Errorf("outline unavailable"))
+
+	underTest := NewContentImportService(mockContentImportRepo, mockOutlineService, nil, config.Env)
+
+	err := underTest.ImportModules("parent")
+
+	is.NotNil(err)
+	is.Contains(err.Error(), "outline unavailable")
+}
+
+func TestContentImportService(t *testing.T) {
+	suite.Run(t, new(ContentImportServiceTestSuite))
+}
@@ -0,0 +1,7 @@
+package services
+
+import "backend/internals/entities/payload"
+
+type ContentLintService interface {
+	LintDocuments(moduleParentDocumentId string, courseParentDocumentId string) ([]*payload.LintProblem, error)
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"backend/internals/content"
+	"backend/internals/entities/payload"
+	"backend/internals/repositories"
+	utilServices "backend/internals/utils/services"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var attachmentRedirectPattern = regexp.MustCompile(`^/api/attachments\.redirect\?id=(.+)$`)
+
+type contentLintService struct {
+	moduleRepo     repositories.ModulesRepository
+	fieldTypeRepo  repositories.FieldTypeRepository
+	outlineService utilServices.OutlineService
+}
+
+func NewContentLintService(
+	moduleRepo repositories.ModulesRepository,
+	fieldTypeRepo repositories.FieldTypeRepository,
+	outlineService utilServices.OutlineService) ContentLintService {
+	return &contentLintService{
+		moduleRepo:     moduleRepo,
+		fieldTypeRepo:  fieldTypeRepo,
+		outlineService: outlineService,
+	}
+}
+
+// lintRun holds the state shared by the documents linted in one LintDocuments call.
+type lintRun struct {
+	problems     []*payload.LintProblem
+	moduleTitles map[string]bool
+	brokenLinks  map[string]error
+}
+
+func (r *lintRun) report(document *payload.OutlineDocument, line int, message string) {
+	r.problems = append(r.problems, &payload.LintProblem{
+		DocumentId: document.Id,
+		Title:      document.Title,
+		Line:       line,
+		Message:    message,
+	})
+}
+
+func (r *contentLintService) LintDocuments(moduleParentDocumentId string, courseParentDocumentId string) ([]*payload.LintProblem, error) {
+	run := &lintRun{
+		problems:     make([]*payload.LintProblem, 0),
+		moduleTitles: make(map[string]bool),
+		brokenLinks:  make(map[string]error),
+	}
+
+	// modules first, so courses can reference modules that are not imported yet
+	if moduleParentDocumentId != "" {
+		err := r.lintChildren(moduleParentDocumentId, func(document *payload.OutlineDocument, markdown string) error {
+			module, problems := content.LintModule(markdown)
+			for _, problem := range problems {
+				run.report(document, problem.Line, problem.Message)
+			}
+			if module != nil {
+				run.moduleTitles[module.Title] = true
+			}
+			r.lintImages(run, document, markdown)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if courseParentDocumentId != "" {
+		fieldTypes, err := r.fieldTypeRepo.FindAllFieldTypes()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get field types: %w", err)
+		}
+		fieldNames := make(map[string]bool)
+		for _, fieldType := range fieldTypes {
+			fieldNames[*fieldType.Name] = true
+		}
+
+		err = r.lintChildren(courseParentDocumentId, func(document *payload.OutlineDocument, markdown string) error {
+			course, problems := content.LintCourse(markdown)
+			for _, problem := range problems {
+				run.report(document, problem.Line, problem.Message)
+			}
+			if course == nil {
+				return nil
+			}
+
+			if course.FieldName != "" && !fieldNames[course.FieldName] {
+				run.report(document, fieldLine(markdown), fmt.Sprintf("unknown field %q", course.FieldName))
+			}
+			for _, courseContent := range course.Contents {
+				if courseContent.Type != "module" {
+					continue
+				}
+				exists, err := r.moduleExists(run, courseContent.ModuleTitle)
+				if err != nil {
+					return err
+				}
+				if !exists {
+					run.report(document, courseContent.Line, fmt.Sprintf("module %q does not exist", courseContent.ModuleTitle))
+				}
+			}
+			r.lintImages(run, document, markdown)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return run.problems, nil
+}
+
+func (r *contentLintService) lintChildren(parentDocumentId string, lint func(document *payload.OutlineDocument, markdown string) error) error {
+	parent, err := r.outlineService.DocumentInfo(parentDocumentId)
+	if err != nil {
+		return fmt.Errorf("failed to get parent document %s: %w", parentDocumentId, err)
+	}
+
+	documents, err := r.outlineService.ListDocuments(parent.Id)
+	if err != nil {
+		return fmt.Errorf("failed to list documents of %s: %w", parent.Id, err)
+	}
+
+	for _, document := range documents {
+		markdown, err := r.outlineService.ExportDocument(document.Id)
+		if err != nil {
+			return fmt.Errorf("failed to export document %s: %w", document.Id, err)
+		}
+		if err := lint(document, markdown); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// moduleExists checks the modules linted in this run, then the modules already imported.
+func (r *contentLintService) moduleExists(run *lintRun, title string) (bool, error) {
+	if exists, ok := run.moduleTitles[title]; ok {
+		return exists, nil
+	}
+
+	module, err := r.moduleRepo.FindModuleByTitle(title)
+	if err != nil {
+		return false, fmt.Errorf("failed to find module %q: %w", title, err)
+	}
+	run.moduleTitles[title] = module != nil
+	return run.moduleTitles[title], nil
+}
+
+func (r *contentLintService) lintImages(run *lintRun, document *payload.OutlineDocument, markdown string) {
+	images, problems := content.FindImages(markdown)
+	for _, problem := range problems {
+		run.report(document, problem.Line, problem.Message)
+	}
+
+	for _, image := range images {
+		location := strings.TrimSpace(image.Url)
+
+		err, checked := run.brokenLinks[location]
+		if !checked {
+			err = r.checkImage(location)
+			run.brokenLinks[location] = err
+		}
+		if err != nil {
+			run.report(document, image.Line, fmt.Sprintf("broken image link %s: %s", location, err))
+		}
+	}
+}
+
+func (r *contentLintService) checkImage(location string) error {
+	if match := attachmentRedirectPattern.FindStringSubmatch(location); match != nil {
+		_, err := r.outlineService.AttachmentLocation(match[1])
+		return err
+	}
+
+	if !strings.HasPrefix(location, "http://") && !strings.HasPrefix(location, "https://") {
+		return fmt.Errorf("not an absolute url")
+	}
+
+	_, _, err := r.outlineService.Download(location)
+	return err
+}
+
+func fieldLine(markdown string) int {
+	lines := strings.Split(markdown, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "## Field" {
+			// the field name is the first non-empty line below the heading
+			for j := i + 1; j < len(lines); j++ {
+				if strings.TrimSpace(lines[j]) != "" {
+					return j + 1
+				}
+			}
+		}
+	}
+	return 1
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/utils"
+	mockRepositories "backend/mocks/repositories"
+	mockUtilServices "backend/mocks/utils"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type ContentLintServiceTestSuite struct {
+	suite.Suite
+}
+
+const mockBrokenModuleMarkdown = `# Wi-Fi basics
+## Image
+
+<https://example.com/banner.png>
+## Description
+
+Connect your board
+# Step 1: Connect
+## Description
+Join the network
+## Content
+![](/api/attachments.redirect?id=gone "image")
+## Outcome
+Connected
+## Error
+No IP
+## Evaluation
+* Show the serial output
+* photo
+* Upload a photo
+* five
+# Step 2: Connect
+## Description
+Again
+## Content
+Again
+## Outcome
+Again
+## Check
+Again
+## Error
+Again
+## Evaluation
+* Done?
+* check
+`
+
+func (suite *ContentLintServiceTestSuite) TestLintDocumentsWhenProblemsFound() {
+	is := assert.New(suite.T())
+
+	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockFieldTypeRepo := new(mockRepositories.FieldTypeRepository)
+	mockOutlineService := new(mockUtilServices.OutlineService)
+
+	courseMarkdown := "# IoT 101\n## Field\n\nCooking\n## Module\n\nWi-Fi basics\nMQTT basics\n"
+
+	mockOutlineService.EXPECT().DocumentInfo("modules").Return(&payload.OutlineDocument{Id: "modules"}, nil)
+	mockOutlineService.EXPECT().DocumentInfo("courses").Return(&payload.OutlineDocument{Id: "courses"}, nil)
+	mockOutlineService.EXPECT().ListDocuments("modules").Return([]*payload.OutlineDocument{{Id: "m1", Title: "Wi-Fi basics"}}, nil)
+	mockOutlineService.EXPECT().ListDocuments("courses").Return([]*payload.OutlineDocument{{Id: "c1", Title: "IoT 101"}}, nil)
+	mockOutlineService.EXPECT().ExportDocument("m1").Return(mockBrokenModuleMarkdown, nil)
+	mockOutlineService.EXPECT().ExportDocument("c1").Return(courseMarkdown, nil)
+	mockOutlineService.EXPECT().Download("https://example.com/banner.png").Return([]byte("png"), "image/png", nil)
+	mockOutlineService.EXPECT().AttachmentLocation("gone").Return("", fmt.Errorf("not found"))
+	mockFieldTypeRepo.EXPECT().FindAllFieldTypes().Return([]models.FieldType{{Name: utils.Ptr("Hardware")}}, nil)
+	mockModuleRepo.EXPECT().FindModuleByTitle("MQTT basics").Return(nil, nil)
+
+	underTest := NewContentLintService(mockModuleRepo, mockFieldTypeRepo, mockOutlineService)
+
+	problems, err := underTest.LintDocuments("modules", "courses")
+
+	messages := make([]string, 0)
+	for _, problem := range problems {
+		messages = append(messages, fmt.Sprintf("%s:%d: %s", problem.DocumentId, problem.Line, problem.Message))
+	}
+
+	is.Nil(err)
+	is.ElementsMatch([]string{
+		`m1:8: step "Connect" is missing required section Check`,
+		`m1:19: invalid evaluation type "photo", expected check, text or image`,
+		`m1:21: invalid gem value "five"`,
+		`m1:22: duplicate step title "Connect", first used on line 8`,
+		`m1:34: incomplete evaluation, expected 4 items but found 2`,
+		`m1:12: broken image link /api/attachments.redirect?id=gone: not found`,
+		`c1:4: unknown field "Cooking"`,
+		`c1:8: module "MQTT basics" does not exist`,
+	}, messages)
+}
+
+func (suite *ContentLintServiceTestSuite) TestLintDocumentsWhenClean() {
+	is := assert.New(suite.T())
+
+	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockFieldTypeRepo := new(mockRepositories.FieldTypeRepository)
+	mockOutlineService := new(mockUtilServices.OutlineService)
+
+	courseMarkdown := "# IoT 101\n## Field\n\nHardware\n## Module\n\nWi-Fi basics\n"
+
+	mockOutlineService.EXPECT().DocumentInfo("courses").Return(&payload.OutlineDocument{Id: "courses"}, nil)
+	mockOutlineService.EXPECT().ListDocuments("courses").Return([]*payload.OutlineDocument{{Id: "c1", Title: "IoT 101"}}, nil)
+	mockOutlineService.EXPECT().ExportDocument("c1").Return(courseMarkdown, nil)
+	mockFieldTypeRepo.EXPECT().FindAllFieldTypes().Return([]models.FieldType{{Name: utils.Ptr("Hardware")}}, nil)
+	mockModuleRepo.EXPECT().FindModuleByTitle("Wi-Fi basics").Return(&models.Module{Id: utils.Ptr(uint64(1))}, nil)
+
+	underTest := NewContentLintService(mockModuleRepo, mockFieldTypeRepo, mockOutlineService)
+
+	problems, err := underTest.LintDocuments("", "courses")
+
+	is.Nil(err)
+	is.Empty(problems)
+}
+
+func (suite *ContentLintServiceTestSuite) TestLintDocumentsWhenOutlineFailed() {
+	is := assert.New(suite.T())
+
+	mockOutlineService := new(mockUtilServices.OutlineService)
+
+	mockOutlineService.EXPECT().DocumentInfo("modules").Return(nil, fmt.Errorf("unauthorized"))
+
+	underTest := NewContentLintService(nil, nil, mockOutlineService)
+
+	problems, err := underTest.LintDocuments("modules", "")
+
+	is.Nil(problems)
+	is.NotNil(err)
+}
+
+func (suite *ContentLintServiceTestSuite) TestLintDocumentsWhenModuleLookupFailed() {
+	is := assert.New(suite.T())
+
+	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockFieldTypeRepo := new(mockRepositories.FieldTypeRepository)
+	mockOutlineService := new(mockUtilServices.OutlineService)
+
+	courseMarkdown := "# IoT 101\n## Field\n\nHardware\n## Module\n\nWi-Fi basics\n"
+
+	mockOutlineService.EXPECT().DocumentInfo("courses").Return(&payload.OutlineDocument{Id: "courses"}, nil)
+	mockOutlineService.EXPECT().ListDocuments("courses").Return([]*payload.OutlineDocument{{Id: "c1", Title: "IoT 101"}}, nil)
+	mockOutlineService.EXPECT().ExportDocument("c1").Return(courseMarkdown, nil)
+	mockFieldTypeRepo.EXPECT().FindAllFieldTypes().Return([]models.FieldType{{Name: utils.Ptr("Hardware")}}, nil)
+	mockModuleRepo.EXPECT().FindModuleByTitle("Wi-Fi basics").Return(nil, fmt.Errorf("connection refused"))
+
+	underTest := NewContentLintService(mockModuleRepo, mockFieldTypeRepo, mockOutlineService)
+
+	problems, err := underTest.LintDocuments("", "courses")
+
+	// a lookup failure is not reported as a missing module
+	is.Nil(problems)
+	is.ErrorContains(err, "connection refused")
+}
+
+func TestContentLintService(t *testing.T) {
+	suite.Run(t, new(ContentLintServiceTestSuite))
+}
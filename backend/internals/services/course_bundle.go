@@ -0,0 +1,8 @@
+package services
+
+import "backend/internals/entities/payload"
+
+type CourseBundleService interface {
+	Export(courseId uint64) ([]byte, error)
+	Import(data []byte) (*payload.CourseBundleImportResult, error)
+}
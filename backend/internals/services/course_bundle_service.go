@@ -0,0 +1,343 @@
+package services
+
+import (
+	"backend/internals/config"
+	"backend/internals/content"
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/repositories"
+	"backend/internals/utils"
+	utilServices "backend/internals/utils/services"
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type courseBundleService struct {
+	coursePageRepo    repositories.CoursePageRepo
+	moduleRepo        repositories.ModulesRepository
+	stepRepo          repositories.StepRepository
+	stepEvalRepo      repositories.StepEvaluateRepository
+	contentImportRepo repositories.ContentImportRepository
+	minioService      utilServices.MinioService
+	conf              *config.Config
+}
+
+func NewCourseBundleService(
+	coursePageRepo repositories.CoursePageRepo,
+	moduleRepo repositories.ModulesRepository,
+	stepRepo repositories.StepRepository,
+	stepEvalRepo repositories.StepEvaluateRepository,
+	contentImportRepo repositories.ContentImportRepository,
+	minioService utilServices.MinioService,
+	conf *config.Config) CourseBundleService {
+	return &courseBundleService{
+		coursePageRepo:    coursePageRepo,
+		moduleRepo:        moduleRepo,
+		stepRepo:          stepRepo,
+		stepEvalRepo:      stepEvalRepo,
+		contentImportRepo: contentImportRepo,
+		minioService:      minioService,
+		conf:              conf,
+	}
+}
+
+func (r *courseBundleService) Export(courseId uint64) ([]byte, error) {
+	course, err := r.coursePageRepo.FindCoursePageInfoByCoursePageID(strconv.FormatUint(courseId, 10))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find course %d: %w", courseId, err)
+	}
+
+	contents, err := r.coursePageRepo.FindCoursePageContentByCoursePageID(strconv.FormatUint(courseId, 10))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find course contents: %w", err)
+	}
+
+	bundle := &content.Bundle{
+		Manifest: &content.BundleManifest{
+			Version:    content.BundleVersion,
+			ExportedAt: utils.TimeNow(),
+			Course: content.BundleCourse{
+				SourceId: *course.Id,
+				Name:     *course.Name,
+				File:     "course.md",
+			},
+		},
+		Course: &content.CourseDocument{
+			Name: *course.Name,
+		},
+		Evaluations: make([]*content.BundleEvaluation, 0),
+		Assets:      make(map[string][]byte),
+	}
+	if course.Field != nil && course.Field.Name != nil {
+		bundle.Course.FieldName = *course.Field.Name
+		bundle.Manifest.Course.FieldName = *course.Field.Name
+	}
+
+	exported := make(map[uint64]bool)
+	for _, courseContent := range contents {
+		if courseContent.Type == nil || *courseContent.Type != "module" {
+			bundle.Course.Contents = append(bundle.Course.Contents, &content.CourseContentDocument{
+				Type: "text",
+				Text: utils.Val(courseContent.Text),
+			})
+			continue
+		}
+
+		module, err := r.moduleRepo.GetModuleById(courseContent.ModuleId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find module %d: %w", *courseContent.ModuleId, err)
+		}
+		bundle.Course.Contents = append(bundle.Course.Contents, &content.CourseContentDocument{
+			Type:        "module",
+			ModuleTitle: *module.Title,
+		})
+
+		// a module listed twice in the course is exported once
+		if exported[*module.Id] {
+			continue
+		}
+		exported[*module.Id] = true
+
+		if err := r.exportModule(bundle, module); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := r.exportAssets(bundle); err != nil {
+		return nil, err
+	}
+
+	buffer := new(bytes.Buffer)
+	if err := content.WriteBundle(buffer, bundle); err != nil {
+		return nil, fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func (r *courseBundleService) exportModule(bundle *content.Bundle, module *models.Module) error {
+	moduleId := strconv.FormatUint(*module.Id, 10)
+	steps, err := r.stepRepo.FindStepsByModuleID(&moduleId)
+	if err != nil {
+		return fmt.Errorf("failed to find steps of module %d: %w", *module.Id, err)
+	}
+
+	document := &content.ModuleDocument{
+		Title:       *module.Title,
+		ImageUrl:    utils.Val(module.ImageUrl),
+		Description: utils.Val(module.Description),
+	}
+	manifest := content.BundleModule{
+		SourceId: *module.Id,
+		Title:    *module.Title,
+		File:     fmt.Sprintf("modules/%d.md", *module.Id),
+		Steps:    make([]content.BundleStep, 0, len(steps)),
+	}
+
+	for _, step := range steps {
+		document.Steps = append(document.Steps, &content.StepDocument{
+			Title:       *step.Title,
+			Description: utils.Val(step.Description),
+			Content:     utils.Val(step.Content),
+			Outcome:     utils.Val(step.Outcome),
+			Check:       utils.Val(step.Check),
+			Error:       utils.Val(step.Error),
+		})
+		manifest.Steps = append(manifest.Steps, content.BundleStep{
+			SourceId: *step.Id,
+			Title:    *step.Title,
+		})
+
+		evaluations, err := r.stepEvalRepo.GetStepEvalByStepId(step.Id)
+		if err != nil {
+			return fmt.Errorf("failed to find evaluations of step %d: %w", *step.Id, err)
+		}
+		sort.Slice(evaluations, func(i, j int) bool {
+			return *evaluations[i].Order < *evaluations[j].Order
+		})
+		for _, evaluation := range evaluations {
+			bundle.Evaluations = append(bundle.Evaluations, &content.BundleEvaluation{
+				SourceId:       *evaluation.Id,
+				ModuleSourceId: *module.Id,
+				StepTitle:      *step.Title,
+				Order:          *evaluation.Order,
+				Question:       *evaluation.Question,
+				Type:           *evaluation.Type,
+				Instruction:    utils.Val(evaluation.Instruction),
+				Gem:            *evaluation.Gem,
+			})
+		}
+	}
+
+	bundle.Modules = append(bundle.Modules, document)
+	bundle.Manifest.Modules = append(bundle.Manifest.Modules, manifest)
+
+	return nil
+}
+
+// exportAssets copies every object of our bucket referenced by the bundle markdown into
+// the bundle, rewriting the references to the bundled files.
+func (r *courseBundleService) exportAssets(bundle *content.Bundle) error {
+	bucketUrl, err := url.JoinPath(*r.conf.MinioS3Endpoint, *r.conf.MinioS3BucketName)
+	if err != nil {
+		return err
+	}
+	pattern := regexp.MustCompile(regexp.QuoteMeta(bucketUrl+"/") + `[^\s)>"]+`)
+
+	var exportErr error
+	replace := func(text *string) {
+		*text = pattern.ReplaceAllStringFunc(*text, func(match string) string {
+			if exportErr != nil {
+				return match
+			}
+
+			objectName := strings.TrimPrefix(match, bucketUrl+"/")
+			// keep the whole object key, attachments of different folders may share a file name
+			file := "assets" + path.Clean("/"+objectName)
+			if _, ok := bundle.Assets[file]; ok {
+				return file
+			}
+
+			data, contentType, err := r.minioService.GetObject(context.Background(), *r.conf.MinioS3BucketName, objectName)
+			if err != nil {
+				exportErr = fmt.Errorf("failed to get asset %s: %w", objectName, err)
+				return match
+			}
+
+			bundle.Assets[file] = data
+			bundle.Manifest.Assets = append(bundle.Manifest.Assets, content.BundleAsset{
+				File:        file,
+				SourceUrl:   match,
+				ContentType: contentType,
+			})
+			return file
+		})
+	}
+
+	forEachBundleText(bundle, replace)
+
+	return exportErr
+}
+
+func (r *courseBundleService) Import(data []byte) (*payload.CourseBundleImportResult, error) {
+	bundle, err := content.ReadBundle(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// evaluations are kept out of the module markdown, attach them to their steps
+	moduleIndex := make(map[uint64]*content.ModuleDocument)
+	for i, module := range bundle.Manifest.Modules {
+		moduleIndex[module.SourceId] = bundle.Modules[i]
+	}
+	for _, evaluation := range bundle.Evaluations {
+		step := findStepDocument(moduleIndex[evaluation.ModuleSourceId], evaluation.StepTitle)
+		if step == nil {
+			return nil, fmt.Errorf("evaluation %d references unknown step: %s", evaluation.SourceId, evaluation.StepTitle)
+		}
+		step.Evaluations = append(step.Evaluations, &content.EvaluationDocument{
+			Order:       evaluation.Order,
+			Question:    evaluation.Question,
+			Type:        evaluation.Type,
+			Instruction: evaluation.Instruction,
+			Gem:         evaluation.Gem,
+		})
+	}
+
+	// upload assets and point the markdown at their stored location
+	for _, asset := range bundle.Manifest.Assets {
+		stableUrl, err := storeAttachment(r.minioService, r.conf, bundle.Assets[asset.File], asset.ContentType, path.Ext(asset.File))
+		if err != nil {
+			return nil, err
+		}
+		forEachBundleText(bundle, func(text *string) {
+			*text = strings.ReplaceAll(*text, asset.File, stableUrl)
+		})
+	}
+
+	course, modules, err := r.contentImportRepo.SaveCourseBundle(bundle.Course, bundle.Modules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save course bundle: %w", err)
+	}
+
+	result := &payload.CourseBundleImportResult{
+		CourseId:        course.Id,
+		ModuleIds:       make(map[uint64]uint64),
+		StepIds:         make(map[uint64]uint64),
+		StepEvaluateIds: make(map[uint64]uint64),
+		AssetCount:      len(bundle.Manifest.Assets),
+	}
+
+	// remap source ids to the ids of the imported records
+	for i, manifest := range bundle.Manifest.Modules {
+		result.ModuleIds[manifest.SourceId] = *modules[i].Id
+
+		moduleId := strconv.FormatUint(*modules[i].Id, 10)
+		steps, err := r.stepRepo.FindStepsByModuleID(&moduleId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find steps of module %d: %w", *modules[i].Id, err)
+		}
+
+		stepIds := make(map[string]*uint64)
+		for _, step := range steps {
+			stepIds[*step.Title] = step.Id
+		}
+		for _, step := range manifest.Steps {
+			if stepId, ok := stepIds[step.Title]; ok {
+				result.StepIds[step.SourceId] = *stepId
+			}
+		}
+
+		for _, evaluation := range bundle.Evaluations {
+			if evaluation.ModuleSourceId != manifest.SourceId {
+				continue
+			}
+			stepEvals, err := r.stepEvalRepo.GetStepEvalByStepId(stepIds[evaluation.StepTitle])
+			if err != nil {
+				return nil, fmt.Errorf("failed to find evaluations of step %s: %w", evaluation.StepTitle, err)
+			}
+			for _, stepEval := range stepEvals {
+				if *stepEval.Order == evaluation.Order {
+					result.StepEvaluateIds[evaluation.SourceId] = *stepEval.Id
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// forEachBundleText calls apply on every markdown text of the bundle that may reference assets.
+func forEachBundleText(bundle *content.Bundle, apply func(text *string)) {
+	for _, courseContent := range bundle.Course.Contents {
+		apply(&courseContent.Text)
+	}
+	for _, module := range bundle.Modules {
+		apply(&module.ImageUrl)
+		apply(&module.Description)
+		for _, step := range module.Steps {
+			for _, section := range []*string{&step.Description, &step.Content, &step.Outcome, &step.Check, &step.Error} {
+				apply(section)
+			}
+		}
+	}
+}
+
+func findStepDocument(module *content.ModuleDocument, title string) *content.StepDocument {
+	if module == nil {
+		return nil
+	}
+	for _, step := range module.Steps {
+		if step.Title == title {
+			return step
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"backend/internals/config"
+	"backend/internals/content"
+	"backend/internals/db/models"
+	"backend/internals/utils"
+	mockRepositories "backend/mocks/repositories"
+	mockUtilServices "backend/mocks/utils"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type CourseBundleServiceTestSuite struct {
+	suite.Suite
+}
+
+// mockCourseBundle exports a course with one text and one module content, whose single
+// step references an asset of our bucket, and returns the bundle zip.
+func (suite *CourseBundleServiceTestSuite) mockCourseBundle() []byte {
+	mockCoursePageRepo := new(mockRepositories.CoursePageRepo)
+	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockStepRepo := new(mockRepositories.StepRepository)
+	mockStepEvalRepo := new(mockRepositories.StepEvaluateRepository)
+	mockMinioService := new(mockUtilServices.MinioService)
+
+	assetUrl := fmt.Sprintf("%s/%s/attachments/abc.png", *config.Env.MinioS3Endpoint, *config.Env.MinioS3BucketName)
+	otherAssetUrl := fmt.Sprintf("%s/%s/imports/abc.png", *config.Env.MinioS3Endpoint, *config.Env.MinioS3BucketName)
+
+	mockCoursePageRepo.EXPECT().FindCoursePageInfoByCoursePageID("7").Return(&models.Course{
+		Id:    utils.Ptr(uint64(7)),
+		Name:  utils.Ptr("IoT 101"),
+		Field: &models.FieldType{Name: utils.Ptr("Hardware")},
+	}, nil)
+	mockCoursePageRepo.EXPECT().FindCoursePageContentByCoursePageID("7").Return([]models.CourseContent{
+		{Type: utils.Ptr("text"), Text: utils.Ptr("Welcome\n")},
+		{Type: utils.Ptr("module"), ModuleId: utils.Ptr(uint64(3))},
+	}, nil)
+	mockModuleRepo.EXPECT().GetModuleById(utils.Ptr(uint64(3))).Return(&models.Module{
+		Id:          utils.Ptr(uint64(3)),
+		Title:       utils.Ptr("Wi-Fi basics"),
+		ImageUrl:    utils.Ptr("https://example.com/banner.png"),
+		Description: utils.Ptr("Connect your board"),
+	}, nil)
+	mockStepRepo.EXPECT().FindStepsByModuleID(utils.Ptr("3")).Return([]*models.Step{
+		{
+			Id:          utils.Ptr(uint64(11)),
+			Title:       utils.Ptr("Connect"),
+			Description: utils.Ptr("Join the network\n"),
+			Content:     utils.Ptr(fmt.Sprintf("![](%s)\n", assetUrl)),
+			Outcome:     utils.Ptr(fmt.Sprintf("![](%s)\n", otherAssetUrl)),
+			Check:       utils.Ptr("Ping works\n"),
+			Error:       utils.Ptr("No IP\n"),
+		},
+	}, nil)
+	mockStepEvalRepo.EXPECT().GetStepEvalByStepId(utils.Ptr(uint64(11))).Return([]*models.StepEvaluate{
+		{Id: utils.Ptr(uint64(22)), Order: utils.Ptr(2), Question: utils.Ptr("Upload photo"), Type: utils.Ptr("image"), Gem: utils.Ptr(5)},
+		{Id: utils.Ptr(uint64(21)), Order: utils.Ptr(1), Question: utils.Ptr("Done?"), Type: utils.Ptr("check"), Gem: utils.Ptr(1)},
+	}, nil)
+	mockMinioService.EXPECT().GetObject(mock.Anything, *config.Env.MinioS3BucketName, "attachments/abc.png").Return([]byte("png"), "image/png", nil)
+	mockMinioService.EXPECT().GetObject(mock.Anything, *config.Env.MinioS3BucketName, "imports/abc.png").Return([]byte("other png"), "image/png", nil)
+
+	underTest := NewCourseBundleService(mockCoursePageRepo, mockModuleRepo, mockStepRepo, mockStepEvalRepo, nil, mockMinioService, config.Env)
+
+	bundle, err := underTest.Export(7)
+	suite.Require().Nil(err)
+
+	return bundle
+}
+
+func (suite *CourseBundleServiceTestSuite) TestExportWhenSuccess() {
+	is := assert.New(suite.T())
+
+	bundle, err := content.ReadBundle(suite.mockCourseBundle())
+
+	is.Nil(err)
+	is.Equal("IoT 101", bundle.Course.Name)
+	is.Equal("Hardware", bundle.Course.FieldName)
+	is.Len(bundle.Course.Contents, 2)
+	is.Equal("Wi-Fi basics", bundle.Course.Contents[1].ModuleTitle)
+	is.Len(bundle.Modules, 1)
+	is.Equal("Connect", bundle.Modules[0].Steps[0].Title)
+	is.Contains(bundle.Modules[0].Steps[0].Content, "![](assets/attachments/abc.png)")
+	is.Contains(bundle.Modules[0].Steps[0].Outcome, "![](assets/imports/abc.png)")
+	is.Empty(bundle.Modules[0].Steps[0].Evaluations)
+	is.Len(bundle.Evaluations, 2)
+	is.Equal(1, bundle.Evaluations[0].Order)
+	is.Equal([]byte("png"), bundle.Assets["assets/attachments/abc.png"])
+	is.Equal([]byte("other png"), bundle.Assets["assets/imports/abc.png"])
+}
+
+func (suite *CourseBundleServiceTestSuite) TestImportWhenSuccess() {
+	is := assert.New(suite.T())
+
+	data := suite.mockCourseBundle()
+
+	mockStepRepo := new(mockRepositories.StepRepository)
+	mockStepEvalRepo := new(mockRepositories.StepEvaluateRepository)
+	mockContentImportRepo := new(mockRepositories.ContentImportRepository)
+	mockMinioService := new(mockUtilServices.MinioService)
+
+	var savedCourse *content.CourseDocument
+	var savedModules []*content.ModuleDocument
+	mockMinioService.EXPECT().ObjectExists(mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+	mockMinioService.EXPECT().PutObjectBytes(mock.Anything, mock.Anything, mock.Anything, []byte("png"), "image/png").Return(nil)
+	mockMinioService.EXPECT().PutObjectBytes(mock.Anything, mock.Anything, mock.Anything, []byte("other png"), "image/png").Return(nil)
+	mockContentImportRepo.EXPECT().SaveCourseBundle(mock.Anything, mock.Anything).Run(func(course *content.CourseDocument, modules []*content.ModuleDocument) {
+		savedCourse = course
+		savedModules = modules
+	}).Return(&models.Course{Id: utils.Ptr(uint64(70))}, []*models.Module{{Id: utils.Ptr(uint64(30))}}, nil)
+	mockStepRepo.EXPECT().FindStepsByModuleID(utils.Ptr("30")).Return([]*models.Step{
+		{Id: utils.Ptr(uint64(110)), Title: utils.Ptr("Connect")},
+	}, nil)
+	mockStepEvalRepo.EXPECT().GetStepEvalByStepId(utils.Ptr(uint64(110))).Return([]*models.StepEvaluate{
+		{Id: utils.Ptr(uint64(210)), Order: utils.Ptr(1)},
+		{Id: utils.Ptr(uint64(220)), Order: utils.Ptr(2)},
+	}, nil)
+
+	underTest := NewCourseBundleService(nil, nil, mockStepRepo, mockStepEvalRepo, mockContentImportRepo, mockMinioService, config.Env)
+
+	result, err := underTest.Import(data)
+
+	is.Nil(err)
+	is.Equal("IoT 101", savedCourse.Name)
+	is.Equal("Welcome\n", savedCourse.Contents[0].Text)
+	is.Len(savedModules, 1)
+	is.Len(savedModules[0].Steps[0].Evaluations, 2)
+	is.Equal(5, savedModules[0].Steps[0].Evaluations[1].Gem)
+	is.NotContains(savedModules[0].Steps[0].Content, "assets/")
+	is.NotContains(savedModules[0].Steps[0].Outcome, "assets/")
+	is.Contains(savedModules[0].Steps[0].Content, *config.Env.MinioS3BucketName+"/attachments/")
+	is.Equal(uint64(70), *result.CourseId)
+	is.Equal(uint64(30), result.ModuleIds[3])
+	is.Equal(uint64(110), result.StepIds[11])
+	is.Equal(uint64(210), result.StepEvaluateIds[21])
+	is.Equal(uint64(220), result.StepEvaluateIds[22])
+	is.Equal(2, result.AssetCount)
+}
+
+func (suite *CourseBundleServiceTestSuite) TestImportWhenArchiveInvalid() {
+	is := assert.New(suite.T())
+
+	mockContentImportRepo := new(mockRepositories.ContentImportRepository)
+
+	underTest := NewCourseBundleService(nil, nil, nil, nil, mockContentImportRepo, nil, config.Env)
+
+	result, err := underTest.Import([]byte("not a zip"))
+
+	is.Nil(result)
+	is.NotNil(err)
+	mockContentImportRepo.AssertNotCalled(suite.T(), "SaveCourseBundle", mock.Anything, mock.Anything)
+}
+
+func TestCourseBundleService(t *testing.T) {
+	suite.Run(t, new(CourseBundleServiceTestSuite))
+}
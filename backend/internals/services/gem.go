@@ -0,0 +1,9 @@
+package services
+
+import "backend/internals/entities/payload"
+
+type GemService interface {
+	GetUserEvalGems(userId uint64, userEvalIds []uint64) (int64, error)
+	GetLedger(userId uint64) (*payload.GemLedger, error)
+	Adjust(adminId uint64, body *payload.GemAdjustment) (*payload.GemEntryInfo, error)
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/repositories"
+	"backend/internals/utils"
+	"errors"
+	"fmt"
+)
+
+const gemLedgerLimit = 100
+
+var ErrGemBalanceInsufficient = errors.New("gem balance would become negative")
+
+type gemService struct {
+	gemRepo repositories.GemRepository
+}
+
+func NewGemService(gemRepo repositories.GemRepository) GemService {
+	return &gemService{
+		gemRepo: gemRepo,
+	}
+}
+
+func (r *gemService) GetUserEvalGems(userId uint64, userEvalIds []uint64) (int64, error) {
+	return r.gemRepo.SumBySourceIds(userId, payload.GemSourceUserEvaluate, userEvalIds)
+}
+
+func (r *gemService) GetLedger(userId uint64) (*payload.GemLedger, error) {
+	balance, err := r.gemRepo.SumByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := r.gemRepo.FindEntriesByUserId(userId, gemLedgerLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	ledger := &payload.GemLedger{
+		Balance: balance,
+		Entries: make([]*payload.GemEntryInfo, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		ledger.Entries = append(ledger.Entries, gemEntryInfo(entry))
+	}
+
+	return ledger, nil
+}
+
+// Adjust corrects the balance of a user by hand, never below zero. Repeating an adjustment with
+// the same key returns the entry written the first time.
+func (r *gemService) Adjust(adminId uint64, body *payload.GemAdjustment) (*payload.GemEntryInfo, error) {
+	key := fmt.Sprintf("%s:%s", payload.GemSourceManual, *body.IdempotencyKey)
+
+	existing, err := r.gemRepo.FindEntryByIdempotencyKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return gemEntryInfo(existing), nil
+	}
+
+	entry := &models.GemEntry{
+		UserId:         body.UserId,
+		Kind:           utils.Ptr("adjust"),
+		Amount:         body.Amount,
+		SourceType:     utils.Ptr(payload.GemSourceManual),
+		IdempotencyKey: &key,
+		Reason:         body.Reason,
+		CreatedBy:      &adminId,
+	}
+	created, err := r.gemRepo.AppendAdjustment(entry)
+	if errors.Is(err, repositories.ErrGemBalanceNegative) {
+		return nil, ErrGemBalanceInsufficient
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		// written by a concurrent request with the same key
+		existing, err := r.gemRepo.FindEntryByIdempotencyKey(key)
+		if err != nil {
+			return nil, err
+		}
+		return gemEntryInfo(existing), nil
+	}
+
+	return gemEntryInfo(entry), nil
+}
+
+func gemEntryInfo(entry *models.GemEntry) *payload.GemEntryInfo {
+	info := &payload.GemEntryInfo{
+		Id:         entry.Id,
+		Kind:       entry.Kind,
+		Amount:     entry.Amount,
+		SourceType: entry.SourceType,
+		SourceId:   entry.SourceId,
+		CourseId:   entry.CourseId,
+		Reason:     entry.Reason,
+		CreatedAt:  entry.CreatedAt,
+	}
+	if entry.Course != nil {
+		info.CourseName = entry.Course.Name
+	}
+
+	return info
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/repositories"
+	"backend/internals/utils"
+	mockRepositories "backend/mocks/repositories"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type GemServiceTestSuite struct {
+	suite.Suite
+}
+
+func (suite *GemServiceTestSuite) TestGetLedgerWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockGemRepo := new(mockRepositories.GemRepository)
+
+	mockGemRepo.EXPECT().SumByUserId(uint64(9)).Return(5, nil)
+	mockGemRepo.EXPECT().FindEntriesByUserId(uint64(9), gemLedgerLimit).Return([]*models.GemEntry{
+		{Id: utils.Ptr(uint64(2)), Kind: utils.Ptr("adjust"), Amount: utils.Ptr(int64(2)), SourceType: utils.Ptr("manual")},
+		{Id: utils.Ptr(uint64(1)), Kind: utils.Ptr("award"), Amount: utils.Ptr(int64(3)), SourceType: utils.Ptr("user_evaluate"),
+			CourseId: utils.Ptr(uint64(7)), Course: &models.Course{Name: utils.Ptr("IoT 101")}},
+	}, nil)
+
+	underTest := NewGemService(mockGemRepo)
+
+	ledger, err := underTest.GetLedger(9)
+
+	is.Nil(err)
+	is.Equal(int64(5), ledger.Balance)
+	is.Len(ledger.Entries, 2)
+	is.Nil(ledger.Entries[0].CourseName)
+	is.Equal("IoT 101", *ledger.Entries[1].CourseName)
+}
+
+func (suite *GemServiceTestSuite) TestAdjustWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockGemRepo := new(mockRepositories.GemRepository)
+
+	var appended *models.GemEntry
+	mockGemRepo.EXPECT().FindEntryByIdempotencyKey("manual:refund-42").Return(nil, nil)
+	mockGemRepo.EXPECT().AppendAdjustment(mock.Anything).Run(func(entry *models.GemEntry) {
+		appended = entry
+	}).Return(true, nil)
+
+	underTest := NewGemService(mockGemRepo)
+
+	entry, err := underTest.Adjust(1, &payload.GemAdjustment{
+		UserId:         utils.Ptr(uint64(9)),
+		Amount:         utils.Ptr(int64(-5)),
+		Reason:         utils.Ptr("awarded twice"),
+		IdempotencyKey: utils.Ptr("refund-42"),
+	})
+
+	is.Nil(err)
+	is.Equal(int64(-5), *entry.Amount)
+	is.Equal("adjust", *appended.Kind)
+	is.Equal("manual", *appended.SourceType)
+	is.Equal(uint64(1), *appended.CreatedBy)
+}
+
+func (suite *GemServiceTestSuite) TestAdjustWhenBalanceWouldBeNegative() {
+	is := assert.New(suite.T())
+
+	mockGemRepo := new(mockRepositories.GemRepository)
+
+	mockGemRepo.EXPECT().FindEntryByIdempotencyKey("manual:refund-42").Return(nil, nil)
+	mockGemRepo.EXPECT().AppendAdjustment(mock.Anything).Return(false, repositories.ErrGemBalanceNegative)
+
+	underTest := NewGemService(mockGemRepo)
+
+	entry, err := underTest.Adjust(1, &payload.GemAdjustment{
+		UserId:         utils.Ptr(uint64(9)),
+		Amount:         utils.Ptr(int64(-5)),
+		Reason:         utils.Ptr("awarded twice"),
+		IdempotencyKey: utils.Ptr("refund-42"),
+	})
+
+	is.Nil(entry)
+	is.ErrorIs(err, ErrGemBalanceInsufficient)
+}
+
+func (suite *GemServiceTestSuite) TestAdjustWhenRepeated() {
+	is := assert.New(suite.T())
+
+	mockGemRepo := new(mockRepositories.GemRepository)
+
+	mockGemRepo.EXPECT().FindEntryByIdempotencyKey("manual:refund-42").Return(&models.GemEntry{
+		Id:     utils.Ptr(uint64(4)),
+		Amount: utils.Ptr(int64(-5)),
+	}, nil)
+
+	underTest := NewGemService(mockGemRepo)
+
+	entry, err := underTest.Adjust(1, &payload.GemAdjustment{
+		UserId:         utils.Ptr(uint64(9)),
+		Amount:         utils.Ptr(int64(-5)),
+		Reason:         utils.Ptr("awarded twice"),
+		IdempotencyKey: utils.Ptr("refund-42"),
+	})
+
+	is.Nil(err)
+	is.Equal(uint64(4), *entry.Id)
+	mockGemRepo.AssertNotCalled(suite.T(), "AppendAdjustment", mock.Anything)
+}
+
+func TestGemService(t *testing.T) {
+	suite.Run(t, new(GemServiceTestSuite))
+}
@@ -0,0 +1,16 @@
+package services
+
+import (
+	"backend/internals/entities/payload"
+	"mime/multipart"
+)
+
+type HelpRequestService interface {
+	RequestHelp(userId uint64, body *payload.CreateHelpRequest, photo *multipart.FileHeader) (*payload.HelpRequestInfo, error)
+	GetMyHelpRequests(userId uint64) ([]*payload.HelpRequestInfo, error)
+	Cancel(helpRequestId uint64, userId uint64) error
+	GetQueue(query *payload.HelpQueueQuery) ([]*payload.HelpRequestInfo, error)
+	Claim(helpRequestId uint64, userId uint64) (*payload.HelpRequestInfo, error)
+	Resolve(helpRequestId uint64, userId uint64) (*payload.HelpRequestInfo, error)
+	GetStepHelpStats(courseId *uint64) ([]*payload.StepHelpStats, error)
+}
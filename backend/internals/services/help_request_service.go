@@ -0,0 +1,275 @@
+package services
+
+import (
+	"backend/internals/config"
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/repositories"
+	"backend/internals/utils"
+	utilServices "backend/internals/utils/services"
+	"context"
+	"fmt"
+	"mime/multipart"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+type helpRequestService struct {
+	helpRequestRepo       repositories.HelpRequestRepository
+	workshopSessionRepo   repositories.WorkshopSessionRepository
+	sessionAttendanceRepo repositories.SessionAttendanceRepository
+	cohortRepo            repositories.CohortRepository
+	stepRepo              repositories.StepRepository
+	courseContentRepo     repositories.CourseContentRepository
+	minioService          utilServices.MinioService
+	conf                  *config.Config
+}
+
+func NewHelpRequestService(
+	helpRequestRepo repositories.HelpRequestRepository,
+	workshopSessionRepo repositories.WorkshopSessionRepository,
+	sessionAttendanceRepo repositories.SessionAttendanceRepository,
+	cohortRepo repositories.CohortRepository,
+	stepRepo repositories.StepRepository,
+	courseContentRepo repositories.CourseContentRepository,
+	minioService utilServices.MinioService,
+	conf *config.Config) HelpRequestService {
+	return &helpRequestService{
+		helpRequestRepo:       helpRequestRepo,
+		workshopSessionRepo:   workshopSessionRepo,
+		sessionAttendanceRepo: sessionAttendanceRepo,
+		cohortRepo:            cohortRepo,
+		stepRepo:              stepRepo,
+		courseContentRepo:     courseContentRepo,
+		minioService:          minioService,
+		conf:                  conf,
+	}
+}
+
+func (r *helpRequestService) RequestHelp(userId uint64, body *payload.CreateHelpRequest, photo *multipart.FileHeader) (*payload.HelpRequestInfo, error) {
+	courseId, err := r.findQueueCourse(userId, body.SessionId, body.CohortId)
+	if err != nil {
+		return nil, err
+	}
+
+	step, err := r.stepRepo.GetStepById(body.StepId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find step %d: %w", *body.StepId, err)
+	}
+	courseContent, err := r.courseContentRepo.FindCourseContentByCourseIdAndModuleId(courseId, step.ModuleId)
+	if err != nil {
+		return nil, err
+	}
+	if courseContent == nil {
+		return nil, fmt.Errorf("step %d is not part of course %d", *body.StepId, *courseId)
+	}
+
+	// a learner keeps a single raised hand per queue
+	open, err := r.helpRequestRepo.FindOpenHelpRequest(userId, body.SessionId, body.CohortId)
+	if err != nil {
+		return nil, err
+	}
+	if open != nil {
+		return nil, fmt.Errorf("user %d already has open help request %d in this queue", userId, *open.Id)
+	}
+
+	helpRequest := &models.HelpRequest{
+		SessionId: body.SessionId,
+		CohortId:  body.CohortId,
+		StepId:    step.Id,
+		Step:      step,
+		UserId:    &userId,
+		Note:      body.Note,
+		Status:    utils.Ptr("waiting"),
+	}
+
+	if photo != nil {
+		if !strings.HasPrefix(photo.Header.Get("Content-Type"), "image/") {
+			return nil, fmt.Errorf("photo must be an image")
+		}
+
+		file, err := photo.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		objectName := fmt.Sprintf("help_step%d_userId%d_%s%s", *step.Id, userId, time.Now().UTC().Format(time.RFC3339), path.Ext(photo.Filename))
+		if err := r.minioService.PutObject(context.Background(), *r.conf.MinioS3BucketName, objectName, file, photo); err != nil {
+			return nil, fmt.Errorf("failed to upload photo: %w", err)
+		}
+		helpRequest.Photo = &objectName
+	}
+
+	if err := r.helpRequestRepo.CreateHelpRequest(helpRequest); err != nil {
+		return nil, err
+	}
+
+	return r.helpRequestInfoWithPosition(helpRequest)
+}
+
+func (r *helpRequestService) GetMyHelpRequests(userId uint64) ([]*payload.HelpRequestInfo, error) {
+	helpRequests, err := r.helpRequestRepo.FindOpenHelpRequestsByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]*payload.HelpRequestInfo, 0, len(helpRequests))
+	for _, helpRequest := range helpRequests {
+		info, err := r.helpRequestInfoWithPosition(helpRequest)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+func (r *helpRequestService) Cancel(helpRequestId uint64, userId uint64) error {
+	if err := r.helpRequestRepo.Cancel(helpRequestId, userId); err != nil {
+		return fmt.Errorf("failed to cancel help request %d: %w", helpRequestId, err)
+	}
+
+	return nil
+}
+
+func (r *helpRequestService) GetQueue(query *payload.HelpQueueQuery) ([]*payload.HelpRequestInfo, error) {
+	helpRequests, err := r.helpRequestRepo.FindQueue(query.SessionId, query.CohortId)
+	if err != nil {
+		return nil, err
+	}
+
+	// the queue is in order, claimed requests keep their place but no longer wait
+	infos := make([]*payload.HelpRequestInfo, 0, len(helpRequests))
+	position := int64(0)
+	for _, helpRequest := range helpRequests {
+		info := r.helpRequestInfo(helpRequest)
+		if *helpRequest.Status == "waiting" {
+			position++
+			info.Position = utils.Ptr(position)
+		}
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+func (r *helpRequestService) Claim(helpRequestId uint64, userId uint64) (*payload.HelpRequestInfo, error) {
+	if err := r.helpRequestRepo.Claim(helpRequestId, userId); err != nil {
+		return nil, fmt.Errorf("failed to claim help request %d: %w", helpRequestId, err)
+	}
+
+	return r.findHelpRequestInfo(helpRequestId)
+}
+
+func (r *helpRequestService) Resolve(helpRequestId uint64, userId uint64) (*payload.HelpRequestInfo, error) {
+	if err := r.helpRequestRepo.Resolve(helpRequestId, userId); err != nil {
+		return nil, fmt.Errorf("failed to resolve help request %d: %w", helpRequestId, err)
+	}
+
+	return r.findHelpRequestInfo(helpRequestId)
+}
+
+func (r *helpRequestService) GetStepHelpStats(courseId *uint64) ([]*payload.StepHelpStats, error) {
+	return r.helpRequestRepo.FindStepHelpStats(courseId)
+}
+
+// findQueueCourse returns the course of the session or cohort queue, which the user must have
+// checked in to or be a member of.
+func (r *helpRequestService) findQueueCourse(userId uint64, sessionId *uint64, cohortId *uint64) (*uint64, error) {
+	if sessionId != nil {
+		session, err := r.workshopSessionRepo.FindSessionById(*sessionId)
+		if err != nil {
+			return nil, err
+		}
+		if session == nil {
+			return nil, fmt.Errorf("session %d not found", *sessionId)
+		}
+
+		attendance, err := r.sessionAttendanceRepo.FindAttendance(*sessionId, userId)
+		if err != nil {
+			return nil, err
+		}
+		if attendance == nil {
+			return nil, fmt.Errorf("user %d has not checked in to session %d", userId, *sessionId)
+		}
+
+		return session.CourseId, nil
+	}
+
+	cohort, err := r.cohortRepo.FindCohortById(*cohortId)
+	if err != nil {
+		return nil, err
+	}
+	if cohort == nil {
+		return nil, fmt.Errorf("cohort %d not found", *cohortId)
+	}
+
+	member, err := r.cohortRepo.FindMember(*cohortId, userId)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, fmt.Errorf("user %d is not a member of cohort %d", userId, *cohortId)
+	}
+
+	return cohort.CourseId, nil
+}
+
+func (r *helpRequestService) findHelpRequestInfo(helpRequestId uint64) (*payload.HelpRequestInfo, error) {
+	helpRequest, err := r.helpRequestRepo.FindHelpRequestById(helpRequestId)
+	if err != nil {
+		return nil, err
+	}
+	if helpRequest == nil {
+		return nil, fmt.Errorf("help request %d not found", helpRequestId)
+	}
+
+	return r.helpRequestInfo(helpRequest), nil
+}
+
+func (r *helpRequestService) helpRequestInfoWithPosition(helpRequest *models.HelpRequest) (*payload.HelpRequestInfo, error) {
+	info := r.helpRequestInfo(helpRequest)
+	if *helpRequest.Status != "waiting" {
+		return info, nil
+	}
+
+	ahead, err := r.helpRequestRepo.CountWaitingBefore(helpRequest)
+	if err != nil {
+		return nil, err
+	}
+	info.Position = utils.Ptr(ahead + 1)
+
+	return info, nil
+}
+
+func (r *helpRequestService) helpRequestInfo(helpRequest *models.HelpRequest) *payload.HelpRequestInfo {
+	info := &payload.HelpRequestInfo{
+		HelpRequestId: helpRequest.Id,
+		SessionId:     helpRequest.SessionId,
+		CohortId:      helpRequest.CohortId,
+		StepId:        helpRequest.StepId,
+		Note:          helpRequest.Note,
+		Status:        helpRequest.Status,
+		ClaimedBy:     helpRequest.ClaimedBy,
+		CreatedAt:     helpRequest.CreatedAt,
+		ClaimedAt:     helpRequest.ClaimedAt,
+		ResolvedAt:    helpRequest.ResolvedAt,
+	}
+	if helpRequest.Step != nil {
+		info.StepTitle = helpRequest.Step.Title
+	}
+	if helpRequest.User != nil {
+		info.Learner = userInfo(helpRequest.User)
+	}
+	if helpRequest.Photo != nil {
+		if photoUrl, err := url.JoinPath(*r.conf.MinioS3Endpoint, *r.conf.MinioS3BucketName, *helpRequest.Photo); err == nil {
+			info.PhotoUrl = &photoUrl
+		}
+	}
+
+	return info
+}
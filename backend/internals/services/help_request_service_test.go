@@ -0,0 +1,225 @@
+package services
+
+import (
+	"backend/internals/config"
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/repositories"
+	"backend/internals/utils"
+	mockRepositories "backend/mocks/repositories"
+	mockUtilServices "backend/mocks/utils"
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+type HelpRequestServiceTestSuite struct {
+	suite.Suite
+	mockHelpRequestRepo       *mockRepositories.HelpRequestRepository
+	mockWorkshopSessionRepo   *mockRepositories.WorkshopSessionRepository
+	mockSessionAttendanceRepo *mockRepositories.SessionAttendanceRepository
+	mockCohortRepo            *mockRepositories.CohortRepository
+	mockStepRepo              *mockRepositories.StepRepository
+	mockCourseContentRepo     *mockRepositories.CourseContentRepository
+	mockMinioService          *mockUtilServices.MinioService
+	underTest                 HelpRequestService
+}
+
+func (suite *HelpRequestServiceTestSuite) SetupTest() {
+	suite.mockHelpRequestRepo = new(mockRepositories.HelpRequestRepository)
+	suite.mockWorkshopSessionRepo = new(mockRepositories.WorkshopSessionRepository)
+	suite.mockSessionAttendanceRepo = new(mockRepositories.SessionAttendanceRepository)
+	suite.mockCohortRepo = new(mockRepositories.CohortRepository)
+	suite.mockStepRepo = new(mockRepositories.StepRepository)
+	suite.mockCourseContentRepo = new(mockRepositories.CourseContentRepository)
+	suite.mockMinioService = new(mockUtilServices.MinioService)
+	suite.underTest = NewHelpRequestService(
+		suite.mockHelpRequestRepo,
+		suite.mockWorkshopSessionRepo,
+		suite.mockSessionAttendanceRepo,
+		suite.mockCohortRepo,
+		suite.mockStepRepo,
+		suite.mockCourseContentRepo,
+		suite.mockMinioService,
+		&config.Config{
+			MinioS3Endpoint:   utils.Ptr("https://s3.example.com"),
+			MinioS3BucketName: utils.Ptr("bookmark"),
+		},
+	)
+}
+
+// mockCheckedInSession expects user 9 checked in to session 4 of course 7, in which step 12 is.
+func (suite *HelpRequestServiceTestSuite) mockCheckedInSession() {
+	suite.mockWorkshopSessionRepo.EXPECT().FindSessionById(uint64(4)).Return(mockWorkshopSession(utils.TimeNow()), nil)
+	suite.mockSessionAttendanceRepo.EXPECT().FindAttendance(uint64(4), uint64(9)).Return(&models.SessionAttendance{}, nil)
+	suite.mockStepRepo.EXPECT().GetStepById(utils.Ptr(uint64(12))).Return(&models.Step{
+		Id:       utils.Ptr(uint64(12)),
+		ModuleId: utils.Ptr(uint64(2)),
+		Title:    utils.Ptr("Flash the firmware"),
+	}, nil)
+	suite.mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndModuleId(utils.Ptr(uint64(7)), utils.Ptr(uint64(2))).Return(&models.CourseContent{}, nil)
+}
+
+func mockHelpPhoto(contentType string) *multipart.FileHeader {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", `form-data; name="photo"; filename="board.jpg"`)
+	header.Set("Content-Type", contentType)
+	part, _ := writer.CreatePart(header)
+	part.Write([]byte("photo"))
+	writer.Close()
+
+	form, _ := multipart.NewReader(body, writer.Boundary()).ReadForm(1 << 20)
+	return form.File["photo"][0]
+}
+
+func (suite *HelpRequestServiceTestSuite) TestRequestHelpWhenSuccess() {
+	is := assert.New(suite.T())
+
+	suite.mockCheckedInSession()
+	suite.mockHelpRequestRepo.EXPECT().FindOpenHelpRequest(uint64(9), utils.Ptr(uint64(4)), (*uint64)(nil)).Return(nil, nil)
+	suite.mockMinioService.EXPECT().PutObject(mock.Anything, "bookmark", mock.MatchedBy(func(objectName string) bool {
+		return strings.HasPrefix(objectName, "help_step12_userId9_")
+	}), mock.Anything, mock.Anything).Return(nil)
+	suite.mockHelpRequestRepo.EXPECT().CreateHelpRequest(mock.Anything).RunAndReturn(func(helpRequest *models.HelpRequest) error {
+		helpRequest.Id = utils.Ptr(uint64(30))
+		helpRequest.CreatedAt = utils.TimeNowPtr()
+		return nil
+	})
+	suite.mockHelpRequestRepo.EXPECT().CountWaitingBefore(mock.Anything).Return(2, nil)
+
+	helpRequest, err := suite.underTest.RequestHelp(9, &payload.CreateHelpRequest{
+		SessionId: utils.Ptr(uint64(4)),
+		StepId:    utils.Ptr(uint64(12)),
+		Note:      utils.Ptr("the board does not show up"),
+	}, mockHelpPhoto("image/jpeg"))
+
+	is.Nil(err)
+	is.Equal(uint64(30), *helpRequest.HelpRequestId)
+	is.Equal("waiting", *helpRequest.Status)
+	is.Equal(int64(3), *helpRequest.Position)
+	is.Equal("Flash the firmware", *helpRequest.StepTitle)
+	is.Contains(*helpRequest.PhotoUrl, "https://s3.example.com/bookmark/help_step12_userId9_")
+}
+
+func (suite *HelpRequestServiceTestSuite) TestRequestHelpWhenPhotoIsNotImage() {
+	is := assert.New(suite.T())
+
+	suite.mockCheckedInSession()
+	suite.mockHelpRequestRepo.EXPECT().FindOpenHelpRequest(uint64(9), utils.Ptr(uint64(4)), (*uint64)(nil)).Return(nil, nil)
+
+	helpRequest, err := suite.underTest.RequestHelp(9, &payload.CreateHelpRequest{
+		SessionId: utils.Ptr(uint64(4)),
+		StepId:    utils.Ptr(uint64(12)),
+	}, mockHelpPhoto("application/pdf"))
+
+	is.Nil(helpRequest)
+	is.Equal("photo must be an image", err.Error())
+	suite.mockHelpRequestRepo.AssertNotCalled(suite.T(), "CreateHelpRequest", mock.Anything)
+}
+
+func (suite *HelpRequestServiceTestSuite) TestRequestHelpWhenAlreadyOpen() {
+	is := assert.New(suite.T())
+
+	suite.mockCheckedInSession()
+	suite.mockHelpRequestRepo.EXPECT().FindOpenHelpRequest(uint64(9), utils.Ptr(uint64(4)), (*uint64)(nil)).Return(&models.HelpRequest{Id: utils.Ptr(uint64(28))}, nil)
+
+	helpRequest, err := suite.underTest.RequestHelp(9, &payload.CreateHelpRequest{
+		SessionId: utils.Ptr(uint64(4)),
+		StepId:    utils.Ptr(uint64(12)),
+	}, nil)
+
+	is.Nil(helpRequest)
+	is.Equal("user 9 already has open help request 28 in this queue", err.Error())
+}
+
+func (suite *HelpRequestServiceTestSuite) TestRequestHelpWhenNotCheckedIn() {
+	is := assert.New(suite.T())
+
+	suite.mockWorkshopSessionRepo.EXPECT().FindSessionById(uint64(4)).Return(mockWorkshopSession(utils.TimeNow()), nil)
+	suite.mockSessionAttendanceRepo.EXPECT().FindAttendance(uint64(4), uint64(9)).Return(nil, nil)
+
+	helpRequest, err := suite.underTest.RequestHelp(9, &payload.CreateHelpRequest{
+		SessionId: utils.Ptr(uint64(4)),
+		StepId:    utils.Ptr(uint64(12)),
+	}, nil)
+
+	is.Nil(helpRequest)
+	is.Equal("user 9 has not checked in to session 4", err.Error())
+}
+
+func (suite *HelpRequestServiceTestSuite) TestRequestHelpWhenNotCohortMember() {
+	is := assert.New(suite.T())
+
+	suite.mockCohortRepo.EXPECT().FindCohortById(uint64(3)).Return(mockCohort(), nil)
+	suite.mockCohortRepo.EXPECT().FindMember(uint64(3), uint64(9)).Return(nil, nil)
+
+	helpRequest, err := suite.underTest.RequestHelp(9, &payload.CreateHelpRequest{
+		CohortId: utils.Ptr(uint64(3)),
+		StepId:   utils.Ptr(uint64(12)),
+	}, nil)
+
+	is.Nil(helpRequest)
+	is.Equal("user 9 is not a member of cohort 3", err.Error())
+}
+
+func (suite *HelpRequestServiceTestSuite) TestGetQueueWhenSuccess() {
+	is := assert.New(suite.T())
+
+	suite.mockHelpRequestRepo.EXPECT().FindQueue((*uint64)(nil), utils.Ptr(uint64(3))).Return([]*models.HelpRequest{
+		{Id: utils.Ptr(uint64(1)), Status: utils.Ptr("claimed"), ClaimedBy: utils.Ptr(uint64(2))},
+		{Id: utils.Ptr(uint64(2)), Status: utils.Ptr("waiting"), User: &models.User{Id: utils.Ptr(uint64(9)), Firstname: utils.Ptr("Somchai")}},
+		{Id: utils.Ptr(uint64(3)), Status: utils.Ptr("waiting")},
+	}, nil)
+
+	queue, err := suite.underTest.GetQueue(&payload.HelpQueueQuery{CohortId: utils.Ptr(uint64(3))})
+
+	is.Nil(err)
+	is.Len(queue, 3)
+	is.Nil(queue[0].Position)
+	is.Equal(int64(1), *queue[1].Position)
+	is.Equal("Somchai", *queue[1].Learner.FirstName)
+	is.Equal(int64(2), *queue[2].Position)
+}
+
+func (suite *HelpRequestServiceTestSuite) TestClaimWhenAlreadyClaimed() {
+	is := assert.New(suite.T())
+
+	suite.mockHelpRequestRepo.EXPECT().Claim(uint64(30), uint64(2)).Return(repositories.ErrHelpRequestNotOpen)
+
+	helpRequest, err := suite.underTest.Claim(30, 2)
+
+	is.Nil(helpRequest)
+	is.ErrorIs(err, repositories.ErrHelpRequestNotOpen)
+	suite.mockHelpRequestRepo.AssertNotCalled(suite.T(), "FindHelpRequestById", mock.Anything)
+}
+
+func (suite *HelpRequestServiceTestSuite) TestResolveWhenSuccess() {
+	is := assert.New(suite.T())
+
+	claimedAt := utils.TimeNow()
+	suite.mockHelpRequestRepo.EXPECT().Resolve(uint64(30), uint64(2)).Return(nil)
+	suite.mockHelpRequestRepo.EXPECT().FindHelpRequestById(uint64(30)).Return(&models.HelpRequest{
+		Id:         utils.Ptr(uint64(30)),
+		Status:     utils.Ptr("resolved"),
+		ClaimedBy:  utils.Ptr(uint64(2)),
+		ClaimedAt:  &claimedAt,
+		ResolvedAt: utils.TimeNowPtr(),
+	}, nil)
+
+	helpRequest, err := suite.underTest.Resolve(30, 2)
+
+	is.Nil(err)
+	is.Equal("resolved", *helpRequest.Status)
+	is.Nil(helpRequest.Position)
+}
+
+func TestHelpRequestService(t *testing.T) {
+	suite.Run(t, new(HelpRequestServiceTestSuite))
+}
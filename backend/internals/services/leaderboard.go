@@ -0,0 +1,10 @@
+package services
+
+import "backend/internals/entities/payload"
+
+type LeaderboardService interface {
+	GetLeaderboard(query *payload.LeaderboardQuery) (*payload.Leaderboard, error)
+	GetStanding(userId uint64, query *payload.LeaderboardQuery) (*payload.Leaderboard, error)
+	GetPreference(userId uint64) (*payload.LeaderboardPreference, error)
+	UpdatePreference(userId uint64, body *payload.LeaderboardPreference) (*payload.LeaderboardPreference, error)
+}
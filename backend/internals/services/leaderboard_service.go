@@ -0,0 +1,131 @@
+package services
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/repositories"
+	"backend/internals/utils"
+	"time"
+)
+
+const (
+	leaderboardDefaultLimit      = 20
+	leaderboardDefaultNeighbours = 2
+)
+
+type leaderboardService struct {
+	leaderboardRepo repositories.LeaderboardRepository
+}
+
+func NewLeaderboardService(leaderboardRepo repositories.LeaderboardRepository) LeaderboardService {
+	return &leaderboardService{
+		leaderboardRepo: leaderboardRepo,
+	}
+}
+
+func (r *leaderboardService) GetLeaderboard(query *payload.LeaderboardQuery) (*payload.Leaderboard, error) {
+	key := leaderboardKey(query, utils.TimeNow())
+
+	limit := leaderboardDefaultLimit
+	if query.Limit != nil {
+		limit = *query.Limit
+	}
+
+	rows, err := r.leaderboardRepo.FindTop(key, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return leaderboard(key, rows), nil
+}
+
+// GetStanding returns the rank of the user among the users around them. Users who opted out are
+// not ranked, so they get no standing.
+func (r *leaderboardService) GetStanding(userId uint64, query *payload.LeaderboardQuery) (*payload.Leaderboard, error) {
+	key := leaderboardKey(query, utils.TimeNow())
+
+	preference, err := r.leaderboardRepo.FindPreference(userId)
+	if err != nil {
+		return nil, err
+	}
+	if preference != nil && *preference.Hidden {
+		return leaderboard(key, nil), nil
+	}
+
+	neighbours := leaderboardDefaultNeighbours
+	if query.Neighbours != nil {
+		neighbours = *query.Neighbours
+	}
+
+	rows, err := r.leaderboardRepo.FindNeighbours(key, userId, neighbours)
+	if err != nil {
+		return nil, err
+	}
+
+	result := leaderboard(key, rows)
+	for _, row := range rows {
+		if row.UserId == userId {
+			result.Me = row
+		}
+	}
+
+	return result, nil
+}
+
+func (r *leaderboardService) GetPreference(userId uint64) (*payload.LeaderboardPreference, error) {
+	preference, err := r.leaderboardRepo.FindPreference(userId)
+	if err != nil {
+		return nil, err
+	}
+	if preference == nil {
+		return &payload.LeaderboardPreference{Hidden: utils.Ptr(false)}, nil
+	}
+
+	return &payload.LeaderboardPreference{Hidden: preference.Hidden}, nil
+}
+
+func (r *leaderboardService) UpdatePreference(userId uint64, body *payload.LeaderboardPreference) (*payload.LeaderboardPreference, error) {
+	preference := &models.LeaderboardPreference{
+		UserId: &userId,
+		Hidden: body.Hidden,
+	}
+	if err := r.leaderboardRepo.SavePreference(preference); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// leaderboardKey resolves the leaderboard of the query for the period containing now.
+func leaderboardKey(query *payload.LeaderboardQuery, now time.Time) *payload.LeaderboardKey {
+	key := &payload.LeaderboardKey{
+		Scope:   *query.Scope,
+		ScopeId: utils.Val(query.ScopeId),
+		Period:  *query.Period,
+	}
+
+	switch key.Period {
+	case payload.LeaderboardPeriodMonth:
+		key.PeriodStart = utils.TimeMonthStart(now)
+	case payload.LeaderboardPeriodWeek:
+		key.PeriodStart = utils.TimeWeekStart(now)
+	default:
+		key.PeriodStart = time.Unix(0, 0).In(utils.BangkokTime)
+	}
+
+	return key
+}
+
+func leaderboard(key *payload.LeaderboardKey, rows []*payload.LeaderboardRow) *payload.Leaderboard {
+	if rows == nil {
+		rows = []*payload.LeaderboardRow{}
+	}
+
+	return &payload.Leaderboard{
+		Scope:       key.Scope,
+		ScopeId:     key.ScopeId,
+		Period:      key.Period,
+		PeriodStart: key.PeriodStart,
+		Rows:        rows,
+	}
+}
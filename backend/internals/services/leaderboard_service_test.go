@@ -0,0 +1,121 @@
+package services
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/utils"
+	mockRepositories "backend/mocks/repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type LeaderboardServiceTestSuite struct {
+	suite.Suite
+}
+
+func (suite *LeaderboardServiceTestSuite) TestGetLeaderboardWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockLeaderboardRepo := new(mockRepositories.LeaderboardRepository)
+
+	mockLeaderboardRepo.EXPECT().FindTop(mock.MatchedBy(func(key *payload.LeaderboardKey) bool {
+		return key.Scope == "course" && key.ScopeId == 7 && key.Period == "week" && key.PeriodStart.Equal(utils.TimeWeekStart(utils.TimeNow()))
+	}), leaderboardDefaultLimit).Return([]*payload.LeaderboardRow{
+		{Rank: 1, UserId: 9, Gems: 12},
+		{Rank: 2, UserId: 4, Gems: 8},
+	}, nil)
+
+	underTest := NewLeaderboardService(mockLeaderboardRepo)
+
+	leaderboard, err := underTest.GetLeaderboard(&payload.LeaderboardQuery{
+		Scope:   utils.Ptr("course"),
+		ScopeId: utils.Ptr(uint64(7)),
+		Period:  utils.Ptr("week"),
+	})
+
+	is.Nil(err)
+	is.Len(leaderboard.Rows, 2)
+	is.Equal(uint64(9), leaderboard.Rows[0].UserId)
+	is.Equal(time.Monday, leaderboard.PeriodStart.Weekday())
+}
+
+func (suite *LeaderboardServiceTestSuite) TestGetStandingWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockLeaderboardRepo := new(mockRepositories.LeaderboardRepository)
+
+	mockLeaderboardRepo.EXPECT().FindPreference(uint64(4)).Return(nil, nil)
+	mockLeaderboardRepo.EXPECT().FindNeighbours(mock.MatchedBy(func(key *payload.LeaderboardKey) bool {
+		return key.Scope == "global" && key.ScopeId == 0 && key.Period == "all"
+	}), uint64(4), 1).Return([]*payload.LeaderboardRow{
+		{Rank: 1, UserId: 9, Gems: 12},
+		{Rank: 2, UserId: 4, Gems: 8},
+		{Rank: 2, UserId: 5, Gems: 8},
+	}, nil)
+
+	underTest := NewLeaderboardService(mockLeaderboardRepo)
+
+	leaderboard, err := underTest.GetStanding(4, &payload.LeaderboardQuery{
+		Scope:      utils.Ptr("global"),
+		Period:     utils.Ptr("all"),
+		Neighbours: utils.Ptr(1),
+	})
+
+	is.Nil(err)
+	is.Equal(int64(2), leaderboard.Me.Rank)
+	is.Len(leaderboard.Rows, 3)
+}
+
+func (suite *LeaderboardServiceTestSuite) TestGetStandingWhenOptedOut() {
+	is := assert.New(suite.T())
+
+	mockLeaderboardRepo := new(mockRepositories.LeaderboardRepository)
+
+	mockLeaderboardRepo.EXPECT().FindPreference(uint64(4)).Return(&models.LeaderboardPreference{Hidden: utils.Ptr(true)}, nil)
+
+	underTest := NewLeaderboardService(mockLeaderboardRepo)
+
+	leaderboard, err := underTest.GetStanding(4, &payload.LeaderboardQuery{
+		Scope:  utils.Ptr("global"),
+		Period: utils.Ptr("month"),
+	})
+
+	is.Nil(err)
+	is.Nil(leaderboard.Me)
+	is.Empty(leaderboard.Rows)
+	mockLeaderboardRepo.AssertNotCalled(suite.T(), "FindNeighbours", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *LeaderboardServiceTestSuite) TestGetPreferenceWhenNotSet() {
+	is := assert.New(suite.T())
+
+	mockLeaderboardRepo := new(mockRepositories.LeaderboardRepository)
+
+	mockLeaderboardRepo.EXPECT().FindPreference(uint64(4)).Return(nil, nil)
+
+	underTest := NewLeaderboardService(mockLeaderboardRepo)
+
+	preference, err := underTest.GetPreference(4)
+
+	is.Nil(err)
+	is.False(*preference.Hidden)
+}
+
+func (suite *LeaderboardServiceTestSuite) TestLeaderboardKeyWhenMonth() {
+	is := assert.New(suite.T())
+
+	// 20:00 UTC on the last of September is already October in Bangkok
+	now := time.Date(2026, 9, 30, 20, 0, 0, 0, time.UTC)
+
+	key := leaderboardKey(&payload.LeaderboardQuery{Scope: utils.Ptr("global"), Period: utils.Ptr("month")}, now)
+
+	is.Equal(time.Date(2026, 10, 1, 0, 0, 0, 0, utils.BangkokTime), key.PeriodStart)
+}
+
+func TestLeaderboardService(t *testing.T) {
+	suite.Run(t, new(LeaderboardServiceTestSuite))
+}
@@ -3,5 +3,5 @@ package services
 import "backend/internals/entities/payload"
 
 type ModuleStepServices interface {
-	GetModuleSteps(userID uint, moduleID string) ([]payload.ModuleStep, error)
+	GetModuleSteps(userID uint, moduleID string, courseID *uint64) ([]payload.ModuleStep, error)
 }
@@ -7,18 +7,20 @@ import (
 )
 
 type moduleStepService struct {
-	stepRepo       repositories.StepRepository
-	userEvaluateRepo repositories.UserEvaluateRepository
+	stepRepo          repositories.StepRepository
+	userEvaluateRepo  repositories.UserEvaluateRepository
+	courseContentRepo repositories.CourseContentRepository
 }
 
-func NewModuleStepService(stepRepo repositories.StepRepository, userEvaluateRepo repositories.UserEvaluateRepository) ModuleStepServices {
+func NewModuleStepService(stepRepo repositories.StepRepository, userEvaluateRepo repositories.UserEvaluateRepository, courseContentRepo repositories.CourseContentRepository) ModuleStepServices {
 	return &moduleStepService{
-		stepRepo:       stepRepo,
-		userEvaluateRepo: userEvaluateRepo,
+		stepRepo:          stepRepo,
+		userEvaluateRepo:  userEvaluateRepo,
+		courseContentRepo: courseContentRepo,
 	}
 }
 
-func (s *moduleStepService) GetModuleSteps(userID uint, moduleID string) ([]payload.ModuleStep, error) {
+func (s *moduleStepService) GetModuleSteps(userID uint, moduleID string, courseID *uint64) ([]payload.ModuleStep, error) {
 	// Fetch steps for the module
 	steps, err := s.stepRepo.FindStepsByModuleID(&moduleID)
 	if err != nil {
@@ -29,6 +31,12 @@ func (s *moduleStepService) GetModuleSteps(userID uint, moduleID string) ([]payl
 		return nil, fmt.Errorf("no steps found for module ID %s", moduleID)
 	}
 
+	// Resolve the course the module is taken in, progress is tracked per course
+	courseContent, err := resolveCourseContent(s.courseContentRepo, courseID, steps[0].ModuleId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve course of module ID %s: %w", moduleID, err)
+	}
+
 	// Prepare response
 	var stepResponses []payload.ModuleStep
 	for _, step := range steps {
@@ -44,7 +52,7 @@ func (s *moduleStepService) GetModuleSteps(userID uint, moduleID string) ([]payl
 		}
 
 		// Get all step_evaluate IDs where user has passed
-		userPassedIDs, err := s.userEvaluateRepo.FindUserPassedEvaluateIDs(userID, *step.Id)
+		userPassedIDs, err := s.userEvaluateRepo.FindUserPassedEvaluateIDs(userID, *courseContent.CourseId, *step.Id)
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch user evaluations for step ID %d: %w", *step.Id, err)
 		}
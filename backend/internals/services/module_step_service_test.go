@@ -2,8 +2,9 @@ package services_test
 
 import (
 	"backend/internals/db/models"
-	mockRepositories "backend/mocks/repositories"
 	"backend/internals/services"
+	"backend/internals/utils"
+	mockRepositories "backend/mocks/repositories"
 	"errors"
 	"github.com/stretchr/testify/suite"
 	"testing"
@@ -11,30 +12,34 @@ import (
 
 type ModuleStepServiceTestSuite struct {
 	suite.Suite
-	mockStepRepo     *mockRepositories.StepRepository
-	mockUserEvalRepo *mockRepositories.UserEvaluateRepository
-	service          services.ModuleStepServices
+	mockStepRepo          *mockRepositories.StepRepository
+	mockUserEvalRepo      *mockRepositories.UserEvaluateRepository
+	mockCourseContentRepo *mockRepositories.CourseContentRepository
+	service               services.ModuleStepServices
 }
 
 func (suite *ModuleStepServiceTestSuite) SetupTest() {
 	suite.mockStepRepo = mockRepositories.NewStepRepository(suite.T())
 	suite.mockUserEvalRepo = mockRepositories.NewUserEvaluateRepository(suite.T())
-	suite.service = services.NewModuleStepService(suite.mockStepRepo, suite.mockUserEvalRepo)
+	suite.mockCourseContentRepo = mockRepositories.NewCourseContentRepository(suite.T())
+	suite.service = services.NewModuleStepService(suite.mockStepRepo, suite.mockUserEvalRepo, suite.mockCourseContentRepo)
 }
 
 func (suite *ModuleStepServiceTestSuite) TestGetModuleStepsSuccess() {
 	moduleID := "module123"
 	steps := []*models.Step{
-		{Id: new(uint64), Title: new(string)},
+		{Id: new(uint64), Title: new(string), ModuleId: utils.Ptr(uint64(3))},
 	}
 	*steps[0].Id = 1
 	*steps[0].Title = "Step 1"
 
 	suite.mockStepRepo.EXPECT().FindStepsByModuleID(&moduleID).Return(steps, nil)
+	suite.mockCourseContentRepo.EXPECT().GetCourseIdsByModuleId(utils.Ptr(uint64(3))).Return([]uint64{5}, nil)
+	suite.mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndModuleId(utils.Ptr(uint64(5)), utils.Ptr(uint64(3))).Return(&models.CourseContent{CourseId: utils.Ptr(uint64(5))}, nil)
 	suite.mockUserEvalRepo.EXPECT().FindStepEvaluateIDsByStepID(uint64(1)).Return([]uint64{1, 2}, nil)
-	suite.mockUserEvalRepo.EXPECT().FindUserPassedEvaluateIDs(uint(1), uint64(1)).Return([]uint64{1, 2}, nil)
+	suite.mockUserEvalRepo.EXPECT().FindUserPassedEvaluateIDs(uint(1), uint64(5), uint64(1)).Return([]uint64{1, 2}, nil)
 
-	result, err := suite.service.GetModuleSteps(1, moduleID)
+	result, err := suite.service.GetModuleSteps(1, moduleID, nil)
 	suite.NoError(err)
 	suite.Len(result, 1)
 	suite.True(result[0].Check)
@@ -44,7 +49,7 @@ func (suite *ModuleStepServiceTestSuite) TestGetModuleStepsNoStepsFound() {
 	moduleID := "module123"
 	suite.mockStepRepo.EXPECT().FindStepsByModuleID(&moduleID).Return(nil, nil)
 
-	result, err := suite.service.GetModuleSteps(1, moduleID)
+	result, err := suite.service.GetModuleSteps(1, moduleID, nil)
 	suite.Error(err)
 	suite.Nil(result)
 }
@@ -52,16 +57,18 @@ func (suite *ModuleStepServiceTestSuite) TestGetModuleStepsNoStepsFound() {
 func (suite *ModuleStepServiceTestSuite) TestGetModuleStepsEvaluationMismatch() {
 	moduleID := "module123"
 	steps := []*models.Step{
-		{Id: new(uint64), Title: new(string)},
+		{Id: new(uint64), Title: new(string), ModuleId: utils.Ptr(uint64(3))},
 	}
 	*steps[0].Id = 1
 	*steps[0].Title = "Step 1"
 
 	suite.mockStepRepo.EXPECT().FindStepsByModuleID(&moduleID).Return(steps, nil)
+	suite.mockCourseContentRepo.EXPECT().GetCourseIdsByModuleId(utils.Ptr(uint64(3))).Return([]uint64{5}, nil)
+	suite.mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndModuleId(utils.Ptr(uint64(5)), utils.Ptr(uint64(3))).Return(&models.CourseContent{CourseId: utils.Ptr(uint64(5))}, nil)
 	suite.mockUserEvalRepo.EXPECT().FindStepEvaluateIDsByStepID(uint64(1)).Return([]uint64{1, 2}, nil)
-	suite.mockUserEvalRepo.EXPECT().FindUserPassedEvaluateIDs(uint(1), uint64(1)).Return([]uint64{1}, nil)
+	suite.mockUserEvalRepo.EXPECT().FindUserPassedEvaluateIDs(uint(1), uint64(5), uint64(1)).Return([]uint64{1}, nil)
 
-	result, err := suite.service.GetModuleSteps(1, moduleID)
+	result, err := suite.service.GetModuleSteps(1, moduleID, nil)
 	suite.NoError(err)
 	suite.Len(result, 1)
 	suite.False(result[0].Check)
@@ -71,7 +78,21 @@ func (suite *ModuleStepServiceTestSuite) TestGetModuleStepsRepoError() {
 	moduleID := "module123"
 	suite.mockStepRepo.EXPECT().FindStepsByModuleID(&moduleID).Return(nil, errors.New("repository error"))
 
-	result, err := suite.service.GetModuleSteps(1, moduleID)
+	result, err := suite.service.GetModuleSteps(1, moduleID, nil)
+	suite.Error(err)
+	suite.Nil(result)
+}
+
+func (suite *ModuleStepServiceTestSuite) TestGetModuleStepsSharedModuleWithoutCourse() {
+	moduleID := "module123"
+	steps := []*models.Step{
+		{Id: utils.Ptr(uint64(1)), Title: utils.Ptr("Step 1"), ModuleId: utils.Ptr(uint64(3))},
+	}
+
+	suite.mockStepRepo.EXPECT().FindStepsByModuleID(&moduleID).Return(steps, nil)
+	suite.mockCourseContentRepo.EXPECT().GetCourseIdsByModuleId(utils.Ptr(uint64(3))).Return([]uint64{5, 6}, nil)
+
+	result, err := suite.service.GetModuleSteps(1, moduleID, nil)
 	suite.Error(err)
 	suite.Nil(result)
 }
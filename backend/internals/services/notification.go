@@ -0,0 +1,16 @@
+package services
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"context"
+)
+
+type NotificationService interface {
+	SubmissionGraded(userEval *models.UserEvaluate) (*models.Notification, error)
+	CommentReply(parent *models.StepComment, reply *models.StepComment, replier *models.User) (*models.Notification, error)
+	Wake()
+	GetPreference(userId uint64) (*payload.NotificationPreference, error)
+	UpdatePreference(userId uint64, body *payload.NotificationPreference) (*payload.NotificationPreference, error)
+	Run(ctx context.Context)
+}
@@ -0,0 +1,297 @@
+package services
+
+import (
+	"backend/internals/config"
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/repositories"
+	"backend/internals/utils"
+	utilServices "backend/internals/utils/services"
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	htmlTemplate "html/template"
+	"strconv"
+	"strings"
+	textTemplate "text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+)
+
+//go:embed templates/email/*.tmpl
+var emailTemplates embed.FS
+
+const (
+	notificationSweepInterval = 30 * time.Second
+	notificationBatchSize     = 50
+	notificationMaxAttempts   = 5
+	notificationRetryDelay    = time.Minute // doubled after every failed attempt
+	notificationDefaultLocale = "th"
+	notificationQuoteLength   = 500 // runes of a reply quoted in the email
+)
+
+type notificationService struct {
+	notificationRepo repositories.NotificationRepository
+	userRepo         repositories.UserRepository
+	stepEvalRepo     repositories.StepEvaluateRepository
+	mailer           utilServices.Mailer
+	conf             *config.Config
+	textTemplates    map[string]*textTemplate.Template
+	htmlTemplates    map[string]*htmlTemplate.Template
+	wake             chan struct{}
+}
+
+func NewNotificationService(
+	notificationRepo repositories.NotificationRepository,
+	userRepo repositories.UserRepository,
+	stepEvalRepo repositories.StepEvaluateRepository,
+	mailer utilServices.Mailer,
+	conf *config.Config) NotificationService {
+	service := &notificationService{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		stepEvalRepo:     stepEvalRepo,
+		mailer:           mailer,
+		conf:             conf,
+		textTemplates:    make(map[string]*textTemplate.Template),
+		htmlTemplates:    make(map[string]*htmlTemplate.Template),
+		wake:             make(chan struct{}, 1),
+	}
+
+	// every template defines the subject and text blocks, executed as text, and the html block
+	for _, kind := range []string{payload.NotificationSubmissionGraded, payload.NotificationCommentReply} {
+		for _, locale := range []string{"th", "en"} {
+			name := fmt.Sprintf("templates/email/%s.%s.tmpl", kind, locale)
+			service.textTemplates[kind+"."+locale] = textTemplate.Must(textTemplate.ParseFS(emailTemplates, name))
+			service.htmlTemplates[kind+"."+locale] = htmlTemplate.Must(htmlTemplate.ParseFS(emailTemplates, name))
+		}
+	}
+
+	return service
+}
+
+// SubmissionGraded renders the email telling the learner about the grade, to be stored in the
+// outbox along with the grade. It returns nil when the learner turned these emails off.
+func (r *notificationService) SubmissionGraded(userEval *models.UserEvaluate) (*models.Notification, error) {
+	stepEval, err := r.stepEvalRepo.GetStepEvalById(userEval.StepEvaluateId)
+	if err != nil {
+		return nil, err
+	}
+
+	url := r.frontendUrl("")
+	if userEval.CourseId != nil {
+		url = r.frontendUrl(fmt.Sprintf("/course/%d", *userEval.CourseId))
+	}
+
+	return r.compose(*userEval.UserId, payload.NotificationSubmissionGraded, func(user *models.User) any {
+		return &payload.SubmissionGradedEmail{
+			FirstName: *user.Firstname,
+			Question:  *stepEval.Question,
+			Pass:      utils.Val(userEval.Pass),
+			Comment:   utils.Val(userEval.Comment),
+			Url:       url,
+		}
+	})
+}
+
+// CommentReply renders the email telling the author of the comment about the reply, to be stored
+// in the outbox along with the reply. It returns nil when the author turned these emails off.
+func (r *notificationService) CommentReply(parent *models.StepComment, reply *models.StepComment, replier *models.User) (*models.Notification, error) {
+	quote := *reply.Content
+	if utf8.RuneCountInString(quote) > notificationQuoteLength {
+		quote = string([]rune(quote)[:notificationQuoteLength]) + "…"
+	}
+
+	return r.compose(*parent.UserId, payload.NotificationCommentReply, func(user *models.User) any {
+		return &payload.CommentReplyEmail{
+			FirstName:   *user.Firstname,
+			ReplierName: strings.TrimSpace(*replier.Firstname + " " + *replier.Lastname),
+			Reply:       quote,
+			Url:         r.frontendUrl(""),
+		}
+	})
+}
+
+func (r *notificationService) GetPreference(userId uint64) (*payload.NotificationPreference, error) {
+	preference, err := r.findPreference(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &payload.NotificationPreference{
+		Locale:           preference.Locale,
+		SubmissionGraded: preference.SubmissionGraded,
+		CommentReply:     preference.CommentReply,
+	}, nil
+}
+
+func (r *notificationService) UpdatePreference(userId uint64, body *payload.NotificationPreference) (*payload.NotificationPreference, error) {
+	preference := &models.NotificationPreference{
+		UserId:           &userId,
+		Locale:           body.Locale,
+		SubmissionGraded: body.SubmissionGraded,
+		CommentReply:     body.CommentReply,
+	}
+	if err := r.notificationRepo.SavePreference(preference); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// Run sends the due emails of the outbox until the context is cancelled. The outbox is swept
+// periodically for retries and for emails enqueued while the worker was down.
+func (r *notificationService) Run(ctx context.Context) {
+	if r.conf.SmtpHost == nil || *r.conf.SmtpHost == "" {
+		logrus.Warn("[NOTIFICATION] SMTP is not configured, emails stay in the outbox")
+		return
+	}
+
+	ticker := time.NewTicker(notificationSweepInterval)
+	defer ticker.Stop()
+
+	r.sweep()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.sweep()
+		case <-r.wake:
+			r.sweep()
+		}
+	}
+}
+
+// Wake tells the worker that notifications were stored in the outbox, so they are sent without
+// waiting for the next sweep.
+func (r *notificationService) Wake() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+		// a sweep is already coming
+	}
+}
+
+// compose renders the email in the locale of the user as a pending notification of the outbox,
+// unless the user turned this kind of email off.
+func (r *notificationService) compose(userId uint64, kind string, data func(user *models.User) any) (*models.Notification, error) {
+	preference, err := r.findPreference(userId)
+	if err != nil {
+		return nil, err
+	}
+	if (kind == payload.NotificationSubmissionGraded && !*preference.SubmissionGraded) ||
+		(kind == payload.NotificationCommentReply && !*preference.CommentReply) {
+		return nil, nil
+	}
+
+	user, err := r.userRepo.FindUserByID(utils.Ptr(strconv.FormatUint(userId, 10)))
+	if err != nil {
+		return nil, err
+	}
+
+	email, err := r.render(kind, *preference.Locale, data(user))
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Notification{
+		UserId:        &userId,
+		Kind:          &kind,
+		Recipient:     user.Email,
+		Subject:       &email.Subject,
+		TextBody:      &email.Text,
+		HtmlBody:      &email.Html,
+		Status:        utils.Ptr("pending"),
+		Attempts:      utils.Ptr(0),
+		NextAttemptAt: utils.TimeNowPtr(),
+	}, nil
+}
+
+func (r *notificationService) render(kind string, locale string, data any) (*payload.Email, error) {
+	var subject, text, html bytes.Buffer
+	textTemplates := r.textTemplates[kind+"."+locale]
+	if err := textTemplates.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, fmt.Errorf("failed to render %s email: %w", kind, err)
+	}
+	if err := textTemplates.ExecuteTemplate(&text, "text", data); err != nil {
+		return nil, fmt.Errorf("failed to render %s email: %w", kind, err)
+	}
+	if err := r.htmlTemplates[kind+"."+locale].ExecuteTemplate(&html, "html", data); err != nil {
+		return nil, fmt.Errorf("failed to render %s email: %w", kind, err)
+	}
+
+	return &payload.Email{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(text.String()),
+		Html:    strings.TrimSpace(html.String()),
+	}, nil
+}
+
+func (r *notificationService) sweep() {
+	notifications, err := r.notificationRepo.FindDueNotifications(utils.TimeNow(), notificationBatchSize)
+	if err != nil {
+		logrus.Errorf("[NOTIFICATION] Unable to fetch due notifications: %v", err)
+		return
+	}
+
+	for _, notification := range notifications {
+		r.deliver(notification)
+	}
+}
+
+func (r *notificationService) deliver(notification *models.Notification) {
+	err := r.mailer.Send(&payload.Email{
+		To:      *notification.Recipient,
+		Subject: *notification.Subject,
+		Text:    *notification.TextBody,
+		Html:    *notification.HtmlBody,
+	})
+
+	notification.Attempts = utils.Ptr(*notification.Attempts + 1)
+	if err == nil {
+		notification.Status = utils.Ptr("sent")
+		notification.SentAt = utils.TimeNowPtr()
+		notification.LastError = nil
+	} else if *notification.Attempts >= notificationMaxAttempts {
+		logrus.Errorf("[NOTIFICATION] Giving up on notification %d after %d attempts: %v", *notification.Id, *notification.Attempts, err)
+		notification.Status = utils.Ptr("failed")
+		notification.LastError = utils.Ptr(err.Error())
+	} else {
+		delay := notificationRetryDelay << (*notification.Attempts - 1)
+		notification.NextAttemptAt = utils.Ptr(utils.TimeNow().Add(delay))
+		notification.LastError = utils.Ptr(err.Error())
+	}
+
+	if err := r.notificationRepo.UpdateNotification(notification); err != nil {
+		logrus.Errorf("[NOTIFICATION] Unable to update notification %d: %v", *notification.Id, err)
+	}
+}
+
+func (r *notificationService) findPreference(userId uint64) (*models.NotificationPreference, error) {
+	preference, err := r.notificationRepo.FindPreference(userId)
+	if err != nil {
+		return nil, err
+	}
+	if preference == nil {
+		preference = &models.NotificationPreference{
+			UserId:           &userId,
+			Locale:           utils.Ptr(notificationDefaultLocale),
+			SubmissionGraded: utils.Ptr(true),
+			CommentReply:     utils.Ptr(true),
+		}
+	}
+
+	return preference, nil
+}
+
+func (r *notificationService) frontendUrl(path string) string {
+	if r.conf.FrontendScheme == nil || r.conf.FrontendUrl == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%s://%s%s", *r.conf.FrontendScheme, *r.conf.FrontendUrl, path)
+}
@@ -0,0 +1,268 @@
+package services
+
+import (
+	"backend/internals/config"
+	"backend/internals/db/models"
+	"backend/internals/utils"
+	utilServices "backend/internals/utils/services"
+	mockRepositories "backend/mocks/repositories"
+	mockUtilServices "backend/mocks/utils"
+	"bufio"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type NotificationServiceTestSuite struct {
+	suite.Suite
+}
+
+func mockNotificationConfig() *config.Config {
+	return &config.Config{
+		FrontendScheme: utils.Ptr("http"),
+		FrontendUrl:    utils.Ptr("localhost:5173"),
+		SmtpFrom:       utils.Ptr("IoT Learning Platform <no-reply@example.com>"),
+	}
+}
+
+func mockNotificationUser() *models.User {
+	return &models.User{
+		Id:        utils.Ptr(uint64(9)),
+		Firstname: utils.Ptr("Somchai"),
+		Lastname:  utils.Ptr("Jaidee"),
+		Email:     utils.Ptr("somchai@example.com"),
+	}
+}
+
+// startSmtpStandIn runs a local SMTP server accepting every message, in place of the real server.
+func startSmtpStandIn(t *testing.T) (int, <-chan []byte) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	messages := make(chan []byte, 1)
+	go func() {
+		for {
+			connection, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conversation := textproto.NewConn(connection)
+			_ = conversation.PrintfLine("220 localhost ESMTP")
+			for {
+				line, err := conversation.ReadLine()
+				if err != nil {
+					break
+				}
+				command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+				if command == "EHLO" || command == "HELO" {
+					_ = conversation.PrintfLine("250-localhost\r\n250 8BITMIME")
+				} else if command == "DATA" {
+					_ = conversation.PrintfLine("354 end with <CRLF>.<CRLF>")
+					message, _ := conversation.ReadDotBytes()
+					messages <- message
+					_ = conversation.PrintfLine("250 queued")
+				} else if command == "QUIT" {
+					_ = conversation.PrintfLine("221 bye")
+					break
+				} else {
+					_ = conversation.PrintfLine("250 ok")
+				}
+			}
+			_ = conversation.Close()
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port, messages
+}
+
+func (suite *NotificationServiceTestSuite) TestSubmissionGradedWhenTurnedOff() {
+	is := assert.New(suite.T())
+
+	mockNotificationRepo := new(mockRepositories.NotificationRepository)
+	mockStepEvalRepo := new(mockRepositories.StepEvaluateRepository)
+
+	mockStepEvalRepo.EXPECT().GetStepEvalById(utils.Ptr(uint64(12))).Return(&models.StepEvaluate{Question: utils.Ptr("Wire the LED")}, nil)
+	mockNotificationRepo.EXPECT().FindPreference(uint64(9)).Return(&models.NotificationPreference{
+		Locale:           utils.Ptr("en"),
+		SubmissionGraded: utils.Ptr(false),
+		CommentReply:     utils.Ptr(true),
+	}, nil)
+
+	underTest := NewNotificationService(mockNotificationRepo, nil, mockStepEvalRepo, nil, mockNotificationConfig())
+
+	notification, err := underTest.SubmissionGraded(&models.UserEvaluate{
+		UserId:         utils.Ptr(uint64(9)),
+		StepEvaluateId: utils.Ptr(uint64(12)),
+		Pass:           utils.Ptr(true),
+	})
+
+	is.Nil(err)
+	is.Nil(notification)
+}
+
+func (suite *NotificationServiceTestSuite) TestSubmissionGradedWhenDefaultPreference() {
+	is := assert.New(suite.T())
+
+	mockNotificationRepo := new(mockRepositories.NotificationRepository)
+	mockUserRepo := new(mockRepositories.UserRepository)
+	mockStepEvalRepo := new(mockRepositories.StepEvaluateRepository)
+
+	mockStepEvalRepo.EXPECT().GetStepEvalById(utils.Ptr(uint64(12))).Return(&models.StepEvaluate{Question: utils.Ptr("Wire the LED")}, nil)
+	mockNotificationRepo.EXPECT().FindPreference(uint64(9)).Return(nil, nil)
+	mockUserRepo.EXPECT().FindUserByID(utils.Ptr("9")).Return(mockNotificationUser(), nil)
+
+	underTest := NewNotificationService(mockNotificationRepo, mockUserRepo, mockStepEvalRepo, nil, mockNotificationConfig())
+
+	created, err := underTest.SubmissionGraded(&models.UserEvaluate{
+		UserId:         utils.Ptr(uint64(9)),
+		StepEvaluateId: utils.Ptr(uint64(12)),
+		CourseId:       utils.Ptr(uint64(7)),
+		Pass:           utils.Ptr(false),
+		Comment:        utils.Ptr("<b>check the resistor</b>"),
+	})
+
+	// emails are in Thai unless the user chose English
+	is.Nil(err)
+	is.Equal("somchai@example.com", *created.Recipient)
+	is.Equal("pending", *created.Status)
+	is.Equal("งานของคุณได้รับการตรวจแล้ว: ยังไม่ผ่าน", *created.Subject)
+	is.Contains(*created.TextBody, "สวัสดีคุณ Somchai")
+	is.Contains(*created.TextBody, "<b>check the resistor</b>")
+	is.Contains(*created.TextBody, "http://localhost:5173/course/7")
+	is.Contains(*created.HtmlBody, "&lt;b&gt;check the resistor&lt;/b&gt;")
+	is.Contains(*created.HtmlBody, `<a href="http://localhost:5173/course/7">`)
+}
+
+func (suite *NotificationServiceTestSuite) TestCommentReplyWhenEnglish() {
+	is := assert.New(suite.T())
+
+	mockNotificationRepo := new(mockRepositories.NotificationRepository)
+	mockUserRepo := new(mockRepositories.UserRepository)
+
+	mockNotificationRepo.EXPECT().FindPreference(uint64(9)).Return(&models.NotificationPreference{
+		Locale:           utils.Ptr("en"),
+		SubmissionGraded: utils.Ptr(true),
+		CommentReply:     utils.Ptr(true),
+	}, nil)
+	mockUserRepo.EXPECT().FindUserByID(utils.Ptr("9")).Return(mockNotificationUser(), nil)
+
+	underTest := NewNotificationService(mockNotificationRepo, mockUserRepo, nil, nil, mockNotificationConfig())
+
+	created, err := underTest.CommentReply(
+		&models.StepComment{Id: utils.Ptr(uint64(8)), UserId: utils.Ptr(uint64(9))},
+		&models.StepComment{Content: utils.Ptr(strings.Repeat("ก", 600))},
+		&models.User{Firstname: utils.Ptr("Malee"), Lastname: utils.Ptr("Sukjai")},
+	)
+
+	is.Nil(err)
+	is.Equal("Malee Sukjai replied to your comment", *created.Subject)
+	is.Contains(*created.TextBody, strings.Repeat("ก", 500)+"…")
+	is.NotContains(*created.TextBody, strings.Repeat("ก", 501))
+}
+
+func (suite *NotificationServiceTestSuite) TestDeliverWhenSendFailed() {
+	is := assert.New(suite.T())
+
+	mockNotificationRepo := new(mockRepositories.NotificationRepository)
+	mockMailer := new(mockUtilServices.Mailer)
+
+	mockMailer.EXPECT().Send(mock.Anything).Return(fmt.Errorf("connection refused"))
+	mockNotificationRepo.EXPECT().UpdateNotification(mock.Anything).Return(nil)
+
+	underTest := NewNotificationService(mockNotificationRepo, nil, nil, mockMailer, mockNotificationConfig()).(*notificationService)
+
+	notification := &models.Notification{
+		Id:        utils.Ptr(uint64(1)),
+		Recipient: utils.Ptr("somchai@example.com"),
+		Subject:   utils.Ptr("subject"),
+		TextBody:  utils.Ptr("text"),
+		HtmlBody:  utils.Ptr("html"),
+		Status:    utils.Ptr("pending"),
+		Attempts:  utils.Ptr(1),
+	}
+	underTest.deliver(notification)
+
+	// retried later, waiting longer after every failure
+	is.Equal("pending", *notification.Status)
+	is.Equal(2, *notification.Attempts)
+	is.Equal("connection refused", *notification.LastError)
+	is.WithinDuration(utils.TimeNow().Add(2*time.Minute), *notification.NextAttemptAt, 5*time.Second)
+
+	notification.Attempts = utils.Ptr(notificationMaxAttempts - 1)
+	underTest.deliver(notification)
+
+	is.Equal("failed", *notification.Status)
+	is.Equal(notificationMaxAttempts, *notification.Attempts)
+}
+
+func (suite *NotificationServiceTestSuite) TestDeliverWhenSentThroughSmtp() {
+	is := assert.New(suite.T())
+
+	port, messages := startSmtpStandIn(suite.T())
+	conf := mockNotificationConfig()
+	conf.SmtpHost = utils.Ptr("127.0.0.1")
+	conf.SmtpPort = &port
+
+	mockNotificationRepo := new(mockRepositories.NotificationRepository)
+	mockNotificationRepo.EXPECT().UpdateNotification(mock.Anything).Return(nil)
+
+	underTest := NewNotificationService(mockNotificationRepo, nil, nil, utilServices.NewSmtpMailer(conf), conf).(*notificationService)
+
+	notification := &models.Notification{
+		Id:        utils.Ptr(uint64(1)),
+		Recipient: utils.Ptr("somchai@example.com"),
+		Subject:   utils.Ptr("งานของคุณได้รับการตรวจแล้ว: ผ่าน"),
+		TextBody:  utils.Ptr("สวัสดีคุณ Somchai"),
+		HtmlBody:  utils.Ptr("<p>สวัสดีคุณ Somchai</p>"),
+		Status:    utils.Ptr("pending"),
+		Attempts:  utils.Ptr(0),
+	}
+	underTest.deliver(notification)
+
+	is.Equal("sent", *notification.Status)
+	is.NotNil(notification.SentAt)
+
+	message, err := mail.ReadMessage(bufio.NewReader(strings.NewReader(string(<-messages))))
+	is.Nil(err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+	is.Nil(err)
+	is.Equal("งานของคุณได้รับการตรวจแล้ว: ผ่าน", subject)
+	is.Equal("somchai@example.com", message.Header.Get("To"))
+	is.Equal(`"IoT Learning Platform" <no-reply@example.com>`, message.Header.Get("From"))
+
+	_, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	is.Nil(err)
+	parts := multipart.NewReader(message.Body, params["boundary"])
+	var bodies []string
+	for {
+		part, err := parts.NextRawPart()
+		if err != nil {
+			break
+		}
+		body, _ := io.ReadAll(quotedprintable.NewReader(part))
+		bodies = append(bodies, part.Header.Get("Content-Type")+" "+string(body))
+	}
+	is.Equal([]string{
+		"text/plain; charset=utf-8 สวัสดีคุณ Somchai",
+		"text/html; charset=utf-8 <p>สวัสดีคุณ Somchai</p>",
+	}, bodies)
+}
+
+func TestNotificationService(t *testing.T) {
+	suite.Run(t, new(NotificationServiceTestSuite))
+}
@@ -0,0 +1,13 @@
+package services
+
+import (
+	"backend/internals/entities/payload"
+	"context"
+)
+
+type OutlineSyncService interface {
+	VerifySignature(signature string, body []byte) error
+	EnqueueEvent(event *payload.OutlineWebhookEvent) (*payload.ImportJobInfo, error)
+	GetImportJobs(limit int) ([]*payload.ImportJobInfo, error)
+	Run(ctx context.Context)
+}
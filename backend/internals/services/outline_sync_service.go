@@ -0,0 +1,279 @@
+package services
+
+import (
+	"backend/internals/config"
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/repositories"
+	"backend/internals/utils"
+	utilServices "backend/internals/utils/services"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	outlineSyncSweepInterval = time.Minute
+	outlineSignatureMaxAge   = 5 * time.Minute // older signed requests are rejected as replays
+)
+
+type outlineSyncService struct {
+	importJobRepo        repositories.ImportJobRepository
+	contentImportService ContentImportService
+	outlineService       utilServices.OutlineService
+	conf                 *config.Config
+	jobs                 chan uint64
+	parentIds            map[string]string
+	parentIdsMutex       sync.Mutex
+}
+
+func NewOutlineSyncService(
+	importJobRepo repositories.ImportJobRepository,
+	contentImportService ContentImportService,
+	outlineService utilServices.OutlineService,
+	conf *config.Config) OutlineSyncService {
+	return &outlineSyncService{
+		importJobRepo:        importJobRepo,
+		contentImportService: contentImportService,
+		outlineService:       outlineService,
+		conf:                 conf,
+		jobs:                 make(chan uint64, 100),
+	}
+}
+
+// VerifySignature checks the Outline-Signature header, formatted as "t=<timestamp>,s=<hmac>"
+// where hmac is the hex encoded HMAC-SHA256 of "<timestamp>.<body>" keyed with the webhook secret.
+// The timestamp, in seconds or milliseconds, must be within outlineSignatureMaxAge of now.
+func (r *outlineSyncService) VerifySignature(signature string, body []byte) error {
+	if r.conf.OutlineWebhookSecret == nil || *r.conf.OutlineWebhookSecret == "" {
+		return fmt.Errorf("outline webhook secret is not configured")
+	}
+
+	var timestamp, digest string
+	for _, part := range strings.Split(signature, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "s":
+			digest = value
+		}
+	}
+	if timestamp == "" || digest == "" {
+		return fmt.Errorf("malformed outline signature")
+	}
+
+	mac := hmac.New(sha256.New, []byte(*r.conf.OutlineWebhookSecret))
+	mac.Write([]byte(timestamp + "." + string(body)))
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(expected), []byte(digest)) {
+		return fmt.Errorf("invalid outline signature")
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("malformed outline signature timestamp")
+	}
+	signedAt := time.Unix(unix, 0)
+	if unix > 1e12 {
+		signedAt = time.UnixMilli(unix)
+	}
+	if age := utils.TimeNow().Sub(signedAt); age > outlineSignatureMaxAge || age < -outlineSignatureMaxAge {
+		return fmt.Errorf("outline signature expired")
+	}
+
+	return nil
+}
+
+func (r *outlineSyncService) EnqueueEvent(event *payload.OutlineWebhookEvent) (*payload.ImportJobInfo, error) {
+	if event.Event != "documents.update" && event.Event != "documents.publish" {
+		return nil, nil
+	}
+
+	document := event.Payload.Model
+	if document == nil || document.Id == "" {
+		info, err := r.outlineService.DocumentInfo(event.Payload.Id)
+		if err != nil {
+			return nil, err
+		}
+		document = info
+	}
+
+	kind, err := r.resolveKind(document.ParentDocumentId)
+	if err != nil {
+		return nil, err
+	}
+	if kind == "" {
+		return nil, nil
+	}
+
+	// an import of the same document that has not started yet will pick up this change too
+	job, err := r.importJobRepo.FindPendingImportJobByDocumentId(document.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if job == nil {
+		job = &models.ImportJob{
+			DocumentId: utils.Ptr(document.Id),
+			Kind:       utils.Ptr(kind),
+			Event:      utils.Ptr(event.Event),
+			Status:     utils.Ptr("pending"),
+			Attempts:   utils.Ptr(0),
+		}
+		if err := r.importJobRepo.CreateImportJob(job); err != nil {
+			return nil, err
+		}
+
+		r.enqueue(*job.Id)
+	}
+
+	return importJobInfo(job), nil
+}
+
+func (r *outlineSyncService) GetImportJobs(limit int) ([]*payload.ImportJobInfo, error) {
+	jobs, err := r.importJobRepo.FindRecentImportJobs(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*payload.ImportJobInfo, 0, len(jobs))
+	for _, job := range jobs {
+		result = append(result, importJobInfo(job))
+	}
+
+	return result, nil
+}
+
+// Run processes queued import jobs until the context is cancelled. Unfinished jobs are
+// swept from the database periodically so that nothing is lost across restarts.
+func (r *outlineSyncService) Run(ctx context.Context) {
+	ticker := time.NewTicker(outlineSyncSweepInterval)
+	defer ticker.Stop()
+
+	r.sweep()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.sweep()
+		case jobId := <-r.jobs:
+			r.process(jobId)
+		}
+	}
+}
+
+func (r *outlineSyncService) enqueue(jobId uint64) {
+	select {
+	case r.jobs <- jobId:
+	default:
+		// queue is full, the next sweep will pick the job up
+	}
+}
+
+func (r *outlineSyncService) sweep() {
+	jobs, err := r.importJobRepo.FindUnfinishedImportJobs()
+	if err != nil {
+		logrus.Errorf("[OUTLINE] Unable to fetch unfinished import jobs: %v", err)
+		return
+	}
+
+	for _, job := range jobs {
+		r.process(*job.Id)
+	}
+}
+
+func (r *outlineSyncService) process(jobId uint64) {
+	job, err := r.importJobRepo.FindImportJobById(jobId)
+	if err != nil {
+		logrus.Errorf("[OUTLINE] Unable to fetch import job %d: %v", jobId, err)
+		return
+	}
+	if *job.Status == "succeeded" || *job.Status == "failed" {
+		return
+	}
+
+	job.Status = utils.Ptr("running")
+	job.Attempts = utils.Ptr(*job.Attempts + 1)
+	job.StartedAt = utils.TimeNowPtr()
+	job.FinishedAt = nil
+	if err := r.importJobRepo.UpdateImportJob(job); err != nil {
+		logrus.Errorf("[OUTLINE] Unable to start import job %d: %v", jobId, err)
+		return
+	}
+
+	if *job.Kind == "course" {
+		err = r.contentImportService.ImportCourse(*job.DocumentId)
+	} else {
+		err = r.contentImportService.ImportModule(*job.DocumentId)
+	}
+
+	job.FinishedAt = utils.TimeNowPtr()
+	if err != nil {
+		logrus.Errorf("[OUTLINE] Import job %d of document %s failed: %v", jobId, *job.DocumentId, err)
+		job.Status = utils.Ptr("failed")
+		job.LastError = utils.Ptr(err.Error())
+	} else {
+		job.Status = utils.Ptr("succeeded")
+		job.LastError = nil
+	}
+
+	if err := r.importJobRepo.UpdateImportJob(job); err != nil {
+		logrus.Errorf("[OUTLINE] Unable to finish import job %d: %v", jobId, err)
+	}
+}
+
+// resolveKind maps a parent document to the importer responsible for its children.
+// Configured parent ids may be url ids, so they are resolved to document ids once.
+func (r *outlineSyncService) resolveKind(parentDocumentId *string) (string, error) {
+	if parentDocumentId == nil {
+		return "", nil
+	}
+
+	r.parentIdsMutex.Lock()
+	defer r.parentIdsMutex.Unlock()
+
+	if r.parentIds == nil {
+		parentIds := make(map[string]string)
+		for kind, configured := range map[string]*string{
+			"module": r.conf.OutlineModuleParentId,
+			"course": r.conf.OutlineCourseParentId,
+		} {
+			if configured == nil || *configured == "" {
+				continue
+			}
+			parent, err := r.outlineService.DocumentInfo(*configured)
+			if err != nil {
+				return "", fmt.Errorf("failed to resolve %s parent document: %w", kind, err)
+			}
+			parentIds[parent.Id] = kind
+		}
+		r.parentIds = parentIds
+	}
+
+	return r.parentIds[*parentDocumentId], nil
+}
+
+func importJobInfo(job *models.ImportJob) *payload.ImportJobInfo {
+	return &payload.ImportJobInfo{
+		Id:         job.Id,
+		DocumentId: job.DocumentId,
+		Kind:       job.Kind,
+		Event:      job.Event,
+		Status:     job.Status,
+		Attempts:   job.Attempts,
+		LastError:  job.LastError,
+		StartedAt:  job.StartedAt,
+		FinishedAt: job.FinishedAt,
+		CreatedAt:  job.CreatedAt,
+	}
+}
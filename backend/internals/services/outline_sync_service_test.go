@@ -0,0 +1,148 @@
+package services
+
+import (
+	"backend/internals/config"
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/utils"
+	mockRepositories "backend/mocks/repositories"
+	mockServices "backend/mocks/services"
+	mockUtilServices "backend/mocks/utils"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type OutlineSyncServiceTestSuite struct {
+	suite.Suite
+}
+
+func mockOutlineSyncConfig() *config.Config {
+	return &config.Config{
+		OutlineWebhookSecret:  utils.Ptr("webhook-secret"),
+		OutlineModuleParentId: utils.Ptr("modules"),
+		OutlineCourseParentId: utils.Ptr("courses"),
+	}
+}
+
+func mockOutlineSignature(timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte("webhook-secret"))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "." + string(body)))
+	return "t=" + strconv.FormatInt(timestamp, 10) + ",s=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (suite *OutlineSyncServiceTestSuite) TestVerifySignatureWhenValid() {
+	is := assert.New(suite.T())
+
+	body := []byte(`{"event":"documents.update"}`)
+	signature := mockOutlineSignature(utils.TimeNow().UnixMilli(), body)
+
+	underTest := NewOutlineSyncService(nil, nil, nil, mockOutlineSyncConfig())
+
+	is.Nil(underTest.VerifySignature(signature, body))
+	is.NotNil(underTest.VerifySignature(signature, []byte(`{"event":"documents.delete"}`)))
+	is.NotNil(underTest.VerifySignature("", body))
+	is.Nil(underTest.VerifySignature(mockOutlineSignature(utils.TimeNow().Unix(), body), body))
+}
+
+func (suite *OutlineSyncServiceTestSuite) TestVerifySignatureWhenStale() {
+	is := assert.New(suite.T())
+
+	body := []byte(`{"event":"documents.update"}`)
+	signature := mockOutlineSignature(utils.TimeNow().Add(-10*time.Minute).UnixMilli(), body)
+
+	underTest := NewOutlineSyncService(nil, nil, nil, mockOutlineSyncConfig())
+
+	is.EqualError(underTest.VerifySignature(signature, body), "outline signature expired")
+}
+
+func (suite *OutlineSyncServiceTestSuite) TestEnqueueEventWhenModuleUpdated() {
+	is := assert.New(suite.T())
+
+	mockImportJobRepo := new(mockRepositories.ImportJobRepository)
+	mockContentImportService := new(mockServices.ContentImportService)
+	mockOutlineService := new(mockUtilServices.OutlineService)
+
+	mockOutlineService.EXPECT().DocumentInfo("modules").Return(&payload.OutlineDocument{Id: "module-parent"}, nil)
+	mockOutlineService.EXPECT().DocumentInfo("courses").Return(&payload.OutlineDocument{Id: "course-parent"}, nil)
+	mockImportJobRepo.EXPECT().FindPendingImportJobByDocumentId("doc").Return(nil, nil)
+	mockImportJobRepo.EXPECT().CreateImportJob(mock.Anything).Run(func(job *models.ImportJob) {
+		job.Id = utils.Ptr(uint64(7))
+	}).Return(nil)
+
+	underTest := NewOutlineSyncService(mockImportJobRepo, mockContentImportService, mockOutlineService, mockOutlineSyncConfig())
+
+	job, err := underTest.EnqueueEvent(&payload.OutlineWebhookEvent{
+		Event: "documents.update",
+		Payload: payload.OutlineWebhookPayload{
+			Id:    "doc",
+			Model: &payload.OutlineDocument{Id: "doc", ParentDocumentId: utils.Ptr("module-parent")},
+		},
+	})
+
+	is.Nil(err)
+	is.Equal(uint64(7), *job.Id)
+	is.Equal("module", *job.Kind)
+	is.Equal("pending", *job.Status)
+}
+
+func (suite *OutlineSyncServiceTestSuite) TestEnqueueEventWhenPendingJobExists() {
+	is := assert.New(suite.T())
+
+	mockImportJobRepo := new(mockRepositories.ImportJobRepository)
+	mockOutlineService := new(mockUtilServices.OutlineService)
+
+	mockOutlineService.EXPECT().DocumentInfo("modules").Return(&payload.OutlineDocument{Id: "module-parent"}, nil)
+	mockOutlineService.EXPECT().DocumentInfo("courses").Return(&payload.OutlineDocument{Id: "course-parent"}, nil)
+	mockImportJobRepo.EXPECT().FindPendingImportJobByDocumentId("doc").Return(&models.ImportJob{
+		Id:     utils.Ptr(uint64(3)),
+		Kind:   utils.Ptr("course"),
+		Status: utils.Ptr("pending"),
+	}, nil)
+
+	underTest := NewOutlineSyncService(mockImportJobRepo, nil, mockOutlineService, mockOutlineSyncConfig())
+
+	job, err := underTest.EnqueueEvent(&payload.OutlineWebhookEvent{
+		Event: "documents.publish",
+		Payload: payload.OutlineWebhookPayload{
+			Model: &payload.OutlineDocument{Id: "doc", ParentDocumentId: utils.Ptr("course-parent")},
+		},
+	})
+
+	is.Nil(err)
+	is.Equal(uint64(3), *job.Id)
+	mockImportJobRepo.AssertNotCalled(suite.T(), "CreateImportJob", mock.Anything)
+}
+
+func (suite *OutlineSyncServiceTestSuite) TestEnqueueEventWhenIgnored() {
+	is := assert.New(suite.T())
+
+	mockOutlineService := new(mockUtilServices.OutlineService)
+	mockOutlineService.EXPECT().DocumentInfo("modules").Return(&payload.OutlineDocument{Id: "module-parent"}, nil)
+	mockOutlineService.EXPECT().DocumentInfo("courses").Return(&payload.OutlineDocument{Id: "course-parent"}, nil)
+
+	underTest := NewOutlineSyncService(nil, nil, mockOutlineService, mockOutlineSyncConfig())
+
+	job, err := underTest.EnqueueEvent(&payload.OutlineWebhookEvent{Event: "documents.delete"})
+	is.Nil(err)
+	is.Nil(job)
+
+	job, err = underTest.EnqueueEvent(&payload.OutlineWebhookEvent{
+		Event: "documents.update",
+		Payload: payload.OutlineWebhookPayload{
+			Model: &payload.OutlineDocument{Id: "doc", ParentDocumentId: utils.Ptr("elsewhere")},
+		},
+	})
+	is.Nil(err)
+	is.Nil(job)
+}
+
+func TestOutlineSyncService(t *testing.T) {
+	suite.Run(t, new(OutlineSyncServiceTestSuite))
+}
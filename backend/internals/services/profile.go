@@ -5,4 +5,6 @@ import "backend/internals/entities/payload"
 type ProfileService interface {
 	GetUserInfo(userId *string) (*payload.Profile, error)
 	GetTotalGems(userID uint) (*payload.GemTotal, error)
+	UpdateTimezone(userId *string, body *payload.ProfileTimezone) (*payload.Profile, error)
+	GetTimeSpent(userId uint64, courseId *uint64) (*payload.TimeSpentReport, error)
 }
@@ -1,17 +1,22 @@
 package services
 
 import (
+	"backend/internals/db/models"
 	"backend/internals/entities/payload"
 	"backend/internals/repositories"
 )
 
 type profileService struct {
-	userRepo repositories.UserRepository
+	userRepo        repositories.UserRepository
+	stepSessionRepo repositories.StepSessionRepository
+	streakSvc       StreakService
 }
 
-func NewProfileService(userRepo repositories.UserRepository) ProfileService {
+func NewProfileService(userRepo repositories.UserRepository, stepSessionRepo repositories.StepSessionRepository, streakSvc StreakService) ProfileService {
 	return &profileService{
-		userRepo: userRepo,
+		userRepo:        userRepo,
+		stepSessionRepo: stepSessionRepo,
+		streakSvc:       streakSvc,
 	}
 }
 
@@ -21,12 +26,83 @@ func (r *profileService) GetUserInfo(userId *string) (*payload.Profile, error) {
 		return nil, tx
 	}
 
+	return r.profile(user)
+}
+
+func (r *profileService) UpdateTimezone(userId *string, body *payload.ProfileTimezone) (*payload.Profile, error) {
+	user, err := r.userRepo.FindUserByID(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	// days already logged keep the time zone they were recorded in
+	user.Timezone = body.Timezone
+	if err := r.userRepo.UpdateUser(user); err != nil {
+		return nil, err
+	}
+
+	return r.profile(user)
+}
+
+// GetTimeSpent reports the active time of the user per course, or per module and step of the
+// course when one is given.
+func (r *profileService) GetTimeSpent(userId uint64, courseId *uint64) (*payload.TimeSpentReport, error) {
+	report := &payload.TimeSpentReport{
+		CourseId: courseId,
+	}
+
+	if courseId == nil {
+		courses, err := r.stepSessionRepo.FindCourseTimeSpentByUserId(userId)
+		if err != nil {
+			return nil, err
+		}
+		report.Courses = courses
+		report.TotalSeconds = totalTimeSpent(courses)
+
+		return report, nil
+	}
+
+	modules, err := r.stepSessionRepo.FindModuleTimeSpentByUserId(userId, *courseId)
+	if err != nil {
+		return nil, err
+	}
+
+	steps, err := r.stepSessionRepo.FindStepTimeSpentByUserId(userId, *courseId)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Modules = modules
+	report.Steps = steps
+	report.TotalSeconds = totalTimeSpent(modules)
+
+	return report, nil
+}
+
+func totalTimeSpent(times []*payload.TimeSpent) int64 {
+	var total int64
+	for _, spent := range times {
+		total += spent.Seconds
+	}
+
+	return total
+}
+
+func (r *profileService) profile(user *models.User) (*payload.Profile, error) {
+	streak, err := r.streakSvc.GetStreak(user)
+	if err != nil {
+		return nil, err
+	}
+
 	result := &payload.Profile{
 		Id:        user.Id,
 		Firstname: user.Firstname,
 		Lastname:  user.Lastname,
 		Email:     user.Email,
 		PhotoUrl:  user.PhotoUrl,
+		Role:      user.Role,
+		Timezone:  user.Timezone,
+		Streak:    streak,
 	}
 
 	return result, nil
@@ -43,4 +119,4 @@ func (s *profileService) GetTotalGems(userID uint) (*payload.GemTotal, error) {
 		Total:  totalGems,
 	}
 	return result, nil
-}
\ No newline at end of file
+}
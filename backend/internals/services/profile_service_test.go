@@ -2,9 +2,11 @@ package services_test
 
 import (
 	"backend/internals/db/models"
+	"backend/internals/entities/payload"
 	"backend/internals/services"
 	"backend/internals/utils"
 	mockRepositories "backend/mocks/repositories"
+	mockServices "backend/mocks/services"
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -38,14 +40,18 @@ func (suite *ProfileTestSuit) TestGetUserInfoWhenSuccess() {
 		PhotoUrl:  mockPhotoUrl,
 	}, nil)
 
+	mockStreakService := new(mockServices.StreakService)
+	mockStreakService.EXPECT().GetStreak(mock.Anything).Return(&payload.Streak{Current: 3, Longest: 5}, nil)
+
 	// Test
-	underTest := services.NewProfileService(mockUserRepo)
+	underTest := services.NewProfileService(mockUserRepo, new(mockRepositories.StepSessionRepository), mockStreakService)
 
 	// Test Success
 	userInfo, err := underTest.GetUserInfo(utils.Ptr(strconv.Itoa(int(*mockUserId))))
 
 	is.Equal(*mockUserId, *userInfo.Id)
 	is.Equal(*mockFirstName, *userInfo.Firstname)
+	is.Equal(3, userInfo.Streak.Current)
 	is.NoError(err)
 }
 
@@ -61,7 +67,7 @@ func (suite *ProfileTestSuit) TestGetUserInfoWhenFailed() {
 	mockUserRepo.EXPECT().FindUserByID(mock.Anything).Return(nil, fmt.Errorf("user not found"))
 
 	// Test
-	underTest := services.NewProfileService(mockUserRepo)
+	underTest := services.NewProfileService(mockUserRepo, new(mockRepositories.StepSessionRepository), new(mockServices.StreakService))
 
 	// Test Success
 	userInfo, err := underTest.GetUserInfo(utils.Ptr(strconv.Itoa(int(*mockUserId))))
@@ -109,7 +115,7 @@ func (suite *ProfileTestSuit) TestGetTotalGemsWhenSuccess() {
 		GetTotalGemsByUserID(mockUserID).
 		Return(totalGems, nil)
 
-	underTest := services.NewProfileService(mockUserRepo)
+	underTest := services.NewProfileService(mockUserRepo, new(mockRepositories.StepSessionRepository), new(mockServices.StreakService))
 
 	result, err := underTest.GetTotalGems(mockUserID)
 
@@ -126,7 +132,7 @@ func (suite *ProfileTestSuit) TestGetTotalGemsWhenFailed() {
 
 	mockUserRepo.EXPECT().GetTotalGemsByUserID(mockUserID).Return(0, fmt.Errorf("gems data not found"))
 
-	underTest := services.NewProfileService(mockUserRepo)
+	underTest := services.NewProfileService(mockUserRepo, new(mockRepositories.StepSessionRepository), new(mockServices.StreakService))
 
 	gemTotal, err := underTest.GetTotalGems(mockUserID)
 
@@ -134,6 +140,83 @@ func (suite *ProfileTestSuit) TestGetTotalGemsWhenFailed() {
 	is.NotNil(err)
 }
 
+func (suite *ProfileTestSuit) TestUpdateTimezoneWhenSuccess() {
+	is := assert.New(suite.T())
+	mockUserRepo := new(mockRepositories.UserRepository)
+	mockStreakService := new(mockServices.StreakService)
+
+	user := &models.User{Id: utils.Ptr[uint64](1), Timezone: utils.Ptr("Asia/Bangkok")}
+	mockUserRepo.EXPECT().FindUserByID(utils.Ptr("1")).Return(user, nil)
+	mockUserRepo.EXPECT().UpdateUser(mock.MatchedBy(func(user *models.User) bool {
+		return *user.Timezone == "Europe/Paris"
+	})).Return(nil)
+	mockStreakService.EXPECT().GetStreak(user).Return(&payload.Streak{}, nil)
+
+	underTest := services.NewProfileService(mockUserRepo, new(mockRepositories.StepSessionRepository), mockStreakService)
+
+	profile, err := underTest.UpdateTimezone(utils.Ptr("1"), &payload.ProfileTimezone{Timezone: utils.Ptr("Europe/Paris")})
+
+	is.NoError(err)
+	is.Equal("Europe/Paris", *profile.Timezone)
+	mockUserRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ProfileTestSuit) TestUpdateTimezoneWhenFailed() {
+	is := assert.New(suite.T())
+	mockUserRepo := new(mockRepositories.UserRepository)
+
+	mockUserRepo.EXPECT().FindUserByID(utils.Ptr("1")).Return(nil, fmt.Errorf("user not found"))
+
+	underTest := services.NewProfileService(mockUserRepo, new(mockRepositories.StepSessionRepository), new(mockServices.StreakService))
+
+	profile, err := underTest.UpdateTimezone(utils.Ptr("1"), &payload.ProfileTimezone{Timezone: utils.Ptr("Europe/Paris")})
+
+	is.Nil(profile)
+	is.Error(err)
+}
+
+func (suite *ProfileTestSuit) TestGetTimeSpentPerCourse() {
+	is := assert.New(suite.T())
+	mockStepSessionRepo := new(mockRepositories.StepSessionRepository)
+
+	mockStepSessionRepo.EXPECT().FindCourseTimeSpentByUserId(uint64(1)).Return([]*payload.TimeSpent{
+		{Id: utils.Ptr[uint64](3), Seconds: 1200},
+		{Id: utils.Ptr[uint64](6), Seconds: 300},
+	}, nil)
+
+	underTest := services.NewProfileService(new(mockRepositories.UserRepository), mockStepSessionRepo, new(mockServices.StreakService))
+
+	report, err := underTest.GetTimeSpent(1, nil)
+
+	is.NoError(err)
+	is.Equal(int64(1500), report.TotalSeconds)
+	is.Len(report.Courses, 2)
+	is.Nil(report.Steps)
+}
+
+func (suite *ProfileTestSuit) TestGetTimeSpentPerModuleAndStep() {
+	is := assert.New(suite.T())
+	mockStepSessionRepo := new(mockRepositories.StepSessionRepository)
+
+	mockStepSessionRepo.EXPECT().FindModuleTimeSpentByUserId(uint64(1), uint64(3)).Return([]*payload.TimeSpent{
+		{Id: utils.Ptr[uint64](5), Seconds: 1200},
+	}, nil)
+	mockStepSessionRepo.EXPECT().FindStepTimeSpentByUserId(uint64(1), uint64(3)).Return([]*payload.TimeSpent{
+		{Id: utils.Ptr[uint64](8), Seconds: 700},
+		{Id: utils.Ptr[uint64](9), Seconds: 500},
+	}, nil)
+
+	underTest := services.NewProfileService(new(mockRepositories.UserRepository), mockStepSessionRepo, new(mockServices.StreakService))
+
+	report, err := underTest.GetTimeSpent(1, utils.Ptr[uint64](3))
+
+	is.NoError(err)
+	is.Equal(int64(1200), report.TotalSeconds)
+	is.Len(report.Modules, 1)
+	is.Len(report.Steps, 2)
+	is.Nil(report.Courses)
+}
+
 func TestProfileService(t *testing.T) {
 	suite.Run(t, new(ProfileTestSuit))
 }
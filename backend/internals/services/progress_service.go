@@ -23,7 +23,7 @@ func (s *progressService) GetCompletionPercentage(userID uint, courseID uint) (f
         return 0, fmt.Errorf("failed to fetch course steps")
     }
 
-    userSteps, err := s.userRepo.GetUserCompletedSteps(userID)
+    userSteps, err := s.userRepo.GetUserCompletedSteps(userID, courseID)
     if err != nil {
         return 0, fmt.Errorf("failed to fetch user completed steps: %w", err)
     }
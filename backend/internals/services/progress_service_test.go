@@ -40,7 +40,7 @@ func (suite *ProgressTestSuite) TestGetCompletionPercentageSuccess() {
 		Return(mockSteps, nil)
 
 	mockUserRepo.EXPECT().
-		GetUserCompletedSteps(mockUserID).
+		GetUserCompletedSteps(mockUserID, mockCourseID).
 		Return(mockUserActivities, nil)
 
 	underTest := services.NewProgressService(mockUserRepo, mockCourseRepo)
@@ -65,7 +65,7 @@ func (suite *ProgressTestSuite) TestGetCompletionPercentageNoStepsFound() {
 		Return([]models.Step{}, nil)
 
 	mockUserRepo.EXPECT().
-		GetUserCompletedSteps(mockUserID).
+		GetUserCompletedSteps(mockUserID, mockCourseID).
 		Return([]models.UserActivity{}, nil)
 
 	underTest := services.NewProgressService(mockUserRepo, mockCourseRepo)
@@ -0,0 +1,13 @@
+package services
+
+import "backend/internals/entities/payload"
+
+type RewardService interface {
+	GetCatalog(includeInactive bool) ([]*payload.RewardItemInfo, error)
+	CreateItem(body *payload.CreateRewardItem) (*payload.RewardItemInfo, error)
+	UpdateItem(itemId uint64, body *payload.UpdateRewardItem) (*payload.RewardItemInfo, error)
+	Redeem(userId uint64, itemId uint64) (*payload.RedemptionInfo, error)
+	GetMyRedemptions(userId uint64) ([]*payload.RedemptionInfo, error)
+	GetRedemptions(status *string) ([]*payload.RedemptionInfo, error)
+	Fulfill(redemptionId uint64, staffId uint64) (*payload.RedemptionInfo, error)
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/repositories"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRewardItemNotFound   = errors.New("reward item not found")
+	ErrRewardNotRedeemable  = errors.New("reward item cannot be redeemed")
+	ErrRedemptionNotFound   = errors.New("redemption not found")
+	ErrRedemptionNotPending = errors.New("redemption cannot be fulfilled")
+)
+
+type rewardService struct {
+	rewardRepo repositories.RewardRepository
+}
+
+func NewRewardService(rewardRepo repositories.RewardRepository) RewardService {
+	return &rewardService{
+		rewardRepo: rewardRepo,
+	}
+}
+
+// GetCatalog lists the items of the shop, cheapest first. Learners only see the active ones.
+func (r *rewardService) GetCatalog(includeInactive bool) ([]*payload.RewardItemInfo, error) {
+	items, err := r.rewardRepo.FindItems(!includeInactive)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]*payload.RewardItemInfo, 0, len(items))
+	for _, item := range items {
+		infos = append(infos, rewardItemInfo(item))
+	}
+
+	return infos, nil
+}
+
+func (r *rewardService) CreateItem(body *payload.CreateRewardItem) (*payload.RewardItemInfo, error) {
+	active := true
+	item := &models.RewardItem{
+		Name:        body.Name,
+		Description: body.Description,
+		ImageUrl:    body.ImageUrl,
+		Price:       body.Price,
+		Stock:       body.Stock,
+		Active:      &active,
+	}
+	if err := r.rewardRepo.CreateItem(item); err != nil {
+		return nil, err
+	}
+
+	return rewardItemInfo(item), nil
+}
+
+func (r *rewardService) UpdateItem(itemId uint64, body *payload.UpdateRewardItem) (*payload.RewardItemInfo, error) {
+	item, err := r.rewardRepo.FindItemById(itemId)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, ErrRewardItemNotFound
+	}
+
+	if body.Name != nil {
+		item.Name = body.Name
+	}
+	if body.Description != nil {
+		item.Description = body.Description
+	}
+	if body.ImageUrl != nil {
+		item.ImageUrl = body.ImageUrl
+	}
+	if body.Price != nil {
+		item.Price = body.Price
+	}
+	if body.Stock != nil {
+		item.Stock = body.Stock
+	}
+	if body.Active != nil {
+		item.Active = body.Active
+	}
+	if err := r.rewardRepo.UpdateItem(item); err != nil {
+		return nil, err
+	}
+
+	return rewardItemInfo(item), nil
+}
+
+func (r *rewardService) Redeem(userId uint64, itemId uint64) (*payload.RedemptionInfo, error) {
+	redemption, err := r.rewardRepo.Redeem(userId, itemId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRewardItemNotFound
+	}
+	if errors.Is(err, repositories.ErrRewardUnavailable) || errors.Is(err, repositories.ErrRewardOutOfStock) || errors.Is(err, repositories.ErrGemsInsufficient) {
+		return nil, fmt.Errorf("%w: %w", ErrRewardNotRedeemable, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to redeem reward item %d: %w", itemId, err)
+	}
+
+	return redemptionInfo(redemption), nil
+}
+
+func (r *rewardService) GetMyRedemptions(userId uint64) ([]*payload.RedemptionInfo, error) {
+	redemptions, err := r.rewardRepo.FindRedemptionsByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return redemptionInfos(redemptions), nil
+}
+
+func (r *rewardService) GetRedemptions(status *string) ([]*payload.RedemptionInfo, error) {
+	redemptions, err := r.rewardRepo.FindRedemptions(status)
+	if err != nil {
+		return nil, err
+	}
+
+	return redemptionInfos(redemptions), nil
+}
+
+func (r *rewardService) Fulfill(redemptionId uint64, staffId uint64) (*payload.RedemptionInfo, error) {
+	redemption, err := r.rewardRepo.FindRedemptionById(redemptionId)
+	if err != nil {
+		return nil, err
+	}
+	if redemption == nil {
+		return nil, ErrRedemptionNotFound
+	}
+
+	err = r.rewardRepo.Fulfill(redemptionId, staffId)
+	if errors.Is(err, repositories.ErrRedemptionFulfilled) {
+		return nil, fmt.Errorf("%w: %w", ErrRedemptionNotPending, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fulfill redemption %d: %w", redemptionId, err)
+	}
+
+	redemption, err = r.rewardRepo.FindRedemptionById(redemptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	return redemptionInfo(redemption), nil
+}
+
+func rewardItemInfo(item *models.RewardItem) *payload.RewardItemInfo {
+	return &payload.RewardItemInfo{
+		Id:          item.Id,
+		Name:        item.Name,
+		Description: item.Description,
+		ImageUrl:    item.ImageUrl,
+		Price:       item.Price,
+		Stock:       item.Stock,
+		Active:      item.Active,
+	}
+}
+
+func redemptionInfo(redemption *models.Redemption) *payload.RedemptionInfo {
+	info := &payload.RedemptionInfo{
+		Id:          redemption.Id,
+		Price:       redemption.Price,
+		Status:      redemption.Status,
+		FulfilledBy: redemption.FulfilledBy,
+		FulfilledAt: redemption.FulfilledAt,
+		CreatedAt:   redemption.CreatedAt,
+	}
+	if redemption.User != nil {
+		info.Learner = userInfo(redemption.User)
+	}
+	if redemption.RewardItem != nil {
+		info.RewardItem = rewardItemInfo(redemption.RewardItem)
+	}
+
+	return info
+}
+
+func redemptionInfos(redemptions []*models.Redemption) []*payload.RedemptionInfo {
+	infos := make([]*payload.RedemptionInfo, 0, len(redemptions))
+	for _, redemption := range redemptions {
+		infos = append(infos, redemptionInfo(redemption))
+	}
+
+	return infos
+}
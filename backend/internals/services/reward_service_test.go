@@ -0,0 +1,190 @@
+package services
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/repositories"
+	"backend/internals/utils"
+	mockRepositories "backend/mocks/repositories"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type RewardServiceTestSuite struct {
+	suite.Suite
+}
+
+func mockRewardItem() *models.RewardItem {
+	return &models.RewardItem{
+		Id:     utils.Ptr(uint64(7)),
+		Name:   utils.Ptr("Sensor kit"),
+		Price:  utils.Ptr(int64(120)),
+		Stock:  utils.Ptr(int64(3)),
+		Active: utils.Ptr(true),
+	}
+}
+
+func (suite *RewardServiceTestSuite) TestGetCatalogOnlyActiveForLearners() {
+	is := assert.New(suite.T())
+
+	mockRewardRepo := new(mockRepositories.RewardRepository)
+	mockRewardRepo.EXPECT().FindItems(true).Return([]*models.RewardItem{mockRewardItem()}, nil)
+
+	underTest := NewRewardService(mockRewardRepo)
+
+	items, err := underTest.GetCatalog(false)
+
+	is.Nil(err)
+	is.Len(items, 1)
+	is.Equal("Sensor kit", *items[0].Name)
+}
+
+func (suite *RewardServiceTestSuite) TestUpdateItemKeepsUnsetFields() {
+	is := assert.New(suite.T())
+
+	mockRewardRepo := new(mockRepositories.RewardRepository)
+	mockRewardRepo.EXPECT().FindItemById(uint64(7)).Return(mockRewardItem(), nil)
+	mockRewardRepo.EXPECT().UpdateItem(mock.MatchedBy(func(item *models.RewardItem) bool {
+		return *item.Stock == 10 && *item.Price == 120 && *item.Name == "Sensor kit"
+	})).Return(nil)
+
+	underTest := NewRewardService(mockRewardRepo)
+
+	item, err := underTest.UpdateItem(7, &payload.UpdateRewardItem{Stock: utils.Ptr(int64(10))})
+
+	is.Nil(err)
+	is.Equal(int64(10), *item.Stock)
+	mockRewardRepo.AssertExpectations(suite.T())
+}
+
+func (suite *RewardServiceTestSuite) TestUpdateItemWhenNotFound() {
+	is := assert.New(suite.T())
+
+	mockRewardRepo := new(mockRepositories.RewardRepository)
+	mockRewardRepo.EXPECT().FindItemById(uint64(7)).Return(nil, nil)
+
+	underTest := NewRewardService(mockRewardRepo)
+
+	item, err := underTest.UpdateItem(7, &payload.UpdateRewardItem{Stock: utils.Ptr(int64(10))})
+
+	is.Nil(item)
+	is.ErrorIs(err, ErrRewardItemNotFound)
+}
+
+func (suite *RewardServiceTestSuite) TestRedeemWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockRewardRepo := new(mockRepositories.RewardRepository)
+	mockRewardRepo.EXPECT().Redeem(uint64(4), uint64(7)).Return(&models.Redemption{
+		Id:         utils.Ptr(uint64(1)),
+		UserId:     utils.Ptr(uint64(4)),
+		RewardItem: mockRewardItem(),
+		Price:      utils.Ptr(int64(120)),
+		Status:     utils.Ptr(payload.RedemptionStatusPending),
+	}, nil)
+
+	underTest := NewRewardService(mockRewardRepo)
+
+	redemption, err := underTest.Redeem(4, 7)
+
+	is.Nil(err)
+	is.Equal(int64(120), *redemption.Price)
+	is.Equal("Sensor kit", *redemption.RewardItem.Name)
+	is.Nil(redemption.Learner)
+}
+
+func (suite *RewardServiceTestSuite) TestRedeemWhenNotRedeemable() {
+	is := assert.New(suite.T())
+
+	for _, repoErr := range []error{repositories.ErrGemsInsufficient, repositories.ErrRewardOutOfStock, repositories.ErrRewardUnavailable} {
+		mockRewardRepo := new(mockRepositories.RewardRepository)
+		mockRewardRepo.EXPECT().Redeem(uint64(4), uint64(7)).Return(nil, repoErr)
+
+		underTest := NewRewardService(mockRewardRepo)
+
+		redemption, err := underTest.Redeem(4, 7)
+
+		is.Nil(redemption)
+		is.ErrorIs(err, ErrRewardNotRedeemable)
+		is.ErrorIs(err, repoErr)
+	}
+}
+
+func (suite *RewardServiceTestSuite) TestRedeemWhenItemNotFound() {
+	is := assert.New(suite.T())
+
+	mockRewardRepo := new(mockRepositories.RewardRepository)
+	mockRewardRepo.EXPECT().Redeem(uint64(4), uint64(7)).Return(nil, gorm.ErrRecordNotFound)
+
+	underTest := NewRewardService(mockRewardRepo)
+
+	redemption, err := underTest.Redeem(4, 7)
+
+	is.Nil(redemption)
+	is.ErrorIs(err, ErrRewardItemNotFound)
+}
+
+func (suite *RewardServiceTestSuite) TestFulfillWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockRewardRepo := new(mockRepositories.RewardRepository)
+	pending := &models.Redemption{
+		Id:     utils.Ptr(uint64(1)),
+		User:   &models.User{Id: utils.Ptr(uint64(4)), Firstname: utils.Ptr("Somchai")},
+		Status: utils.Ptr(payload.RedemptionStatusPending),
+	}
+	fulfilled := &models.Redemption{
+		Id:          utils.Ptr(uint64(1)),
+		User:        pending.User,
+		Status:      utils.Ptr(payload.RedemptionStatusFulfilled),
+		FulfilledBy: utils.Ptr(uint64(9)),
+	}
+	mockRewardRepo.EXPECT().FindRedemptionById(uint64(1)).Return(pending, nil).Once()
+	mockRewardRepo.EXPECT().Fulfill(uint64(1), uint64(9)).Return(nil)
+	mockRewardRepo.EXPECT().FindRedemptionById(uint64(1)).Return(fulfilled, nil).Once()
+
+	underTest := NewRewardService(mockRewardRepo)
+
+	redemption, err := underTest.Fulfill(1, 9)
+
+	is.Nil(err)
+	is.Equal(payload.RedemptionStatusFulfilled, *redemption.Status)
+	is.Equal(uint64(4), *redemption.Learner.UserId)
+}
+
+func (suite *RewardServiceTestSuite) TestFulfillWhenAlreadyFulfilled() {
+	is := assert.New(suite.T())
+
+	mockRewardRepo := new(mockRepositories.RewardRepository)
+	mockRewardRepo.EXPECT().FindRedemptionById(uint64(1)).Return(&models.Redemption{Id: utils.Ptr(uint64(1))}, nil)
+	mockRewardRepo.EXPECT().Fulfill(uint64(1), uint64(9)).Return(repositories.ErrRedemptionFulfilled)
+
+	underTest := NewRewardService(mockRewardRepo)
+
+	redemption, err := underTest.Fulfill(1, 9)
+
+	is.Nil(redemption)
+	is.ErrorIs(err, ErrRedemptionNotPending)
+}
+
+func (suite *RewardServiceTestSuite) TestFulfillWhenNotFound() {
+	is := assert.New(suite.T())
+
+	mockRewardRepo := new(mockRepositories.RewardRepository)
+	mockRewardRepo.EXPECT().FindRedemptionById(uint64(1)).Return(nil, nil)
+
+	underTest := NewRewardService(mockRewardRepo)
+
+	redemption, err := underTest.Fulfill(1, 9)
+
+	is.Nil(redemption)
+	is.ErrorIs(err, ErrRedemptionNotFound)
+}
+
+func TestRewardService(t *testing.T) {
+	suite.Run(t, new(RewardServiceTestSuite))
+}
@@ -0,0 +1,10 @@
+package services
+
+import "backend/internals/entities/payload"
+
+type SessionAttendanceService interface {
+	GenerateCheckInCode(sessionId uint64) (*payload.CheckInCode, error)
+	GenerateCheckInQrCode(sessionId uint64) ([]byte, error)
+	CheckIn(code string, userId uint64) (*payload.AttendanceInfo, error)
+	ExportRoster(sessionId uint64) ([]byte, error)
+}
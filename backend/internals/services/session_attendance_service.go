@@ -0,0 +1,228 @@
+package services
+
+import (
+	"backend/internals/config"
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/repositories"
+	"backend/internals/utils"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/csv"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// checkInCodeWindow is how often the check-in code rotates. A code stays valid for the window
+// it was issued in and the next one, so a code scanned just before rotating is still accepted.
+const checkInCodeWindow = 30 * time.Second
+
+// checkInLeadTime is how long before a session starts learners may check in.
+const checkInLeadTime = 30 * time.Minute
+
+type sessionAttendanceService struct {
+	sessionAttendanceRepo repositories.SessionAttendanceRepository
+	workshopSessionRepo   repositories.WorkshopSessionRepository
+	conf                  *config.Config
+}
+
+func NewSessionAttendanceService(
+	sessionAttendanceRepo repositories.SessionAttendanceRepository,
+	workshopSessionRepo repositories.WorkshopSessionRepository,
+	conf *config.Config) SessionAttendanceService {
+	return &sessionAttendanceService{
+		sessionAttendanceRepo: sessionAttendanceRepo,
+		workshopSessionRepo:   workshopSessionRepo,
+		conf:                  conf,
+	}
+}
+
+func (r *sessionAttendanceService) GenerateCheckInCode(sessionId uint64) (*payload.CheckInCode, error) {
+	session, err := r.findOpenSession(sessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	window := utils.TimeNow().Unix() / int64(checkInCodeWindow.Seconds())
+	code := r.signCheckInCode(*session.Id, window)
+	expiresAt := time.Unix((window+2)*int64(checkInCodeWindow.Seconds()), 0).In(utils.BangkokTime)
+
+	return &payload.CheckInCode{
+		SessionId: session.Id,
+		Code:      &code,
+		ExpiresAt: &expiresAt,
+	}, nil
+}
+
+func (r *sessionAttendanceService) GenerateCheckInQrCode(sessionId uint64) ([]byte, error) {
+	checkInCode, err := r.GenerateCheckInCode(sessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	// point phone cameras at the check-in page when the frontend is known
+	content := *checkInCode.Code
+	if r.conf.FrontendScheme != nil && r.conf.FrontendUrl != nil {
+		content = fmt.Sprintf("%s://%s/checkin?code=%s", *r.conf.FrontendScheme, *r.conf.FrontendUrl, url.QueryEscape(content))
+	}
+
+	png, err := qrcode.Encode(content, qrcode.Medium, 512)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render check-in qr code: %w", err)
+	}
+
+	return png, nil
+}
+
+func (r *sessionAttendanceService) CheckIn(code string, userId uint64) (*payload.AttendanceInfo, error) {
+	sessionId, err := r.verifyCheckInCode(code)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := r.findOpenSession(sessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	attendance := &models.SessionAttendance{
+		SessionId:   session.Id,
+		UserId:      &userId,
+		CheckedInAt: utils.TimeNowPtr(),
+	}
+	if err := r.sessionAttendanceRepo.CreateAttendance(attendance); err != nil {
+		return nil, err
+	}
+
+	// read back, a second scan keeps the first check-in time
+	attendance, err = r.sessionAttendanceRepo.FindAttendance(sessionId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &payload.AttendanceInfo{
+		SessionId:   session.Id,
+		Title:       session.Title,
+		CheckedInAt: attendance.CheckedInAt,
+	}, nil
+}
+
+func (r *sessionAttendanceService) ExportRoster(sessionId uint64) ([]byte, error) {
+	session, err := r.workshopSessionRepo.FindSessionById(sessionId)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, fmt.Errorf("session %d not found", sessionId)
+	}
+
+	registrations, err := r.workshopSessionRepo.FindRegistrationsBySessionId(sessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	attendances, err := r.sessionAttendanceRepo.FindAttendancesBySessionId(sessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	checkedIn := make(map[uint64]*models.SessionAttendance)
+	for _, attendance := range attendances {
+		checkedIn[*attendance.UserId] = attendance
+	}
+
+	buffer := new(bytes.Buffer)
+	writer := csv.NewWriter(buffer)
+	writer.Write([]string{"user_id", "first_name", "last_name", "email", "registration", "checked_in_at"})
+
+	row := func(user *models.User, registration string) []string {
+		checkedInAt := ""
+		if attendance, ok := checkedIn[*user.Id]; ok {
+			checkedInAt = attendance.CheckedInAt.In(utils.BangkokTime).Format(time.RFC3339)
+			delete(checkedIn, *user.Id)
+		}
+		return []string{strconv.FormatUint(*user.Id, 10), utils.Val(user.Firstname), utils.Val(user.Lastname), utils.Val(user.Email), registration, checkedInAt}
+	}
+
+	for _, registration := range registrations {
+		writer.Write(row(registration.User, *registration.Status))
+	}
+
+	// learners who checked in without a registration
+	for _, attendance := range attendances {
+		if _, ok := checkedIn[*attendance.UserId]; ok {
+			writer.Write(row(attendance.User, "walk-in"))
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// findOpenSession returns the session when attendance can be taken for it.
+func (r *sessionAttendanceService) findOpenSession(sessionId uint64) (*models.WorkshopSession, error) {
+	session, err := r.workshopSessionRepo.FindSessionById(sessionId)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, fmt.Errorf("session %d not found", sessionId)
+	}
+
+	now := utils.TimeNow()
+	if now.Before(session.StartsAt.Add(-checkInLeadTime)) || now.After(*session.EndsAt) {
+		return nil, fmt.Errorf("session %d is not open for check-in", sessionId)
+	}
+
+	return session, nil
+}
+
+// signCheckInCode returns a code for the session in the given window, formatted as
+// <sessionId>.<window>.<signature>.
+func (r *sessionAttendanceService) signCheckInCode(sessionId uint64, window int64) string {
+	mac := hmac.New(sha256.New, []byte(*r.conf.SecretKey))
+	mac.Write([]byte(fmt.Sprintf("checkin.%d.%d", sessionId, window)))
+	signature := base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+
+	return fmt.Sprintf("%d.%d.%s", sessionId, window, signature)
+}
+
+// verifyCheckInCode checks the signature and the window of the code and returns its session.
+func (r *sessionAttendanceService) verifyCheckInCode(code string) (uint64, error) {
+	code = strings.TrimSpace(code)
+	parts := strings.Split(code, ".")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("malformed check-in code")
+	}
+
+	sessionId, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("malformed check-in code")
+	}
+	window, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("malformed check-in code")
+	}
+
+	if !hmac.Equal([]byte(r.signCheckInCode(sessionId, window)), []byte(code)) {
+		return 0, fmt.Errorf("invalid check-in code")
+	}
+
+	current := utils.TimeNow().Unix() / int64(checkInCodeWindow.Seconds())
+	if window != current && window != current-1 {
+		return 0, fmt.Errorf("check-in code has expired")
+	}
+
+	return sessionId, nil
+}
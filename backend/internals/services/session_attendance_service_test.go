@@ -0,0 +1,154 @@
+package services
+
+import (
+	"backend/internals/config"
+	"backend/internals/db/models"
+	"backend/internals/utils"
+	mockRepositories "backend/mocks/repositories"
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+	"time"
+)
+
+type SessionAttendanceServiceTestSuite struct {
+	suite.Suite
+}
+
+func mockAttendanceConfig() *config.Config {
+	return &config.Config{
+		SecretKey: utils.Ptr("attendance-secret"),
+	}
+}
+
+func (suite *SessionAttendanceServiceTestSuite) TestCheckInWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockSessionAttendanceRepo := new(mockRepositories.SessionAttendanceRepository)
+	mockWorkshopSessionRepo := new(mockRepositories.WorkshopSessionRepository)
+
+	session := mockWorkshopSession(utils.TimeNow().Add(-10 * time.Minute))
+	checkedInAt := utils.TimeNow().Add(-time.Minute)
+
+	mockWorkshopSessionRepo.EXPECT().FindSessionById(uint64(4)).Return(session, nil)
+	mockSessionAttendanceRepo.EXPECT().CreateAttendance(mock.MatchedBy(func(attendance *models.SessionAttendance) bool {
+		return *attendance.SessionId == 4 && *attendance.UserId == 1
+	})).Return(nil)
+	mockSessionAttendanceRepo.EXPECT().FindAttendance(uint64(4), uint64(1)).Return(&models.SessionAttendance{CheckedInAt: &checkedInAt}, nil)
+
+	underTest := NewSessionAttendanceService(mockSessionAttendanceRepo, mockWorkshopSessionRepo, mockAttendanceConfig())
+
+	code, err := underTest.GenerateCheckInCode(4)
+	is.Nil(err)
+
+	attendance, err := underTest.CheckIn(*code.Code, 1)
+
+	is.Nil(err)
+	is.Equal(checkedInAt, *attendance.CheckedInAt)
+	is.True(code.ExpiresAt.After(utils.TimeNow()))
+}
+
+func (suite *SessionAttendanceServiceTestSuite) TestCheckInWhenCodeForged() {
+	is := assert.New(suite.T())
+
+	mockSessionAttendanceRepo := new(mockRepositories.SessionAttendanceRepository)
+	mockWorkshopSessionRepo := new(mockRepositories.WorkshopSessionRepository)
+
+	underTest := NewSessionAttendanceService(mockSessionAttendanceRepo, mockWorkshopSessionRepo, mockAttendanceConfig())
+	forger := NewSessionAttendanceService(mockSessionAttendanceRepo, mockWorkshopSessionRepo, &config.Config{SecretKey: utils.Ptr("guess")}).(*sessionAttendanceService)
+
+	window := utils.TimeNow().Unix() / int64(checkInCodeWindow.Seconds())
+	attendance, err := underTest.CheckIn(forger.signCheckInCode(4, window), 1)
+
+	is.Nil(attendance)
+	is.Equal("invalid check-in code", err.Error())
+	mockSessionAttendanceRepo.AssertNotCalled(suite.T(), "CreateAttendance", mock.Anything)
+}
+
+func (suite *SessionAttendanceServiceTestSuite) TestCheckInWhenCodeExpired() {
+	is := assert.New(suite.T())
+
+	mockSessionAttendanceRepo := new(mockRepositories.SessionAttendanceRepository)
+	mockWorkshopSessionRepo := new(mockRepositories.WorkshopSessionRepository)
+
+	underTest := NewSessionAttendanceService(mockSessionAttendanceRepo, mockWorkshopSessionRepo, mockAttendanceConfig()).(*sessionAttendanceService)
+
+	window := utils.TimeNow().Unix()/int64(checkInCodeWindow.Seconds()) - 2
+	attendance, err := underTest.CheckIn(underTest.signCheckInCode(4, window), 1)
+
+	is.Nil(attendance)
+	is.Equal("check-in code has expired", err.Error())
+}
+
+func (suite *SessionAttendanceServiceTestSuite) TestGenerateCheckInCodeWhenSessionEnded() {
+	is := assert.New(suite.T())
+
+	mockSessionAttendanceRepo := new(mockRepositories.SessionAttendanceRepository)
+	mockWorkshopSessionRepo := new(mockRepositories.WorkshopSessionRepository)
+
+	mockWorkshopSessionRepo.EXPECT().FindSessionById(uint64(4)).Return(mockWorkshopSession(utils.TimeNow().Add(-3*time.Hour)), nil)
+
+	underTest := NewSessionAttendanceService(mockSessionAttendanceRepo, mockWorkshopSessionRepo, mockAttendanceConfig())
+
+	code, err := underTest.GenerateCheckInCode(4)
+
+	is.Nil(code)
+	is.Equal("session 4 is not open for check-in", err.Error())
+}
+
+func (suite *SessionAttendanceServiceTestSuite) TestGenerateCheckInQrCodeWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockSessionAttendanceRepo := new(mockRepositories.SessionAttendanceRepository)
+	mockWorkshopSessionRepo := new(mockRepositories.WorkshopSessionRepository)
+
+	mockWorkshopSessionRepo.EXPECT().FindSessionById(uint64(4)).Return(mockWorkshopSession(utils.TimeNow().Add(10*time.Minute)), nil)
+
+	underTest := NewSessionAttendanceService(mockSessionAttendanceRepo, mockWorkshopSessionRepo, mockAttendanceConfig())
+
+	png, err := underTest.GenerateCheckInQrCode(4)
+
+	is.Nil(err)
+	is.True(bytes.HasPrefix(png, []byte("\x89PNG")))
+}
+
+func (suite *SessionAttendanceServiceTestSuite) TestExportRosterWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockSessionAttendanceRepo := new(mockRepositories.SessionAttendanceRepository)
+	mockWorkshopSessionRepo := new(mockRepositories.WorkshopSessionRepository)
+
+	registered := &models.User{Id: utils.Ptr(uint64(1)), Firstname: utils.Ptr("Ann"), Lastname: utils.Ptr("A"), Email: utils.Ptr("ann@example.com")}
+	absent := &models.User{Id: utils.Ptr(uint64(2)), Firstname: utils.Ptr("Bo"), Lastname: utils.Ptr("B"), Email: utils.Ptr("bo@example.com")}
+	walkIn := &models.User{Id: utils.Ptr(uint64(3)), Firstname: utils.Ptr("Cy"), Lastname: utils.Ptr("C"), Email: utils.Ptr("cy@example.com")}
+	checkedInAt := time.Date(2026, 10, 1, 9, 5, 0, 0, utils.BangkokTime)
+
+	mockWorkshopSessionRepo.EXPECT().FindSessionById(uint64(4)).Return(mockWorkshopSession(checkedInAt), nil)
+	mockWorkshopSessionRepo.EXPECT().FindRegistrationsBySessionId(uint64(4)).Return([]*models.SessionRegistration{
+		{UserId: registered.Id, User: registered, Status: utils.Ptr("registered")},
+		{UserId: absent.Id, User: absent, Status: utils.Ptr("waitlisted")},
+	}, nil)
+	mockSessionAttendanceRepo.EXPECT().FindAttendancesBySessionId(uint64(4)).Return([]*models.SessionAttendance{
+		{UserId: registered.Id, User: registered, CheckedInAt: &checkedInAt},
+		{UserId: walkIn.Id, User: walkIn, CheckedInAt: &checkedInAt},
+	}, nil)
+
+	underTest := NewSessionAttendanceService(mockSessionAttendanceRepo, mockWorkshopSessionRepo, mockAttendanceConfig())
+
+	roster, err := underTest.ExportRoster(4)
+
+	is.Nil(err)
+	is.Equal([]string{
+		"user_id,first_name,last_name,email,registration,checked_in_at",
+		"1,Ann,A,ann@example.com,registered,2026-10-01T09:05:00+07:00",
+		"2,Bo,B,bo@example.com,waitlisted,",
+		"3,Cy,C,cy@example.com,walk-in,2026-10-01T09:05:00+07:00",
+	}, strings.Split(strings.TrimSpace(string(roster)), "\n"))
+}
+
+func TestSessionAttendanceService(t *testing.T) {
+	suite.Run(t, new(SessionAttendanceServiceTestSuite))
+}
@@ -5,14 +5,17 @@ import (
 )
 
 type StepService interface {
-	GetGems(stepId *uint64, userId *float64) (*int, *int, error)
+	ResolveCourseId(stepId *uint64, courseId *uint64) (*uint64, error)
+	EnsureStepUnlocked(stepId *uint64, courseId *uint64, userId *float64) error
+	GetGems(stepId *uint64, courseId *uint64, userId *float64) (*int, *int, error)
 	GetStepComment(stepId *uint64, userId *uint64) ([]payload.StepCommentInfo, error)
-	CreateStpComment(stepId *uint64, userId *float64, content *string) error
+	CreateStpComment(stepId *uint64, userId *float64, content *string, parentId *uint64) error
 	CreateOrDeleteStepCommentUpVote(userId *float64, stepCommentId *uint64) error
-	GetStepInfo(stepId *uint64) (*payload.StepInfo, error)
-	GetStepEvalInfo(stepId *uint64, userId *float64) ([]*payload.StepEvalInfo, error)
-	CreateFileFormat(stepId *uint64, stepEvalId *uint64, userId *float64) (*string, error)
+	GetStepInfo(stepId *uint64, courseId *uint64, userId *float64) (*payload.StepInfo, error)
+	GetStepEvalInfo(stepId *uint64, courseId *uint64, userId *float64) ([]*payload.StepEvalInfo, error)
+	CreateFileFormat(courseId *uint64, stepId *uint64, stepEvalId *uint64, userId *float64) (*string, error)
 	CreateUserEval(payload *payload.CreateUserEvalReq) (*uint64, error)
 	CheckStepEvalStatus(userEvalId *uint64, userId *uint64) (*payload.UserEvalResult, error)
-	SubmitStepEvalTypeCheck(stepEvalId *uint64, userId *uint64) (*uint64, error)
+	SubmitStepEvalTypeCheck(stepEvalId *uint64, courseId *uint64, userId *uint64) (*uint64, error)
+	GradeUserEval(userEvalId *uint64, graderId uint64, body *payload.GradeUserEval) (*payload.UserEvalResult, error)
 }
@@ -6,11 +6,14 @@ import (
 	"backend/internals/entities/payload"
 	"backend/internals/repositories"
 	"backend/internals/utils"
+	utilServices "backend/internals/utils/services"
 	"fmt"
 	"net/url"
 	"sort"
 	"strconv"
 	"time"
+
+	"github.com/sirupsen/logrus"
 )
 
 type stepService struct {
@@ -23,6 +26,14 @@ type stepService struct {
 	userEvalRepo          repositories.UserEvaluateRepository
 	courseContentRepo     repositories.CourseContentRepository
 	moduleRepo            repositories.ModulesRepository
+	cohortRepo            repositories.CohortRepository
+	teamRepo              repositories.TeamRepository
+	gemSvc                GemService
+	achievementSvc        AchievementService
+	notificationSvc       NotificationService
+	xapiSvc               XapiService
+	certificateSvc        CertificateService
+	eventHub              utilServices.EventHub
 }
 
 func NewStepService(
@@ -34,7 +45,15 @@ func NewStepService(
 	userRepo repositories.UserRepository,
 	userEvalRepo repositories.UserEvaluateRepository,
 	courseContentRepo repositories.CourseContentRepository,
-	moduleRepo repositories.ModulesRepository) StepService {
+	moduleRepo repositories.ModulesRepository,
+	cohortRepo repositories.CohortRepository,
+	teamRepo repositories.TeamRepository,
+	gemSvc GemService,
+	achievementSvc AchievementService,
+	notificationSvc NotificationService,
+	xapiSvc XapiService,
+	certificateSvc CertificateService,
+	eventHub utilServices.EventHub) StepService {
 	return &stepService{
 		stepEvalRepo:          stepEvalRepo,
 		userEvalRepo:          userEvalRepo,
@@ -45,20 +64,126 @@ func NewStepService(
 		stepAuthorRepo:        stepAuthorRepo,
 		courseContentRepo:     courseContentRepo,
 		moduleRepo:            moduleRepo,
+		cohortRepo:            cohortRepo,
+		teamRepo:              teamRepo,
+		gemSvc:                gemSvc,
+		achievementSvc:        achievementSvc,
+		notificationSvc:       notificationSvc,
+		xapiSvc:               xapiSvc,
+		certificateSvc:        certificateSvc,
+		eventHub:              eventHub,
+	}
+}
+
+// resolveCourseContent returns the content placing the module in the course it is taken in:
+// the given course, or the only course containing the module when none is given.
+func resolveCourseContent(courseContentRepo repositories.CourseContentRepository, courseId *uint64, moduleId *uint64) (*models.CourseContent, error) {
+	if courseId == nil {
+		courseIds, err := courseContentRepo.GetCourseIdsByModuleId(moduleId)
+		if err != nil {
+			return nil, err
+		}
+
+		switch len(courseIds) {
+		case 0:
+			return nil, fmt.Errorf("module %d is not part of any course", *moduleId)
+		case 1:
+			courseId = &courseIds[0]
+		default:
+			return nil, fmt.Errorf("module %d is shared by several courses, courseId is required", *moduleId)
+		}
+	}
+
+	courseContent, err := courseContentRepo.FindCourseContentByCourseIdAndModuleId(courseId, moduleId)
+	if err != nil {
+		return nil, err
+	}
+	if courseContent == nil {
+		return nil, fmt.Errorf("module %d is not part of course %d", *moduleId, *courseId)
+	}
+
+	return courseContent, nil
+}
+
+func (r *stepService) ResolveCourseId(stepId *uint64, courseId *uint64) (*uint64, error) {
+	moduleId, err := r.stepRepo.GetModuleIdByStepId(stepId)
+	if err != nil {
+		return nil, err
 	}
+
+	courseContent, err := resolveCourseContent(r.courseContentRepo, courseId, moduleId)
+	if err != nil {
+		return nil, err
+	}
+
+	return courseContent.CourseId, nil
 }
 
-func (r *stepService) GetGems(stepId *uint64, userId *float64) (*int, *int, error) {
+// EnsureStepUnlocked fails when the user learns the course in a cohort whose instructors have
+// not unlocked the step yet.
+func (r *stepService) EnsureStepUnlocked(stepId *uint64, courseId *uint64, userId *float64) error {
+	cohort, err := r.cohortRepo.FindLearnerCohort(uint64(*userId), *courseId)
+	if err != nil {
+		return err
+	}
+	if cohort == nil {
+		return nil
+	}
+
+	step, err := r.stepRepo.GetStepById(stepId)
+	if err != nil {
+		return err
+	}
+
+	courseContent, err := r.courseContentRepo.FindCourseContentByCourseIdAndModuleId(courseId, step.ModuleId)
+	if err != nil {
+		return err
+	}
+	if courseContent == nil {
+		return fmt.Errorf("module %d is not part of course %d", *step.ModuleId, *courseId)
+	}
+
+	locked := fmt.Errorf("step %d is locked until the cohort instructor unlocks it", *stepId)
+	switch {
+	case cohort.UnlockedOrder == nil || *courseContent.Order > *cohort.UnlockedOrder:
+		return locked
+	case *courseContent.Order < *cohort.UnlockedOrder || cohort.UnlockedStepId == nil:
+		return nil
+	}
+
+	// the module is being taken, steps up to the unlocked one are open
+	steps, err := r.stepRepo.FindStepsByModuleID(utils.Ptr(strconv.FormatUint(*step.ModuleId, 10)))
+	if err != nil {
+		return err
+	}
+	for _, moduleStep := range steps {
+		if *moduleStep.Id == *stepId {
+			return nil
+		}
+		if *moduleStep.Id == *cohort.UnlockedStepId {
+			return locked
+		}
+	}
+
+	return locked
+}
+
+func (r *stepService) GetGems(stepId *uint64, courseId *uint64, userId *float64) (*int, *int, error) {
+	courseId, err := r.ResolveCourseId(stepId, courseId)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	stepEvals, err := r.stepEvalRepo.GetStepEvalByStepId(stepId)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	totalGems := 0
-	currentGems := 0
+	userEvalIds := make([]uint64, 0, len(stepEvals))
 	for _, eval := range stepEvals {
 		totalGems += *eval.Gem
-		userEval, err2 := r.userEvalRepo.GetUserEvalByStepEvalIdUserId(eval.Id, userId)
+		userEval, err2 := r.userEvalRepo.GetUserEvalByStepEvalIdUserId(eval.Id, courseId, userId)
 		if err2 != nil {
 			return nil, nil, err2
 		}
@@ -67,15 +192,15 @@ func (r *stepService) GetGems(stepId *uint64, userId *float64) (*int, *int, erro
 			continue
 		}
 
-		if userEval.Pass == nil {
-			continue
-		}
-
-		if *userEval.Pass == true {
-			currentGems += *eval.Gem
-		}
+		userEvalIds = append(userEvalIds, *userEval.Id)
+	}
 
+	// the gems earned are what the ledger holds for the evaluations, not what they are worth now
+	earned, err := r.gemSvc.GetUserEvalGems(uint64(*userId), userEvalIds)
+	if err != nil {
+		return nil, nil, err
 	}
+	currentGems := int(earned)
 
 	return &totalGems, &currentGems, nil
 }
@@ -106,6 +231,7 @@ func (r *stepService) GetStepComment(stepId *uint64, userId *uint64) ([]payload.
 
 		stepCommentInfo = append(stepCommentInfo, payload.StepCommentInfo{
 			StepCommentId: comment.Id,
+			ParentId:      comment.ParentId,
 			UserInfo: &payload.CommentedBy{
 				UserId:    user.Id,
 				FirstName: user.Firstname,
@@ -122,17 +248,76 @@ func (r *stepService) GetStepComment(stepId *uint64, userId *uint64) ([]payload.
 	return stepCommentInfo, nil
 }
 
-func (r *stepService) CreateStpComment(stepId *uint64, userId *float64, content *string) error {
+func (r *stepService) CreateStpComment(stepId *uint64, userId *float64, content *string, parentId *uint64) error {
+	var parent *models.StepComment
+	if parentId != nil {
+		var err error
+		parent, err = r.stepCommentRepo.GetStepCommentById(parentId)
+		if err != nil {
+			return err
+		}
+		if parent == nil || *parent.StepId != *stepId {
+			return fmt.Errorf("comment %d is not a comment of step %d", *parentId, *stepId)
+		}
+	}
+
 	stepComment := &models.StepComment{
-		Content: content,
-		StepId:  stepId,
-		UserId:  utils.Ptr(uint64(*userId)),
+		Content:  content,
+		StepId:   stepId,
+		UserId:   utils.Ptr(uint64(*userId)),
+		ParentId: parentId,
 	}
 
-	if err := r.stepCommentRepo.CreateStepComment(stepComment); err != nil {
+	user, err := r.userRepo.FindUserByID(utils.Ptr(strconv.FormatUint(*stepComment.UserId, 10)))
+	if err != nil {
 		return err
 	}
 
+	replied := parent != nil && *parent.UserId != *stepComment.UserId
+	var notification *models.Notification
+	if replied {
+		// the email is stored with the reply, failing to render it must not fail the reply
+		notification, err = r.notificationSvc.CommentReply(parent, stepComment, user)
+		if err != nil {
+			logrus.Errorf("[NOTIFICATION] Unable to notify the reply to comment %d: %v", *parent.Id, err)
+		}
+	}
+
+	if err := r.stepCommentRepo.CreateStepComment(stepComment, notification); err != nil {
+		return err
+	}
+	if notification != nil {
+		r.notificationSvc.Wake()
+	}
+	r.achievementSvc.Record(*stepComment.UserId, payload.AchievementTriggerCommentCreated)
+	if err := r.xapiSvc.RecordCommented(stepComment); err != nil {
+		logrus.Errorf("[XAPI] Unable to record comment %d: %v", *stepComment.Id, err)
+	}
+
+	commentEvent := &payload.StepCommentEvent{
+		StepCommentId: stepComment.Id,
+		StepId:        stepId,
+		ParentId:      parentId,
+		UserInfo: &payload.CommentedBy{
+			UserId:    user.Id,
+			FirstName: user.Firstname,
+			Lastname:  user.Lastname,
+			Email:     user.Email,
+			PhotoUrl:  user.PhotoUrl,
+		},
+		Comment: content,
+	}
+	r.eventHub.Publish(utilServices.StepTopic(*stepId), &payload.Event{
+		Type: payload.EventStepComment,
+		Data: commentEvent,
+	})
+	if replied {
+		r.eventHub.Publish(utilServices.UserTopic(*parent.UserId), &payload.Event{
+			Type: payload.EventCommentReply,
+			Data: commentEvent,
+		})
+	}
+
 	return nil
 }
 
@@ -151,7 +336,7 @@ func (r *stepService) CreateOrDeleteStepCommentUpVote(userId *float64, stepComme
 		if err := r.stepCommentUpVoteRepo.CreateStepCommentUpVote(stepCommentUpVote); err != nil {
 			return err
 		}
-		return nil
+		return r.publishUpVote(stepCommentId, utils.Ptr(uint64(*userId)))
 	}
 
 	if err := r.stepCommentUpVoteRepo.DeleteStepCommentUpVote(stepCommentId, utils.Ptr(uint64(*userId))); err != nil {
@@ -160,7 +345,7 @@ func (r *stepService) CreateOrDeleteStepCommentUpVote(userId *float64, stepComme
 	return nil
 }
 
-func (r *stepService) GetStepInfo(stepId *uint64) (*payload.StepInfo, error) {
+func (r *stepService) GetStepInfo(stepId *uint64, courseId *uint64, userId *float64) (*payload.StepInfo, error) {
 	step, err := r.stepRepo.GetStepById(stepId)
 	if err != nil {
 		return nil, err
@@ -171,6 +356,15 @@ func (r *stepService) GetStepInfo(stepId *uint64) (*payload.StepInfo, error) {
 		return nil, err
 	}
 
+	courseContent, err := resolveCourseContent(r.courseContentRepo, courseId, step.ModuleId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.EnsureStepUnlocked(stepId, courseContent.CourseId, userId); err != nil {
+		return nil, err
+	}
+
 	stepAuthors, err := r.stepAuthorRepo.GetStepAuthorByStepId(stepId)
 	if err != nil {
 		return nil, err
@@ -184,7 +378,9 @@ func (r *stepService) GetStepInfo(stepId *uint64) (*payload.StepInfo, error) {
 	stepDetail := &payload.StepDetail{
 		StepId:      step.Id,
 		Banner:      module.ImageUrl,
+		CourseId:    courseContent.CourseId,
 		ModuleId:    step.ModuleId,
+		ModuleTitle: module.Title,
 		Title:       step.Title,
 		Description: step.Description,
 		Content:     step.Content,
@@ -193,6 +389,10 @@ func (r *stepService) GetStepInfo(stepId *uint64) (*payload.StepInfo, error) {
 		Error:       step.Error,
 	}
 
+	if courseContent.Course != nil {
+		stepDetail.CourseName = courseContent.Course.Name
+	}
+
 	stepInfo := &payload.StepInfo{
 		Step: stepDetail,
 	}
@@ -215,24 +415,27 @@ func (r *stepService) GetStepInfo(stepId *uint64) (*payload.StepInfo, error) {
 	}
 	stepInfo.Authors = authors
 
-	passedUsersMap := make(map[uint64]int)
+	passedUsersMap := make(map[uint64]map[uint64]bool)
 	for _, stepEval := range stepEvals {
-		userEvals, err := r.userEvalRepo.GetPassAllUserEvalByStepEvalId(stepEval.Id)
+		userEvals, err := r.userEvalRepo.GetPassAllUserEvalByStepEvalId(stepEval.Id, courseContent.CourseId)
 		if err != nil {
 			return nil, err
 		}
 
-		// Increment pass count for each user
+		// Collect passed evaluations for each user, a user may have passed one in the course and before it was tracked
 		for _, userEval := range userEvals {
 			userId := *userEval.UserId
-			passedUsersMap[userId]++
+			if passedUsersMap[userId] == nil {
+				passedUsersMap[userId] = make(map[uint64]bool)
+			}
+			passedUsersMap[userId][*stepEval.Id] = true
 		}
 	}
 
 	requiredPassCount := len(stepEvals)
 	passedUsers := make([]uint64, 0)
-	for userId, passCount := range passedUsersMap {
-		if passCount == requiredPassCount {
+	for userId, passed := range passedUsersMap {
+		if len(passed) == requiredPassCount {
 			passedUsers = append(passedUsers, userId)
 		}
 	}
@@ -259,7 +462,16 @@ func (r *stepService) GetStepInfo(stepId *uint64) (*payload.StepInfo, error) {
 	return stepInfo, nil
 }
 
-func (r *stepService) GetStepEvalInfo(stepId *uint64, userId *float64) ([]*payload.StepEvalInfo, error) {
+func (r *stepService) GetStepEvalInfo(stepId *uint64, courseId *uint64, userId *float64) ([]*payload.StepEvalInfo, error) {
+	courseId, err := r.ResolveCourseId(stepId, courseId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.EnsureStepUnlocked(stepId, courseId, userId); err != nil {
+		return nil, err
+	}
+
 	stepEvals, err := r.stepEvalRepo.GetStepEvalByStepId(stepId)
 	if err != nil {
 		return nil, err
@@ -277,7 +489,7 @@ func (r *stepService) GetStepEvalInfo(stepId *uint64, userId *float64) ([]*paylo
 			Question:    eval.Question,
 		}
 
-		userEval, err := r.userEvalRepo.GetUserEvalByStepEvalIdUserId(eval.Id, userId)
+		userEval, err := r.userEvalRepo.GetUserEvalByStepEvalIdUserId(eval.Id, courseId, userId)
 		if err != nil {
 			return nil, err
 		}
@@ -289,6 +501,10 @@ func (r *stepService) GetStepEvalInfo(stepId *uint64, userId *float64) ([]*paylo
 				Content:    userEval.Content,
 				Pass:       userEval.Pass,
 				Comment:    userEval.Comment,
+				TeamId:     userEval.TeamId,
+			}
+			if userEval.Submitter != nil {
+				evalResult.SubmittedBy = userInfo(userEval.Submitter)
 			}
 			if *eval.Type == "image" {
 				content, err := url.JoinPath(*config.Env.MinioS3Endpoint, *config.Env.MinioS3BucketName, *userEval.Content)
@@ -312,54 +528,139 @@ func (r *stepService) GetStepEvalInfo(stepId *uint64, userId *float64) ([]*paylo
 	return stepEvalInfoList, nil
 }
 
-func (r *stepService) CreateFileFormat(stepId *uint64, stepEvalId *uint64, userId *float64) (*string, error) {
+func (r *stepService) CreateFileFormat(courseId *uint64, stepId *uint64, stepEvalId *uint64, userId *float64) (*string, error) {
 	moduleId, err := r.stepRepo.GetModuleIdByStepId(stepId)
 	if err != nil {
 		return nil, err
 	}
 
-	courseId, err := r.courseContentRepo.GetCourseIdByModuleId(moduleId)
-	if err != nil {
-		return nil, err
-	}
-
 	filename := fmt.Sprintf("course%d_module%d_step%d_userId%d_eval%d_%s.png", *courseId, *moduleId, *stepId, uint64(*userId), *stepEvalId, time.Now().UTC().Format(time.RFC3339))
 
 	return &filename, nil
 }
 
 func (r *stepService) CreateUserEval(payload *payload.CreateUserEvalReq) (*uint64, error) {
-	userEval, err := r.userEvalRepo.GetUserEvalByStepEvalIdUserId(payload.StepEvalId, payload.UserId)
+	userId := uint64(*payload.UserId)
+
+	stepEval, err := r.stepEvalRepo.GetStepEvalById(payload.StepEvalId)
+	if err != nil {
+		return nil, err
+	}
+
+	// the step was checked to be unlocked, so the evaluation must belong to it
+	if utils.Val(stepEval.StepId) != utils.Val(payload.StepId) {
+		return nil, fmt.Errorf("step evaluation %d is not part of step %d", *payload.StepEvalId, utils.Val(payload.StepId))
+	}
+
+	team, err := r.submissionTeam(stepEval, payload.CourseId, userId)
+	if err != nil {
+		return nil, err
+	}
+	if team != nil {
+		userEvals, submitted, err := r.saveTeamUserEvals(team, stepEval, payload.CourseId, userId, payload.Content, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		r.recordAttempted(userEvals...)
+
+		return submitted.Id, nil
+	}
+
+	userEval, err := r.userEvalRepo.GetUserEvalByStepEvalIdUserId(payload.StepEvalId, payload.CourseId, payload.UserId)
 	if err != nil {
 		return nil, err
 	}
 
 	if userEval == nil {
 		NewUserEval := &models.UserEvaluate{
-			UserId:         utils.Ptr(uint64(*payload.UserId)),
+			UserId:         &userId,
 			Content:        payload.Content,
 			StepEvaluateId: payload.StepEvalId,
+			CourseId:       payload.CourseId,
+			Attempts:       utils.Ptr(1),
+			SubmittedBy:    &userId,
 		}
 
 		result, err := r.userEvalRepo.CreateUserEval(NewUserEval)
 		if err != nil {
 			return nil, err
 		}
+		r.recordAttempted(result)
 
 		return result.Id, nil
 	}
 
 	userEval.Content = payload.Content
+	userEval.CourseId = payload.CourseId
 	userEval.Pass = nil
 	userEval.Comment = nil
-	if err := r.userEvalRepo.Update(userEval); err != nil {
+	userEval.TeamId = nil
+	userEval.Attempts = utils.Ptr(utils.Val(userEval.Attempts) + 1)
+	userEval.SubmittedBy = &userId
+	// a resubmission waits for a new grade, so the gems of the previous one are revoked
+	if err := r.userEvalRepo.SaveUserEvals([]*models.UserEvaluate{userEval}, stepGems(stepEval), nil); err != nil {
 		return nil, err
 	}
+	r.recordAttempted(userEval)
 
 	return userEval.Id, err
 
 }
 
+// submissionTeam returns the team a submission of the user counts for, or nil when the user
+// takes the evaluation alone.
+func (r *stepService) submissionTeam(stepEval *models.StepEvaluate, courseId *uint64, userId uint64) (*models.Team, error) {
+	if !utils.Val(stepEval.TeamEligible) || courseId == nil {
+		return nil, nil
+	}
+
+	return r.teamRepo.FindTeamOfUser(userId, *courseId)
+}
+
+// saveTeamUserEvals records the submission for every member of the team, replacing what each of
+// them submitted before and settling their gems. It returns the evaluations it saved and the one
+// of the submitter.
+func (r *stepService) saveTeamUserEvals(team *models.Team, stepEval *models.StepEvaluate, courseId *uint64, submitterId uint64, content *string, pass *bool, comment *string) ([]*models.UserEvaluate, *models.UserEvaluate, error) {
+	userEvals := make([]*models.UserEvaluate, 0, len(team.Members))
+	var submitted *models.UserEvaluate
+	for _, member := range team.Members {
+		userEval, err := r.userEvalRepo.GetUserEvalByStepEvalIdUserId(stepEval.Id, courseId, utils.Ptr(float64(*member.UserId)))
+		if err != nil {
+			return nil, nil, err
+		}
+		if userEval == nil {
+			userEval = &models.UserEvaluate{
+				UserId:         member.UserId,
+				StepEvaluateId: stepEval.Id,
+			}
+		}
+		if *member.UserId == submitterId {
+			submitted = userEval
+		}
+		// marking as complete changes nothing for a member who already passed
+		if utils.Val(pass) && utils.Val(userEval.Pass) {
+			continue
+		}
+		userEval.Attempts = utils.Ptr(utils.Val(userEval.Attempts) + 1)
+
+		userEval.CourseId = courseId
+		userEval.Content = content
+		userEval.Pass = pass
+		userEval.Comment = comment
+		userEval.TeamId = team.Id
+		userEval.SubmittedBy = &submitterId
+		userEvals = append(userEvals, userEval)
+	}
+
+	if len(userEvals) > 0 {
+		if err := r.userEvalRepo.SaveUserEvals(userEvals, stepGems(stepEval), nil); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return userEvals, submitted, nil
+}
+
 func (r *stepService) CheckStepEvalStatus(userEvalId *uint64, userId *uint64) (*payload.UserEvalResult, error) {
 	userEvalInfo, err := r.userEvalRepo.GetUserEvalByIdAndUserId(userEvalId, userId)
 	if err != nil {
@@ -376,6 +677,10 @@ func (r *stepService) CheckStepEvalStatus(userEvalId *uint64, userId *uint64) (*
 			Pass:       userEvalInfo.Pass,
 			Comment:    userEvalInfo.Comment,
 			Content:    userEvalInfo.Content,
+			TeamId:     userEvalInfo.TeamId,
+		}
+		if userEvalInfo.Submitter != nil {
+			result.SubmittedBy = userInfo(userEvalInfo.Submitter)
 		}
 
 		return result, nil
@@ -384,19 +689,262 @@ func (r *stepService) CheckStepEvalStatus(userEvalId *uint64, userId *uint64) (*
 	return nil, nil
 }
 
-func (r *stepService) SubmitStepEvalTypeCheck(stepEvalId *uint64, userId *uint64) (*uint64, error) {
-	userEval := &models.UserEvaluate{
-		UserId:         userId,
-		StepEvaluateId: stepEvalId,
-		Pass:           utils.Ptr(true),
-		Comment:        utils.Ptr(""),
-		Content:        utils.Ptr("mark as complete"),
+func (r *stepService) SubmitStepEvalTypeCheck(stepEvalId *uint64, courseId *uint64, userId *uint64) (*uint64, error) {
+	stepEval, err := r.stepEvalRepo.GetStepEvalById(stepEvalId)
+	if err != nil {
+		return nil, err
 	}
 
-	newUserEval, err := r.userEvalRepo.CreateUserEval(userEval)
+	// only a check evaluation is passed by marking it, the others wait for an answer and a grade
+	if utils.Val(stepEval.Type) != "check" {
+		return nil, fmt.Errorf("step evaluation %d is not a check evaluation", *stepEvalId)
+	}
+
+	courseId, err = r.ResolveCourseId(stepEval.StepId, courseId)
 	if err != nil {
 		return nil, err
 	}
 
-	return newUserEval.Id, nil
+	team, err := r.submissionTeam(stepEval, courseId, *userId)
+	if err != nil {
+		return nil, err
+	}
+	if team != nil {
+		// the step is completed for every member, so it must be unlocked for each of them
+		for _, member := range team.Members {
+			if err := r.EnsureStepUnlocked(stepEval.StepId, courseId, utils.Ptr(float64(*member.UserId))); err != nil {
+				return nil, fmt.Errorf("team member %d: %w", *member.UserId, err)
+			}
+		}
+
+		userEvals, submitted, err := r.saveTeamUserEvals(team, stepEval, courseId, *userId, utils.Ptr("mark as complete"), utils.Ptr(true), utils.Ptr(""))
+		if err != nil {
+			return nil, err
+		}
+		r.recordAttempted(userEvals...)
+		for _, userEval := range userEvals {
+			r.publishGraded(userEval)
+		}
+
+		return submitted.Id, nil
+	}
+
+	if err := r.EnsureStepUnlocked(stepEval.StepId, courseId, utils.Ptr(float64(*userId))); err != nil {
+		return nil, err
+	}
+
+	userEval, err := r.userEvalRepo.GetUserEvalByStepEvalIdUserId(stepEvalId, courseId, utils.Ptr(float64(*userId)))
+	if err != nil {
+		return nil, err
+	}
+
+	if userEval == nil {
+		userEval = &models.UserEvaluate{
+			UserId:         userId,
+			StepEvaluateId: stepEvalId,
+		}
+	} else if utils.Val(userEval.Pass) {
+		// the step is already complete, marking it again changes nothing
+		return userEval.Id, nil
+	}
+
+	userEval.CourseId = courseId
+	userEval.Pass = utils.Ptr(true)
+	userEval.Comment = utils.Ptr("")
+	userEval.Content = utils.Ptr("mark as complete")
+	userEval.TeamId = nil
+	userEval.Attempts = utils.Ptr(utils.Val(userEval.Attempts) + 1)
+	userEval.SubmittedBy = userId
+	// marking as complete passes right away
+	if err := r.userEvalRepo.SaveUserEvals([]*models.UserEvaluate{userEval}, stepGems(stepEval), nil); err != nil {
+		return nil, err
+	}
+	r.recordAttempted(userEval)
+	r.publishGraded(userEval)
+
+	return userEval.Id, nil
+}
+
+func (r *stepService) GradeUserEval(userEvalId *uint64, graderId uint64, body *payload.GradeUserEval) (*payload.UserEvalResult, error) {
+	userEval, err := r.userEvalRepo.GetUserEvalById(userEvalId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.ensureGrader(graderId, userEval); err != nil {
+		return nil, err
+	}
+
+	stepEval, err := r.stepEvalRepo.GetStepEvalById(userEval.StepEvaluateId)
+	if err != nil {
+		return nil, err
+	}
+
+	// the status check only reports evaluations with both a result and a comment
+	comment := utils.Ptr(utils.Val(body.Comment))
+
+	if userEval.TeamId == nil {
+		userEval.Pass = body.Pass
+		userEval.Comment = comment
+		notifications := r.gradedNotifications(userEval)
+		if err := r.userEvalRepo.SaveUserEvals([]*models.UserEvaluate{userEval}, stepGems(stepEval), notifications); err != nil {
+			return nil, err
+		}
+		if len(notifications) > 0 {
+			r.notificationSvc.Wake()
+		}
+
+		return r.publishGraded(userEval), nil
+	}
+
+	// a team submission is graded for every member, counting for their progress and gems
+	userEvals, err := r.userEvalRepo.FindTeamUserEvals(userEval.StepEvaluateId, userEval.CourseId, userEval.TeamId)
+	if err != nil {
+		return nil, err
+	}
+	for _, teamUserEval := range userEvals {
+		teamUserEval.Pass = body.Pass
+		teamUserEval.Comment = comment
+	}
+	notifications := r.gradedNotifications(userEvals...)
+	if err := r.userEvalRepo.SaveUserEvals(userEvals, stepGems(stepEval), notifications); err != nil {
+		return nil, err
+	}
+	if len(notifications) > 0 {
+		r.notificationSvc.Wake()
+	}
+
+	var result *payload.UserEvalResult
+	for _, teamUserEval := range userEvals {
+		published := r.publishGraded(teamUserEval)
+		if *teamUserEval.Id == *userEval.Id {
+			result = published
+		}
+	}
+
+	return result, nil
+}
+
+// ensureGrader checks the grader is an admin or an instructor of a cohort taking the course the
+// evaluation was submitted in. Evaluations taken outside a course are only graded by admins.
+func (r *stepService) ensureGrader(graderId uint64, userEval *models.UserEvaluate) error {
+	grader, err := r.userRepo.FindUserByID(utils.Ptr(strconv.FormatUint(graderId, 10)))
+	if err != nil {
+		return err
+	}
+	if utils.Val(grader.Role) == "admin" {
+		return nil
+	}
+
+	if userEval.CourseId != nil {
+		instructor, err := r.cohortRepo.IsCourseInstructor(graderId, *userEval.CourseId)
+		if err != nil {
+			return err
+		}
+		if instructor {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("user %d is not an instructor of the course of evaluation %d", graderId, *userEval.Id)
+}
+
+// stepGems returns the gems a passed evaluation of the step evaluation is worth.
+func stepGems(stepEval *models.StepEvaluate) int64 {
+	return int64(utils.Val(stepEval.Gem))
+}
+
+func (r *stepService) publishGraded(userEval *models.UserEvaluate) *payload.UserEvalResult {
+	result := &payload.UserEvalResult{
+		UserEvalId: userEval.Id,
+		Pass:       userEval.Pass,
+		Comment:    userEval.Comment,
+		Content:    userEval.Content,
+		TeamId:     userEval.TeamId,
+	}
+	r.eventHub.Publish(utilServices.UserTopic(*userEval.UserId), &payload.Event{
+		Type: payload.EventSubmissionGraded,
+		Data: result,
+	})
+	if utils.Val(userEval.Pass) {
+		r.achievementSvc.Record(*userEval.UserId, payload.AchievementTriggerSubmissionPassed)
+	}
+	if err := r.xapiSvc.RecordGraded(userEval); err != nil {
+		logrus.Errorf("[XAPI] Unable to record the grade of user evaluation %d: %v", *userEval.Id, err)
+	}
+	// the grade is saved, a certificate failing to be issued is issued again on request
+	if utils.Val(userEval.Pass) && userEval.CourseId != nil {
+		if err := r.certificateSvc.IssueOnCompletion(*userEval.UserId, *userEval.CourseId); err != nil {
+			logrus.Errorf("[CERTIFICATE] Unable to issue the certificate of course %d to user %d: %v", *userEval.CourseId, *userEval.UserId, err)
+		}
+	}
+
+	return result
+}
+
+// recordAttempted states the submission of the evaluations to the LRS. The submission is saved,
+// so a failed statement must not fail it.
+func (r *stepService) recordAttempted(userEvals ...*models.UserEvaluate) {
+	for _, userEval := range userEvals {
+		if err := r.xapiSvc.RecordAttempted(userEval); err != nil {
+			logrus.Errorf("[XAPI] Unable to record the submission of user evaluation %d: %v", *userEval.Id, err)
+		}
+	}
+}
+
+// gradedNotifications renders the emails telling the learners about their grade, stored in the
+// outbox along with it. Failing to render an email must not fail the grade.
+func (r *stepService) gradedNotifications(userEvals ...*models.UserEvaluate) []*models.Notification {
+	notifications := make([]*models.Notification, 0, len(userEvals))
+	for _, userEval := range userEvals {
+		notification, err := r.notificationSvc.SubmissionGraded(userEval)
+		if err != nil {
+			logrus.Errorf("[NOTIFICATION] Unable to notify the grade of user evaluation %d: %v", *userEval.Id, err)
+			continue
+		}
+		if notification != nil {
+			notifications = append(notifications, notification)
+		}
+	}
+
+	return notifications
+}
+
+// publishUpVote tells the author of the comment about the upvote, unless they upvoted themselves.
+func (r *stepService) publishUpVote(stepCommentId *uint64, userId *uint64) error {
+	stepComment, err := r.stepCommentRepo.GetStepCommentById(stepCommentId)
+	if err != nil {
+		return err
+	}
+	if stepComment == nil || *stepComment.UserId == *userId {
+		return nil
+	}
+	r.achievementSvc.Record(*stepComment.UserId, payload.AchievementTriggerUpVoteReceived)
+
+	upVotes, err := r.stepCommentUpVoteRepo.GetStepCommentUpVoteByStepCommentId(stepCommentId)
+	if err != nil {
+		return err
+	}
+
+	r.eventHub.Publish(utilServices.UserTopic(*stepComment.UserId), &payload.Event{
+		Type: payload.EventCommentUpVote,
+		Data: &payload.CommentUpVoteEvent{
+			StepCommentId: stepCommentId,
+			StepId:        stepComment.StepId,
+			UserId:        userId,
+			UpVote:        utils.Ptr(len(upVotes)),
+		},
+	})
+
+	return nil
+}
+
+func userInfo(user *models.User) *payload.UserInfo {
+	return &payload.UserInfo{
+		UserId:    user.Id,
+		FirstName: user.Firstname,
+		LastName:  user.Lastname,
+		Email:     user.Email,
+		PhotoUrl:  user.PhotoUrl,
+	}
 }
@@ -2,8 +2,11 @@ package services
 
 import (
 	"backend/internals/db/models"
+	"backend/internals/entities/payload"
 	"backend/internals/utils"
+	utilServices "backend/internals/utils/services"
 	mockRepositories "backend/mocks/repositories"
+	mockServices "backend/mocks/services"
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -31,6 +34,9 @@ func (suite *StepServiceTestSuite) TestGetGemsWhenSuccess() {
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	mockGemService := new(mockServices.GemService)
+	eventHub := utilServices.NewEventHub()
 
 	mockStepId := utils.Ptr(uint64(2))
 	mockUserId := utils.Ptr(float64(1))
@@ -42,15 +48,23 @@ func (suite *StepServiceTestSuite) TestGetGemsWhenSuccess() {
 		},
 	}
 	mockUserEval := &models.UserEvaluate{
+		Id:   utils.Ptr(uint64(5)),
 		Pass: utils.Ptr(true),
 	}
 
 	mockStepEvalRepo.EXPECT().GetStepEvalByStepId(mock.Anything).Return(mockStepEval, nil)
-	mockUserEvalRepo.EXPECT().GetUserEvalByStepEvalIdUserId(mock.Anything, mock.Anything).Return(mockUserEval, nil)
+	mockUserEvalRepo.EXPECT().GetUserEvalByStepEvalIdUserId(mock.Anything, mock.Anything, mock.Anything).Return(mockUserEval, nil)
+	mockGemService.EXPECT().GetUserEvalGems(uint64(1), []uint64{5}).Return(2, nil)
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	mockStepRepo.EXPECT().GetModuleIdByStepId(mock.Anything).Return(utils.Ptr(uint64(2)), nil).Maybe()
+	mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndModuleId(mock.Anything, mock.Anything).Return(&models.CourseContent{
+		CourseId: utils.Ptr(uint64(1)),
+		Course:   &models.Course{Id: utils.Ptr(uint64(1)), Name: utils.Ptr("Course")},
+	}, nil).Maybe()
 
-	totalGem, currentGem, err := underTest.GetGems(mockStepId, mockUserId)
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, mockGemService, nil, nil, nil, nil, eventHub)
+
+	totalGem, currentGem, err := underTest.GetGems(mockStepId, utils.Ptr(uint64(1)), mockUserId)
 
 	is.Nil(err)
 	is.Equal(2, *totalGem)
@@ -72,6 +86,9 @@ func (suite *StepServiceTestSuite) TestGetGemsWhenPassNil() {
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	mockGemService := new(mockServices.GemService)
+	eventHub := utilServices.NewEventHub()
 
 	mockStepId := utils.Ptr(uint64(2))
 	mockUserId := utils.Ptr(float64(1))
@@ -82,14 +99,21 @@ func (suite *StepServiceTestSuite) TestGetGemsWhenPassNil() {
 			Gem:    utils.Ptr(2),
 		},
 	}
-	mockUserEval := &models.UserEvaluate{}
+	mockUserEval := &models.UserEvaluate{Id: utils.Ptr(uint64(5))}
 
 	mockStepEvalRepo.EXPECT().GetStepEvalByStepId(mock.Anything).Return(mockStepEval, nil)
-	mockUserEvalRepo.EXPECT().GetUserEvalByStepEvalIdUserId(mock.Anything, mock.Anything).Return(mockUserEval, nil)
+	mockUserEvalRepo.EXPECT().GetUserEvalByStepEvalIdUserId(mock.Anything, mock.Anything, mock.Anything).Return(mockUserEval, nil)
+	mockGemService.EXPECT().GetUserEvalGems(uint64(1), []uint64{5}).Return(0, nil)
+
+	mockStepRepo.EXPECT().GetModuleIdByStepId(mock.Anything).Return(utils.Ptr(uint64(2)), nil).Maybe()
+	mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndModuleId(mock.Anything, mock.Anything).Return(&models.CourseContent{
+		CourseId: utils.Ptr(uint64(1)),
+		Course:   &models.Course{Id: utils.Ptr(uint64(1)), Name: utils.Ptr("Course")},
+	}, nil).Maybe()
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, mockGemService, nil, nil, nil, nil, eventHub)
 
-	totalGem, currentGem, err := underTest.GetGems(mockStepId, mockUserId)
+	totalGem, currentGem, err := underTest.GetGems(mockStepId, utils.Ptr(uint64(1)), mockUserId)
 
 	is.Nil(err)
 	is.Equal(2, *totalGem)
@@ -111,6 +135,9 @@ func (suite *StepServiceTestSuite) TestGetGemsWhenUserEvalNil() {
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	mockGemService := new(mockServices.GemService)
+	eventHub := utilServices.NewEventHub()
 
 	mockStepId := utils.Ptr(uint64(2))
 	mockUserId := utils.Ptr(float64(1))
@@ -123,11 +150,18 @@ func (suite *StepServiceTestSuite) TestGetGemsWhenUserEvalNil() {
 	}
 
 	mockStepEvalRepo.EXPECT().GetStepEvalByStepId(mock.Anything).Return(mockStepEval, nil)
-	mockUserEvalRepo.EXPECT().GetUserEvalByStepEvalIdUserId(mock.Anything, mock.Anything).Return(nil, nil)
+	mockUserEvalRepo.EXPECT().GetUserEvalByStepEvalIdUserId(mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	mockGemService.EXPECT().GetUserEvalGems(uint64(1), []uint64{}).Return(0, nil)
+
+	mockStepRepo.EXPECT().GetModuleIdByStepId(mock.Anything).Return(utils.Ptr(uint64(2)), nil).Maybe()
+	mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndModuleId(mock.Anything, mock.Anything).Return(&models.CourseContent{
+		CourseId: utils.Ptr(uint64(1)),
+		Course:   &models.Course{Id: utils.Ptr(uint64(1)), Name: utils.Ptr("Course")},
+	}, nil).Maybe()
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, mockGemService, nil, nil, nil, nil, eventHub)
 
-	totalGem, currentGem, err := underTest.GetGems(mockStepId, mockUserId)
+	totalGem, currentGem, err := underTest.GetGems(mockStepId, utils.Ptr(uint64(1)), mockUserId)
 
 	is.Nil(err)
 	is.Equal(2, *totalGem)
@@ -148,15 +182,23 @@ func (suite *StepServiceTestSuite) TestGetGemsWhenFailedToGetStepEvalByStepId()
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
 
 	mockStepId := utils.Ptr(uint64(2))
 	mockUserId := utils.Ptr(float64(1))
 
 	mockStepEvalRepo.EXPECT().GetStepEvalByStepId(mock.Anything).Return(nil, fmt.Errorf("failed to getStepEval"))
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	mockStepRepo.EXPECT().GetModuleIdByStepId(mock.Anything).Return(utils.Ptr(uint64(2)), nil).Maybe()
+	mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndModuleId(mock.Anything, mock.Anything).Return(&models.CourseContent{
+		CourseId: utils.Ptr(uint64(1)),
+		Course:   &models.Course{Id: utils.Ptr(uint64(1)), Name: utils.Ptr("Course")},
+	}, nil).Maybe()
 
-	totalGem, currentGem, err := underTest.GetGems(mockStepId, mockUserId)
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
+
+	totalGem, currentGem, err := underTest.GetGems(mockStepId, utils.Ptr(uint64(1)), mockUserId)
 
 	is.NotNil(err)
 	is.Nil(totalGem)
@@ -179,6 +221,8 @@ func (suite *StepServiceTestSuite) TestGetGemsWhenFailedToGetUserEval() {
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
 
 	mockStepId := utils.Ptr(uint64(2))
 	mockUserId := utils.Ptr(float64(1))
@@ -190,11 +234,17 @@ func (suite *StepServiceTestSuite) TestGetGemsWhenFailedToGetUserEval() {
 		},
 	}
 	mockStepEvalRepo.EXPECT().GetStepEvalByStepId(mock.Anything).Return(mockStepEval, nil)
-	mockUserEvalRepo.EXPECT().GetUserEvalByStepEvalIdUserId(mock.Anything, mock.Anything).Return(nil, fmt.Errorf("failed to getUserEvalByStepEvalIdUserId"))
+	mockUserEvalRepo.EXPECT().GetUserEvalByStepEvalIdUserId(mock.Anything, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("failed to getUserEvalByStepEvalIdUserId"))
+
+	mockStepRepo.EXPECT().GetModuleIdByStepId(mock.Anything).Return(utils.Ptr(uint64(2)), nil).Maybe()
+	mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndModuleId(mock.Anything, mock.Anything).Return(&models.CourseContent{
+		CourseId: utils.Ptr(uint64(1)),
+		Course:   &models.Course{Id: utils.Ptr(uint64(1)), Name: utils.Ptr("Course")},
+	}, nil).Maybe()
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
 
-	totalGem, currentGem, err := underTest.GetGems(mockStepId, mockUserId)
+	totalGem, currentGem, err := underTest.GetGems(mockStepId, utils.Ptr(uint64(1)), mockUserId)
 
 	is.NotNil(err)
 	is.Nil(totalGem)
@@ -217,6 +267,8 @@ func (suite *StepServiceTestSuite) TestGetStepCommentWhenSuccess() {
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
 
 	mockStepId := utils.Ptr(uint64(2))
 	mockUserId := utils.Ptr(float64(1))
@@ -241,7 +293,7 @@ func (suite *StepServiceTestSuite) TestGetStepCommentWhenSuccess() {
 	mockUserRepo.EXPECT().FindUserByID(mock.Anything).Return(mockUser, nil)
 	mockStepCommentUpVoteRepo.EXPECT().GetStepCommentUpVoteByStepCommentId(mock.Anything).Return(mockStepCommentUpVote, nil)
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
 
 	stepCommentInfo, err := underTest.GetStepComment(mockStepId, utils.Ptr(uint64(*mockUserId)))
 
@@ -264,13 +316,15 @@ func (suite *StepServiceTestSuite) TestGetStepCommentWhenFailedToGetStepCommentB
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
 
 	mockStepId := utils.Ptr(uint64(2))
 	mockUserId := utils.Ptr(float64(1))
 
 	mockStepCommentRepo.EXPECT().GetStepCommentByStepId(mock.Anything).Return(nil, fmt.Errorf("failed to get stepComment by stepId"))
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
 
 	stepCommentInfo, err := underTest.GetStepComment(mockStepId, utils.Ptr(uint64(*mockUserId)))
 
@@ -294,6 +348,8 @@ func (suite *StepServiceTestSuite) TestGetStepCommentWhenFailedToFindUserById()
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
 
 	mockStepId := utils.Ptr(uint64(2))
 	mockUserId := utils.Ptr(float64(1))
@@ -308,7 +364,7 @@ func (suite *StepServiceTestSuite) TestGetStepCommentWhenFailedToFindUserById()
 	mockStepCommentRepo.EXPECT().GetStepCommentByStepId(mock.Anything).Return(mockStepComments, nil)
 	mockUserRepo.EXPECT().FindUserByID(mock.Anything).Return(nil, fmt.Errorf("failed to find user by id"))
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
 
 	stepCommentInfo, err := underTest.GetStepComment(mockStepId, utils.Ptr(uint64(*mockUserId)))
 
@@ -332,6 +388,8 @@ func (suite *StepServiceTestSuite) TestGetStepCommentWhenFailedToGetStepCommentU
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
 
 	mockStepId := utils.Ptr(uint64(2))
 	mockUserId := utils.Ptr(float64(1))
@@ -350,7 +408,7 @@ func (suite *StepServiceTestSuite) TestGetStepCommentWhenFailedToGetStepCommentU
 	mockUserRepo.EXPECT().FindUserByID(mock.Anything).Return(mockUser, nil)
 	mockStepCommentUpVoteRepo.EXPECT().GetStepCommentUpVoteByStepCommentId(mock.Anything).Return(nil, fmt.Errorf("failed to get stepCommentUpvote"))
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
 
 	stepCommentInfo, err := underTest.GetStepComment(mockStepId, utils.Ptr(uint64(*mockUserId)))
 
@@ -373,18 +431,34 @@ func (suite *StepServiceTestSuite) TestCreateStepCommentWhenSuccess() {
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
 
 	mockStepId := utils.Ptr(uint64(2))
 	mockUserId := utils.Ptr(float64(1))
 	mockContent := utils.Ptr("comment")
 
-	mockStepCommentRepo.EXPECT().CreateStepComment(mock.Anything).Return(nil)
+	mockStepCommentRepo.EXPECT().CreateStepComment(mock.Anything, (*models.Notification)(nil)).Return(nil)
+	mockUserRepo.EXPECT().FindUserByID(utils.Ptr("1")).Return(&models.User{Id: utils.Ptr(uint64(1)), Firstname: utils.Ptr("fn")}, nil)
+	events, unsubscribe := eventHub.Subscribe(utilServices.StepTopic(2))
+	defer unsubscribe()
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	mockAchievementService := new(mockServices.AchievementService)
+	mockAchievementService.EXPECT().Record(mock.Anything, payload.AchievementTriggerCommentCreated).Return()
 
-	err := underTest.CreateStpComment(mockStepId, mockUserId, mockContent)
+	mockXapiService := new(mockServices.XapiService)
+	mockXapiService.EXPECT().RecordCommented(mock.Anything).Return(nil)
+
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, mockAchievementService, nil, mockXapiService, nil, eventHub)
+
+	err := underTest.CreateStpComment(mockStepId, mockUserId, mockContent, nil)
 
 	is.Nil(err)
+	event := <-events
+	is.Equal(payload.EventStepComment, event.Type)
+	is.Equal("comment", *event.Data.(*payload.StepCommentEvent).Comment)
+	mockAchievementService.AssertExpectations(suite.T())
+	mockXapiService.AssertExpectations(suite.T())
 }
 
 func (suite *StepServiceTestSuite) TestCreateStepCommentWhenFailedToCreateComment() {
@@ -402,16 +476,19 @@ func (suite *StepServiceTestSuite) TestCreateStepCommentWhenFailedToCreateCommen
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
 
 	mockStepId := utils.Ptr(uint64(2))
 	mockUserId := utils.Ptr(float64(1))
 	mockContent := utils.Ptr("comment")
 
-	mockStepCommentRepo.EXPECT().CreateStepComment(mock.Anything).Return(fmt.Errorf("failed to create comment"))
+	mockUserRepo.EXPECT().FindUserByID(utils.Ptr("1")).Return(&models.User{Id: utils.Ptr(uint64(1)), Firstname: utils.Ptr("fn")}, nil)
+	mockStepCommentRepo.EXPECT().CreateStepComment(mock.Anything, (*models.Notification)(nil)).Return(fmt.Errorf("failed to create comment"))
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
 
-	err := underTest.CreateStpComment(mockStepId, mockUserId, mockContent)
+	err := underTest.CreateStpComment(mockStepId, mockUserId, mockContent, nil)
 
 	is.NotNil(err)
 	is.Equal("failed to create comment", err.Error())
@@ -432,18 +509,32 @@ func (suite *StepServiceTestSuite) TestCreateOrDeleteStepCommentUpVoteWhenNoExit
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
 
 	mockUserId := utils.Ptr(float64(1))
 	mockStepCommentId := utils.Ptr(uint64(1))
 
 	mockStepCommentUpVoteRepo.EXPECT().GetStepCommentUpVoteByStepCommentIdAndUserId(mock.Anything, mock.Anything).Return(nil, nil)
 	mockStepCommentUpVoteRepo.EXPECT().CreateStepCommentUpVote(mock.Anything).Return(nil)
+	mockStepCommentRepo.EXPECT().GetStepCommentById(mockStepCommentId).Return(&models.StepComment{Id: mockStepCommentId, StepId: utils.Ptr(uint64(2)), UserId: utils.Ptr(uint64(5))}, nil)
+	mockStepCommentUpVoteRepo.EXPECT().GetStepCommentUpVoteByStepCommentId(mockStepCommentId).Return([]*models.StepCommentUpvote{{}, {}}, nil)
+	events, unsubscribe := eventHub.Subscribe(utilServices.UserTopic(5))
+	defer unsubscribe()
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	mockAchievementService := new(mockServices.AchievementService)
+	mockAchievementService.EXPECT().Record(mock.Anything, payload.AchievementTriggerUpVoteReceived).Return()
+
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, mockAchievementService, nil, nil, nil, eventHub)
 
 	err := underTest.CreateOrDeleteStepCommentUpVote(mockUserId, mockStepCommentId)
 
 	is.Nil(err)
+	event := <-events
+	is.Equal(payload.EventCommentUpVote, event.Type)
+	is.Equal(2, *event.Data.(*payload.CommentUpVoteEvent).UpVote)
+	is.Equal(uint64(1), *event.Data.(*payload.CommentUpVoteEvent).UserId)
+	mockAchievementService.AssertExpectations(suite.T())
 }
 
 func (suite *StepServiceTestSuite) TestCreateOrDeleteStepCommentUpVoteWhenNoExitUpVoteAndFailedToGetExistStepComment() {
@@ -461,13 +552,15 @@ func (suite *StepServiceTestSuite) TestCreateOrDeleteStepCommentUpVoteWhenNoExit
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
 
 	mockUserId := utils.Ptr(float64(1))
 	mockStepCommentId := utils.Ptr(uint64(1))
 
 	mockStepCommentUpVoteRepo.EXPECT().GetStepCommentUpVoteByStepCommentIdAndUserId(mock.Anything, mock.Anything).Return(nil, fmt.Errorf("failed to get stepCommentUpVote"))
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
 
 	err := underTest.CreateOrDeleteStepCommentUpVote(mockUserId, mockStepCommentId)
 
@@ -490,6 +583,8 @@ func (suite *StepServiceTestSuite) TestCreateOrDeleteStepCommentUpVoteWhenNoExit
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
 
 	mockUserId := utils.Ptr(float64(1))
 	mockStepCommentId := utils.Ptr(uint64(1))
@@ -497,7 +592,7 @@ func (suite *StepServiceTestSuite) TestCreateOrDeleteStepCommentUpVoteWhenNoExit
 	mockStepCommentUpVoteRepo.EXPECT().GetStepCommentUpVoteByStepCommentIdAndUserId(mock.Anything, mock.Anything).Return(nil, nil)
 	mockStepCommentUpVoteRepo.EXPECT().CreateStepCommentUpVote(mock.Anything).Return(fmt.Errorf("failed to create comment"))
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
 
 	err := underTest.CreateOrDeleteStepCommentUpVote(mockUserId, mockStepCommentId)
 
@@ -520,6 +615,8 @@ func (suite *StepServiceTestSuite) TestCreateOrDeleteStepCommentUpVoteWhenHaveEx
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
 
 	mockUserId := utils.Ptr(float64(1))
 	mockStepCommentId := utils.Ptr(uint64(1))
@@ -530,7 +627,7 @@ func (suite *StepServiceTestSuite) TestCreateOrDeleteStepCommentUpVoteWhenHaveEx
 	mockStepCommentUpVoteRepo.EXPECT().GetStepCommentUpVoteByStepCommentIdAndUserId(mock.Anything, mock.Anything).Return(mockStepCommentUpVote, nil)
 	mockStepCommentUpVoteRepo.EXPECT().DeleteStepCommentUpVote(mock.Anything, mock.Anything).Return(nil)
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
 
 	err := underTest.CreateOrDeleteStepCommentUpVote(mockUserId, mockStepCommentId)
 
@@ -552,6 +649,8 @@ func (suite *StepServiceTestSuite) TestCreateOrDeleteStepCommentUpVoteWhenHaveEx
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
 
 	mockUserId := utils.Ptr(float64(1))
 	mockStepCommentId := utils.Ptr(uint64(1))
@@ -562,7 +661,7 @@ func (suite *StepServiceTestSuite) TestCreateOrDeleteStepCommentUpVoteWhenHaveEx
 	mockStepCommentUpVoteRepo.EXPECT().GetStepCommentUpVoteByStepCommentIdAndUserId(mock.Anything, mock.Anything).Return(mockStepCommentUpVote, nil)
 	mockStepCommentUpVoteRepo.EXPECT().DeleteStepCommentUpVote(mock.Anything, mock.Anything).Return(fmt.Errorf("failed to delete comment"))
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
 
 	err := underTest.CreateOrDeleteStepCommentUpVote(mockUserId, mockStepCommentId)
 
@@ -585,6 +684,9 @@ func (suite *StepServiceTestSuite) TestGetStepEvalInfoTypeTextWhenSuccess() {
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
+	mockCohortRepo.EXPECT().FindLearnerCohort(mock.Anything, mock.Anything).Return(nil, nil).Maybe()
 
 	mockUserId := utils.Ptr(float64(1))
 	mockStepId := utils.Ptr(uint64(1))
@@ -614,11 +716,17 @@ func (suite *StepServiceTestSuite) TestGetStepEvalInfoTypeTextWhenSuccess() {
 	}
 
 	mockStepEvalRepo.EXPECT().GetStepEvalByStepId(mock.Anything).Return(mockStepEvals, nil)
-	mockUserEvalRepo.EXPECT().GetUserEvalByStepEvalIdUserId(mock.Anything, mock.Anything).Return(mockUserEval, nil)
+	mockUserEvalRepo.EXPECT().GetUserEvalByStepEvalIdUserId(mock.Anything, mock.Anything, mock.Anything).Return(mockUserEval, nil)
+
+	mockStepRepo.EXPECT().GetModuleIdByStepId(mock.Anything).Return(utils.Ptr(uint64(2)), nil).Maybe()
+	mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndModuleId(mock.Anything, mock.Anything).Return(&models.CourseContent{
+		CourseId: utils.Ptr(uint64(1)),
+		Course:   &models.Course{Id: utils.Ptr(uint64(1)), Name: utils.Ptr("Course")},
+	}, nil).Maybe()
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
 
-	stepEvals, err := underTest.GetStepEvalInfo(mockStepId, mockUserId)
+	stepEvals, err := underTest.GetStepEvalInfo(mockStepId, utils.Ptr(uint64(1)), mockUserId)
 
 	is.Nil(err)
 	is.NotNil(stepEvals)
@@ -639,6 +747,9 @@ func (suite *StepServiceTestSuite) TestGetStepEvalInfoTypeImageWhenSuccess() {
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
+	mockCohortRepo.EXPECT().FindLearnerCohort(mock.Anything, mock.Anything).Return(nil, nil).Maybe()
 
 	mockUserId := utils.Ptr(float64(1))
 	mockStepId := utils.Ptr(uint64(1))
@@ -668,11 +779,17 @@ func (suite *StepServiceTestSuite) TestGetStepEvalInfoTypeImageWhenSuccess() {
 	}
 
 	mockStepEvalRepo.EXPECT().GetStepEvalByStepId(mock.Anything).Return(mockStepEvals, nil)
-	mockUserEvalRepo.EXPECT().GetUserEvalByStepEvalIdUserId(mock.Anything, mock.Anything).Return(mockUserEval, nil)
+	mockUserEvalRepo.EXPECT().GetUserEvalByStepEvalIdUserId(mock.Anything, mock.Anything, mock.Anything).Return(mockUserEval, nil)
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	mockStepRepo.EXPECT().GetModuleIdByStepId(mock.Anything).Return(utils.Ptr(uint64(2)), nil).Maybe()
+	mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndModuleId(mock.Anything, mock.Anything).Return(&models.CourseContent{
+		CourseId: utils.Ptr(uint64(1)),
+		Course:   &models.Course{Id: utils.Ptr(uint64(1)), Name: utils.Ptr("Course")},
+	}, nil).Maybe()
 
-	stepEvals, err := underTest.GetStepEvalInfo(mockStepId, mockUserId)
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
+
+	stepEvals, err := underTest.GetStepEvalInfo(mockStepId, utils.Ptr(uint64(1)), mockUserId)
 
 	is.Nil(err)
 	is.NotNil(stepEvals)
@@ -693,15 +810,24 @@ func (suite *StepServiceTestSuite) TestGetStepEvalInfoWhenFailedToGetStepEval()
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
+	mockCohortRepo.EXPECT().FindLearnerCohort(mock.Anything, mock.Anything).Return(nil, nil).Maybe()
 
 	mockUserId := utils.Ptr(float64(1))
 	mockStepId := utils.Ptr(uint64(1))
 
 	mockStepEvalRepo.EXPECT().GetStepEvalByStepId(mock.Anything).Return(nil, fmt.Errorf("failed to get step eval"))
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	mockStepRepo.EXPECT().GetModuleIdByStepId(mock.Anything).Return(utils.Ptr(uint64(2)), nil).Maybe()
+	mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndModuleId(mock.Anything, mock.Anything).Return(&models.CourseContent{
+		CourseId: utils.Ptr(uint64(1)),
+		Course:   &models.Course{Id: utils.Ptr(uint64(1)), Name: utils.Ptr("Course")},
+	}, nil).Maybe()
+
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
 
-	stepEvals, err := underTest.GetStepEvalInfo(mockStepId, mockUserId)
+	stepEvals, err := underTest.GetStepEvalInfo(mockStepId, utils.Ptr(uint64(1)), mockUserId)
 
 	is.NotNil(err)
 	is.Nil(stepEvals)
@@ -723,6 +849,9 @@ func (suite *StepServiceTestSuite) TestGetStepEvalInfoTypeImageWhenFailedToGetUs
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
+	mockCohortRepo.EXPECT().FindLearnerCohort(mock.Anything, mock.Anything).Return(nil, nil).Maybe()
 
 	mockUserId := utils.Ptr(float64(1))
 	mockStepId := utils.Ptr(uint64(1))
@@ -745,11 +874,17 @@ func (suite *StepServiceTestSuite) TestGetStepEvalInfoTypeImageWhenFailedToGetUs
 	}
 
 	mockStepEvalRepo.EXPECT().GetStepEvalByStepId(mock.Anything).Return(mockStepEvals, nil)
-	mockUserEvalRepo.EXPECT().GetUserEvalByStepEvalIdUserId(mock.Anything, mock.Anything).Return(nil, fmt.Errorf("failed to get user eval"))
+	mockUserEvalRepo.EXPECT().GetUserEvalByStepEvalIdUserId(mock.Anything, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("failed to get user eval"))
+
+	mockStepRepo.EXPECT().GetModuleIdByStepId(mock.Anything).Return(utils.Ptr(uint64(2)), nil).Maybe()
+	mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndModuleId(mock.Anything, mock.Anything).Return(&models.CourseContent{
+		CourseId: utils.Ptr(uint64(1)),
+		Course:   &models.Course{Id: utils.Ptr(uint64(1)), Name: utils.Ptr("Course")},
+	}, nil).Maybe()
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
 
-	stepEvals, err := underTest.GetStepEvalInfo(mockStepId, mockUserId)
+	stepEvals, err := underTest.GetStepEvalInfo(mockStepId, utils.Ptr(uint64(1)), mockUserId)
 
 	is.NotNil(err)
 	is.Nil(stepEvals)
@@ -771,19 +906,19 @@ func (suite *StepServiceTestSuite) TestCreateFileFormatWhenSuccess() {
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
 
 	mockUserId := utils.Ptr(float64(1))
 	mockStepId := utils.Ptr(uint64(1))
 	mockStepEvalId := utils.Ptr(uint64(1))
 	mockModuleId := utils.Ptr(uint64(2))
-	mockCourseId := utils.Ptr(uint64(1))
 
 	mockStepRepo.EXPECT().GetModuleIdByStepId(mock.Anything).Return(mockModuleId, nil)
-	mockCourseContentRepo.EXPECT().GetCourseIdByModuleId(mock.Anything).Return(mockCourseId, nil)
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
 
-	filename, err := underTest.CreateFileFormat(mockStepId, mockStepEvalId, mockUserId)
+	filename, err := underTest.CreateFileFormat(utils.Ptr(uint64(1)), mockStepId, mockStepEvalId, mockUserId)
 
 	is.Nil(err)
 	is.NotNil(filename)
@@ -804,6 +939,8 @@ func (suite *StepServiceTestSuite) TestCreateFileFormatWhenFailedToGetModule() {
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
 
 	mockUserId := utils.Ptr(float64(1))
 	mockStepId := utils.Ptr(uint64(1))
@@ -811,16 +948,16 @@ func (suite *StepServiceTestSuite) TestCreateFileFormatWhenFailedToGetModule() {
 
 	mockStepRepo.EXPECT().GetModuleIdByStepId(mock.Anything).Return(nil, fmt.Errorf("failed to get moduleId"))
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
 
-	filename, err := underTest.CreateFileFormat(mockStepId, mockStepEvalId, mockUserId)
+	filename, err := underTest.CreateFileFormat(utils.Ptr(uint64(1)), mockStepId, mockStepEvalId, mockUserId)
 
 	is.NotNil(err)
 	is.Nil(filename)
 	is.Equal("failed to get moduleId", err.Error())
 }
 
-func (suite *StepServiceTestSuite) TestCreateFileFormatWhenFailedToGetCourse() {
+func (suite *StepServiceTestSuite) TestResolveCourseIdWhenModuleInSingleCourse() {
 	is := assert.New(suite.T())
 
 	mockStepRepo := new(mockRepositories.StepRepository)
@@ -835,22 +972,160 @@ func (suite *StepServiceTestSuite) TestCreateFileFormatWhenFailedToGetCourse() {
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
 
-	mockUserId := utils.Ptr(float64(1))
 	mockStepId := utils.Ptr(uint64(1))
-	mockStepEvalId := utils.Ptr(uint64(1))
 	mockModuleId := utils.Ptr(uint64(2))
 
-	mockStepRepo.EXPECT().GetModuleIdByStepId(mock.Anything).Return(mockModuleId, nil)
-	mockCourseContentRepo.EXPECT().GetCourseIdByModuleId(mock.Anything).Return(nil, fmt.Errorf("failed to get courseId"))
+	mockStepRepo.EXPECT().GetModuleIdByStepId(mockStepId).Return(mockModuleId, nil)
+	mockCourseContentRepo.EXPECT().GetCourseIdsByModuleId(mockModuleId).Return([]uint64{4}, nil)
+	mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndModuleId(utils.Ptr(uint64(4)), mockModuleId).Return(&models.CourseContent{CourseId: utils.Ptr(uint64(4))}, nil)
+
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
+
+	courseId, err := underTest.ResolveCourseId(mockStepId, nil)
+
+	is.Nil(err)
+	is.Equal(uint64(4), *courseId)
+}
+
+func (suite *StepServiceTestSuite) TestResolveCourseIdWhenModuleSharedWithoutCourse() {
+	is := assert.New(suite.T())
+
+	mockStepRepo := new(mockRepositories.StepRepository)
+	mockStepEvalRepo := new(mockRepositories.StepEvaluateRepository)
+	mockStepCommentRepo := new(mockRepositories.StepCommentRepository)
+	mockStepCommentUpVoteRepo := new(mockRepositories.StepCommentUpVoteRepository)
+	mockStepAuthorRepo := new(mockRepositories.StepAuthorRepository)
+
+	mockUserRepo := new(mockRepositories.UserRepository)
+	mockUserEvalRepo := new(mockRepositories.UserEvaluateRepository)
+
+	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
+
+	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
+
+	mockStepId := utils.Ptr(uint64(1))
+	mockModuleId := utils.Ptr(uint64(2))
+
+	mockStepRepo.EXPECT().GetModuleIdByStepId(mockStepId).Return(mockModuleId, nil)
+	mockCourseContentRepo.EXPECT().GetCourseIdsByModuleId(mockModuleId).Return([]uint64{4, 5}, nil)
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
 
-	filename, err := underTest.CreateFileFormat(mockStepId, mockStepEvalId, mockUserId)
+	courseId, err := underTest.ResolveCourseId(mockStepId, nil)
 
 	is.NotNil(err)
-	is.Nil(filename)
-	is.Equal("failed to get courseId", err.Error())
+	is.Nil(courseId)
+	is.Equal("module 2 is shared by several courses, courseId is required", err.Error())
+}
+
+func (suite *StepServiceTestSuite) TestResolveCourseIdWhenModuleNotInCourse() {
+	is := assert.New(suite.T())
+
+	mockStepRepo := new(mockRepositories.StepRepository)
+	mockStepEvalRepo := new(mockRepositories.StepEvaluateRepository)
+	mockStepCommentRepo := new(mockRepositories.StepCommentRepository)
+	mockStepCommentUpVoteRepo := new(mockRepositories.StepCommentUpVoteRepository)
+	mockStepAuthorRepo := new(mockRepositories.StepAuthorRepository)
+
+	mockUserRepo := new(mockRepositories.UserRepository)
+	mockUserEvalRepo := new(mockRepositories.UserEvaluateRepository)
+
+	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
+
+	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
+
+	mockStepId := utils.Ptr(uint64(1))
+	mockModuleId := utils.Ptr(uint64(2))
+
+	mockStepRepo.EXPECT().GetModuleIdByStepId(mockStepId).Return(mockModuleId, nil)
+	mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndModuleId(utils.Ptr(uint64(7)), mockModuleId).Return(nil, nil)
+
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
+
+	courseId, err := underTest.ResolveCourseId(mockStepId, utils.Ptr(uint64(7)))
+
+	is.NotNil(err)
+	is.Nil(courseId)
+	is.Equal("module 2 is not part of course 7", err.Error())
+}
+
+func (suite *StepServiceTestSuite) TestEnsureStepUnlockedWhenModuleAfterUnlock() {
+	is := assert.New(suite.T())
+
+	mockStepRepo := new(mockRepositories.StepRepository)
+	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
+
+	mockStepId := utils.Ptr(uint64(1))
+	mockModuleId := utils.Ptr(uint64(2))
+
+	mockCohortRepo.EXPECT().FindLearnerCohort(uint64(9), uint64(7)).Return(&models.Cohort{UnlockedOrder: utils.Ptr(int64(1))}, nil)
+	mockStepRepo.EXPECT().GetStepById(mockStepId).Return(&models.Step{Id: mockStepId, ModuleId: mockModuleId}, nil)
+	mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndModuleId(utils.Ptr(uint64(7)), mockModuleId).Return(&models.CourseContent{Order: utils.Ptr(int64(2))}, nil)
+
+	underTest := NewStepService(mockStepRepo, nil, nil, nil, nil, nil, nil, mockCourseContentRepo, nil, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
+
+	err := underTest.EnsureStepUnlocked(mockStepId, utils.Ptr(uint64(7)), utils.Ptr(float64(9)))
+
+	is.NotNil(err)
+	is.Equal("step 1 is locked until the cohort instructor unlocks it", err.Error())
+}
+
+func (suite *StepServiceTestSuite) TestEnsureStepUnlockedWhenWithoutCohort() {
+	is := assert.New(suite.T())
+
+	mockStepRepo := new(mockRepositories.StepRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
+
+	mockCohortRepo.EXPECT().FindLearnerCohort(uint64(9), uint64(7)).Return(nil, nil)
+
+	underTest := NewStepService(mockStepRepo, nil, nil, nil, nil, nil, nil, nil, nil, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
+
+	err := underTest.EnsureStepUnlocked(utils.Ptr(uint64(1)), utils.Ptr(uint64(7)), utils.Ptr(float64(9)))
+
+	is.Nil(err)
+	mockStepRepo.AssertNotCalled(suite.T(), "GetStepById", mock.Anything)
+}
+
+func (suite *StepServiceTestSuite) TestEnsureStepUnlockedWithinUnlockedModule() {
+	is := assert.New(suite.T())
+
+	mockStepRepo := new(mockRepositories.StepRepository)
+	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
+
+	mockModuleId := utils.Ptr(uint64(2))
+	moduleSteps := []*models.Step{
+		{Id: utils.Ptr(uint64(4)), ModuleId: mockModuleId},
+		{Id: utils.Ptr(uint64(5)), ModuleId: mockModuleId},
+		{Id: utils.Ptr(uint64(6)), ModuleId: mockModuleId},
+	}
+
+	mockCohortRepo.EXPECT().FindLearnerCohort(uint64(9), uint64(7)).Return(&models.Cohort{
+		UnlockedOrder:  utils.Ptr(int64(2)),
+		UnlockedStepId: utils.Ptr(uint64(5)),
+	}, nil)
+	mockStepRepo.EXPECT().GetStepById(mock.Anything).RunAndReturn(func(stepId *uint64) (*models.Step, error) {
+		return &models.Step{Id: stepId, ModuleId: mockModuleId}, nil
+	})
+	mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndModuleId(utils.Ptr(uint64(7)), mockModuleId).Return(&models.CourseContent{Order: utils.Ptr(int64(2))}, nil)
+	mockStepRepo.EXPECT().FindStepsByModuleID(utils.Ptr("2")).Return(moduleSteps, nil)
+
+	underTest := NewStepService(mockStepRepo, nil, nil, nil, nil, nil, nil, mockCourseContentRepo, nil, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
+
+	is.Nil(underTest.EnsureStepUnlocked(utils.Ptr(uint64(4)), utils.Ptr(uint64(7)), utils.Ptr(float64(9))))
+	is.Nil(underTest.EnsureStepUnlocked(utils.Ptr(uint64(5)), utils.Ptr(uint64(7)), utils.Ptr(float64(9))))
+	is.NotNil(underTest.EnsureStepUnlocked(utils.Ptr(uint64(6)), utils.Ptr(uint64(7)), utils.Ptr(float64(9))))
 }
 
 //func (suite *StepServiceTestSuite) TestCreateUserEvalWhenSuccess() {
@@ -868,6 +1143,8 @@ func (suite *StepServiceTestSuite) TestCreateFileFormatWhenFailedToGetCourse() {
 //	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 //
 //	mockModuleRepo := new(mockRepositories.ModulesRepository)
+//	mockCohortRepo := new(mockRepositories.CohortRepository)
+//	eventHub := utilServices.NewEventHub()
 //
 //	mockPayload := &payload.CreateUserEvalReq{
 //		UserId:     utils.Ptr(float64(1)),
@@ -881,7 +1158,7 @@ func (suite *StepServiceTestSuite) TestCreateFileFormatWhenFailedToGetCourse() {
 //
 //	mockUserEvalRepo.EXPECT().CreateUserEval(mock.Anything).Return(mockCreatedUserEval, nil)
 //
-//	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+//	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, eventHub)
 //
 //	userEvalId, err := underTest.CreateUserEval(mockPayload)
 //
@@ -905,6 +1182,8 @@ func (suite *StepServiceTestSuite) TestCreateFileFormatWhenFailedToGetCourse() {
 //	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 //
 //	mockModuleRepo := new(mockRepositories.ModulesRepository)
+//	mockCohortRepo := new(mockRepositories.CohortRepository)
+//	eventHub := utilServices.NewEventHub()
 //
 //	mockPayload := &payload.CreateUserEvalReq{
 //		UserId:     utils.Ptr(float64(1)),
@@ -914,7 +1193,7 @@ func (suite *StepServiceTestSuite) TestCreateFileFormatWhenFailedToGetCourse() {
 //
 //	mockUserEvalRepo.EXPECT().CreateUserEval(mock.Anything).Return(nil, fmt.Errorf("failed to create user eval"))
 //
-//	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+//	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, eventHub)
 //
 //	userEvalId, err := underTest.CreateUserEval(mockPayload)
 //
@@ -938,6 +1217,8 @@ func (suite *StepServiceTestSuite) TestCheckStepEvalStatusWhenSuccess() {
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
 
 	mockUserEvalId := utils.Ptr(uint64(12))
 	mockUserId := utils.Ptr(uint64(1))
@@ -950,7 +1231,7 @@ func (suite *StepServiceTestSuite) TestCheckStepEvalStatusWhenSuccess() {
 
 	mockUserEvalRepo.EXPECT().GetUserEvalByIdAndUserId(mock.Anything, mock.Anything).Return(mockUserEval, nil)
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
 
 	userEvalResult, err := underTest.CheckStepEvalStatus(mockUserEvalId, mockUserId)
 
@@ -973,13 +1254,15 @@ func (suite *StepServiceTestSuite) TestCheckStepEvalStatusWhenFailedToGetUserEva
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
 
 	mockUserEvalId := utils.Ptr(uint64(12))
 	mockUserId := utils.Ptr(uint64(1))
 
 	mockUserEvalRepo.EXPECT().GetUserEvalByIdAndUserId(mock.Anything, mock.Anything).Return(nil, fmt.Errorf("failed to get user eval"))
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
 
 	userEvalResult, err := underTest.CheckStepEvalStatus(mockUserEvalId, mockUserId)
 
@@ -1003,13 +1286,15 @@ func (suite *StepServiceTestSuite) TestCheckStepEvalStatusWhenUserEvalNil() {
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
 
 	mockUserEvalId := utils.Ptr(uint64(12))
 	mockUserId := utils.Ptr(uint64(1))
 
 	mockUserEvalRepo.EXPECT().GetUserEvalByIdAndUserId(mock.Anything, mock.Anything).Return(nil, nil)
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
 
 	userEvalResult, err := underTest.CheckStepEvalStatus(mockUserEvalId, mockUserId)
 
@@ -1032,6 +1317,8 @@ func (suite *StepServiceTestSuite) TestCheckStepEvalStatusWhenPassNilCommentNil(
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
 
 	mockUserEvalId := utils.Ptr(uint64(12))
 	mockUserId := utils.Ptr(uint64(1))
@@ -1042,7 +1329,7 @@ func (suite *StepServiceTestSuite) TestCheckStepEvalStatusWhenPassNilCommentNil(
 
 	mockUserEvalRepo.EXPECT().GetUserEvalByIdAndUserId(mock.Anything, mock.Anything).Return(mockUserEval, nil)
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
 
 	userEvalResult, err := underTest.CheckStepEvalStatus(mockUserEvalId, mockUserId)
 
@@ -1065,6 +1352,9 @@ func (suite *StepServiceTestSuite) TestSubmitStepEvalTypeCheckWhenSuccess() {
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
+	mockCohortRepo.EXPECT().FindLearnerCohort(mock.Anything, mock.Anything).Return(nil, nil).Maybe()
 
 	mockStepEvalId := utils.Ptr(uint64(12))
 	mockUserId := utils.Ptr(uint64(1))
@@ -1072,15 +1362,48 @@ func (suite *StepServiceTestSuite) TestSubmitStepEvalTypeCheckWhenSuccess() {
 		Id: utils.Ptr(uint64(1)),
 	}
 
-	mockUserEvalRepo.EXPECT().CreateUserEval(mock.Anything).Return(mockUserEval, nil)
+	mockStepEvalRepo.EXPECT().GetStepEvalById(mock.Anything).Return(&models.StepEvaluate{Id: mockStepEvalId, StepId: utils.Ptr(uint64(3)), Type: utils.Ptr("check")}, nil)
+	mockUserEvalRepo.EXPECT().GetUserEvalByStepEvalIdUserId(mockStepEvalId, utils.Ptr(uint64(1)), utils.Ptr(float64(1))).Return(nil, nil)
+	mockUserEvalRepo.EXPECT().SaveUserEvals(mock.MatchedBy(func(userEvals []*models.UserEvaluate) bool {
+		return len(userEvals) == 1 && userEvals[0].Id == nil && *userEvals[0].Pass && *userEvals[0].Attempts == 1
+	}), int64(0), []*models.Notification(nil)).RunAndReturn(func(userEvals []*models.UserEvaluate, gems int64, notifications []*models.Notification) error {
+		userEvals[0].Id = mockUserEval.Id
+		return nil
+	})
+
+	mockStepRepo.EXPECT().GetModuleIdByStepId(mock.Anything).Return(utils.Ptr(uint64(2)), nil).Maybe()
+	mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndModuleId(mock.Anything, mock.Anything).Return(&models.CourseContent{
+		CourseId: utils.Ptr(uint64(1)),
+		Course:   &models.Course{Id: utils.Ptr(uint64(1)), Name: utils.Ptr("Course")},
+	}, nil).Maybe()
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	mockAchievementService := new(mockServices.AchievementService)
+	mockAchievementService.EXPECT().Record(mock.Anything, payload.AchievementTriggerSubmissionPassed).Return()
 
-	userEvalId, err := underTest.SubmitStepEvalTypeCheck(mockStepEvalId, mockUserId)
+	mockXapiService := new(mockServices.XapiService)
+	mockXapiService.EXPECT().RecordAttempted(mock.Anything).Return(nil)
+	mockXapiService.EXPECT().RecordGraded(mock.Anything).Return(nil)
+
+	// marking the last step of the course as complete certifies it
+	mockCertificateService := new(mockServices.CertificateService)
+	mockCertificateService.EXPECT().IssueOnCompletion(uint64(1), uint64(1)).Return(nil)
+
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, mockAchievementService, nil, mockXapiService, mockCertificateService, eventHub)
+
+	events, unsubscribe := eventHub.Subscribe(utilServices.UserTopic(1))
+	defer unsubscribe()
+
+	userEvalId, err := underTest.SubmitStepEvalTypeCheck(mockStepEvalId, utils.Ptr(uint64(1)), mockUserId)
 
 	is.Nil(err)
 	is.NotNil(userEvalId)
 	is.Equal(uint64(1), *userEvalId)
+	event := <-events
+	is.Equal(payload.EventSubmissionGraded, event.Type)
+	is.True(*event.Data.(*payload.UserEvalResult).Pass)
+	mockAchievementService.AssertExpectations(suite.T())
+	mockXapiService.AssertExpectations(suite.T())
+	mockCertificateService.AssertExpectations(suite.T())
 }
 
 func (suite *StepServiceTestSuite) TestSubmitStepEvalTypeCheckWhenFailedToCreateUserEval() {
@@ -1098,21 +1421,107 @@ func (suite *StepServiceTestSuite) TestSubmitStepEvalTypeCheckWhenFailedToCreate
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
+	mockCohortRepo.EXPECT().FindLearnerCohort(mock.Anything, mock.Anything).Return(nil, nil).Maybe()
 
 	mockStepEvalId := utils.Ptr(uint64(12))
 	mockUserId := utils.Ptr(uint64(1))
 
-	mockUserEvalRepo.EXPECT().CreateUserEval(mock.Anything).Return(nil, fmt.Errorf("failed to create user eval"))
+	mockStepEvalRepo.EXPECT().GetStepEvalById(mock.Anything).Return(&models.StepEvaluate{Id: mockStepEvalId, StepId: utils.Ptr(uint64(3)), Type: utils.Ptr("check")}, nil)
+	mockUserEvalRepo.EXPECT().GetUserEvalByStepEvalIdUserId(mockStepEvalId, utils.Ptr(uint64(1)), utils.Ptr(float64(1))).Return(nil, nil)
+	mockUserEvalRepo.EXPECT().SaveUserEvals(mock.Anything, int64(0), []*models.Notification(nil)).Return(fmt.Errorf("failed to create user eval"))
+
+	mockStepRepo.EXPECT().GetModuleIdByStepId(mock.Anything).Return(utils.Ptr(uint64(2)), nil).Maybe()
+	mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndModuleId(mock.Anything, mock.Anything).Return(&models.CourseContent{
+		CourseId: utils.Ptr(uint64(1)),
+		Course:   &models.Course{Id: utils.Ptr(uint64(1)), Name: utils.Ptr("Course")},
+	}, nil).Maybe()
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
 
-	userEvalId, err := underTest.SubmitStepEvalTypeCheck(mockStepEvalId, mockUserId)
+	userEvalId, err := underTest.SubmitStepEvalTypeCheck(mockStepEvalId, utils.Ptr(uint64(1)), mockUserId)
 
 	is.NotNil(err)
 	is.Nil(userEvalId)
 	is.Equal("failed to create user eval", err.Error())
 }
 
+func (suite *StepServiceTestSuite) TestSubmitStepEvalTypeCheckWhenMarkedBefore() {
+	is := assert.New(suite.T())
+
+	mockStepEvalRepo := new(mockRepositories.StepEvaluateRepository)
+	mockUserEvalRepo := new(mockRepositories.UserEvaluateRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+
+	mockStepEvalId := utils.Ptr(uint64(12))
+	mockStepEvalRepo.EXPECT().GetStepEvalById(mockStepEvalId).Return(&models.StepEvaluate{Id: mockStepEvalId, StepId: utils.Ptr(uint64(3)), Type: utils.Ptr("check"), Gem: utils.Ptr(3)}, nil)
+	mockCohortRepo.EXPECT().FindLearnerCohort(uint64(1), uint64(7)).Return(nil, nil)
+	mockStepRepo := new(mockRepositories.StepRepository)
+	mockStepRepo.EXPECT().GetModuleIdByStepId(utils.Ptr(uint64(3))).Return(utils.Ptr(uint64(2)), nil)
+	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
+	mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndModuleId(utils.Ptr(uint64(7)), utils.Ptr(uint64(2))).Return(&models.CourseContent{CourseId: utils.Ptr(uint64(7))}, nil)
+	mockUserEvalRepo.EXPECT().GetUserEvalByStepEvalIdUserId(mockStepEvalId, utils.Ptr(uint64(7)), utils.Ptr(float64(1))).Return(&models.UserEvaluate{
+		Id:             utils.Ptr(uint64(30)),
+		UserId:         utils.Ptr(uint64(1)),
+		StepEvaluateId: mockStepEvalId,
+		CourseId:       utils.Ptr(uint64(7)),
+		Pass:           utils.Ptr(true),
+	}, nil)
+
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, nil, nil, nil, nil, mockUserEvalRepo, mockCourseContentRepo, nil, mockCohortRepo, nil, nil, nil, nil, nil, nil, utilServices.NewEventHub())
+
+	// marking a completed step again neither adds an evaluation nor awards its gems again
+	userEvalId, err := underTest.SubmitStepEvalTypeCheck(mockStepEvalId, utils.Ptr(uint64(7)), utils.Ptr(uint64(1)))
+
+	is.Nil(err)
+	is.Equal(uint64(30), *userEvalId)
+	mockUserEvalRepo.AssertNotCalled(suite.T(), "SaveUserEvals", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *StepServiceTestSuite) TestSubmitStepEvalTypeCheckWhenFailedBefore() {
+	is := assert.New(suite.T())
+
+	mockStepEvalRepo := new(mockRepositories.StepEvaluateRepository)
+	mockUserEvalRepo := new(mockRepositories.UserEvaluateRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+
+	mockStepEvalId := utils.Ptr(uint64(12))
+	mockStepEvalRepo.EXPECT().GetStepEvalById(mockStepEvalId).Return(&models.StepEvaluate{Id: mockStepEvalId, StepId: utils.Ptr(uint64(3)), Type: utils.Ptr("check"), Gem: utils.Ptr(3)}, nil)
+	mockCohortRepo.EXPECT().FindLearnerCohort(uint64(1), uint64(7)).Return(nil, nil)
+	mockStepRepo := new(mockRepositories.StepRepository)
+	mockStepRepo.EXPECT().GetModuleIdByStepId(utils.Ptr(uint64(3))).Return(utils.Ptr(uint64(2)), nil)
+	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
+	mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndModuleId(utils.Ptr(uint64(7)), utils.Ptr(uint64(2))).Return(&models.CourseContent{CourseId: utils.Ptr(uint64(7))}, nil)
+	mockUserEvalRepo.EXPECT().GetUserEvalByStepEvalIdUserId(mockStepEvalId, utils.Ptr(uint64(7)), utils.Ptr(float64(1))).Return(&models.UserEvaluate{
+		Id:             utils.Ptr(uint64(30)),
+		UserId:         utils.Ptr(uint64(1)),
+		StepEvaluateId: mockStepEvalId,
+		Pass:           utils.Ptr(false),
+		Attempts:       utils.Ptr(2),
+	}, nil)
+	// the evaluation is passed along with its gems
+	mockUserEvalRepo.EXPECT().SaveUserEvals(mock.MatchedBy(func(userEvals []*models.UserEvaluate) bool {
+		return len(userEvals) == 1 && *userEvals[0].Id == 30 && *userEvals[0].Pass && *userEvals[0].Attempts == 3 && *userEvals[0].CourseId == 7
+	}), int64(3), []*models.Notification(nil)).Return(nil)
+
+	mockAchievementService := new(mockServices.AchievementService)
+	mockAchievementService.EXPECT().Record(uint64(1), payload.AchievementTriggerSubmissionPassed).Return()
+	mockXapiService := new(mockServices.XapiService)
+	mockXapiService.EXPECT().RecordAttempted(mock.Anything).Return(nil)
+	mockXapiService.EXPECT().RecordGraded(mock.Anything).Return(nil)
+	mockCertificateService := new(mockServices.CertificateService)
+	mockCertificateService.EXPECT().IssueOnCompletion(uint64(1), uint64(7)).Return(nil)
+
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, nil, nil, nil, nil, mockUserEvalRepo, mockCourseContentRepo, nil, mockCohortRepo, nil, nil, mockAchievementService, nil, mockXapiService, mockCertificateService, utilServices.NewEventHub())
+
+	userEvalId, err := underTest.SubmitStepEvalTypeCheck(mockStepEvalId, utils.Ptr(uint64(7)), utils.Ptr(uint64(1)))
+
+	is.Nil(err)
+	is.Equal(uint64(30), *userEvalId)
+	mockUserEvalRepo.AssertExpectations(suite.T())
+}
+
 func (suite *StepServiceTestSuite) TestGetStepInfoWhenSuccess() {
 	is := assert.New(suite.T())
 
@@ -1128,6 +1537,9 @@ func (suite *StepServiceTestSuite) TestGetStepInfoWhenSuccess() {
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
+	mockCohortRepo.EXPECT().FindLearnerCohort(mock.Anything, mock.Anything).Return(nil, nil).Maybe()
 
 	mockStepId := utils.Ptr(uint64(1))
 	mockUserIdPassed := utils.Ptr(uint64(9))
@@ -1181,12 +1593,18 @@ func (suite *StepServiceTestSuite) TestGetStepInfoWhenSuccess() {
 	mockStepAuthorRepo.EXPECT().GetStepAuthorByStepId(mock.Anything).Return(mockStepAuthors, nil)
 	mockStepEvalRepo.EXPECT().GetStepEvalByStepId(mock.Anything).Return(mockStepEvals, nil)
 	mockUserRepo.EXPECT().FindUserByID(utils.Ptr(strconv.FormatUint(*mockAuthorId, 10))).Return(mockAuthorUser, nil)
-	mockUserEvalRepo.EXPECT().GetPassAllUserEvalByStepEvalId(mock.Anything).Return(mockUserEval, nil)
+	mockUserEvalRepo.EXPECT().GetPassAllUserEvalByStepEvalId(mock.Anything, mock.Anything).Return(mockUserEval, nil)
 	mockUserRepo.EXPECT().FindUserByID(utils.Ptr(strconv.FormatUint(*mockUserIdPassed, 10))).Return(mockUserPass, nil)
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	mockStepRepo.EXPECT().GetModuleIdByStepId(mock.Anything).Return(utils.Ptr(uint64(2)), nil).Maybe()
+	mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndModuleId(mock.Anything, mock.Anything).Return(&models.CourseContent{
+		CourseId: utils.Ptr(uint64(1)),
+		Course:   &models.Course{Id: utils.Ptr(uint64(1)), Name: utils.Ptr("Course")},
+	}, nil).Maybe()
+
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
 
-	stepInfo, err := underTest.GetStepInfo(mockStepId)
+	stepInfo, err := underTest.GetStepInfo(mockStepId, utils.Ptr(uint64(1)), utils.Ptr(float64(1)))
 
 	is.Nil(err)
 	is.NotNil(stepInfo)
@@ -1207,14 +1625,23 @@ func (suite *StepServiceTestSuite) TestGetStepInfoWhenFailedToGetStep() {
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
+	mockCohortRepo.EXPECT().FindLearnerCohort(mock.Anything, mock.Anything).Return(nil, nil).Maybe()
 
 	mockStepId := utils.Ptr(uint64(1))
 
 	mockStepRepo.EXPECT().GetStepById(mock.Anything).Return(nil, fmt.Errorf("failed to get step"))
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	mockStepRepo.EXPECT().GetModuleIdByStepId(mock.Anything).Return(utils.Ptr(uint64(2)), nil).Maybe()
+	mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndModuleId(mock.Anything, mock.Anything).Return(&models.CourseContent{
+		CourseId: utils.Ptr(uint64(1)),
+		Course:   &models.Course{Id: utils.Ptr(uint64(1)), Name: utils.Ptr("Course")},
+	}, nil).Maybe()
 
-	stepInfo, err := underTest.GetStepInfo(mockStepId)
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
+
+	stepInfo, err := underTest.GetStepInfo(mockStepId, utils.Ptr(uint64(1)), utils.Ptr(float64(1)))
 
 	is.NotNil(err)
 	is.Nil(stepInfo)
@@ -1236,6 +1663,9 @@ func (suite *StepServiceTestSuite) TestGetStepInfoWhenFailedToGetModule() {
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
+	mockCohortRepo.EXPECT().FindLearnerCohort(mock.Anything, mock.Anything).Return(nil, nil).Maybe()
 
 	mockStepId := utils.Ptr(uint64(1))
 	mockStep := &models.Step{
@@ -1252,9 +1682,15 @@ func (suite *StepServiceTestSuite) TestGetStepInfoWhenFailedToGetModule() {
 	mockStepRepo.EXPECT().GetStepById(mock.Anything).Return(mockStep, nil)
 	mockModuleRepo.EXPECT().GetModuleById(mock.Anything).Return(nil, fmt.Errorf("failed to get module"))
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	mockStepRepo.EXPECT().GetModuleIdByStepId(mock.Anything).Return(utils.Ptr(uint64(2)), nil).Maybe()
+	mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndModuleId(mock.Anything, mock.Anything).Return(&models.CourseContent{
+		CourseId: utils.Ptr(uint64(1)),
+		Course:   &models.Course{Id: utils.Ptr(uint64(1)), Name: utils.Ptr("Course")},
+	}, nil).Maybe()
+
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
 
-	stepInfo, err := underTest.GetStepInfo(mockStepId)
+	stepInfo, err := underTest.GetStepInfo(mockStepId, utils.Ptr(uint64(1)), utils.Ptr(float64(1)))
 
 	is.NotNil(err)
 	is.Nil(stepInfo)
@@ -1276,6 +1712,9 @@ func (suite *StepServiceTestSuite) TestGetStepInfoWhenFailedToGetStepAuthors() {
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
+	mockCohortRepo.EXPECT().FindLearnerCohort(mock.Anything, mock.Anything).Return(nil, nil).Maybe()
 
 	mockStepId := utils.Ptr(uint64(1))
 	mockStep := &models.Step{
@@ -1296,9 +1735,15 @@ func (suite *StepServiceTestSuite) TestGetStepInfoWhenFailedToGetStepAuthors() {
 	mockModuleRepo.EXPECT().GetModuleById(mock.Anything).Return(mockModule, nil)
 	mockStepAuthorRepo.EXPECT().GetStepAuthorByStepId(mock.Anything).Return(nil, fmt.Errorf("failed to get step authors"))
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	mockStepRepo.EXPECT().GetModuleIdByStepId(mock.Anything).Return(utils.Ptr(uint64(2)), nil).Maybe()
+	mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndModuleId(mock.Anything, mock.Anything).Return(&models.CourseContent{
+		CourseId: utils.Ptr(uint64(1)),
+		Course:   &models.Course{Id: utils.Ptr(uint64(1)), Name: utils.Ptr("Course")},
+	}, nil).Maybe()
 
-	stepInfo, err := underTest.GetStepInfo(mockStepId)
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
+
+	stepInfo, err := underTest.GetStepInfo(mockStepId, utils.Ptr(uint64(1)), utils.Ptr(float64(1)))
 
 	is.NotNil(err)
 	is.Nil(stepInfo)
@@ -1319,6 +1764,9 @@ func (suite *StepServiceTestSuite) TestGetStepInfoWhenFailedToGetStepEval() {
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
+	mockCohortRepo.EXPECT().FindLearnerCohort(mock.Anything, mock.Anything).Return(nil, nil).Maybe()
 
 	mockStepId := utils.Ptr(uint64(1))
 	mockAuthorId := utils.Ptr(uint64(12))
@@ -1346,9 +1794,15 @@ func (suite *StepServiceTestSuite) TestGetStepInfoWhenFailedToGetStepEval() {
 	mockStepAuthorRepo.EXPECT().GetStepAuthorByStepId(mock.Anything).Return(mockStepAuthors, nil)
 	mockStepEvalRepo.EXPECT().GetStepEvalByStepId(mock.Anything).Return(nil, fmt.Errorf("failed to get step eval"))
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	mockStepRepo.EXPECT().GetModuleIdByStepId(mock.Anything).Return(utils.Ptr(uint64(2)), nil).Maybe()
+	mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndModuleId(mock.Anything, mock.Anything).Return(&models.CourseContent{
+		CourseId: utils.Ptr(uint64(1)),
+		Course:   &models.Course{Id: utils.Ptr(uint64(1)), Name: utils.Ptr("Course")},
+	}, nil).Maybe()
+
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
 
-	stepInfo, err := underTest.GetStepInfo(mockStepId)
+	stepInfo, err := underTest.GetStepInfo(mockStepId, utils.Ptr(uint64(1)), utils.Ptr(float64(1)))
 
 	is.NotNil(err)
 	is.Nil(stepInfo)
@@ -1369,6 +1823,9 @@ func (suite *StepServiceTestSuite) TestGetStepInfoWhenFailedToFindAuthorInfo() {
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
+	mockCohortRepo.EXPECT().FindLearnerCohort(mock.Anything, mock.Anything).Return(nil, nil).Maybe()
 
 	mockStepId := utils.Ptr(uint64(1))
 	mockAuthorId := utils.Ptr(uint64(12))
@@ -1402,9 +1859,15 @@ func (suite *StepServiceTestSuite) TestGetStepInfoWhenFailedToFindAuthorInfo() {
 	mockStepEvalRepo.EXPECT().GetStepEvalByStepId(mock.Anything).Return(mockStepEvals, nil)
 	mockUserRepo.EXPECT().FindUserByID(utils.Ptr(strconv.FormatUint(*mockAuthorId, 10))).Return(nil, fmt.Errorf("failed to find author info"))
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	mockStepRepo.EXPECT().GetModuleIdByStepId(mock.Anything).Return(utils.Ptr(uint64(2)), nil).Maybe()
+	mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndModuleId(mock.Anything, mock.Anything).Return(&models.CourseContent{
+		CourseId: utils.Ptr(uint64(1)),
+		Course:   &models.Course{Id: utils.Ptr(uint64(1)), Name: utils.Ptr("Course")},
+	}, nil).Maybe()
 
-	stepInfo, err := underTest.GetStepInfo(mockStepId)
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
+
+	stepInfo, err := underTest.GetStepInfo(mockStepId, utils.Ptr(uint64(1)), utils.Ptr(float64(1)))
 
 	is.NotNil(err)
 	is.Nil(stepInfo)
@@ -1425,6 +1888,9 @@ func (suite *StepServiceTestSuite) TestGetStepInfoWhenFailedToGetPassAllUserEval
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
+	mockCohortRepo.EXPECT().FindLearnerCohort(mock.Anything, mock.Anything).Return(nil, nil).Maybe()
 
 	mockStepId := utils.Ptr(uint64(1))
 	mockAuthorId := utils.Ptr(uint64(12))
@@ -1464,11 +1930,17 @@ func (suite *StepServiceTestSuite) TestGetStepInfoWhenFailedToGetPassAllUserEval
 	mockStepAuthorRepo.EXPECT().GetStepAuthorByStepId(mock.Anything).Return(mockStepAuthors, nil)
 	mockStepEvalRepo.EXPECT().GetStepEvalByStepId(mock.Anything).Return(mockStepEvals, nil)
 	mockUserRepo.EXPECT().FindUserByID(utils.Ptr(strconv.FormatUint(*mockAuthorId, 10))).Return(mockAuthorUser, nil)
-	mockUserEvalRepo.EXPECT().GetPassAllUserEvalByStepEvalId(mock.Anything).Return(nil, fmt.Errorf("failed to get user eval that pass all step eval"))
+	mockUserEvalRepo.EXPECT().GetPassAllUserEvalByStepEvalId(mock.Anything, mock.Anything).Return(nil, fmt.Errorf("failed to get user eval that pass all step eval"))
+
+	mockStepRepo.EXPECT().GetModuleIdByStepId(mock.Anything).Return(utils.Ptr(uint64(2)), nil).Maybe()
+	mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndModuleId(mock.Anything, mock.Anything).Return(&models.CourseContent{
+		CourseId: utils.Ptr(uint64(1)),
+		Course:   &models.Course{Id: utils.Ptr(uint64(1)), Name: utils.Ptr("Course")},
+	}, nil).Maybe()
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
 
-	stepInfo, err := underTest.GetStepInfo(mockStepId)
+	stepInfo, err := underTest.GetStepInfo(mockStepId, utils.Ptr(uint64(1)), utils.Ptr(float64(1)))
 
 	is.NotNil(err)
 	is.Nil(stepInfo)
@@ -1489,6 +1961,9 @@ func (suite *StepServiceTestSuite) TestGetStepInfoWhenFailedToFindUserPassedInfo
 	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
 
 	mockModuleRepo := new(mockRepositories.ModulesRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	eventHub := utilServices.NewEventHub()
+	mockCohortRepo.EXPECT().FindLearnerCohort(mock.Anything, mock.Anything).Return(nil, nil).Maybe()
 
 	mockStepId := utils.Ptr(uint64(1))
 	mockUserIdPassed := utils.Ptr(uint64(9))
@@ -1535,18 +2010,507 @@ func (suite *StepServiceTestSuite) TestGetStepInfoWhenFailedToFindUserPassedInfo
 	mockStepAuthorRepo.EXPECT().GetStepAuthorByStepId(mock.Anything).Return(mockStepAuthors, nil)
 	mockStepEvalRepo.EXPECT().GetStepEvalByStepId(mock.Anything).Return(mockStepEvals, nil)
 	mockUserRepo.EXPECT().FindUserByID(utils.Ptr(strconv.FormatUint(*mockAuthorId, 10))).Return(mockAuthorUser, nil)
-	mockUserEvalRepo.EXPECT().GetPassAllUserEvalByStepEvalId(mock.Anything).Return(mockUserEval, nil)
+	mockUserEvalRepo.EXPECT().GetPassAllUserEvalByStepEvalId(mock.Anything, mock.Anything).Return(mockUserEval, nil)
 	mockUserRepo.EXPECT().FindUserByID(utils.Ptr(strconv.FormatUint(*mockUserIdPassed, 10))).Return(nil, fmt.Errorf("failed to find user passed info"))
 
-	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo)
+	mockStepRepo.EXPECT().GetModuleIdByStepId(mock.Anything).Return(utils.Ptr(uint64(2)), nil).Maybe()
+	mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndModuleId(mock.Anything, mock.Anything).Return(&models.CourseContent{
+		CourseId: utils.Ptr(uint64(1)),
+		Course:   &models.Course{Id: utils.Ptr(uint64(1)), Name: utils.Ptr("Course")},
+	}, nil).Maybe()
 
-	stepInfo, err := underTest.GetStepInfo(mockStepId)
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, mockStepCommentRepo, mockStepCommentUpVoteRepo, mockStepAuthorRepo, mockUserRepo, mockUserEvalRepo, mockCourseContentRepo, mockModuleRepo, mockCohortRepo, nil, nil, nil, nil, nil, nil, eventHub)
+
+	stepInfo, err := underTest.GetStepInfo(mockStepId, utils.Ptr(uint64(1)), utils.Ptr(float64(1)))
 
 	is.NotNil(err)
 	is.Nil(stepInfo)
 	is.Equal("failed to find user passed info", err.Error())
 }
 
+func (suite *StepServiceTestSuite) TestCreateStepCommentWhenReplyingToAnotherStep() {
+	is := assert.New(suite.T())
+
+	mockStepCommentRepo := new(mockRepositories.StepCommentRepository)
+
+	mockStepCommentRepo.EXPECT().GetStepCommentById(utils.Ptr(uint64(8))).Return(&models.StepComment{Id: utils.Ptr(uint64(8)), StepId: utils.Ptr(uint64(3))}, nil)
+
+	underTest := NewStepService(nil, nil, mockStepCommentRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, utilServices.NewEventHub())
+
+	err := underTest.CreateStpComment(utils.Ptr(uint64(2)), utils.Ptr(float64(1)), utils.Ptr("reply"), utils.Ptr(uint64(8)))
+
+	is.NotNil(err)
+	is.Equal("comment 8 is not a comment of step 2", err.Error())
+	mockStepCommentRepo.AssertNotCalled(suite.T(), "CreateStepComment", mock.Anything, mock.Anything)
+}
+
+func (suite *StepServiceTestSuite) TestCreateStepCommentWhenReplySuccess() {
+	is := assert.New(suite.T())
+
+	mockStepCommentRepo := new(mockRepositories.StepCommentRepository)
+	mockUserRepo := new(mockRepositories.UserRepository)
+	eventHub := utilServices.NewEventHub()
+
+	mockStepCommentRepo.EXPECT().GetStepCommentById(utils.Ptr(uint64(8))).Return(&models.StepComment{Id: utils.Ptr(uint64(8)), StepId: utils.Ptr(uint64(2)), UserId: utils.Ptr(uint64(5))}, nil)
+	mockUserRepo.EXPECT().FindUserByID(utils.Ptr("1")).Return(&models.User{Id: utils.Ptr(uint64(1))}, nil)
+	notification := &models.Notification{UserId: utils.Ptr(uint64(5)), Status: utils.Ptr("pending")}
+	mockNotificationService := new(mockServices.NotificationService)
+	mockNotificationService.EXPECT().CommentReply(mock.MatchedBy(func(parent *models.StepComment) bool {
+		return *parent.Id == 8
+	}), mock.Anything, mock.Anything).Return(notification, nil)
+	mockNotificationService.EXPECT().Wake().Return()
+	// the email is stored along with the reply
+	mockStepCommentRepo.EXPECT().CreateStepComment(mock.MatchedBy(func(stepComment *models.StepComment) bool {
+		return *stepComment.ParentId == 8
+	}), notification).Return(nil)
+
+	events, unsubscribe := eventHub.Subscribe(utilServices.UserTopic(5))
+	defer unsubscribe()
+
+	mockAchievementService := new(mockServices.AchievementService)
+	mockAchievementService.EXPECT().Record(mock.Anything, payload.AchievementTriggerCommentCreated).Return()
+
+	mockXapiService := new(mockServices.XapiService)
+	mockXapiService.EXPECT().RecordCommented(mock.Anything).Return(nil)
+
+	underTest := NewStepService(nil, nil, mockStepCommentRepo, nil, nil, mockUserRepo, nil, nil, nil, nil, nil, nil, mockAchievementService, mockNotificationService, mockXapiService, nil, eventHub)
+
+	err := underTest.CreateStpComment(utils.Ptr(uint64(2)), utils.Ptr(float64(1)), utils.Ptr("reply"), utils.Ptr(uint64(8)))
+
+	is.Nil(err)
+	event := <-events
+	is.Equal(payload.EventCommentReply, event.Type)
+	is.Equal(uint64(8), *event.Data.(*payload.StepCommentEvent).ParentId)
+	mockNotificationService.AssertExpectations(suite.T())
+	mockAchievementService.AssertExpectations(suite.T())
+	mockXapiService.AssertExpectations(suite.T())
+}
+
+func (suite *StepServiceTestSuite) TestSubmitStepEvalTypeCheckWhenTeamMemberPassed() {
+	is := assert.New(suite.T())
+
+	mockStepEvalRepo := new(mockRepositories.StepEvaluateRepository)
+	mockUserEvalRepo := new(mockRepositories.UserEvaluateRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	mockTeamRepo := new(mockRepositories.TeamRepository)
+
+	mockStepEvalId := utils.Ptr(uint64(12))
+	mockStepEvalRepo.EXPECT().GetStepEvalById(mockStepEvalId).Return(&models.StepEvaluate{Id: mockStepEvalId, StepId: utils.Ptr(uint64(3)), Type: utils.Ptr("check"), Gem: utils.Ptr(3), TeamEligible: utils.Ptr(true)}, nil)
+	mockStepRepo := new(mockRepositories.StepRepository)
+	mockStepRepo.EXPECT().GetModuleIdByStepId(utils.Ptr(uint64(3))).Return(utils.Ptr(uint64(2)), nil)
+	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
+	mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndModuleId(utils.Ptr(uint64(7)), utils.Ptr(uint64(2))).Return(&models.CourseContent{CourseId: utils.Ptr(uint64(7))}, nil)
+	mockTeamRepo.EXPECT().FindTeamOfUser(uint64(1), uint64(7)).Return(&models.Team{
+		Id: utils.Ptr(uint64(6)),
+		Members: []*models.TeamMember{
+			{UserId: utils.Ptr(uint64(1))},
+			{UserId: utils.Ptr(uint64(2))},
+		},
+	}, nil)
+	mockCohortRepo.EXPECT().FindLearnerCohort(uint64(1), uint64(7)).Return(nil, nil)
+	mockCohortRepo.EXPECT().FindLearnerCohort(uint64(2), uint64(7)).Return(nil, nil)
+	mockUserEvalRepo.EXPECT().GetUserEvalByStepEvalIdUserId(mockStepEvalId, utils.Ptr(uint64(7)), utils.Ptr(float64(1))).Return(&models.UserEvaluate{
+		Id:             utils.Ptr(uint64(30)),
+		UserId:         utils.Ptr(uint64(1)),
+		StepEvaluateId: mockStepEvalId,
+		CourseId:       utils.Ptr(uint64(7)),
+		Pass:           utils.Ptr(true),
+		Attempts:       utils.Ptr(1),
+	}, nil)
+	mockUserEvalRepo.EXPECT().GetUserEvalByStepEvalIdUserId(mockStepEvalId, utils.Ptr(uint64(7)), utils.Ptr(float64(2))).Return(nil, nil)
+	mockUserEvalRepo.EXPECT().SaveUserEvals(mock.MatchedBy(func(userEvals []*models.UserEvaluate) bool {
+		return len(userEvals) == 1 && *userEvals[0].UserId == 2 && *userEvals[0].Pass && *userEvals[0].Attempts == 1
+	}), int64(3), []*models.Notification(nil)).Return(nil)
+
+	// only the member completing the step is settled and told about it
+	mockAchievementService := new(mockServices.AchievementService)
+	mockAchievementService.EXPECT().Record(uint64(2), payload.AchievementTriggerSubmissionPassed).Return().Once()
+	mockXapiService := new(mockServices.XapiService)
+	mockXapiService.EXPECT().RecordAttempted(mock.Anything).Return(nil).Once()
+	mockXapiService.EXPECT().RecordGraded(mock.Anything).Return(nil).Once()
+	mockCertificateService := new(mockServices.CertificateService)
+	mockCertificateService.EXPECT().IssueOnCompletion(uint64(2), uint64(7)).Return(nil).Once()
+
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, nil, nil, nil, nil, mockUserEvalRepo, mockCourseContentRepo, nil, mockCohortRepo, mockTeamRepo, nil, mockAchievementService, nil, mockXapiService, mockCertificateService, utilServices.NewEventHub())
+
+	userEvalId, err := underTest.SubmitStepEvalTypeCheck(mockStepEvalId, utils.Ptr(uint64(7)), utils.Ptr(uint64(1)))
+
+	is.Nil(err)
+	is.Equal(uint64(30), *userEvalId)
+	mockUserEvalRepo.AssertExpectations(suite.T())
+	mockAchievementService.AssertExpectations(suite.T())
+	mockXapiService.AssertExpectations(suite.T())
+}
+
+func (suite *StepServiceTestSuite) TestSubmitStepEvalTypeCheckWhenTeamMemberLocked() {
+	is := assert.New(suite.T())
+
+	mockStepEvalRepo := new(mockRepositories.StepEvaluateRepository)
+	mockUserEvalRepo := new(mockRepositories.UserEvaluateRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	mockTeamRepo := new(mockRepositories.TeamRepository)
+
+	mockStepEvalId := utils.Ptr(uint64(12))
+	mockStepEvalRepo.EXPECT().GetStepEvalById(mockStepEvalId).Return(&models.StepEvaluate{Id: mockStepEvalId, StepId: utils.Ptr(uint64(3)), Type: utils.Ptr("check"), Gem: utils.Ptr(3), TeamEligible: utils.Ptr(true)}, nil)
+	mockStepRepo := new(mockRepositories.StepRepository)
+	mockStepRepo.EXPECT().GetModuleIdByStepId(utils.Ptr(uint64(3))).Return(utils.Ptr(uint64(2)), nil)
+	mockStepRepo.EXPECT().GetStepById(utils.Ptr(uint64(3))).Return(&models.Step{Id: utils.Ptr(uint64(3)), ModuleId: utils.Ptr(uint64(2))}, nil)
+	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
+	mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndModuleId(utils.Ptr(uint64(7)), utils.Ptr(uint64(2))).Return(&models.CourseContent{CourseId: utils.Ptr(uint64(7)), Order: utils.Ptr(int64(1))}, nil)
+	mockTeamRepo.EXPECT().FindTeamOfUser(uint64(1), uint64(7)).Return(&models.Team{
+		Id: utils.Ptr(uint64(6)),
+		Members: []*models.TeamMember{
+			{UserId: utils.Ptr(uint64(1))},
+			{UserId: utils.Ptr(uint64(2))},
+		},
+	}, nil)
+	mockCohortRepo.EXPECT().FindLearnerCohort(uint64(1), uint64(7)).Return(nil, nil)
+	mockCohortRepo.EXPECT().FindLearnerCohort(uint64(2), uint64(7)).Return(&models.Cohort{Id: utils.Ptr(uint64(4))}, nil)
+
+	underTest := NewStepService(mockStepRepo, mockStepEvalRepo, nil, nil, nil, nil, mockUserEvalRepo, mockCourseContentRepo, nil, mockCohortRepo, mockTeamRepo, nil, nil, nil, nil, nil, utilServices.NewEventHub())
+
+	// the cohort of a member has not reached the step yet, so nobody completes it
+	userEvalId, err := underTest.SubmitStepEvalTypeCheck(mockStepEvalId, utils.Ptr(uint64(7)), utils.Ptr(uint64(1)))
+
+	is.Nil(userEvalId)
+	is.EqualError(err, "team member 2: step 3 is locked until the cohort instructor unlocks it")
+	mockUserEvalRepo.AssertNotCalled(suite.T(), "SaveUserEvals", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *StepServiceTestSuite) TestSubmitStepEvalTypeCheckWhenNotCheck() {
+	is := assert.New(suite.T())
+
+	mockStepEvalRepo := new(mockRepositories.StepEvaluateRepository)
+	mockUserEvalRepo := new(mockRepositories.UserEvaluateRepository)
+
+	mockStepEvalId := utils.Ptr(uint64(12))
+	mockStepEvalRepo.EXPECT().GetStepEvalById(mockStepEvalId).Return(&models.StepEvaluate{Id: mockStepEvalId, StepId: utils.Ptr(uint64(3)), Type: utils.Ptr("image"), Gem: utils.Ptr(3)}, nil)
+
+	underTest := NewStepService(nil, mockStepEvalRepo, nil, nil, nil, nil, mockUserEvalRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, utilServices.NewEventHub())
+
+	// an image evaluation is passed by grading the image, not by marking it
+	userEvalId, err := underTest.SubmitStepEvalTypeCheck(mockStepEvalId, utils.Ptr(uint64(7)), utils.Ptr(uint64(1)))
+
+	is.Nil(userEvalId)
+	is.EqualError(err, "step evaluation 12 is not a check evaluation")
+	mockUserEvalRepo.AssertNotCalled(suite.T(), "SaveUserEvals", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *StepServiceTestSuite) TestGradeUserEvalWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockUserEvalRepo := new(mockRepositories.UserEvaluateRepository)
+	eventHub := utilServices.NewEventHub()
+
+	mockUserEvalRepo.EXPECT().GetUserEvalById(utils.Ptr(uint64(4))).Return(&models.UserEvaluate{
+		Id:      utils.Ptr(uint64(4)),
+		UserId:  utils.Ptr(uint64(9)),
+		Content: utils.Ptr("photo.png"),
+	}, nil)
+	mockStepEvalRepo := new(mockRepositories.StepEvaluateRepository)
+	mockStepEvalRepo.EXPECT().GetStepEvalById(mock.Anything).Return(&models.StepEvaluate{Id: utils.Ptr(uint64(12)), Gem: utils.Ptr(3)}, nil)
+	mockUserEvalRepo.EXPECT().SaveUserEvals(mock.MatchedBy(func(userEvals []*models.UserEvaluate) bool {
+		return len(userEvals) == 1 && *userEvals[0].Id == 4 && *userEvals[0].Pass && *userEvals[0].Comment == ""
+	}), int64(3), mock.MatchedBy(func(notifications []*models.Notification) bool {
+		return len(notifications) == 0
+	})).Return(nil)
+
+	mockNotificationService := new(mockServices.NotificationService)
+	mockNotificationService.EXPECT().SubmissionGraded(mock.Anything).Return(nil, fmt.Errorf("failed to find user"))
+
+	events, unsubscribe := eventHub.Subscribe(utilServices.UserTopic(9))
+	defer unsubscribe()
+
+	mockAchievementService := new(mockServices.AchievementService)
+	mockAchievementService.EXPECT().Record(mock.Anything, payload.AchievementTriggerSubmissionPassed).Return()
+
+	mockXapiService := new(mockServices.XapiService)
+	mockXapiService.EXPECT().RecordGraded(mock.Anything).Return(fmt.Errorf("lrs is unreachable"))
+
+	mockUserRepo := new(mockRepositories.UserRepository)
+	mockUserRepo.EXPECT().FindUserByID(utils.Ptr("5")).Return(&models.User{Role: utils.Ptr("admin")}, nil)
+
+	underTest := NewStepService(nil, mockStepEvalRepo, nil, nil, nil, mockUserRepo, mockUserEvalRepo, nil, nil, nil, nil, nil, mockAchievementService, mockNotificationService, mockXapiService, nil, eventHub)
+
+	result, err := underTest.GradeUserEval(utils.Ptr(uint64(4)), 5, &payload.GradeUserEval{Pass: utils.Ptr(true)})
+
+	// the grade is saved even when the email cannot be enqueued nor the statement recorded
+	is.Nil(err)
+	is.True(*result.Pass)
+	event := <-events
+	is.Equal(payload.EventSubmissionGraded, event.Type)
+	is.Equal(uint64(4), *event.Data.(*payload.UserEvalResult).UserEvalId)
+	mockUserEvalRepo.AssertExpectations(suite.T())
+	mockAchievementService.AssertExpectations(suite.T())
+	mockXapiService.AssertExpectations(suite.T())
+}
+
+func (suite *StepServiceTestSuite) TestCreateUserEvalWhenTeamEligible() {
+	is := assert.New(suite.T())
+
+	mockStepEvalRepo := new(mockRepositories.StepEvaluateRepository)
+	mockUserEvalRepo := new(mockRepositories.UserEvaluateRepository)
+	mockTeamRepo := new(mockRepositories.TeamRepository)
+
+	mockStepEvalRepo.EXPECT().GetStepEvalById(utils.Ptr(uint64(12))).Return(&models.StepEvaluate{
+		Id:           utils.Ptr(uint64(12)),
+		StepId:       utils.Ptr(uint64(3)),
+		TeamEligible: utils.Ptr(true),
+	}, nil)
+	mockTeamRepo.EXPECT().FindTeamOfUser(uint64(1), uint64(7)).Return(&models.Team{
+		Id: utils.Ptr(uint64(6)),
+		Members: []*models.TeamMember{
+			{UserId: utils.Ptr(uint64(1))},
+			{UserId: utils.Ptr(uint64(2))},
+		},
+	}, nil)
+	mockUserEvalRepo.EXPECT().GetUserEvalByStepEvalIdUserId(utils.Ptr(uint64(12)), utils.Ptr(uint64(7)), utils.Ptr(float64(1))).Return(&models.UserEvaluate{
+		Id:             utils.Ptr(uint64(30)),
+		UserId:         utils.Ptr(uint64(1)),
+		StepEvaluateId: utils.Ptr(uint64(12)),
+		Pass:           utils.Ptr(false),
+	}, nil)
+	mockUserEvalRepo.EXPECT().GetUserEvalByStepEvalIdUserId(utils.Ptr(uint64(12)), utils.Ptr(uint64(7)), utils.Ptr(float64(2))).Return(nil, nil)
+	mockUserEvalRepo.EXPECT().SaveUserEvals(mock.MatchedBy(func(userEvals []*models.UserEvaluate) bool {
+		if len(userEvals) != 2 {
+			return false
+		}
+		for _, userEval := range userEvals {
+			if *userEval.Content != "https://github.com/team-a/lab" || *userEval.TeamId != 6 || *userEval.SubmittedBy != 1 || userEval.Pass != nil {
+				return false
+			}
+		}
+		return *userEvals[1].UserId == 2
+	}), int64(0), []*models.Notification(nil)).Return(nil)
+
+	mockXapiService := new(mockServices.XapiService)
+	mockXapiService.EXPECT().RecordAttempted(mock.Anything).Return(nil).Times(2)
+
+	underTest := NewStepService(nil, mockStepEvalRepo, nil, nil, nil, nil, mockUserEvalRepo, nil, nil, nil, mockTeamRepo, nil, nil, nil, mockXapiService, nil, utilServices.NewEventHub())
+
+	userEvalId, err := underTest.CreateUserEval(&payload.CreateUserEvalReq{
+		UserId:     utils.Ptr(float64(1)),
+		CourseId:   utils.Ptr(uint64(7)),
+		StepId:     utils.Ptr(uint64(3)),
+		StepEvalId: utils.Ptr(uint64(12)),
+		Content:    utils.Ptr("https://github.com/team-a/lab"),
+	})
+
+	is.Nil(err)
+	is.Equal(uint64(30), *userEvalId)
+	mockUserEvalRepo.AssertNotCalled(suite.T(), "CreateUserEval", mock.Anything)
+	mockXapiService.AssertExpectations(suite.T())
+}
+
+func (suite *StepServiceTestSuite) TestCreateUserEvalWhenNotTeamEligible() {
+	is := assert.New(suite.T())
+
+	mockStepEvalRepo := new(mockRepositories.StepEvaluateRepository)
+	mockUserEvalRepo := new(mockRepositories.UserEvaluateRepository)
+	mockTeamRepo := new(mockRepositories.TeamRepository)
+
+	mockStepEvalRepo.EXPECT().GetStepEvalById(utils.Ptr(uint64(12))).Return(&models.StepEvaluate{
+		Id:           utils.Ptr(uint64(12)),
+		StepId:       utils.Ptr(uint64(3)),
+		TeamEligible: utils.Ptr(false),
+	}, nil)
+	mockUserEvalRepo.EXPECT().GetUserEvalByStepEvalIdUserId(utils.Ptr(uint64(12)), utils.Ptr(uint64(7)), utils.Ptr(float64(1))).Return(nil, nil)
+	mockUserEvalRepo.EXPECT().CreateUserEval(mock.MatchedBy(func(userEval *models.UserEvaluate) bool {
+		return *userEval.SubmittedBy == 1 && userEval.TeamId == nil
+	})).Return(&models.UserEvaluate{Id: utils.Ptr(uint64(31))}, nil)
+
+	mockXapiService := new(mockServices.XapiService)
+	mockXapiService.EXPECT().RecordAttempted(mock.MatchedBy(func(userEval *models.UserEvaluate) bool {
+		return *userEval.Id == 31
+	})).Return(nil)
+
+	underTest := NewStepService(nil, mockStepEvalRepo, nil, nil, nil, nil, mockUserEvalRepo, nil, nil, nil, mockTeamRepo, nil, nil, nil, mockXapiService, nil, utilServices.NewEventHub())
+
+	userEvalId, err := underTest.CreateUserEval(&payload.CreateUserEvalReq{
+		UserId:     utils.Ptr(float64(1)),
+		CourseId:   utils.Ptr(uint64(7)),
+		StepId:     utils.Ptr(uint64(3)),
+		StepEvalId: utils.Ptr(uint64(12)),
+		Content:    utils.Ptr("answer"),
+	})
+
+	is.Nil(err)
+	is.Equal(uint64(31), *userEvalId)
+	mockTeamRepo.AssertNotCalled(suite.T(), "FindTeamOfUser", mock.Anything, mock.Anything)
+	mockXapiService.AssertExpectations(suite.T())
+}
+
+func (suite *StepServiceTestSuite) TestCreateUserEvalWhenResubmitted() {
+	is := assert.New(suite.T())
+
+	mockStepEvalRepo := new(mockRepositories.StepEvaluateRepository)
+	mockUserEvalRepo := new(mockRepositories.UserEvaluateRepository)
+
+	mockStepEvalRepo.EXPECT().GetStepEvalById(utils.Ptr(uint64(12))).Return(&models.StepEvaluate{
+		Id:     utils.Ptr(uint64(12)),
+		StepId: utils.Ptr(uint64(3)),
+		Gem:    utils.Ptr(3),
+	}, nil)
+	mockUserEvalRepo.EXPECT().GetUserEvalByStepEvalIdUserId(utils.Ptr(uint64(12)), utils.Ptr(uint64(7)), utils.Ptr(float64(1))).Return(&models.UserEvaluate{
+		Id:             utils.Ptr(uint64(30)),
+		UserId:         utils.Ptr(uint64(1)),
+		StepEvaluateId: utils.Ptr(uint64(12)),
+		Pass:           utils.Ptr(true),
+		Comment:        utils.Ptr("good"),
+		Attempts:       utils.Ptr(1),
+	}, nil)
+	// the new answer waits for a grade, so the gems of the passed one are revoked with it
+	mockUserEvalRepo.EXPECT().SaveUserEvals(mock.MatchedBy(func(userEvals []*models.UserEvaluate) bool {
+		return len(userEvals) == 1 && *userEvals[0].Id == 30 && userEvals[0].Pass == nil && *userEvals[0].Content == "answer" && *userEvals[0].Attempts == 2
+	}), int64(3), []*models.Notification(nil)).Return(nil)
+
+	mockXapiService := new(mockServices.XapiService)
+	mockXapiService.EXPECT().RecordAttempted(mock.Anything).Return(nil)
+
+	underTest := NewStepService(nil, mockStepEvalRepo, nil, nil, nil, nil, mockUserEvalRepo, nil, nil, nil, nil, nil, nil, nil, mockXapiService, nil, utilServices.NewEventHub())
+
+	userEvalId, err := underTest.CreateUserEval(&payload.CreateUserEvalReq{
+		UserId:     utils.Ptr(float64(1)),
+		CourseId:   utils.Ptr(uint64(7)),
+		StepId:     utils.Ptr(uint64(3)),
+		StepEvalId: utils.Ptr(uint64(12)),
+		Content:    utils.Ptr("answer"),
+	})
+
+	is.Nil(err)
+	is.Equal(uint64(30), *userEvalId)
+	mockUserEvalRepo.AssertExpectations(suite.T())
+}
+
+func (suite *StepServiceTestSuite) TestCreateUserEvalWhenStepMismatch() {
+	is := assert.New(suite.T())
+
+	mockStepEvalRepo := new(mockRepositories.StepEvaluateRepository)
+	mockUserEvalRepo := new(mockRepositories.UserEvaluateRepository)
+
+	mockStepEvalRepo.EXPECT().GetStepEvalById(utils.Ptr(uint64(12))).Return(&models.StepEvaluate{
+		Id:     utils.Ptr(uint64(12)),
+		StepId: utils.Ptr(uint64(4)),
+	}, nil)
+
+	underTest := NewStepService(nil, mockStepEvalRepo, nil, nil, nil, nil, mockUserEvalRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, utilServices.NewEventHub())
+
+	// the evaluation of a locked step cannot be submitted through an unlocked one
+	userEvalId, err := underTest.CreateUserEval(&payload.CreateUserEvalReq{
+		UserId:     utils.Ptr(float64(1)),
+		CourseId:   utils.Ptr(uint64(7)),
+		StepId:     utils.Ptr(uint64(3)),
+		StepEvalId: utils.Ptr(uint64(12)),
+		Content:    utils.Ptr("answer"),
+	})
+
+	is.Nil(userEvalId)
+	is.EqualError(err, "step evaluation 12 is not part of step 3")
+	mockUserEvalRepo.AssertNotCalled(suite.T(), "CreateUserEval", mock.Anything)
+}
+
+func (suite *StepServiceTestSuite) TestGradeUserEvalWhenNotCourseInstructor() {
+	is := assert.New(suite.T())
+
+	mockUserEvalRepo := new(mockRepositories.UserEvaluateRepository)
+	mockUserRepo := new(mockRepositories.UserRepository)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+
+	mockUserEvalRepo.EXPECT().GetUserEvalById(utils.Ptr(uint64(4))).Return(&models.UserEvaluate{
+		Id:       utils.Ptr(uint64(4)),
+		UserId:   utils.Ptr(uint64(9)),
+		CourseId: utils.Ptr(uint64(7)),
+	}, nil)
+	mockUserRepo.EXPECT().FindUserByID(utils.Ptr("5")).Return(&models.User{Role: utils.Ptr("instructor")}, nil)
+	mockCohortRepo.EXPECT().IsCourseInstructor(uint64(5), uint64(7)).Return(false, nil)
+
+	underTest := NewStepService(nil, nil, nil, nil, nil, mockUserRepo, mockUserEvalRepo, nil, nil, mockCohortRepo, nil, nil, nil, nil, nil, nil, utilServices.NewEventHub())
+
+	// instructors only grade the courses they teach
+	result, err := underTest.GradeUserEval(utils.Ptr(uint64(4)), 5, &payload.GradeUserEval{Pass: utils.Ptr(true)})
+
+	is.Nil(result)
+	is.EqualError(err, "user 5 is not an instructor of the course of evaluation 4")
+	mockUserEvalRepo.AssertNotCalled(suite.T(), "SaveUserEvals", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *StepServiceTestSuite) TestGradeUserEvalWhenTeamSubmission() {
+	is := assert.New(suite.T())
+
+	mockUserEvalRepo := new(mockRepositories.UserEvaluateRepository)
+	eventHub := utilServices.NewEventHub()
+
+	teamUserEval := func(id uint64, userId uint64) *models.UserEvaluate {
+		return &models.UserEvaluate{
+			Id:             utils.Ptr(id),
+			UserId:         utils.Ptr(userId),
+			StepEvaluateId: utils.Ptr(uint64(12)),
+			CourseId:       utils.Ptr(uint64(7)),
+			TeamId:         utils.Ptr(uint64(6)),
+			SubmittedBy:    utils.Ptr(uint64(1)),
+			Content:        utils.Ptr("photo.png"),
+		}
+	}
+
+	mockUserEvalRepo.EXPECT().GetUserEvalById(utils.Ptr(uint64(31))).Return(teamUserEval(31, 2), nil)
+	mockUserEvalRepo.EXPECT().FindTeamUserEvals(utils.Ptr(uint64(12)), utils.Ptr(uint64(7)), utils.Ptr(uint64(6))).Return([]*models.UserEvaluate{
+		teamUserEval(30, 1),
+		teamUserEval(31, 2),
+	}, nil)
+	mockUserEvalRepo.EXPECT().SaveUserEvals(mock.MatchedBy(func(userEvals []*models.UserEvaluate) bool {
+		return len(userEvals) == 2 && *userEvals[0].Pass && *userEvals[1].Pass && *userEvals[0].Comment == "nice wiring"
+	}), int64(3), mock.MatchedBy(func(notifications []*models.Notification) bool {
+		// the emails are stored along with the grade
+		return len(notifications) == 2 && *notifications[0].UserId == 1 && *notifications[1].UserId == 2
+	})).Return(nil)
+	mockStepEvalRepo := new(mockRepositories.StepEvaluateRepository)
+	mockStepEvalRepo.EXPECT().GetStepEvalById(utils.Ptr(uint64(12))).Return(&models.StepEvaluate{Id: utils.Ptr(uint64(12)), Gem: utils.Ptr(3)}, nil)
+
+	submitterEvents, unsubscribeSubmitter := eventHub.Subscribe(utilServices.UserTopic(1))
+	defer unsubscribeSubmitter()
+	memberEvents, unsubscribeMember := eventHub.Subscribe(utilServices.UserTopic(2))
+	defer unsubscribeMember()
+	mockNotificationService := new(mockServices.NotificationService)
+	mockNotificationService.EXPECT().SubmissionGraded(mock.Anything).RunAndReturn(func(userEval *models.UserEvaluate) (*models.Notification, error) {
+		return &models.Notification{UserId: userEval.UserId, Status: utils.Ptr("pending")}, nil
+	}).Times(2)
+	mockNotificationService.EXPECT().Wake().Return().Once()
+
+	mockAchievementService := new(mockServices.AchievementService)
+	mockAchievementService.EXPECT().Record(mock.Anything, payload.AchievementTriggerSubmissionPassed).Return().Times(2)
+
+	mockXapiService := new(mockServices.XapiService)
+	mockXapiService.EXPECT().RecordGraded(mock.Anything).Return(nil).Times(2)
+
+	mockUserRepo := new(mockRepositories.UserRepository)
+	mockUserRepo.EXPECT().FindUserByID(utils.Ptr("5")).Return(&models.User{Role: utils.Ptr("instructor")}, nil)
+	mockCohortRepo := new(mockRepositories.CohortRepository)
+	mockCohortRepo.EXPECT().IsCourseInstructor(uint64(5), uint64(7)).Return(true, nil)
+
+	// a certificate failing to be issued does not fail the grade
+	mockCertificateService := new(mockServices.CertificateService)
+	mockCertificateService.EXPECT().IssueOnCompletion(mock.Anything, uint64(7)).Return(nil).Once()
+	mockCertificateService.EXPECT().IssueOnCompletion(mock.Anything, uint64(7)).Return(fmt.Errorf("bucket is unreachable")).Once()
+
+	underTest := NewStepService(nil, mockStepEvalRepo, nil, nil, nil, mockUserRepo, mockUserEvalRepo, nil, nil, mockCohortRepo, nil, nil, mockAchievementService, mockNotificationService, mockXapiService, mockCertificateService, eventHub)
+
+	result, err := underTest.GradeUserEval(utils.Ptr(uint64(31)), 5, &payload.GradeUserEval{
+		Pass:    utils.Ptr(true),
+		Comment: utils.Ptr("nice wiring"),
+	})
+
+	is.Nil(err)
+	is.Equal(uint64(31), *result.UserEvalId)
+	is.Equal(uint64(6), *result.TeamId)
+	is.Equal(uint64(30), *(<-submitterEvents).Data.(*payload.UserEvalResult).UserEvalId)
+	is.Equal(uint64(31), *(<-memberEvents).Data.(*payload.UserEvalResult).UserEvalId)
+	mockUserEvalRepo.AssertNotCalled(suite.T(), "Update", mock.Anything)
+	mockCertificateService.AssertExpectations(suite.T())
+	mockNotificationService.AssertExpectations(suite.T())
+	mockAchievementService.AssertExpectations(suite.T())
+	mockXapiService.AssertExpectations(suite.T())
+}
+
 func TestStepService(t *testing.T) {
 	suite.Run(t, new(StepServiceTestSuite))
 }
@@ -0,0 +1,12 @@
+package services
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+)
+
+type StreakService interface {
+	RecordActivity(userId uint64) error
+	GetStreak(user *models.User) (*payload.Streak, error)
+	GetHeatmap(userId uint64) (*payload.ActivityHeatmap, error)
+}
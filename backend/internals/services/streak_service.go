@@ -0,0 +1,135 @@
+package services
+
+import (
+	"backend/internals/config"
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/repositories"
+	"backend/internals/utils"
+	"strconv"
+	"time"
+)
+
+const (
+	activityDayFormat = "2006-01-02"
+	heatmapDays       = 365
+)
+
+type streakService struct {
+	dailyActivityRepo repositories.UserDailyActivityRepository
+	userRepo          repositories.UserRepository
+	conf              *config.Config
+}
+
+func NewStreakService(
+	dailyActivityRepo repositories.UserDailyActivityRepository,
+	userRepo repositories.UserRepository,
+	conf *config.Config) StreakService {
+	return &streakService{
+		dailyActivityRepo: dailyActivityRepo,
+		userRepo:          userRepo,
+		conf:              conf,
+	}
+}
+
+// RecordActivity counts an activity of the user on the current day of their time zone.
+func (r *streakService) RecordActivity(userId uint64) error {
+	user, err := r.userRepo.FindUserByID(utils.Ptr(strconv.FormatUint(userId, 10)))
+	if err != nil {
+		return err
+	}
+
+	return r.dailyActivityRepo.IncrementDailyActivity(userId, utils.TimeNow().In(userLocation(user)))
+}
+
+func (r *streakService) GetStreak(user *models.User) (*payload.Streak, error) {
+	days, err := r.dailyActivityRepo.FindActiveDays(*user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	freezeDays := utils.Val(r.conf.StreakFreezeDays)
+	today := utils.TimeDate(utils.TimeNow().In(userLocation(user)))
+	current, longest := streaks(days, today, freezeDays)
+
+	streak := &payload.Streak{
+		Current:    current,
+		Longest:    longest,
+		FreezeDays: freezeDays,
+	}
+	if len(days) > 0 {
+		streak.LastActive = utils.Ptr(days[len(days)-1].Format(activityDayFormat))
+	}
+
+	return streak, nil
+}
+
+// GetHeatmap counts the activities of the user per day over the last year, today included.
+func (r *streakService) GetHeatmap(userId uint64) (*payload.ActivityHeatmap, error) {
+	user, err := r.userRepo.FindUserByID(utils.Ptr(strconv.FormatUint(userId, 10)))
+	if err != nil {
+		return nil, err
+	}
+
+	location := userLocation(user)
+	today := utils.TimeDate(utils.TimeNow().In(location))
+	from := today.AddDate(0, 0, 1-heatmapDays)
+
+	activities, err := r.dailyActivityRepo.FindDailyActivities(userId, from)
+	if err != nil {
+		return nil, err
+	}
+
+	heatmap := &payload.ActivityHeatmap{
+		From:     from.Format(activityDayFormat),
+		To:       today.Format(activityDayFormat),
+		Timezone: location.String(),
+		Days:     make([]*payload.ActivityDay, 0, len(activities)),
+	}
+	for _, activity := range activities {
+		heatmap.Days = append(heatmap.Days, &payload.ActivityDay{
+			Day:   activity.Day.Format(activityDayFormat),
+			Count: *activity.Count,
+		})
+	}
+
+	return heatmap, nil
+}
+
+// streaks measures the current and longest runs of the active days, sorted oldest first. A run
+// survives up to freezeDays missed days in a row, which do not count toward its length. The
+// current run is still alive when today is within the days it may miss.
+func streaks(days []time.Time, today time.Time, freezeDays int) (int, int) {
+	run, longest := 0, 0
+	for i, day := range days {
+		if i > 0 && daysBetween(days[i-1], day) > freezeDays+1 {
+			run = 0
+		}
+		run++
+		longest = max(longest, run)
+	}
+
+	if len(days) == 0 || daysBetween(days[len(days)-1], today) > freezeDays+1 {
+		return 0, longest
+	}
+
+	return run, longest
+}
+
+func daysBetween(from time.Time, to time.Time) int {
+	return int(utils.TimeDate(to).Sub(utils.TimeDate(from)).Hours() / 24)
+}
+
+// userLocation returns the time zone of the user, Bangkok when it is unknown.
+func userLocation(user *models.User) *time.Location {
+	if user == nil || user.Timezone == nil {
+		return utils.BangkokTime
+	}
+
+	location, err := time.LoadLocation(*user.Timezone)
+	if err != nil {
+		return utils.BangkokTime
+	}
+
+	return location
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"backend/internals/config"
+	"backend/internals/db/models"
+	"backend/internals/utils"
+	mockRepositories "backend/mocks/repositories"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type StreakServiceTestSuite struct {
+	suite.Suite
+}
+
+func parseDay(value string) time.Time {
+	t, _ := time.Parse(activityDayFormat, value)
+	return t
+}
+
+func (suite *StreakServiceTestSuite) TestStreaksWithoutFreeze() {
+	is := assert.New(suite.T())
+
+	days := []time.Time{parseDay("2025-01-01"), parseDay("2025-01-02"), parseDay("2025-01-03"), parseDay("2025-01-05"), parseDay("2025-01-06")}
+
+	current, longest := streaks(days, parseDay("2025-01-07"), 0)
+	is.Equal(2, current)
+	is.Equal(3, longest)
+
+	current, longest = streaks(days, parseDay("2025-01-08"), 0)
+	is.Equal(0, current)
+	is.Equal(3, longest)
+}
+
+func (suite *StreakServiceTestSuite) TestStreaksWithFreeze() {
+	is := assert.New(suite.T())
+
+	days := []time.Time{parseDay("2025-01-01"), parseDay("2025-01-02"), parseDay("2025-01-04"), parseDay("2025-01-07")}
+
+	current, longest := streaks(days, parseDay("2025-01-09"), 1)
+	is.Equal(1, current)
+	is.Equal(3, longest)
+
+	current, longest = streaks(days, parseDay("2025-01-09"), 2)
+	is.Equal(4, current)
+	is.Equal(4, longest)
+}
+
+func (suite *StreakServiceTestSuite) TestStreaksWhenNoActivity() {
+	is := assert.New(suite.T())
+
+	current, longest := streaks(nil, parseDay("2025-01-09"), 1)
+	is.Equal(0, current)
+	is.Equal(0, longest)
+}
+
+func (suite *StreakServiceTestSuite) TestRecordActivityInTimezoneOfUser() {
+	is := assert.New(suite.T())
+
+	mockDailyActivityRepo := new(mockRepositories.UserDailyActivityRepository)
+	mockUserRepo := new(mockRepositories.UserRepository)
+
+	mockUserRepo.EXPECT().FindUserByID(utils.Ptr("4")).Return(&models.User{
+		Id:       utils.Ptr(uint64(4)),
+		Timezone: utils.Ptr("Pacific/Kiritimati"),
+	}, nil)
+	mockDailyActivityRepo.EXPECT().IncrementDailyActivity(uint64(4), mock.MatchedBy(func(t time.Time) bool {
+		return t.Location().String() == "Pacific/Kiritimati"
+	})).Return(nil)
+
+	underTest := NewStreakService(mockDailyActivityRepo, mockUserRepo, &config.Config{})
+
+	is.Nil(underTest.RecordActivity(4))
+	mockDailyActivityRepo.AssertExpectations(suite.T())
+}
+
+func (suite *StreakServiceTestSuite) TestGetStreakWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockDailyActivityRepo := new(mockRepositories.UserDailyActivityRepository)
+
+	today := utils.TimeDate(utils.TimeNow())
+	mockDailyActivityRepo.EXPECT().FindActiveDays(uint64(4)).Return([]time.Time{
+		today.AddDate(0, 0, -4), today.AddDate(0, 0, -2), today.AddDate(0, 0, -1),
+	}, nil)
+
+	underTest := NewStreakService(mockDailyActivityRepo, nil, &config.Config{StreakFreezeDays: utils.Ptr(1)})
+
+	streak, err := underTest.GetStreak(&models.User{Id: utils.Ptr(uint64(4))})
+
+	is.Nil(err)
+	is.Equal(3, streak.Current)
+	is.Equal(3, streak.Longest)
+	is.Equal(1, streak.FreezeDays)
+	is.Equal(today.AddDate(0, 0, -1).Format(activityDayFormat), *streak.LastActive)
+}
+
+func (suite *StreakServiceTestSuite) TestGetHeatmapWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockDailyActivityRepo := new(mockRepositories.UserDailyActivityRepository)
+	mockUserRepo := new(mockRepositories.UserRepository)
+
+	today := utils.TimeDate(utils.TimeNow())
+	mockUserRepo.EXPECT().FindUserByID(utils.Ptr("4")).Return(&models.User{Id: utils.Ptr(uint64(4))}, nil)
+	mockDailyActivityRepo.EXPECT().FindDailyActivities(uint64(4), today.AddDate(0, 0, 1-heatmapDays)).Return([]*models.UserDailyActivity{
+		{Day: utils.Ptr(today), Count: utils.Ptr(int64(3))},
+	}, nil)
+
+	underTest := NewStreakService(mockDailyActivityRepo, mockUserRepo, &config.Config{})
+
+	heatmap, err := underTest.GetHeatmap(4)
+
+	is.Nil(err)
+	is.Equal(today.Format(activityDayFormat), heatmap.To)
+	is.Equal("Asia/Bangkok", heatmap.Timezone)
+	is.Len(heatmap.Days, 1)
+	is.Equal(int64(3), heatmap.Days[0].Count)
+}
+
+func (suite *StreakServiceTestSuite) TestGetHeatmapWhenFailed() {
+	is := assert.New(suite.T())
+
+	mockUserRepo := new(mockRepositories.UserRepository)
+	mockUserRepo.EXPECT().FindUserByID(utils.Ptr("4")).Return(nil, fmt.Errorf("database error"))
+
+	underTest := NewStreakService(nil, mockUserRepo, &config.Config{})
+
+	heatmap, err := underTest.GetHeatmap(4)
+
+	is.Nil(heatmap)
+	is.Error(err)
+}
+
+func TestStreakService(t *testing.T) {
+	suite.Run(t, new(StreakServiceTestSuite))
+}
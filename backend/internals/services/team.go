@@ -0,0 +1,10 @@
+package services
+
+import "backend/internals/entities/payload"
+
+type TeamService interface {
+	CreateTeam(body *payload.CreateTeam) (*payload.TeamInfo, error)
+	AddMember(teamId uint64, body *payload.AddTeamMember) (*payload.TeamInfo, error)
+	GetTeam(teamId uint64) (*payload.TeamInfo, error)
+	SetTeamEligible(stepEvalId uint64, body *payload.TeamEligibleBody) (*payload.StepEvalTeamEligible, error)
+}
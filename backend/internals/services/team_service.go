@@ -0,0 +1,143 @@
+package services
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/repositories"
+	"fmt"
+)
+
+type teamService struct {
+	teamRepo            repositories.TeamRepository
+	courseRepo          repositories.CourseRepository
+	workshopSessionRepo repositories.WorkshopSessionRepository
+	stepEvalRepo        repositories.StepEvaluateRepository
+}
+
+func NewTeamService(
+	teamRepo repositories.TeamRepository,
+	courseRepo repositories.CourseRepository,
+	workshopSessionRepo repositories.WorkshopSessionRepository,
+	stepEvalRepo repositories.StepEvaluateRepository) TeamService {
+	return &teamService{
+		teamRepo:            teamRepo,
+		courseRepo:          courseRepo,
+		workshopSessionRepo: workshopSessionRepo,
+		stepEvalRepo:        stepEvalRepo,
+	}
+}
+
+func (r *teamService) CreateTeam(body *payload.CreateTeam) (*payload.TeamInfo, error) {
+	course, err := r.courseRepo.FindCourseByCourseId(body.CourseId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find course %d: %w", *body.CourseId, err)
+	}
+
+	if body.SessionId != nil {
+		session, err := r.workshopSessionRepo.FindSessionById(*body.SessionId)
+		if err != nil {
+			return nil, err
+		}
+		if session == nil {
+			return nil, fmt.Errorf("session %d not found", *body.SessionId)
+		}
+		if *session.CourseId != *course.Id {
+			return nil, fmt.Errorf("session %d is not a session of course %d", *body.SessionId, *course.Id)
+		}
+	}
+
+	team := &models.Team{
+		CourseId:  course.Id,
+		Course:    course,
+		SessionId: body.SessionId,
+		Name:      body.Name,
+	}
+	if err := r.teamRepo.CreateTeam(team); err != nil {
+		return nil, err
+	}
+
+	return teamInfo(team), nil
+}
+
+func (r *teamService) AddMember(teamId uint64, body *payload.AddTeamMember) (*payload.TeamInfo, error) {
+	team, err := r.findTeam(teamId)
+	if err != nil {
+		return nil, err
+	}
+
+	// a submission counts for a single team per course
+	existing, err := r.teamRepo.FindTeamOfUser(*body.UserId, *team.CourseId)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && *existing.Id != teamId {
+		return nil, fmt.Errorf("user %d is already a member of team %d of this course", *body.UserId, *existing.Id)
+	}
+
+	if err := r.teamRepo.SaveMember(&models.TeamMember{
+		TeamId: team.Id,
+		UserId: body.UserId,
+	}); err != nil {
+		return nil, err
+	}
+
+	return r.GetTeam(teamId)
+}
+
+func (r *teamService) GetTeam(teamId uint64) (*payload.TeamInfo, error) {
+	team, err := r.findTeam(teamId)
+	if err != nil {
+		return nil, err
+	}
+
+	return teamInfo(team), nil
+}
+
+func (r *teamService) SetTeamEligible(stepEvalId uint64, body *payload.TeamEligibleBody) (*payload.StepEvalTeamEligible, error) {
+	stepEval, err := r.stepEvalRepo.GetStepEvalById(&stepEvalId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find step evaluation %d: %w", stepEvalId, err)
+	}
+
+	stepEval.TeamEligible = body.TeamEligible
+	if err := r.stepEvalRepo.UpdateStepEval(stepEval); err != nil {
+		return nil, err
+	}
+
+	return &payload.StepEvalTeamEligible{
+		StepEvalId:   stepEval.Id,
+		TeamEligible: stepEval.TeamEligible,
+	}, nil
+}
+
+func (r *teamService) findTeam(teamId uint64) (*models.Team, error) {
+	team, err := r.teamRepo.FindTeamById(teamId)
+	if err != nil {
+		return nil, err
+	}
+	if team == nil {
+		return nil, fmt.Errorf("team %d not found", teamId)
+	}
+
+	return team, nil
+}
+
+func teamInfo(team *models.Team) *payload.TeamInfo {
+	info := &payload.TeamInfo{
+		TeamId:    team.Id,
+		CourseId:  team.CourseId,
+		SessionId: team.SessionId,
+		Name:      team.Name,
+		Members:   make([]*payload.UserInfo, 0, len(team.Members)),
+	}
+	if team.Course != nil {
+		info.CourseName = team.Course.Name
+	}
+	for _, member := range team.Members {
+		if member.User != nil {
+			info.Members = append(info.Members, userInfo(member.User))
+		}
+	}
+
+	return info
+}
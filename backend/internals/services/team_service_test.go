@@ -0,0 +1,117 @@
+package services
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/utils"
+	mockRepositories "backend/mocks/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type TeamServiceTestSuite struct {
+	suite.Suite
+}
+
+func mockTeam() *models.Team {
+	return &models.Team{
+		Id:       utils.Ptr(uint64(6)),
+		CourseId: utils.Ptr(uint64(7)),
+		Course:   &models.Course{Id: utils.Ptr(uint64(7)), Name: utils.Ptr("IoT 101")},
+		Name:     utils.Ptr("Team A"),
+		Members: []*models.TeamMember{
+			{UserId: utils.Ptr(uint64(1)), User: &models.User{Id: utils.Ptr(uint64(1)), Firstname: utils.Ptr("Somchai")}},
+			{UserId: utils.Ptr(uint64(2)), User: &models.User{Id: utils.Ptr(uint64(2)), Firstname: utils.Ptr("Suda")}},
+		},
+	}
+}
+
+func (suite *TeamServiceTestSuite) TestCreateTeamWhenSessionOfAnotherCourse() {
+	is := assert.New(suite.T())
+
+	mockTeamRepo := new(mockRepositories.TeamRepository)
+	mockCourseRepo := new(mockRepositories.CourseRepository)
+	mockWorkshopSessionRepo := new(mockRepositories.WorkshopSessionRepository)
+
+	mockCourseRepo.EXPECT().FindCourseByCourseId(utils.Ptr(uint64(7))).Return(&models.Course{Id: utils.Ptr(uint64(7))}, nil)
+	mockWorkshopSessionRepo.EXPECT().FindSessionById(uint64(4)).Return(&models.WorkshopSession{Id: utils.Ptr(uint64(4)), CourseId: utils.Ptr(uint64(8))}, nil)
+
+	underTest := NewTeamService(mockTeamRepo, mockCourseRepo, mockWorkshopSessionRepo, nil)
+
+	team, err := underTest.CreateTeam(&payload.CreateTeam{
+		CourseId:  utils.Ptr(uint64(7)),
+		SessionId: utils.Ptr(uint64(4)),
+		Name:      utils.Ptr("Team A"),
+	})
+
+	is.Nil(team)
+	is.Equal("session 4 is not a session of course 7", err.Error())
+	mockTeamRepo.AssertNotCalled(suite.T(), "CreateTeam", mock.Anything)
+}
+
+func (suite *TeamServiceTestSuite) TestAddMemberWhenMemberOfAnotherTeam() {
+	is := assert.New(suite.T())
+
+	mockTeamRepo := new(mockRepositories.TeamRepository)
+
+	other := mockTeam()
+	other.Id = utils.Ptr(uint64(5))
+
+	mockTeamRepo.EXPECT().FindTeamById(uint64(6)).Return(mockTeam(), nil)
+	mockTeamRepo.EXPECT().FindTeamOfUser(uint64(9), uint64(7)).Return(other, nil)
+
+	underTest := NewTeamService(mockTeamRepo, nil, nil, nil)
+
+	team, err := underTest.AddMember(6, &payload.AddTeamMember{UserId: utils.Ptr(uint64(9))})
+
+	is.Nil(team)
+	is.Equal("user 9 is already a member of team 5 of this course", err.Error())
+	mockTeamRepo.AssertNotCalled(suite.T(), "SaveMember", mock.Anything)
+}
+
+func (suite *TeamServiceTestSuite) TestAddMemberWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockTeamRepo := new(mockRepositories.TeamRepository)
+
+	mockTeamRepo.EXPECT().FindTeamById(uint64(6)).Return(mockTeam(), nil)
+	mockTeamRepo.EXPECT().FindTeamOfUser(uint64(2), uint64(7)).Return(nil, nil)
+	mockTeamRepo.EXPECT().SaveMember(&models.TeamMember{
+		TeamId: utils.Ptr(uint64(6)),
+		UserId: utils.Ptr(uint64(2)),
+	}).Return(nil)
+
+	underTest := NewTeamService(mockTeamRepo, nil, nil, nil)
+
+	team, err := underTest.AddMember(6, &payload.AddTeamMember{UserId: utils.Ptr(uint64(2))})
+
+	is.Nil(err)
+	is.Equal("IoT 101", *team.CourseName)
+	is.Len(team.Members, 2)
+	is.Equal("Suda", *team.Members[1].FirstName)
+}
+
+func (suite *TeamServiceTestSuite) TestSetTeamEligibleWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockStepEvalRepo := new(mockRepositories.StepEvaluateRepository)
+
+	mockStepEvalRepo.EXPECT().GetStepEvalById(utils.Ptr(uint64(12))).Return(&models.StepEvaluate{Id: utils.Ptr(uint64(12))}, nil)
+	mockStepEvalRepo.EXPECT().UpdateStepEval(mock.MatchedBy(func(stepEval *models.StepEvaluate) bool {
+		return *stepEval.TeamEligible
+	})).Return(nil)
+
+	underTest := NewTeamService(nil, nil, nil, mockStepEvalRepo)
+
+	stepEval, err := underTest.SetTeamEligible(12, &payload.TeamEligibleBody{TeamEligible: utils.Ptr(true)})
+
+	is.Nil(err)
+	is.Equal(uint64(12), *stepEval.StepEvalId)
+	is.True(*stepEval.TeamEligible)
+}
+
+func TestTeamService(t *testing.T) {
+	suite.Run(t, new(TeamServiceTestSuite))
+}
@@ -4,5 +4,6 @@ import "backend/internals/entities/payload"
 
 type UserActivityService interface {
 	GetRecentActivitiesByUserID(userId *string) (*payload.UserActivitiesResponse, error)
-	UpdateUserActivity(userId uint64, stepId uint64) error
+	UpdateUserActivity(userId uint64, stepId uint64, courseId *uint64) error
+	RecordHeartbeat(userId uint64, stepId uint64, body *payload.Heartbeat) (*payload.StepSessionInfo, error)
 }
@@ -3,19 +3,38 @@ package services
 import (
 	"backend/internals/entities/payload"
 	"backend/internals/repositories"
+	"backend/internals/utils"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// stepSessionGap is the pause between heartbeats ending a session, like closing the page
+	stepSessionGap = 5 * time.Minute
+	// heartbeatMaxCredit caps the time a heartbeat adds, pages beat every 30 seconds while open
+	heartbeatMaxCredit = time.Minute
 )
 
 type userActivityService struct {
 	userActivityRepo  repositories.UserActivityRepository
+	stepSessionRepo   repositories.StepSessionRepository
 	stepRepo          repositories.StepRepository
 	courseContentRepo repositories.CourseContentRepository
+	streakSvc         StreakService
+	achievementSvc    AchievementService
+	xapiSvc           XapiService
 }
 
-func NewUserActivityService(userActivityRepo repositories.UserActivityRepository, stepRepo repositories.StepRepository, courseContentRepo repositories.CourseContentRepository) UserActivityService {
+func NewUserActivityService(userActivityRepo repositories.UserActivityRepository, stepSessionRepo repositories.StepSessionRepository, stepRepo repositories.StepRepository, courseContentRepo repositories.CourseContentRepository, streakSvc StreakService, achievementSvc AchievementService, xapiSvc XapiService) UserActivityService {
 	return &userActivityService{
 		userActivityRepo:  userActivityRepo,
+		stepSessionRepo:   stepSessionRepo,
 		stepRepo:          stepRepo,
 		courseContentRepo: courseContentRepo,
+		streakSvc:         streakSvc,
+		achievementSvc:    achievementSvc,
+		xapiSvc:           xapiSvc,
 	}
 }
 
@@ -42,9 +61,17 @@ func (s *userActivityService) GetRecentActivitiesByUserID(userId *string) (*payl
 			return nil, err
 		}
 
-		courseId, err := s.courseContentRepo.GetCourseIdByModuleId(moduleId)
-		if err != nil {
-			return nil, err
+		// activities recorded before course context was tracked fall back to the module's first course
+		courseId := activity.CourseId
+		if courseId == nil {
+			courseIds, err := s.courseContentRepo.GetCourseIdsByModuleId(moduleId)
+			if err != nil {
+				return nil, err
+			}
+			if len(courseIds) == 0 {
+				continue
+			}
+			courseId = &courseIds[0]
 		}
 
 		activityResponses = append(activityResponses, payload.UserActivityResponse{
@@ -63,11 +90,57 @@ func (s *userActivityService) GetRecentActivitiesByUserID(userId *string) (*payl
 	}, nil
 }
 
-func (s *userActivityService) UpdateUserActivity(userId uint64, stepId uint64) error {
-	err := s.userActivityRepo.UpdateUserActivity(userId, stepId)
+func (s *userActivityService) UpdateUserActivity(userId uint64, stepId uint64, courseId *uint64) error {
+	moduleId, err := s.stepRepo.GetModuleIdByStepId(&stepId)
 	if err != nil {
 		return err
 	}
 
+	courseContent, err := resolveCourseContent(s.courseContentRepo, courseId, moduleId)
+	if err != nil {
+		return err
+	}
+
+	err = s.userActivityRepo.UpdateUserActivity(userId, stepId, courseContent.CourseId)
+	if err != nil {
+		return err
+	}
+	if err := s.streakSvc.RecordActivity(userId); err != nil {
+		return err
+	}
+	s.achievementSvc.Record(userId, payload.AchievementTriggerActivityRecorded)
+	// the visit is saved, a failed statement must not fail it
+	if err := s.xapiSvc.RecordExperienced(userId, stepId, courseContent.CourseId); err != nil {
+		logrus.Errorf("[XAPI] Unable to record the visit of step %d: %v", stepId, err)
+	}
+
 	return nil
 }
+
+// RecordHeartbeat counts the time of the user on the step since their previous heartbeat, in the
+// course the step is visited in.
+func (s *userActivityService) RecordHeartbeat(userId uint64, stepId uint64, body *payload.Heartbeat) (*payload.StepSessionInfo, error) {
+	moduleId, err := s.stepRepo.GetModuleIdByStepId(&stepId)
+	if err != nil {
+		return nil, err
+	}
+
+	courseContent, err := resolveCourseContent(s.courseContentRepo, body.CourseId, moduleId)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.stepSessionRepo.RecordHeartbeat(userId, stepId, *courseContent.CourseId, utils.TimeNow(), utils.Val(body.Idle), stepSessionGap, heartbeatMaxCredit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &payload.StepSessionInfo{
+		SessionId:     session.Id,
+		StepId:        session.StepId,
+		CourseId:      session.CourseId,
+		StartedAt:     session.StartedAt,
+		LastSeenAt:    session.LastSeenAt,
+		ActiveSeconds: session.ActiveSeconds,
+	}, nil
+}
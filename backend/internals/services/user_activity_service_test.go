@@ -0,0 +1,83 @@
+package services
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/utils"
+	mockRepositories "backend/mocks/repositories"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type UserActivityServiceTestSuite struct {
+	suite.Suite
+}
+
+func (suite *UserActivityServiceTestSuite) TestRecordHeartbeatWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockStepSessionRepo := new(mockRepositories.StepSessionRepository)
+	mockStepRepo := new(mockRepositories.StepRepository)
+	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
+
+	mockStepRepo.EXPECT().GetModuleIdByStepId(utils.Ptr(uint64(8))).Return(utils.Ptr(uint64(5)), nil)
+	mockCourseContentRepo.EXPECT().FindCourseContentByCourseIdAndModuleId(utils.Ptr(uint64(3)), utils.Ptr(uint64(5))).
+		Return(&models.CourseContent{CourseId: utils.Ptr(uint64(3)), ModuleId: utils.Ptr(uint64(5))}, nil)
+	mockStepSessionRepo.EXPECT().RecordHeartbeat(uint64(4), uint64(8), uint64(3), mock.AnythingOfType("time.Time"), true, stepSessionGap, heartbeatMaxCredit).
+		Return(&models.StepSession{
+			Id:            utils.Ptr(uint64(1)),
+			StepId:        utils.Ptr(uint64(8)),
+			CourseId:      utils.Ptr(uint64(3)),
+			StartedAt:     utils.Ptr(utils.TimeNow().Add(-time.Minute)),
+			LastSeenAt:    utils.TimeNowPtr(),
+			ActiveSeconds: utils.Ptr(int64(30)),
+		}, nil)
+
+	underTest := NewUserActivityService(nil, mockStepSessionRepo, mockStepRepo, mockCourseContentRepo, nil, nil, nil)
+
+	session, err := underTest.RecordHeartbeat(4, 8, &payload.Heartbeat{CourseId: utils.Ptr(uint64(3)), Idle: utils.Ptr(true)})
+
+	is.Nil(err)
+	is.Equal(uint64(1), *session.SessionId)
+	is.Equal(int64(30), *session.ActiveSeconds)
+}
+
+func (suite *UserActivityServiceTestSuite) TestRecordHeartbeatWhenModuleShared() {
+	is := assert.New(suite.T())
+
+	mockStepRepo := new(mockRepositories.StepRepository)
+	mockCourseContentRepo := new(mockRepositories.CourseContentRepository)
+
+	mockStepRepo.EXPECT().GetModuleIdByStepId(utils.Ptr(uint64(8))).Return(utils.Ptr(uint64(5)), nil)
+	mockCourseContentRepo.EXPECT().GetCourseIdsByModuleId(utils.Ptr(uint64(5))).Return([]uint64{3, 6}, nil)
+
+	underTest := NewUserActivityService(nil, nil, mockStepRepo, mockCourseContentRepo, nil, nil, nil)
+
+	session, err := underTest.RecordHeartbeat(4, 8, &payload.Heartbeat{})
+
+	is.Nil(session)
+	is.Error(err)
+}
+
+func (suite *UserActivityServiceTestSuite) TestRecordHeartbeatWhenFailed() {
+	is := assert.New(suite.T())
+
+	mockStepRepo := new(mockRepositories.StepRepository)
+	mockStepRepo.EXPECT().GetModuleIdByStepId(utils.Ptr(uint64(8))).Return(nil, fmt.Errorf("database error"))
+
+	underTest := NewUserActivityService(nil, nil, mockStepRepo, nil, nil, nil, nil)
+
+	session, err := underTest.RecordHeartbeat(4, 8, &payload.Heartbeat{})
+
+	is.Nil(session)
+	is.Error(err)
+}
+
+func TestUserActivityService(t *testing.T) {
+	suite.Run(t, new(UserActivityServiceTestSuite))
+}
@@ -1,8 +1,13 @@
 package services
 
-import "backend/internals/entities/payload"
+import (
+	"backend/internals/entities/payload"
+	"context"
+)
 
 type UserStrengthService interface {
 	GetStrengthDataByUserID(userId uint64) (*payload.StrengthDataResponse, error)
+	GetStrengthRadar(userId uint64, query *payload.StrengthRadarQuery) (*payload.StrengthRadar, error)
 	GetSuggestionCourse(userId uint64) ([]payload.CourseResponse, error)
+	Run(ctx context.Context)
 }
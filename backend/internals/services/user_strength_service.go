@@ -4,90 +4,307 @@ import (
 	"backend/internals/entities/payload"
 	"backend/internals/repositories"
 	"backend/internals/utils"
+	"context"
 	"fmt"
 	"log"
+	"math"
+	"math/rand"
+	"sort"
 	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	strengthSnapshotInterval = 6 * time.Hour
+	strengthHistoryWeeks     = 12
+	strengthWeakestFields    = 3
+	strengthStepSuggestions  = 5 // steps suggested for each weak field
+)
+
+// Weights of the course suggestion score, each part being between 0 and 1.
+const (
+	courseSuggestions          = 5
+	suggestionGapWeight        = 0.5
+	suggestionPopularityWeight = 0.3
+	suggestionRecencyWeight    = 0.15
+	suggestionJitterWeight     = 0.05 // rotates close suggestions from one day to the next
+	suggestionRecencyHalfLife  = 90 * 24 * time.Hour
 )
 
 type userStrengthService struct {
 	userStrengthRepo repositories.UserStrengthRepository
-	fieldTypeRepo    repositories.FieldTypeRepository
 	userRepo         repositories.UserRepository
+	now              func() time.Time
+	seed             int64
 }
 
-func NewUserStrengthService(userStrengthRepo repositories.UserStrengthRepository, fieldTypeRepo repositories.FieldTypeRepository, userRepo repositories.UserRepository) UserStrengthService {
+// NewUserStrengthService creates the service with the clock it reads the time from and the seed
+// ordering close course suggestions, the same clock and seed always giving the same suggestions.
+func NewUserStrengthService(userStrengthRepo repositories.UserStrengthRepository, userRepo repositories.UserRepository, now func() time.Time, seed int64) UserStrengthService {
 	return &userStrengthService{
 		userStrengthRepo: userStrengthRepo,
-		fieldTypeRepo:    fieldTypeRepo,
 		userRepo:         userRepo,
+		now:              now,
+		seed:             seed,
 	}
 }
 
 func (s *userStrengthService) GetStrengthDataByUserID(userId uint64) (*payload.StrengthDataResponse, error) {
-	strengthFieldData, err := s.userStrengthRepo.GetStrengthDataByUserID(userId)
+	strengthFieldData, err := s.fieldStrengths(userId)
 	if err != nil {
 		log.Printf("Error in service while fetching strength data for user %d: %v", userId, err)
 		return nil, err
 	}
-	fieldTypes, err := s.fieldTypeRepo.FindAllFieldTypes()
+
+	user, err := s.userRepo.FindUserByID(utils.Ptr(strconv.FormatUint(userId, 10)))
 	if err != nil {
 		return nil, err
 	}
 
-	if strengthFieldData == nil {
-		log.Printf("No passing evaluations found for user %d", userId)
-		return &payload.StrengthDataResponse{}, nil
+	strengthData := &payload.StrengthDataResponse{
+		Data:     strengthFieldData,
+		Username: fmt.Sprintf("%s %s", *user.Firstname, *user.Lastname),
 	}
 
-	strengthField := make([]string, 0)
-	for _, strength := range strengthFieldData {
-		strengthField = append(strengthField, strength.FieldName)
+	return strengthData, nil
+}
+
+// GetStrengthRadar returns the strengths of the user in the weeks snapshotted, with those of the
+// current week computed now, and the weakest fields with the steps that would improve them.
+func (s *userStrengthService) GetStrengthRadar(userId uint64, query *payload.StrengthRadarQuery) (*payload.StrengthRadar, error) {
+	weeks := strengthHistoryWeeks
+	if query.Weeks != nil {
+		weeks = *query.Weeks
+	}
+	currentWeek := utils.TimeWeekStart(s.now())
+
+	snapshots, err := s.userStrengthRepo.FindStrengthSnapshots(userId, currentWeek.AddDate(0, 0, -7*(weeks-1)))
+	if err != nil {
+		return nil, err
 	}
 
-	for _, item := range fieldTypes {
-		if !utils.Contains(strengthField, *item.Name) {
-			strengthFieldData = append(strengthFieldData, payload.StrengthFieldData{
-				FieldName: *item.Name,
-				TotalGems: int64(0),
-			})
+	radar := &payload.StrengthRadar{
+		Weeks:   make([]*payload.StrengthWeek, 0, weeks),
+		Weakest: make([]*payload.WeakField, 0, strengthWeakestFields),
+	}
+	for _, snapshot := range snapshots {
+		// dates are stored at UTC midnight, weeks start at Bangkok midnight
+		weekStart := time.Date(snapshot.WeekStart.Year(), snapshot.WeekStart.Month(), snapshot.WeekStart.Day(), 0, 0, 0, 0, utils.BangkokTime)
+		if !weekStart.Before(currentWeek) {
+			continue
 		}
+		if len(radar.Weeks) == 0 || !radar.Weeks[len(radar.Weeks)-1].WeekStart.Equal(weekStart) {
+			radar.Weeks = append(radar.Weeks, &payload.StrengthWeek{WeekStart: weekStart})
+		}
+		week := radar.Weeks[len(radar.Weeks)-1]
+		week.Fields = append(week.Fields, payload.StrengthFieldData{
+			FieldId:       snapshot.FieldId,
+			FieldName:     utils.Val(snapshot.Field.Name),
+			TotalGems:     *snapshot.EarnedGems,
+			AvailableGems: *snapshot.AvailableGems,
+			Strength:      strengthPercent(*snapshot.EarnedGems, *snapshot.AvailableGems),
+		})
 	}
 
-	user, err := s.userRepo.FindUserByID(utils.Ptr(strconv.FormatUint(userId, 10)))
+	current, err := s.fieldStrengths(userId)
 	if err != nil {
 		return nil, err
 	}
+	radar.Weeks = append(radar.Weeks, &payload.StrengthWeek{WeekStart: currentWeek, Fields: current})
 
-	strengthData := &payload.StrengthDataResponse{
-		Data:     strengthFieldData,
-		Username: fmt.Sprintf("%s %s", *user.Firstname, *user.Lastname),
+	for _, field := range weakestFields(current, strengthWeakestFields) {
+		steps, err := s.userStrengthRepo.FindUnfinishedSteps(userId, *field.FieldId, strengthStepSuggestions)
+		if err != nil {
+			return nil, err
+		}
+		radar.Weakest = append(radar.Weakest, &payload.WeakField{StrengthFieldData: field, Steps: steps})
 	}
 
-	return strengthData, nil
+	return radar, nil
+}
+
+// Run snapshots the strengths of the current week until the context is cancelled, so the
+// snapshot of a week holds the strengths at its end.
+func (s *userStrengthService) Run(ctx context.Context) {
+	ticker := time.NewTicker(strengthSnapshotInterval)
+	defer ticker.Stop()
+
+	s.snapshot()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.snapshot()
+		}
+	}
+}
+
+func (s *userStrengthService) snapshot() {
+	if _, err := s.userStrengthRepo.SnapshotStrengths(utils.TimeWeekStart(s.now())); err != nil {
+		logrus.Errorf("[STRENGTH] Unable to snapshot strengths: %v", err)
+	}
+}
+
+func (s *userStrengthService) fieldStrengths(userId uint64) ([]payload.StrengthFieldData, error) {
+	strengthFieldData, err := s.userStrengthRepo.GetStrengthDataByUserID(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range strengthFieldData {
+		strengthFieldData[i].Strength = strengthPercent(strengthFieldData[i].TotalGems, strengthFieldData[i].AvailableGems)
+	}
+
+	return strengthFieldData, nil
+}
+
+// strengthPercent returns the percent of the available gems earned, to one decimal. A field
+// offering no gems yet has no strength.
+func strengthPercent(earned int64, available int64) float64 {
+	if available <= 0 {
+		return 0
+	}
+
+	return math.Round(float64(earned)*1000/float64(available)) / 10
 }
 
+// weakestFields returns the fields with the lowest strength, leaving out those offering no gems
+// and those fully earned.
+func weakestFields(fields []payload.StrengthFieldData, limit int) []payload.StrengthFieldData {
+	weakest := make([]payload.StrengthFieldData, 0, len(fields))
+	for _, field := range fields {
+		if field.AvailableGems > 0 && field.TotalGems < field.AvailableGems {
+			weakest = append(weakest, field)
+		}
+	}
+
+	sort.SliceStable(weakest, func(i, j int) bool {
+		return weakest[i].Strength < weakest[j].Strength
+	})
+	if len(weakest) > limit {
+		weakest = weakest[:limit]
+	}
+
+	return weakest
+}
+
+// GetSuggestionCourse suggests the courses the user is neither enrolled in nor done with, scored by
+// how weak the user is in their field, how popular they are among learners sharing a course with
+// the user, and how new they are.
 func (s *userStrengthService) GetSuggestionCourse(userId uint64) ([]payload.CourseResponse, error) {
-	courses, err := s.userStrengthRepo.GetSuggestionCourse(userId)
+	candidates, err := s.userStrengthRepo.FindCourseCandidates(userId)
+	if err != nil {
+		log.Printf("Error in service while fetching course candidates for user %d: %v", userId, err)
+		return nil, err
+	}
+
+	strengths, err := s.fieldStrengths(userId)
 	if err != nil {
-		log.Printf("Error in service while fetching random courses for user %d: %v", userId, err)
 		return nil, err
 	}
 
-	// Transform to response DTOs
-	var responses []payload.CourseResponse
-	for _, course := range courses {
-		if course.Id != nil && course.Name != nil && course.Field != nil && course.Field.Id != nil && course.Field.Name != nil {
-			responses = append(responses, payload.CourseResponse{
-				ID:   *course.Id,
-				Name: *course.Name,
-				Field: payload.FieldResponse{
-					ID:       *course.Field.Id,
-					Name:     *course.Field.Name,
-					ImageUrl: course.Field.ImageUrl,
-				},
-			})
+	return s.recommendCourses(userId, candidates, strengths, courseSuggestions), nil
+}
+
+type courseSuggestion struct {
+	candidate *payload.CourseCandidate
+	score     float64
+	reason    string
+}
+
+// recommendCourses returns the candidates with the best scores, with the part weighing the most
+// in the score of each as its reason. Close scores are ordered by a jitter drawn from the seed,
+// the user and the day, so suggestions rotate daily but never between two requests.
+func (s *userStrengthService) recommendCourses(userId uint64, candidates []*payload.CourseCandidate, strengths []payload.StrengthFieldData, limit int) []payload.CourseResponse {
+	now := s.now()
+
+	fieldStrengths := make(map[uint64]payload.StrengthFieldData, len(strengths))
+	for _, strength := range strengths {
+		fieldStrengths[*strength.FieldId] = strength
+	}
+
+	// co-enrollments are only known for users enrolled somewhere, others get the overall popularity
+	var maxCoEnrollments, maxEnrollments int64
+	for _, candidate := range candidates {
+		maxCoEnrollments = max(maxCoEnrollments, candidate.CoEnrollments)
+		maxEnrollments = max(maxEnrollments, candidate.Enrollments)
+	}
+
+	day := utils.TimeDate(utils.TimeInBangkok(now)).Unix() / int64(24*time.Hour/time.Second)
+	jitter := rand.New(rand.NewSource(s.seed ^ int64(userId)<<20 ^ day))
+
+	suggestions := make([]*courseSuggestion, 0, len(candidates))
+	for _, candidate := range candidates {
+		field := fieldStrengths[utils.Val(candidate.FieldId)]
+		gap := 1 - field.Strength/100
+
+		var popularity float64
+		var popularityReason string
+		if maxCoEnrollments > 0 {
+			popularity = float64(candidate.CoEnrollments) / float64(maxCoEnrollments)
+			popularityReason = fmt.Sprintf("Taken by %d learners who share a course with you", candidate.CoEnrollments)
+		} else if maxEnrollments > 0 {
+			popularity = float64(candidate.Enrollments) / float64(maxEnrollments)
+			popularityReason = fmt.Sprintf("Taken by %d learners", candidate.Enrollments)
+		}
+
+		age := max(now.Sub(utils.Val(candidate.CreatedAt)), 0)
+		recency := math.Pow(0.5, float64(age)/float64(suggestionRecencyHalfLife))
+
+		parts := []struct {
+			weight float64
+			reason string
+		}{
+			{suggestionGapWeight * gap, gapReason(utils.Val(candidate.FieldName), field)},
+			{suggestionPopularityWeight * popularity, popularityReason},
+			{suggestionRecencyWeight * recency, fmt.Sprintf("New course, added on %s", utils.TimeInBangkok(utils.Val(candidate.CreatedAt)).Format("2 Jan 2006"))},
 		}
+		suggestion := &courseSuggestion{
+			candidate: candidate,
+			score:     suggestionJitterWeight * jitter.Float64(),
+		}
+		var strongest float64
+		for _, part := range parts {
+			suggestion.score += part.weight
+			if part.weight > strongest {
+				strongest = part.weight
+				suggestion.reason = part.reason
+			}
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].score > suggestions[j].score
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+
+	responses := make([]payload.CourseResponse, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		responses = append(responses, payload.CourseResponse{
+			ID:   utils.Val(suggestion.candidate.CourseId),
+			Name: utils.Val(suggestion.candidate.Name),
+			Field: payload.FieldResponse{
+				ID:       utils.Val(suggestion.candidate.FieldId),
+				Name:     utils.Val(suggestion.candidate.FieldName),
+				ImageUrl: suggestion.candidate.FieldImageUrl,
+			},
+			Reason: suggestion.reason,
+		})
+	}
+
+	return responses
+}
+
+func gapReason(fieldName string, field payload.StrengthFieldData) string {
+	if field.TotalGems == 0 {
+		return fmt.Sprintf("Starts you in %s", fieldName)
 	}
 
-	return responses, nil
+	return fmt.Sprintf("Builds up %s, where you have earned %.0f%% of the gems", fieldName, field.Strength)
 }
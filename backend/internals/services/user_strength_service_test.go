@@ -0,0 +1,240 @@
+package services
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/utils"
+	mockRepositories "backend/mocks/repositories"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type UserStrengthServiceTestSuite struct {
+	suite.Suite
+}
+
+func mockFieldStrengths() []payload.StrengthFieldData {
+	return []payload.StrengthFieldData{
+		{FieldId: utils.Ptr(uint64(1)), FieldName: "Electronics", TotalGems: 30, AvailableGems: 120},
+		{FieldId: utils.Ptr(uint64(2)), FieldName: "Networking", TotalGems: 10, AvailableGems: 15},
+		{FieldId: utils.Ptr(uint64(3)), FieldName: "Cloud", TotalGems: 0, AvailableGems: 0},
+		{FieldId: utils.Ptr(uint64(4)), FieldName: "Embedded", TotalGems: 40, AvailableGems: 40},
+		{FieldId: utils.Ptr(uint64(5)), FieldName: "Security", TotalGems: 1, AvailableGems: 30},
+	}
+}
+
+func (suite *UserStrengthServiceTestSuite) TestGetStrengthDataByUserIDWhenNormalized() {
+	is := assert.New(suite.T())
+
+	mockUserStrengthRepo := new(mockRepositories.UserStrengthRepository)
+	mockUserRepo := new(mockRepositories.UserRepository)
+
+	mockUserStrengthRepo.EXPECT().GetStrengthDataByUserID(uint64(9)).Return(mockFieldStrengths(), nil)
+	mockUserRepo.EXPECT().FindUserByID(utils.Ptr("9")).Return(&models.User{Firstname: utils.Ptr("Somchai"), Lastname: utils.Ptr("Jaidee")}, nil)
+
+	underTest := NewUserStrengthService(mockUserStrengthRepo, mockUserRepo, utils.TimeNow, 0)
+
+	strengthData, err := underTest.GetStrengthDataByUserID(9)
+
+	is.Nil(err)
+	is.Equal("Somchai Jaidee", strengthData.Username)
+	// a field with more content is not stronger for it
+	is.Equal(25.0, strengthData.Data[0].Strength)
+	is.Equal(66.7, strengthData.Data[1].Strength)
+	is.Equal(0.0, strengthData.Data[2].Strength)
+	is.Equal(100.0, strengthData.Data[3].Strength)
+	is.Equal(3.3, strengthData.Data[4].Strength)
+}
+
+func (suite *UserStrengthServiceTestSuite) TestGetStrengthRadarWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockUserStrengthRepo := new(mockRepositories.UserStrengthRepository)
+
+	currentWeek := utils.TimeWeekStart(utils.TimeNow())
+	snapshot := func(weeksAgo int, fieldId uint64, earned int64) *models.StrengthSnapshot {
+		return &models.StrengthSnapshot{
+			WeekStart:     utils.Ptr(utils.TimeDate(currentWeek.AddDate(0, 0, -7*weeksAgo))),
+			FieldId:       utils.Ptr(fieldId),
+			Field:         &models.FieldType{Id: utils.Ptr(fieldId), Name: utils.Ptr("Electronics")},
+			EarnedGems:    utils.Ptr(earned),
+			AvailableGems: utils.Ptr(int64(120)),
+		}
+	}
+	mockUserStrengthRepo.EXPECT().FindStrengthSnapshots(uint64(9), currentWeek.AddDate(0, 0, -7*3)).Return([]*models.StrengthSnapshot{
+		snapshot(2, 1, 6),
+		snapshot(2, 2, 0),
+		snapshot(1, 1, 12),
+		snapshot(1, 2, 0),
+		// the snapshot of the current week is older than the strengths computed now
+		snapshot(0, 1, 24),
+	}, nil)
+	mockUserStrengthRepo.EXPECT().GetStrengthDataByUserID(uint64(9)).Return(mockFieldStrengths(), nil)
+	mockUserStrengthRepo.EXPECT().FindUnfinishedSteps(uint64(9), mock.Anything, strengthStepSuggestions).RunAndReturn(func(userId uint64, fieldId uint64, limit int) ([]*payload.StrengthStep, error) {
+		return []*payload.StrengthStep{{StepId: utils.Ptr(fieldId * 10), Gems: 5}}, nil
+	})
+
+	underTest := NewUserStrengthService(mockUserStrengthRepo, nil, utils.TimeNow, 0)
+
+	radar, err := underTest.GetStrengthRadar(9, &payload.StrengthRadarQuery{Weeks: utils.Ptr(4)})
+
+	is.Nil(err)
+	is.Len(radar.Weeks, 3)
+	is.True(radar.Weeks[0].WeekStart.Equal(currentWeek.AddDate(0, 0, -14)))
+	is.Len(radar.Weeks[0].Fields, 2)
+	is.Equal(5.0, radar.Weeks[0].Fields[0].Strength)
+	is.Equal(10.0, radar.Weeks[1].Fields[0].Strength)
+	is.True(radar.Weeks[2].WeekStart.Equal(currentWeek))
+	is.Equal(25.0, radar.Weeks[2].Fields[0].Strength)
+
+	// fields offering no gems and those fully earned cannot be improved
+	is.Len(radar.Weakest, 3)
+	is.Equal("Security", radar.Weakest[0].FieldName)
+	is.Equal("Electronics", radar.Weakest[1].FieldName)
+	is.Equal("Networking", radar.Weakest[2].FieldName)
+	is.Equal(uint64(50), *radar.Weakest[0].Steps[0].StepId)
+}
+
+func (suite *UserStrengthServiceTestSuite) TestGetStrengthRadarWhenNoHistory() {
+	is := assert.New(suite.T())
+
+	mockUserStrengthRepo := new(mockRepositories.UserStrengthRepository)
+
+	currentWeek := utils.TimeWeekStart(utils.TimeNow())
+	mockUserStrengthRepo.EXPECT().FindStrengthSnapshots(uint64(9), currentWeek.AddDate(0, 0, -7*(strengthHistoryWeeks-1))).Return(nil, nil)
+	mockUserStrengthRepo.EXPECT().GetStrengthDataByUserID(uint64(9)).Return([]payload.StrengthFieldData{
+		{FieldId: utils.Ptr(uint64(1)), FieldName: "Electronics", AvailableGems: 120},
+	}, nil)
+	mockUserStrengthRepo.EXPECT().FindUnfinishedSteps(uint64(9), uint64(1), strengthStepSuggestions).Return(nil, nil)
+
+	underTest := NewUserStrengthService(mockUserStrengthRepo, nil, utils.TimeNow, 0)
+
+	radar, err := underTest.GetStrengthRadar(9, &payload.StrengthRadarQuery{})
+
+	is.Nil(err)
+	is.Len(radar.Weeks, 1)
+	is.Equal(0.0, radar.Weeks[0].Fields[0].Strength)
+	is.Len(radar.Weakest, 1)
+}
+
+func (suite *UserStrengthServiceTestSuite) TestSnapshotWhenCurrentWeek() {
+	mockUserStrengthRepo := new(mockRepositories.UserStrengthRepository)
+
+	mockUserStrengthRepo.EXPECT().SnapshotStrengths(mock.MatchedBy(func(weekStart time.Time) bool {
+		return weekStart.Equal(utils.TimeWeekStart(utils.TimeNow()))
+	})).Return(12, nil)
+
+	underTest := NewUserStrengthService(mockUserStrengthRepo, nil, utils.TimeNow, 0).(*userStrengthService)
+
+	underTest.snapshot()
+
+	mockUserStrengthRepo.AssertExpectations(suite.T())
+}
+
+func mockCourseCandidate(courseId uint64, fieldId uint64, fieldName string, createdAt time.Time, enrollments int64, coEnrollments int64) *payload.CourseCandidate {
+	return &payload.CourseCandidate{
+		CourseId:      utils.Ptr(courseId),
+		Name:          utils.Ptr("Course " + strconv.FormatUint(courseId, 10)),
+		FieldId:       utils.Ptr(fieldId),
+		FieldName:     utils.Ptr(fieldName),
+		CreatedAt:     utils.Ptr(createdAt),
+		Enrollments:   enrollments,
+		CoEnrollments: coEnrollments,
+	}
+}
+
+func (suite *UserStrengthServiceTestSuite) TestGetSuggestionCourseWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockUserStrengthRepo := new(mockRepositories.UserStrengthRepository)
+
+	now := time.Date(2024, 6, 15, 10, 0, 0, 0, time.UTC)
+	old := now.AddDate(-2, 0, 0)
+	mockUserStrengthRepo.EXPECT().FindCourseCandidates(uint64(9)).RunAndReturn(func(userId uint64) ([]*payload.CourseCandidate, error) {
+		return []*payload.CourseCandidate{
+			mockCourseCandidate(1, 4, "Embedded", old, 50, 0),
+			mockCourseCandidate(2, 1, "Electronics", old, 3, 0),
+			mockCourseCandidate(3, 4, "Embedded", old, 80, 20),
+			mockCourseCandidate(4, 4, "Embedded", now.AddDate(0, 0, -2), 0, 0),
+			mockCourseCandidate(5, 3, "Cloud", old, 1, 0),
+			mockCourseCandidate(6, 4, "Embedded", old, 2, 1),
+		}, nil
+	})
+	mockUserStrengthRepo.EXPECT().GetStrengthDataByUserID(uint64(9)).RunAndReturn(func(userId uint64) ([]payload.StrengthFieldData, error) {
+		return mockFieldStrengths(), nil
+	})
+
+	underTest := NewUserStrengthService(mockUserStrengthRepo, nil, func() time.Time { return now }, 42)
+
+	suggestions, err := underTest.GetSuggestionCourse(9)
+
+	is.Nil(err)
+	is.Len(suggestions, courseSuggestions)
+	// weak fields come first, then the courses learners sharing a course with the user took
+	is.Equal(uint64(5), suggestions[0].ID)
+	is.Equal("Starts you in Cloud", suggestions[0].Reason)
+	is.Equal(uint64(2), suggestions[1].ID)
+	is.Equal("Builds up Electronics, where you have earned 25% of the gems", suggestions[1].Reason)
+	is.Equal(uint64(3), suggestions[2].ID)
+	is.Equal("Taken by 20 learners who share a course with you", suggestions[2].Reason)
+	is.Equal(uint64(4), suggestions[3].ID)
+	is.Equal("New course, added on 13 Jun 2024", suggestions[3].Reason)
+	is.Equal("Embedded", suggestions[3].Field.Name)
+
+	// the same clock and seed always suggest the same courses
+	again, err := underTest.GetSuggestionCourse(9)
+
+	is.Nil(err)
+	is.Equal(suggestions, again)
+}
+
+func (suite *UserStrengthServiceTestSuite) TestGetSuggestionCourseWhenNotEnrolled() {
+	is := assert.New(suite.T())
+
+	mockUserStrengthRepo := new(mockRepositories.UserStrengthRepository)
+
+	now := time.Date(2024, 6, 15, 10, 0, 0, 0, time.UTC)
+	old := now.AddDate(-2, 0, 0)
+	mockUserStrengthRepo.EXPECT().FindCourseCandidates(uint64(9)).Return([]*payload.CourseCandidate{
+		mockCourseCandidate(1, 1, "Electronics", old, 5, 0),
+		mockCourseCandidate(2, 1, "Electronics", old, 40, 0),
+	}, nil)
+	mockUserStrengthRepo.EXPECT().GetStrengthDataByUserID(uint64(9)).Return([]payload.StrengthFieldData{
+		{FieldId: utils.Ptr(uint64(1)), FieldName: "Electronics", TotalGems: 120, AvailableGems: 120},
+	}, nil)
+
+	underTest := NewUserStrengthService(mockUserStrengthRepo, nil, func() time.Time { return now }, 42)
+
+	suggestions, err := underTest.GetSuggestionCourse(9)
+
+	is.Nil(err)
+	is.Len(suggestions, 2)
+	// without co-enrollments the courses are ranked by how many learners took them
+	is.Equal(uint64(2), suggestions[0].ID)
+	is.Equal("Taken by 40 learners", suggestions[0].Reason)
+}
+
+func (suite *UserStrengthServiceTestSuite) TestGetSuggestionCourseWhenNoCandidate() {
+	is := assert.New(suite.T())
+
+	mockUserStrengthRepo := new(mockRepositories.UserStrengthRepository)
+
+	mockUserStrengthRepo.EXPECT().FindCourseCandidates(uint64(9)).Return(nil, nil)
+	mockUserStrengthRepo.EXPECT().GetStrengthDataByUserID(uint64(9)).Return(mockFieldStrengths(), nil)
+
+	underTest := NewUserStrengthService(mockUserStrengthRepo, nil, utils.TimeNow, 0)
+
+	suggestions, err := underTest.GetSuggestionCourse(9)
+
+	is.Nil(err)
+	is.Empty(suggestions)
+}
+
+func TestUserStrengthService(t *testing.T) {
+	suite.Run(t, new(UserStrengthServiceTestSuite))
+}
@@ -0,0 +1,11 @@
+package services
+
+import "backend/internals/entities/payload"
+
+type WorkshopSessionService interface {
+	CreateSession(body *payload.CreateWorkshopSession) (*payload.WorkshopSessionInfo, error)
+	GetUpcomingSessions(courseId *uint64, fieldId *uint64) ([]*payload.WorkshopSessionInfo, error)
+	Register(sessionId uint64, userId uint64) (*payload.SessionRegistrationInfo, error)
+	Cancel(sessionId uint64, userId uint64) error
+	GetUserRegistrations(userId uint64) ([]*payload.SessionRegistrationInfo, error)
+}
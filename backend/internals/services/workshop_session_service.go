@@ -0,0 +1,183 @@
+package services
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/repositories"
+	"backend/internals/utils"
+	"fmt"
+)
+
+type workshopSessionService struct {
+	workshopSessionRepo repositories.WorkshopSessionRepository
+	courseRepo          repositories.CourseRepository
+}
+
+func NewWorkshopSessionService(workshopSessionRepo repositories.WorkshopSessionRepository, courseRepo repositories.CourseRepository) WorkshopSessionService {
+	return &workshopSessionService{
+		workshopSessionRepo: workshopSessionRepo,
+		courseRepo:          courseRepo,
+	}
+}
+
+func (r *workshopSessionService) CreateSession(body *payload.CreateWorkshopSession) (*payload.WorkshopSessionInfo, error) {
+	if !body.EndsAt.After(*body.StartsAt) {
+		return nil, fmt.Errorf("session must end after it starts")
+	}
+
+	course, err := r.courseRepo.FindCourseByCourseId(body.CourseId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find course %d: %w", *body.CourseId, err)
+	}
+
+	session := &models.WorkshopSession{
+		CourseId:  course.Id,
+		Course:    course,
+		Title:     body.Title,
+		StartsAt:  body.StartsAt,
+		EndsAt:    body.EndsAt,
+		Room:      body.Room,
+		OnlineUrl: body.OnlineUrl,
+		Capacity:  body.Capacity,
+	}
+	if err := r.workshopSessionRepo.CreateSession(session); err != nil {
+		return nil, err
+	}
+
+	return sessionInfo(session, nil), nil
+}
+
+func (r *workshopSessionService) GetUpcomingSessions(courseId *uint64, fieldId *uint64) ([]*payload.WorkshopSessionInfo, error) {
+	sessions, err := r.workshopSessionRepo.FindUpcomingSessions(courseId, fieldId, utils.TimeNow())
+	if err != nil {
+		return nil, err
+	}
+
+	sessionIds := make([]uint64, 0, len(sessions))
+	for _, session := range sessions {
+		sessionIds = append(sessionIds, *session.Id)
+	}
+
+	counts, err := r.workshopSessionRepo.CountRegistrationsBySessionIds(sessionIds)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*payload.WorkshopSessionInfo, 0, len(sessions))
+	for _, session := range sessions {
+		sessionCounts, ok := counts[*session.Id]
+		if !ok {
+			sessionCounts = make(map[string]int64)
+		}
+		result = append(result, sessionInfo(session, sessionCounts))
+	}
+
+	return result, nil
+}
+
+func (r *workshopSessionService) Register(sessionId uint64, userId uint64) (*payload.SessionRegistrationInfo, error) {
+	session, err := r.workshopSessionRepo.FindSessionById(sessionId)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, fmt.Errorf("session %d not found", sessionId)
+	}
+	if !session.StartsAt.After(utils.TimeNow()) {
+		return nil, fmt.Errorf("session %d has already started", sessionId)
+	}
+
+	registration, err := r.workshopSessionRepo.Register(sessionId, userId)
+	if err != nil {
+		return nil, err
+	}
+	registration.Session = session
+
+	return r.registrationInfo(registration)
+}
+
+func (r *workshopSessionService) Cancel(sessionId uint64, userId uint64) error {
+	session, err := r.workshopSessionRepo.FindSessionById(sessionId)
+	if err != nil {
+		return err
+	}
+	if session == nil {
+		return fmt.Errorf("session %d not found", sessionId)
+	}
+	if !session.StartsAt.After(utils.TimeNow()) {
+		return fmt.Errorf("session %d has already started", sessionId)
+	}
+
+	// the first waitlisted person, if any, takes the freed seat
+	if _, err := r.workshopSessionRepo.Cancel(sessionId, userId); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *workshopSessionService) GetUserRegistrations(userId uint64) ([]*payload.SessionRegistrationInfo, error) {
+	registrations, err := r.workshopSessionRepo.FindRegistrationsByUserId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*payload.SessionRegistrationInfo, 0, len(registrations))
+	for _, registration := range registrations {
+		info, err := r.registrationInfo(registration)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, info)
+	}
+
+	return result, nil
+}
+
+func (r *workshopSessionService) registrationInfo(registration *models.SessionRegistration) (*payload.SessionRegistrationInfo, error) {
+	info := &payload.SessionRegistrationInfo{
+		RegistrationId: registration.Id,
+		Status:         registration.Status,
+		RegisteredAt:   registration.QueuedAt,
+		Session:        sessionInfo(registration.Session, nil),
+	}
+
+	if *registration.Status == "waitlisted" {
+		ahead, err := r.workshopSessionRepo.CountWaitlistedBefore(registration)
+		if err != nil {
+			return nil, err
+		}
+		info.WaitlistPosition = utils.Ptr(ahead + 1)
+	}
+
+	return info, nil
+}
+
+// sessionInfo maps a session, counts holds its registrations per status when they are known.
+func sessionInfo(session *models.WorkshopSession, counts map[string]int64) *payload.WorkshopSessionInfo {
+	info := &payload.WorkshopSessionInfo{
+		SessionId: session.Id,
+		CourseId:  session.CourseId,
+		Title:     session.Title,
+		StartsAt:  session.StartsAt,
+		EndsAt:    session.EndsAt,
+		Room:      session.Room,
+		OnlineUrl: session.OnlineUrl,
+		Capacity:  session.Capacity,
+	}
+	if session.Course != nil {
+		info.CourseName = session.Course.Name
+	}
+
+	if counts != nil {
+		seatsLeft := int64(*session.Capacity) - counts["registered"]
+		if seatsLeft < 0 {
+			seatsLeft = 0
+		}
+		info.Registered = utils.Ptr(counts["registered"])
+		info.Waitlisted = utils.Ptr(counts["waitlisted"])
+		info.SeatsLeft = &seatsLeft
+	}
+
+	return info
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/repositories"
+	"backend/internals/utils"
+	mockRepositories "backend/mocks/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type WorkshopSessionServiceTestSuite struct {
+	suite.Suite
+}
+
+func mockWorkshopSession(startsAt time.Time) *models.WorkshopSession {
+	return &models.WorkshopSession{
+		Id:       utils.Ptr(uint64(4)),
+		CourseId: utils.Ptr(uint64(7)),
+		Course:   &models.Course{Id: utils.Ptr(uint64(7)), Name: utils.Ptr("IoT 101")},
+		Title:    utils.Ptr("Soldering lab"),
+		StartsAt: &startsAt,
+		EndsAt:   utils.Ptr(startsAt.Add(2 * time.Hour)),
+		Room:     utils.Ptr("HM-301"),
+		Capacity: utils.Ptr(2),
+	}
+}
+
+func (suite *WorkshopSessionServiceTestSuite) TestGetUpcomingSessionsWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockWorkshopSessionRepo := new(mockRepositories.WorkshopSessionRepository)
+	mockCourseRepo := new(mockRepositories.CourseRepository)
+
+	full := mockWorkshopSession(utils.TimeNow().Add(24 * time.Hour))
+	empty := mockWorkshopSession(utils.TimeNow().Add(48 * time.Hour))
+	empty.Id = utils.Ptr(uint64(5))
+
+	mockWorkshopSessionRepo.EXPECT().FindUpcomingSessions(utils.Ptr(uint64(7)), (*uint64)(nil), mock.Anything).Return([]*models.WorkshopSession{full, empty}, nil)
+	mockWorkshopSessionRepo.EXPECT().CountRegistrationsBySessionIds([]uint64{4, 5}).Return(map[uint64]map[string]int64{
+		4: {"registered": 2, "waitlisted": 3},
+	}, nil)
+
+	underTest := NewWorkshopSessionService(mockWorkshopSessionRepo, mockCourseRepo)
+
+	sessions, err := underTest.GetUpcomingSessions(utils.Ptr(uint64(7)), nil)
+
+	is.Nil(err)
+	is.Len(sessions, 2)
+	is.Equal("IoT 101", *sessions[0].CourseName)
+	is.Equal(int64(0), *sessions[0].SeatsLeft)
+	is.Equal(int64(3), *sessions[0].Waitlisted)
+	is.Equal(int64(2), *sessions[1].SeatsLeft)
+	is.Equal(int64(0), *sessions[1].Registered)
+}
+
+func (suite *WorkshopSessionServiceTestSuite) TestRegisterWhenWaitlisted() {
+	is := assert.New(suite.T())
+
+	mockWorkshopSessionRepo := new(mockRepositories.WorkshopSessionRepository)
+	mockCourseRepo := new(mockRepositories.CourseRepository)
+
+	registration := &models.SessionRegistration{
+		Id:        utils.Ptr(uint64(9)),
+		SessionId: utils.Ptr(uint64(4)),
+		UserId:    utils.Ptr(uint64(1)),
+		Status:    utils.Ptr("waitlisted"),
+		QueuedAt:  utils.TimeNowPtr(),
+	}
+
+	mockWorkshopSessionRepo.EXPECT().FindSessionById(uint64(4)).Return(mockWorkshopSession(utils.TimeNow().Add(time.Hour)), nil)
+	mockWorkshopSessionRepo.EXPECT().Register(uint64(4), uint64(1)).Return(registration, nil)
+	mockWorkshopSessionRepo.EXPECT().CountWaitlistedBefore(registration).Return(int64(2), nil)
+
+	underTest := NewWorkshopSessionService(mockWorkshopSessionRepo, mockCourseRepo)
+
+	result, err := underTest.Register(4, 1)
+
+	is.Nil(err)
+	is.Equal("waitlisted", *result.Status)
+	is.Equal(int64(3), *result.WaitlistPosition)
+	is.Equal("Soldering lab", *result.Session.Title)
+}
+
+func (suite *WorkshopSessionServiceTestSuite) TestRegisterWhenSessionStarted() {
+	is := assert.New(suite.T())
+
+	mockWorkshopSessionRepo := new(mockRepositories.WorkshopSessionRepository)
+	mockCourseRepo := new(mockRepositories.CourseRepository)
+
+	mockWorkshopSessionRepo.EXPECT().FindSessionById(uint64(4)).Return(mockWorkshopSession(utils.TimeNow().Add(-time.Minute)), nil)
+
+	underTest := NewWorkshopSessionService(mockWorkshopSessionRepo, mockCourseRepo)
+
+	result, err := underTest.Register(4, 1)
+
+	is.Nil(result)
+	is.Equal("session 4 has already started", err.Error())
+	mockWorkshopSessionRepo.AssertNotCalled(suite.T(), "Register", mock.Anything, mock.Anything)
+}
+
+func (suite *WorkshopSessionServiceTestSuite) TestRegisterWhenAlreadyRegistered() {
+	is := assert.New(suite.T())
+
+	mockWorkshopSessionRepo := new(mockRepositories.WorkshopSessionRepository)
+	mockCourseRepo := new(mockRepositories.CourseRepository)
+
+	mockWorkshopSessionRepo.EXPECT().FindSessionById(uint64(4)).Return(mockWorkshopSession(utils.TimeNow().Add(time.Hour)), nil)
+	mockWorkshopSessionRepo.EXPECT().Register(uint64(4), uint64(1)).Return(nil, repositories.ErrAlreadyRegistered)
+
+	underTest := NewWorkshopSessionService(mockWorkshopSessionRepo, mockCourseRepo)
+
+	result, err := underTest.Register(4, 1)
+
+	is.Nil(result)
+	is.ErrorIs(err, repositories.ErrAlreadyRegistered)
+}
+
+func (suite *WorkshopSessionServiceTestSuite) TestCancelWhenSessionNotFound() {
+	is := assert.New(suite.T())
+
+	mockWorkshopSessionRepo := new(mockRepositories.WorkshopSessionRepository)
+	mockCourseRepo := new(mockRepositories.CourseRepository)
+
+	mockWorkshopSessionRepo.EXPECT().FindSessionById(uint64(4)).Return(nil, nil)
+
+	underTest := NewWorkshopSessionService(mockWorkshopSessionRepo, mockCourseRepo)
+
+	err := underTest.Cancel(4, 1)
+
+	is.Equal("session 4 not found", err.Error())
+}
+
+func (suite *WorkshopSessionServiceTestSuite) TestCancelWhenSuccess() {
+	is := assert.New(suite.T())
+
+	mockWorkshopSessionRepo := new(mockRepositories.WorkshopSessionRepository)
+	mockCourseRepo := new(mockRepositories.CourseRepository)
+
+	mockWorkshopSessionRepo.EXPECT().FindSessionById(uint64(4)).Return(mockWorkshopSession(utils.TimeNow().Add(time.Hour)), nil)
+	mockWorkshopSessionRepo.EXPECT().Cancel(uint64(4), uint64(1)).Return(&models.SessionRegistration{Id: utils.Ptr(uint64(10))}, nil)
+
+	underTest := NewWorkshopSessionService(mockWorkshopSessionRepo, mockCourseRepo)
+
+	err := underTest.Cancel(4, 1)
+
+	is.Nil(err)
+}
+
+func (suite *WorkshopSessionServiceTestSuite) TestCreateSessionWhenEndsBeforeStart() {
+	is := assert.New(suite.T())
+
+	mockWorkshopSessionRepo := new(mockRepositories.WorkshopSessionRepository)
+	mockCourseRepo := new(mockRepositories.CourseRepository)
+
+	startsAt := utils.TimeNow().Add(time.Hour)
+
+	underTest := NewWorkshopSessionService(mockWorkshopSessionRepo, mockCourseRepo)
+
+	result, err := underTest.CreateSession(&payload.CreateWorkshopSession{
+		CourseId: utils.Ptr(uint64(7)),
+		Title:    utils.Ptr("Soldering lab"),
+		StartsAt: &startsAt,
+		EndsAt:   &startsAt,
+		Capacity: utils.Ptr(10),
+	})
+
+	is.Nil(result)
+	is.Equal("session must end after it starts", err.Error())
+}
+
+func TestWorkshopSessionService(t *testing.T) {
+	suite.Run(t, new(WorkshopSessionServiceTestSuite))
+}
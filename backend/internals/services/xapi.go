@@ -0,0 +1,16 @@
+package services
+
+import (
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"context"
+)
+
+type XapiService interface {
+	RecordExperienced(userId uint64, stepId uint64, courseId *uint64) error
+	RecordAttempted(userEval *models.UserEvaluate) error
+	RecordGraded(userEval *models.UserEvaluate) error
+	RecordCommented(stepComment *models.StepComment) error
+	Replay() (*payload.XapiReplay, error)
+	Run(ctx context.Context)
+}
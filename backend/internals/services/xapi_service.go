@@ -0,0 +1,555 @@
+package services
+
+import (
+	"backend/internals/config"
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/repositories"
+	"backend/internals/utils"
+	utilServices "backend/internals/utils/services"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	xapiSweepInterval = 30 * time.Second
+	xapiBatchSize     = 50
+	xapiMaxAttempts   = 10          // about 8 hours of retries, so an LRS outage loses no statement
+	xapiRetryDelay    = time.Minute // doubled after every failed attempt
+	xapiReplayBatch   = 500
+	xapiLanguage      = "und" // titles are written in Thai or English, untagged
+)
+
+// xapiNamespace names the statement ids, derived from the event they describe so the same event
+// always gets the same id, live or replayed.
+var xapiNamespace = uuid.MustParse("4f8d3a52-6b1e-4c7a-9d2f-3e5b7c1a8f60")
+
+type xapiService struct {
+	xapiStatementRepo repositories.XapiStatementRepository
+	userRepo          repositories.UserRepository
+	stepRepo          repositories.StepRepository
+	stepEvalRepo      repositories.StepEvaluateRepository
+	courseRepo        repositories.CourseRepository
+	certificateRepo   repositories.CertificateRepository
+	lrsClient         utilServices.LrsClient
+	conf              *config.Config
+	wake              chan struct{}
+}
+
+func NewXapiService(
+	xapiStatementRepo repositories.XapiStatementRepository,
+	userRepo repositories.UserRepository,
+	stepRepo repositories.StepRepository,
+	stepEvalRepo repositories.StepEvaluateRepository,
+	courseRepo repositories.CourseRepository,
+	certificateRepo repositories.CertificateRepository,
+	lrsClient utilServices.LrsClient,
+	conf *config.Config) XapiService {
+	return &xapiService{
+		xapiStatementRepo: xapiStatementRepo,
+		userRepo:          userRepo,
+		stepRepo:          stepRepo,
+		stepEvalRepo:      stepEvalRepo,
+		courseRepo:        courseRepo,
+		certificateRepo:   certificateRepo,
+		lrsClient:         lrsClient,
+		conf:              conf,
+		wake:              make(chan struct{}, 1),
+	}
+}
+
+// RecordExperienced states the user viewed the step, in the course it was viewed in.
+func (r *xapiService) RecordExperienced(userId uint64, stepId uint64, courseId *uint64) error {
+	if !r.configured() {
+		return nil
+	}
+
+	activity := &models.UserActivity{UserId: &userId, StepId: &stepId, CourseId: courseId}
+	if err := r.resolveActivity(activity); err != nil {
+		return err
+	}
+
+	_, err := r.recordExperienced(activity, utils.TimeNow())
+	return err
+}
+
+// RecordAttempted states the user submitted the evaluation, once per attempt.
+func (r *xapiService) RecordAttempted(userEval *models.UserEvaluate) error {
+	if !r.configured() {
+		return nil
+	}
+
+	userEval, err := r.resolveUserEval(userEval)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.recordAttempted(userEval)
+	return err
+}
+
+// RecordGraded states the user passed or failed the evaluation, and completed the course when it
+// was the last evaluation of the course left to pass.
+func (r *xapiService) RecordGraded(userEval *models.UserEvaluate) error {
+	if !r.configured() || userEval.Pass == nil {
+		return nil
+	}
+
+	userEval, err := r.resolveUserEval(userEval)
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.recordGraded(userEval); err != nil {
+		return err
+	}
+	_, err = r.recordCompleted(userEval)
+	return err
+}
+
+// RecordCommented states the user commented on the step.
+func (r *xapiService) RecordCommented(stepComment *models.StepComment) error {
+	if !r.configured() {
+		return nil
+	}
+
+	user, err := r.userRepo.FindUserByID(utils.Ptr(strconv.FormatUint(*stepComment.UserId, 10)))
+	if err != nil {
+		return err
+	}
+	step, err := r.stepRepo.GetStepById(stepComment.StepId)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.enqueue(
+		fmt.Sprintf("step_comment:%d", *stepComment.Id),
+		payload.XapiVerbCommented,
+		user,
+		r.stepActivity(step),
+		&payload.XapiResult{Response: stepComment.Content},
+		nil,
+		utils.Val(stepComment.CreatedAt),
+	)
+	return err
+}
+
+// Replay adds the statements of the evaluations and step visits recorded before statements were
+// emitted to the outbox. Statements of events already in the outbox are skipped, so it is safe to
+// run again.
+func (r *xapiService) Replay() (*payload.XapiReplay, error) {
+	if !r.configured() {
+		return nil, utilServices.ErrLrsNotConfigured
+	}
+
+	replay := new(payload.XapiReplay)
+	completions := make(map[string]bool)
+	count := func(added bool) {
+		if added {
+			replay.Statements++
+		}
+	}
+
+	for afterId := uint64(0); ; {
+		userEvals, err := r.xapiStatementRepo.FindUserEvaluates(afterId, xapiReplayBatch)
+		if err != nil {
+			return nil, err
+		}
+		if len(userEvals) == 0 {
+			break
+		}
+
+		for _, userEval := range userEvals {
+			afterId = *userEval.Id
+			replay.UserEvaluates++
+
+			userEval, err := r.resolveUserEval(userEval)
+			if err != nil {
+				return nil, err
+			}
+			added, err := r.recordAttempted(userEval)
+			if err != nil {
+				return nil, err
+			}
+			count(added)
+
+			if userEval.Pass == nil {
+				continue
+			}
+			added, err = r.recordGraded(userEval)
+			if err != nil {
+				return nil, err
+			}
+			count(added)
+
+			// a course is completed once, the first passed evaluation of it tells
+			if userEval.CourseId == nil {
+				continue
+			}
+			completion := fmt.Sprintf("%d:%d", *userEval.UserId, *userEval.CourseId)
+			if completions[completion] {
+				continue
+			}
+			completions[completion] = true
+			added, err = r.recordCompleted(userEval)
+			if err != nil {
+				return nil, err
+			}
+			count(added)
+		}
+	}
+
+	for offset := 0; ; offset += xapiReplayBatch {
+		activities, err := r.xapiStatementRepo.FindUserActivities(offset, xapiReplayBatch)
+		if err != nil {
+			return nil, err
+		}
+		if len(activities) == 0 {
+			break
+		}
+
+		for _, activity := range activities {
+			replay.UserActivities++
+			if err := r.resolveActivity(activity); err != nil {
+				return nil, err
+			}
+
+			// a visit keeps its first and latest view
+			added, err := r.recordExperienced(activity, *activity.CreatedAt)
+			if err != nil {
+				return nil, err
+			}
+			count(added)
+			if activity.UpdatedAt.Equal(*activity.CreatedAt) {
+				continue
+			}
+			added, err = r.recordExperienced(activity, *activity.UpdatedAt)
+			if err != nil {
+				return nil, err
+			}
+			count(added)
+		}
+	}
+
+	r.wakeUp()
+
+	return replay, nil
+}
+
+// Run sends the due statements of the outbox until the context is cancelled. The outbox is swept
+// periodically for retries and for statements enqueued while the worker was down.
+func (r *xapiService) Run(ctx context.Context) {
+	if !r.configured() {
+		logrus.Warn("[XAPI] LRS is not configured, learning events are not recorded")
+		return
+	}
+
+	ticker := time.NewTicker(xapiSweepInterval)
+	defer ticker.Stop()
+
+	r.sweep()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.sweep()
+		case <-r.wake:
+			r.sweep()
+		}
+	}
+}
+
+func (r *xapiService) configured() bool {
+	return r.conf.LrsEndpoint != nil && *r.conf.LrsEndpoint != ""
+}
+
+func (r *xapiService) recordExperienced(activity *models.UserActivity, at time.Time) (bool, error) {
+	return r.enqueue(
+		fmt.Sprintf("user_activity:%d:%d:%d:%d", *activity.UserId, *activity.StepId, utils.Val(activity.CourseId), at.Unix()),
+		payload.XapiVerbExperienced,
+		activity.User,
+		r.stepActivity(activity.Step),
+		nil,
+		r.context(nil, activity.Course),
+		at,
+	)
+}
+
+func (r *xapiService) recordAttempted(userEval *models.UserEvaluate) (bool, error) {
+	// the first attempt created the evaluation, later ones updated it
+	at := utils.Val(userEval.UpdatedAt)
+	if utils.Val(userEval.Attempts) <= 1 {
+		at = utils.Val(userEval.CreatedAt)
+	}
+
+	return r.enqueue(
+		fmt.Sprintf("user_evaluate:%d:%d:%s", *userEval.Id, utils.Val(userEval.Attempts), payload.XapiVerbAttempted),
+		payload.XapiVerbAttempted,
+		userEval.User,
+		r.evaluationActivity(userEval.StepEvaluate),
+		&payload.XapiResult{Response: userEval.Content},
+		r.context(userEval.StepEvaluate.Step, userEval.Course),
+		at,
+	)
+}
+
+func (r *xapiService) recordGraded(userEval *models.UserEvaluate) (bool, error) {
+	verb := payload.XapiVerbFailed
+	if *userEval.Pass {
+		verb = payload.XapiVerbPassed
+	}
+
+	return r.enqueue(
+		fmt.Sprintf("user_evaluate:%d:%d:%s", *userEval.Id, utils.Val(userEval.Attempts), verb),
+		verb,
+		userEval.User,
+		r.evaluationActivity(userEval.StepEvaluate),
+		&payload.XapiResult{Success: userEval.Pass, Completion: utils.Ptr(true)},
+		r.context(userEval.StepEvaluate.Step, userEval.Course),
+		utils.Val(userEval.UpdatedAt),
+	)
+}
+
+// recordCompleted states the user completed the course of the passed evaluation, when every
+// evaluation of the course is passed.
+func (r *xapiService) recordCompleted(userEval *models.UserEvaluate) (bool, error) {
+	if !utils.Val(userEval.Pass) || userEval.Course == nil {
+		return false, nil
+	}
+
+	completion, err := r.certificateRepo.FindCourseCompletion(*userEval.UserId, *userEval.CourseId)
+	if err != nil {
+		return false, err
+	}
+	if completion.Required == 0 || completion.Passed < completion.Required {
+		return false, nil
+	}
+
+	return r.enqueue(
+		fmt.Sprintf("course:%d:user:%d:%s", *userEval.CourseId, *userEval.UserId, payload.XapiVerbCompleted),
+		payload.XapiVerbCompleted,
+		userEval.User,
+		r.courseActivity(userEval.Course),
+		&payload.XapiResult{Success: utils.Ptr(true), Completion: utils.Ptr(true)},
+		nil,
+		utils.Val(completion.CompletedAt),
+	)
+}
+
+// resolveUserEval returns a copy of the evaluation with the user, evaluation, step and course its
+// statements describe, loading those not preloaded.
+func (r *xapiService) resolveUserEval(userEval *models.UserEvaluate) (*models.UserEvaluate, error) {
+	resolved := *userEval
+
+	if resolved.User == nil {
+		user, err := r.userRepo.FindUserByID(utils.Ptr(strconv.FormatUint(*resolved.UserId, 10)))
+		if err != nil {
+			return nil, err
+		}
+		resolved.User = user
+	}
+	if resolved.StepEvaluate == nil {
+		stepEval, err := r.stepEvalRepo.GetStepEvalById(resolved.StepEvaluateId)
+		if err != nil {
+			return nil, err
+		}
+		resolved.StepEvaluate = stepEval
+	}
+	if resolved.StepEvaluate.Step == nil {
+		stepEval := *resolved.StepEvaluate
+		step, err := r.stepRepo.GetStepById(stepEval.StepId)
+		if err != nil {
+			return nil, err
+		}
+		stepEval.Step = step
+		resolved.StepEvaluate = &stepEval
+	}
+	if resolved.CourseId != nil && resolved.Course == nil {
+		course, err := r.courseRepo.FindCourseByCourseId(resolved.CourseId)
+		if err != nil {
+			return nil, err
+		}
+		resolved.Course = course
+	}
+
+	return &resolved, nil
+}
+
+// resolveActivity loads the user, step and course of the visit not preloaded.
+func (r *xapiService) resolveActivity(activity *models.UserActivity) error {
+	if activity.User == nil {
+		user, err := r.userRepo.FindUserByID(utils.Ptr(strconv.FormatUint(*activity.UserId, 10)))
+		if err != nil {
+			return err
+		}
+		activity.User = user
+	}
+	if activity.Step == nil {
+		step, err := r.stepRepo.GetStepById(activity.StepId)
+		if err != nil {
+			return err
+		}
+		activity.Step = step
+	}
+	if activity.CourseId != nil && activity.Course == nil {
+		course, err := r.courseRepo.FindCourseByCourseId(activity.CourseId)
+		if err != nil {
+			return err
+		}
+		activity.Course = course
+	}
+
+	return nil
+}
+
+// enqueue builds the statement of the event and stores it in the outbox, unless the event is
+// already there. It tells whether the statement was added.
+func (r *xapiService) enqueue(key string, verb string, user *models.User, object *payload.XapiObject, result *payload.XapiResult, context *payload.XapiContext, at time.Time) (bool, error) {
+	statementId := uuid.NewSHA1(xapiNamespace, []byte(key)).String()
+
+	body, err := json.Marshal(&payload.XapiStatement{
+		Id:    statementId,
+		Actor: r.actor(user),
+		Verb: &payload.XapiVerb{
+			Id:      "http://adlnet.gov/expapi/verbs/" + verb,
+			Display: map[string]string{"en-US": verb},
+		},
+		Object:    object,
+		Result:    result,
+		Context:   context,
+		Timestamp: at.UTC(),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	added, err := r.xapiStatementRepo.CreateStatement(&models.XapiStatement{
+		StatementId:   &statementId,
+		UserId:        user.Id,
+		Verb:          &verb,
+		Statement:     utils.Ptr(string(body)),
+		Status:        utils.Ptr("pending"),
+		Attempts:      utils.Ptr(0),
+		NextAttemptAt: utils.TimeNowPtr(),
+	})
+	if err != nil {
+		return false, err
+	}
+	if added {
+		r.wakeUp()
+	}
+
+	return added, nil
+}
+
+func (r *xapiService) wakeUp() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+		// a sweep is already coming
+	}
+}
+
+func (r *xapiService) actor(user *models.User) *payload.XapiActor {
+	actor := &payload.XapiActor{
+		ObjectType: "Agent",
+		Name:       strings.TrimSpace(utils.Val(user.Firstname) + " " + utils.Val(user.Lastname)),
+	}
+	if utils.Val(user.Email) != "" {
+		actor.Mbox = "mailto:" + *user.Email
+	} else {
+		actor.Account = &payload.XapiAccount{
+			HomePage: r.activityBase(),
+			Name:     strconv.FormatUint(*user.Id, 10),
+		}
+	}
+
+	return actor
+}
+
+func (r *xapiService) context(step *models.Step, course *models.Course) *payload.XapiContext {
+	activities := new(payload.XapiContextActivities)
+	if step != nil {
+		activities.Parent = append(activities.Parent, r.stepActivity(step))
+	}
+	if course != nil {
+		activities.Grouping = append(activities.Grouping, r.courseActivity(course))
+	}
+	if activities.Parent == nil && activities.Grouping == nil {
+		return nil
+	}
+
+	return &payload.XapiContext{ContextActivities: activities}
+}
+
+func (r *xapiService) stepActivity(step *models.Step) *payload.XapiObject {
+	return r.activity("steps", *step.Id, utils.Val(step.Title), payload.XapiActivityLesson)
+}
+
+func (r *xapiService) evaluationActivity(stepEval *models.StepEvaluate) *payload.XapiObject {
+	return r.activity("step-evaluations", *stepEval.Id, utils.Val(stepEval.Question), payload.XapiActivityInteraction)
+}
+
+func (r *xapiService) courseActivity(course *models.Course) *payload.XapiObject {
+	return r.activity("courses", *course.Id, utils.Val(course.Name), payload.XapiActivityCourse)
+}
+
+func (r *xapiService) activity(kind string, id uint64, name string, activityType string) *payload.XapiObject {
+	return &payload.XapiObject{
+		ObjectType: "Activity",
+		Id:         fmt.Sprintf("%s/%s/%d", r.activityBase(), kind, id),
+		Definition: &payload.XapiActivityDefinition{
+			Name: map[string]string{xapiLanguage: name},
+			Type: activityType,
+		},
+	}
+}
+
+// activityBase is the IRI the activities of the platform are named under, it must not change once
+// statements are sent.
+func (r *xapiService) activityBase() string {
+	return strings.TrimSuffix(utils.Val(r.conf.ApiUrl), "/") + "/xapi/activities"
+}
+
+func (r *xapiService) sweep() {
+	statements, err := r.xapiStatementRepo.FindDueStatements(utils.TimeNow(), xapiBatchSize)
+	if err != nil {
+		logrus.Errorf("[XAPI] Unable to fetch due statements: %v", err)
+		return
+	}
+
+	for _, statement := range statements {
+		r.deliver(statement)
+	}
+}
+
+func (r *xapiService) deliver(statement *models.XapiStatement) {
+	err := r.lrsClient.PutStatement(*statement.StatementId, []byte(*statement.Statement))
+
+	statement.Attempts = utils.Ptr(*statement.Attempts + 1)
+	if err == nil {
+		statement.Status = utils.Ptr("sent")
+		statement.SentAt = utils.TimeNowPtr()
+		statement.LastError = nil
+	} else if *statement.Attempts >= xapiMaxAttempts {
+		logrus.Errorf("[XAPI] Giving up on statement %s after %d attempts: %v", *statement.StatementId, *statement.Attempts, err)
+		statement.Status = utils.Ptr("failed")
+		statement.LastError = utils.Ptr(err.Error())
+	} else {
+		delay := xapiRetryDelay << (*statement.Attempts - 1)
+		statement.NextAttemptAt = utils.Ptr(utils.TimeNow().Add(delay))
+		statement.LastError = utils.Ptr(err.Error())
+	}
+
+	if err := r.xapiStatementRepo.UpdateStatement(statement); err != nil {
+		logrus.Errorf("[XAPI] Unable to update statement %s: %v", *statement.StatementId, err)
+	}
+}
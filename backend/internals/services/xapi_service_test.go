@@ -0,0 +1,339 @@
+package services
+
+import (
+	"backend/internals/config"
+	"backend/internals/db/models"
+	"backend/internals/entities/payload"
+	"backend/internals/utils"
+	utilServices "backend/internals/utils/services"
+	mockRepositories "backend/mocks/repositories"
+	mockUtilServices "backend/mocks/utils"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type XapiServiceTestSuite struct {
+	suite.Suite
+}
+
+func mockXapiConfig() *config.Config {
+	return &config.Config{
+		ApiUrl:      utils.Ptr("https://learn.example.com/api"),
+		LrsEndpoint: utils.Ptr("https://lrs.example.com/xapi"),
+	}
+}
+
+func mockXapiUserEval() *models.UserEvaluate {
+	return &models.UserEvaluate{
+		Id:             utils.Ptr(uint64(30)),
+		UserId:         utils.Ptr(uint64(9)),
+		User:           &models.User{Id: utils.Ptr(uint64(9)), Firstname: utils.Ptr("Somchai"), Lastname: utils.Ptr("Jaidee"), Email: utils.Ptr("somchai@example.com")},
+		StepEvaluateId: utils.Ptr(uint64(12)),
+		StepEvaluate: &models.StepEvaluate{
+			Id:       utils.Ptr(uint64(12)),
+			StepId:   utils.Ptr(uint64(3)),
+			Step:     &models.Step{Id: utils.Ptr(uint64(3)), Title: utils.Ptr("Wire the LED")},
+			Question: utils.Ptr("Upload a photo of the circuit"),
+		},
+		CourseId:  utils.Ptr(uint64(7)),
+		Course:    &models.Course{Id: utils.Ptr(uint64(7)), Name: utils.Ptr("IoT Basics")},
+		Content:   utils.Ptr("photo.png"),
+		Pass:      utils.Ptr(true),
+		Attempts:  utils.Ptr(2),
+		CreatedAt: utils.Ptr(time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)),
+		UpdatedAt: utils.Ptr(time.Date(2024, 3, 2, 9, 0, 0, 0, time.UTC)),
+	}
+}
+
+// decodeStatement reads the xAPI statement stored in the outbox row.
+func decodeStatement(statement *models.XapiStatement) *payload.XapiStatement {
+	decoded := new(payload.XapiStatement)
+	if err := json.Unmarshal([]byte(*statement.Statement), decoded); err != nil {
+		panic(err)
+	}
+	return decoded
+}
+
+func (suite *XapiServiceTestSuite) TestRecordGradedWhenCourseCompleted() {
+	is := assert.New(suite.T())
+
+	mockXapiStatementRepo := new(mockRepositories.XapiStatementRepository)
+	mockCertificateRepo := new(mockRepositories.CertificateRepository)
+
+	var statements []*models.XapiStatement
+	mockXapiStatementRepo.EXPECT().CreateStatement(mock.Anything).RunAndReturn(func(statement *models.XapiStatement) (bool, error) {
+		statements = append(statements, statement)
+		return true, nil
+	})
+	mockCertificateRepo.EXPECT().FindCourseCompletion(uint64(9), uint64(7)).Return(&payload.CourseCompletion{
+		Required:    4,
+		Passed:      4,
+		CompletedAt: utils.Ptr(time.Date(2024, 3, 2, 9, 0, 0, 0, time.UTC)),
+	}, nil)
+
+	underTest := NewXapiService(mockXapiStatementRepo, nil, nil, nil, nil, mockCertificateRepo, nil, mockXapiConfig())
+
+	err := underTest.RecordGraded(mockXapiUserEval())
+
+	is.Nil(err)
+	is.Len(statements, 2)
+
+	passed := decodeStatement(statements[0])
+	is.Equal(payload.XapiVerbPassed, *statements[0].Verb)
+	is.Equal(*statements[0].StatementId, passed.Id)
+	is.Equal("http://adlnet.gov/expapi/verbs/passed", passed.Verb.Id)
+	is.Equal("mailto:somchai@example.com", passed.Actor.Mbox)
+	is.Equal("https://learn.example.com/api/xapi/activities/step-evaluations/12", passed.Object.Id)
+	is.Equal("https://learn.example.com/api/xapi/activities/steps/3", passed.Context.ContextActivities.Parent[0].Id)
+	is.Equal("https://learn.example.com/api/xapi/activities/courses/7", passed.Context.ContextActivities.Grouping[0].Id)
+	is.True(*passed.Result.Success)
+
+	completed := decodeStatement(statements[1])
+	is.Equal(payload.XapiVerbCompleted, *statements[1].Verb)
+	is.Equal("https://learn.example.com/api/xapi/activities/courses/7", completed.Object.Id)
+	is.Equal(payload.XapiActivityCourse, completed.Object.Definition.Type)
+}
+
+func (suite *XapiServiceTestSuite) TestRecordGradedWhenGradedAgain() {
+	is := assert.New(suite.T())
+
+	mockXapiStatementRepo := new(mockRepositories.XapiStatementRepository)
+	mockCertificateRepo := new(mockRepositories.CertificateRepository)
+
+	var statementIds []string
+	mockXapiStatementRepo.EXPECT().CreateStatement(mock.Anything).RunAndReturn(func(statement *models.XapiStatement) (bool, error) {
+		statementIds = append(statementIds, *statement.StatementId)
+		return true, nil
+	})
+	mockCertificateRepo.EXPECT().FindCourseCompletion(uint64(9), uint64(7)).Return(&payload.CourseCompletion{Required: 4, Passed: 3}, nil)
+
+	underTest := NewXapiService(mockXapiStatementRepo, nil, nil, nil, nil, mockCertificateRepo, nil, mockXapiConfig())
+
+	// the same grade of the same attempt is the same event, a new attempt is another one
+	is.Nil(underTest.RecordGraded(mockXapiUserEval()))
+	is.Nil(underTest.RecordGraded(mockXapiUserEval()))
+	resubmitted := mockXapiUserEval()
+	resubmitted.Attempts = utils.Ptr(3)
+	is.Nil(underTest.RecordGraded(resubmitted))
+
+	is.Len(statementIds, 3)
+	is.Equal(statementIds[0], statementIds[1])
+	is.NotEqual(statementIds[0], statementIds[2])
+}
+
+func (suite *XapiServiceTestSuite) TestRecordAttemptedWhenNotConfigured() {
+	is := assert.New(suite.T())
+
+	mockXapiStatementRepo := new(mockRepositories.XapiStatementRepository)
+
+	underTest := NewXapiService(mockXapiStatementRepo, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+	err := underTest.RecordAttempted(mockXapiUserEval())
+
+	is.Nil(err)
+	mockXapiStatementRepo.AssertNotCalled(suite.T(), "CreateStatement", mock.Anything)
+}
+
+func (suite *XapiServiceTestSuite) TestRecordAttemptedWhenLoadingDetails() {
+	is := assert.New(suite.T())
+
+	mockXapiStatementRepo := new(mockRepositories.XapiStatementRepository)
+	mockUserRepo := new(mockRepositories.UserRepository)
+	mockStepRepo := new(mockRepositories.StepRepository)
+	mockStepEvalRepo := new(mockRepositories.StepEvaluateRepository)
+
+	mockUserRepo.EXPECT().FindUserByID(utils.Ptr("9")).Return(&models.User{Id: utils.Ptr(uint64(9)), Firstname: utils.Ptr("Somchai"), Lastname: utils.Ptr("Jaidee")}, nil)
+	mockStepEvalRepo.EXPECT().GetStepEvalById(utils.Ptr(uint64(12))).Return(&models.StepEvaluate{Id: utils.Ptr(uint64(12)), StepId: utils.Ptr(uint64(3)), Question: utils.Ptr("What does GPIO stand for?")}, nil)
+	mockStepRepo.EXPECT().GetStepById(utils.Ptr(uint64(3))).Return(&models.Step{Id: utils.Ptr(uint64(3)), Title: utils.Ptr("GPIO")}, nil)
+
+	var statement *models.XapiStatement
+	mockXapiStatementRepo.EXPECT().CreateStatement(mock.Anything).RunAndReturn(func(created *models.XapiStatement) (bool, error) {
+		statement = created
+		return true, nil
+	})
+
+	underTest := NewXapiService(mockXapiStatementRepo, mockUserRepo, mockStepRepo, mockStepEvalRepo, nil, nil, nil, mockXapiConfig())
+
+	userEval := &models.UserEvaluate{
+		Id:             utils.Ptr(uint64(31)),
+		UserId:         utils.Ptr(uint64(9)),
+		StepEvaluateId: utils.Ptr(uint64(12)),
+		Content:        utils.Ptr("General purpose input output"),
+		Attempts:       utils.Ptr(1),
+		CreatedAt:      utils.Ptr(time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)),
+		UpdatedAt:      utils.Ptr(time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)),
+	}
+	err := underTest.RecordAttempted(userEval)
+
+	is.Nil(err)
+	attempted := decodeStatement(statement)
+	is.Equal(payload.XapiVerbAttempted, *statement.Verb)
+	is.Equal("pending", *statement.Status)
+	is.Equal("General purpose input output", *attempted.Result.Response)
+	is.Equal("What does GPIO stand for?", attempted.Object.Definition.Name[xapiLanguage])
+	// users without an email are named by their account on the platform
+	is.Equal("9", attempted.Actor.Account.Name)
+	is.Empty(attempted.Actor.Mbox)
+	// only the course is unknown, it is not in the context
+	is.Nil(attempted.Context.ContextActivities.Grouping)
+	// the evaluation of the caller is left untouched
+	is.Nil(userEval.User)
+}
+
+func (suite *XapiServiceTestSuite) TestReplay() {
+	is := assert.New(suite.T())
+
+	mockXapiStatementRepo := new(mockRepositories.XapiStatementRepository)
+	mockCertificateRepo := new(mockRepositories.CertificateRepository)
+
+	pending := mockXapiUserEval()
+	pending.Id = utils.Ptr(uint64(31))
+	pending.Pass = nil
+	mockXapiStatementRepo.EXPECT().FindUserEvaluates(uint64(0), xapiReplayBatch).Return([]*models.UserEvaluate{mockXapiUserEval(), pending}, nil)
+	mockXapiStatementRepo.EXPECT().FindUserEvaluates(uint64(31), xapiReplayBatch).Return(nil, nil)
+
+	visited := time.Date(2024, 2, 28, 9, 0, 0, 0, time.UTC)
+	activity := &models.UserActivity{
+		UserId:    utils.Ptr(uint64(9)),
+		User:      mockXapiUserEval().User,
+		StepId:    utils.Ptr(uint64(3)),
+		Step:      &models.Step{Id: utils.Ptr(uint64(3)), Title: utils.Ptr("Wire the LED")},
+		CreatedAt: &visited,
+		UpdatedAt: utils.Ptr(visited.Add(time.Hour)),
+	}
+	mockXapiStatementRepo.EXPECT().FindUserActivities(0, xapiReplayBatch).Return([]*models.UserActivity{activity}, nil)
+	mockXapiStatementRepo.EXPECT().FindUserActivities(xapiReplayBatch, xapiReplayBatch).Return(nil, nil)
+
+	mockCertificateRepo.EXPECT().FindCourseCompletion(uint64(9), uint64(7)).Return(&payload.CourseCompletion{Required: 4, Passed: 3}, nil)
+
+	// the passed statement was emitted live already
+	var verbs []string
+	mockXapiStatementRepo.EXPECT().CreateStatement(mock.Anything).RunAndReturn(func(statement *models.XapiStatement) (bool, error) {
+		verbs = append(verbs, *statement.Verb)
+		return *statement.Verb != payload.XapiVerbPassed, nil
+	})
+
+	underTest := NewXapiService(mockXapiStatementRepo, nil, nil, nil, nil, mockCertificateRepo, nil, mockXapiConfig())
+
+	replay, err := underTest.Replay()
+
+	is.Nil(err)
+	is.Equal(int64(2), replay.UserEvaluates)
+	is.Equal(int64(1), replay.UserActivities)
+	is.Equal(int64(4), replay.Statements)
+	is.Equal([]string{
+		payload.XapiVerbAttempted,
+		payload.XapiVerbPassed,
+		payload.XapiVerbAttempted,
+		payload.XapiVerbExperienced,
+		payload.XapiVerbExperienced,
+	}, verbs)
+}
+
+func (suite *XapiServiceTestSuite) TestReplayWhenNotConfigured() {
+	is := assert.New(suite.T())
+
+	underTest := NewXapiService(nil, nil, nil, nil, nil, nil, nil, &config.Config{})
+
+	replay, err := underTest.Replay()
+
+	is.Nil(replay)
+	is.ErrorIs(err, utilServices.ErrLrsNotConfigured)
+}
+
+func (suite *XapiServiceTestSuite) TestDeliverWhenPutFailed() {
+	is := assert.New(suite.T())
+
+	mockXapiStatementRepo := new(mockRepositories.XapiStatementRepository)
+	mockLrsClient := new(mockUtilServices.LrsClient)
+
+	statement := &models.XapiStatement{
+		Id:            utils.Ptr(uint64(1)),
+		StatementId:   utils.Ptr("2f1c9a8e-7d3b-5e4f-a6c2-1b0d9e8f7a6c"),
+		Statement:     utils.Ptr(`{"id":"2f1c9a8e-7d3b-5e4f-a6c2-1b0d9e8f7a6c"}`),
+		Status:        utils.Ptr("pending"),
+		Attempts:      utils.Ptr(2),
+		NextAttemptAt: utils.TimeNowPtr(),
+	}
+	mockLrsClient.EXPECT().PutStatement(*statement.StatementId, mock.Anything).Return(fmt.Errorf("lrs is unreachable"))
+	mockXapiStatementRepo.EXPECT().UpdateStatement(mock.Anything).Return(nil)
+
+	underTest := NewXapiService(mockXapiStatementRepo, nil, nil, nil, nil, nil, mockLrsClient, mockXapiConfig()).(*xapiService)
+
+	before := utils.TimeNow()
+	underTest.deliver(statement)
+
+	// the third failure waits four times the first delay
+	is.Equal("pending", *statement.Status)
+	is.Equal(3, *statement.Attempts)
+	is.Equal("lrs is unreachable", *statement.LastError)
+	is.True(statement.NextAttemptAt.After(before.Add(4*xapiRetryDelay - time.Second)))
+	mockXapiStatementRepo.AssertExpectations(suite.T())
+}
+
+func (suite *XapiServiceTestSuite) TestDeliverWhenSentToLrs() {
+	is := assert.New(suite.T())
+
+	type request struct {
+		method      string
+		path        string
+		statementId string
+		version     string
+		username    string
+		password    string
+		body        string
+	}
+	requests := make(chan request, 1)
+	lrs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, _ := r.BasicAuth()
+		body, _ := io.ReadAll(r.Body)
+		requests <- request{r.Method, r.URL.Path, r.URL.Query().Get("statementId"), r.Header.Get("X-Experience-API-Version"), username, password, string(body)}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer lrs.Close()
+
+	conf := mockXapiConfig()
+	conf.LrsEndpoint = utils.Ptr(lrs.URL + "/xapi/")
+	conf.LrsUsername = utils.Ptr("key")
+	conf.LrsPassword = utils.Ptr("secret")
+
+	mockXapiStatementRepo := new(mockRepositories.XapiStatementRepository)
+	mockXapiStatementRepo.EXPECT().UpdateStatement(mock.Anything).Return(nil)
+
+	underTest := NewXapiService(mockXapiStatementRepo, nil, nil, nil, nil, nil, utilServices.NewLrsClient(conf), conf).(*xapiService)
+
+	statement := &models.XapiStatement{
+		Id:            utils.Ptr(uint64(1)),
+		StatementId:   utils.Ptr("2f1c9a8e-7d3b-5e4f-a6c2-1b0d9e8f7a6c"),
+		Statement:     utils.Ptr(`{"id":"2f1c9a8e-7d3b-5e4f-a6c2-1b0d9e8f7a6c"}`),
+		Status:        utils.Ptr("pending"),
+		Attempts:      utils.Ptr(0),
+		NextAttemptAt: utils.TimeNowPtr(),
+	}
+	underTest.deliver(statement)
+
+	sent := <-requests
+	is.Equal(http.MethodPut, sent.method)
+	is.Equal("/xapi/statements", sent.path)
+	is.Equal("2f1c9a8e-7d3b-5e4f-a6c2-1b0d9e8f7a6c", sent.statementId)
+	is.Equal("1.0.3", sent.version)
+	is.Equal("key", sent.username)
+	is.Equal("secret", sent.password)
+	is.Equal(*statement.Statement, sent.body)
+	is.Equal("sent", *statement.Status)
+	is.Equal(1, *statement.Attempts)
+	is.NotNil(statement.SentAt)
+}
+
+func TestXapiService(t *testing.T) {
+	suite.Run(t, new(XapiServiceTestSuite))
+}
@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"bytes"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ICalEvent is a VEVENT of an iCalendar (RFC 5545). An event without End is a point in time,
+// like a deadline. Status is one of CONFIRMED, TENTATIVE and CANCELLED, or empty.
+type ICalEvent struct {
+	Uid         string
+	Summary     string
+	Description string
+	Location    string
+	Url         string
+	Status      string
+	Start       time.Time
+	End         *time.Time
+	Updated     time.Time
+}
+
+const iCalTimeFormat = "20060102T150405Z"
+
+var iCalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// ICalendar writes the events as an iCalendar named name, with times in UTC.
+func ICalendar(name string, events []*ICalEvent, now time.Time) []byte {
+	var buffer bytes.Buffer
+
+	line := func(property string, value string) {
+		writeICalLine(&buffer, property+":"+value)
+	}
+	text := func(property string, value string) {
+		if value != "" {
+			line(property, iCalEscaper.Replace(value))
+		}
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//IoT Learning Platform//Calendar//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	text("X-WR-CALNAME", name)
+	line("X-WR-TIMEZONE", BangkokTime.String())
+	// calendar apps poll subscribed feeds, ask them to do it hourly
+	line("REFRESH-INTERVAL;VALUE=DURATION", "PT1H")
+	line("X-PUBLISHED-TTL", "PT1H")
+
+	for _, event := range events {
+		line("BEGIN", "VEVENT")
+		line("UID", event.Uid)
+		line("DTSTAMP", now.UTC().Format(iCalTimeFormat))
+		line("DTSTART", event.Start.UTC().Format(iCalTimeFormat))
+		if event.End != nil {
+			line("DTEND", event.End.UTC().Format(iCalTimeFormat))
+		}
+		if !event.Updated.IsZero() {
+			line("LAST-MODIFIED", event.Updated.UTC().Format(iCalTimeFormat))
+		}
+		text("SUMMARY", event.Summary)
+		text("DESCRIPTION", event.Description)
+		text("LOCATION", event.Location)
+		if event.Url != "" {
+			line("URL", event.Url)
+		}
+		if event.Status != "" {
+			line("STATUS", event.Status)
+		}
+		line("END", "VEVENT")
+	}
+
+	line("END", "VCALENDAR")
+	return buffer.Bytes()
+}
+
+// writeICalLine folds the content line at 75 octets, continuation lines start with a space.
+func writeICalLine(buffer *bytes.Buffer, content string) {
+	limit := 75
+	for len(content) > limit {
+		cut := limit
+		// never split a multibyte character
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		buffer.WriteString(content[:cut])
+		buffer.WriteString("\r\n ")
+		content = content[cut:]
+		limit = 74
+	}
+	buffer.WriteString(content)
+	buffer.WriteString("\r\n")
+}
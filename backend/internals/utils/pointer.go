@@ -3,3 +3,12 @@ package utils
 func Ptr[T any](v T) *T {
 	return &v
 }
+
+// Val dereferences v, returning the zero value for nil.
+func Val[T any](v *T) T {
+	if v == nil {
+		var zero T
+		return zero
+	}
+	return *v
+}
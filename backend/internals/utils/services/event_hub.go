@@ -0,0 +1,9 @@
+package utilServices
+
+import "backend/internals/entities/payload"
+
+type EventHub interface {
+	Publish(topic string, event *payload.Event)
+	Subscribe(topics ...string) (<-chan *payload.Event, func())
+	Close()
+}
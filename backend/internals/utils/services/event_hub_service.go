@@ -0,0 +1,123 @@
+package utilServices
+
+import (
+	"backend/internals/entities/payload"
+	"fmt"
+	"sync"
+)
+
+// eventBufferSize is how many events a subscriber may fall behind. Past it the oldest unread
+// event is dropped, so a slow subscriber never blocks the publisher.
+const eventBufferSize = 16
+
+type eventHub struct {
+	mutex  sync.Mutex
+	closed bool
+	topics map[string]map[*eventSubscriber]bool
+}
+
+type eventSubscriber struct {
+	events chan *payload.Event
+	topics []string
+	closed bool
+}
+
+// NewEventHub creates an in-process pub/sub hub, every subscriber of a topic gets each event
+// published to it.
+func NewEventHub() EventHub {
+	return &eventHub{
+		topics: make(map[string]map[*eventSubscriber]bool),
+	}
+}
+
+func UserTopic(userId uint64) string {
+	return fmt.Sprintf("user:%d", userId)
+}
+
+func StepTopic(stepId uint64) string {
+	return fmt.Sprintf("step:%d", stepId)
+}
+
+func CohortTopic(cohortId uint64) string {
+	return fmt.Sprintf("cohort:%d", cohortId)
+}
+
+func (h *eventHub) Publish(topic string, event *payload.Event) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for subscriber := range h.topics[topic] {
+		select {
+		case subscriber.events <- event:
+			continue
+		default:
+		}
+
+		// only publishers send, holding the lock, so after dropping one the send cannot block
+		select {
+		case <-subscriber.events:
+		default:
+		}
+		subscriber.events <- event
+	}
+}
+
+// Subscribe returns the events of the topics and a function to stop receiving them. The channel
+// is closed once unsubscribed or when the hub is closed.
+func (h *eventHub) Subscribe(topics ...string) (<-chan *payload.Event, func()) {
+	subscriber := &eventSubscriber{
+		events: make(chan *payload.Event, eventBufferSize),
+		topics: topics,
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.closed {
+		close(subscriber.events)
+		return subscriber.events, func() {}
+	}
+
+	for _, topic := range topics {
+		if h.topics[topic] == nil {
+			h.topics[topic] = make(map[*eventSubscriber]bool)
+		}
+		h.topics[topic][subscriber] = true
+	}
+
+	unsubscribe := func() {
+		h.mutex.Lock()
+		defer h.mutex.Unlock()
+		h.remove(subscriber)
+	}
+
+	return subscriber.events, unsubscribe
+}
+
+// Close ends every subscription, letting open streams finish before the server shuts down.
+func (h *eventHub) Close() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.closed = true
+	for _, subscribers := range h.topics {
+		for subscriber := range subscribers {
+			h.remove(subscriber)
+		}
+	}
+}
+
+func (h *eventHub) remove(subscriber *eventSubscriber) {
+	if subscriber.closed {
+		return
+	}
+	subscriber.closed = true
+	close(subscriber.events)
+
+	for _, topic := range subscriber.topics {
+		delete(h.topics[topic], subscriber)
+		if len(h.topics[topic]) == 0 {
+			delete(h.topics, topic)
+		}
+	}
+}
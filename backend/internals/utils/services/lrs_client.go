@@ -0,0 +1,5 @@
+package utilServices
+
+type LrsClient interface {
+	PutStatement(statementId string, statement []byte) error
+}
@@ -0,0 +1,61 @@
+package utilServices
+
+import (
+	"backend/internals/config"
+	"backend/internals/utils"
+	"errors"
+	"fmt"
+	"github.com/go-resty/resty/v2"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var ErrLrsNotConfigured = errors.New("lrs is not configured")
+
+const (
+	lrsTimeout    = 30 * time.Second
+	xapiVersion   = "1.0.3"
+	lrsErrorQuote = 200 // bytes of an error response kept in the error
+)
+
+type lrsClient struct {
+	conf   *config.Config
+	client *resty.Client
+}
+
+func NewLrsClient(conf *config.Config) LrsClient {
+	return &lrsClient{
+		conf:   conf,
+		client: resty.New().SetTimeout(lrsTimeout),
+	}
+}
+
+// PutStatement stores the statement under its id. The LRS keeps the first statement stored
+// under an id, so a statement sent again, by a retry or a replay, is accepted without a duplicate.
+func (r *lrsClient) PutStatement(statementId string, statement []byte) error {
+	if r.conf.LrsEndpoint == nil || *r.conf.LrsEndpoint == "" {
+		return ErrLrsNotConfigured
+	}
+
+	resp, err := r.client.R().
+		SetBasicAuth(utils.Val(r.conf.LrsUsername), utils.Val(r.conf.LrsPassword)).
+		SetHeader("X-Experience-API-Version", xapiVersion).
+		SetHeader("Content-Type", "application/json").
+		SetQueryParam("statementId", statementId).
+		SetBody(statement).
+		Put(strings.TrimSuffix(*r.conf.LrsEndpoint, "/") + "/statements")
+	if err != nil {
+		return err
+	}
+	// a conflict is a statement already stored under the id, sent before with other details
+	if resp.IsSuccess() || resp.StatusCode() == http.StatusConflict {
+		return nil
+	}
+
+	body := resp.Body()
+	if len(body) > lrsErrorQuote {
+		body = body[:lrsErrorQuote]
+	}
+	return fmt.Errorf("lrs rejected statement %s: %s %s", statementId, resp.Status(), body)
+}
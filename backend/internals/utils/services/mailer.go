@@ -0,0 +1,7 @@
+package utilServices
+
+import "backend/internals/entities/payload"
+
+type Mailer interface {
+	Send(email *payload.Email) error
+}
@@ -0,0 +1,156 @@
+package utilServices
+
+import (
+	"backend/internals/config"
+	"backend/internals/entities/payload"
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrMailerNotConfigured = errors.New("smtp is not configured")
+
+const smtpTimeout = 30 * time.Second
+
+type smtpMailer struct {
+	conf *config.Config
+}
+
+func NewSmtpMailer(conf *config.Config) Mailer {
+	return &smtpMailer{
+		conf: conf,
+	}
+}
+
+// Send delivers the email as a multipart/alternative message with its text and HTML bodies,
+// upgrading to TLS when the server offers STARTTLS.
+func (r *smtpMailer) Send(email *payload.Email) error {
+	if r.conf.SmtpHost == nil || *r.conf.SmtpHost == "" {
+		return ErrMailerNotConfigured
+	}
+
+	from, err := mail.ParseAddress(*r.conf.SmtpFrom)
+	if err != nil {
+		return fmt.Errorf("invalid smtp sender: %w", err)
+	}
+
+	message, err := r.message(from, email)
+	if err != nil {
+		return err
+	}
+
+	port := 25
+	if r.conf.SmtpPort != nil {
+		port = *r.conf.SmtpPort
+	}
+	connection, err := net.DialTimeout("tcp", net.JoinHostPort(*r.conf.SmtpHost, strconv.Itoa(port)), smtpTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	_ = connection.SetDeadline(time.Now().Add(smtpTimeout))
+
+	client, err := smtp.NewClient(connection, *r.conf.SmtpHost)
+	if err != nil {
+		_ = connection.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: *r.conf.SmtpHost}); err != nil {
+			return err
+		}
+	}
+	if r.conf.SmtpUsername != nil && *r.conf.SmtpUsername != "" {
+		auth := smtp.PlainAuth("", *r.conf.SmtpUsername, *r.conf.SmtpPassword, *r.conf.SmtpHost)
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(email.To); err != nil {
+		return err
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(message); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+func (r *smtpMailer) message(from *mail.Address, email *payload.Email) ([]byte, error) {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+
+	for _, alternative := range []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=utf-8", email.Text},
+		{"text/html; charset=utf-8", email.Html},
+	} {
+		part, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {alternative.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		encoder := quotedprintable.NewWriter(part)
+		if _, err := encoder.Write([]byte(alternative.content)); err != nil {
+			return nil, err
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	messageId := make([]byte, 16)
+	if _, err := rand.Read(messageId); err != nil {
+		return nil, err
+	}
+	domain := from.Address[strings.LastIndex(from.Address, "@")+1:]
+
+	var message bytes.Buffer
+	for _, header := range [][2]string{
+		{"From", from.String()},
+		{"To", email.To},
+		{"Subject", mime.BEncoding.Encode("utf-8", email.Subject)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"Message-ID", fmt.Sprintf("<%s@%s>", hex.EncodeToString(messageId), domain)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", fmt.Sprintf("multipart/alternative; boundary=%q", parts.Boundary())},
+	} {
+		message.WriteString(header[0] + ": " + header[1] + "\r\n")
+	}
+	message.WriteString("\r\n")
+	message.Write(body.Bytes())
+
+	return message.Bytes(), nil
+}
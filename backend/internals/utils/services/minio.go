@@ -8,4 +8,7 @@ import (
 
 type MinioService interface {
 	PutObject(ctx context.Context, bucketName string, objectName string, reader io.Reader, fileHeader *multipart.FileHeader) error
+	PutObjectBytes(ctx context.Context, bucketName string, objectName string, data []byte, contentType string) error
+	ObjectExists(ctx context.Context, bucketName string, objectName string) (bool, error)
+	GetObject(ctx context.Context, bucketName string, objectName string) ([]byte, string, error)
 }
@@ -1,6 +1,7 @@
 package utilServices
 
 import (
+	"bytes"
 	"context"
 	"github.com/minio/minio-go/v7"
 	"io"
@@ -31,3 +32,49 @@ func (r *minioService) PutObject(ctx context.Context, bucketName string, objectN
 	}
 	return nil
 }
+
+func (r *minioService) PutObjectBytes(ctx context.Context, bucketName string, objectName string, data []byte, contentType string) error {
+	_, err := r.minioClient.PutObject(
+		ctx,
+		bucketName,
+		objectName,
+		bytes.NewReader(data),
+		int64(len(data)),
+		minio.PutObjectOptions{ContentType: contentType},
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *minioService) ObjectExists(ctx context.Context, bucketName string, objectName string) (bool, error) {
+	_, err := r.minioClient.StatObject(ctx, bucketName, objectName, minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *minioService) GetObject(ctx context.Context, bucketName string, objectName string) ([]byte, string, error) {
+	object, err := r.minioClient.GetObject(ctx, bucketName, objectName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, "", err
+	}
+	defer object.Close()
+
+	info, err := object.Stat()
+	if err != nil {
+		return nil, "", err
+	}
+
+	data, err := io.ReadAll(object)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return data, info.ContentType, nil
+}
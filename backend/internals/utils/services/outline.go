@@ -0,0 +1,11 @@
+package utilServices
+
+import "backend/internals/entities/payload"
+
+type OutlineService interface {
+	DocumentInfo(documentId string) (*payload.OutlineDocument, error)
+	ListDocuments(parentDocumentId string) ([]*payload.OutlineDocument, error)
+	ExportDocument(documentId string) (string, error)
+	AttachmentLocation(attachmentId string) (string, error)
+	Download(location string) ([]byte, string, error)
+}
@@ -0,0 +1,108 @@
+package utilServices
+
+import (
+	"backend/internals/config"
+	"backend/internals/entities/payload"
+	"fmt"
+	"github.com/go-resty/resty/v2"
+	"net/http"
+)
+
+const outlineEndpoint = "https://outline.cscms.me/api"
+
+type outlineService struct {
+	client *resty.Client
+	token  string
+}
+
+func NewOutlineService(conf *config.Config) OutlineService {
+	token := ""
+	if conf.OutlineToken != nil {
+		token = *conf.OutlineToken
+	}
+
+	return &outlineService{
+		client: resty.New().SetBaseURL(outlineEndpoint),
+		token:  token,
+	}
+}
+
+func (r *outlineService) DocumentInfo(documentId string) (*payload.OutlineDocument, error) {
+	result := new(struct {
+		Data *payload.OutlineDocument `json:"data"`
+	})
+	if err := r.post("/documents.info", map[string]any{"id": documentId}, result); err != nil {
+		return nil, err
+	}
+
+	return result.Data, nil
+}
+
+func (r *outlineService) ListDocuments(parentDocumentId string) ([]*payload.OutlineDocument, error) {
+	result := new(struct {
+		Data []*payload.OutlineDocument `json:"data"`
+	})
+	if err := r.post("/documents.list", map[string]any{"parentDocumentId": parentDocumentId}, result); err != nil {
+		return nil, err
+	}
+
+	return result.Data, nil
+}
+
+func (r *outlineService) ExportDocument(documentId string) (string, error) {
+	result := new(struct {
+		Data string `json:"data"`
+	})
+	if err := r.post("/documents.export", map[string]any{"id": documentId}, result); err != nil {
+		return "", err
+	}
+
+	return result.Data, nil
+}
+
+func (r *outlineService) AttachmentLocation(attachmentId string) (string, error) {
+	client := resty.New().
+		SetBaseURL(outlineEndpoint).
+		SetRedirectPolicy(resty.NoRedirectPolicy())
+
+	resp, err := client.R().
+		SetAuthToken(r.token).
+		SetQueryParam("id", attachmentId).
+		Get("/attachments.redirect")
+	if resp != nil && resp.StatusCode() == http.StatusFound {
+		return resp.Header().Get("Location"), nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return "", fmt.Errorf("unexpected attachment redirect status: %s", resp.Status())
+}
+
+func (r *outlineService) Download(location string) ([]byte, string, error) {
+	resp, err := resty.New().R().Get(location)
+	if err != nil {
+		return nil, "", err
+	}
+	if resp.StatusCode() != http.StatusOK {
+		return nil, "", fmt.Errorf("failed to download %s: %s", location, resp.Status())
+	}
+
+	return resp.Body(), resp.Header().Get("Content-Type"), nil
+}
+
+func (r *outlineService) post(path string, body map[string]any, result any) error {
+	resp, err := r.client.R().
+		SetAuthToken(r.token).
+		SetBody(body).
+		SetResult(result).
+		Post(path)
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return fmt.Errorf("outline api %s failed: %s", path, resp.Status())
+	}
+
+	return nil
+}
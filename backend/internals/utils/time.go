@@ -35,3 +35,22 @@ func TimeNowPtr() *time.Time {
 	now := TimeNow()
 	return &now
 }
+
+// TimeWeekStart returns the Monday midnight, Bangkok time, starting the week of t.
+func TimeWeekStart(t time.Time) time.Time {
+	day := TimeInBangkok(t)
+	offset := (int(day.Weekday()) + 6) % 7
+	return time.Date(day.Year(), day.Month(), day.Day()-offset, 0, 0, 0, 0, BangkokTime)
+}
+
+// TimeMonthStart returns the midnight, Bangkok time, of the first day of the month of t.
+func TimeMonthStart(t time.Time) time.Time {
+	day := TimeInBangkok(t)
+	return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, BangkokTime)
+}
+
+// TimeDate returns the calendar day of t at UTC midnight, so storing it in a DATE column does not
+// shift it to another day in the time zone of the connection.
+func TimeDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}